@@ -0,0 +1,10 @@
+-- Creates notification_db and notification_service so notification-migrate can run.
+-- Run via notification-db-ensure service. Safe to run multiple times with ON_ERROR_STOP=0.
+
+\c postgres
+CREATE DATABASE notification_db;
+CREATE USER notification_service WITH PASSWORD :'notification_password';
+GRANT ALL PRIVILEGES ON DATABASE notification_db TO notification_service;
+\c notification_db
+GRANT USAGE ON SCHEMA public TO notification_service;
+GRANT CREATE ON SCHEMA public TO notification_service;
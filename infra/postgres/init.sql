@@ -41,3 +41,12 @@ GRANT ALL PRIVILEGES ON DATABASE payment_db TO payment_service;
 GRANT USAGE ON SCHEMA public TO payment_service;
 GRANT CREATE ON SCHEMA public TO payment_service;
 
+
+-- NOTIFICATION SERVICE
+\c postgres
+CREATE DATABASE notification_db;
+CREATE USER notification_service WITH PASSWORD :'notification_password';
+GRANT ALL PRIVILEGES ON DATABASE notification_db TO notification_service;
+\c notification_db
+GRANT USAGE ON SCHEMA public TO notification_service;
+GRANT CREATE ON SCHEMA public TO notification_service;
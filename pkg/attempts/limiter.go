@@ -0,0 +1,244 @@
+// Package attempts limits failed attempts (passwords, PINs, OTPs) per subject with a Redis sliding window and
+// progressive lockouts. It is shared by the services that verify secrets so every check locks out the same way.
+package attempts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "attempts:"
+
+// Policy describes one kind of attempt, e.g. password login per user or per IP.
+type Policy struct {
+	Name        string          // key namespace, e.g. "login:user"
+	MaxFailures int             // failures inside Window that trigger a lockout
+	Window      time.Duration   // sliding window for counting failures
+	Lockouts    []time.Duration // lockout length for the 1st, 2nd, ... lockout; the last entry repeats
+	// LevelTTL is how long earlier lockouts count towards the next one. Defaults to 24h.
+	LevelTTL time.Duration
+}
+
+// LockedError is returned while a subject is locked out.
+type LockedError struct {
+	Policy     string
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return "too many failed attempts; try again in " + humanDuration(e.RetryAfter)
+}
+
+// humanDuration renders a lockout as whole minutes (rounded up), or hours and minutes from an hour up.
+func humanDuration(d time.Duration) string {
+	mins := int((d + time.Minute - 1) / time.Minute)
+	if mins < 60 {
+		if mins <= 1 {
+			return "1 minute"
+		}
+		return fmt.Sprintf("%d minutes", mins)
+	}
+	if mins%60 == 0 {
+		return fmt.Sprintf("%dh", mins/60)
+	}
+	return fmt.Sprintf("%dh%02dm", mins/60, mins%60)
+}
+
+// IsLocked reports whether err is a lockout and returns it.
+func IsLocked(err error) (*LockedError, bool) {
+	var le *LockedError
+	if errors.As(err, &le) {
+		return le, true
+	}
+	return nil, false
+}
+
+// Limiter enforces one Policy. A Limiter without a Redis client allows everything (fail open), matching how the
+// services treat Redis as a cache elsewhere.
+type Limiter struct {
+	rdb    redis.UniversalClient
+	policy Policy
+	now    func() time.Time
+}
+
+// NewLimiter returns a limiter for the policy. rdb may be nil.
+func NewLimiter(rdb redis.UniversalClient, p Policy) *Limiter {
+	if p.LevelTTL <= 0 {
+		p.LevelTTL = 24 * time.Hour
+	}
+	if len(p.Lockouts) == 0 {
+		p.Lockouts = []time.Duration{15 * time.Minute}
+	}
+	return &Limiter{rdb: rdb, policy: p, now: time.Now}
+}
+
+// Check returns a *LockedError if subject is locked out, otherwise nil.
+func (l *Limiter) Check(ctx context.Context, subject string) error {
+	if l == nil || l.rdb == nil || subject == "" {
+		return nil
+	}
+	ttl, err := l.rdb.PTTL(ctx, l.key("lock", subject)).Result()
+	if err != nil {
+		log.Printf("attempts: check %s failed err=%v", l.policy.Name, err)
+		return nil
+	}
+	if ttl > 0 {
+		return &LockedError{Policy: l.policy.Name, RetryAfter: ttl}
+	}
+	return nil
+}
+
+// Fail records a failed attempt. When it reaches MaxFailures inside the window the subject is locked out and a
+// *LockedError is returned; each further lockout within LevelTTL uses the next (longer) entry of Lockouts.
+func (l *Limiter) Fail(ctx context.Context, subject string) error {
+	if l == nil || l.rdb == nil || subject == "" {
+		return nil
+	}
+	now := l.now()
+	windowKey := l.key("window", subject)
+	var card *redis.IntCmd
+	_, err := l.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZRemRangeByScore(ctx, windowKey, "-inf", strconv.FormatInt(now.Add(-l.policy.Window).UnixNano(), 10))
+		p.ZAdd(ctx, windowKey, redis.Z{Score: float64(now.UnixNano()), Member: strconv.FormatInt(now.UnixNano(), 10)})
+		card = p.ZCard(ctx, windowKey)
+		p.PExpire(ctx, windowKey, l.policy.Window)
+		return nil
+	})
+	if err != nil {
+		log.Printf("attempts: record %s failure err=%v", l.policy.Name, err)
+		return nil
+	}
+	if int(card.Val()) < l.policy.MaxFailures {
+		return nil
+	}
+	return l.lockOut(ctx, subject)
+}
+
+// lockOut locks subject out for the lockout of its next level and clears its failure window.
+func (l *Limiter) lockOut(ctx context.Context, subject string) error {
+	levelKey := l.key("level", subject)
+	level, err := l.rdb.Incr(ctx, levelKey).Result()
+	if err != nil {
+		log.Printf("attempts: lock %s err=%v", l.policy.Name, err)
+		return nil
+	}
+	l.rdb.PExpire(ctx, levelKey, l.policy.LevelTTL)
+	idx := int(level) - 1
+	if idx >= len(l.policy.Lockouts) {
+		idx = len(l.policy.Lockouts) - 1
+	}
+	d := l.policy.Lockouts[idx]
+	if err := l.rdb.Set(ctx, l.key("lock", subject), level, d).Err(); err != nil {
+		log.Printf("attempts: lock %s err=%v", l.policy.Name, err)
+		return nil
+	}
+	l.rdb.Del(ctx, l.key("window", subject))
+	return &LockedError{Policy: l.policy.Name, RetryAfter: d}
+}
+
+// Reservation is an attempt counted against the limit before the secret is checked, so concurrent guesses cannot all
+// pass a Check before any of them is recorded. Settle it with Fail or Succeed.
+type Reservation struct {
+	l       *Limiter
+	subject string
+	n       int // the attempt's position in the window
+}
+
+// Reserve counts an attempt by subject and returns it, or a *LockedError while subject is locked out or MaxFailures
+// attempts are already in the window. The lock check and the count are one MULTI, so at most MaxFailures attempts
+// are ever checked before a lockout. Redis errors allow the attempt (fail open).
+func (l *Limiter) Reserve(ctx context.Context, subject string) (*Reservation, error) {
+	if l == nil || l.rdb == nil || subject == "" {
+		return &Reservation{}, nil
+	}
+	now := l.now()
+	windowKey := l.key("window", subject)
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + randomSuffix()
+	var lockTTL *redis.DurationCmd
+	var card *redis.IntCmd
+	var oldest *redis.ZSliceCmd
+	_, err := l.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		lockTTL = p.PTTL(ctx, l.key("lock", subject))
+		p.ZRemRangeByScore(ctx, windowKey, "-inf", strconv.FormatInt(now.Add(-l.policy.Window).UnixNano(), 10))
+		p.ZAdd(ctx, windowKey, redis.Z{Score: float64(now.UnixNano()), Member: member})
+		card = p.ZCard(ctx, windowKey)
+		oldest = p.ZRangeWithScores(ctx, windowKey, 0, 0)
+		p.PExpire(ctx, windowKey, l.policy.Window)
+		return nil
+	})
+	if err != nil {
+		log.Printf("attempts: reserve %s err=%v", l.policy.Name, err)
+		return &Reservation{}, nil
+	}
+	if ttl := lockTTL.Val(); ttl > 0 {
+		l.rdb.ZRem(ctx, windowKey, member)
+		return nil, &LockedError{Policy: l.policy.Name, RetryAfter: ttl}
+	}
+	if n := int(card.Val()); n <= l.policy.MaxFailures {
+		return &Reservation{l: l, subject: subject, n: n}, nil
+	}
+	// The window is full of failures and attempts still being checked; a slot frees when the oldest leaves it.
+	l.rdb.ZRem(ctx, windowKey, member)
+	retry := l.policy.Window
+	if z := oldest.Val(); len(z) == 1 {
+		retry = time.Unix(0, int64(z[0].Score)).Add(l.policy.Window).Sub(now)
+	}
+	return nil, &LockedError{Policy: l.policy.Name, RetryAfter: retry}
+}
+
+// Fail keeps the attempt counted as a failure. The attempt that filled the window locks subject out and returns a
+// *LockedError, as Limiter.Fail does.
+func (r *Reservation) Fail(ctx context.Context) error {
+	if r.l == nil || r.n < r.l.policy.MaxFailures {
+		return nil
+	}
+	return r.l.lockOut(ctx, r.subject)
+}
+
+// Succeed clears the failure window, as Limiter.Succeed does.
+func (r *Reservation) Succeed(ctx context.Context) {
+	r.l.Succeed(ctx, r.subject)
+}
+
+func randomSuffix() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Succeed clears the failure window after a correct attempt. Earlier lockouts still count towards the next one.
+func (l *Limiter) Succeed(ctx context.Context, subject string) {
+	if l == nil || l.rdb == nil || subject == "" {
+		return
+	}
+	l.rdb.Del(ctx, l.key("window", subject))
+}
+
+// Unlock lifts a lockout and forgets earlier ones (admin action). Returns true if the subject was locked.
+func (l *Limiter) Unlock(ctx context.Context, subject string) (bool, error) {
+	if l == nil || l.rdb == nil || subject == "" {
+		return false, nil
+	}
+	var locked *redis.IntCmd
+	_, err := l.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		locked = p.Del(ctx, l.key("lock", subject))
+		p.Del(ctx, l.key("window", subject), l.key("level", subject))
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return locked.Val() > 0, nil
+}
+
+func (l *Limiter) key(kind, subject string) string {
+	return keyPrefix + l.policy.Name + ":" + kind + ":" + subject
+}
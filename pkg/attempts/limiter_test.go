@@ -0,0 +1,308 @@
+package attempts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis keeps the keys the limiter uses in memory, on the limiter's clock. Commands it does not know panic
+// through the embedded nil interfaces. err fails every pipeline, like a Redis outage.
+type fakeRedis struct {
+	redis.UniversalClient
+	now     *time.Time
+	strings map[string]string
+	zsets   map[string]map[string]float64
+	expires map[string]time.Time
+	err     error
+}
+
+func newFakeRedis(now *time.Time) *fakeRedis {
+	return &fakeRedis{now: now, strings: map[string]string{}, zsets: map[string]map[string]float64{}, expires: map[string]time.Time{}}
+}
+
+// expire drops key if its TTL has passed.
+func (f *fakeRedis) expire(key string) {
+	if at, ok := f.expires[key]; ok && !f.now.Before(at) {
+		delete(f.strings, key)
+		delete(f.zsets, key)
+		delete(f.expires, key)
+	}
+}
+
+func (f *fakeRedis) pttl(key string) time.Duration {
+	f.expire(key)
+	if _, ok := f.strings[key]; !ok {
+		if _, ok := f.zsets[key]; !ok {
+			return -2 * time.Millisecond
+		}
+	}
+	at, ok := f.expires[key]
+	if !ok {
+		return -time.Millisecond
+	}
+	return at.Sub(*f.now)
+}
+
+func (f *fakeRedis) del(keys ...string) int64 {
+	var n int64
+	for _, k := range keys {
+		f.expire(k)
+		_, s := f.strings[k]
+		_, z := f.zsets[k]
+		if s || z {
+			n++
+		}
+		delete(f.strings, k)
+		delete(f.zsets, k)
+		delete(f.expires, k)
+	}
+	return n
+}
+
+func (f *fakeRedis) pexpire(key string, d time.Duration) bool {
+	f.expire(key)
+	_, s := f.strings[key]
+	_, z := f.zsets[key]
+	if s || z {
+		f.expires[key] = f.now.Add(d)
+	}
+	return s || z
+}
+
+func (f *fakeRedis) zset(key string) map[string]float64 {
+	f.expire(key)
+	z := f.zsets[key]
+	if z == nil {
+		z = map[string]float64{}
+		f.zsets[key] = z
+	}
+	return z
+}
+
+func (f *fakeRedis) TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	// Commands run as they are queued: nothing else touches the fake in between, as with MULTI.
+	return nil, fn(&fakePipe{f: f})
+}
+
+func (f *fakeRedis) PTTL(ctx context.Context, key string) *redis.DurationCmd {
+	cmd := redis.NewDurationCmd(ctx, time.Millisecond)
+	cmd.SetVal(f.pttl(key))
+	return cmd
+}
+
+func (f *fakeRedis) Incr(ctx context.Context, key string) *redis.IntCmd {
+	f.expire(key)
+	n, _ := strconv.ParseInt(f.strings[key], 10, 64)
+	f.strings[key] = strconv.FormatInt(n+1, 10)
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(n + 1)
+	return cmd
+}
+
+func (f *fakeRedis) PExpire(ctx context.Context, key string, d time.Duration) *redis.BoolCmd {
+	cmd := redis.NewBoolCmd(ctx)
+	cmd.SetVal(f.pexpire(key, d))
+	return cmd
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value interface{}, d time.Duration) *redis.StatusCmd {
+	f.strings[key] = fmt.Sprint(value)
+	f.expires[key] = f.now.Add(d)
+	return redis.NewStatusCmd(ctx)
+}
+
+func (f *fakeRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(f.del(keys...))
+	return cmd
+}
+
+func (f *fakeRedis) ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	z := f.zset(key)
+	for _, m := range members {
+		delete(z, m.(string))
+	}
+	return redis.NewIntCmd(ctx)
+}
+
+type fakePipe struct {
+	redis.Pipeliner
+	f *fakeRedis
+}
+
+func (p *fakePipe) PTTL(ctx context.Context, key string) *redis.DurationCmd {
+	return p.f.PTTL(ctx, key)
+}
+
+func (p *fakePipe) PExpire(ctx context.Context, key string, d time.Duration) *redis.BoolCmd {
+	return p.f.PExpire(ctx, key, d)
+}
+
+func (p *fakePipe) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	return p.f.Del(ctx, keys...)
+}
+
+func (p *fakePipe) ZRemRangeByScore(ctx context.Context, key, min, max string) *redis.IntCmd {
+	upTo, _ := strconv.ParseFloat(max, 64)
+	z := p.f.zset(key)
+	for m, score := range z {
+		if score <= upTo {
+			delete(z, m)
+		}
+	}
+	return redis.NewIntCmd(ctx)
+}
+
+func (p *fakePipe) ZAdd(ctx context.Context, key string, members ...redis.Z) *redis.IntCmd {
+	z := p.f.zset(key)
+	for _, m := range members {
+		z[m.Member.(string)] = m.Score
+	}
+	return redis.NewIntCmd(ctx)
+}
+
+func (p *fakePipe) ZCard(ctx context.Context, key string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(int64(len(p.f.zset(key))))
+	return cmd
+}
+
+func (p *fakePipe) ZRangeWithScores(ctx context.Context, key string, start, stop int64) *redis.ZSliceCmd {
+	var all []redis.Z
+	for m, score := range p.f.zset(key) {
+		all = append(all, redis.Z{Member: m, Score: score})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Score < all[j].Score })
+	cmd := redis.NewZSliceCmd(ctx)
+	if len(all) > 0 {
+		cmd.SetVal(all[start : stop+1])
+	}
+	return cmd
+}
+
+// newTestLimiter allows 3 failures a minute, locking out for 15 minutes and then an hour.
+func newTestLimiter() (*Limiter, *fakeRedis, *time.Time) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	rdb := newFakeRedis(&now)
+	l := NewLimiter(rdb, Policy{Name: "pin", MaxFailures: 3, Window: time.Minute, Lockouts: []time.Duration{15 * time.Minute, time.Hour}})
+	l.now = func() time.Time { return now }
+	return l, rdb, &now
+}
+
+// failAttempts reserves and fails n attempts, returning the last error.
+func failAttempts(t *testing.T, l *Limiter, n int) error {
+	t.Helper()
+	var err error
+	for i := 0; i < n; i++ {
+		var r *Reservation
+		if r, err = l.Reserve(context.Background(), "user-1"); err != nil {
+			return err
+		}
+		err = r.Fail(context.Background())
+	}
+	return err
+}
+
+func TestReserveLocksAtThreshold(t *testing.T) {
+	l, _, now := newTestLimiter()
+	if err := failAttempts(t, l, 2); err != nil {
+		t.Fatalf("two failures: %v", err)
+	}
+	le, ok := IsLocked(failAttempts(t, l, 1))
+	if !ok || le.RetryAfter != 15*time.Minute {
+		t.Fatalf("third failure = %v, want a 15 minute lockout", le)
+	}
+	if _, err := l.Reserve(context.Background(), "user-1"); err == nil {
+		t.Fatal("reserved an attempt while locked out")
+	}
+
+	// The next lockout inside LevelTTL is longer.
+	*now = now.Add(16 * time.Minute)
+	if le, ok := IsLocked(failAttempts(t, l, 3)); !ok || le.RetryAfter != time.Hour {
+		t.Errorf("second lockout = %v, want an hour", le)
+	}
+}
+
+func TestReserveCountsAttemptsInFlight(t *testing.T) {
+	l, _, _ := newTestLimiter()
+	// Three guesses are checked at once; none has failed yet.
+	var inFlight []*Reservation
+	for i := 0; i < 3; i++ {
+		r, err := l.Reserve(context.Background(), "user-1")
+		if err != nil {
+			t.Fatalf("attempt %d: %v", i+1, err)
+		}
+		inFlight = append(inFlight, r)
+	}
+	le, ok := IsLocked(func() error { _, err := l.Reserve(context.Background(), "user-1"); return err }())
+	if !ok || le.RetryAfter != time.Minute {
+		t.Fatalf("fourth parallel attempt = %v, want refused until the oldest leaves the window", le)
+	}
+
+	var locks int
+	for _, r := range inFlight {
+		if err := r.Fail(context.Background()); err != nil {
+			locks++
+		}
+	}
+	if locks != 1 {
+		t.Errorf("%d of the failed attempts locked out, want exactly one", locks)
+	}
+	if err := l.Check(context.Background(), "user-1"); err == nil {
+		t.Error("not locked out after three failures")
+	}
+}
+
+func TestReserveWindowExpires(t *testing.T) {
+	l, _, now := newTestLimiter()
+	if err := failAttempts(t, l, 2); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(61 * time.Second)
+	if err := failAttempts(t, l, 2); err != nil {
+		t.Errorf("failures from an expired window still counted: %v", err)
+	}
+}
+
+func TestReservationSucceedResets(t *testing.T) {
+	l, _, _ := newTestLimiter()
+	if err := failAttempts(t, l, 2); err != nil {
+		t.Fatal(err)
+	}
+	r, err := l.Reserve(context.Background(), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Succeed(context.Background())
+	if err := failAttempts(t, l, 2); err != nil {
+		t.Errorf("failures before a success still counted: %v", err)
+	}
+}
+
+func TestReserveFailsOpen(t *testing.T) {
+	l, rdb, _ := newTestLimiter()
+	if err := failAttempts(t, l, 2); err != nil {
+		t.Fatal(err)
+	}
+	rdb.err = errors.New("dial tcp 10.0.0.5:6379: connect: connection refused")
+	if err := failAttempts(t, l, 5); err != nil {
+		t.Errorf("attempts during a Redis outage = %v, want allowed", err)
+	}
+
+	var unconfigured *Limiter
+	r, err := unconfigured.Reserve(context.Background(), "user-1")
+	if err != nil || r.Fail(context.Background()) != nil {
+		t.Errorf("limiter without Redis refused an attempt: %v", err)
+	}
+	r.Succeed(context.Background())
+}
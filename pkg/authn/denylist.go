@@ -0,0 +1,120 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const denyKeyPrefix = "authn:revoked:"
+
+// ErrTokenRevoked is returned for a token that was logged out, belongs to a revoked session, or was issued before
+// the user's tokens were revoked (password change, restriction, log out everywhere).
+var ErrTokenRevoked = errors.New("token revoked")
+
+// DenyList records revoked access tokens in Redis so every service rejects them before they expire. Entries only
+// need to outlive the tokens they block, so each is kept for MaxTokenTTL. A DenyList without Redis revokes nothing.
+type DenyList struct {
+	rdb         redis.UniversalClient
+	maxTokenTTL time.Duration
+}
+
+// NewDenyList returns a deny-list on rdb (may be nil). maxTokenTTL is the longest lifetime of a token it may block.
+func NewDenyList(rdb redis.UniversalClient, maxTokenTTL time.Duration) *DenyList {
+	if maxTokenTTL <= 0 {
+		maxTokenTTL = time.Hour
+	}
+	return &DenyList{rdb: rdb, maxTokenTTL: maxTokenTTL}
+}
+
+// RevokeToken blocks one token by jti until it expires.
+func (d *DenyList) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if d == nil || d.rdb == nil || jti == "" {
+		return nil
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if ttl > d.maxTokenTTL {
+		ttl = d.maxTokenTTL
+	}
+	return d.rdb.Set(ctx, denyKeyPrefix+"jti:"+jti, "1", ttl).Err()
+}
+
+// Consume marks a single-use token (by jti) as used and returns ErrTokenRevoked if it already was. The check and the
+// mark are one SETNX, so two requests racing with the same token cannot both succeed. Unlike Check it fails closed:
+// a Redis error refuses the token. A DenyList without Redis consumes nothing.
+func (d *DenyList) Consume(ctx context.Context, jti string, expiresAt time.Time) error {
+	if d == nil || d.rdb == nil {
+		return nil
+	}
+	if jti == "" {
+		return ErrTokenRevoked
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return ErrTokenRevoked
+	}
+	if ttl > d.maxTokenTTL {
+		ttl = d.maxTokenTTL
+	}
+	first, err := d.rdb.SetNX(ctx, denyKeyPrefix+"jti:"+jti, "1", ttl).Result()
+	if err != nil {
+		return fmt.Errorf("authn: consume token: %w", err)
+	}
+	if !first {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// RevokeSession blocks every access token carrying the session id (sid claim).
+func (d *DenyList) RevokeSession(ctx context.Context, sessionID string) error {
+	if d == nil || d.rdb == nil || sessionID == "" {
+		return nil
+	}
+	return d.rdb.Set(ctx, denyKeyPrefix+"sid:"+sessionID, "1", d.maxTokenTTL).Err()
+}
+
+// RevokeUser blocks every token of the user issued before now. Tokens issued later (a fresh login) still work.
+func (d *DenyList) RevokeUser(ctx context.Context, userID string) error {
+	if d == nil || d.rdb == nil || userID == "" {
+		return nil
+	}
+	cutoff := strconv.FormatInt(time.Now().Unix(), 10)
+	return d.rdb.Set(ctx, denyKeyPrefix+"user:"+userID, cutoff, d.maxTokenTTL).Err()
+}
+
+// Check returns ErrTokenRevoked if the token is on the deny-list. Redis errors are logged and the token is allowed,
+// the same fail-open choice the services make for their other Redis lookups.
+func (d *DenyList) Check(ctx context.Context, c *Claims) error {
+	if d == nil || d.rdb == nil || c == nil {
+		return nil
+	}
+	vals, err := d.rdb.MGet(ctx,
+		denyKeyPrefix+"jti:"+c.ID,
+		denyKeyPrefix+"sid:"+c.SessionID,
+		denyKeyPrefix+"user:"+c.UserID,
+	).Result()
+	if err != nil {
+		log.Printf("authn: deny-list lookup err=%v", err)
+		return nil
+	}
+	if (c.ID != "" && vals[0] != nil) || (c.SessionID != "" && vals[1] != nil) {
+		return ErrTokenRevoked
+	}
+	if s, ok := vals[2].(string); ok {
+		cutoff, _ := strconv.ParseInt(s, 10, 64)
+		// Whole seconds: a token from the same second as the revocation is treated as newer.
+		if c.IssuedAt == nil || c.IssuedAt.Unix() < cutoff {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
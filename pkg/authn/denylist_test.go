@@ -0,0 +1,55 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setNXRedis implements the one Redis command Consume sends; anything else panics.
+type setNXRedis struct {
+	redis.UniversalClient
+	keys map[string]bool
+	err  error
+}
+
+func (r *setNXRedis) SetNX(ctx context.Context, key string, _ interface{}, _ time.Duration) *redis.BoolCmd {
+	if r.err != nil {
+		return redis.NewBoolResult(false, r.err)
+	}
+	if r.keys[key] {
+		return redis.NewBoolResult(false, nil)
+	}
+	r.keys[key] = true
+	return redis.NewBoolResult(true, nil)
+}
+
+func TestConsume(t *testing.T) {
+	rdb := &setNXRedis{keys: map[string]bool{}}
+	d := NewDenyList(rdb, time.Hour)
+	ctx := context.Background()
+	exp := time.Now().Add(5 * time.Minute)
+
+	if err := d.Consume(ctx, "jti-1", exp); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := d.Consume(ctx, "jti-1", exp); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("second use: err = %v, want ErrTokenRevoked", err)
+	}
+	if err := d.Consume(ctx, "jti-2", exp); err != nil {
+		t.Errorf("another token: %v", err)
+	}
+	if err := d.Consume(ctx, "", exp); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("token without jti: err = %v", err)
+	}
+	if err := d.Consume(ctx, "jti-3", time.Now().Add(-time.Second)); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("expired token: err = %v", err)
+	}
+	rdb.err = errors.New("connection refused")
+	if err := d.Consume(ctx, "jti-4", exp); err == nil {
+		t.Error("Redis down: token accepted")
+	}
+}
@@ -0,0 +1,47 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/pkg/jwtauth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestAuthenticateRejectsPurposeTokens(t *testing.T) {
+	ks, err := jwtauth.GenerateKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAuthenticator(jwtauth.LocalVerifier{Keys: ks, Issuer: jwtauth.UserIssuer, Audience: jwtauth.UserAudience}, nil)
+	sign := func(purpose string) string {
+		now := time.Now()
+		tok, err := ks.Sign(&Claims{
+			UserID:  "user-1",
+			Purpose: purpose,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    jwtauth.UserIssuer,
+				Audience:  jwt.ClaimStrings{jwtauth.UserAudience},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok
+	}
+
+	claims, err := a.Authenticate(context.Background(), "Bearer "+sign(""))
+	if err != nil || claims.UserID != "user-1" {
+		t.Fatalf("access token: claims=%v err=%v", claims, err)
+	}
+	if _, err := a.Authenticate(context.Background(), "Bearer "+sign("2fa_login")); !errors.Is(err, ErrPurposeToken) {
+		t.Fatalf("2FA pending token: err=%v, want ErrPurposeToken", err)
+	}
+	if _, err := a.Authenticate(context.Background(), sign("")); !errors.Is(err, ErrMissingBearer) {
+		t.Fatalf("no Bearer prefix: err=%v, want ErrMissingBearer", err)
+	}
+}
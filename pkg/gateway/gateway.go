@@ -0,0 +1,39 @@
+// Package gateway configures services for running behind the nginx API gateway, which is the only container that
+// publishes a port.
+package gateway
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RealIPHeader is set by nginx to the address the request came from ($remote_addr), replacing any value the client
+// sent. X-Forwarded-For is not used: nginx appends to it, so its first entry is whatever the client wrote.
+const RealIPHeader = "X-Real-IP"
+
+// DefaultTrustedProxies are the private ranges the gateway reaches the services from on the compose network.
+var DefaultTrustedProxies = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "127.0.0.1/32", "::1/128"}
+
+// TrustProxies makes ctx.ClientIP() the client address nginx saw: RealIPHeader is honoured only on requests from
+// proxies (addresses or CIDRs; nil or empty means DefaultTrustedProxies), and any other request is keyed on its
+// own remote address. Per-IP attempt limits depend on this; with gin's defaults a client picks its own IP.
+func TrustProxies(r *gin.Engine, proxies []string) error {
+	if len(proxies) == 0 {
+		proxies = DefaultTrustedProxies
+	}
+	r.ForwardedByClientIP = true
+	r.RemoteIPHeaders = []string{RealIPHeader}
+	return r.SetTrustedProxies(proxies)
+}
+
+// ParseProxies splits a comma-separated TRUSTED_PROXIES value into addresses and CIDRs.
+func ParseProxies(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
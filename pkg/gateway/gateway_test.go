@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrustProxiesClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if err := TrustProxies(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	cases := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"via gateway", "172.18.0.9:41000", map[string]string{"X-Real-IP": "41.58.1.2", "X-Forwarded-For": "6.6.6.6, 41.58.1.2"}, "41.58.1.2"},
+		{"forwarded-for ignored", "172.18.0.9:41000", map[string]string{"X-Forwarded-For": "6.6.6.6"}, "172.18.0.9"},
+		{"direct client spoofing", "41.58.1.2:5000", map[string]string{"X-Real-IP": "6.6.6.6", "X-Forwarded-For": "6.6.6.6"}, "41.58.1.2"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = c.remote
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Body.String(); got != c.want {
+			t.Errorf("%s: ClientIP = %s, want %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestTrustProxiesRejectsBadRange(t *testing.T) {
+	if err := TrustProxies(gin.New(), []string{"not-an-ip"}); err == nil {
+		t.Error("invalid proxy accepted")
+	}
+}
@@ -0,0 +1,135 @@
+package jwtauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeKey(t *testing.T, dir, kid string, key interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func userClaims(iss string, exp time.Time) *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{
+		Issuer:    iss,
+		Subject:   "user-1",
+		Audience:  jwt.ClaimStrings{UserAudience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(exp),
+	}
+}
+
+func TestVerifierFollowsKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	writeKey(t, dir, "2026-09", edKey)
+	oldSet, err := LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "2026-10", rsaKey)
+	newSet, err := LoadKeySet(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newSet.ActiveKID() != "2026-10" {
+		t.Fatalf("active kid = %q, want newest key", newSet.ActiveKID())
+	}
+
+	var served atomic.Value
+	served.Store(oldSet.JWKS())
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(served.Load())
+	}))
+	defer srv.Close()
+	v := NewUserVerifier(srv.URL)
+
+	oldToken, err := oldSet.Sign(userClaims(UserIssuer, time.Now().Add(time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Verify(oldToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("token from old key: %v", err)
+	}
+
+	// The issuer rotates: the JWKS now lists both keys and new tokens carry the RSA kid.
+	served.Store(newSet.JWKS())
+	v.lastAttempt = time.Time{} // skip the refresh backoff
+	newToken, err := newSet.Sign(userClaims(UserIssuer, time.Now().Add(time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Verify(newToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("token from rotated-in key: %v", err)
+	}
+	if err := v.Verify(oldToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("token from previous key during overlap: %v", err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+func TestVerifierRejectsWrongIssuerExpiredAndForeignKeys(t *testing.T) {
+	ks, err := GenerateKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ks.JWKS())
+	}))
+	defer srv.Close()
+	v := NewUserVerifier(srv.URL)
+
+	cases := map[string]func() (string, error){
+		"admin issuer": func() (string, error) { return ks.Sign(userClaims(AdminIssuer, time.Now().Add(time.Minute))) },
+		"expired":      func() (string, error) { return ks.Sign(userClaims(UserIssuer, time.Now().Add(-time.Minute))) },
+		"foreign key":  func() (string, error) { return other.Sign(userClaims(UserIssuer, time.Now().Add(time.Minute))) },
+		"HS256": func() (string, error) {
+			tok := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims(UserIssuer, time.Now().Add(time.Minute)))
+			tok.Header["kid"] = ks.ActiveKID()
+			return tok.SignedString([]byte("shared-secret"))
+		},
+	}
+	for name, mint := range cases {
+		token, err := mint()
+		if err != nil {
+			t.Fatalf("%s: sign: %v", name, err)
+		}
+		if err := v.Verify(token, &jwt.RegisteredClaims{}); err == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Package jwtauth signs and verifies PayUp JWTs with asymmetric keys (EdDSA or RS256) identified by a kid.
+// The issuing service holds a KeySet of private keys and publishes the public halves as a JWKS; other services verify
+// tokens with a Verifier that fetches and caches that JWKS, so only the issuer can mint tokens.
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Issuer and audience of user access tokens (user service). Admin tokens use AdminIssuer/AdminAudience.
+const (
+	UserIssuer    = "payup"
+	UserAudience  = "api"
+	AdminIssuer   = "payup-admin"
+	AdminAudience = "admin-portal"
+)
+
+var (
+	ErrNoActiveKey = errors.New("jwtauth: no active signing key")
+	ErrUnknownKey  = errors.New("jwtauth: unknown signing key")
+)
+
+// Key is one signing key. Only the public half is published.
+type Key struct {
+	ID      string
+	Alg     string // "EdDSA" or "RS256"
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet holds the private keys of an issuer. The active key signs new tokens; every key in the set verifies, which
+// lets a new key take over while tokens signed by the previous one are still live.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// LoadKeySet reads every *.pem file in dir as a PKCS#8 (or PKCS#1 RSA) private key whose kid is the file name without
+// the extension. activeKID picks the signing key; when empty the last kid in lexical order is used, so naming keys by
+// date (2026-10.pem) makes the newest one active.
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("jwtauth: no *.pem keys in %s", dir)
+	}
+	sort.Strings(paths)
+	ks := &KeySet{keys: make(map[string]*Key, len(paths))}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		kid := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		k, err := parsePrivateKeyPEM(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("jwtauth: key %s: %w", kid, err)
+		}
+		ks.keys[kid] = k
+		ks.active = k
+	}
+	if activeKID != "" {
+		k, ok := ks.keys[activeKID]
+		if !ok {
+			return nil, fmt.Errorf("jwtauth: active kid %q not found in %s", activeKID, dir)
+		}
+		ks.active = k
+	}
+	return ks, nil
+}
+
+// KeySetFromEnv loads the keys named by <prefix>_KEYS_DIR and <prefix>_ACTIVE_KID (e.g. JWT_KEYS_DIR). Without a
+// directory it falls back to GenerateKeySet and logs a warning, so a bare `docker compose up` still works.
+func KeySetFromEnv(prefix string) (*KeySet, error) {
+	dir := strings.TrimSpace(os.Getenv(prefix + "_KEYS_DIR"))
+	if dir == "" {
+		ks, err := GenerateKeySet()
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("WARN: %s_KEYS_DIR not set; signing with ephemeral key %s (tokens die on restart)", prefix, ks.ActiveKID())
+		return ks, nil
+	}
+	return LoadKeySet(dir, strings.TrimSpace(os.Getenv(prefix+"_ACTIVE_KID")))
+}
+
+// GenerateKeySet returns a set with one fresh Ed25519 key. Meant for local development: tokens stop verifying when the
+// process restarts, and replicas do not share the key.
+func GenerateKeySet() (*KeySet, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(pub)
+	k := &Key{ID: "dev-" + hex.EncodeToString(sum[:6]), Alg: jwt.SigningMethodEdDSA.Alg(), private: priv, public: pub}
+	return &KeySet{active: k, keys: map[string]*Key{k.ID: k}}, nil
+}
+
+func parsePrivateKeyPEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch pk := parsed.(type) {
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Alg: jwt.SigningMethodEdDSA.Alg(), private: pk, public: pk.Public()}, nil
+	case *rsa.PrivateKey:
+		if pk.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return &Key{ID: kid, Alg: jwt.SigningMethodRS256.Alg(), private: pk, public: &pk.PublicKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (want Ed25519 or RSA)", parsed)
+	}
+}
+
+// ActiveKID is the kid new tokens are signed with.
+func (ks *KeySet) ActiveKID() string {
+	if ks == nil || ks.active == nil {
+		return ""
+	}
+	return ks.active.ID
+}
+
+// Sign signs claims with the active key and sets the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks == nil || ks.active == nil {
+		return "", ErrNoActiveKey
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(ks.active.Alg), claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.private)
+}
+
+// Parse verifies a token signed by any key in the set and checks issuer, audience and expiry.
+func (ks *KeySet) Parse(tokenString, issuer, audience string, claims jwt.Claims) error {
+	return parse(tokenString, issuer, audience, claims, func(kid string) (crypto.PublicKey, error) {
+		if ks == nil {
+			return nil, ErrUnknownKey
+		}
+		k, ok := ks.keys[kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		return k.public, nil
+	})
+}
+
+// LocalVerifier verifies tokens against a KeySet held in-process, i.e. by the service that issues them.
+type LocalVerifier struct {
+	Keys     *KeySet
+	Issuer   string
+	Audience string
+}
+
+// Verify checks a token signed by one of the keys for the verifier's issuer and audience.
+func (v LocalVerifier) Verify(tokenString string, claims jwt.Claims) error {
+	return v.Keys.Parse(tokenString, v.Issuer, v.Audience, claims)
+}
+
+// JWKS returns the public keys of the set, active key first.
+func (ks *KeySet) JWKS() JWKS {
+	out := JWKS{Keys: []JWK{}}
+	if ks == nil {
+		return out
+	}
+	if ks.active != nil {
+		out.Keys = append(out.Keys, toJWK(ks.active))
+	}
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		if ks.active == nil || kid != ks.active.ID {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		out.Keys = append(out.Keys, toJWK(ks.keys[kid]))
+	}
+	return out
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is the public part of an Ed25519 (kty OKP) or RSA key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+func toJWK(k *Key) JWK {
+	j := JWK{Kid: k.ID, Use: "sig", Alg: k.Alg}
+	switch pub := k.public.(type) {
+	case ed25519.PublicKey:
+		j.Kty, j.Crv, j.X = "OKP", "Ed25519", base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		j.Kty = "RSA"
+		j.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		j.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return j
+}
+
+// publicKey converts a JWK back to a key usable for verification.
+func (j JWK) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+	}
+}
+
+// parse verifies the signature with the key named by the kid header and requires iss, aud and exp.
+func parse(tokenString, issuer, audience string, claims jwt.Claims, lookup func(kid string) (crypto.PublicKey, error)) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("jwtauth: token has no kid")
+		}
+		return lookup(kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultCacheTTL   = 10 * time.Minute
+	minRefreshBackoff = 30 * time.Second
+)
+
+// Verifier checks tokens against an issuer's JWKS. Keys are cached for CacheTTL; a token with an unknown kid triggers
+// an early refresh (at most every 30s) so a rotated-in key is picked up without waiting for the cache to expire.
+// If the JWKS cannot be fetched, the last good keys keep being used.
+type Verifier struct {
+	URL      string
+	Issuer   string
+	Audience string
+	CacheTTL time.Duration
+	Client   *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// NewVerifier returns a verifier for tokens from the issuer whose JWKS is served at jwksURL.
+func NewVerifier(jwksURL, issuer, audience string) *Verifier {
+	return &Verifier{
+		URL:      jwksURL,
+		Issuer:   issuer,
+		Audience: audience,
+		CacheTTL: defaultCacheTTL,
+		Client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// NewUserVerifier verifies user access tokens issued by the user service.
+func NewUserVerifier(jwksURL string) *Verifier {
+	return NewVerifier(jwksURL, UserIssuer, UserAudience)
+}
+
+// Verify checks signature, issuer, audience and expiry and fills claims.
+func (v *Verifier) Verify(tokenString string, claims jwt.Claims) error {
+	return parse(tokenString, v.Issuer, v.Audience, claims, v.key)
+}
+
+func (v *Verifier) key(kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	ttl := v.CacheTTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	k, ok := v.keys[kid]
+	stale := time.Since(v.fetchedAt) > ttl
+	if ok && !stale {
+		return k, nil
+	}
+	if time.Since(v.lastAttempt) >= minRefreshBackoff {
+		v.lastAttempt = time.Now()
+		if err := v.refreshLocked(); err != nil {
+			log.Printf("jwtauth: fetch JWKS %s: %v", v.URL, err)
+		}
+		k, ok = v.keys[kid]
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return k, nil
+}
+
+func (v *Verifier) refreshLocked() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.URL, nil)
+	if err != nil {
+		return err
+	}
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		pk, err := j.publicKey()
+		if err != nil {
+			log.Printf("jwtauth: skip JWKS key %s: %v", j.Kid, err)
+			continue
+		}
+		keys[j.Kid] = pk
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
@@ -627,7 +627,9 @@ func (x *GetKYCStatusRequest) GetUserId() string {
 
 type GetKYCStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                      // overall_status; "not_started" when the user has no KYC profile
+	KycLevel      int32                  `protobuf:"varint,2,opt,name=kyc_level,json=kycLevel,proto3" json:"kyc_level,omitempty"` // 0 until a wallet is opened; Payment caps tier limits at this level
+	Found         bool                   `protobuf:"varint,3,opt,name=found,proto3" json:"found,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -669,6 +671,20 @@ func (x *GetKYCStatusResponse) GetStatus() string {
 	return ""
 }
 
+func (x *GetKYCStatusResponse) GetKycLevel() int32 {
+	if x != nil {
+		return x.KycLevel
+	}
+	return 0
+}
+
+func (x *GetKYCStatusResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
 type GetFullKYCForAdminRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -861,6 +877,214 @@ func (x *CountProfilesResponse) GetCount() int64 {
 	return 0
 }
 
+type ExportUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_proto_kyc_kyc_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRequest) ProtoMessage() {}
+
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_kyc_kyc_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_kyc_kyc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExportUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ExportUserDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	JsonPayload   string                 `protobuf:"bytes,2,opt,name=json_payload,json=jsonPayload,proto3" json:"json_payload,omitempty"` // KYC records as JSON when found
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataResponse) Reset() {
+	*x = ExportUserDataResponse{}
+	mi := &file_proto_kyc_kyc_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataResponse) ProtoMessage() {}
+
+func (x *ExportUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_kyc_kyc_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_kyc_kyc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExportUserDataResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ExportUserDataResponse) GetJsonPayload() string {
+	if x != nil {
+		return x.JsonPayload
+	}
+	return ""
+}
+
+type EraseUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserDataRequest) Reset() {
+	*x = EraseUserDataRequest{}
+	mi := &file_proto_kyc_kyc_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserDataRequest) ProtoMessage() {}
+
+func (x *EraseUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_kyc_kyc_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserDataRequest.ProtoReflect.Descriptor instead.
+func (*EraseUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_kyc_kyc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EraseUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type EraseUserDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Held          bool                   `protobuf:"varint,1,opt,name=held,proto3" json:"held,omitempty"` // true if nothing was erased because a retention hold applies
+	HoldReasons   []string               `protobuf:"bytes,2,rep,name=hold_reasons,json=holdReasons,proto3" json:"hold_reasons,omitempty"`
+	Erased        []string               `protobuf:"bytes,3,rep,name=erased,proto3" json:"erased,omitempty"`     // categories deleted, e.g. "kyc"
+	Retained      []string               `protobuf:"bytes,4,rep,name=retained,proto3" json:"retained,omitempty"` // categories kept under a retention obligation
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserDataResponse) Reset() {
+	*x = EraseUserDataResponse{}
+	mi := &file_proto_kyc_kyc_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserDataResponse) ProtoMessage() {}
+
+func (x *EraseUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_kyc_kyc_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserDataResponse.ProtoReflect.Descriptor instead.
+func (*EraseUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_kyc_kyc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EraseUserDataResponse) GetHeld() bool {
+	if x != nil {
+		return x.Held
+	}
+	return false
+}
+
+func (x *EraseUserDataResponse) GetHoldReasons() []string {
+	if x != nil {
+		return x.HoldReasons
+	}
+	return nil
+}
+
+func (x *EraseUserDataResponse) GetErased() []string {
+	if x != nil {
+		return x.Erased
+	}
+	return nil
+}
+
+func (x *EraseUserDataResponse) GetRetained() []string {
+	if x != nil {
+		return x.Retained
+	}
+	return nil
+}
+
 var File_proto_kyc_kyc_proto protoreflect.FileDescriptor
 
 const file_proto_kyc_kyc_proto_rawDesc = "" +
@@ -920,9 +1144,11 @@ const file_proto_kyc_kyc_proto_rawDesc = "" +
 	"\x10next_of_kin_name\x18\r \x01(\tR\rnextOfKinName\x12\x14\n" +
 	"\x05email\x18\x0e \x01(\tR\x05email\".\n" +
 	"\x13GetKYCStatusRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\".\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"a\n" +
 	"\x14GetKYCStatusResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"4\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
+	"\tkyc_level\x18\x02 \x01(\x05R\bkycLevel\x12\x14\n" +
+	"\x05found\x18\x03 \x01(\bR\x05found\"4\n" +
 	"\x19GetFullKYCForAdminRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"U\n" +
 	"\x1aGetFullKYCForAdminResponse\x12\x14\n" +
@@ -934,7 +1160,19 @@ const file_proto_kyc_kyc_proto_rawDesc = "" +
 	"\n" +
 	"_kyc_level\"-\n" +
 	"\x15CountProfilesResponse\x12\x14\n" +
-	"\x05count\x18\x01 \x01(\x03R\x05count2\xe0\x03\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"0\n" +
+	"\x15ExportUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"Q\n" +
+	"\x16ExportUserDataResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12!\n" +
+	"\fjson_payload\x18\x02 \x01(\tR\vjsonPayload\"/\n" +
+	"\x14EraseUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x82\x01\n" +
+	"\x15EraseUserDataResponse\x12\x12\n" +
+	"\x04held\x18\x01 \x01(\bR\x04held\x12!\n" +
+	"\fhold_reasons\x18\x02 \x03(\tR\vholdReasons\x12\x16\n" +
+	"\x06erased\x18\x03 \x03(\tR\x06erased\x12\x1a\n" +
+	"\bretained\x18\x04 \x03(\tR\bretained2\xf3\x04\n" +
 	"\n" +
 	"KYCService\x12C\n" +
 	"\fGetKYCStatus\x12\x18.kyc.GetKYCStatusRequest\x1a\x19.kyc.GetKYCStatusResponse\x12U\n" +
@@ -943,7 +1181,9 @@ const file_proto_kyc_kyc_proto_rawDesc = "" +
 	"\x0fGetKYCForWallet\x12\x1b.kyc.GetKYCForWalletRequest\x1a\x1c.kyc.GetKYCForWalletResponse\x12a\n" +
 	"\x16GetKYCForWalletUpgrade\x12\".kyc.GetKYCForWalletUpgradeRequest\x1a#.kyc.GetKYCForWalletUpgradeResponse\x12=\n" +
 	"\n" +
-	"ApproveKYC\x12\x16.kyc.ApproveKYCRequest\x1a\x17.kyc.ApproveKYCResponseB3Z1github.com/abubakvr/payup-backend/proto/kyc;kycpbb\x06proto3"
+	"ApproveKYC\x12\x16.kyc.ApproveKYCRequest\x1a\x17.kyc.ApproveKYCResponse\x12I\n" +
+	"\x0eExportUserData\x12\x1a.kyc.ExportUserDataRequest\x1a\x1b.kyc.ExportUserDataResponse\x12F\n" +
+	"\rEraseUserData\x12\x19.kyc.EraseUserDataRequest\x1a\x1a.kyc.EraseUserDataResponseB3Z1github.com/abubakvr/payup-backend/proto/kyc;kycpbb\x06proto3"
 
 var (
 	file_proto_kyc_kyc_proto_rawDescOnce sync.Once
@@ -957,7 +1197,7 @@ func file_proto_kyc_kyc_proto_rawDescGZIP() []byte {
 	return file_proto_kyc_kyc_proto_rawDescData
 }
 
-var file_proto_kyc_kyc_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_kyc_kyc_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_proto_kyc_kyc_proto_goTypes = []any{
 	(*GetKYCForWalletUpgradeRequest)(nil),  // 0: kyc.GetKYCForWalletUpgradeRequest
 	(*GetKYCForWalletUpgradeResponse)(nil), // 1: kyc.GetKYCForWalletUpgradeResponse
@@ -971,6 +1211,10 @@ var file_proto_kyc_kyc_proto_goTypes = []any{
 	(*GetFullKYCForAdminResponse)(nil),     // 9: kyc.GetFullKYCForAdminResponse
 	(*CountProfilesRequest)(nil),           // 10: kyc.CountProfilesRequest
 	(*CountProfilesResponse)(nil),          // 11: kyc.CountProfilesResponse
+	(*ExportUserDataRequest)(nil),          // 12: kyc.ExportUserDataRequest
+	(*ExportUserDataResponse)(nil),         // 13: kyc.ExportUserDataResponse
+	(*EraseUserDataRequest)(nil),           // 14: kyc.EraseUserDataRequest
+	(*EraseUserDataResponse)(nil),          // 15: kyc.EraseUserDataResponse
 }
 var file_proto_kyc_kyc_proto_depIdxs = []int32{
 	6,  // 0: kyc.KYCService.GetKYCStatus:input_type -> kyc.GetKYCStatusRequest
@@ -979,14 +1223,18 @@ var file_proto_kyc_kyc_proto_depIdxs = []int32{
 	4,  // 3: kyc.KYCService.GetKYCForWallet:input_type -> kyc.GetKYCForWalletRequest
 	0,  // 4: kyc.KYCService.GetKYCForWalletUpgrade:input_type -> kyc.GetKYCForWalletUpgradeRequest
 	2,  // 5: kyc.KYCService.ApproveKYC:input_type -> kyc.ApproveKYCRequest
-	7,  // 6: kyc.KYCService.GetKYCStatus:output_type -> kyc.GetKYCStatusResponse
-	9,  // 7: kyc.KYCService.GetFullKYCForAdmin:output_type -> kyc.GetFullKYCForAdminResponse
-	11, // 8: kyc.KYCService.CountProfiles:output_type -> kyc.CountProfilesResponse
-	5,  // 9: kyc.KYCService.GetKYCForWallet:output_type -> kyc.GetKYCForWalletResponse
-	1,  // 10: kyc.KYCService.GetKYCForWalletUpgrade:output_type -> kyc.GetKYCForWalletUpgradeResponse
-	3,  // 11: kyc.KYCService.ApproveKYC:output_type -> kyc.ApproveKYCResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
+	12, // 6: kyc.KYCService.ExportUserData:input_type -> kyc.ExportUserDataRequest
+	14, // 7: kyc.KYCService.EraseUserData:input_type -> kyc.EraseUserDataRequest
+	7,  // 8: kyc.KYCService.GetKYCStatus:output_type -> kyc.GetKYCStatusResponse
+	9,  // 9: kyc.KYCService.GetFullKYCForAdmin:output_type -> kyc.GetFullKYCForAdminResponse
+	11, // 10: kyc.KYCService.CountProfiles:output_type -> kyc.CountProfilesResponse
+	5,  // 11: kyc.KYCService.GetKYCForWallet:output_type -> kyc.GetKYCForWalletResponse
+	1,  // 12: kyc.KYCService.GetKYCForWalletUpgrade:output_type -> kyc.GetKYCForWalletUpgradeResponse
+	3,  // 13: kyc.KYCService.ApproveKYC:output_type -> kyc.ApproveKYCResponse
+	13, // 14: kyc.KYCService.ExportUserData:output_type -> kyc.ExportUserDataResponse
+	15, // 15: kyc.KYCService.EraseUserData:output_type -> kyc.EraseUserDataResponse
+	8,  // [8:16] is the sub-list for method output_type
+	0,  // [0:8] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -1004,7 +1252,7 @@ func file_proto_kyc_kyc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_kyc_kyc_proto_rawDesc), len(file_proto_kyc_kyc_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
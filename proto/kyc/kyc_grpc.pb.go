@@ -25,6 +25,8 @@ const (
 	KYCService_GetKYCForWallet_FullMethodName        = "/kyc.KYCService/GetKYCForWallet"
 	KYCService_GetKYCForWalletUpgrade_FullMethodName = "/kyc.KYCService/GetKYCForWalletUpgrade"
 	KYCService_ApproveKYC_FullMethodName             = "/kyc.KYCService/ApproveKYC"
+	KYCService_ExportUserData_FullMethodName         = "/kyc.KYCService/ExportUserData"
+	KYCService_EraseUserData_FullMethodName          = "/kyc.KYCService/EraseUserData"
 )
 
 // KYCServiceClient is the client API for KYCService service.
@@ -42,6 +44,10 @@ type KYCServiceClient interface {
 	GetKYCForWalletUpgrade(ctx context.Context, in *GetKYCForWalletUpgradeRequest, opts ...grpc.CallOption) (*GetKYCForWalletUpgradeResponse, error)
 	// ApproveKYC sets the user's KYC overall_status to approved and triggers success email. Used by Admin after wallet creation.
 	ApproveKYC(ctx context.Context, in *ApproveKYCRequest, opts ...grpc.CallOption) (*ApproveKYCResponse, error)
+	// ExportUserData returns everything held for the user as JSON. Used by User service for data-subject access requests.
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error)
+	// EraseUserData deletes the user's KYC records unless they must be kept (AML retention). Used by User service account deletion.
+	EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*EraseUserDataResponse, error)
 }
 
 type kYCServiceClient struct {
@@ -112,6 +118,26 @@ func (c *kYCServiceClient) ApproveKYC(ctx context.Context, in *ApproveKYCRequest
 	return out, nil
 }
 
+func (c *kYCServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportUserDataResponse)
+	err := c.cc.Invoke(ctx, KYCService_ExportUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kYCServiceClient) EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*EraseUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseUserDataResponse)
+	err := c.cc.Invoke(ctx, KYCService_EraseUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KYCServiceServer is the server API for KYCService service.
 // All implementations must embed UnimplementedKYCServiceServer
 // for forward compatibility.
@@ -127,6 +153,10 @@ type KYCServiceServer interface {
 	GetKYCForWalletUpgrade(context.Context, *GetKYCForWalletUpgradeRequest) (*GetKYCForWalletUpgradeResponse, error)
 	// ApproveKYC sets the user's KYC overall_status to approved and triggers success email. Used by Admin after wallet creation.
 	ApproveKYC(context.Context, *ApproveKYCRequest) (*ApproveKYCResponse, error)
+	// ExportUserData returns everything held for the user as JSON. Used by User service for data-subject access requests.
+	ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error)
+	// EraseUserData deletes the user's KYC records unless they must be kept (AML retention). Used by User service account deletion.
+	EraseUserData(context.Context, *EraseUserDataRequest) (*EraseUserDataResponse, error)
 	mustEmbedUnimplementedKYCServiceServer()
 }
 
@@ -155,6 +185,12 @@ func (UnimplementedKYCServiceServer) GetKYCForWalletUpgrade(context.Context, *Ge
 func (UnimplementedKYCServiceServer) ApproveKYC(context.Context, *ApproveKYCRequest) (*ApproveKYCResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ApproveKYC not implemented")
 }
+func (UnimplementedKYCServiceServer) ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedKYCServiceServer) EraseUserData(context.Context, *EraseUserDataRequest) (*EraseUserDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EraseUserData not implemented")
+}
 func (UnimplementedKYCServiceServer) mustEmbedUnimplementedKYCServiceServer() {}
 func (UnimplementedKYCServiceServer) testEmbeddedByValue()                    {}
 
@@ -284,6 +320,42 @@ func _KYCService_ApproveKYC_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KYCService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KYCServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KYCService_ExportUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KYCServiceServer).ExportUserData(ctx, req.(*ExportUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KYCService_EraseUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KYCServiceServer).EraseUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KYCService_EraseUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KYCServiceServer).EraseUserData(ctx, req.(*EraseUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // KYCService_ServiceDesc is the grpc.ServiceDesc for KYCService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -315,6 +387,14 @@ var KYCService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ApproveKYC",
 			Handler:    _KYCService_ApproveKYC_Handler,
 		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _KYCService_ExportUserData_Handler,
+		},
+		{
+			MethodName: "EraseUserData",
+			Handler:    _KYCService_EraseUserData_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/kyc/kyc.proto",
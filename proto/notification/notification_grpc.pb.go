@@ -0,0 +1,210 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v6.33.4
+// source: proto/notification/notification.proto
+
+package notificationpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NotificationServiceForAdmin_ListNotifications_FullMethodName  = "/notification.NotificationServiceForAdmin/ListNotifications"
+	NotificationServiceForAdmin_GetNotification_FullMethodName    = "/notification.NotificationServiceForAdmin/GetNotification"
+	NotificationServiceForAdmin_ResendNotification_FullMethodName = "/notification.NotificationServiceForAdmin/ResendNotification"
+)
+
+// NotificationServiceForAdminClient is the client API for NotificationServiceForAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NotificationServiceForAdmin is used by Admin service for support: a user's notification history, delivery status and
+// resending. Recipients are never returned in full, only masked.
+type NotificationServiceForAdminClient interface {
+	// ListNotifications returns deliveries newest first, for one user or for a recipient address.
+	ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error)
+	GetNotification(ctx context.Context, in *GetNotificationRequest, opts ...grpc.CallOption) (*GetNotificationResponse, error)
+	// ResendNotification sends a copy of a delivery as a new delivery linked to the original. One-time codes are never
+	// resent; the user requests a new one.
+	ResendNotification(ctx context.Context, in *ResendNotificationRequest, opts ...grpc.CallOption) (*ResendNotificationResponse, error)
+}
+
+type notificationServiceForAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNotificationServiceForAdminClient(cc grpc.ClientConnInterface) NotificationServiceForAdminClient {
+	return &notificationServiceForAdminClient{cc}
+}
+
+func (c *notificationServiceForAdminClient) ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNotificationsResponse)
+	err := c.cc.Invoke(ctx, NotificationServiceForAdmin_ListNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceForAdminClient) GetNotification(ctx context.Context, in *GetNotificationRequest, opts ...grpc.CallOption) (*GetNotificationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNotificationResponse)
+	err := c.cc.Invoke(ctx, NotificationServiceForAdmin_GetNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceForAdminClient) ResendNotification(ctx context.Context, in *ResendNotificationRequest, opts ...grpc.CallOption) (*ResendNotificationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResendNotificationResponse)
+	err := c.cc.Invoke(ctx, NotificationServiceForAdmin_ResendNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationServiceForAdminServer is the server API for NotificationServiceForAdmin service.
+// All implementations must embed UnimplementedNotificationServiceForAdminServer
+// for forward compatibility.
+//
+// NotificationServiceForAdmin is used by Admin service for support: a user's notification history, delivery status and
+// resending. Recipients are never returned in full, only masked.
+type NotificationServiceForAdminServer interface {
+	// ListNotifications returns deliveries newest first, for one user or for a recipient address.
+	ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error)
+	GetNotification(context.Context, *GetNotificationRequest) (*GetNotificationResponse, error)
+	// ResendNotification sends a copy of a delivery as a new delivery linked to the original. One-time codes are never
+	// resent; the user requests a new one.
+	ResendNotification(context.Context, *ResendNotificationRequest) (*ResendNotificationResponse, error)
+	mustEmbedUnimplementedNotificationServiceForAdminServer()
+}
+
+// UnimplementedNotificationServiceForAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNotificationServiceForAdminServer struct{}
+
+func (UnimplementedNotificationServiceForAdminServer) ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedNotificationServiceForAdminServer) GetNotification(context.Context, *GetNotificationRequest) (*GetNotificationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNotification not implemented")
+}
+func (UnimplementedNotificationServiceForAdminServer) ResendNotification(context.Context, *ResendNotificationRequest) (*ResendNotificationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResendNotification not implemented")
+}
+func (UnimplementedNotificationServiceForAdminServer) mustEmbedUnimplementedNotificationServiceForAdminServer() {
+}
+func (UnimplementedNotificationServiceForAdminServer) testEmbeddedByValue() {}
+
+// UnsafeNotificationServiceForAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NotificationServiceForAdminServer will
+// result in compilation errors.
+type UnsafeNotificationServiceForAdminServer interface {
+	mustEmbedUnimplementedNotificationServiceForAdminServer()
+}
+
+func RegisterNotificationServiceForAdminServer(s grpc.ServiceRegistrar, srv NotificationServiceForAdminServer) {
+	// If the following call panics, it indicates UnimplementedNotificationServiceForAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NotificationServiceForAdmin_ServiceDesc, srv)
+}
+
+func _NotificationServiceForAdmin_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceForAdminServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationServiceForAdmin_ListNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceForAdminServer).ListNotifications(ctx, req.(*ListNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationServiceForAdmin_GetNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceForAdminServer).GetNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationServiceForAdmin_GetNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceForAdminServer).GetNotification(ctx, req.(*GetNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationServiceForAdmin_ResendNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceForAdminServer).ResendNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotificationServiceForAdmin_ResendNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceForAdminServer).ResendNotification(ctx, req.(*ResendNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NotificationServiceForAdmin_ServiceDesc is the grpc.ServiceDesc for NotificationServiceForAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NotificationServiceForAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "notification.NotificationServiceForAdmin",
+	HandlerType: (*NotificationServiceForAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListNotifications",
+			Handler:    _NotificationServiceForAdmin_ListNotifications_Handler,
+		},
+		{
+			MethodName: "GetNotification",
+			Handler:    _NotificationServiceForAdmin_GetNotification_Handler,
+		},
+		{
+			MethodName: "ResendNotification",
+			Handler:    _NotificationServiceForAdmin_ResendNotification_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/notification/notification.proto",
+}
@@ -21,6 +21,1162 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type DisputeItem struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DisputeRef           string                 `protobuf:"bytes,2,opt,name=dispute_ref,json=disputeRef,proto3" json:"dispute_ref,omitempty"`
+	TransactionRef       string                 `protobuf:"bytes,3,opt,name=transaction_ref,json=transactionRef,proto3" json:"transaction_ref,omitempty"`
+	TransactionAmount    float64                `protobuf:"fixed64,4,opt,name=transaction_amount,json=transactionAmount,proto3" json:"transaction_amount,omitempty"`
+	TransactionStatus    string                 `protobuf:"bytes,5,opt,name=transaction_status,json=transactionStatus,proto3" json:"transaction_status,omitempty"`
+	TransactionDirection string                 `protobuf:"bytes,6,opt,name=transaction_direction,json=transactionDirection,proto3" json:"transaction_direction,omitempty"`
+	WalletId             string                 `protobuf:"bytes,7,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	UserId               string                 `protobuf:"bytes,8,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason               string                 `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"` // NOT_RECEIVED, WRONG_AMOUNT, DUPLICATE, UNAUTHORIZED, OTHER
+	Description          string                 `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
+	Status               string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`                                            // OPEN, UNDER_REVIEW, AWAITING_USER, PAYMENT_PENDING, RESOLVED, REJECTED
+	AssignedTo           string                 `protobuf:"bytes,12,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`                  // admin id
+	SlaDueAt             string                 `protobuf:"bytes,13,opt,name=sla_due_at,json=slaDueAt,proto3" json:"sla_due_at,omitempty"`                      // RFC3339
+	SlaBreached          bool                   `protobuf:"varint,14,opt,name=sla_breached,json=slaBreached,proto3" json:"sla_breached,omitempty"`              // unresolved and past sla_due_at
+	FirstResponseAt      string                 `protobuf:"bytes,15,opt,name=first_response_at,json=firstResponseAt,proto3" json:"first_response_at,omitempty"` // RFC3339
+	Resolution           string                 `protobuf:"bytes,16,opt,name=resolution,proto3" json:"resolution,omitempty"`                                    // REVERSAL, ADJUSTMENT, NO_ACTION
+	ResolutionAmount     float64                `protobuf:"fixed64,17,opt,name=resolution_amount,json=resolutionAmount,proto3" json:"resolution_amount,omitempty"`
+	ResolutionTxnRef     string                 `protobuf:"bytes,18,opt,name=resolution_txn_ref,json=resolutionTxnRef,proto3" json:"resolution_txn_ref,omitempty"`
+	ResolutionNote       string                 `protobuf:"bytes,19,opt,name=resolution_note,json=resolutionNote,proto3" json:"resolution_note,omitempty"`
+	ResolvedBy           string                 `protobuf:"bytes,20,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`
+	ResolvedAt           string                 `protobuf:"bytes,21,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"` // RFC3339
+	CreatedAt            string                 `protobuf:"bytes,22,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            string                 `protobuf:"bytes,23,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *DisputeItem) Reset() {
+	*x = DisputeItem{}
+	mi := &file_proto_payment_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisputeItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeItem) ProtoMessage() {}
+
+func (x *DisputeItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeItem.ProtoReflect.Descriptor instead.
+func (*DisputeItem) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DisputeItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetDisputeRef() string {
+	if x != nil {
+		return x.DisputeRef
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetTransactionRef() string {
+	if x != nil {
+		return x.TransactionRef
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetTransactionAmount() float64 {
+	if x != nil {
+		return x.TransactionAmount
+	}
+	return 0
+}
+
+func (x *DisputeItem) GetTransactionStatus() string {
+	if x != nil {
+		return x.TransactionStatus
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetTransactionDirection() string {
+	if x != nil {
+		return x.TransactionDirection
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetSlaDueAt() string {
+	if x != nil {
+		return x.SlaDueAt
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetSlaBreached() bool {
+	if x != nil {
+		return x.SlaBreached
+	}
+	return false
+}
+
+func (x *DisputeItem) GetFirstResponseAt() string {
+	if x != nil {
+		return x.FirstResponseAt
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetResolutionAmount() float64 {
+	if x != nil {
+		return x.ResolutionAmount
+	}
+	return 0
+}
+
+func (x *DisputeItem) GetResolutionTxnRef() string {
+	if x != nil {
+		return x.ResolutionTxnRef
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetResolutionNote() string {
+	if x != nil {
+		return x.ResolutionNote
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetResolvedAt() string {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *DisputeItem) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type DisputeNote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorType    string                 `protobuf:"bytes,2,opt,name=author_type,json=authorType,proto3" json:"author_type,omitempty"` // USER, ADMIN, SYSTEM
+	AuthorId      string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	IsInternal    bool                   `protobuf:"varint,5,opt,name=is_internal,json=isInternal,proto3" json:"is_internal,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisputeNote) Reset() {
+	*x = DisputeNote{}
+	mi := &file_proto_payment_payment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisputeNote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeNote) ProtoMessage() {}
+
+func (x *DisputeNote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeNote.ProtoReflect.Descriptor instead.
+func (*DisputeNote) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DisputeNote) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DisputeNote) GetAuthorType() string {
+	if x != nil {
+		return x.AuthorType
+	}
+	return ""
+}
+
+func (x *DisputeNote) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *DisputeNote) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *DisputeNote) GetIsInternal() bool {
+	if x != nil {
+		return x.IsInternal
+	}
+	return false
+}
+
+func (x *DisputeNote) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type DisputeAttachment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UploadedByType string                 `protobuf:"bytes,2,opt,name=uploaded_by_type,json=uploadedByType,proto3" json:"uploaded_by_type,omitempty"` // USER, ADMIN
+	UploadedBy     string                 `protobuf:"bytes,3,opt,name=uploaded_by,json=uploadedBy,proto3" json:"uploaded_by,omitempty"`
+	FileName       string                 `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	ContentType    string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Url            string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DisputeAttachment) Reset() {
+	*x = DisputeAttachment{}
+	mi := &file_proto_payment_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisputeAttachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeAttachment) ProtoMessage() {}
+
+func (x *DisputeAttachment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeAttachment.ProtoReflect.Descriptor instead.
+func (*DisputeAttachment) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DisputeAttachment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DisputeAttachment) GetUploadedByType() string {
+	if x != nil {
+		return x.UploadedByType
+	}
+	return ""
+}
+
+func (x *DisputeAttachment) GetUploadedBy() string {
+	if x != nil {
+		return x.UploadedBy
+	}
+	return ""
+}
+
+func (x *DisputeAttachment) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *DisputeAttachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *DisputeAttachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DisputeAttachment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListDisputesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // optional filter
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // default 50, max 100
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDisputesRequest) Reset() {
+	*x = ListDisputesRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDisputesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDisputesRequest) ProtoMessage() {}
+
+func (x *ListDisputesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDisputesRequest.ProtoReflect.Descriptor instead.
+func (*ListDisputesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListDisputesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListDisputesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDisputesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListDisputesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Disputes      []*DisputeItem         `protobuf:"bytes,1,rep,name=disputes,proto3" json:"disputes,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDisputesResponse) Reset() {
+	*x = ListDisputesResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDisputesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDisputesResponse) ProtoMessage() {}
+
+func (x *ListDisputesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDisputesResponse.ProtoReflect.Descriptor instead.
+func (*ListDisputesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListDisputesResponse) GetDisputes() []*DisputeItem {
+	if x != nil {
+		return x.Disputes
+	}
+	return nil
+}
+
+func (x *ListDisputesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetDisputeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // dispute UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDisputeRequest) Reset() {
+	*x = GetDisputeRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDisputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDisputeRequest) ProtoMessage() {}
+
+func (x *GetDisputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDisputeRequest.ProtoReflect.Descriptor instead.
+func (*GetDisputeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetDisputeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetDisputeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Item          *DisputeItem           `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	Notes         []*DisputeNote         `protobuf:"bytes,3,rep,name=notes,proto3" json:"notes,omitempty"`
+	Attachments   []*DisputeAttachment   `protobuf:"bytes,4,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDisputeResponse) Reset() {
+	*x = GetDisputeResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDisputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDisputeResponse) ProtoMessage() {}
+
+func (x *GetDisputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDisputeResponse.ProtoReflect.Descriptor instead.
+func (*GetDisputeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetDisputeResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetDisputeResponse) GetItem() *DisputeItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *GetDisputeResponse) GetNotes() []*DisputeNote {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *GetDisputeResponse) GetAttachments() []*DisputeAttachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type UpdateDisputeStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                              // UNDER_REVIEW, AWAITING_USER or REJECTED
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`                                  // user-visible; required for REJECTED
+	InitiatedBy   string                 `protobuf:"bytes,4,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"` // admin user id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDisputeStatusRequest) Reset() {
+	*x = UpdateDisputeStatusRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDisputeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDisputeStatusRequest) ProtoMessage() {}
+
+func (x *UpdateDisputeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDisputeStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDisputeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateDisputeStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateDisputeStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateDisputeStatusRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *UpdateDisputeStatusRequest) GetInitiatedBy() string {
+	if x != nil {
+		return x.InitiatedBy
+	}
+	return ""
+}
+
+type UpdateDisputeStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Item          *DisputeItem           `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDisputeStatusResponse) Reset() {
+	*x = UpdateDisputeStatusResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDisputeStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDisputeStatusResponse) ProtoMessage() {}
+
+func (x *UpdateDisputeStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDisputeStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDisputeStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateDisputeStatusResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateDisputeStatusResponse) GetItem() *DisputeItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *UpdateDisputeStatusResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type AddDisputeNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	IsInternal    bool                   `protobuf:"varint,3,opt,name=is_internal,json=isInternal,proto3" json:"is_internal,omitempty"`
+	InitiatedBy   string                 `protobuf:"bytes,4,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"` // admin user id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDisputeNoteRequest) Reset() {
+	*x = AddDisputeNoteRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDisputeNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDisputeNoteRequest) ProtoMessage() {}
+
+func (x *AddDisputeNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDisputeNoteRequest.ProtoReflect.Descriptor instead.
+func (*AddDisputeNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AddDisputeNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddDisputeNoteRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *AddDisputeNoteRequest) GetIsInternal() bool {
+	if x != nil {
+		return x.IsInternal
+	}
+	return false
+}
+
+func (x *AddDisputeNoteRequest) GetInitiatedBy() string {
+	if x != nil {
+		return x.InitiatedBy
+	}
+	return ""
+}
+
+type AddDisputeNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Note          *DisputeNote           `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDisputeNoteResponse) Reset() {
+	*x = AddDisputeNoteResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDisputeNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDisputeNoteResponse) ProtoMessage() {}
+
+func (x *AddDisputeNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDisputeNoteResponse.ProtoReflect.Descriptor instead.
+func (*AddDisputeNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddDisputeNoteResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddDisputeNoteResponse) GetNote() *DisputeNote {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *AddDisputeNoteResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type AddDisputeAttachmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`                                    // https
+	InitiatedBy   string                 `protobuf:"bytes,5,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"` // admin user id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDisputeAttachmentRequest) Reset() {
+	*x = AddDisputeAttachmentRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDisputeAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDisputeAttachmentRequest) ProtoMessage() {}
+
+func (x *AddDisputeAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDisputeAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*AddDisputeAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddDisputeAttachmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddDisputeAttachmentRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *AddDisputeAttachmentRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *AddDisputeAttachmentRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AddDisputeAttachmentRequest) GetInitiatedBy() string {
+	if x != nil {
+		return x.InitiatedBy
+	}
+	return ""
+}
+
+type AddDisputeAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Attachment    *DisputeAttachment     `protobuf:"bytes,2,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDisputeAttachmentResponse) Reset() {
+	*x = AddDisputeAttachmentResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDisputeAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDisputeAttachmentResponse) ProtoMessage() {}
+
+func (x *AddDisputeAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDisputeAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*AddDisputeAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AddDisputeAttachmentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddDisputeAttachmentResponse) GetAttachment() *DisputeAttachment {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+func (x *AddDisputeAttachmentResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type ResolveDisputeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Resolution    string                 `protobuf:"bytes,2,opt,name=resolution,proto3" json:"resolution,omitempty"`              // REVERSAL, ADJUSTMENT or NO_ACTION
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`                    // ADJUSTMENT only
+	IsCredit      bool                   `protobuf:"varint,4,opt,name=is_credit,json=isCredit,proto3" json:"is_credit,omitempty"` // ADJUSTMENT only: true = credit user, false = debit
+	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	InitiatedBy   string                 `protobuf:"bytes,6,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"` // admin user id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveDisputeRequest) Reset() {
+	*x = ResolveDisputeRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveDisputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveDisputeRequest) ProtoMessage() {}
+
+func (x *ResolveDisputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveDisputeRequest.ProtoReflect.Descriptor instead.
+func (*ResolveDisputeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResolveDisputeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResolveDisputeRequest) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *ResolveDisputeRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ResolveDisputeRequest) GetIsCredit() bool {
+	if x != nil {
+		return x.IsCredit
+	}
+	return false
+}
+
+func (x *ResolveDisputeRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ResolveDisputeRequest) GetInitiatedBy() string {
+	if x != nil {
+		return x.InitiatedBy
+	}
+	return ""
+}
+
+type ResolveDisputeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Item          *DisputeItem           `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveDisputeResponse) Reset() {
+	*x = ResolveDisputeResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveDisputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveDisputeResponse) ProtoMessage() {}
+
+func (x *ResolveDisputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveDisputeResponse.ProtoReflect.Descriptor instead.
+func (*ResolveDisputeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ResolveDisputeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResolveDisputeResponse) GetItem() *DisputeItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *ResolveDisputeResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 type SubmitWalletUpgradeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // wallet owner
@@ -31,7 +1187,7 @@ type SubmitWalletUpgradeRequest struct {
 
 func (x *SubmitWalletUpgradeRequest) Reset() {
 	*x = SubmitWalletUpgradeRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[0]
+	mi := &file_proto_payment_payment_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -43,7 +1199,7 @@ func (x *SubmitWalletUpgradeRequest) String() string {
 func (*SubmitWalletUpgradeRequest) ProtoMessage() {}
 
 func (x *SubmitWalletUpgradeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[0]
+	mi := &file_proto_payment_payment_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,7 +1212,7 @@ func (x *SubmitWalletUpgradeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitWalletUpgradeRequest.ProtoReflect.Descriptor instead.
 func (*SubmitWalletUpgradeRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{0}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *SubmitWalletUpgradeRequest) GetUserId() string {
@@ -84,7 +1240,7 @@ type SubmitWalletUpgradeResponse struct {
 
 func (x *SubmitWalletUpgradeResponse) Reset() {
 	*x = SubmitWalletUpgradeResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[1]
+	mi := &file_proto_payment_payment_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -96,7 +1252,7 @@ func (x *SubmitWalletUpgradeResponse) String() string {
 func (*SubmitWalletUpgradeResponse) ProtoMessage() {}
 
 func (x *SubmitWalletUpgradeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[1]
+	mi := &file_proto_payment_payment_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -109,7 +1265,7 @@ func (x *SubmitWalletUpgradeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubmitWalletUpgradeResponse.ProtoReflect.Descriptor instead.
 func (*SubmitWalletUpgradeResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{1}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *SubmitWalletUpgradeResponse) GetSuccess() bool {
@@ -143,7 +1299,7 @@ type ListWalletUpgradeRequestsRequest struct {
 
 func (x *ListWalletUpgradeRequestsRequest) Reset() {
 	*x = ListWalletUpgradeRequestsRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[2]
+	mi := &file_proto_payment_payment_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -155,7 +1311,7 @@ func (x *ListWalletUpgradeRequestsRequest) String() string {
 func (*ListWalletUpgradeRequestsRequest) ProtoMessage() {}
 
 func (x *ListWalletUpgradeRequestsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[2]
+	mi := &file_proto_payment_payment_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +1324,7 @@ func (x *ListWalletUpgradeRequestsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWalletUpgradeRequestsRequest.ProtoReflect.Descriptor instead.
 func (*ListWalletUpgradeRequestsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{2}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ListWalletUpgradeRequestsRequest) GetLimit() int32 {
@@ -206,7 +1362,7 @@ type WalletUpgradeRequestItem struct {
 
 func (x *WalletUpgradeRequestItem) Reset() {
 	*x = WalletUpgradeRequestItem{}
-	mi := &file_proto_payment_payment_proto_msgTypes[3]
+	mi := &file_proto_payment_payment_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -218,7 +1374,7 @@ func (x *WalletUpgradeRequestItem) String() string {
 func (*WalletUpgradeRequestItem) ProtoMessage() {}
 
 func (x *WalletUpgradeRequestItem) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[3]
+	mi := &file_proto_payment_payment_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -231,7 +1387,7 @@ func (x *WalletUpgradeRequestItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WalletUpgradeRequestItem.ProtoReflect.Descriptor instead.
 func (*WalletUpgradeRequestItem) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{3}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *WalletUpgradeRequestItem) GetId() string {
@@ -334,7 +1490,7 @@ type ListWalletUpgradeRequestsResponse struct {
 
 func (x *ListWalletUpgradeRequestsResponse) Reset() {
 	*x = ListWalletUpgradeRequestsResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[4]
+	mi := &file_proto_payment_payment_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -346,7 +1502,7 @@ func (x *ListWalletUpgradeRequestsResponse) String() string {
 func (*ListWalletUpgradeRequestsResponse) ProtoMessage() {}
 
 func (x *ListWalletUpgradeRequestsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[4]
+	mi := &file_proto_payment_payment_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -359,7 +1515,7 @@ func (x *ListWalletUpgradeRequestsResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use ListWalletUpgradeRequestsResponse.ProtoReflect.Descriptor instead.
 func (*ListWalletUpgradeRequestsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{4}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListWalletUpgradeRequestsResponse) GetRequests() []*WalletUpgradeRequestItem {
@@ -378,7 +1534,7 @@ type GetWalletUpgradeRequestRequest struct {
 
 func (x *GetWalletUpgradeRequestRequest) Reset() {
 	*x = GetWalletUpgradeRequestRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[5]
+	mi := &file_proto_payment_payment_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -390,7 +1546,7 @@ func (x *GetWalletUpgradeRequestRequest) String() string {
 func (*GetWalletUpgradeRequestRequest) ProtoMessage() {}
 
 func (x *GetWalletUpgradeRequestRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[5]
+	mi := &file_proto_payment_payment_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +1559,7 @@ func (x *GetWalletUpgradeRequestRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWalletUpgradeRequestRequest.ProtoReflect.Descriptor instead.
 func (*GetWalletUpgradeRequestRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{5}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetWalletUpgradeRequestRequest) GetId() string {
@@ -425,7 +1581,7 @@ type GetWalletUpgradeRequestResponse struct {
 
 func (x *GetWalletUpgradeRequestResponse) Reset() {
 	*x = GetWalletUpgradeRequestResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[6]
+	mi := &file_proto_payment_payment_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -437,7 +1593,7 @@ func (x *GetWalletUpgradeRequestResponse) String() string {
 func (*GetWalletUpgradeRequestResponse) ProtoMessage() {}
 
 func (x *GetWalletUpgradeRequestResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[6]
+	mi := &file_proto_payment_payment_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -450,7 +1606,7 @@ func (x *GetWalletUpgradeRequestResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWalletUpgradeRequestResponse.ProtoReflect.Descriptor instead.
 func (*GetWalletUpgradeRequestResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{6}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetWalletUpgradeRequestResponse) GetFound() bool {
@@ -490,7 +1646,7 @@ type GetWalletUpgradeStatusByUserIDRequest struct {
 
 func (x *GetWalletUpgradeStatusByUserIDRequest) Reset() {
 	*x = GetWalletUpgradeStatusByUserIDRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[7]
+	mi := &file_proto_payment_payment_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -502,7 +1658,7 @@ func (x *GetWalletUpgradeStatusByUserIDRequest) String() string {
 func (*GetWalletUpgradeStatusByUserIDRequest) ProtoMessage() {}
 
 func (x *GetWalletUpgradeStatusByUserIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[7]
+	mi := &file_proto_payment_payment_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -515,7 +1671,7 @@ func (x *GetWalletUpgradeStatusByUserIDRequest) ProtoReflect() protoreflect.Mess
 
 // Deprecated: Use GetWalletUpgradeStatusByUserIDRequest.ProtoReflect.Descriptor instead.
 func (*GetWalletUpgradeStatusByUserIDRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{7}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GetWalletUpgradeStatusByUserIDRequest) GetUserId() string {
@@ -538,7 +1694,7 @@ type UpgradeStatusFrom9PSB struct {
 
 func (x *UpgradeStatusFrom9PSB) Reset() {
 	*x = UpgradeStatusFrom9PSB{}
-	mi := &file_proto_payment_payment_proto_msgTypes[8]
+	mi := &file_proto_payment_payment_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -550,7 +1706,7 @@ func (x *UpgradeStatusFrom9PSB) String() string {
 func (*UpgradeStatusFrom9PSB) ProtoMessage() {}
 
 func (x *UpgradeStatusFrom9PSB) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[8]
+	mi := &file_proto_payment_payment_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -563,7 +1719,7 @@ func (x *UpgradeStatusFrom9PSB) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpgradeStatusFrom9PSB.ProtoReflect.Descriptor instead.
 func (*UpgradeStatusFrom9PSB) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{8}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *UpgradeStatusFrom9PSB) GetStatus() string {
@@ -605,7 +1761,7 @@ type GetWalletUpgradeStatusByUserIDResponse struct {
 
 func (x *GetWalletUpgradeStatusByUserIDResponse) Reset() {
 	*x = GetWalletUpgradeStatusByUserIDResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[9]
+	mi := &file_proto_payment_payment_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -617,7 +1773,7 @@ func (x *GetWalletUpgradeStatusByUserIDResponse) String() string {
 func (*GetWalletUpgradeStatusByUserIDResponse) ProtoMessage() {}
 
 func (x *GetWalletUpgradeStatusByUserIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[9]
+	mi := &file_proto_payment_payment_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,7 +1786,7 @@ func (x *GetWalletUpgradeStatusByUserIDResponse) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use GetWalletUpgradeStatusByUserIDResponse.ProtoReflect.Descriptor instead.
 func (*GetWalletUpgradeStatusByUserIDResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{9}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *GetWalletUpgradeStatusByUserIDResponse) GetHasWallet() bool {
@@ -662,7 +1818,7 @@ type HealthRequest struct {
 
 func (x *HealthRequest) Reset() {
 	*x = HealthRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[10]
+	mi := &file_proto_payment_payment_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -674,7 +1830,7 @@ func (x *HealthRequest) String() string {
 func (*HealthRequest) ProtoMessage() {}
 
 func (x *HealthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[10]
+	mi := &file_proto_payment_payment_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -687,7 +1843,7 @@ func (x *HealthRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
 func (*HealthRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{10}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{25}
 }
 
 type HealthResponse struct {
@@ -699,7 +1855,7 @@ type HealthResponse struct {
 
 func (x *HealthResponse) Reset() {
 	*x = HealthResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[11]
+	mi := &file_proto_payment_payment_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -711,7 +1867,7 @@ func (x *HealthResponse) String() string {
 func (*HealthResponse) ProtoMessage() {}
 
 func (x *HealthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[11]
+	mi := &file_proto_payment_payment_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -724,7 +1880,7 @@ func (x *HealthResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
 func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{11}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *HealthResponse) GetStatus() string {
@@ -743,7 +1899,7 @@ type CreateWalletRequest struct {
 
 func (x *CreateWalletRequest) Reset() {
 	*x = CreateWalletRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[12]
+	mi := &file_proto_payment_payment_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -755,7 +1911,7 @@ func (x *CreateWalletRequest) String() string {
 func (*CreateWalletRequest) ProtoMessage() {}
 
 func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[12]
+	mi := &file_proto_payment_payment_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -768,7 +1924,7 @@ func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateWalletRequest.ProtoReflect.Descriptor instead.
 func (*CreateWalletRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{12}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *CreateWalletRequest) GetUserId() string {
@@ -789,7 +1945,7 @@ type CreateWalletResponse struct {
 
 func (x *CreateWalletResponse) Reset() {
 	*x = CreateWalletResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[13]
+	mi := &file_proto_payment_payment_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -801,7 +1957,7 @@ func (x *CreateWalletResponse) String() string {
 func (*CreateWalletResponse) ProtoMessage() {}
 
 func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[13]
+	mi := &file_proto_payment_payment_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -814,7 +1970,7 @@ func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateWalletResponse.ProtoReflect.Descriptor instead.
 func (*CreateWalletResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{13}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *CreateWalletResponse) GetSuccess() bool {
@@ -848,7 +2004,7 @@ type ListWalletsRequest struct {
 
 func (x *ListWalletsRequest) Reset() {
 	*x = ListWalletsRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[14]
+	mi := &file_proto_payment_payment_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -860,7 +2016,7 @@ func (x *ListWalletsRequest) String() string {
 func (*ListWalletsRequest) ProtoMessage() {}
 
 func (x *ListWalletsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[14]
+	mi := &file_proto_payment_payment_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -873,7 +2029,7 @@ func (x *ListWalletsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWalletsRequest.ProtoReflect.Descriptor instead.
 func (*ListWalletsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{14}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ListWalletsRequest) GetLimit() int32 {
@@ -914,7 +2070,7 @@ type WalletDetail struct {
 
 func (x *WalletDetail) Reset() {
 	*x = WalletDetail{}
-	mi := &file_proto_payment_payment_proto_msgTypes[15]
+	mi := &file_proto_payment_payment_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -926,7 +2082,7 @@ func (x *WalletDetail) String() string {
 func (*WalletDetail) ProtoMessage() {}
 
 func (x *WalletDetail) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[15]
+	mi := &file_proto_payment_payment_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -939,7 +2095,7 @@ func (x *WalletDetail) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WalletDetail.ProtoReflect.Descriptor instead.
 func (*WalletDetail) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{15}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *WalletDetail) GetId() string {
@@ -1063,7 +2219,7 @@ type ListWalletsResponse struct {
 
 func (x *ListWalletsResponse) Reset() {
 	*x = ListWalletsResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[16]
+	mi := &file_proto_payment_payment_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1075,7 +2231,7 @@ func (x *ListWalletsResponse) String() string {
 func (*ListWalletsResponse) ProtoMessage() {}
 
 func (x *ListWalletsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[16]
+	mi := &file_proto_payment_payment_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1088,7 +2244,7 @@ func (x *ListWalletsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWalletsResponse.ProtoReflect.Descriptor instead.
 func (*ListWalletsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{16}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *ListWalletsResponse) GetWallets() []*WalletDetail {
@@ -1099,19 +2255,20 @@ func (x *ListWalletsResponse) GetWallets() []*WalletDetail {
 }
 
 type DebitCreditWalletRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // wallet owner
-	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`                            // must be positive
-	IsCredit      bool                   `protobuf:"varint,3,opt,name=is_credit,json=isCredit,proto3" json:"is_credit,omitempty"`         // true = credit (add), false = debit (deduct)
-	Narration     string                 `protobuf:"bytes,4,opt,name=narration,proto3" json:"narration,omitempty"`                        // e.g. "Airtime - 08012345678", "Data bundle", "Electricity", "DSTV", "Admin credit"
-	InitiatedBy   string                 `protobuf:"bytes,5,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"` // admin user id or "system"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                         // wallet owner
+	Amount         float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`                                     // must be positive
+	IsCredit       bool                   `protobuf:"varint,3,opt,name=is_credit,json=isCredit,proto3" json:"is_credit,omitempty"`                  // true = credit (add), false = debit (deduct)
+	Narration      string                 `protobuf:"bytes,4,opt,name=narration,proto3" json:"narration,omitempty"`                                 // e.g. "Airtime - 08012345678", "Data bundle", "Electricity", "DSTV", "Admin credit"
+	InitiatedBy    string                 `protobuf:"bytes,5,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"`          // admin user id or "system"
+	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // optional; a repeat with the same key returns the first transaction instead of moving money again
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *DebitCreditWalletRequest) Reset() {
 	*x = DebitCreditWalletRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[17]
+	mi := &file_proto_payment_payment_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1123,7 +2280,7 @@ func (x *DebitCreditWalletRequest) String() string {
 func (*DebitCreditWalletRequest) ProtoMessage() {}
 
 func (x *DebitCreditWalletRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[17]
+	mi := &file_proto_payment_payment_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1136,7 +2293,7 @@ func (x *DebitCreditWalletRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DebitCreditWalletRequest.ProtoReflect.Descriptor instead.
 func (*DebitCreditWalletRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{17}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *DebitCreditWalletRequest) GetUserId() string {
@@ -1174,18 +2331,26 @@ func (x *DebitCreditWalletRequest) GetInitiatedBy() string {
 	return ""
 }
 
+func (x *DebitCreditWalletRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type DebitCreditWalletResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	TransactionRef string                 `protobuf:"bytes,2,opt,name=transaction_ref,json=transactionRef,proto3" json:"transaction_ref,omitempty"`
 	ErrorMessage   string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Duplicate      bool                   `protobuf:"varint,4,opt,name=duplicate,proto3" json:"duplicate,omitempty"` // true when idempotency_key had already been used; transaction_ref is the original one
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *DebitCreditWalletResponse) Reset() {
 	*x = DebitCreditWalletResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[18]
+	mi := &file_proto_payment_payment_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1197,7 +2362,7 @@ func (x *DebitCreditWalletResponse) String() string {
 func (*DebitCreditWalletResponse) ProtoMessage() {}
 
 func (x *DebitCreditWalletResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[18]
+	mi := &file_proto_payment_payment_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1210,7 +2375,7 @@ func (x *DebitCreditWalletResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DebitCreditWalletResponse.ProtoReflect.Descriptor instead.
 func (*DebitCreditWalletResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{18}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *DebitCreditWalletResponse) GetSuccess() bool {
@@ -1234,6 +2399,13 @@ func (x *DebitCreditWalletResponse) GetErrorMessage() string {
 	return ""
 }
 
+func (x *DebitCreditWalletResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
 type GetWaasTransactionHistoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -1246,7 +2418,7 @@ type GetWaasTransactionHistoryRequest struct {
 
 func (x *GetWaasTransactionHistoryRequest) Reset() {
 	*x = GetWaasTransactionHistoryRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[19]
+	mi := &file_proto_payment_payment_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1258,7 +2430,7 @@ func (x *GetWaasTransactionHistoryRequest) String() string {
 func (*GetWaasTransactionHistoryRequest) ProtoMessage() {}
 
 func (x *GetWaasTransactionHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[19]
+	mi := &file_proto_payment_payment_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1271,7 +2443,7 @@ func (x *GetWaasTransactionHistoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWaasTransactionHistoryRequest.ProtoReflect.Descriptor instead.
 func (*GetWaasTransactionHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{19}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *GetWaasTransactionHistoryRequest) GetUserId() string {
@@ -1320,7 +2492,7 @@ type WaasTransactionItem struct {
 
 func (x *WaasTransactionItem) Reset() {
 	*x = WaasTransactionItem{}
-	mi := &file_proto_payment_payment_proto_msgTypes[20]
+	mi := &file_proto_payment_payment_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1332,7 +2504,7 @@ func (x *WaasTransactionItem) String() string {
 func (*WaasTransactionItem) ProtoMessage() {}
 
 func (x *WaasTransactionItem) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[20]
+	mi := &file_proto_payment_payment_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1345,7 +2517,7 @@ func (x *WaasTransactionItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WaasTransactionItem.ProtoReflect.Descriptor instead.
 func (*WaasTransactionItem) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{20}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *WaasTransactionItem) GetTransactionDate() string {
@@ -1430,7 +2602,7 @@ type GetWaasTransactionHistoryResponse struct {
 
 func (x *GetWaasTransactionHistoryResponse) Reset() {
 	*x = GetWaasTransactionHistoryResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[21]
+	mi := &file_proto_payment_payment_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1442,7 +2614,7 @@ func (x *GetWaasTransactionHistoryResponse) String() string {
 func (*GetWaasTransactionHistoryResponse) ProtoMessage() {}
 
 func (x *GetWaasTransactionHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[21]
+	mi := &file_proto_payment_payment_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1455,7 +2627,7 @@ func (x *GetWaasTransactionHistoryResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use GetWaasTransactionHistoryResponse.ProtoReflect.Descriptor instead.
 func (*GetWaasTransactionHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{21}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *GetWaasTransactionHistoryResponse) GetSuccess() bool {
@@ -1495,7 +2667,7 @@ type GetWaasWalletStatusRequest struct {
 
 func (x *GetWaasWalletStatusRequest) Reset() {
 	*x = GetWaasWalletStatusRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[22]
+	mi := &file_proto_payment_payment_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1507,7 +2679,7 @@ func (x *GetWaasWalletStatusRequest) String() string {
 func (*GetWaasWalletStatusRequest) ProtoMessage() {}
 
 func (x *GetWaasWalletStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[22]
+	mi := &file_proto_payment_payment_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1520,7 +2692,7 @@ func (x *GetWaasWalletStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWaasWalletStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetWaasWalletStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{22}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *GetWaasWalletStatusRequest) GetUserId() string {
@@ -1542,7 +2714,7 @@ type GetWaasWalletStatusResponse struct {
 
 func (x *GetWaasWalletStatusResponse) Reset() {
 	*x = GetWaasWalletStatusResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[23]
+	mi := &file_proto_payment_payment_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1554,7 +2726,7 @@ func (x *GetWaasWalletStatusResponse) String() string {
 func (*GetWaasWalletStatusResponse) ProtoMessage() {}
 
 func (x *GetWaasWalletStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[23]
+	mi := &file_proto_payment_payment_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1567,7 +2739,7 @@ func (x *GetWaasWalletStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWaasWalletStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetWaasWalletStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{23}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *GetWaasWalletStatusResponse) GetSuccess() bool {
@@ -1609,7 +2781,7 @@ type ChangeWalletStatusRequest struct {
 
 func (x *ChangeWalletStatusRequest) Reset() {
 	*x = ChangeWalletStatusRequest{}
-	mi := &file_proto_payment_payment_proto_msgTypes[24]
+	mi := &file_proto_payment_payment_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1621,7 +2793,7 @@ func (x *ChangeWalletStatusRequest) String() string {
 func (*ChangeWalletStatusRequest) ProtoMessage() {}
 
 func (x *ChangeWalletStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[24]
+	mi := &file_proto_payment_payment_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1634,7 +2806,7 @@ func (x *ChangeWalletStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChangeWalletStatusRequest.ProtoReflect.Descriptor instead.
 func (*ChangeWalletStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{24}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *ChangeWalletStatusRequest) GetUserId() string {
@@ -1669,7 +2841,7 @@ type ChangeWalletStatusResponse struct {
 
 func (x *ChangeWalletStatusResponse) Reset() {
 	*x = ChangeWalletStatusResponse{}
-	mi := &file_proto_payment_payment_proto_msgTypes[25]
+	mi := &file_proto_payment_payment_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1681,7 +2853,7 @@ func (x *ChangeWalletStatusResponse) String() string {
 func (*ChangeWalletStatusResponse) ProtoMessage() {}
 
 func (x *ChangeWalletStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_payment_payment_proto_msgTypes[25]
+	mi := &file_proto_payment_payment_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1694,7 +2866,7 @@ func (x *ChangeWalletStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChangeWalletStatusResponse.ProtoReflect.Descriptor instead.
 func (*ChangeWalletStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_payment_payment_proto_rawDescGZIP(), []int{25}
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ChangeWalletStatusResponse) GetSuccess() bool {
@@ -1718,11 +2890,331 @@ func (x *ChangeWalletStatusResponse) GetErrorMessage() string {
 	return ""
 }
 
+type ExportUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRequest) ProtoMessage() {}
+
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ExportUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ExportUserDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	JsonPayload   string                 `protobuf:"bytes,2,opt,name=json_payload,json=jsonPayload,proto3" json:"json_payload,omitempty"` // wallets, transactions and disputes as JSON when found
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataResponse) Reset() {
+	*x = ExportUserDataResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataResponse) ProtoMessage() {}
+
+func (x *ExportUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataResponse.ProtoReflect.Descriptor instead.
+func (*ExportUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ExportUserDataResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ExportUserDataResponse) GetJsonPayload() string {
+	if x != nil {
+		return x.JsonPayload
+	}
+	return ""
+}
+
+type EraseUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserDataRequest) Reset() {
+	*x = EraseUserDataRequest{}
+	mi := &file_proto_payment_payment_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserDataRequest) ProtoMessage() {}
+
+func (x *EraseUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserDataRequest.ProtoReflect.Descriptor instead.
+func (*EraseUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *EraseUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type EraseUserDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Held          bool                   `protobuf:"varint,1,opt,name=held,proto3" json:"held,omitempty"` // true if nothing was changed because a hold applies (see hold_reasons)
+	HoldReasons   []string               `protobuf:"bytes,2,rep,name=hold_reasons,json=holdReasons,proto3" json:"hold_reasons,omitempty"`
+	Erased        []string               `protobuf:"bytes,3,rep,name=erased,proto3" json:"erased,omitempty"`     // categories anonymised, e.g. "wallet_contact"
+	Retained      []string               `protobuf:"bytes,4,rep,name=retained,proto3" json:"retained,omitempty"` // categories kept under financial-record retention
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserDataResponse) Reset() {
+	*x = EraseUserDataResponse{}
+	mi := &file_proto_payment_payment_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserDataResponse) ProtoMessage() {}
+
+func (x *EraseUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_payment_payment_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserDataResponse.ProtoReflect.Descriptor instead.
+func (*EraseUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_payment_payment_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *EraseUserDataResponse) GetHeld() bool {
+	if x != nil {
+		return x.Held
+	}
+	return false
+}
+
+func (x *EraseUserDataResponse) GetHoldReasons() []string {
+	if x != nil {
+		return x.HoldReasons
+	}
+	return nil
+}
+
+func (x *EraseUserDataResponse) GetErased() []string {
+	if x != nil {
+		return x.Erased
+	}
+	return nil
+}
+
+func (x *EraseUserDataResponse) GetRetained() []string {
+	if x != nil {
+		return x.Retained
+	}
+	return nil
+}
+
 var File_proto_payment_payment_proto protoreflect.FileDescriptor
 
 const file_proto_payment_payment_proto_rawDesc = "" +
 	"\n" +
-	"\x1bproto/payment/payment.proto\x12\apayment\"X\n" +
+	"\x1bproto/payment/payment.proto\x12\apayment\"\xb4\x06\n" +
+	"\vDisputeItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vdispute_ref\x18\x02 \x01(\tR\n" +
+	"disputeRef\x12'\n" +
+	"\x0ftransaction_ref\x18\x03 \x01(\tR\x0etransactionRef\x12-\n" +
+	"\x12transaction_amount\x18\x04 \x01(\x01R\x11transactionAmount\x12-\n" +
+	"\x12transaction_status\x18\x05 \x01(\tR\x11transactionStatus\x123\n" +
+	"\x15transaction_direction\x18\x06 \x01(\tR\x14transactionDirection\x12\x1b\n" +
+	"\twallet_id\x18\a \x01(\tR\bwalletId\x12\x17\n" +
+	"\auser_id\x18\b \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12 \n" +
+	"\vdescription\x18\n" +
+	" \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x12\x1f\n" +
+	"\vassigned_to\x18\f \x01(\tR\n" +
+	"assignedTo\x12\x1c\n" +
+	"\n" +
+	"sla_due_at\x18\r \x01(\tR\bslaDueAt\x12!\n" +
+	"\fsla_breached\x18\x0e \x01(\bR\vslaBreached\x12*\n" +
+	"\x11first_response_at\x18\x0f \x01(\tR\x0ffirstResponseAt\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x10 \x01(\tR\n" +
+	"resolution\x12+\n" +
+	"\x11resolution_amount\x18\x11 \x01(\x01R\x10resolutionAmount\x12,\n" +
+	"\x12resolution_txn_ref\x18\x12 \x01(\tR\x10resolutionTxnRef\x12'\n" +
+	"\x0fresolution_note\x18\x13 \x01(\tR\x0eresolutionNote\x12\x1f\n" +
+	"\vresolved_by\x18\x14 \x01(\tR\n" +
+	"resolvedBy\x12\x1f\n" +
+	"\vresolved_at\x18\x15 \x01(\tR\n" +
+	"resolvedAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x16 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x17 \x01(\tR\tupdatedAt\"\xaf\x01\n" +
+	"\vDisputeNote\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vauthor_type\x18\x02 \x01(\tR\n" +
+	"authorType\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x1f\n" +
+	"\vis_internal\x18\x05 \x01(\bR\n" +
+	"isInternal\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\"\xdf\x01\n" +
+	"\x11DisputeAttachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\x10uploaded_by_type\x18\x02 \x01(\tR\x0euploadedByType\x12\x1f\n" +
+	"\vuploaded_by\x18\x03 \x01(\tR\n" +
+	"uploadedBy\x12\x1b\n" +
+	"\tfile_name\x18\x04 \x01(\tR\bfileName\x12!\n" +
+	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"[\n" +
+	"\x13ListDisputesRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"^\n" +
+	"\x14ListDisputesResponse\x120\n" +
+	"\bdisputes\x18\x01 \x03(\v2\x14.payment.DisputeItemR\bdisputes\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"#\n" +
+	"\x11GetDisputeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xbe\x01\n" +
+	"\x12GetDisputeResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12(\n" +
+	"\x04item\x18\x02 \x01(\v2\x14.payment.DisputeItemR\x04item\x12*\n" +
+	"\x05notes\x18\x03 \x03(\v2\x14.payment.DisputeNoteR\x05notes\x12<\n" +
+	"\vattachments\x18\x04 \x03(\v2\x1a.payment.DisputeAttachmentR\vattachments\"{\n" +
+	"\x1aUpdateDisputeStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12!\n" +
+	"\finitiated_by\x18\x04 \x01(\tR\vinitiatedBy\"\x86\x01\n" +
+	"\x1bUpdateDisputeStatusResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12(\n" +
+	"\x04item\x18\x02 \x01(\v2\x14.payment.DisputeItemR\x04item\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\x7f\n" +
+	"\x15AddDisputeNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x12\x1f\n" +
+	"\vis_internal\x18\x03 \x01(\bR\n" +
+	"isInternal\x12!\n" +
+	"\finitiated_by\x18\x04 \x01(\tR\vinitiatedBy\"\x81\x01\n" +
+	"\x16AddDisputeNoteResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12(\n" +
+	"\x04note\x18\x02 \x01(\v2\x14.payment.DisputeNoteR\x04note\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\xa2\x01\n" +
+	"\x1bAddDisputeAttachmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12!\n" +
+	"\finitiated_by\x18\x05 \x01(\tR\vinitiatedBy\"\x99\x01\n" +
+	"\x1cAddDisputeAttachmentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12:\n" +
+	"\n" +
+	"attachment\x18\x02 \x01(\v2\x1a.payment.DisputeAttachmentR\n" +
+	"attachment\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\xb3\x01\n" +
+	"\x15ResolveDisputeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x02 \x01(\tR\n" +
+	"resolution\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1b\n" +
+	"\tis_credit\x18\x04 \x01(\bR\bisCredit\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\x12!\n" +
+	"\finitiated_by\x18\x06 \x01(\tR\vinitiatedBy\"\x81\x01\n" +
+	"\x16ResolveDisputeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12(\n" +
+	"\x04item\x18\x02 \x01(\v2\x14.payment.DisputeItemR\x04item\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"X\n" +
 	"\x1aSubmitWalletUpgradeRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
 	"\finitiated_by\x18\x02 \x01(\tR\vinitiatedBy\"v\n" +
@@ -1806,17 +3298,19 @@ const file_proto_payment_payment_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\x10 \x01(\tR\tupdatedAt\"F\n" +
 	"\x13ListWalletsResponse\x12/\n" +
-	"\awallets\x18\x01 \x03(\v2\x15.payment.WalletDetailR\awallets\"\xa9\x01\n" +
+	"\awallets\x18\x01 \x03(\v2\x15.payment.WalletDetailR\awallets\"\xd2\x01\n" +
 	"\x18DebitCreditWalletRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1b\n" +
 	"\tis_credit\x18\x03 \x01(\bR\bisCredit\x12\x1c\n" +
 	"\tnarration\x18\x04 \x01(\tR\tnarration\x12!\n" +
-	"\finitiated_by\x18\x05 \x01(\tR\vinitiatedBy\"\x83\x01\n" +
+	"\finitiated_by\x18\x05 \x01(\tR\vinitiatedBy\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\"\xa1\x01\n" +
 	"\x19DebitCreditWalletResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12'\n" +
 	"\x0ftransaction_ref\x18\x02 \x01(\tR\x0etransactionRef\x12#\n" +
-	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"\x87\x01\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x12\x1c\n" +
+	"\tduplicate\x18\x04 \x01(\bR\tduplicate\"\x87\x01\n" +
 	" GetWaasTransactionHistoryRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
 	"\tfrom_date\x18\x02 \x01(\tR\bfromDate\x12\x17\n" +
@@ -1854,7 +3348,19 @@ const file_proto_payment_payment_proto_rawDesc = "" +
 	"\x1aChangeWalletStatusResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12*\n" +
 	"\x11new_wallet_status\x18\x02 \x01(\tR\x0fnewWalletStatus\x12#\n" +
-	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage2\xbb\b\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"0\n" +
+	"\x15ExportUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"Q\n" +
+	"\x16ExportUserDataResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12!\n" +
+	"\fjson_payload\x18\x02 \x01(\tR\vjsonPayload\"/\n" +
+	"\x14EraseUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x82\x01\n" +
+	"\x15EraseUserDataResponse\x12\x12\n" +
+	"\x04held\x18\x01 \x01(\bR\x04held\x12!\n" +
+	"\fhold_reasons\x18\x02 \x03(\tR\vholdReasons\x12\x16\n" +
+	"\x06erased\x18\x03 \x03(\tR\x06erased\x12\x1a\n" +
+	"\bretained\x18\x04 \x03(\tR\bretained2\xdf\r\n" +
 	"\x0ePaymentService\x129\n" +
 	"\x06Health\x12\x16.payment.HealthRequest\x1a\x17.payment.HealthResponse\x12K\n" +
 	"\fCreateWallet\x12\x1c.payment.CreateWalletRequest\x1a\x1d.payment.CreateWalletResponse\x12H\n" +
@@ -1866,7 +3372,16 @@ const file_proto_payment_payment_proto_rawDesc = "" +
 	"\x13SubmitWalletUpgrade\x12#.payment.SubmitWalletUpgradeRequest\x1a$.payment.SubmitWalletUpgradeResponse\x12r\n" +
 	"\x19ListWalletUpgradeRequests\x12).payment.ListWalletUpgradeRequestsRequest\x1a*.payment.ListWalletUpgradeRequestsResponse\x12l\n" +
 	"\x17GetWalletUpgradeRequest\x12'.payment.GetWalletUpgradeRequestRequest\x1a(.payment.GetWalletUpgradeRequestResponse\x12\x81\x01\n" +
-	"\x1eGetWalletUpgradeStatusByUserID\x12..payment.GetWalletUpgradeStatusByUserIDRequest\x1a/.payment.GetWalletUpgradeStatusByUserIDResponseB;Z9github.com/abubakvr/payup-backend/proto/payment;paymentpbb\x06proto3"
+	"\x1eGetWalletUpgradeStatusByUserID\x12..payment.GetWalletUpgradeStatusByUserIDRequest\x1a/.payment.GetWalletUpgradeStatusByUserIDResponse\x12K\n" +
+	"\fListDisputes\x12\x1c.payment.ListDisputesRequest\x1a\x1d.payment.ListDisputesResponse\x12E\n" +
+	"\n" +
+	"GetDispute\x12\x1a.payment.GetDisputeRequest\x1a\x1b.payment.GetDisputeResponse\x12`\n" +
+	"\x13UpdateDisputeStatus\x12#.payment.UpdateDisputeStatusRequest\x1a$.payment.UpdateDisputeStatusResponse\x12Q\n" +
+	"\x0eAddDisputeNote\x12\x1e.payment.AddDisputeNoteRequest\x1a\x1f.payment.AddDisputeNoteResponse\x12c\n" +
+	"\x14AddDisputeAttachment\x12$.payment.AddDisputeAttachmentRequest\x1a%.payment.AddDisputeAttachmentResponse\x12Q\n" +
+	"\x0eResolveDispute\x12\x1e.payment.ResolveDisputeRequest\x1a\x1f.payment.ResolveDisputeResponse\x12Q\n" +
+	"\x0eExportUserData\x12\x1e.payment.ExportUserDataRequest\x1a\x1f.payment.ExportUserDataResponse\x12N\n" +
+	"\rEraseUserData\x12\x1d.payment.EraseUserDataRequest\x1a\x1e.payment.EraseUserDataResponseB;Z9github.com/abubakvr/payup-backend/proto/payment;paymentpbb\x06proto3"
 
 var (
 	file_proto_payment_payment_proto_rawDescOnce sync.Once
@@ -1880,69 +3395,112 @@ func file_proto_payment_payment_proto_rawDescGZIP() []byte {
 	return file_proto_payment_payment_proto_rawDescData
 }
 
-var file_proto_payment_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_proto_payment_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_proto_payment_payment_proto_goTypes = []any{
-	(*SubmitWalletUpgradeRequest)(nil),             // 0: payment.SubmitWalletUpgradeRequest
-	(*SubmitWalletUpgradeResponse)(nil),            // 1: payment.SubmitWalletUpgradeResponse
-	(*ListWalletUpgradeRequestsRequest)(nil),       // 2: payment.ListWalletUpgradeRequestsRequest
-	(*WalletUpgradeRequestItem)(nil),               // 3: payment.WalletUpgradeRequestItem
-	(*ListWalletUpgradeRequestsResponse)(nil),      // 4: payment.ListWalletUpgradeRequestsResponse
-	(*GetWalletUpgradeRequestRequest)(nil),         // 5: payment.GetWalletUpgradeRequestRequest
-	(*GetWalletUpgradeRequestResponse)(nil),        // 6: payment.GetWalletUpgradeRequestResponse
-	(*GetWalletUpgradeStatusByUserIDRequest)(nil),  // 7: payment.GetWalletUpgradeStatusByUserIDRequest
-	(*UpgradeStatusFrom9PSB)(nil),                  // 8: payment.UpgradeStatusFrom9PSB
-	(*GetWalletUpgradeStatusByUserIDResponse)(nil), // 9: payment.GetWalletUpgradeStatusByUserIDResponse
-	(*HealthRequest)(nil),                          // 10: payment.HealthRequest
-	(*HealthResponse)(nil),                         // 11: payment.HealthResponse
-	(*CreateWalletRequest)(nil),                    // 12: payment.CreateWalletRequest
-	(*CreateWalletResponse)(nil),                   // 13: payment.CreateWalletResponse
-	(*ListWalletsRequest)(nil),                     // 14: payment.ListWalletsRequest
-	(*WalletDetail)(nil),                           // 15: payment.WalletDetail
-	(*ListWalletsResponse)(nil),                    // 16: payment.ListWalletsResponse
-	(*DebitCreditWalletRequest)(nil),               // 17: payment.DebitCreditWalletRequest
-	(*DebitCreditWalletResponse)(nil),              // 18: payment.DebitCreditWalletResponse
-	(*GetWaasTransactionHistoryRequest)(nil),       // 19: payment.GetWaasTransactionHistoryRequest
-	(*WaasTransactionItem)(nil),                    // 20: payment.WaasTransactionItem
-	(*GetWaasTransactionHistoryResponse)(nil),      // 21: payment.GetWaasTransactionHistoryResponse
-	(*GetWaasWalletStatusRequest)(nil),             // 22: payment.GetWaasWalletStatusRequest
-	(*GetWaasWalletStatusResponse)(nil),            // 23: payment.GetWaasWalletStatusResponse
-	(*ChangeWalletStatusRequest)(nil),              // 24: payment.ChangeWalletStatusRequest
-	(*ChangeWalletStatusResponse)(nil),             // 25: payment.ChangeWalletStatusResponse
+	(*DisputeItem)(nil),                            // 0: payment.DisputeItem
+	(*DisputeNote)(nil),                            // 1: payment.DisputeNote
+	(*DisputeAttachment)(nil),                      // 2: payment.DisputeAttachment
+	(*ListDisputesRequest)(nil),                    // 3: payment.ListDisputesRequest
+	(*ListDisputesResponse)(nil),                   // 4: payment.ListDisputesResponse
+	(*GetDisputeRequest)(nil),                      // 5: payment.GetDisputeRequest
+	(*GetDisputeResponse)(nil),                     // 6: payment.GetDisputeResponse
+	(*UpdateDisputeStatusRequest)(nil),             // 7: payment.UpdateDisputeStatusRequest
+	(*UpdateDisputeStatusResponse)(nil),            // 8: payment.UpdateDisputeStatusResponse
+	(*AddDisputeNoteRequest)(nil),                  // 9: payment.AddDisputeNoteRequest
+	(*AddDisputeNoteResponse)(nil),                 // 10: payment.AddDisputeNoteResponse
+	(*AddDisputeAttachmentRequest)(nil),            // 11: payment.AddDisputeAttachmentRequest
+	(*AddDisputeAttachmentResponse)(nil),           // 12: payment.AddDisputeAttachmentResponse
+	(*ResolveDisputeRequest)(nil),                  // 13: payment.ResolveDisputeRequest
+	(*ResolveDisputeResponse)(nil),                 // 14: payment.ResolveDisputeResponse
+	(*SubmitWalletUpgradeRequest)(nil),             // 15: payment.SubmitWalletUpgradeRequest
+	(*SubmitWalletUpgradeResponse)(nil),            // 16: payment.SubmitWalletUpgradeResponse
+	(*ListWalletUpgradeRequestsRequest)(nil),       // 17: payment.ListWalletUpgradeRequestsRequest
+	(*WalletUpgradeRequestItem)(nil),               // 18: payment.WalletUpgradeRequestItem
+	(*ListWalletUpgradeRequestsResponse)(nil),      // 19: payment.ListWalletUpgradeRequestsResponse
+	(*GetWalletUpgradeRequestRequest)(nil),         // 20: payment.GetWalletUpgradeRequestRequest
+	(*GetWalletUpgradeRequestResponse)(nil),        // 21: payment.GetWalletUpgradeRequestResponse
+	(*GetWalletUpgradeStatusByUserIDRequest)(nil),  // 22: payment.GetWalletUpgradeStatusByUserIDRequest
+	(*UpgradeStatusFrom9PSB)(nil),                  // 23: payment.UpgradeStatusFrom9PSB
+	(*GetWalletUpgradeStatusByUserIDResponse)(nil), // 24: payment.GetWalletUpgradeStatusByUserIDResponse
+	(*HealthRequest)(nil),                          // 25: payment.HealthRequest
+	(*HealthResponse)(nil),                         // 26: payment.HealthResponse
+	(*CreateWalletRequest)(nil),                    // 27: payment.CreateWalletRequest
+	(*CreateWalletResponse)(nil),                   // 28: payment.CreateWalletResponse
+	(*ListWalletsRequest)(nil),                     // 29: payment.ListWalletsRequest
+	(*WalletDetail)(nil),                           // 30: payment.WalletDetail
+	(*ListWalletsResponse)(nil),                    // 31: payment.ListWalletsResponse
+	(*DebitCreditWalletRequest)(nil),               // 32: payment.DebitCreditWalletRequest
+	(*DebitCreditWalletResponse)(nil),              // 33: payment.DebitCreditWalletResponse
+	(*GetWaasTransactionHistoryRequest)(nil),       // 34: payment.GetWaasTransactionHistoryRequest
+	(*WaasTransactionItem)(nil),                    // 35: payment.WaasTransactionItem
+	(*GetWaasTransactionHistoryResponse)(nil),      // 36: payment.GetWaasTransactionHistoryResponse
+	(*GetWaasWalletStatusRequest)(nil),             // 37: payment.GetWaasWalletStatusRequest
+	(*GetWaasWalletStatusResponse)(nil),            // 38: payment.GetWaasWalletStatusResponse
+	(*ChangeWalletStatusRequest)(nil),              // 39: payment.ChangeWalletStatusRequest
+	(*ChangeWalletStatusResponse)(nil),             // 40: payment.ChangeWalletStatusResponse
+	(*ExportUserDataRequest)(nil),                  // 41: payment.ExportUserDataRequest
+	(*ExportUserDataResponse)(nil),                 // 42: payment.ExportUserDataResponse
+	(*EraseUserDataRequest)(nil),                   // 43: payment.EraseUserDataRequest
+	(*EraseUserDataResponse)(nil),                  // 44: payment.EraseUserDataResponse
 }
 var file_proto_payment_payment_proto_depIdxs = []int32{
-	3,  // 0: payment.ListWalletUpgradeRequestsResponse.requests:type_name -> payment.WalletUpgradeRequestItem
-	3,  // 1: payment.GetWalletUpgradeRequestResponse.item:type_name -> payment.WalletUpgradeRequestItem
-	8,  // 2: payment.GetWalletUpgradeStatusByUserIDResponse.upgrade_status:type_name -> payment.UpgradeStatusFrom9PSB
-	3,  // 3: payment.GetWalletUpgradeStatusByUserIDResponse.latest:type_name -> payment.WalletUpgradeRequestItem
-	15, // 4: payment.ListWalletsResponse.wallets:type_name -> payment.WalletDetail
-	20, // 5: payment.GetWaasTransactionHistoryResponse.transactions:type_name -> payment.WaasTransactionItem
-	10, // 6: payment.PaymentService.Health:input_type -> payment.HealthRequest
-	12, // 7: payment.PaymentService.CreateWallet:input_type -> payment.CreateWalletRequest
-	14, // 8: payment.PaymentService.ListWallets:input_type -> payment.ListWalletsRequest
-	17, // 9: payment.PaymentService.DebitCreditWallet:input_type -> payment.DebitCreditWalletRequest
-	19, // 10: payment.PaymentService.GetWaasTransactionHistory:input_type -> payment.GetWaasTransactionHistoryRequest
-	22, // 11: payment.PaymentService.GetWaasWalletStatus:input_type -> payment.GetWaasWalletStatusRequest
-	24, // 12: payment.PaymentService.ChangeWalletStatus:input_type -> payment.ChangeWalletStatusRequest
-	0,  // 13: payment.PaymentService.SubmitWalletUpgrade:input_type -> payment.SubmitWalletUpgradeRequest
-	2,  // 14: payment.PaymentService.ListWalletUpgradeRequests:input_type -> payment.ListWalletUpgradeRequestsRequest
-	5,  // 15: payment.PaymentService.GetWalletUpgradeRequest:input_type -> payment.GetWalletUpgradeRequestRequest
-	7,  // 16: payment.PaymentService.GetWalletUpgradeStatusByUserID:input_type -> payment.GetWalletUpgradeStatusByUserIDRequest
-	11, // 17: payment.PaymentService.Health:output_type -> payment.HealthResponse
-	13, // 18: payment.PaymentService.CreateWallet:output_type -> payment.CreateWalletResponse
-	16, // 19: payment.PaymentService.ListWallets:output_type -> payment.ListWalletsResponse
-	18, // 20: payment.PaymentService.DebitCreditWallet:output_type -> payment.DebitCreditWalletResponse
-	21, // 21: payment.PaymentService.GetWaasTransactionHistory:output_type -> payment.GetWaasTransactionHistoryResponse
-	23, // 22: payment.PaymentService.GetWaasWalletStatus:output_type -> payment.GetWaasWalletStatusResponse
-	25, // 23: payment.PaymentService.ChangeWalletStatus:output_type -> payment.ChangeWalletStatusResponse
-	1,  // 24: payment.PaymentService.SubmitWalletUpgrade:output_type -> payment.SubmitWalletUpgradeResponse
-	4,  // 25: payment.PaymentService.ListWalletUpgradeRequests:output_type -> payment.ListWalletUpgradeRequestsResponse
-	6,  // 26: payment.PaymentService.GetWalletUpgradeRequest:output_type -> payment.GetWalletUpgradeRequestResponse
-	9,  // 27: payment.PaymentService.GetWalletUpgradeStatusByUserID:output_type -> payment.GetWalletUpgradeStatusByUserIDResponse
-	17, // [17:28] is the sub-list for method output_type
-	6,  // [6:17] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	0,  // 0: payment.ListDisputesResponse.disputes:type_name -> payment.DisputeItem
+	0,  // 1: payment.GetDisputeResponse.item:type_name -> payment.DisputeItem
+	1,  // 2: payment.GetDisputeResponse.notes:type_name -> payment.DisputeNote
+	2,  // 3: payment.GetDisputeResponse.attachments:type_name -> payment.DisputeAttachment
+	0,  // 4: payment.UpdateDisputeStatusResponse.item:type_name -> payment.DisputeItem
+	1,  // 5: payment.AddDisputeNoteResponse.note:type_name -> payment.DisputeNote
+	2,  // 6: payment.AddDisputeAttachmentResponse.attachment:type_name -> payment.DisputeAttachment
+	0,  // 7: payment.ResolveDisputeResponse.item:type_name -> payment.DisputeItem
+	18, // 8: payment.ListWalletUpgradeRequestsResponse.requests:type_name -> payment.WalletUpgradeRequestItem
+	18, // 9: payment.GetWalletUpgradeRequestResponse.item:type_name -> payment.WalletUpgradeRequestItem
+	23, // 10: payment.GetWalletUpgradeStatusByUserIDResponse.upgrade_status:type_name -> payment.UpgradeStatusFrom9PSB
+	18, // 11: payment.GetWalletUpgradeStatusByUserIDResponse.latest:type_name -> payment.WalletUpgradeRequestItem
+	30, // 12: payment.ListWalletsResponse.wallets:type_name -> payment.WalletDetail
+	35, // 13: payment.GetWaasTransactionHistoryResponse.transactions:type_name -> payment.WaasTransactionItem
+	25, // 14: payment.PaymentService.Health:input_type -> payment.HealthRequest
+	27, // 15: payment.PaymentService.CreateWallet:input_type -> payment.CreateWalletRequest
+	29, // 16: payment.PaymentService.ListWallets:input_type -> payment.ListWalletsRequest
+	32, // 17: payment.PaymentService.DebitCreditWallet:input_type -> payment.DebitCreditWalletRequest
+	34, // 18: payment.PaymentService.GetWaasTransactionHistory:input_type -> payment.GetWaasTransactionHistoryRequest
+	37, // 19: payment.PaymentService.GetWaasWalletStatus:input_type -> payment.GetWaasWalletStatusRequest
+	39, // 20: payment.PaymentService.ChangeWalletStatus:input_type -> payment.ChangeWalletStatusRequest
+	15, // 21: payment.PaymentService.SubmitWalletUpgrade:input_type -> payment.SubmitWalletUpgradeRequest
+	17, // 22: payment.PaymentService.ListWalletUpgradeRequests:input_type -> payment.ListWalletUpgradeRequestsRequest
+	20, // 23: payment.PaymentService.GetWalletUpgradeRequest:input_type -> payment.GetWalletUpgradeRequestRequest
+	22, // 24: payment.PaymentService.GetWalletUpgradeStatusByUserID:input_type -> payment.GetWalletUpgradeStatusByUserIDRequest
+	3,  // 25: payment.PaymentService.ListDisputes:input_type -> payment.ListDisputesRequest
+	5,  // 26: payment.PaymentService.GetDispute:input_type -> payment.GetDisputeRequest
+	7,  // 27: payment.PaymentService.UpdateDisputeStatus:input_type -> payment.UpdateDisputeStatusRequest
+	9,  // 28: payment.PaymentService.AddDisputeNote:input_type -> payment.AddDisputeNoteRequest
+	11, // 29: payment.PaymentService.AddDisputeAttachment:input_type -> payment.AddDisputeAttachmentRequest
+	13, // 30: payment.PaymentService.ResolveDispute:input_type -> payment.ResolveDisputeRequest
+	41, // 31: payment.PaymentService.ExportUserData:input_type -> payment.ExportUserDataRequest
+	43, // 32: payment.PaymentService.EraseUserData:input_type -> payment.EraseUserDataRequest
+	26, // 33: payment.PaymentService.Health:output_type -> payment.HealthResponse
+	28, // 34: payment.PaymentService.CreateWallet:output_type -> payment.CreateWalletResponse
+	31, // 35: payment.PaymentService.ListWallets:output_type -> payment.ListWalletsResponse
+	33, // 36: payment.PaymentService.DebitCreditWallet:output_type -> payment.DebitCreditWalletResponse
+	36, // 37: payment.PaymentService.GetWaasTransactionHistory:output_type -> payment.GetWaasTransactionHistoryResponse
+	38, // 38: payment.PaymentService.GetWaasWalletStatus:output_type -> payment.GetWaasWalletStatusResponse
+	40, // 39: payment.PaymentService.ChangeWalletStatus:output_type -> payment.ChangeWalletStatusResponse
+	16, // 40: payment.PaymentService.SubmitWalletUpgrade:output_type -> payment.SubmitWalletUpgradeResponse
+	19, // 41: payment.PaymentService.ListWalletUpgradeRequests:output_type -> payment.ListWalletUpgradeRequestsResponse
+	21, // 42: payment.PaymentService.GetWalletUpgradeRequest:output_type -> payment.GetWalletUpgradeRequestResponse
+	24, // 43: payment.PaymentService.GetWalletUpgradeStatusByUserID:output_type -> payment.GetWalletUpgradeStatusByUserIDResponse
+	4,  // 44: payment.PaymentService.ListDisputes:output_type -> payment.ListDisputesResponse
+	6,  // 45: payment.PaymentService.GetDispute:output_type -> payment.GetDisputeResponse
+	8,  // 46: payment.PaymentService.UpdateDisputeStatus:output_type -> payment.UpdateDisputeStatusResponse
+	10, // 47: payment.PaymentService.AddDisputeNote:output_type -> payment.AddDisputeNoteResponse
+	12, // 48: payment.PaymentService.AddDisputeAttachment:output_type -> payment.AddDisputeAttachmentResponse
+	14, // 49: payment.PaymentService.ResolveDispute:output_type -> payment.ResolveDisputeResponse
+	42, // 50: payment.PaymentService.ExportUserData:output_type -> payment.ExportUserDataResponse
+	44, // 51: payment.PaymentService.EraseUserData:output_type -> payment.EraseUserDataResponse
+	33, // [33:52] is the sub-list for method output_type
+	14, // [14:33] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_proto_payment_payment_proto_init() }
@@ -1956,7 +3514,7 @@ func file_proto_payment_payment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_payment_payment_proto_rawDesc), len(file_proto_payment_payment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   26,
+			NumMessages:   45,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
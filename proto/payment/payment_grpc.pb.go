@@ -30,6 +30,14 @@ const (
 	PaymentService_ListWalletUpgradeRequests_FullMethodName      = "/payment.PaymentService/ListWalletUpgradeRequests"
 	PaymentService_GetWalletUpgradeRequest_FullMethodName        = "/payment.PaymentService/GetWalletUpgradeRequest"
 	PaymentService_GetWalletUpgradeStatusByUserID_FullMethodName = "/payment.PaymentService/GetWalletUpgradeStatusByUserID"
+	PaymentService_ListDisputes_FullMethodName                   = "/payment.PaymentService/ListDisputes"
+	PaymentService_GetDispute_FullMethodName                     = "/payment.PaymentService/GetDispute"
+	PaymentService_UpdateDisputeStatus_FullMethodName            = "/payment.PaymentService/UpdateDisputeStatus"
+	PaymentService_AddDisputeNote_FullMethodName                 = "/payment.PaymentService/AddDisputeNote"
+	PaymentService_AddDisputeAttachment_FullMethodName           = "/payment.PaymentService/AddDisputeAttachment"
+	PaymentService_ResolveDispute_FullMethodName                 = "/payment.PaymentService/ResolveDispute"
+	PaymentService_ExportUserData_FullMethodName                 = "/payment.PaymentService/ExportUserData"
+	PaymentService_EraseUserData_FullMethodName                  = "/payment.PaymentService/EraseUserData"
 )
 
 // PaymentServiceClient is the client API for PaymentService service.
@@ -59,6 +67,22 @@ type PaymentServiceClient interface {
 	GetWalletUpgradeRequest(ctx context.Context, in *GetWalletUpgradeRequestRequest, opts ...grpc.CallOption) (*GetWalletUpgradeRequestResponse, error)
 	// GetWalletUpgradeStatusByUserID returns wallet upgrade status for a user (latest request if any). Used by admin GET /users/:id/wallet/upgrade-status.
 	GetWalletUpgradeStatusByUserID(ctx context.Context, in *GetWalletUpgradeStatusByUserIDRequest, opts ...grpc.CallOption) (*GetWalletUpgradeStatusByUserIDResponse, error)
+	// ListDisputes returns the transaction dispute queue for admin (optional status filter), most urgent SLA first. Paginated.
+	ListDisputes(ctx context.Context, in *ListDisputesRequest, opts ...grpc.CallOption) (*ListDisputesResponse, error)
+	// GetDispute returns one dispute by id with all notes (including internal) and attachments.
+	GetDispute(ctx context.Context, in *GetDisputeRequest, opts ...grpc.CallOption) (*GetDisputeResponse, error)
+	// UpdateDisputeStatus moves a dispute to UNDER_REVIEW, AWAITING_USER or REJECTED. Audit + email via Kafka.
+	UpdateDisputeStatus(ctx context.Context, in *UpdateDisputeStatusRequest, opts ...grpc.CallOption) (*UpdateDisputeStatusResponse, error)
+	// AddDisputeNote adds an admin note (internal notes are hidden from the user).
+	AddDisputeNote(ctx context.Context, in *AddDisputeNoteRequest, opts ...grpc.CallOption) (*AddDisputeNoteResponse, error)
+	// AddDisputeAttachment records an evidence file on a dispute.
+	AddDisputeAttachment(ctx context.Context, in *AddDisputeAttachmentRequest, opts ...grpc.CallOption) (*AddDisputeAttachmentResponse, error)
+	// ResolveDispute closes a dispute as RESOLVED. REVERSAL/ADJUSTMENT post a wallet credit or debit (same path as DebitCreditWallet).
+	ResolveDispute(ctx context.Context, in *ResolveDisputeRequest, opts ...grpc.CallOption) (*ResolveDisputeResponse, error)
+	// ExportUserData returns the user's wallets, transactions and disputes as JSON. Used by User service for data-subject access requests.
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error)
+	// EraseUserData anonymises the user's wallet contact details. Held (nothing changed) while money or open cases remain.
+	EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*EraseUserDataResponse, error)
 }
 
 type paymentServiceClient struct {
@@ -179,6 +203,86 @@ func (c *paymentServiceClient) GetWalletUpgradeStatusByUserID(ctx context.Contex
 	return out, nil
 }
 
+func (c *paymentServiceClient) ListDisputes(ctx context.Context, in *ListDisputesRequest, opts ...grpc.CallOption) (*ListDisputesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDisputesResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListDisputes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetDispute(ctx context.Context, in *GetDisputeRequest, opts ...grpc.CallOption) (*GetDisputeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDisputeResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetDispute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) UpdateDisputeStatus(ctx context.Context, in *UpdateDisputeStatusRequest, opts ...grpc.CallOption) (*UpdateDisputeStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateDisputeStatusResponse)
+	err := c.cc.Invoke(ctx, PaymentService_UpdateDisputeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) AddDisputeNote(ctx context.Context, in *AddDisputeNoteRequest, opts ...grpc.CallOption) (*AddDisputeNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddDisputeNoteResponse)
+	err := c.cc.Invoke(ctx, PaymentService_AddDisputeNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) AddDisputeAttachment(ctx context.Context, in *AddDisputeAttachmentRequest, opts ...grpc.CallOption) (*AddDisputeAttachmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddDisputeAttachmentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_AddDisputeAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ResolveDispute(ctx context.Context, in *ResolveDisputeRequest, opts ...grpc.CallOption) (*ResolveDisputeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveDisputeResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ResolveDispute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*ExportUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportUserDataResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ExportUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*EraseUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseUserDataResponse)
+	err := c.cc.Invoke(ctx, PaymentService_EraseUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PaymentServiceServer is the server API for PaymentService service.
 // All implementations must embed UnimplementedPaymentServiceServer
 // for forward compatibility.
@@ -206,6 +310,22 @@ type PaymentServiceServer interface {
 	GetWalletUpgradeRequest(context.Context, *GetWalletUpgradeRequestRequest) (*GetWalletUpgradeRequestResponse, error)
 	// GetWalletUpgradeStatusByUserID returns wallet upgrade status for a user (latest request if any). Used by admin GET /users/:id/wallet/upgrade-status.
 	GetWalletUpgradeStatusByUserID(context.Context, *GetWalletUpgradeStatusByUserIDRequest) (*GetWalletUpgradeStatusByUserIDResponse, error)
+	// ListDisputes returns the transaction dispute queue for admin (optional status filter), most urgent SLA first. Paginated.
+	ListDisputes(context.Context, *ListDisputesRequest) (*ListDisputesResponse, error)
+	// GetDispute returns one dispute by id with all notes (including internal) and attachments.
+	GetDispute(context.Context, *GetDisputeRequest) (*GetDisputeResponse, error)
+	// UpdateDisputeStatus moves a dispute to UNDER_REVIEW, AWAITING_USER or REJECTED. Audit + email via Kafka.
+	UpdateDisputeStatus(context.Context, *UpdateDisputeStatusRequest) (*UpdateDisputeStatusResponse, error)
+	// AddDisputeNote adds an admin note (internal notes are hidden from the user).
+	AddDisputeNote(context.Context, *AddDisputeNoteRequest) (*AddDisputeNoteResponse, error)
+	// AddDisputeAttachment records an evidence file on a dispute.
+	AddDisputeAttachment(context.Context, *AddDisputeAttachmentRequest) (*AddDisputeAttachmentResponse, error)
+	// ResolveDispute closes a dispute as RESOLVED. REVERSAL/ADJUSTMENT post a wallet credit or debit (same path as DebitCreditWallet).
+	ResolveDispute(context.Context, *ResolveDisputeRequest) (*ResolveDisputeResponse, error)
+	// ExportUserData returns the user's wallets, transactions and disputes as JSON. Used by User service for data-subject access requests.
+	ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error)
+	// EraseUserData anonymises the user's wallet contact details. Held (nothing changed) while money or open cases remain.
+	EraseUserData(context.Context, *EraseUserDataRequest) (*EraseUserDataResponse, error)
 	mustEmbedUnimplementedPaymentServiceServer()
 }
 
@@ -249,6 +369,30 @@ func (UnimplementedPaymentServiceServer) GetWalletUpgradeRequest(context.Context
 func (UnimplementedPaymentServiceServer) GetWalletUpgradeStatusByUserID(context.Context, *GetWalletUpgradeStatusByUserIDRequest) (*GetWalletUpgradeStatusByUserIDResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetWalletUpgradeStatusByUserID not implemented")
 }
+func (UnimplementedPaymentServiceServer) ListDisputes(context.Context, *ListDisputesRequest) (*ListDisputesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDisputes not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetDispute(context.Context, *GetDisputeRequest) (*GetDisputeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDispute not implemented")
+}
+func (UnimplementedPaymentServiceServer) UpdateDisputeStatus(context.Context, *UpdateDisputeStatusRequest) (*UpdateDisputeStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateDisputeStatus not implemented")
+}
+func (UnimplementedPaymentServiceServer) AddDisputeNote(context.Context, *AddDisputeNoteRequest) (*AddDisputeNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddDisputeNote not implemented")
+}
+func (UnimplementedPaymentServiceServer) AddDisputeAttachment(context.Context, *AddDisputeAttachmentRequest) (*AddDisputeAttachmentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddDisputeAttachment not implemented")
+}
+func (UnimplementedPaymentServiceServer) ResolveDispute(context.Context, *ResolveDisputeRequest) (*ResolveDisputeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveDispute not implemented")
+}
+func (UnimplementedPaymentServiceServer) ExportUserData(context.Context, *ExportUserDataRequest) (*ExportUserDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedPaymentServiceServer) EraseUserData(context.Context, *EraseUserDataRequest) (*EraseUserDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EraseUserData not implemented")
+}
 func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
 func (UnimplementedPaymentServiceServer) testEmbeddedByValue()                        {}
 
@@ -468,6 +612,150 @@ func _PaymentService_GetWalletUpgradeStatusByUserID_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_ListDisputes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDisputesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListDisputes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListDisputes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListDisputes(ctx, req.(*ListDisputesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetDispute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDisputeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetDispute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetDispute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetDispute(ctx, req.(*GetDisputeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_UpdateDisputeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDisputeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).UpdateDisputeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_UpdateDisputeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).UpdateDisputeStatus(ctx, req.(*UpdateDisputeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_AddDisputeNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDisputeNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).AddDisputeNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_AddDisputeNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).AddDisputeNote(ctx, req.(*AddDisputeNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_AddDisputeAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDisputeAttachmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).AddDisputeAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_AddDisputeAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).AddDisputeAttachment(ctx, req.(*AddDisputeAttachmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ResolveDispute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveDisputeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ResolveDispute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ResolveDispute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ResolveDispute(ctx, req.(*ResolveDisputeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ExportUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ExportUserData(ctx, req.(*ExportUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_EraseUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).EraseUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_EraseUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).EraseUserData(ctx, req.(*EraseUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -519,6 +807,38 @@ var PaymentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetWalletUpgradeStatusByUserID",
 			Handler:    _PaymentService_GetWalletUpgradeStatusByUserID_Handler,
 		},
+		{
+			MethodName: "ListDisputes",
+			Handler:    _PaymentService_ListDisputes_Handler,
+		},
+		{
+			MethodName: "GetDispute",
+			Handler:    _PaymentService_GetDispute_Handler,
+		},
+		{
+			MethodName: "UpdateDisputeStatus",
+			Handler:    _PaymentService_UpdateDisputeStatus_Handler,
+		},
+		{
+			MethodName: "AddDisputeNote",
+			Handler:    _PaymentService_AddDisputeNote_Handler,
+		},
+		{
+			MethodName: "AddDisputeAttachment",
+			Handler:    _PaymentService_AddDisputeAttachment_Handler,
+		},
+		{
+			MethodName: "ResolveDispute",
+			Handler:    _PaymentService_ResolveDispute_Handler,
+		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _PaymentService_ExportUserData_Handler,
+		},
+		{
+			MethodName: "EraseUserData",
+			Handler:    _PaymentService_EraseUserData_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/payment/payment.proto",
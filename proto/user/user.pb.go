@@ -72,6 +72,7 @@ type GetUserForKYCResponse struct {
 	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
 	FirstName     string                 `protobuf:"bytes,4,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
 	LastName      string                 `protobuf:"bytes,5,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,6,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"` // E.164-style as stored; used for SMS/WhatsApp delivery
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -141,6 +142,13 @@ func (x *GetUserForKYCResponse) GetLastName() string {
 	return ""
 }
 
+func (x *GetUserForKYCResponse) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
 type ListUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -553,29 +561,27 @@ func (x *SetUserRestrictedResponse) GetMessage() string {
 	return ""
 }
 
-type ValidateTransferRequest struct {
+type ListUserSessionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"` // transfer amount for limit checks
-	Pin           string                 `protobuf:"bytes,3,opt,name=pin,proto3" json:"pin,omitempty"`         // plain PIN (4 digits)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidateTransferRequest) Reset() {
-	*x = ValidateTransferRequest{}
+func (x *ListUserSessionsRequest) Reset() {
+	*x = ListUserSessionsRequest{}
 	mi := &file_proto_user_user_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateTransferRequest) String() string {
+func (x *ListUserSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateTransferRequest) ProtoMessage() {}
+func (*ListUserSessionsRequest) ProtoMessage() {}
 
-func (x *ValidateTransferRequest) ProtoReflect() protoreflect.Message {
+func (x *ListUserSessionsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_user_user_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -587,57 +593,194 @@ func (x *ValidateTransferRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateTransferRequest.ProtoReflect.Descriptor instead.
-func (*ValidateTransferRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserSessionsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_user_user_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ValidateTransferRequest) GetUserId() string {
+func (x *ListUserSessionsRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *ValidateTransferRequest) GetAmount() float64 {
+type ListUserSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*UserSession         `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserSessionsResponse) Reset() {
+	*x = ListUserSessionsResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserSessionsResponse) ProtoMessage() {}
+
+func (x *ListUserSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[10]
 	if x != nil {
-		return x.Amount
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ValidateTransferRequest) GetPin() string {
+// Deprecated: Use ListUserSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListUserSessionsResponse) GetSessions() []*UserSession {
 	if x != nil {
-		return x.Pin
+		return x.Sessions
+	}
+	return nil
+}
+
+// UserSession is one login on one device that can still refresh its tokens.
+type UserSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	Platform      string                 `protobuf:"bytes,4,opt,name=platform,proto3" json:"platform,omitempty"` // ios, android, web
+	AppVersion    string                 `protobuf:"bytes,5,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,6,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"` // last seen IP
+	UserAgent     string                 `protobuf:"bytes,7,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSeenAt    string                 `protobuf:"bytes,9,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserSession) Reset() {
+	*x = UserSession{}
+	mi := &file_proto_user_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserSession) ProtoMessage() {}
+
+func (x *UserSession) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserSession.ProtoReflect.Descriptor instead.
+func (*UserSession) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UserSession) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
-type ValidateTransferResponse struct {
-	state                protoimpl.MessageState `protogen:"open.v1"`
-	Allowed              bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
-	Message              string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                                                           // reason when allowed is false, e.g. "invalid PIN", "transfers paused"
-	DailyTransferLimit   float64                `protobuf:"fixed64,3,opt,name=daily_transfer_limit,json=dailyTransferLimit,proto3" json:"daily_transfer_limit,omitempty"`       // from user_settings; 0 means not set (no daily cap)
-	MonthlyTransferLimit float64                `protobuf:"fixed64,4,opt,name=monthly_transfer_limit,json=monthlyTransferLimit,proto3" json:"monthly_transfer_limit,omitempty"` // from user_settings; 0 means not set (no monthly cap)
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+func (x *UserSession) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
 }
 
-func (x *ValidateTransferResponse) Reset() {
-	*x = ValidateTransferResponse{}
-	mi := &file_proto_user_user_proto_msgTypes[10]
+func (x *UserSession) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *UserSession) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *UserSession) GetAppVersion() string {
+	if x != nil {
+		return x.AppVersion
+	}
+	return ""
+}
+
+func (x *UserSession) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *UserSession) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *UserSession) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *UserSession) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+type RevokeUserSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // empty = every session of the user
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeUserSessionsRequest) Reset() {
+	*x = RevokeUserSessionsRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidateTransferResponse) String() string {
+func (x *RevokeUserSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidateTransferResponse) ProtoMessage() {}
+func (*RevokeUserSessionsRequest) ProtoMessage() {}
 
-func (x *ValidateTransferResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_user_proto_msgTypes[10]
+func (x *RevokeUserSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -648,102 +791,971 @@ func (x *ValidateTransferResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidateTransferResponse.ProtoReflect.Descriptor instead.
-func (*ValidateTransferResponse) Descriptor() ([]byte, []int) {
-	return file_proto_user_user_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RevokeUserSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeUserSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ValidateTransferResponse) GetAllowed() bool {
+func (x *RevokeUserSessionsRequest) GetUserId() string {
 	if x != nil {
-		return x.Allowed
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RevokeUserSessionsRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeUserSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // error message when success is false
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeUserSessionsResponse) Reset() {
+	*x = RevokeUserSessionsResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeUserSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeUserSessionsResponse) ProtoMessage() {}
+
+func (x *RevokeUserSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeUserSessionsResponse.ProtoReflect.Descriptor instead.
+func (*RevokeUserSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevokeUserSessionsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
 	}
 	return false
 }
 
-func (x *ValidateTransferResponse) GetMessage() string {
+func (x *RevokeUserSessionsResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *ValidateTransferResponse) GetDailyTransferLimit() float64 {
+// UnlockUserRequest lifts login, 2FA and PIN lockouts caused by failed attempts.
+type UnlockUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockUserRequest) Reset() {
+	*x = UnlockUserRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserRequest) ProtoMessage() {}
+
+func (x *UnlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[14]
 	if x != nil {
-		return x.DailyTransferLimit
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ValidateTransferResponse) GetMonthlyTransferLimit() float64 {
+// Deprecated: Use UnlockUserRequest.ProtoReflect.Descriptor instead.
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnlockUserRequest) GetUserId() string {
 	if x != nil {
-		return x.MonthlyTransferLimit
+		return x.UserId
 	}
-	return 0
+	return ""
 }
 
-var File_proto_user_user_proto protoreflect.FileDescriptor
+type UnlockUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                       // error message when success is false
+	WasLocked     bool                   `protobuf:"varint,3,opt,name=was_locked,json=wasLocked,proto3" json:"was_locked,omitempty"` // true if any lockout was active
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_user_user_proto_rawDesc = "" +
-	"\n" +
-	"\x15proto/user/user.proto\x12\x04user\"/\n" +
-	"\x14GetUserForKYCRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x98\x01\n" +
-	"\x15GetUserForKYCResponse\x12\x14\n" +
-	"\x05found\x18\x01 \x01(\bR\x05found\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1d\n" +
-	"\n" +
-	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\x05 \x01(\tR\blastName\"@\n" +
-	"\x10ListUsersRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"W\n" +
-	"\x11ListUsersResponse\x12,\n" +
-	"\x05users\x18\x01 \x03(\v2\x16.user.AdminUserSummaryR\x05users\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"1\n" +
-	"\x16GetUserForAdminRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"[\n" +
-	"\x17GetUserForAdminResponse\x12\x14\n" +
-	"\x05found\x18\x01 \x01(\bR\x05found\x12*\n" +
-	"\x04user\x18\x02 \x01(\v2\x16.user.AdminUserSummaryR\x04user\"\xab\x02\n" +
-	"\x10AdminUserSummary\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
-	"\n" +
-	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
-	"\tlast_name\x18\x04 \x01(\tR\blastName\x12!\n" +
-	"\fphone_number\x18\x05 \x01(\tR\vphoneNumber\x12%\n" +
-	"\x0eemail_verified\x18\x06 \x01(\bR\remailVerified\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\b \x01(\tR\tupdatedAt\x12-\n" +
-	"\x12banking_restricted\x18\t \x01(\bR\x11bankingRestricted\"S\n" +
-	"\x18SetUserRestrictedRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1e\n" +
-	"\n" +
-	"restricted\x18\x02 \x01(\bR\n" +
-	"restricted\"O\n" +
-	"\x19SetUserRestrictedResponse\x12\x18\n" +
+func (x *UnlockUserResponse) Reset() {
+	*x = UnlockUserResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserResponse) ProtoMessage() {}
+
+func (x *UnlockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserResponse.ProtoReflect.Descriptor instead.
+func (*UnlockUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UnlockUserResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UnlockUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UnlockUserResponse) GetWasLocked() bool {
+	if x != nil {
+		return x.WasLocked
+	}
+	return false
+}
+
+// ResetTwoFactorRequest turns off 2FA for a user who lost every second factor, after support verified their identity.
+// Recovery codes are deleted and all sessions revoked.
+type ResetTwoFactorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AdminId       string                 `protobuf:"bytes,2,opt,name=admin_id,json=adminId,proto3" json:"admin_id,omitempty"` // recorded in the user service audit log
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetTwoFactorRequest) Reset() {
+	*x = ResetTwoFactorRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetTwoFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetTwoFactorRequest) ProtoMessage() {}
+
+func (x *ResetTwoFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetTwoFactorRequest.ProtoReflect.Descriptor instead.
+func (*ResetTwoFactorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ResetTwoFactorRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ResetTwoFactorRequest) GetAdminId() string {
+	if x != nil {
+		return x.AdminId
+	}
+	return ""
+}
+
+func (x *ResetTwoFactorRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ResetTwoFactorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // error message when success is false
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetTwoFactorResponse) Reset() {
+	*x = ResetTwoFactorResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetTwoFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetTwoFactorResponse) ProtoMessage() {}
+
+func (x *ResetTwoFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetTwoFactorResponse.ProtoReflect.Descriptor instead.
+func (*ResetTwoFactorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResetTwoFactorResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResetTwoFactorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ValidateTransferRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserId           string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount           float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`                                           // transfer amount for limit checks
+	Pin              string                 `protobuf:"bytes,3,opt,name=pin,proto3" json:"pin,omitempty"`                                                   // plain PIN (4 digits)
+	StepUpToken      string                 `protobuf:"bytes,4,opt,name=step_up_token,json=stepUpToken,proto3" json:"step_up_token,omitempty"`              // X-Step-Up-Token from the client; needed for amounts at or above the step-up threshold
+	PasskeyAssertion string                 `protobuf:"bytes,5,opt,name=passkey_assertion,json=passkeyAssertion,proto3" json:"passkey_assertion,omitempty"` // JSON {challengeId, credential} from POST /passkeys/transaction/begin; replaces pin
+	// Payee a passkey approval must have been begun for: bank code and account number of a transfer, biller code and
+	// customer ID of a bill payment.
+	BeneficiaryBankCode      string `protobuf:"bytes,6,opt,name=beneficiary_bank_code,json=beneficiaryBankCode,proto3" json:"beneficiary_bank_code,omitempty"`
+	BeneficiaryAccountNumber string `protobuf:"bytes,7,opt,name=beneficiary_account_number,json=beneficiaryAccountNumber,proto3" json:"beneficiary_account_number,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ValidateTransferRequest) Reset() {
+	*x = ValidateTransferRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTransferRequest) ProtoMessage() {}
+
+func (x *ValidateTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTransferRequest.ProtoReflect.Descriptor instead.
+func (*ValidateTransferRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ValidateTransferRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ValidateTransferRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ValidateTransferRequest) GetPin() string {
+	if x != nil {
+		return x.Pin
+	}
+	return ""
+}
+
+func (x *ValidateTransferRequest) GetStepUpToken() string {
+	if x != nil {
+		return x.StepUpToken
+	}
+	return ""
+}
+
+func (x *ValidateTransferRequest) GetPasskeyAssertion() string {
+	if x != nil {
+		return x.PasskeyAssertion
+	}
+	return ""
+}
+
+func (x *ValidateTransferRequest) GetBeneficiaryBankCode() string {
+	if x != nil {
+		return x.BeneficiaryBankCode
+	}
+	return ""
+}
+
+func (x *ValidateTransferRequest) GetBeneficiaryAccountNumber() string {
+	if x != nil {
+		return x.BeneficiaryAccountNumber
+	}
+	return ""
+}
+
+type ValidateTransferResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Allowed              bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Message              string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                                                           // reason when allowed is false, e.g. "invalid PIN", "transfers paused"
+	DailyTransferLimit   float64                `protobuf:"fixed64,3,opt,name=daily_transfer_limit,json=dailyTransferLimit,proto3" json:"daily_transfer_limit,omitempty"`       // from user_settings; 0 means not set (no daily cap)
+	MonthlyTransferLimit float64                `protobuf:"fixed64,4,opt,name=monthly_transfer_limit,json=monthlyTransferLimit,proto3" json:"monthly_transfer_limit,omitempty"` // from user_settings; 0 means not set (no monthly cap)
+	StepUpRequired       bool                   `protobuf:"varint,5,opt,name=step_up_required,json=stepUpRequired,proto3" json:"step_up_required,omitempty"`                    // not allowed until the client confirms with POST /auth/step-up (action "transfer")
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ValidateTransferResponse) Reset() {
+	*x = ValidateTransferResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTransferResponse) ProtoMessage() {}
+
+func (x *ValidateTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTransferResponse.ProtoReflect.Descriptor instead.
+func (*ValidateTransferResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ValidateTransferResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *ValidateTransferResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ValidateTransferResponse) GetDailyTransferLimit() float64 {
+	if x != nil {
+		return x.DailyTransferLimit
+	}
+	return 0
+}
+
+func (x *ValidateTransferResponse) GetMonthlyTransferLimit() float64 {
+	if x != nil {
+		return x.MonthlyTransferLimit
+	}
+	return 0
+}
+
+func (x *ValidateTransferResponse) GetStepUpRequired() bool {
+	if x != nil {
+		return x.StepUpRequired
+	}
+	return false
+}
+
+type ListPushTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPushTokensRequest) Reset() {
+	*x = ListPushTokensRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPushTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPushTokensRequest) ProtoMessage() {}
+
+func (x *ListPushTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPushTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListPushTokensRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListPushTokensRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type PushToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Platform      string                 `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"` // ios (APNs) or android (FCM)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushToken) Reset() {
+	*x = PushToken{}
+	mi := &file_proto_user_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushToken) ProtoMessage() {}
+
+func (x *PushToken) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushToken.ProtoReflect.Descriptor instead.
+func (*PushToken) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PushToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PushToken) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+type ListPushTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*PushToken           `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPushTokensResponse) Reset() {
+	*x = ListPushTokensResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPushTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPushTokensResponse) ProtoMessage() {}
+
+func (x *ListPushTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPushTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListPushTokensResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListPushTokensResponse) GetTokens() []*PushToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type RemovePushTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []string               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // provider feedback, e.g. UNREGISTERED, BadDeviceToken; recorded in the audit log
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePushTokensRequest) Reset() {
+	*x = RemovePushTokensRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePushTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePushTokensRequest) ProtoMessage() {}
+
+func (x *RemovePushTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePushTokensRequest.ProtoReflect.Descriptor instead.
+func (*RemovePushTokensRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RemovePushTokensRequest) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *RemovePushTokensRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RemovePushTokensResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       int32                  `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePushTokensResponse) Reset() {
+	*x = RemovePushTokensResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePushTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePushTokensResponse) ProtoMessage() {}
+
+func (x *RemovePushTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePushTokensResponse.ProtoReflect.Descriptor instead.
+func (*RemovePushTokensResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RemovePushTokensResponse) GetRemoved() int32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+type GetNotificationRecipientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationRecipientRequest) Reset() {
+	*x = GetNotificationRecipientRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationRecipientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationRecipientRequest) ProtoMessage() {}
+
+func (x *GetNotificationRecipientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationRecipientRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationRecipientRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetNotificationRecipientRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetNotificationRecipientResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	FirstName     string                 `protobuf:"bytes,4,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,5,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Language      string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"` // user_settings.language, e.g. en, ha, yo; empty when the user never set one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationRecipientResponse) Reset() {
+	*x = GetNotificationRecipientResponse{}
+	mi := &file_proto_user_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationRecipientResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationRecipientResponse) ProtoMessage() {}
+
+func (x *GetNotificationRecipientResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationRecipientResponse.ProtoReflect.Descriptor instead.
+func (*GetNotificationRecipientResponse) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetNotificationRecipientResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetNotificationRecipientResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *GetNotificationRecipientResponse) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *GetNotificationRecipientResponse) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *GetNotificationRecipientResponse) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *GetNotificationRecipientResponse) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+var File_proto_user_user_proto protoreflect.FileDescriptor
+
+const file_proto_user_user_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/user/user.proto\x12\x04user\"/\n" +
+	"\x14GetUserForKYCRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xbb\x01\n" +
+	"\x15GetUserForKYCResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x05 \x01(\tR\blastName\x12!\n" +
+	"\fphone_number\x18\x06 \x01(\tR\vphoneNumber\"@\n" +
+	"\x10ListUsersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"W\n" +
+	"\x11ListUsersResponse\x12,\n" +
+	"\x05users\x18\x01 \x03(\v2\x16.user.AdminUserSummaryR\x05users\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"1\n" +
+	"\x16GetUserForAdminRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"[\n" +
+	"\x17GetUserForAdminResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12*\n" +
+	"\x04user\x18\x02 \x01(\v2\x16.user.AdminUserSummaryR\x04user\"\xab\x02\n" +
+	"\x10AdminUserSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12!\n" +
+	"\fphone_number\x18\x05 \x01(\tR\vphoneNumber\x12%\n" +
+	"\x0eemail_verified\x18\x06 \x01(\bR\remailVerified\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12-\n" +
+	"\x12banking_restricted\x18\t \x01(\bR\x11bankingRestricted\"S\n" +
+	"\x18SetUserRestrictedRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"restricted\x18\x02 \x01(\bR\n" +
+	"restricted\"O\n" +
+	"\x19SetUserRestrictedResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"2\n" +
+	"\x17ListUserSessionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"I\n" +
+	"\x18ListUserSessionsResponse\x12-\n" +
+	"\bsessions\x18\x01 \x03(\v2\x11.user.UserSessionR\bsessions\"\x97\x02\n" +
+	"\vUserSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\x12\x1a\n" +
+	"\bplatform\x18\x04 \x01(\tR\bplatform\x12\x1f\n" +
+	"\vapp_version\x18\x05 \x01(\tR\n" +
+	"appVersion\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x06 \x01(\tR\tipAddress\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\a \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_seen_at\x18\t \x01(\tR\n" +
+	"lastSeenAt\"S\n" +
+	"\x19RevokeUserSessionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"P\n" +
+	"\x1aRevokeUserSessionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\",\n" +
+	"\x11UnlockUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"g\n" +
+	"\x12UnlockUserResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"was_locked\x18\x03 \x01(\bR\twasLocked\"c\n" +
+	"\x15ResetTwoFactorRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\badmin_id\x18\x02 \x01(\tR\aadminId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"L\n" +
+	"\x16ResetTwoFactorResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\\\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9f\x02\n" +
 	"\x17ValidateTransferRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x10\n" +
-	"\x03pin\x18\x03 \x01(\tR\x03pin\"\xb6\x01\n" +
+	"\x03pin\x18\x03 \x01(\tR\x03pin\x12\"\n" +
+	"\rstep_up_token\x18\x04 \x01(\tR\vstepUpToken\x12+\n" +
+	"\x11passkey_assertion\x18\x05 \x01(\tR\x10passkeyAssertion\x122\n" +
+	"\x15beneficiary_bank_code\x18\x06 \x01(\tR\x13beneficiaryBankCode\x12<\n" +
+	"\x1abeneficiary_account_number\x18\a \x01(\tR\x18beneficiaryAccountNumber\"\xe0\x01\n" +
 	"\x18ValidateTransferResponse\x12\x18\n" +
 	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
 	"\x14daily_transfer_limit\x18\x03 \x01(\x01R\x12dailyTransferLimit\x124\n" +
-	"\x16monthly_transfer_limit\x18\x04 \x01(\x01R\x14monthlyTransferLimit2]\n" +
+	"\x16monthly_transfer_limit\x18\x04 \x01(\x01R\x14monthlyTransferLimit\x12(\n" +
+	"\x10step_up_required\x18\x05 \x01(\bR\x0estepUpRequired\"0\n" +
+	"\x15ListPushTokensRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"=\n" +
+	"\tPushToken\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1a\n" +
+	"\bplatform\x18\x02 \x01(\tR\bplatform\"A\n" +
+	"\x16ListPushTokensResponse\x12'\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x0f.user.PushTokenR\x06tokens\"I\n" +
+	"\x17RemovePushTokensRequest\x12\x16\n" +
+	"\x06tokens\x18\x01 \x03(\tR\x06tokens\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"4\n" +
+	"\x18RemovePushTokensResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\x05R\aremoved\":\n" +
+	"\x1fGetNotificationRecipientRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc9\x01\n" +
+	" GetNotificationRecipientResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12!\n" +
+	"\fphone_number\x18\x03 \x01(\tR\vphoneNumber\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x05 \x01(\tR\blastName\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage2]\n" +
 	"\x11UserServiceForKYC\x12H\n" +
-	"\rGetUserForKYC\x12\x1a.user.GetUserForKYCRequest\x1a\x1b.user.GetUserForKYCResponse2\xf9\x01\n" +
+	"\rGetUserForKYC\x12\x1a.user.GetUserForKYCRequest\x1a\x1b.user.GetUserForKYCResponse2\xb3\x04\n" +
 	"\x13UserServiceForAdmin\x12<\n" +
 	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x12N\n" +
 	"\x0fGetUserForAdmin\x12\x1c.user.GetUserForAdminRequest\x1a\x1d.user.GetUserForAdminResponse\x12T\n" +
-	"\x11SetUserRestricted\x12\x1e.user.SetUserRestrictedRequest\x1a\x1f.user.SetUserRestrictedResponse2j\n" +
+	"\x11SetUserRestricted\x12\x1e.user.SetUserRestrictedRequest\x1a\x1f.user.SetUserRestrictedResponse\x12Q\n" +
+	"\x10ListUserSessions\x12\x1d.user.ListUserSessionsRequest\x1a\x1e.user.ListUserSessionsResponse\x12W\n" +
+	"\x12RevokeUserSessions\x12\x1f.user.RevokeUserSessionsRequest\x1a .user.RevokeUserSessionsResponse\x12?\n" +
+	"\n" +
+	"UnlockUser\x12\x17.user.UnlockUserRequest\x1a\x18.user.UnlockUserResponse\x12K\n" +
+	"\x0eResetTwoFactor\x12\x1b.user.ResetTwoFactorRequest\x1a\x1c.user.ResetTwoFactorResponse2j\n" +
 	"\x15UserServiceForPayment\x12Q\n" +
-	"\x10ValidateTransfer\x12\x1d.user.ValidateTransferRequest\x1a\x1e.user.ValidateTransferResponseB5Z3github.com/abubakvr/payup-backend/proto/user;userpbb\x06proto3"
+	"\x10ValidateTransfer\x12\x1d.user.ValidateTransferRequest\x1a\x1e.user.ValidateTransferResponse2\xa7\x02\n" +
+	"\x1aUserServiceForNotification\x12K\n" +
+	"\x0eListPushTokens\x12\x1b.user.ListPushTokensRequest\x1a\x1c.user.ListPushTokensResponse\x12Q\n" +
+	"\x10RemovePushTokens\x12\x1d.user.RemovePushTokensRequest\x1a\x1e.user.RemovePushTokensResponse\x12i\n" +
+	"\x18GetNotificationRecipient\x12%.user.GetNotificationRecipientRequest\x1a&.user.GetNotificationRecipientResponseB5Z3github.com/abubakvr/payup-backend/proto/user;userpbb\x06proto3"
 
 var (
 	file_proto_user_user_proto_rawDescOnce sync.Once
@@ -757,38 +1769,70 @@ func file_proto_user_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_user_proto_rawDescData
 }
 
-var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_proto_user_user_proto_goTypes = []any{
-	(*GetUserForKYCRequest)(nil),      // 0: user.GetUserForKYCRequest
-	(*GetUserForKYCResponse)(nil),     // 1: user.GetUserForKYCResponse
-	(*ListUsersRequest)(nil),          // 2: user.ListUsersRequest
-	(*ListUsersResponse)(nil),         // 3: user.ListUsersResponse
-	(*GetUserForAdminRequest)(nil),    // 4: user.GetUserForAdminRequest
-	(*GetUserForAdminResponse)(nil),   // 5: user.GetUserForAdminResponse
-	(*AdminUserSummary)(nil),          // 6: user.AdminUserSummary
-	(*SetUserRestrictedRequest)(nil),  // 7: user.SetUserRestrictedRequest
-	(*SetUserRestrictedResponse)(nil), // 8: user.SetUserRestrictedResponse
-	(*ValidateTransferRequest)(nil),   // 9: user.ValidateTransferRequest
-	(*ValidateTransferResponse)(nil),  // 10: user.ValidateTransferResponse
+	(*GetUserForKYCRequest)(nil),             // 0: user.GetUserForKYCRequest
+	(*GetUserForKYCResponse)(nil),            // 1: user.GetUserForKYCResponse
+	(*ListUsersRequest)(nil),                 // 2: user.ListUsersRequest
+	(*ListUsersResponse)(nil),                // 3: user.ListUsersResponse
+	(*GetUserForAdminRequest)(nil),           // 4: user.GetUserForAdminRequest
+	(*GetUserForAdminResponse)(nil),          // 5: user.GetUserForAdminResponse
+	(*AdminUserSummary)(nil),                 // 6: user.AdminUserSummary
+	(*SetUserRestrictedRequest)(nil),         // 7: user.SetUserRestrictedRequest
+	(*SetUserRestrictedResponse)(nil),        // 8: user.SetUserRestrictedResponse
+	(*ListUserSessionsRequest)(nil),          // 9: user.ListUserSessionsRequest
+	(*ListUserSessionsResponse)(nil),         // 10: user.ListUserSessionsResponse
+	(*UserSession)(nil),                      // 11: user.UserSession
+	(*RevokeUserSessionsRequest)(nil),        // 12: user.RevokeUserSessionsRequest
+	(*RevokeUserSessionsResponse)(nil),       // 13: user.RevokeUserSessionsResponse
+	(*UnlockUserRequest)(nil),                // 14: user.UnlockUserRequest
+	(*UnlockUserResponse)(nil),               // 15: user.UnlockUserResponse
+	(*ResetTwoFactorRequest)(nil),            // 16: user.ResetTwoFactorRequest
+	(*ResetTwoFactorResponse)(nil),           // 17: user.ResetTwoFactorResponse
+	(*ValidateTransferRequest)(nil),          // 18: user.ValidateTransferRequest
+	(*ValidateTransferResponse)(nil),         // 19: user.ValidateTransferResponse
+	(*ListPushTokensRequest)(nil),            // 20: user.ListPushTokensRequest
+	(*PushToken)(nil),                        // 21: user.PushToken
+	(*ListPushTokensResponse)(nil),           // 22: user.ListPushTokensResponse
+	(*RemovePushTokensRequest)(nil),          // 23: user.RemovePushTokensRequest
+	(*RemovePushTokensResponse)(nil),         // 24: user.RemovePushTokensResponse
+	(*GetNotificationRecipientRequest)(nil),  // 25: user.GetNotificationRecipientRequest
+	(*GetNotificationRecipientResponse)(nil), // 26: user.GetNotificationRecipientResponse
 }
 var file_proto_user_user_proto_depIdxs = []int32{
 	6,  // 0: user.ListUsersResponse.users:type_name -> user.AdminUserSummary
 	6,  // 1: user.GetUserForAdminResponse.user:type_name -> user.AdminUserSummary
-	0,  // 2: user.UserServiceForKYC.GetUserForKYC:input_type -> user.GetUserForKYCRequest
-	2,  // 3: user.UserServiceForAdmin.ListUsers:input_type -> user.ListUsersRequest
-	4,  // 4: user.UserServiceForAdmin.GetUserForAdmin:input_type -> user.GetUserForAdminRequest
-	7,  // 5: user.UserServiceForAdmin.SetUserRestricted:input_type -> user.SetUserRestrictedRequest
-	9,  // 6: user.UserServiceForPayment.ValidateTransfer:input_type -> user.ValidateTransferRequest
-	1,  // 7: user.UserServiceForKYC.GetUserForKYC:output_type -> user.GetUserForKYCResponse
-	3,  // 8: user.UserServiceForAdmin.ListUsers:output_type -> user.ListUsersResponse
-	5,  // 9: user.UserServiceForAdmin.GetUserForAdmin:output_type -> user.GetUserForAdminResponse
-	8,  // 10: user.UserServiceForAdmin.SetUserRestricted:output_type -> user.SetUserRestrictedResponse
-	10, // 11: user.UserServiceForPayment.ValidateTransfer:output_type -> user.ValidateTransferResponse
-	7,  // [7:12] is the sub-list for method output_type
-	2,  // [2:7] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	11, // 2: user.ListUserSessionsResponse.sessions:type_name -> user.UserSession
+	21, // 3: user.ListPushTokensResponse.tokens:type_name -> user.PushToken
+	0,  // 4: user.UserServiceForKYC.GetUserForKYC:input_type -> user.GetUserForKYCRequest
+	2,  // 5: user.UserServiceForAdmin.ListUsers:input_type -> user.ListUsersRequest
+	4,  // 6: user.UserServiceForAdmin.GetUserForAdmin:input_type -> user.GetUserForAdminRequest
+	7,  // 7: user.UserServiceForAdmin.SetUserRestricted:input_type -> user.SetUserRestrictedRequest
+	9,  // 8: user.UserServiceForAdmin.ListUserSessions:input_type -> user.ListUserSessionsRequest
+	12, // 9: user.UserServiceForAdmin.RevokeUserSessions:input_type -> user.RevokeUserSessionsRequest
+	14, // 10: user.UserServiceForAdmin.UnlockUser:input_type -> user.UnlockUserRequest
+	16, // 11: user.UserServiceForAdmin.ResetTwoFactor:input_type -> user.ResetTwoFactorRequest
+	18, // 12: user.UserServiceForPayment.ValidateTransfer:input_type -> user.ValidateTransferRequest
+	20, // 13: user.UserServiceForNotification.ListPushTokens:input_type -> user.ListPushTokensRequest
+	23, // 14: user.UserServiceForNotification.RemovePushTokens:input_type -> user.RemovePushTokensRequest
+	25, // 15: user.UserServiceForNotification.GetNotificationRecipient:input_type -> user.GetNotificationRecipientRequest
+	1,  // 16: user.UserServiceForKYC.GetUserForKYC:output_type -> user.GetUserForKYCResponse
+	3,  // 17: user.UserServiceForAdmin.ListUsers:output_type -> user.ListUsersResponse
+	5,  // 18: user.UserServiceForAdmin.GetUserForAdmin:output_type -> user.GetUserForAdminResponse
+	8,  // 19: user.UserServiceForAdmin.SetUserRestricted:output_type -> user.SetUserRestrictedResponse
+	10, // 20: user.UserServiceForAdmin.ListUserSessions:output_type -> user.ListUserSessionsResponse
+	13, // 21: user.UserServiceForAdmin.RevokeUserSessions:output_type -> user.RevokeUserSessionsResponse
+	15, // 22: user.UserServiceForAdmin.UnlockUser:output_type -> user.UnlockUserResponse
+	17, // 23: user.UserServiceForAdmin.ResetTwoFactor:output_type -> user.ResetTwoFactorResponse
+	19, // 24: user.UserServiceForPayment.ValidateTransfer:output_type -> user.ValidateTransferResponse
+	22, // 25: user.UserServiceForNotification.ListPushTokens:output_type -> user.ListPushTokensResponse
+	24, // 26: user.UserServiceForNotification.RemovePushTokens:output_type -> user.RemovePushTokensResponse
+	26, // 27: user.UserServiceForNotification.GetNotificationRecipient:output_type -> user.GetNotificationRecipientResponse
+	16, // [16:28] is the sub-list for method output_type
+	4,  // [4:16] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_user_user_proto_init() }
@@ -802,9 +1846,9 @@ func file_proto_user_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_user_proto_rawDesc), len(file_proto_user_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   27,
 			NumExtensions: 0,
-			NumServices:   3,
+			NumServices:   4,
 		},
 		GoTypes:           file_proto_user_user_proto_goTypes,
 		DependencyIndexes: file_proto_user_user_proto_depIdxs,
@@ -125,20 +125,28 @@ var UserServiceForKYC_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	UserServiceForAdmin_ListUsers_FullMethodName         = "/user.UserServiceForAdmin/ListUsers"
-	UserServiceForAdmin_GetUserForAdmin_FullMethodName   = "/user.UserServiceForAdmin/GetUserForAdmin"
-	UserServiceForAdmin_SetUserRestricted_FullMethodName = "/user.UserServiceForAdmin/SetUserRestricted"
+	UserServiceForAdmin_ListUsers_FullMethodName          = "/user.UserServiceForAdmin/ListUsers"
+	UserServiceForAdmin_GetUserForAdmin_FullMethodName    = "/user.UserServiceForAdmin/GetUserForAdmin"
+	UserServiceForAdmin_SetUserRestricted_FullMethodName  = "/user.UserServiceForAdmin/SetUserRestricted"
+	UserServiceForAdmin_ListUserSessions_FullMethodName   = "/user.UserServiceForAdmin/ListUserSessions"
+	UserServiceForAdmin_RevokeUserSessions_FullMethodName = "/user.UserServiceForAdmin/RevokeUserSessions"
+	UserServiceForAdmin_UnlockUser_FullMethodName         = "/user.UserServiceForAdmin/UnlockUser"
+	UserServiceForAdmin_ResetTwoFactor_FullMethodName     = "/user.UserServiceForAdmin/ResetTwoFactor"
 )
 
 // UserServiceForAdminClient is the client API for UserServiceForAdmin service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
-// UserServiceForAdmin is used by Admin service for portal: list users, get user, set restricted.
+// UserServiceForAdmin is used by Admin service for portal: list users, get user, set restricted, manage sessions.
 type UserServiceForAdminClient interface {
 	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
 	GetUserForAdmin(ctx context.Context, in *GetUserForAdminRequest, opts ...grpc.CallOption) (*GetUserForAdminResponse, error)
 	SetUserRestricted(ctx context.Context, in *SetUserRestrictedRequest, opts ...grpc.CallOption) (*SetUserRestrictedResponse, error)
+	ListUserSessions(ctx context.Context, in *ListUserSessionsRequest, opts ...grpc.CallOption) (*ListUserSessionsResponse, error)
+	RevokeUserSessions(ctx context.Context, in *RevokeUserSessionsRequest, opts ...grpc.CallOption) (*RevokeUserSessionsResponse, error)
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error)
+	ResetTwoFactor(ctx context.Context, in *ResetTwoFactorRequest, opts ...grpc.CallOption) (*ResetTwoFactorResponse, error)
 }
 
 type userServiceForAdminClient struct {
@@ -179,15 +187,59 @@ func (c *userServiceForAdminClient) SetUserRestricted(ctx context.Context, in *S
 	return out, nil
 }
 
+func (c *userServiceForAdminClient) ListUserSessions(ctx context.Context, in *ListUserSessionsRequest, opts ...grpc.CallOption) (*ListUserSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserSessionsResponse)
+	err := c.cc.Invoke(ctx, UserServiceForAdmin_ListUserSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceForAdminClient) RevokeUserSessions(ctx context.Context, in *RevokeUserSessionsRequest, opts ...grpc.CallOption) (*RevokeUserSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeUserSessionsResponse)
+	err := c.cc.Invoke(ctx, UserServiceForAdmin_RevokeUserSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceForAdminClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockUserResponse)
+	err := c.cc.Invoke(ctx, UserServiceForAdmin_UnlockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceForAdminClient) ResetTwoFactor(ctx context.Context, in *ResetTwoFactorRequest, opts ...grpc.CallOption) (*ResetTwoFactorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetTwoFactorResponse)
+	err := c.cc.Invoke(ctx, UserServiceForAdmin_ResetTwoFactor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceForAdminServer is the server API for UserServiceForAdmin service.
 // All implementations must embed UnimplementedUserServiceForAdminServer
 // for forward compatibility.
 //
-// UserServiceForAdmin is used by Admin service for portal: list users, get user, set restricted.
+// UserServiceForAdmin is used by Admin service for portal: list users, get user, set restricted, manage sessions.
 type UserServiceForAdminServer interface {
 	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
 	GetUserForAdmin(context.Context, *GetUserForAdminRequest) (*GetUserForAdminResponse, error)
 	SetUserRestricted(context.Context, *SetUserRestrictedRequest) (*SetUserRestrictedResponse, error)
+	ListUserSessions(context.Context, *ListUserSessionsRequest) (*ListUserSessionsResponse, error)
+	RevokeUserSessions(context.Context, *RevokeUserSessionsRequest) (*RevokeUserSessionsResponse, error)
+	UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error)
+	ResetTwoFactor(context.Context, *ResetTwoFactorRequest) (*ResetTwoFactorResponse, error)
 	mustEmbedUnimplementedUserServiceForAdminServer()
 }
 
@@ -207,6 +259,18 @@ func (UnimplementedUserServiceForAdminServer) GetUserForAdmin(context.Context, *
 func (UnimplementedUserServiceForAdminServer) SetUserRestricted(context.Context, *SetUserRestrictedRequest) (*SetUserRestrictedResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SetUserRestricted not implemented")
 }
+func (UnimplementedUserServiceForAdminServer) ListUserSessions(context.Context, *ListUserSessionsRequest) (*ListUserSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUserSessions not implemented")
+}
+func (UnimplementedUserServiceForAdminServer) RevokeUserSessions(context.Context, *RevokeUserSessionsRequest) (*RevokeUserSessionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RevokeUserSessions not implemented")
+}
+func (UnimplementedUserServiceForAdminServer) UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnlockUser not implemented")
+}
+func (UnimplementedUserServiceForAdminServer) ResetTwoFactor(context.Context, *ResetTwoFactorRequest) (*ResetTwoFactorResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResetTwoFactor not implemented")
+}
 func (UnimplementedUserServiceForAdminServer) mustEmbedUnimplementedUserServiceForAdminServer() {}
 func (UnimplementedUserServiceForAdminServer) testEmbeddedByValue()                             {}
 
@@ -282,6 +346,78 @@ func _UserServiceForAdmin_SetUserRestricted_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserServiceForAdmin_ListUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForAdminServer).ListUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForAdmin_ListUserSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForAdminServer).ListUserSessions(ctx, req.(*ListUserSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserServiceForAdmin_RevokeUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForAdminServer).RevokeUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForAdmin_RevokeUserSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForAdminServer).RevokeUserSessions(ctx, req.(*RevokeUserSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserServiceForAdmin_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForAdminServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForAdmin_UnlockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForAdminServer).UnlockUser(ctx, req.(*UnlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserServiceForAdmin_ResetTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetTwoFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForAdminServer).ResetTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForAdmin_ResetTwoFactor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForAdminServer).ResetTwoFactor(ctx, req.(*ResetTwoFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserServiceForAdmin_ServiceDesc is the grpc.ServiceDesc for UserServiceForAdmin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -301,6 +437,22 @@ var UserServiceForAdmin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetUserRestricted",
 			Handler:    _UserServiceForAdmin_SetUserRestricted_Handler,
 		},
+		{
+			MethodName: "ListUserSessions",
+			Handler:    _UserServiceForAdmin_ListUserSessions_Handler,
+		},
+		{
+			MethodName: "RevokeUserSessions",
+			Handler:    _UserServiceForAdmin_RevokeUserSessions_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _UserServiceForAdmin_UnlockUser_Handler,
+		},
+		{
+			MethodName: "ResetTwoFactor",
+			Handler:    _UserServiceForAdmin_ResetTwoFactor_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user/user.proto",
@@ -411,3 +563,188 @@ var UserServiceForPayment_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/user/user.proto",
 }
+
+const (
+	UserServiceForNotification_ListPushTokens_FullMethodName           = "/user.UserServiceForNotification/ListPushTokens"
+	UserServiceForNotification_RemovePushTokens_FullMethodName         = "/user.UserServiceForNotification/RemovePushTokens"
+	UserServiceForNotification_GetNotificationRecipient_FullMethodName = "/user.UserServiceForNotification/GetNotificationRecipient"
+)
+
+// UserServiceForNotificationClient is the client API for UserServiceForNotification service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserServiceForNotification is used by the notification service to fan push notifications out to a user's devices,
+// to drop tokens the push provider reported as invalid, and to address and localize templated notifications.
+type UserServiceForNotificationClient interface {
+	ListPushTokens(ctx context.Context, in *ListPushTokensRequest, opts ...grpc.CallOption) (*ListPushTokensResponse, error)
+	RemovePushTokens(ctx context.Context, in *RemovePushTokensRequest, opts ...grpc.CallOption) (*RemovePushTokensResponse, error)
+	GetNotificationRecipient(ctx context.Context, in *GetNotificationRecipientRequest, opts ...grpc.CallOption) (*GetNotificationRecipientResponse, error)
+}
+
+type userServiceForNotificationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceForNotificationClient(cc grpc.ClientConnInterface) UserServiceForNotificationClient {
+	return &userServiceForNotificationClient{cc}
+}
+
+func (c *userServiceForNotificationClient) ListPushTokens(ctx context.Context, in *ListPushTokensRequest, opts ...grpc.CallOption) (*ListPushTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPushTokensResponse)
+	err := c.cc.Invoke(ctx, UserServiceForNotification_ListPushTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceForNotificationClient) RemovePushTokens(ctx context.Context, in *RemovePushTokensRequest, opts ...grpc.CallOption) (*RemovePushTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePushTokensResponse)
+	err := c.cc.Invoke(ctx, UserServiceForNotification_RemovePushTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceForNotificationClient) GetNotificationRecipient(ctx context.Context, in *GetNotificationRecipientRequest, opts ...grpc.CallOption) (*GetNotificationRecipientResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNotificationRecipientResponse)
+	err := c.cc.Invoke(ctx, UserServiceForNotification_GetNotificationRecipient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceForNotificationServer is the server API for UserServiceForNotification service.
+// All implementations must embed UnimplementedUserServiceForNotificationServer
+// for forward compatibility.
+//
+// UserServiceForNotification is used by the notification service to fan push notifications out to a user's devices,
+// to drop tokens the push provider reported as invalid, and to address and localize templated notifications.
+type UserServiceForNotificationServer interface {
+	ListPushTokens(context.Context, *ListPushTokensRequest) (*ListPushTokensResponse, error)
+	RemovePushTokens(context.Context, *RemovePushTokensRequest) (*RemovePushTokensResponse, error)
+	GetNotificationRecipient(context.Context, *GetNotificationRecipientRequest) (*GetNotificationRecipientResponse, error)
+	mustEmbedUnimplementedUserServiceForNotificationServer()
+}
+
+// UnimplementedUserServiceForNotificationServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceForNotificationServer struct{}
+
+func (UnimplementedUserServiceForNotificationServer) ListPushTokens(context.Context, *ListPushTokensRequest) (*ListPushTokensResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPushTokens not implemented")
+}
+func (UnimplementedUserServiceForNotificationServer) RemovePushTokens(context.Context, *RemovePushTokensRequest) (*RemovePushTokensResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemovePushTokens not implemented")
+}
+func (UnimplementedUserServiceForNotificationServer) GetNotificationRecipient(context.Context, *GetNotificationRecipientRequest) (*GetNotificationRecipientResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNotificationRecipient not implemented")
+}
+func (UnimplementedUserServiceForNotificationServer) mustEmbedUnimplementedUserServiceForNotificationServer() {
+}
+func (UnimplementedUserServiceForNotificationServer) testEmbeddedByValue() {}
+
+// UnsafeUserServiceForNotificationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceForNotificationServer will
+// result in compilation errors.
+type UnsafeUserServiceForNotificationServer interface {
+	mustEmbedUnimplementedUserServiceForNotificationServer()
+}
+
+func RegisterUserServiceForNotificationServer(s grpc.ServiceRegistrar, srv UserServiceForNotificationServer) {
+	// If the following call panics, it indicates UnimplementedUserServiceForNotificationServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserServiceForNotification_ServiceDesc, srv)
+}
+
+func _UserServiceForNotification_ListPushTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPushTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForNotificationServer).ListPushTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForNotification_ListPushTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForNotificationServer).ListPushTokens(ctx, req.(*ListPushTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserServiceForNotification_RemovePushTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePushTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForNotificationServer).RemovePushTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForNotification_RemovePushTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForNotificationServer).RemovePushTokens(ctx, req.(*RemovePushTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserServiceForNotification_GetNotificationRecipient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationRecipientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceForNotificationServer).GetNotificationRecipient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserServiceForNotification_GetNotificationRecipient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceForNotificationServer).GetNotificationRecipient(ctx, req.(*GetNotificationRecipientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserServiceForNotification_ServiceDesc is the grpc.ServiceDesc for UserServiceForNotification service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserServiceForNotification_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "user.UserServiceForNotification",
+	HandlerType: (*UserServiceForNotificationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPushTokens",
+			Handler:    _UserServiceForNotification_ListPushTokens_Handler,
+		},
+		{
+			MethodName: "RemovePushTokens",
+			Handler:    _UserServiceForNotification_RemovePushTokens_Handler,
+		},
+		{
+			MethodName: "GetNotificationRecipient",
+			Handler:    _UserServiceForNotification_GetNotificationRecipient_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/user/user.proto",
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/abubakvr/payup-backend/pkg/gateway"
 	"github.com/abubakvr/payup-backend/services/admin/internal/config"
 	"github.com/abubakvr/payup-backend/services/admin/internal/controller"
 	"github.com/abubakvr/payup-backend/services/admin/internal/repository"
@@ -57,6 +58,15 @@ func main() {
 			defer paymentClient.Close()
 		}
 	}
+	var notificationClient *clients.NotificationAdminClient
+	if cfg.NotificationServiceGrpcAddr != "" {
+		if n, err := clients.NewNotificationAdminClient(cfg.NotificationServiceGrpcAddr); err != nil {
+			log.Printf("admin: notification gRPC client: %v", err)
+		} else {
+			notificationClient = n
+			defer notificationClient.Close()
+		}
+	}
 
 	var auditProducer *kafka.AuditProducer
 	var notificationProducer *kafka.NotificationProducer
@@ -82,8 +92,11 @@ func main() {
 		log.Printf("Super admin created from ADMIN_BOOTSTRAP_* env")
 	}
 
-	ctrl := controller.NewAdminController(svc, userClient, kycClient, auditClient, paymentClient, auditProducer, notificationProducer, cfg.AdminPortalURL, cfg.KYCAdminAPIKey)
+	ctrl := controller.NewAdminController(svc, userClient, kycClient, auditClient, paymentClient, notificationClient, auditProducer, notificationProducer, cfg.AdminPortalURL, cfg.KYCAdminAPIKey)
 	r := router.Setup(ctrl)
+	if err := gateway.TrustProxies(r, cfg.TrustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 
 	addr := ":" + cfg.Port
 	log.Printf("Admin service listening on %s", addr)
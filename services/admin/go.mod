@@ -3,6 +3,7 @@ module github.com/abubakvr/payup-backend/services/admin
 go 1.25.0
 
 require (
+	github.com/abubakvr/payup-backend/pkg v0.0.0
 	github.com/abubakvr/payup-backend/proto v0.0.0
 	github.com/gin-gonic/gin v1.11.0
 	github.com/golang-jwt/jwt/v5 v5.3.1
@@ -13,6 +14,8 @@ require (
 	google.golang.org/grpc v1.79.0
 )
 
+replace github.com/abubakvr/payup-backend/pkg => ../../pkg
+
 replace github.com/abubakvr/payup-backend/proto => ../../proto
 
 require (
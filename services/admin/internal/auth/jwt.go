@@ -1,19 +1,29 @@
 package auth
 
 import (
-	"os"
+	"log"
 	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/jwtauth"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte(os.Getenv("ADMIN_JWT_SECRET"))
+// jwtKeys signs admin tokens. It is a separate key set from the user service (ADMIN_JWT_KEYS_DIR,
+// ADMIN_JWT_ACTIVE_KID), so a user-service key can never mint an admin token and vice versa.
+var jwtKeys = mustLoadJWTKeys()
 
-func init() {
-	if len(jwtSecret) == 0 {
-		jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+func mustLoadJWTKeys() *jwtauth.KeySet {
+	ks, err := jwtauth.KeySetFromEnv("ADMIN_JWT")
+	if err != nil {
+		log.Fatalf("admin jwt keys: %v", err)
 	}
+	return ks
+}
+
+// JWKS returns the public keys admin tokens are signed with.
+func JWKS() jwtauth.JWKS {
+	return jwtKeys.JWKS()
 }
 
 type AdminClaims struct {
@@ -39,15 +49,14 @@ func IssueToken(adminID, email, role string, mustChangePassword bool) (string, t
 		IssuedAt:           now.Unix(),
 		ExpiresAt:          exp.Unix(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:   "payup-admin",
-			Subject:  adminID,
-			Audience: jwt.ClaimStrings{"admin-portal"},
-			IssuedAt: jwt.NewNumericDate(now),
+			Issuer:    jwtauth.AdminIssuer,
+			Subject:   adminID,
+			Audience:  jwt.ClaimStrings{jwtauth.AdminAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(exp),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	s, err := token.SignedString(jwtSecret)
+	s, err := jwtKeys.Sign(claims)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -55,16 +64,10 @@ func IssueToken(adminID, email, role string, mustChangePassword bool) (string, t
 }
 
 func ValidateToken(tokenString string) (*AdminClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AdminClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-	if err != nil {
+	claims := &AdminClaims{}
+	if err := jwtKeys.Parse(tokenString, jwtauth.AdminIssuer, jwtauth.AdminAudience, claims); err != nil {
 		return nil, err
 	}
-	claims, ok := token.Claims.(*AdminClaims)
-	if !ok || !token.Valid {
-		return nil, jwt.ErrTokenInvalidClaims
-	}
 	return claims, nil
 }
 
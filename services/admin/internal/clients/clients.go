@@ -6,6 +6,7 @@ import (
 
 	auditpb "github.com/abubakvr/payup-backend/proto/audit"
 	kycpb "github.com/abubakvr/payup-backend/proto/kyc"
+	notificationpb "github.com/abubakvr/payup-backend/proto/notification"
 	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
 	userpb "github.com/abubakvr/payup-backend/proto/user"
 	"google.golang.org/grpc"
@@ -63,6 +64,45 @@ func (c *UserAdminClient) SetUserRestricted(ctx context.Context, userID string,
 	return resp, nil
 }
 
+func (c *UserAdminClient) ListUserSessions(ctx context.Context, userID string) (*userpb.ListUserSessionsResponse, error) {
+	resp, err := c.client.ListUserSessions(ctx, &userpb.ListUserSessionsRequest{UserId: userID})
+	if err != nil {
+		log.Printf("admin: user gRPC ListUserSessions: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RevokeUserSessions kills one session of the user, or all of them when sessionID is empty.
+func (c *UserAdminClient) RevokeUserSessions(ctx context.Context, userID, sessionID string) (*userpb.RevokeUserSessionsResponse, error) {
+	resp, err := c.client.RevokeUserSessions(ctx, &userpb.RevokeUserSessionsRequest{UserId: userID, SessionId: sessionID})
+	if err != nil {
+		log.Printf("admin: user gRPC RevokeUserSessions: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UnlockUser lifts the user's login, 2FA and PIN lockouts.
+func (c *UserAdminClient) UnlockUser(ctx context.Context, userID string) (*userpb.UnlockUserResponse, error) {
+	resp, err := c.client.UnlockUser(ctx, &userpb.UnlockUserRequest{UserId: userID})
+	if err != nil {
+		log.Printf("admin: user gRPC UnlockUser: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ResetTwoFactor turns off the user's 2FA, deletes their recovery codes and signs them out everywhere.
+func (c *UserAdminClient) ResetTwoFactor(ctx context.Context, userID, adminID, reason string) (*userpb.ResetTwoFactorResponse, error) {
+	resp, err := c.client.ResetTwoFactor(ctx, &userpb.ResetTwoFactorRequest{UserId: userID, AdminId: adminID, Reason: reason})
+	if err != nil {
+		log.Printf("admin: user gRPC ResetTwoFactor: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
 // KYCAdminClient calls KYC service gRPC for admin (GetFullKYCForAdmin).
 type KYCAdminClient struct {
 	client kycpb.KYCServiceClient
@@ -320,3 +360,151 @@ func (c *PaymentAdminClient) GetWalletUpgradeStatusByUserID(ctx context.Context,
 	}
 	return c.client.GetWalletUpgradeStatusByUserID(ctx, &paymentpb.GetWalletUpgradeStatusByUserIDRequest{UserId: userID})
 }
+
+// ListDisputes returns the transaction dispute queue for admin (optional status filter, paginated).
+func (c *PaymentAdminClient) ListDisputes(ctx context.Context, status string, limit, offset int32) (*paymentpb.ListDisputesResponse, error) {
+	if c == nil || c.client == nil {
+		return &paymentpb.ListDisputesResponse{}, nil
+	}
+	return c.client.ListDisputes(ctx, &paymentpb.ListDisputesRequest{Status: status, Limit: limit, Offset: offset})
+}
+
+// GetDispute returns one dispute by id with notes and attachments.
+func (c *PaymentAdminClient) GetDispute(ctx context.Context, id string) (*paymentpb.GetDisputeResponse, error) {
+	if c == nil || c.client == nil {
+		return &paymentpb.GetDisputeResponse{Found: false}, nil
+	}
+	return c.client.GetDispute(ctx, &paymentpb.GetDisputeRequest{Id: id})
+}
+
+// UpdateDisputeStatus moves a dispute to UNDER_REVIEW, AWAITING_USER or REJECTED.
+func (c *PaymentAdminClient) UpdateDisputeStatus(ctx context.Context, id, status, note, initiatedBy string) (*paymentpb.UpdateDisputeStatusResponse, error) {
+	if c == nil || c.client == nil {
+		return &paymentpb.UpdateDisputeStatusResponse{Success: false, ErrorMessage: "payment client not configured"}, nil
+	}
+	resp, err := c.client.UpdateDisputeStatus(ctx, &paymentpb.UpdateDisputeStatusRequest{Id: id, Status: status, Note: note, InitiatedBy: initiatedBy})
+	if err != nil {
+		log.Printf("admin: payment gRPC UpdateDisputeStatus: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AddDisputeNote adds an admin note to a dispute (internal notes are hidden from the user).
+func (c *PaymentAdminClient) AddDisputeNote(ctx context.Context, id, body string, internal bool, initiatedBy string) (*paymentpb.AddDisputeNoteResponse, error) {
+	if c == nil || c.client == nil {
+		return &paymentpb.AddDisputeNoteResponse{Success: false, ErrorMessage: "payment client not configured"}, nil
+	}
+	resp, err := c.client.AddDisputeNote(ctx, &paymentpb.AddDisputeNoteRequest{Id: id, Body: body, IsInternal: internal, InitiatedBy: initiatedBy})
+	if err != nil {
+		log.Printf("admin: payment gRPC AddDisputeNote: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AddDisputeAttachment records an evidence file on a dispute.
+func (c *PaymentAdminClient) AddDisputeAttachment(ctx context.Context, id, fileName, contentType, url, initiatedBy string) (*paymentpb.AddDisputeAttachmentResponse, error) {
+	if c == nil || c.client == nil {
+		return &paymentpb.AddDisputeAttachmentResponse{Success: false, ErrorMessage: "payment client not configured"}, nil
+	}
+	resp, err := c.client.AddDisputeAttachment(ctx, &paymentpb.AddDisputeAttachmentRequest{
+		Id:          id,
+		FileName:    fileName,
+		ContentType: contentType,
+		Url:         url,
+		InitiatedBy: initiatedBy,
+	})
+	if err != nil {
+		log.Printf("admin: payment gRPC AddDisputeAttachment: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ResolveDispute closes a dispute as RESOLVED; REVERSAL/ADJUSTMENT post a wallet credit or debit in the payment service.
+func (c *PaymentAdminClient) ResolveDispute(ctx context.Context, id, resolution string, amount float64, isCredit bool, note, initiatedBy string) (*paymentpb.ResolveDisputeResponse, error) {
+	if c == nil || c.client == nil {
+		return &paymentpb.ResolveDisputeResponse{Success: false, ErrorMessage: "payment client not configured"}, nil
+	}
+	resp, err := c.client.ResolveDispute(ctx, &paymentpb.ResolveDisputeRequest{
+		Id:          id,
+		Resolution:  resolution,
+		Amount:      amount,
+		IsCredit:    isCredit,
+		Note:        note,
+		InitiatedBy: initiatedBy,
+	})
+	if err != nil {
+		log.Printf("admin: payment gRPC ResolveDispute: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// NotificationAdminClient calls notification service gRPC for support: notification history, delivery status and resend.
+type NotificationAdminClient struct {
+	client notificationpb.NotificationServiceForAdminClient
+	conn   *grpc.ClientConn
+}
+
+func NewNotificationAdminClient(addr string) (*NotificationAdminClient, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationAdminClient{
+		client: notificationpb.NewNotificationServiceForAdminClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+func (c *NotificationAdminClient) Close() error {
+	if c != nil && c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// ListNotifications returns notifications sent to a user (userID) or to an email address or phone number (recipient),
+// newest first. Recipients come back masked.
+func (c *NotificationAdminClient) ListNotifications(ctx context.Context, req *notificationpb.ListNotificationsRequest) (*notificationpb.ListNotificationsResponse, error) {
+	if c == nil || c.client == nil {
+		return &notificationpb.ListNotificationsResponse{}, nil
+	}
+	resp, err := c.client.ListNotifications(ctx, req)
+	if err != nil {
+		log.Printf("admin: notification gRPC ListNotifications: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNotification returns one notification with its delivery status and the channels tried.
+func (c *NotificationAdminClient) GetNotification(ctx context.Context, id string) (*notificationpb.GetNotificationResponse, error) {
+	if c == nil || c.client == nil {
+		return &notificationpb.GetNotificationResponse{Found: false}, nil
+	}
+	resp, err := c.client.GetNotification(ctx, &notificationpb.GetNotificationRequest{Id: id})
+	if err != nil {
+		log.Printf("admin: notification gRPC GetNotification: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ResendNotification sends a notification again to the user's current address. One-time codes cannot be resent.
+func (c *NotificationAdminClient) ResendNotification(ctx context.Context, id, adminID string) (*notificationpb.ResendNotificationResponse, error) {
+	if c == nil || c.client == nil {
+		return &notificationpb.ResendNotificationResponse{Success: false, ErrorMessage: "notification client not configured"}, nil
+	}
+	resp, err := c.client.ResendNotification(ctx, &notificationpb.ResendNotificationRequest{Id: id, AdminId: adminID})
+	if err != nil {
+		log.Printf("admin: notification gRPC ResendNotification: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/abubakvr/payup-backend/pkg/gateway"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 type Config struct {
 	Port                  string
 	DatabaseURL           string
-	JWTSecret             string
 	BootstrapEmail        string
 	BootstrapPassword     string
 	BootstrapFirstName    string
@@ -20,9 +20,11 @@ type Config struct {
 	KYCServiceGrpcAddr    string // e.g. kyc-service:9002
 	AuditServiceGrpcAddr  string // e.g. audit-service:9003
 	PaymentServiceGrpcAddr string // e.g. payment-service:9004
+	NotificationServiceGrpcAddr string // e.g. notification-service:9005
 	KYCAdminAPIKey        string // X-Admin-Key used to call KYC HTTP admin image endpoint
 	KafkaBroker           string // e.g. redpanda:9092 (for audit-events, notification-events)
 	AdminPortalURL        string // optional; e.g. https://admin.payup.ng (included in welcome email login link)
+	TrustedProxies        []string // TRUSTED_PROXIES: addresses/CIDRs whose X-Real-IP is believed; empty = private ranges
 }
 
 func LoadConfig() *Config {
@@ -42,10 +44,6 @@ func LoadConfig() *Config {
 			os.Getenv("ADMIN_DB_SSLMODE"),
 		)
 	}
-	jwtSecret := os.Getenv("ADMIN_JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = os.Getenv("JWT_SECRET")
-	}
 	userGrpc := os.Getenv("USER_SERVICE_GRPC_ADDR")
 	if userGrpc == "" {
 		userGrpc = "user-service:9001"
@@ -62,6 +60,10 @@ func LoadConfig() *Config {
 	if paymentGrpc == "" {
 		paymentGrpc = "payment-service:9004"
 	}
+	notificationGrpc := os.Getenv("NOTIFICATION_SERVICE_GRPC_ADDR")
+	if notificationGrpc == "" {
+		notificationGrpc = "notification-service:9005"
+	}
 	kycAdminKey := os.Getenv("KYC_ADMIN_API_KEY")
 	if kycAdminKey == "" {
 		kycAdminKey = os.Getenv("ADMIN_API_KEY")
@@ -74,7 +76,6 @@ func LoadConfig() *Config {
 	return &Config{
 		Port:                 port,
 		DatabaseURL:          dbURL,
-		JWTSecret:            jwtSecret,
 		BootstrapEmail:       os.Getenv("ADMIN_BOOTSTRAP_EMAIL"),
 		BootstrapPassword:    os.Getenv("ADMIN_BOOTSTRAP_PASSWORD"),
 		BootstrapFirstName:   os.Getenv("ADMIN_BOOTSTRAP_FIRST_NAME"),
@@ -83,9 +84,11 @@ func LoadConfig() *Config {
 		KYCServiceGrpcAddr:   kycGrpc,
 		AuditServiceGrpcAddr:  auditGrpc,
 		PaymentServiceGrpcAddr: paymentGrpc,
+		NotificationServiceGrpcAddr: notificationGrpc,
 		KYCAdminAPIKey:       kycAdminKey,
 		KafkaBroker:          kafkaBroker,
 		AdminPortalURL:       portalURL,
+		TrustedProxies:       gateway.ParseProxies(os.Getenv("TRUSTED_PROXIES")),
 	}
 }
 
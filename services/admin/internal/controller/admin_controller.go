@@ -25,14 +25,15 @@ type AdminController struct {
 	kyc                  *clients.KYCAdminClient
 	audit                *clients.AuditAdminClient
 	payment              *clients.PaymentAdminClient
+	notification         *clients.NotificationAdminClient
 	auditProducer        *kafka.AuditProducer
 	notificationProducer *kafka.NotificationProducer
 	portalURL            string
 	kycKey               string
 }
 
-func NewAdminController(svc *service.AdminService, user *clients.UserAdminClient, kyc *clients.KYCAdminClient, audit *clients.AuditAdminClient, payment *clients.PaymentAdminClient, auditProducer *kafka.AuditProducer, notificationProducer *kafka.NotificationProducer, portalURL, kycAdminKey string) *AdminController {
-	return &AdminController{svc: svc, user: user, kyc: kyc, audit: audit, payment: payment, auditProducer: auditProducer, notificationProducer: notificationProducer, portalURL: portalURL, kycKey: kycAdminKey}
+func NewAdminController(svc *service.AdminService, user *clients.UserAdminClient, kyc *clients.KYCAdminClient, audit *clients.AuditAdminClient, payment *clients.PaymentAdminClient, notification *clients.NotificationAdminClient, auditProducer *kafka.AuditProducer, notificationProducer *kafka.NotificationProducer, portalURL, kycAdminKey string) *AdminController {
+	return &AdminController{svc: svc, user: user, kyc: kyc, audit: audit, payment: payment, notification: notification, auditProducer: auditProducer, notificationProducer: notificationProducer, portalURL: portalURL, kycKey: kycAdminKey}
 }
 
 // respondSuccess sends 200 with common ApiResponse envelope (status success, responseCode 01).
@@ -192,6 +193,12 @@ func (c *AdminController) CreateAdmin(ctx *gin.Context) {
 	}
 }
 
+// JWKS GET /.well-known/jwks.json (public) — public keys admin tokens are signed with.
+func (c *AdminController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, auth.JWKS())
+}
+
 // GetMe GET /me (current admin)
 func (c *AdminController) GetMe(ctx *gin.Context) {
 	claims, ok := auth.ClaimsFrom(ctx)
@@ -494,6 +501,150 @@ func (c *AdminController) SetUserRestricted(ctx *gin.Context) {
 	respondSuccess(ctx, "ok", map[string]interface{}{"restricted": body.Restricted})
 }
 
+// ListUserSessions GET /users/:id/sessions (admin JWT) — devices the user is logged in on, via user gRPC.
+func (c *AdminController) ListUserSessions(ctx *gin.Context) {
+	if c.user == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "user service unavailable")
+		return
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		respondError(ctx, http.StatusBadRequest, "02", "user id required")
+		return
+	}
+	resp, err := c.user.ListUserSessions(ctx.Request.Context(), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	respondSuccess(ctx, "ok", resp.Sessions)
+}
+
+// RevokeUserSession DELETE /users/:id/sessions/:session_id (admin JWT) — log the user out of one device.
+func (c *AdminController) RevokeUserSession(ctx *gin.Context) {
+	c.revokeUserSessions(ctx, ctx.Param("session_id"))
+}
+
+// RevokeAllUserSessions POST /users/:id/sessions/revoke-all (admin JWT) — log the user out of every device.
+func (c *AdminController) RevokeAllUserSessions(ctx *gin.Context) {
+	c.revokeUserSessions(ctx, "")
+}
+
+func (c *AdminController) revokeUserSessions(ctx *gin.Context, sessionID string) {
+	claims, _ := auth.ClaimsFrom(ctx)
+	adminID := ""
+	if claims != nil {
+		adminID = claims.AdminID
+	}
+	if c.user == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "user service unavailable")
+		return
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		respondError(ctx, http.StatusBadRequest, "02", "user id required")
+		return
+	}
+	resp, err := c.user.RevokeUserSessions(ctx.Request.Context(), id, sessionID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		if resp.Message == "user not found" || resp.Message == "session not found" {
+			respondError(ctx, http.StatusNotFound, "02", resp.Message)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, "02", resp.Message)
+		return
+	}
+	if c.auditProducer != nil {
+		_ = c.auditProducer.SendAudit("admin_user_sessions_revoked", "user", id, adminID, map[string]interface{}{"user_id": id, "session_id": sessionID})
+	}
+	respondSuccess(ctx, "ok", map[string]interface{}{"user_id": id, "session_id": sessionID})
+}
+
+// UnlockUser POST /users/:id/unlock (admin JWT) — lift lockouts from failed login, 2FA or PIN attempts before they expire.
+func (c *AdminController) UnlockUser(ctx *gin.Context) {
+	claims, _ := auth.ClaimsFrom(ctx)
+	adminID := ""
+	if claims != nil {
+		adminID = claims.AdminID
+	}
+	if c.user == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "user service unavailable")
+		return
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		respondError(ctx, http.StatusBadRequest, "02", "user id required")
+		return
+	}
+	resp, err := c.user.UnlockUser(ctx.Request.Context(), id)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		if resp.Message == "user not found" {
+			respondError(ctx, http.StatusNotFound, "02", resp.Message)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, "02", resp.Message)
+		return
+	}
+	if c.auditProducer != nil {
+		_ = c.auditProducer.SendAudit("admin_user_unlocked", "user", id, adminID, map[string]interface{}{"user_id": id, "was_locked": resp.WasLocked})
+	}
+	respondSuccess(ctx, "ok", map[string]interface{}{"user_id": id, "was_locked": resp.WasLocked})
+}
+
+// ResetUser2FARequest is the body for POST /users/:id/2fa/reset.
+type ResetUser2FARequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// ResetUser2FA POST /users/:id/2fa/reset (admin JWT) — turn off 2FA for a user who lost their authenticator and recovery
+// codes, after identity checks. The user is signed out everywhere and enrols again after logging in.
+func (c *AdminController) ResetUser2FA(ctx *gin.Context) {
+	claims, _ := auth.ClaimsFrom(ctx)
+	adminID := ""
+	if claims != nil {
+		adminID = claims.AdminID
+	}
+	if c.user == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "user service unavailable")
+		return
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		respondError(ctx, http.StatusBadRequest, "02", "user id required")
+		return
+	}
+	var req ResetUser2FARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		respondError(ctx, http.StatusBadRequest, "02", "reason is required (5-500 characters)")
+		return
+	}
+	resp, err := c.user.ResetTwoFactor(ctx.Request.Context(), id, adminID, req.Reason)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		if resp.Message == "user not found" {
+			respondError(ctx, http.StatusNotFound, "02", resp.Message)
+			return
+		}
+		respondError(ctx, http.StatusBadRequest, "02", resp.Message)
+		return
+	}
+	if c.auditProducer != nil {
+		_ = c.auditProducer.SendAudit("admin_2fa_reset", "user", id, adminID, map[string]interface{}{"user_id": id, "reason": req.Reason})
+	}
+	respondSuccess(ctx, "ok", map[string]interface{}{"user_id": id})
+}
+
 // CreateUserWallet POST /users/:id/wallet (admin JWT) — create 9PSB wallet for user. Calls payment service gRPC; payment fetches KYC via gRPC, calls 9PSB, saves wallet and emits audit + success email via Kafka.
 func (c *AdminController) CreateUserWallet(ctx *gin.Context) {
 	claims, _ := auth.ClaimsFrom(ctx)
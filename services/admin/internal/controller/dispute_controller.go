@@ -0,0 +1,272 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"github.com/abubakvr/payup-backend/services/admin/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ListDisputes GET /disputes (admin JWT) — transaction dispute queue via payment gRPC, most urgent SLA first. Query: status, limit, offset.
+func (c *AdminController) ListDisputes(ctx *gin.Context) {
+	if c.payment == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "payment service unavailable")
+		return
+	}
+	limit, offset := int32(50), int32(0)
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = int32(n)
+			if limit > 100 {
+				limit = 100
+			}
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			offset = int32(n)
+		}
+	}
+	status := strings.ToUpper(strings.TrimSpace(ctx.Query("status")))
+	resp, err := c.payment.ListDisputes(ctx.Request.Context(), status, limit, offset)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	disputes := make([]map[string]interface{}, 0, len(resp.Disputes))
+	for _, d := range resp.Disputes {
+		disputes = append(disputes, disputeMap(d))
+	}
+	respondSuccess(ctx, "ok", gin.H{"disputes": disputes, "total": resp.Total, "limit": limit, "offset": offset})
+}
+
+// GetDispute GET /disputes/:id (admin JWT) — one dispute with all notes (including internal) and attachments.
+func (c *AdminController) GetDispute(ctx *gin.Context) {
+	if c.payment == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "payment service unavailable")
+		return
+	}
+	resp, err := c.payment.GetDispute(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if resp == nil || !resp.Found {
+		respondError(ctx, http.StatusNotFound, "02", "dispute not found")
+		return
+	}
+	data := disputeMap(resp.Item)
+	notes := make([]map[string]interface{}, 0, len(resp.Notes))
+	for _, n := range resp.Notes {
+		notes = append(notes, disputeNoteMap(n))
+	}
+	attachments := make([]map[string]interface{}, 0, len(resp.Attachments))
+	for _, a := range resp.Attachments {
+		attachments = append(attachments, disputeAttachmentMap(a))
+	}
+	data["notes"] = notes
+	data["attachments"] = attachments
+	respondSuccess(ctx, "ok", data)
+}
+
+// UpdateDisputeStatus PUT /disputes/:id/status (admin JWT) — move a dispute to UNDER_REVIEW, AWAITING_USER or REJECTED.
+// Body: { "status": "...", "note": "..." } (note shown to the user; required for REJECTED).
+func (c *AdminController) UpdateDisputeStatus(ctx *gin.Context) {
+	adminID := adminIDFrom(ctx)
+	if c.payment == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "payment service unavailable")
+		return
+	}
+	var body struct {
+		Status string `json:"status" binding:"required,oneof=UNDER_REVIEW AWAITING_USER REJECTED"`
+		Note   string `json:"note"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		respondError(ctx, http.StatusBadRequest, "02", "invalid body: status (UNDER_REVIEW, AWAITING_USER or REJECTED) required")
+		return
+	}
+	resp, err := c.payment.UpdateDisputeStatus(ctx.Request.Context(), ctx.Param("id"), body.Status, body.Note, adminID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		respondDisputeFailure(ctx, resp.ErrorMessage)
+		return
+	}
+	respondSuccess(ctx, "dispute status updated", disputeMap(resp.Item))
+}
+
+// AddDisputeNote POST /disputes/:id/notes (admin JWT). Body: { "body": "...", "internal": true|false }.
+func (c *AdminController) AddDisputeNote(ctx *gin.Context) {
+	adminID := adminIDFrom(ctx)
+	if c.payment == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "payment service unavailable")
+		return
+	}
+	var body struct {
+		Body     string `json:"body" binding:"required,max=2000"`
+		Internal bool   `json:"internal"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		respondError(ctx, http.StatusBadRequest, "02", "invalid body: body (max 2000 chars) required")
+		return
+	}
+	resp, err := c.payment.AddDisputeNote(ctx.Request.Context(), ctx.Param("id"), body.Body, body.Internal, adminID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		respondDisputeFailure(ctx, resp.ErrorMessage)
+		return
+	}
+	respondCreated(ctx, "note added", disputeNoteMap(resp.Note))
+}
+
+// AddDisputeAttachment POST /disputes/:id/attachments (admin JWT). Body: { "file_name", "content_type", "url" (https) }.
+func (c *AdminController) AddDisputeAttachment(ctx *gin.Context) {
+	adminID := adminIDFrom(ctx)
+	if c.payment == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "payment service unavailable")
+		return
+	}
+	var body struct {
+		FileName    string `json:"file_name" binding:"required,max=255"`
+		ContentType string `json:"content_type" binding:"required,max=100"`
+		URL         string `json:"url" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		respondError(ctx, http.StatusBadRequest, "02", "invalid body: file_name, content_type and url required")
+		return
+	}
+	resp, err := c.payment.AddDisputeAttachment(ctx.Request.Context(), ctx.Param("id"), body.FileName, body.ContentType, body.URL, adminID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		respondDisputeFailure(ctx, resp.ErrorMessage)
+		return
+	}
+	respondCreated(ctx, "attachment added", disputeAttachmentMap(resp.Attachment))
+}
+
+// ResolveDispute POST /disputes/:id/resolve (admin JWT) — close a dispute as RESOLVED.
+// Body: { "resolution": "REVERSAL"|"ADJUSTMENT"|"NO_ACTION", "amount": n, "type": "credit"|"debit", "note": "..." }.
+// REVERSAL refunds the original amount plus fee; amount and type apply to ADJUSTMENT only.
+func (c *AdminController) ResolveDispute(ctx *gin.Context) {
+	adminID := adminIDFrom(ctx)
+	if c.payment == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "payment service unavailable")
+		return
+	}
+	var body struct {
+		Resolution string  `json:"resolution" binding:"required,oneof=REVERSAL ADJUSTMENT NO_ACTION"`
+		Amount     float64 `json:"amount"`
+		Type       string  `json:"type"`
+		Note       string  `json:"note"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		respondError(ctx, http.StatusBadRequest, "02", "invalid body: resolution (REVERSAL, ADJUSTMENT or NO_ACTION) required")
+		return
+	}
+	if body.Resolution == "ADJUSTMENT" && (body.Amount <= 0 || (body.Type != "credit" && body.Type != "debit")) {
+		respondError(ctx, http.StatusBadRequest, "02", "ADJUSTMENT requires amount (positive number) and type (credit or debit)")
+		return
+	}
+	resp, err := c.payment.ResolveDispute(ctx.Request.Context(), ctx.Param("id"), body.Resolution, body.Amount, body.Type == "credit", body.Note, adminID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		respondDisputeFailure(ctx, resp.ErrorMessage)
+		return
+	}
+	respondSuccess(ctx, "dispute resolved", disputeMap(resp.Item))
+}
+
+func adminIDFrom(ctx *gin.Context) string {
+	if claims, _ := auth.ClaimsFrom(ctx); claims != nil {
+		return claims.AdminID
+	}
+	return ""
+}
+
+func respondDisputeFailure(ctx *gin.Context, msg string) {
+	if msg == "" {
+		msg = "dispute update failed"
+	}
+	switch {
+	case strings.Contains(msg, "not found"):
+		respondError(ctx, http.StatusNotFound, "02", msg)
+	case strings.Contains(msg, "already closed"), strings.Contains(msg, "status has changed"):
+		respondError(ctx, http.StatusConflict, "04", msg)
+	default:
+		respondError(ctx, http.StatusBadRequest, "02", msg)
+	}
+}
+
+func disputeMap(d *paymentpb.DisputeItem) map[string]interface{} {
+	if d == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"id":                    d.Id,
+		"dispute_ref":           d.DisputeRef,
+		"transaction_ref":       d.TransactionRef,
+		"transaction_amount":    d.TransactionAmount,
+		"transaction_status":    d.TransactionStatus,
+		"transaction_direction": d.TransactionDirection,
+		"wallet_id":             d.WalletId,
+		"user_id":               d.UserId,
+		"reason":                d.Reason,
+		"description":           d.Description,
+		"status":                d.Status,
+		"assigned_to":           d.AssignedTo,
+		"sla_due_at":            d.SlaDueAt,
+		"sla_breached":          d.SlaBreached,
+		"first_response_at":     d.FirstResponseAt,
+		"resolution":            d.Resolution,
+		"resolution_amount":     d.ResolutionAmount,
+		"resolution_txn_ref":    d.ResolutionTxnRef,
+		"resolution_note":       d.ResolutionNote,
+		"resolved_by":           d.ResolvedBy,
+		"resolved_at":           d.ResolvedAt,
+		"created_at":            d.CreatedAt,
+		"updated_at":            d.UpdatedAt,
+	}
+}
+
+func disputeNoteMap(n *paymentpb.DisputeNote) map[string]interface{} {
+	if n == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"id":          n.Id,
+		"author_type": n.AuthorType,
+		"author_id":   n.AuthorId,
+		"body":        n.Body,
+		"internal":    n.IsInternal,
+		"created_at":  n.CreatedAt,
+	}
+}
+
+func disputeAttachmentMap(a *paymentpb.DisputeAttachment) map[string]interface{} {
+	if a == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"id":               a.Id,
+		"uploaded_by_type": a.UploadedByType,
+		"uploaded_by":      a.UploadedBy,
+		"file_name":        a.FileName,
+		"content_type":     a.ContentType,
+		"url":              a.Url,
+		"created_at":       a.CreatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	notificationpb "github.com/abubakvr/payup-backend/proto/notification"
+	"github.com/gin-gonic/gin"
+)
+
+// ListUserNotifications GET /users/:id/notifications (admin JWT) — notifications sent to a user, newest first, with
+// delivery status. Query: type, channel, status, limit, offset.
+func (c *AdminController) ListUserNotifications(ctx *gin.Context) {
+	c.listNotifications(ctx, &notificationpb.ListNotificationsRequest{UserId: ctx.Param("id")})
+}
+
+// SearchNotifications GET /notifications?recipient=... (admin JWT) — notifications sent to an email address or phone
+// number, for customers who ask about a message before support knows their account. Matched by hash; the full
+// address is never stored. Query: recipient (required), type, channel, status, limit, offset.
+func (c *AdminController) SearchNotifications(ctx *gin.Context) {
+	recipient := strings.TrimSpace(ctx.Query("recipient"))
+	if recipient == "" {
+		respondError(ctx, http.StatusBadRequest, "02", "recipient (email or phone number) is required")
+		return
+	}
+	c.listNotifications(ctx, &notificationpb.ListNotificationsRequest{Recipient: recipient})
+}
+
+func (c *AdminController) listNotifications(ctx *gin.Context, req *notificationpb.ListNotificationsRequest) {
+	if c.notification == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "notification service unavailable")
+		return
+	}
+	req.Limit, req.Offset = 50, 0
+	if l := ctx.Query("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			req.Limit = int32(n)
+			if req.Limit > 200 {
+				req.Limit = 200
+			}
+		}
+	}
+	if o := ctx.Query("offset"); o != "" {
+		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
+			req.Offset = int32(n)
+		}
+	}
+	req.Type = strings.TrimSpace(ctx.Query("type"))
+	req.Channel = strings.ToLower(strings.TrimSpace(ctx.Query("channel")))
+	req.Status = strings.ToLower(strings.TrimSpace(ctx.Query("status")))
+	resp, err := c.notification.ListNotifications(ctx.Request.Context(), req)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	items := make([]map[string]interface{}, 0, len(resp.Notifications))
+	for _, n := range resp.Notifications {
+		items = append(items, notificationMap(n))
+	}
+	respondSuccess(ctx, "ok", gin.H{"notifications": items, "total": resp.Total, "limit": req.Limit, "offset": req.Offset})
+}
+
+// GetNotification GET /notifications/:id (admin JWT) — one notification with its delivery status and every channel
+// tried.
+func (c *AdminController) GetNotification(ctx *gin.Context) {
+	if c.notification == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "notification service unavailable")
+		return
+	}
+	resp, err := c.notification.GetNotification(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if resp == nil || !resp.Found {
+		respondError(ctx, http.StatusNotFound, "02", "notification not found")
+		return
+	}
+	respondSuccess(ctx, "ok", notificationMap(resp.Item))
+}
+
+// ResendNotification POST /notifications/:id/resend (admin JWT) — send a notification again, to the user's current
+// address, as a new notification linked to the original. One-time codes cannot be resent; the user requests a new one.
+func (c *AdminController) ResendNotification(ctx *gin.Context) {
+	adminID := adminIDFrom(ctx)
+	if c.notification == nil {
+		respondError(ctx, http.StatusServiceUnavailable, "99", "notification service unavailable")
+		return
+	}
+	id := ctx.Param("id")
+	resp, err := c.notification.ResendNotification(ctx.Request.Context(), id, adminID)
+	if err != nil {
+		respondError(ctx, http.StatusInternalServerError, "99", err.Error())
+		return
+	}
+	if !resp.Success {
+		msg := resp.ErrorMessage
+		if msg == "" {
+			msg = "resend failed"
+		}
+		status := http.StatusBadRequest
+		if strings.Contains(msg, "not found") {
+			status = http.StatusNotFound
+		}
+		respondError(ctx, status, "02", msg)
+		return
+	}
+	item := notificationMap(resp.Item)
+	if c.auditProducer != nil {
+		_ = c.auditProducer.SendAudit("admin_notification_resent", "notification", id, adminID, map[string]interface{}{
+			"user_id":         resp.Item.GetUserId(),
+			"type":            resp.Item.GetType(),
+			"resend_id":       resp.Item.GetId(),
+			"delivery_status": resp.Item.GetStatus(),
+		})
+	}
+	respondSuccess(ctx, "notification resent", item)
+}
+
+func notificationMap(n *notificationpb.NotificationItem) map[string]interface{} {
+	if n == nil {
+		return map[string]interface{}{}
+	}
+	route := make([]map[string]interface{}, 0, len(n.Route))
+	for _, h := range n.Route {
+		route = append(route, map[string]interface{}{
+			"channel":  h.Channel,
+			"provider": h.Provider,
+			"status":   h.Status,
+			"error":    h.Error,
+			"at":       h.At,
+		})
+	}
+	return map[string]interface{}{
+		"id":           n.Id,
+		"user_id":      n.UserId,
+		"type":         n.Type,
+		"channel":      n.Channel,
+		"sent_channel": n.SentChannel,
+		"provider":     n.Provider,
+		"status":       n.Status,
+		"attempts":     n.Attempts,
+		"last_error":   n.LastError,
+		"subject":      n.Subject,
+		"recipient":    n.RecipientMasked,
+		"resendable":   n.Resendable,
+		"resent_from":  n.ResentFrom,
+		"resent_by":    n.ResentBy,
+		"created_at":   n.CreatedAt,
+		"updated_at":   n.UpdatedAt,
+		"sent_at":      n.SentAt,
+		"delivered_at": n.DeliveredAt,
+		"route":        route,
+	}
+}
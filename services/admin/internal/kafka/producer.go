@@ -3,7 +3,6 @@ package kafka
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
 	"time"
 
@@ -80,10 +79,12 @@ func strPtr(s string) *string {
 }
 
 // NotificationEvent is the payload for notification-events topic (consumed by notification service).
+// Data carries the template variables for types the notification service renders itself.
 type NotificationEvent struct {
 	Type     string                 `json:"type"`
 	Channel  string                 `json:"channel"`
 	Metadata map[string]interface{} `json:"metadata"`
+	Data     map[string]interface{} `json:"data,omitempty"`
 }
 
 // NotificationProducer writes to the notification-events topic (email, sms, etc.).
@@ -120,36 +121,16 @@ func (p *NotificationProducer) SendAdminWelcomeEmail(email, firstName, lastName,
 	if toName == "" {
 		toName = email
 	}
-	subject := "Your Admin Portal Login Details"
-	body := fmt.Sprintf("Hello %s,\n\nYour admin account has been created. Please use the following details to log in:\n\nEmail: %s\nTemporary password: %s\n\nYou must change your password immediately after your first login.\n\n", toName, email, temporaryPassword)
-	if portalURL != "" {
-		body += fmt.Sprintf("Log in here: %s\n\n", portalURL)
-	}
-	body += "If you did not expect this email, please contact your administrator."
-
-	html := fmt.Sprintf(`<p>Hello %s,</p>
-<p>Your admin account has been created. Please use the following details to log in:</p>
-<ul>
-<li><strong>Email:</strong> %s</li>
-<li><strong>Temporary password:</strong> %s</li>
-</ul>
-<p>You <strong>must change your password immediately</strong> after your first login.</p>
-`, escapeHTML(toName), escapeHTML(email), escapeHTML(temporaryPassword))
-	if portalURL != "" {
-		html += fmt.Sprintf(`<p><a href="%s">Log in here</a></p>
-`, portalURL)
-	}
-	html += `<p>If you did not expect this email, please contact your administrator.</p>`
-
+	// The notification service renders the admin_welcome template; admins have no account to look the address up in
 	ev := NotificationEvent{
-		Type:    "admin_welcome",
-		Channel: "email",
-		Metadata: map[string]interface{}{
-			"to":       email,
-			"to_name":  toName,
-			"subject":  subject,
-			"body":     body,
-			"html":     html,
+		Type:     "admin_welcome",
+		Channel:  "email",
+		Metadata: map[string]interface{}{"to": email, "to_name": toName},
+		Data: map[string]interface{}{
+			"name":               toName,
+			"email":              email,
+			"temporary_password": temporaryPassword,
+			"portal_url":         portalURL,
 		},
 	}
 	payload, err := json.Marshal(ev)
@@ -166,22 +147,3 @@ func (p *NotificationProducer) SendAdminWelcomeEmail(email, firstName, lastName,
 	log.Printf("admin: welcome email queued for %s", email)
 	return nil
 }
-
-func escapeHTML(s string) string {
-	var out []rune
-	for _, r := range s {
-		switch r {
-		case '<':
-			out = append(out, '&', 'l', 't', ';')
-		case '>':
-			out = append(out, '&', 'g', 't', ';')
-		case '&':
-			out = append(out, '&', 'a', 'm', 'p', ';')
-		case '"':
-			out = append(out, '&', 'q', 'u', 'o', 't', ';')
-		default:
-			out = append(out, r)
-		}
-	}
-	return string(out)
-}
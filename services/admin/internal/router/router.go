@@ -16,6 +16,7 @@ func Setup(ctrl *controller.AdminController) *gin.Engine {
 	})
 
 	r.POST("/auth/login", ctrl.Login)
+	r.GET("/.well-known/jwks.json", ctrl.JWKS)
 
 	protected := r.Group("")
 	protected.Use(middleware.RequireAdmin())
@@ -29,6 +30,11 @@ func Setup(ctrl *controller.AdminController) *gin.Engine {
 		protected.GET("/users/:id", ctrl.GetUser)
 		protected.GET("/wallets", ctrl.ListWallets)
 		protected.POST("/users/:id/restrict", ctrl.SetUserRestricted)
+		protected.GET("/users/:id/sessions", ctrl.ListUserSessions)
+		protected.DELETE("/users/:id/sessions/:session_id", ctrl.RevokeUserSession)
+		protected.POST("/users/:id/sessions/revoke-all", ctrl.RevokeAllUserSessions)
+		protected.POST("/users/:id/unlock", ctrl.UnlockUser)
+		protected.POST("/users/:id/2fa/reset", ctrl.ResetUser2FA)
 		protected.POST("/users/:id/wallet", ctrl.CreateUserWallet)
 		protected.POST("/users/:id/wallet/adjust", ctrl.AdjustUserWallet)
 		protected.PUT("/users/:id/wallet/status", ctrl.ChangeUserWalletStatus)
@@ -44,6 +50,18 @@ func Setup(ctrl *controller.AdminController) *gin.Engine {
 		protected.PUT("/users/:id/kyc/steps/:step/rejection-message", ctrl.SetStepRejectionMessage)
 		protected.GET("/kyc-list", ctrl.ListKYC)
 		protected.GET("/audits", ctrl.ListAudits)
+		// Transaction disputes (payment gRPC): queue, notes, attachments, status changes and resolution
+		protected.GET("/disputes", ctrl.ListDisputes)
+		protected.GET("/disputes/:id", ctrl.GetDispute)
+		protected.PUT("/disputes/:id/status", ctrl.UpdateDisputeStatus)
+		protected.POST("/disputes/:id/notes", ctrl.AddDisputeNote)
+		protected.POST("/disputes/:id/attachments", ctrl.AddDisputeAttachment)
+		protected.POST("/disputes/:id/resolve", ctrl.ResolveDispute)
+		// Notification history (notification gRPC): by user or recipient, delivery status and resend
+		protected.GET("/users/:id/notifications", ctrl.ListUserNotifications)
+		protected.GET("/notifications", ctrl.SearchNotifications)
+		protected.GET("/notifications/:id", ctrl.GetNotification)
+		protected.POST("/notifications/:id/resend", ctrl.ResendNotification)
 	}
 
 	return r
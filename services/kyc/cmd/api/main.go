@@ -7,7 +7,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/abubakvr/payup-backend/pkg/gateway"
 	kycpb "github.com/abubakvr/payup-backend/proto/kyc"
+	"github.com/abubakvr/payup-backend/services/kyc/internal/auth"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/clients"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/config"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/controller"
@@ -19,6 +21,7 @@ import (
 	"github.com/abubakvr/payup-backend/services/kyc/internal/service"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/storage"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/worker"
+	"github.com/redis/go-redis/v9"
 	grpclib "google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
@@ -44,6 +47,7 @@ func main() {
 	}
 	auditProducer := kafka.NewAuditProducer(brokers)
 	notifier := kafka.NewNotificationProducer(brokers)
+	kycEvents := kafka.NewKYCEventsProducer(brokers)
 	dojahConfig := dojah.DefaultConfig()
 	s3Cfg := storage.LoadS3ConfigFromEnv()
 	var selfieUploader service.SelfieUploader
@@ -66,13 +70,29 @@ func main() {
 		log.Printf("Upload worker pool started: workers=%d queue=%d", cfg.UploadWorkers, cfg.UploadQueueSize)
 	}
 
+	// Redis backs phone OTP attempt limiting; without REDIS_ADDR the limiter allows every attempt.
+	var rdb redis.UniversalClient
+	if cfg.RedisAddr != "" {
+		rdb = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword})
+		defer rdb.Close()
+	} else {
+		log.Printf("WARN: REDIS_ADDR not set; phone OTP attempt limiting disabled")
+	}
+
 	repo := repository.NewKYCRepository(db, cfg.EncryptionKey)
-	svc := service.NewKYCService(repo, userClient, auditProducer, notifier, dojahConfig, selfieUploader, uploadPool)
+	svc := service.NewKYCService(repo, userClient, auditProducer, notifier, kycEvents, dojahConfig, selfieUploader, uploadPool, rdb)
 	ctrl := controller.NewKYCController(svc)
 
-	r := router.SetupRouter(cfg, ctrl)
+	r := router.SetupRouter(cfg, ctrl, auth.Middleware(rdb))
+	if err := gateway.TrustProxies(r, cfg.TrustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 
-	consumer := kafka.NewConsumer(brokers)
+	consumer := kafka.NewConsumer(brokers, func(ctx context.Context, userID, phone string) {
+		if err := svc.SyncPhoneFromUser(userID, phone); err != nil {
+			log.Printf("kyc: sync phone after user_contact_changed user=%s: %v", userID, err)
+		}
+	})
 	go consumer.Start()
 
 	walletConsumer := kafka.NewWalletEventsConsumer(brokers, func(ctx context.Context, userID string) {
@@ -2,35 +2,34 @@ package auth
 
 import (
 	"os"
+	"time"
 
-	"github.com/abubakvr/payup-backend/services/kyc/internal/utils"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/pkg/jwtauth"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+// verifier checks user tokens against the user service JWKS (USER_JWKS_URL).
+var verifier = jwtauth.NewUserVerifier(userJWKSURL())
 
-type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.RegisteredClaims
+func userJWKSURL() string {
+	if u := os.Getenv("USER_JWKS_URL"); u != "" {
+		return u
+	}
+	return "http://user-service:8001/.well-known/jwks.json"
 }
 
-// DecodeJWTFromContext reads Authorization header, validates JWT, returns claims (user_id). Used for KYC endpoints.
-func DecodeJWTFromContext(getHeader func(string) string) (*Claims, error) {
-	token, ok := utils.ExtractBearerToken(getHeader("Authorization"))
-	if !ok {
-		return nil, utils.ErrMissingOrInvalidBearer
-	}
-	parsed, err := jwt.ParseWithClaims(token, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+// Middleware authenticates KYC user routes. rdb is the Redis shared with the user service, which writes the
+// revocation deny-list; nil skips revocation checks.
+func Middleware(rdb redis.UniversalClient) gin.HandlerFunc {
+	a := authn.NewAuthenticator(verifier, authn.NewDenyList(rdb, time.Hour))
+	return a.Middleware(func(ctx *gin.Context, err error) {
+		ctx.AbortWithStatus(401)
 	})
-	if err != nil {
-		return nil, err
-	}
-	claims, ok := parsed.Claims.(*Claims)
-	if !ok || !parsed.Valid {
-		return nil, jwt.ErrSignatureInvalid
-	}
-	return claims, nil
+}
+
+// DecodeJWTFromContext returns the claims (user_id) stored by Middleware. Used for KYC endpoints.
+func DecodeJWTFromContext(ctx *gin.Context) (*authn.Claims, error) {
+	return authn.ClaimsFrom(ctx)
 }
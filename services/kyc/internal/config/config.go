@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/abubakvr/payup-backend/pkg/gateway"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -13,12 +14,14 @@ type Config struct {
 	Port                string
 	DatabaseURL         string
 	EncryptionKey       string // 32-byte hex for AES-256 (KYC_ENCRYPTION_KEY)
-	JWTSecret           string // same as user service for auth_request
 	UserServiceGrpcAddr string // user service gRPC address for GetUserForKYC (e.g. user-service:9001)
 	KafkaBroker         string // comma-separated brokers for audit-events and notification-events
 	UploadWorkers       int    // number of workers for image uploads (0 = synchronous, no pool)
 	UploadQueueSize     int    // size of upload job queue when using workers
 	AdminAPIKey         string // If set, admin routes require X-Admin-Key header
+	RedisAddr           string // REDIS_ADDR; phone OTP attempt limiting is disabled when empty
+	RedisPassword       string
+	TrustedProxies      []string // TRUSTED_PROXIES: addresses/CIDRs whose X-Real-IP is believed; empty = private ranges
 	// Dojah BVN: set DOJAH_APP_ID, DOJAH_AUTHORIZATION_KEY; optional BVN_SELFIE_MIN_CONFIDENCE (default 70)
 }
 
@@ -71,12 +74,14 @@ func LoadConfig() *Config {
 		Port:                port,
 		DatabaseURL:         dbURL,
 		EncryptionKey:       encKey,
-		JWTSecret:           os.Getenv("JWT_SECRET"),
 		UserServiceGrpcAddr: userGrpc,
 		KafkaBroker:         kafkaBroker,
 		UploadWorkers:       uploadWorkers,
 		UploadQueueSize:     uploadQueueSize,
 		AdminAPIKey:         adminKey,
+		RedisAddr:           os.Getenv("REDIS_ADDR"),
+		RedisPassword:       os.Getenv("REDIS_PASSWORD"),
+		TrustedProxies:      gateway.ParseProxies(os.Getenv("TRUSTED_PROXIES")),
 	}
 }
 
@@ -3,8 +3,10 @@ package controller
 import (
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/abubakvr/payup-backend/services/kyc/internal/auth"
+	"github.com/abubakvr/payup-backend/pkg/attempts"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/common/response"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/dto"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/repository"
@@ -22,7 +24,7 @@ func NewKYCController(svc *service.KYCService) *KYCController {
 }
 
 func (c *KYCController) withUserID(ctx *gin.Context) (userID string, ok bool) {
-	claims, err := auth.DecodeJWTFromContext(ctx.GetHeader)
+	claims, err := auth.DecodeJWTFromContext(ctx)
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return "", false
@@ -40,6 +42,13 @@ func (c *KYCController) handleKYCError(ctx *gin.Context, err error) bool {
 		})
 		return true
 	}
+	if le, locked := attempts.IsLocked(err); locked {
+		ctx.Header("Retry-After", strconv.Itoa(int(le.RetryAfter.Seconds())))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": response.ValidationError,
+		})
+		return true
+	}
 	if err == service.ErrUserNotFound {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"status": "error", "message": "User not found", "responseCode": response.ValidationError,
@@ -8,7 +8,7 @@ import (
 	"github.com/abubakvr/payup-backend/services/kyc/internal/service"
 )
 
-// KYCAdminServer implements kycpb.KYCServiceServer for Admin service (GetFullKYCForAdmin). GetKYCStatus returns a stub.
+// KYCAdminServer implements kycpb.KYCServiceServer for Admin (GetFullKYCForAdmin) and Payment (GetKYCStatus, wallet KYC).
 type KYCAdminServer struct {
 	kycpb.UnimplementedKYCServiceServer
 	svc *service.KYCService
@@ -18,9 +18,19 @@ func NewKYCAdminServer(svc *service.KYCService) *KYCAdminServer {
 	return &KYCAdminServer{svc: svc}
 }
 
+// GetKYCStatus returns the user's KYC overall status and level. Payment uses the level to cap wallet tier limits.
 func (s *KYCAdminServer) GetKYCStatus(ctx context.Context, req *kycpb.GetKYCStatusRequest) (*kycpb.GetKYCStatusResponse, error) {
-	// Status can be derived from flow if needed; stub for admin gRPC.
-	return &kycpb.GetKYCStatusResponse{Status: "pending"}, nil
+	if req == nil || req.UserId == "" {
+		return &kycpb.GetKYCStatusResponse{Status: "not_started"}, nil
+	}
+	p, err := s.svc.GetKYCProfile(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return &kycpb.GetKYCStatusResponse{Status: "not_started"}, nil
+	}
+	return &kycpb.GetKYCStatusResponse{Found: true, Status: p.OverallStatus, KycLevel: int32(p.KYCLevel)}, nil
 }
 
 func (s *KYCAdminServer) GetFullKYCForAdmin(ctx context.Context, req *kycpb.GetFullKYCForAdminRequest) (*kycpb.GetFullKYCForAdminResponse, error) {
@@ -129,3 +139,34 @@ func (s *KYCAdminServer) ApproveKYC(ctx context.Context, req *kycpb.ApproveKYCRe
 	}
 	return &kycpb.ApproveKYCResponse{Success: true}, nil
 }
+
+// ExportUserData returns the user's KYC records as JSON for a data-subject access request (User service).
+func (s *KYCAdminServer) ExportUserData(ctx context.Context, req *kycpb.ExportUserDataRequest) (*kycpb.ExportUserDataResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &kycpb.ExportUserDataResponse{Found: false}, nil
+	}
+	data, err := s.svc.ExportUserData(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &kycpb.ExportUserDataResponse{Found: false}, nil
+	}
+	jsonPayload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &kycpb.ExportUserDataResponse{Found: true, JsonPayload: string(jsonPayload)}, nil
+}
+
+// EraseUserData deletes or retains the user's KYC records for account deletion (User service).
+func (s *KYCAdminServer) EraseUserData(ctx context.Context, req *kycpb.EraseUserDataRequest) (*kycpb.EraseUserDataResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &kycpb.EraseUserDataResponse{}, nil
+	}
+	res, err := s.svc.EraseUserData(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &kycpb.EraseUserDataResponse{Erased: res.Erased, Retained: res.Retained}, nil
+}
@@ -2,23 +2,37 @@ package kafka
 
 import (
 	"context"
-
+	"encoding/json"
 	"log"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// UserContactChangedMessage is published by the user service on user-events when a user's email or phone number
+// changes. Email and PhoneNumber are the values after the change.
+type UserContactChangedMessage struct {
+	EventType   string `json:"event_type"` // user_contact_changed
+	UserID      string `json:"user_id"`
+	Field       string `json:"field"` // email or phone
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
 type Consumer struct {
-	reader *kafka.Reader
+	reader         *kafka.Reader
+	onPhoneChanged func(ctx context.Context, userID, phone string)
 }
 
-func NewConsumer(brokers []string) *Consumer {
+// NewConsumer creates a consumer for user-events. onPhoneChanged is called when a user changes their phone number
+// (e.g. update the KYC phone step); nil only logs events.
+func NewConsumer(brokers []string, onPhoneChanged func(ctx context.Context, userID, phone string)) *Consumer {
 	return &Consumer{
 		reader: kafka.NewReader(kafka.ReaderConfig{
 			Brokers: brokers,
 			Topic:   "user-events",
 			GroupID: "kyc-service",
 		}),
+		onPhoneChanged: onPhoneChanged,
 	}
 }
 
@@ -31,6 +45,14 @@ func (c *Consumer) Start() {
 			continue
 		}
 
-		log.Printf("KYC received user event: %s\n", string(msg.Value))
+		var ev UserContactChangedMessage
+		if err := json.Unmarshal(msg.Value, &ev); err != nil || ev.EventType != "user_contact_changed" {
+			log.Printf("KYC received user event: %s\n", string(msg.Value))
+			continue
+		}
+		log.Printf("KYC received user_contact_changed user=%s field=%s", ev.UserID, ev.Field)
+		if ev.Field == "phone" && ev.UserID != "" && ev.PhoneNumber != "" && c.onPhoneChanged != nil {
+			c.onPhoneChanged(context.Background(), ev.UserID, ev.PhoneNumber)
+		}
 	}
 }
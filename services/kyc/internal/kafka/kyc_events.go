@@ -0,0 +1,50 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const kycEventsTopic = "kyc-events"
+
+// KYCApprovedEvent is published to kyc-events when an admin approves a user's KYC. User service consumes it for
+// referral rewards.
+type KYCApprovedEvent struct {
+	EventType string `json:"event_type"` // "kyc_approved"
+	UserID    string `json:"user_id"`
+}
+
+// KYCEventsProducer writes KYC domain events to the kyc-events topic.
+type KYCEventsProducer struct {
+	writer *kafka.Writer
+}
+
+// NewKYCEventsProducer creates a producer for kyc-events.
+func NewKYCEventsProducer(brokers []string) *KYCEventsProducer {
+	if len(brokers) == 0 {
+		return nil
+	}
+	return &KYCEventsProducer{
+		writer: kafka.NewWriter(kafka.WriterConfig{
+			Brokers: brokers,
+			Topic:   kycEventsTopic,
+		}),
+	}
+}
+
+// PublishKYCApproved sends a kyc_approved event. Safe to call with nil producer (no-op).
+func (p *KYCEventsProducer) PublishKYCApproved(ctx context.Context, userID string) error {
+	if p == nil || p.writer == nil {
+		return nil
+	}
+	payload, err := json.Marshal(KYCApprovedEvent{EventType: "kyc_approved", UserID: userID})
+	if err != nil {
+		return err
+	}
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return p.writer.WriteMessages(cctx, kafka.Message{Value: payload})
+}
@@ -11,10 +11,14 @@ import (
 const notificationTopic = "notification-events"
 
 // NotificationEvent matches the payload consumed by the notification service.
+// UserID (optional) lets the notification service apply the user's preferences and keep an inbox copy. Data replaces
+// Metadata for types the notification service has a template for; it then looks up the address and language itself.
 type NotificationEvent struct {
+	UserID   string                 `json:"user_id,omitempty"`
 	Type     string                 `json:"type"`
 	Channel  string                 `json:"channel"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
 }
 
 // NotificationProducer writes to the notification-events topic (SMS, email, etc.).
@@ -87,6 +87,12 @@ func (r *KYCRepository) SetKYCLevel(ctx context.Context, userID string, level in
 	return err
 }
 
+// DeleteProfile deletes a KYC profile; every step table cascades from kyc_profile. Used by account deletion.
+func (r *KYCRepository) DeleteProfile(ctx context.Context, profileID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM kyc_profile WHERE id = $1`, profileID)
+	return err
+}
+
 // SetOverallStatusByUserID sets overall_status for the user's KYC profile (e.g. "approved"). Used by admin approve action.
 func (r *KYCRepository) SetOverallStatusByUserID(ctx context.Context, userID string, status string) error {
 	query := `UPDATE kyc_profile SET overall_status = $1, updated_at = $2 WHERE user_id = $3`
@@ -376,6 +382,20 @@ func (r *KYCRepository) UpsertPhone(profileID string, phoneEnc []byte, verificat
 	return err
 }
 
+// ReplacePhone swaps the number on an existing phone step and drops any code sent to the old one. A verified step
+// stays verified, with verified_at moved to now. Returns false if the profile has no phone step yet.
+func (r *KYCRepository) ReplacePhone(profileID string, phoneEnc []byte) (bool, error) {
+	now := time.Now()
+	res, err := r.db.Exec(`UPDATE kyc_phone SET phone = $2, otp_code = NULL, otp_expires_at = NULL, updated_at = $3,
+		verified_at = CASE WHEN verification_status = $4 THEN $3 ELSE verified_at END
+		WHERE kyc_profile_id = $1`, profileID, phoneEnc, now, model.StatusVerified)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
 func (r *KYCRepository) SetPhoneOTP(profileID string, code string, expiresAt time.Time) error {
 	_, err := r.db.Exec(`UPDATE kyc_phone SET otp_code = $2, otp_expires_at = $3, updated_at = $3 WHERE kyc_profile_id = $1`,
 		profileID, code, expiresAt)
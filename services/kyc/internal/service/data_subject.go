@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abubakvr/payup-backend/services/kyc/internal/dto"
+)
+
+// ErasureResult reports what EraseUserData did with the user's KYC records.
+type ErasureResult struct {
+	Erased   []string
+	Retained []string
+}
+
+// ExportUserData returns the user's KYC records for a data-subject access request, in the same shape admins see.
+// Returns nil if KYC was never started.
+func (s *KYCService) ExportUserData(userID string) (*dto.AdminKYCResponse, error) {
+	data, err := s.GetFullKYCByUserID(userID)
+	if errors.Is(err, ErrKYCNotStarted) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// EraseUserData handles account deletion. Once a wallet was opened (kyc_level >= 1) the identity records are customer
+// due diligence data that AML/CFT rules require us to keep for years after the relationship ends, so they are retained.
+// Otherwise the profile, its steps and the uploaded images are deleted.
+func (s *KYCService) EraseUserData(ctx context.Context, userID string) (*ErasureResult, error) {
+	p, err := s.repo.GetProfileByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return &ErasureResult{}, nil
+	}
+	if p.KYCLevel >= 1 {
+		s.sendAudit("kyc_erasure_retained", "kyc_profile", p.ID, userID, map[string]interface{}{"reason": "aml_retention", "kyc_level": p.KYCLevel})
+		return &ErasureResult{Retained: []string{"kyc"}}, nil
+	}
+	var urls []string
+	if bvn, _ := s.repo.GetBVNByProfileID(p.ID); bvn != nil {
+		urls = append(urls, bvn.ImageURL)
+	}
+	if id, _ := s.repo.GetIdentityByProfileID(p.ID); id != nil {
+		urls = append(urls, id.IDFrontURL, id.IDBackURL, id.CustomerImageURL, id.SignatureURL)
+	}
+	if av, _ := s.repo.GetAddressVerificationByProfileID(p.ID); av != nil {
+		urls = append(urls, av.UtilityBillURL, av.StreetImageURL)
+	}
+	if err := s.repo.DeleteProfile(ctx, p.ID); err != nil {
+		return nil, err
+	}
+	if s.selfieUploader != nil {
+		for _, u := range urls {
+			_ = s.selfieUploader.DeleteObject(ctx, u)
+		}
+	}
+	s.sendAudit("kyc_erased", "kyc_profile", p.ID, userID, map[string]interface{}{"images": len(urls)})
+	return &ErasureResult{Erased: []string{"kyc"}}, nil
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/attempts"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/clients"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/dojah"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/dto"
@@ -20,6 +21,7 @@ import (
 	"github.com/abubakvr/payup-backend/services/kyc/internal/repository"
 	"github.com/abubakvr/payup-backend/services/kyc/internal/worker"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
 var (
@@ -49,13 +51,22 @@ type KYCService struct {
 	userClient      *clients.UserClient
 	auditProducer   *kafka.AuditProducer
 	notifier        *kafka.NotificationProducer
+	events          *kafka.KYCEventsProducer
 	dojahConfig     dojah.Config
 	selfieUploader  SelfieUploader
 	uploadPool      *worker.Pool // optional: when set, image uploads run in worker pool
+	otpLimiter      *attempts.Limiter
 }
 
-func NewKYCService(repo *repository.KYCRepository, userClient *clients.UserClient, auditProducer *kafka.AuditProducer, notifier *kafka.NotificationProducer, dojahConfig dojah.Config, selfieUploader SelfieUploader, uploadPool *worker.Pool) *KYCService {
-	return &KYCService{repo: repo, userClient: userClient, auditProducer: auditProducer, notifier: notifier, dojahConfig: dojahConfig, selfieUploader: selfieUploader, uploadPool: uploadPool}
+func NewKYCService(repo *repository.KYCRepository, userClient *clients.UserClient, auditProducer *kafka.AuditProducer, notifier *kafka.NotificationProducer, events *kafka.KYCEventsProducer, dojahConfig dojah.Config, selfieUploader SelfieUploader, uploadPool *worker.Pool, rdb redis.UniversalClient) *KYCService {
+	// Phone OTP guesses per user: 5 per 15 minutes, then locked for 15m, 1h, 24h.
+	otpLimiter := attempts.NewLimiter(rdb, attempts.Policy{
+		Name:        "otp:kyc_phone",
+		MaxFailures: 5,
+		Window:      15 * time.Minute,
+		Lockouts:    []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
+	})
+	return &KYCService{repo: repo, userClient: userClient, auditProducer: auditProducer, notifier: notifier, events: events, dojahConfig: dojahConfig, selfieUploader: selfieUploader, uploadPool: uploadPool, otpLimiter: otpLimiter}
 }
 
 func (s *KYCService) sendAudit(action, entity, entityID, userID string, metadata map[string]interface{}) {
@@ -415,6 +426,7 @@ func (s *KYCService) VerifyBVN(userID string, req *dto.BVNVerifyRequest) (*dto.B
 		to := normalizePhoneForSMS(phone)
 		if to != "" && s.notifier != nil {
 			_ = s.notifier.Send(kafka.NotificationEvent{
+				UserID:  userID,
 				Type:    "kyc_phone_otp",
 				Channel: "whatsapp",
 				Metadata: map[string]interface{}{
@@ -660,6 +672,7 @@ func (s *KYCService) SendPhoneOTP(userID string, req *dto.PhoneSendOTPRequest) e
 	if s.notifier != nil && to != "" {
 		if channel == "sms" {
 			_ = s.notifier.Send(kafka.NotificationEvent{
+				UserID:  userID,
 				Type:    "kyc_phone_otp",
 				Channel: "sms",
 				Metadata: map[string]interface{}{
@@ -670,6 +683,7 @@ func (s *KYCService) SendPhoneOTP(userID string, req *dto.PhoneSendOTPRequest) e
 			})
 		} else {
 			_ = s.notifier.Send(kafka.NotificationEvent{
+				UserID:  userID,
 				Type:    "kyc_phone_otp",
 				Channel: "whatsapp",
 				Metadata: map[string]interface{}{
@@ -689,13 +703,24 @@ func (s *KYCService) VerifyPhoneOTP(userID string, req *dto.PhoneVerifyOTPReques
 	if err != nil || p == nil {
 		return err
 	}
+	ctx := context.Background()
+	if err := s.otpLimiter.Check(ctx, userID); err != nil {
+		return err
+	}
 	ok, err := s.repo.ValidateAndClearPhoneOTP(p.ID, req.Code)
 	if err != nil {
 		return err
 	}
 	if !ok {
+		if err := s.otpLimiter.Fail(ctx, userID); err != nil {
+			if le, locked := attempts.IsLocked(err); locked {
+				s.sendAudit("otp_locked", "kyc_profile", p.ID, userID, map[string]interface{}{"step": model.StepPhone, "locked_for_seconds": int(le.RetryAfter.Seconds())})
+			}
+			return err
+		}
 		return ErrInvalidOrExpiredOTP
 	}
+	s.otpLimiter.Succeed(ctx, userID)
 	if err := s.repo.SetPhoneVerified(p.ID, time.Now()); err != nil {
 		return err
 	}
@@ -705,6 +730,26 @@ func (s *KYCService) VerifyPhoneOTP(userID string, req *dto.PhoneVerifyOTPReques
 	return nil
 }
 
+// SyncPhoneFromUser puts a phone number changed in the user service on the phone step (user_contact_changed). The
+// user confirmed the new number with a code there, so a verified step stays verified. Users who have not reached the
+// phone step are skipped; BVN verification fills it in.
+func (s *KYCService) SyncPhoneFromUser(userID, phone string) error {
+	p, err := s.repo.GetProfileByUserID(userID)
+	if err != nil || p == nil {
+		return err
+	}
+	phoneEnc, err := s.repo.Encrypt(phone)
+	if err != nil {
+		return err
+	}
+	replaced, err := s.repo.ReplacePhone(p.ID, phoneEnc)
+	if err != nil || !replaced {
+		return err
+	}
+	s.sendAudit("phone_changed", "kyc_profile", p.ID, userID, map[string]interface{}{"step": model.StepPhone, "source": "user_service"})
+	return nil
+}
+
 // GetPhone returns phone verification status and masked number (GET /phone).
 func (s *KYCService) GetPhone(userID string) (*dto.PhoneResponse, error) {
 	p, err := s.getProfile(userID)
@@ -1428,6 +1473,11 @@ func (s *KYCService) GetFullKYCByUserID(userID string) (*dto.AdminKYCResponse, e
 	return out, nil
 }
 
+// GetKYCProfile returns the user's KYC profile (level and overall status), or nil if KYC was never started.
+func (s *KYCService) GetKYCProfile(userID string) (*model.KYCProfile, error) {
+	return s.repo.GetProfileByUserID(userID)
+}
+
 // CountProfiles returns the number of KYC profiles, optionally filtered by status and/or kyc_level (for admin kyc-list total).
 func (s *KYCService) CountProfiles(status string, kycLevel *int32) (int64, error) {
 	return s.repo.CountProfiles(status, kycLevel)
@@ -1445,26 +1495,10 @@ func (s *KYCService) ApproveKYC(ctx context.Context, userID string) (success boo
 	if err := s.repo.SetOverallStatusByUserID(ctx, userID, "approved"); err != nil {
 		return false, "failed to update KYC status"
 	}
-	email := ""
-	if s.userClient != nil {
-		if u, _ := s.userClient.GetUserForKYC(ctx, userID); u != nil && u.Found {
-			email = u.Email
-		}
-	}
-	if email != "" && s.notifier != nil {
-		subject := "Your KYC has been approved"
-		html := `<p>Congratulations! Your KYC verification has been successfully approved.</p>` +
-			`<p>You now have full access to your PayUp account and wallet.</p>` +
-			`<p>Thank you for completing the verification process.</p>`
-		_ = s.notifier.Send(kafka.NotificationEvent{
-			Type:    "kyc_approved",
-			Channel: "email",
-			Metadata: map[string]interface{}{
-				"to":      email,
-				"subject": subject,
-				"html":    html,
-			},
-		})
+	_ = s.events.PublishKYCApproved(ctx, userID)
+	if s.notifier != nil {
+		// Copy, address and language come from the notification service's kyc_approved template
+		_ = s.notifier.Send(kafka.NotificationEvent{UserID: userID, Type: "kyc_approved", Channel: "email"})
 	}
 	return true, ""
 }
@@ -1848,6 +1882,7 @@ func (s *KYCService) sendKYCRejectedEmail(ctx context.Context, userID, profileID
 	htmlParts = append(htmlParts, "</ul><p>Please log in and complete the steps above, then submit again.</p>")
 	html := strings.Join(htmlParts, "")
 	_ = s.notifier.Send(kafka.NotificationEvent{
+		UserID:  userID,
 		Type:    "kyc_rejected",
 		Channel: "email",
 		Metadata: map[string]interface{}{
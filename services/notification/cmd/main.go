@@ -1,22 +1,51 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	notificationpb "github.com/abubakvr/payup-backend/proto/notification"
+	"github.com/abubakvr/payup-backend/services/notification/internal/clients"
 	"github.com/abubakvr/payup-backend/services/notification/internal/config"
 	"github.com/abubakvr/payup-backend/services/notification/internal/controller"
+	notificationgrpc "github.com/abubakvr/payup-backend/services/notification/internal/grpc"
 	"github.com/abubakvr/payup-backend/services/notification/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/apns"
 	"github.com/abubakvr/payup-backend/services/notification/internal/providers/brevo"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/fcm"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
 	"github.com/abubakvr/payup-backend/services/notification/internal/providers/termii"
 	"github.com/abubakvr/payup-backend/services/notification/internal/providers/whatsapp"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
 	"github.com/abubakvr/payup-backend/services/notification/internal/router"
+	"github.com/abubakvr/payup-backend/services/notification/internal/routing"
 	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
+	"github.com/abubakvr/payup-backend/services/notification/internal/webhooks"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
+// minRecipientHashKeyLen is the shortest NOTIFICATION_RECIPIENT_HASH_KEY accepted.
+const minRecipientHashKeyLen = 32
+
 func main() {
 	cfg := config.LoadConfig()
+	// Unkeyed recipient hashes of phone numbers and emails can be reversed by brute force, so there is no fallback.
+	if len(cfg.RecipientHashKey) < minRecipientHashKeyLen {
+		log.Fatalf("notification: NOTIFICATION_RECIPIENT_HASH_KEY must be at least %d characters (e.g. openssl rand -hex 32)", minRecipientHashKeyLen)
+	}
+
+	db, err := config.OpenDB(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
 
 	var brevoClient *brevo.Client
 	if cfg.BrevoAPIKey != "" {
@@ -42,18 +71,135 @@ func main() {
 		log.Printf("notification: WhatsApp not configured")
 	}
 
-	svc := service.NewNotificationService(brevoClient, termiiClient, whatsappClient, cfg.WhatsAppOTPTemplateName)
-	log.Printf("notification: Kafka broker=%s topic=notification-events", cfg.KafkaBroker)
-	ctrl := controller.NewController()
-	r := router.SetupRouter(ctrl)
+	pushCfg := service.PushConfig{Providers: make(map[string]push.Provider)}
+	if cfg.FCMCredentialsFile != "" {
+		sa, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			log.Fatalf("notification: read FCM credentials: %v", err)
+		}
+		fcmClient, err := fcm.NewClient(sa, cfg.FCMBaseURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pushCfg.Providers[push.PlatformAndroid] = fcmClient
+		log.Printf("notification: FCM configured project=%s", fcmClient.ProjectID)
+	} else {
+		log.Printf("notification: FCM not configured")
+	}
+	if cfg.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNsKeyFile)
+		if err != nil {
+			log.Fatalf("notification: read APNs key: %v", err)
+		}
+		apnsClient, err := apns.NewClient(cfg.APNsKeyID, cfg.APNsTeamID, cfg.APNsTopic, key, cfg.APNsBaseURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pushCfg.Providers[push.PlatformIOS] = apnsClient
+		log.Printf("notification: APNs configured topic=%s url=%s", cfg.APNsTopic, cfg.APNsBaseURL)
+	} else {
+		log.Printf("notification: APNs not configured")
+	}
+	// The user service owns push tokens and the contact details and language templated notifications are sent with.
+	userClient, err := clients.NewUserClient(cfg.UserServiceGRPCAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer userClient.Close()
+	pushCfg.Tokens = userClient
+
+	templateStore := repository.NewTemplateRepository(db)
+	registry, err := templates.NewRegistry(templateStore)
+	if err != nil {
+		log.Fatal(err)
+	}
+	templateCfg := service.TemplateConfig{Registry: registry, Store: templateStore, Recipients: userClient}
 
 	brokers := []string{cfg.KafkaBroker}
 	if b := os.Getenv("KAFKA_BROKER"); b != "" {
 		brokers = []string{b}
 	}
-	consumer := kafka.NewConsumer(brokers, svc)
+	dlq := kafka.NewDeadLetterQueue(brokers)
+	defer dlq.Close()
+	deliveryCfg := service.DeliveryConfig{
+		Log:          repository.NewDeliveryRepository(db),
+		DeadLetters:  dlq,
+		RecipientKey: []byte(cfg.RecipientHashKey),
+	}
+
+	policies := routing.DefaultPolicies()
+	if cfg.Routes != "" {
+		custom, err := routing.ParsePolicies(cfg.Routes)
+		if err != nil {
+			log.Fatalf("notification: NOTIFICATION_ROUTES: %v", err)
+		}
+		policies = policies.Merge(custom)
+	}
+	routingCfg := service.RoutingConfig{Policies: policies, Breakers: routing.NewBreakers(cfg.BreakerThreshold, cfg.BreakerCooldown)}
+	log.Printf("notification: %d failover policies, breaker threshold=%d cooldown=%s", len(policies), cfg.BreakerThreshold, cfg.BreakerCooldown)
+
+	// Partner webhooks need the encryption key for endpoint secrets; without it the API answers 503 and nothing is queued.
+	var hooksCfg service.WebhookConfig
+	if cfg.EncryptionKey != "" {
+		hooksCfg = service.WebhookConfig{
+			Store:        repository.NewWebhookRepository(db, cfg.EncryptionKey),
+			Client:       webhooks.NewClient(cfg.PartnerWebhookTimeout, cfg.PartnerWebhookAllowInsecure),
+			MaxAttempts:  cfg.PartnerWebhookMaxAttempts,
+			MaxEndpoints: cfg.PartnerWebhookMaxEndpoints,
+			AllowHTTP:    cfg.PartnerWebhookAllowInsecure,
+			AllowPrivate: cfg.PartnerWebhookAllowInsecure,
+		}
+		log.Printf("notification: partner webhooks enabled max_attempts=%d max_endpoints=%d timeout=%s", cfg.PartnerWebhookMaxAttempts, cfg.PartnerWebhookMaxEndpoints, cfg.PartnerWebhookTimeout)
+	} else {
+		log.Printf("notification: NOTIFICATION_ENCRYPTION_KEY empty; partner webhooks disabled")
+	}
+
+	svc := service.NewNotificationService(brevoClient, termiiClient, whatsappClient, cfg.WhatsAppOTPTemplateName,
+		repository.NewInboxRepository(db), repository.NewPreferenceRepository(db), pushCfg, templateCfg, deliveryCfg, routingCfg, hooksCfg)
+	log.Printf("notification: Kafka broker=%s topic=notification-events max_attempts=%d", cfg.KafkaBroker, cfg.MaxAttempts)
+	ctrl := controller.NewController(svc, cfg)
+	r := router.SetupRouter(ctrl)
+
+	// gRPC server for Admin service (notification history, delivery status, resend)
+	grpcPort := "9005"
+	if p := strings.TrimSpace(os.Getenv("NOTIFICATION_GRPC_PORT")); p != "" {
+		grpcPort = p
+	}
+	go func() {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Printf("notification gRPC listen: %v", err)
+			return
+		}
+		srv := grpclib.NewServer()
+		notificationpb.RegisterNotificationServiceForAdminServer(srv, notificationgrpc.NewAdminServer(svc))
+		reflection.Register(srv)
+		log.Printf("Notification gRPC listening on port %s", grpcPort)
+		if err := srv.Serve(lis); err != nil {
+			log.Printf("notification gRPC serve: %v", err)
+		}
+	}()
+
+	consumer := kafka.NewConsumer(brokers, svc, dlq, cfg.MaxAttempts)
 	go consumer.Start()
 
+	if hooksCfg.Store != nil {
+		go kafka.NewPartnerEventConsumer(brokers, svc).Start()
+
+		// Post due partner webhook deliveries; retries are scheduled in the database, so they survive restarts.
+		go func() {
+			ticker := time.NewTicker(5 * time.Second)
+			defer ticker.Stop()
+			for range ticker.C {
+				if n, err := svc.DispatchWebhooks(context.Background()); err != nil {
+					log.Printf("partner webhooks dispatch: %v", err)
+				} else if n > 0 {
+					log.Printf("partner webhooks dispatch: attempted %d deliveries", n)
+				}
+			}
+		}()
+	}
+
 	log.Printf("Notification service running on port %s", cfg.Port)
 	if err := http.ListenAndServe(":"+cfg.Port, r); err != nil {
 		log.Fatal(err)
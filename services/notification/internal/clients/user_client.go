@@ -0,0 +1,74 @@
+package clients
+
+import (
+	"context"
+
+	userpb "github.com/abubakvr/payup-backend/proto/user"
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// UserClient calls the user service gRPC for push tokens (list for fan-out, remove after provider feedback) and for
+// the recipient details templated notifications are addressed and localized with.
+type UserClient struct {
+	client userpb.UserServiceForNotificationClient
+	conn   *grpc.ClientConn
+}
+
+// NewUserClient dials the user service and returns a client. Call Close when done.
+func NewUserClient(addr string) (*UserClient, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &UserClient{client: userpb.NewUserServiceForNotificationClient(conn), conn: conn}, nil
+}
+
+func (c *UserClient) Close() error {
+	if c != nil && c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// ListPushTokens returns the devices the user registered for push.
+func (c *UserClient) ListPushTokens(ctx context.Context, userID string) ([]push.Device, error) {
+	resp, err := c.client.ListPushTokens(ctx, &userpb.ListPushTokensRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]push.Device, 0, len(resp.GetTokens()))
+	for _, t := range resp.GetTokens() {
+		out = append(out, push.Device{Token: t.GetToken(), Platform: t.GetPlatform()})
+	}
+	return out, nil
+}
+
+// RemovePushTokens deletes tokens the provider reported as invalid.
+func (c *UserClient) RemovePushTokens(ctx context.Context, tokens []string, reason string) error {
+	_, err := c.client.RemovePushTokens(ctx, &userpb.RemovePushTokensRequest{Tokens: tokens, Reason: reason})
+	return err
+}
+
+// GetNotificationRecipient returns the user's email, phone, name and language, or nil when the user does not exist.
+func (c *UserClient) GetNotificationRecipient(ctx context.Context, userID string) (*model.Recipient, error) {
+	resp, err := c.client.GetNotificationRecipient(ctx, &userpb.GetNotificationRecipientRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.GetFound() {
+		return nil, nil
+	}
+	return &model.Recipient{
+		Email:     resp.GetEmail(),
+		Phone:     resp.GetPhoneNumber(),
+		FirstName: resp.GetFirstName(),
+		LastName:  resp.GetLastName(),
+		Language:  resp.GetLanguage(),
+	}, nil
+}
@@ -1,12 +1,65 @@
 package config
 
-import "os"
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
 
 type Config struct {
 	Port string
 
+	// Postgres: the in-app inbox and notification preferences.
+	DatabaseURL string
+
+	// Redis for the access-token deny-list (REDIS_ADDR). Optional; if empty, revoked tokens are not checked.
+	RedisAddr     string
+	RedisPassword string
+
+	// User service JWKS used to verify Bearer tokens on the inbox and preference routes.
+	JWKSURL string
+
+	// If set, the template admin routes require a matching X-Admin-Key header; if empty they are disabled.
+	AdminAPIKey string
+
 	// Kafka
 	KafkaBroker string
+	// Attempts at one event (with backoff) before it is parked on notification-events-dlq; default 5.
+	MaxAttempts int
+
+	// Channel failover. Routes overrides or adds per-type policies (NOTIFICATION_ROUTES, e.g.
+	// "kyc_phone_otp=whatsapp,sms@30s,email@30s"); a provider's breaker opens after BreakerThreshold consecutive
+	// failures and stays open for BreakerCooldown.
+	Routes           string
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	// Delivery receipt webhooks. A webhook whose secret is empty rejects every request.
+	// Brevo: sent as "Authorization: Bearer <token>" (configure the webhook with bearer auth).
+	BrevoWebhookToken string
+	// Termii: X-Termii-Signature, hex HMAC-SHA512 of the body.
+	TermiiWebhookSecret string
+	// WhatsApp: X-Hub-Signature-256 signed with the Meta app secret; the verify token answers the subscription check.
+	WhatsAppAppSecret          string
+	WhatsAppWebhookVerifyToken string
+
+	// Key for the recipient hashes in the delivery log (NOTIFICATION_RECIPIENT_HASH_KEY). Changing it means history
+	// recorded earlier can no longer be searched by recipient.
+	RecipientHashKey string
+
+	// Partner webhooks. Endpoint signing secrets are encrypted with EncryptionKey (NOTIFICATION_ENCRYPTION_KEY, 64 hex
+	// chars); without it partner webhooks are off. A delivery is attempted up to PartnerWebhookMaxAttempts times with
+	// exponential backoff; each POST times out after PartnerWebhookTimeout. PartnerWebhookAllowInsecure accepts http and
+	// internal URLs, for local development only.
+	EncryptionKey               string
+	PartnerWebhookMaxAttempts   int
+	PartnerWebhookMaxEndpoints  int
+	PartnerWebhookTimeout       time.Duration
+	PartnerWebhookAllowInsecure bool
 
 	// Brevo (email)
 	BrevoAPIKey string
@@ -18,6 +71,19 @@ type Config struct {
 	TermiiSenderID string
 	TermiiBaseURL  string
 
+	// Push: device tokens come from the user service gRPC.
+	UserServiceGRPCAddr string
+	// FCM HTTP v1 (Android): path to the Firebase service account key file. FCMBaseURL overrides the endpoint.
+	FCMCredentialsFile string
+	FCMBaseURL         string
+	// APNs (iOS): .p8 signing key file, its key ID, the team ID and the app bundle ID (topic). APNsBaseURL defaults to
+	// production, or sandbox when APNS_ENVIRONMENT=sandbox.
+	APNsKeyFile string
+	APNsKeyID   string
+	APNsTeamID  string
+	APNsTopic   string
+	APNsBaseURL string
+
 	// WhatsApp Business Cloud API
 	WhatsAppToken           string
 	WhatsAppPhoneID         string
@@ -30,10 +96,54 @@ func LoadConfig() *Config {
 	if port == "" {
 		port = "8004"
 	}
+	dbURL := os.Getenv("NOTIFICATION_DATABASE_URL")
+	if dbURL == "" {
+		dbURL = fmt.Sprintf(
+			"postgres://%s:%s@%s:%s/%s?sslmode=%s",
+			os.Getenv("NOTIFICATION_DB_USER"),
+			os.Getenv("NOTIFICATION_DB_PASSWORD"),
+			os.Getenv("NOTIFICATION_DB_HOST"),
+			os.Getenv("NOTIFICATION_DB_PORT"),
+			os.Getenv("NOTIFICATION_DB_NAME"),
+			os.Getenv("NOTIFICATION_DB_SSLMODE"),
+		)
+	}
+	jwksURL := os.Getenv("USER_JWKS_URL")
+	if jwksURL == "" {
+		jwksURL = "http://user-service:8001/.well-known/jwks.json"
+	}
+	adminKey := os.Getenv("ADMIN_API_KEY")
+	if adminKey == "" {
+		adminKey = os.Getenv("NOTIFICATION_ADMIN_API_KEY")
+	}
 	kafkaBroker := os.Getenv("KAFKA_BROKER")
 	if kafkaBroker == "" {
 		kafkaBroker = "redpanda:9092"
 	}
+	maxAttempts, err := strconv.Atoi(os.Getenv("NOTIFICATION_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 5
+	}
+	breakerThreshold, err := strconv.Atoi(os.Getenv("NOTIFICATION_BREAKER_THRESHOLD"))
+	if err != nil || breakerThreshold < 1 {
+		breakerThreshold = 5
+	}
+	breakerCooldown, err := time.ParseDuration(os.Getenv("NOTIFICATION_BREAKER_COOLDOWN"))
+	if err != nil || breakerCooldown <= 0 {
+		breakerCooldown = 30 * time.Second
+	}
+	hookAttempts, err := strconv.Atoi(os.Getenv("PARTNER_WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || hookAttempts < 1 {
+		hookAttempts = 10
+	}
+	hookEndpoints, err := strconv.Atoi(os.Getenv("PARTNER_WEBHOOK_MAX_ENDPOINTS"))
+	if err != nil || hookEndpoints < 1 {
+		hookEndpoints = 5
+	}
+	hookTimeout, err := time.ParseDuration(os.Getenv("PARTNER_WEBHOOK_TIMEOUT"))
+	if err != nil || hookTimeout <= 0 {
+		hookTimeout = 10 * time.Second
+	}
 	brevoSender := os.Getenv("BREVO_SENDER_EMAIL")
 	if brevoSender == "" {
 		brevoSender = "noreply@example.com"
@@ -46,6 +156,17 @@ func LoadConfig() *Config {
 	if termiiBase == "" {
 		termiiBase = "https://api.termii.com"
 	}
+	userGRPC := os.Getenv("USER_SERVICE_GRPC_ADDR")
+	if userGRPC == "" {
+		userGRPC = "user-service:9001"
+	}
+	apnsBase := os.Getenv("APNS_BASE_URL")
+	if apnsBase == "" {
+		apnsBase = "https://api.push.apple.com"
+		if os.Getenv("APNS_ENVIRONMENT") == "sandbox" {
+			apnsBase = "https://api.sandbox.push.apple.com"
+		}
+	}
 	waVersion := os.Getenv("WHATSAPP_API_VERSION")
 	if waVersion == "" {
 		waVersion = "v25.0"
@@ -56,17 +177,49 @@ func LoadConfig() *Config {
 	}
 
 	return &Config{
-		Port:                    port,
-		KafkaBroker:             kafkaBroker,
-		BrevoAPIKey:             os.Getenv("BREVO_API_KEY"),
-		BrevoSenderEmail:        brevoSender,
-		BrevoSenderName:         brevoName,
-		TermiiAPIKey:            os.Getenv("TERMII_API_KEY"),
-		TermiiSenderID:          os.Getenv("TERMII_SENDER_ID"),
-		TermiiBaseURL:           termiiBase,
-		WhatsAppToken:           os.Getenv("WHATSAPP_ACCESS_TOKEN"),
-		WhatsAppPhoneID:         os.Getenv("WHATSAPP_PHONE_NUMBER_ID"),
-		WhatsAppAPIVersion:      waVersion,
-		WhatsAppOTPTemplateName: waOTPTemplate,
+		Port:                        port,
+		DatabaseURL:                 dbURL,
+		RedisAddr:                   os.Getenv("REDIS_ADDR"),
+		RedisPassword:               os.Getenv("REDIS_PASSWORD"),
+		JWKSURL:                     jwksURL,
+		AdminAPIKey:                 adminKey,
+		KafkaBroker:                 kafkaBroker,
+		MaxAttempts:                 maxAttempts,
+		Routes:                      os.Getenv("NOTIFICATION_ROUTES"),
+		BreakerThreshold:            breakerThreshold,
+		BreakerCooldown:             breakerCooldown,
+		BrevoWebhookToken:           os.Getenv("BREVO_WEBHOOK_TOKEN"),
+		TermiiWebhookSecret:         os.Getenv("TERMII_WEBHOOK_SECRET"),
+		WhatsAppAppSecret:           os.Getenv("WHATSAPP_APP_SECRET"),
+		WhatsAppWebhookVerifyToken:  os.Getenv("WHATSAPP_WEBHOOK_VERIFY_TOKEN"),
+		RecipientHashKey:            os.Getenv("NOTIFICATION_RECIPIENT_HASH_KEY"),
+		EncryptionKey:               os.Getenv("NOTIFICATION_ENCRYPTION_KEY"),
+		PartnerWebhookMaxAttempts:   hookAttempts,
+		PartnerWebhookMaxEndpoints:  hookEndpoints,
+		PartnerWebhookTimeout:       hookTimeout,
+		PartnerWebhookAllowInsecure: os.Getenv("PARTNER_WEBHOOK_ALLOW_INSECURE") == "true",
+		BrevoAPIKey:                 os.Getenv("BREVO_API_KEY"),
+		BrevoSenderEmail:            brevoSender,
+		BrevoSenderName:             brevoName,
+		TermiiAPIKey:                os.Getenv("TERMII_API_KEY"),
+		TermiiSenderID:              os.Getenv("TERMII_SENDER_ID"),
+		TermiiBaseURL:               termiiBase,
+		UserServiceGRPCAddr:         userGRPC,
+		FCMCredentialsFile:          os.Getenv("FCM_CREDENTIALS_FILE"),
+		FCMBaseURL:                  os.Getenv("FCM_BASE_URL"),
+		APNsKeyFile:                 os.Getenv("APNS_KEY_FILE"),
+		APNsKeyID:                   os.Getenv("APNS_KEY_ID"),
+		APNsTeamID:                  os.Getenv("APNS_TEAM_ID"),
+		APNsTopic:                   os.Getenv("APNS_TOPIC"),
+		APNsBaseURL:                 apnsBase,
+		WhatsAppToken:               os.Getenv("WHATSAPP_ACCESS_TOKEN"),
+		WhatsAppPhoneID:             os.Getenv("WHATSAPP_PHONE_NUMBER_ID"),
+		WhatsAppAPIVersion:          waVersion,
+		WhatsAppOTPTemplateName:     waOTPTemplate,
 	}
 }
+
+// OpenDB opens a Postgres connection. Caller must close when done.
+func OpenDB(cfg *Config) (*sql.DB, error) {
+	return sql.Open("pgx", cfg.DatabaseURL)
+}
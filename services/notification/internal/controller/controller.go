@@ -1,17 +1,68 @@
 package controller
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/pkg/jwtauth"
+	"github.com/abubakvr/payup-backend/services/notification/internal/config"
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 // Controller holds notification HTTP handlers.
-type Controller struct{}
+type Controller struct {
+	svc      *service.NotificationService
+	authn    *authn.Authenticator
+	adminKey string
+	webhooks webhookSecrets
+}
+
+// NewController returns a new controller. Bearer tokens are verified against the user service JWKS and the shared
+// Redis deny-list.
+func NewController(svc *service.NotificationService, cfg *config.Config) *Controller {
+	var rdb redis.UniversalClient
+	if cfg.RedisAddr != "" {
+		rdb = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword})
+	}
+	tokens := authn.NewAuthenticator(jwtauth.NewUserVerifier(cfg.JWKSURL), authn.NewDenyList(rdb, time.Hour))
+	return &Controller{
+		svc:      svc,
+		authn:    tokens,
+		adminKey: cfg.AdminAPIKey,
+		webhooks: webhookSecrets{
+			brevoToken:          cfg.BrevoWebhookToken,
+			termiiSecret:        cfg.TermiiWebhookSecret,
+			whatsappAppSecret:   cfg.WhatsAppAppSecret,
+			whatsappVerifyToken: cfg.WhatsAppWebhookVerifyToken,
+		},
+	}
+}
+
+// RequireUser is the middleware for user routes: a valid, non-revoked access token.
+func (c *Controller) RequireUser() gin.HandlerFunc {
+	return c.authn.Middleware(func(ctx *gin.Context, err error) {
+		AbortUnauthorized(ctx, "invalid or missing token")
+	})
+}
 
-// NewController returns a new controller.
-func NewController() *Controller {
-	return &Controller{}
+// RequireAdminKey is the middleware for the admin routes: X-Admin-Key must match ADMIN_API_KEY. With no key
+// configured the routes are disabled.
+func (c *Controller) RequireAdminKey() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if c.adminKey == "" {
+			AbortUnauthorized(ctx, "admin API not configured")
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("X-Admin-Key")), []byte(c.adminKey)) != 1 {
+			AbortUnauthorized(ctx, "invalid or missing X-Admin-Key")
+			return
+		}
+		ctx.Next()
+	}
 }
 
 // Health returns 200 for liveness/readiness.
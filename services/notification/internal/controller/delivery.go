@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ListDeliveries returns the delivery log newest first. Query: user_id, type, channel, status, limit (default 50,
+// max 200), offset.
+func (c *Controller) ListDeliveries(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	offset, _ := strconv.Atoi(ctx.Query("offset"))
+	f := repository.DeliveryFilter{
+		UserID:  ctx.Query("user_id"),
+		Type:    ctx.Query("type"),
+		Channel: ctx.Query("channel"),
+		Status:  ctx.Query("status"),
+	}
+	list, total, err := c.svc.ListDeliveries(ctx.Request.Context(), f, limit, offset)
+	if err != nil {
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, deliveryJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"deliveries": out, "total": total})
+}
+
+// ReplayDeadLettersRequest is the optional JSON body for POST /admin/dlq/replay.
+type ReplayDeadLettersRequest struct {
+	Limit int `json:"limit"`
+}
+
+// ReplayDeadLetters puts parked events back on notification-events, oldest first (default 100, max 1000).
+func (c *Controller) ReplayDeadLetters(ctx *gin.Context) {
+	var body ReplayDeadLettersRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
+			return
+		}
+	}
+	n, err := c.svc.ReplayDeadLetters(ctx.Request.Context(), body.Limit)
+	if err != nil {
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusOK, "Dead letters replayed", CodeSuccess, gin.H{"replayed": n})
+}
+
+func deliveryJSON(d *repository.Delivery) gin.H {
+	var sentAt, deliveredAt interface{}
+	if d.SentAt != nil {
+		sentAt = d.SentAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		deliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+	route := d.Route
+	if route == nil {
+		route = []repository.RouteHop{}
+	}
+	return gin.H{
+		"id":                  d.ID,
+		"user_id":             d.UserID,
+		"type":                d.Type,
+		"channel":             d.Channel,
+		"sent_channel":        d.SentChannel,
+		"provider":            d.Provider,
+		"provider_message_id": d.ProviderMessageID,
+		"status":              d.Status,
+		"attempts":            d.Attempts,
+		"last_error":          d.LastError,
+		"subject":             d.Subject,
+		"recipient":           d.RecipientMasked,
+		"resent_from":         d.ResentFrom,
+		"resent_by":           d.ResentBy,
+		"created_at":          d.CreatedAt.Format(time.RFC3339),
+		"updated_at":          d.UpdatedAt.Format(time.RFC3339),
+		"sent_at":             sentAt,
+		"delivered_at":        deliveredAt,
+		"route":               route,
+	}
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotifications returns the user's inbox, newest first, with the unread count. Query: unread=true, limit, offset.
+func (c *Controller) ListNotifications(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	unreadOnly := ctx.Query("unread") == "true"
+	items, unread, err := c.svc.ListInbox(ctx.Request.Context(), claims.UserID, unreadOnly, limit, offset)
+	if err != nil {
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	list := make([]gin.H, 0, len(items))
+	for i := range items {
+		list = append(list, inboxJSON(&items[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"notifications": list, "unread_count": unread})
+}
+
+// MarkRead marks one notification read.
+func (c *Controller) MarkRead(ctx *gin.Context) {
+	c.setRead(ctx, true)
+}
+
+// MarkUnread marks one notification unread.
+func (c *Controller) MarkUnread(ctx *gin.Context) {
+	c.setRead(ctx, false)
+}
+
+func (c *Controller) setRead(ctx *gin.Context, read bool) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	if err := c.svc.SetRead(ctx.Request.Context(), claims.UserID, ctx.Param("id"), read); err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			Error(ctx, http.StatusNotFound, err.Error(), CodeNotFound)
+			return
+		}
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, nil)
+}
+
+// MarkAllRead marks every unread notification read.
+func (c *Controller) MarkAllRead(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	n, err := c.svc.MarkAllRead(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"updated": n})
+}
+
+// GetPreferences returns every notification type with the user's per-channel choices.
+func (c *Controller) GetPreferences(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	prefs, err := c.svc.GetPreferences(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"preferences": prefs})
+}
+
+// UpdatePreferencesRequest is the JSON body for PUT /notifications/preferences.
+type UpdatePreferencesRequest struct {
+	Preferences []struct {
+		Type    string `json:"type" binding:"required"`
+		Channel string `json:"channel" binding:"required"`
+		Enabled *bool  `json:"enabled" binding:"required"`
+	} `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// UpdatePreferences turns notification types on or off per channel. Critical types cannot be turned off.
+func (c *Controller) UpdatePreferences(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body UpdatePreferencesRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: preferences (type, channel, enabled) required", CodeBadRequest)
+		return
+	}
+	prefs := make([]repository.Preference, 0, len(body.Preferences))
+	for _, p := range body.Preferences {
+		prefs = append(prefs, repository.Preference{Type: p.Type, Channel: p.Channel, Enabled: *p.Enabled})
+	}
+	if err := c.svc.UpdatePreferences(ctx.Request.Context(), claims.UserID, prefs); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownNotificationType), errors.Is(err, service.ErrUnknownChannel):
+			Error(ctx, http.StatusBadRequest, err.Error(), CodeBadRequest)
+		case errors.Is(err, service.ErrCriticalNotification):
+			Error(ctx, http.StatusForbidden, err.Error(), CodeForbidden)
+		default:
+			Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		}
+		return
+	}
+	c.GetPreferences(ctx)
+}
+
+func inboxJSON(it *repository.InboxItem) gin.H {
+	var readAt interface{}
+	if it.ReadAt != nil {
+		readAt = it.ReadAt.Format(time.RFC3339)
+	}
+	return gin.H{
+		"id":         it.ID,
+		"type":       it.Type,
+		"category":   it.Category,
+		"title":      it.Title,
+		"body":       it.Body,
+		"data":       it.Data,
+		"read":       it.ReadAt != nil,
+		"read_at":    readAt,
+		"created_at": it.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/abubakvr/payup-backend/services/notification/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhookEndpointRequest is the JSON body for POST /notifications/webhooks.
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1"`
+}
+
+// UpdateWebhookEndpointRequest is the JSON body for PATCH /notifications/webhooks/:id; omitted fields are unchanged.
+type UpdateWebhookEndpointRequest struct {
+	URL         *string   `json:"url"`
+	Description *string   `json:"description"`
+	EventTypes  *[]string `json:"event_types"`
+	Active      *bool     `json:"active"`
+}
+
+// ListWebhookEndpoints returns the account's partner webhook endpoints and the event types they can subscribe to.
+func (c *Controller) ListWebhookEndpoints(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	list, err := c.svc.ListWebhookEndpoints(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, webhookEndpointJSON(&list[i], false))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"endpoints": out, "event_types": webhooks.EventTypes})
+}
+
+// CreateWebhookEndpoint registers an endpoint. The response carries the signing secret, which is not shown again.
+func (c *Controller) CreateWebhookEndpoint(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body CreateWebhookEndpointRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: url and event_types required", CodeBadRequest)
+		return
+	}
+	e, err := c.svc.CreateWebhookEndpoint(ctx.Request.Context(), claims.UserID, body.URL, body.Description, body.EventTypes)
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Webhook endpoint created", CodeSuccess, webhookEndpointJSON(e, true))
+}
+
+// GetWebhookEndpoint returns one endpoint.
+func (c *Controller) GetWebhookEndpoint(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	e, err := c.svc.GetWebhookEndpoint(ctx.Request.Context(), claims.UserID, ctx.Param("id"))
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, webhookEndpointJSON(e, false))
+}
+
+// UpdateWebhookEndpoint changes an endpoint's url, description, event_types or active flag.
+func (c *Controller) UpdateWebhookEndpoint(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body UpdateWebhookEndpointRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
+		return
+	}
+	e, err := c.svc.UpdateWebhookEndpoint(ctx.Request.Context(), claims.UserID, ctx.Param("id"), service.WebhookEndpointUpdate{
+		URL:         body.URL,
+		Description: body.Description,
+		EventTypes:  body.EventTypes,
+		Active:      body.Active,
+	})
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Webhook endpoint updated", CodeSuccess, webhookEndpointJSON(e, false))
+}
+
+// DeleteWebhookEndpoint removes an endpoint and its delivery log.
+func (c *Controller) DeleteWebhookEndpoint(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	if err := c.svc.DeleteWebhookEndpoint(ctx.Request.Context(), claims.UserID, ctx.Param("id")); err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Webhook endpoint deleted", CodeSuccess, nil)
+}
+
+// RotateWebhookSecret replaces an endpoint's signing secret and returns the new one.
+func (c *Controller) RotateWebhookSecret(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	e, err := c.svc.RotateWebhookSecret(ctx.Request.Context(), claims.UserID, ctx.Param("id"))
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Webhook secret rotated", CodeSuccess, webhookEndpointJSON(e, true))
+}
+
+// ListWebhookDeliveries returns an endpoint's deliveries, newest first. Query: status (pending, succeeded, failed),
+// limit (default 20, max 100), offset.
+func (c *Controller) ListWebhookDeliveries(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	list, total, err := c.svc.ListWebhookDeliveries(ctx.Request.Context(), claims.UserID, ctx.Param("id"), ctx.Query("status"), limit, offset)
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, webhookDeliveryJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"deliveries": out, "total": total})
+}
+
+// GetWebhookDelivery returns one delivery with its payload and every attempt: status code, error and the start of
+// the endpoint's response.
+func (c *Controller) GetWebhookDelivery(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	d, attempts, err := c.svc.GetWebhookDelivery(ctx.Request.Context(), claims.UserID, ctx.Param("delivery_id"))
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	out := webhookDeliveryJSON(d)
+	out["payload"] = json.RawMessage(d.Payload)
+	list := make([]gin.H, 0, len(attempts))
+	for _, a := range attempts {
+		list = append(list, gin.H{
+			"attempt":       a.Attempt,
+			"manual":        a.Manual,
+			"status_code":   a.StatusCode,
+			"error":         a.Error,
+			"response_body": a.ResponseBody,
+			"duration_ms":   a.DurationMS,
+			"created_at":    a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	out["attempts"] = list
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, out)
+}
+
+// RedeliverWebhook posts a delivery to its endpoint again now and returns the result.
+func (c *Controller) RedeliverWebhook(ctx *gin.Context) {
+	claims, err := authn.ClaimsFrom(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	d, err := c.svc.RedeliverWebhook(ctx.Request.Context(), claims.UserID, ctx.Param("delivery_id"))
+	if err != nil {
+		webhookError(ctx, err)
+		return
+	}
+	msg := "Redelivered"
+	if d.Status != repository.WebhookSucceeded {
+		msg = "Redelivery failed"
+	}
+	Success(ctx, http.StatusOK, msg, CodeSuccess, webhookDeliveryJSON(d))
+}
+
+func webhookError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound), errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		Error(ctx, http.StatusNotFound, err.Error(), CodeNotFound)
+	case errors.Is(err, service.ErrInvalidWebhook), errors.Is(err, service.ErrTooManyWebhooks):
+		Error(ctx, http.StatusBadRequest, err.Error(), CodeBadRequest)
+	case errors.Is(err, service.ErrWebhooksDisabled):
+		Error(ctx, http.StatusServiceUnavailable, err.Error(), CodeInternal)
+	default:
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+	}
+}
+
+func webhookEndpointJSON(e *repository.WebhookEndpoint, withSecret bool) gin.H {
+	out := gin.H{
+		"id":          e.ID,
+		"url":         e.URL,
+		"description": e.Description,
+		"event_types": e.EventTypes,
+		"active":      e.Active,
+		"created_at":  e.CreatedAt.Format(time.RFC3339),
+		"updated_at":  e.UpdatedAt.Format(time.RFC3339),
+	}
+	if withSecret {
+		out["secret"] = e.Secret
+	}
+	return out
+}
+
+func webhookDeliveryJSON(d *repository.WebhookDelivery) gin.H {
+	var next, delivered interface{}
+	if d.NextAttemptAt != nil && d.Status == repository.WebhookPending {
+		next = d.NextAttemptAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		delivered = d.DeliveredAt.Format(time.RFC3339)
+	}
+	return gin.H{
+		"id":               d.ID,
+		"endpoint_id":      d.EndpointID,
+		"event_id":         d.EventID,
+		"event_type":       d.EventType,
+		"status":           d.Status,
+		"attempts":         d.Attempts,
+		"next_attempt_at":  next,
+		"last_status_code": d.LastStatusCode,
+		"last_error":       d.LastError,
+		"created_at":       d.CreatedAt.Format(time.RFC3339),
+		"delivered_at":     delivered,
+	}
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRouting returns the failover policy for each type and the provider circuit breakers.
+func (c *Controller) GetRouting(ctx *gin.Context) {
+	policies, breakers := c.svc.Routing()
+	types := make([]string, 0, len(policies))
+	for t := range policies {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	out := make([]gin.H, 0, len(types))
+	for _, t := range types {
+		steps := make([]gin.H, 0, len(policies[t].Steps))
+		for i, st := range policies[t].Steps {
+			step := gin.H{"channel": st.Channel}
+			if i > 0 && st.After > 0 {
+				step["after"] = st.After.String()
+			}
+			steps = append(steps, step)
+		}
+		out = append(out, gin.H{"type": t, "steps": steps})
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"policies": out, "breakers": breakers})
+}
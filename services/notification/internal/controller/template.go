@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ListTemplates returns built-in defaults and stored versions. Query: type, channel, locale (all optional).
+func (c *Controller) ListTemplates(ctx *gin.Context) {
+	list, err := c.svc.ListTemplates(ctx.Request.Context(), ctx.Query("type"), ctx.Query("channel"), ctx.Query("locale"))
+	if err != nil {
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, templateJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"templates": out})
+}
+
+// CreateTemplateRequest is the JSON body for POST /admin/templates.
+type CreateTemplateRequest struct {
+	Type      string `json:"type" binding:"required"`
+	Channel   string `json:"channel" binding:"required"`
+	Locale    string `json:"locale" binding:"required"`
+	Subject   string `json:"subject"`
+	HTML      string `json:"html"`
+	Text      string `json:"text"`
+	CreatedBy string `json:"created_by"`
+}
+
+// CreateTemplate stores a new draft version. It is not used until activated.
+func (c *Controller) CreateTemplate(ctx *gin.Context) {
+	var body CreateTemplateRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: type, channel and locale required", CodeBadRequest)
+		return
+	}
+	t := &repository.Template{
+		Type: body.Type, Channel: body.Channel, Locale: body.Locale,
+		Subject: body.Subject, HTML: body.HTML, Text: body.Text, CreatedBy: body.CreatedBy,
+	}
+	if err := c.svc.CreateTemplate(ctx.Request.Context(), t); err != nil {
+		if errors.Is(err, service.ErrInvalidTemplate) {
+			Error(ctx, http.StatusBadRequest, err.Error(), CodeBadRequest)
+			return
+		}
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Template draft created", CodeSuccess, templateJSON(t))
+}
+
+// ActivateTemplate makes a stored version live for its type, channel and locale.
+func (c *Controller) ActivateTemplate(ctx *gin.Context) {
+	t, err := c.svc.ActivateTemplate(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrTemplateNotFound) {
+			Error(ctx, http.StatusNotFound, err.Error(), CodeNotFound)
+			return
+		}
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusOK, "Template activated", CodeSuccess, templateJSON(t))
+}
+
+// PreviewTemplateRequest is the JSON body for POST /admin/templates/preview. Set id to preview a stored version
+// (drafts included), or type, channel and locale to preview what would be sent today.
+type PreviewTemplateRequest struct {
+	ID      string                 `json:"id"`
+	Type    string                 `json:"type"`
+	Channel string                 `json:"channel"`
+	Locale  string                 `json:"locale"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+// PreviewTemplate renders a template against sample data without sending it.
+func (c *Controller) PreviewTemplate(ctx *gin.Context) {
+	var body PreviewTemplateRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil || (body.ID == "" && (body.Type == "" || body.Channel == "")) {
+		Error(ctx, http.StatusBadRequest, "invalid body: id, or type and channel, required", CodeBadRequest)
+		return
+	}
+	r, err := c.svc.PreviewTemplate(ctx.Request.Context(), body.ID, body.Type, body.Channel, body.Locale, body.Data)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTemplateNotFound):
+			Error(ctx, http.StatusNotFound, err.Error(), CodeNotFound)
+		case errors.Is(err, service.ErrInvalidTemplate):
+			Error(ctx, http.StatusBadRequest, err.Error(), CodeBadRequest)
+		default:
+			Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		}
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{
+		"subject": r.Subject,
+		"html":    r.HTML,
+		"text":    r.Text,
+		"locale":  r.Locale,
+		"version": r.Version,
+	})
+}
+
+func templateJSON(t *repository.Template) gin.H {
+	var activatedAt, createdAt interface{}
+	if t.ActivatedAt != nil {
+		activatedAt = t.ActivatedAt.Format(time.RFC3339)
+	}
+	if !t.CreatedAt.IsZero() {
+		createdAt = t.CreatedAt.Format(time.RFC3339)
+	}
+	return gin.H{
+		"id":           t.ID,
+		"type":         t.Type,
+		"channel":      t.Channel,
+		"locale":       t.Locale,
+		"version":      t.Version,
+		"status":       t.Status,
+		"subject":      t.Subject,
+		"html":         t.HTML,
+		"text":         t.Text,
+		"created_by":   t.CreatedBy,
+		"created_at":   createdAt,
+		"activated_at": activatedAt,
+	}
+}
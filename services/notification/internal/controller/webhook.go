@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody caps receipt payloads; WhatsApp batches statuses but stays well under this.
+const maxWebhookBody = 1 << 20
+
+// webhookSecrets authenticate provider delivery receipts. An empty secret disables that provider's webhook.
+type webhookSecrets struct {
+	brevoToken          string
+	termiiSecret        string
+	whatsappAppSecret   string
+	whatsappVerifyToken string
+}
+
+// BrevoWebhook takes Brevo transactional email events (delivered, opened, hard_bounce, ...). Brevo is configured to
+// send the token as a bearer Authorization header.
+func (c *Controller) BrevoWebhook(ctx *gin.Context) {
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !equalSecret(c.webhooks.brevoToken, token) {
+		AbortUnauthorized(ctx, "invalid webhook token")
+		return
+	}
+	var body struct {
+		Event     string `json:"event"`
+		MessageID string `json:"message-id"`
+		Reason    string `json:"reason"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
+		return
+	}
+	c.applyReceipt(ctx, service.ProviderBrevo, body.MessageID, body.Event, body.Reason)
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, nil)
+}
+
+// TermiiWebhook takes Termii SMS delivery reports, signed with X-Termii-Signature (hex HMAC-SHA512 of the body).
+func (c *Controller) TermiiWebhook(ctx *gin.Context) {
+	raw, ok := c.signedBody(ctx, c.webhooks.termiiSecret, sha512.New, ctx.GetHeader("X-Termii-Signature"))
+	if !ok {
+		return
+	}
+	var body struct {
+		MessageID string `json:"message_id"`
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
+		return
+	}
+	c.applyReceipt(ctx, service.ProviderTermii, body.MessageID, body.Status, "")
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, nil)
+}
+
+// WhatsAppWebhookVerify answers Meta's subscription check by echoing hub.challenge when the verify token matches.
+func (c *Controller) WhatsAppWebhookVerify(ctx *gin.Context) {
+	if ctx.Query("hub.mode") != "subscribe" || !equalSecret(c.webhooks.whatsappVerifyToken, ctx.Query("hub.verify_token")) {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	ctx.String(http.StatusOK, ctx.Query("hub.challenge"))
+}
+
+// WhatsAppWebhook takes WhatsApp Cloud API status updates (sent, delivered, read, failed), signed with
+// X-Hub-Signature-256. Inbound messages in the same payload are ignored.
+func (c *Controller) WhatsAppWebhook(ctx *gin.Context) {
+	raw, ok := c.signedBody(ctx, c.webhooks.whatsappAppSecret, sha256.New, strings.TrimPrefix(ctx.GetHeader("X-Hub-Signature-256"), "sha256="))
+	if !ok {
+		return
+	}
+	var body struct {
+		Entry []struct {
+			Changes []struct {
+				Value struct {
+					Statuses []struct {
+						ID     string `json:"id"`
+						Status string `json:"status"`
+						Errors []struct {
+							Title   string `json:"title"`
+							Message string `json:"message"`
+						} `json:"errors"`
+					} `json:"statuses"`
+				} `json:"value"`
+			} `json:"changes"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
+		return
+	}
+	for _, e := range body.Entry {
+		for _, ch := range e.Changes {
+			for _, st := range ch.Value.Statuses {
+				reason := ""
+				if len(st.Errors) > 0 {
+					reason = st.Errors[0].Title
+					if st.Errors[0].Message != "" {
+						reason += ": " + st.Errors[0].Message
+					}
+				}
+				c.applyReceipt(ctx, service.ProviderWhatsApp, st.ID, st.Status, reason)
+			}
+		}
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, nil)
+}
+
+// applyReceipt logs rather than fails: a 5xx would make the provider resend a receipt we cannot use anyway.
+func (c *Controller) applyReceipt(ctx *gin.Context, provider, messageID, status, reason string) {
+	if err := c.svc.ApplyReceipt(ctx.Request.Context(), provider, messageID, status, reason); err != nil {
+		log.Printf("notification: receipt failed provider=%s message_id=%s err=%v", provider, messageID, err)
+	}
+}
+
+// signedBody reads the request body and checks signature, the hex HMAC of the body under secret. On failure it has
+// already responded.
+func (c *Controller) signedBody(ctx *gin.Context, secret string, h func() hash.Hash, signature string) ([]byte, bool) {
+	raw, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxWebhookBody))
+	if err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
+		return nil, false
+	}
+	if err := VerifySignature(secret, h, raw, signature); err != nil {
+		AbortUnauthorized(ctx, err.Error())
+		return nil, false
+	}
+	return raw, true
+}
+
+// VerifySignature checks that signature is the hex HMAC of body under secret. An empty secret fails every request.
+func VerifySignature(secret string, h func() hash.Hash, body []byte, signature string) error {
+	if secret == "" {
+		return errors.New("webhook not configured")
+	}
+	got, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(got) == 0 {
+		return errors.New("invalid or missing signature")
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("invalid or missing signature")
+	}
+	return nil
+}
+
+func equalSecret(want, got string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1
+}
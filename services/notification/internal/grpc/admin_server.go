@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	notificationpb "github.com/abubakvr/payup-backend/proto/notification"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+)
+
+// AdminServer implements notificationpb.NotificationServiceForAdminServer: notification history, delivery status and
+// resends for Admin support tools.
+type AdminServer struct {
+	notificationpb.UnimplementedNotificationServiceForAdminServer
+	svc *service.NotificationService
+}
+
+func NewAdminServer(svc *service.NotificationService) *AdminServer {
+	return &AdminServer{svc: svc}
+}
+
+// ListNotifications returns a user's deliveries, or those sent to an email address or phone number.
+func (s *AdminServer) ListNotifications(ctx context.Context, req *notificationpb.ListNotificationsRequest) (*notificationpb.ListNotificationsResponse, error) {
+	if req == nil || (req.UserId == "" && req.Recipient == "") {
+		return &notificationpb.ListNotificationsResponse{}, nil
+	}
+	f := repository.DeliveryFilter{UserID: req.UserId, Type: req.Type, Channel: req.Channel, Status: req.Status}
+	if req.Recipient != "" {
+		f.RecipientHash = s.svc.HashRecipient(req.Recipient)
+	}
+	list, total, err := s.svc.ListDeliveries(ctx, f, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*notificationpb.NotificationItem, 0, len(list))
+	for i := range list {
+		out = append(out, toItem(&list[i]))
+	}
+	return &notificationpb.ListNotificationsResponse{Notifications: out, Total: total}, nil
+}
+
+func (s *AdminServer) GetNotification(ctx context.Context, req *notificationpb.GetNotificationRequest) (*notificationpb.GetNotificationResponse, error) {
+	if req == nil || req.Id == "" {
+		return &notificationpb.GetNotificationResponse{Found: false}, nil
+	}
+	d, err := s.svc.GetDelivery(ctx, req.Id)
+	if errors.Is(err, service.ErrDeliveryNotFound) {
+		return &notificationpb.GetNotificationResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notificationpb.GetNotificationResponse{Found: true, Item: toItem(d)}, nil
+}
+
+// ResendNotification sends a delivery again. A send that fails still succeeds here; the new item's status says so.
+func (s *AdminServer) ResendNotification(ctx context.Context, req *notificationpb.ResendNotificationRequest) (*notificationpb.ResendNotificationResponse, error) {
+	if req == nil || req.Id == "" {
+		return &notificationpb.ResendNotificationResponse{ErrorMessage: "id is required"}, nil
+	}
+	d, err := s.svc.Resend(ctx, req.Id, req.AdminId)
+	if errors.Is(err, service.ErrDeliveryNotFound) || errors.Is(err, service.ErrNotResendable) {
+		return &notificationpb.ResendNotificationResponse{ErrorMessage: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notificationpb.ResendNotificationResponse{Success: true, Item: toItem(d)}, nil
+}
+
+func toItem(d *repository.Delivery) *notificationpb.NotificationItem {
+	item := &notificationpb.NotificationItem{
+		Id:              d.ID,
+		UserId:          d.UserID,
+		Type:            d.Type,
+		Channel:         d.Channel,
+		SentChannel:     d.SentChannel,
+		Provider:        d.Provider,
+		Status:          d.Status,
+		Attempts:        int32(d.Attempts),
+		LastError:       d.LastError,
+		Subject:         d.Subject,
+		RecipientMasked: d.RecipientMasked,
+		Resendable:      len(d.Payload) > 0,
+		ResentFrom:      d.ResentFrom,
+		ResentBy:        d.ResentBy,
+		CreatedAt:       d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       d.UpdatedAt.Format(time.RFC3339),
+		SentAt:          formatTime(d.SentAt),
+		DeliveredAt:     formatTime(d.DeliveredAt),
+	}
+	for _, h := range d.Route {
+		item.Route = append(item.Route, &notificationpb.RouteHop{
+			Channel:  h.Channel,
+			Provider: h.Provider,
+			Status:   h.Status,
+			Error:    h.Error,
+			At:       h.At.Format(time.RFC3339),
+		})
+	}
+	return item
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -3,10 +3,14 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/abubakvr/payup-backend/services/notification/internal/model"
 	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/google/uuid"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -14,21 +18,56 @@ import (
 const topic = "notification-events"
 const groupID = "notification-service"
 
-// Consumer reads notification events from Kafka and hands them to the notification service.
+// retryTopic holds events waiting for their next attempt, so a failing provider does not hold up the events behind
+// them on notification-events. retryGroupID consumes it.
+const (
+	retryTopic   = "notification-events-retry"
+	retryGroupID = "notification-service-retry"
+)
+
+// Backoff between attempts at one event: 1s, 2s, 4s, ... capped at 30s. One-time codes are retried in place and
+// sooner (250ms, 500ms, then every second): the user is waiting for the code, and it goes stale.
+const (
+	retryBaseDelay       = time.Second
+	retryMaxDelay        = 30 * time.Second
+	secretRetryBaseDelay = 250 * time.Millisecond
+	secretRetryMaxDelay  = time.Second
+)
+
+// Consumer reads notification events from Kafka and hands them to the notification service. An event that fails in a
+// way worth retrying is moved to the retry topic and tried again after a backoff, until it is sent, fails permanently
+// or runs out of attempts; then it is parked on the dead-letter topic. Offsets are committed once the event is sent,
+// moved or parked.
 type Consumer struct {
-	reader  *kafka.Reader
-	service *service.NotificationService
+	reader      *kafka.Reader
+	retryReader *kafka.Reader
+	retries     *kafka.Writer
+	service     *service.NotificationService
+	dlq         *DeadLetterQueue
+	maxAttempts int
 }
 
-// NewConsumer creates a Kafka consumer for the notification-events topic.
-func NewConsumer(brokers []string, svc *service.NotificationService) *Consumer {
+// NewConsumer creates a Kafka consumer for the notification-events topic and its retry topic. maxAttempts below 1
+// means 1.
+func NewConsumer(brokers []string, svc *service.NotificationService, dlq *DeadLetterQueue, maxAttempts int) *Consumer {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
 	return &Consumer{
 		reader: kafka.NewReader(kafka.ReaderConfig{
 			Brokers: brokers,
 			Topic:   topic,
 			GroupID: groupID,
 		}),
-		service: svc,
+		retryReader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: brokers,
+			Topic:   retryTopic,
+			GroupID: retryGroupID,
+		}),
+		retries:     &kafka.Writer{Addr: kafka.TCP(brokers...), Topic: retryTopic, Balancer: &kafka.LeastBytes{}},
+		service:     svc,
+		dlq:         dlq,
+		maxAttempts: maxAttempts,
 	}
 }
 
@@ -43,33 +82,161 @@ func metadataKeys(m map[string]interface{}) []string {
 	return keys
 }
 
-// Start runs the consumer loop. Call it in a goroutine.
+// deliveryID identifies the delivery across retries and replays: the delivery_id header on replayed messages, else
+// one derived from the message's position so a redelivery after a crash lands on the same log row.
+func deliveryID(msg kafka.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == headerDeliveryID {
+			if id, err := uuid.ParseBytes(h.Value); err == nil {
+				return id.String()
+			}
+		}
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s/%d/%d", msg.Topic, msg.Partition, msg.Offset))).String()
+}
+
+func backoff(attempt int) time.Duration {
+	return capped(retryBaseDelay, retryMaxDelay, attempt)
+}
+
+// retryDelay is how long to wait before the attempt after attempt.
+func retryDelay(secret bool, attempt int) time.Duration {
+	if secret {
+		return capped(secretRetryBaseDelay, secretRetryMaxDelay, attempt)
+	}
+	return backoff(attempt)
+}
+
+func capped(base, max time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	if d <= 0 || d > max {
+		return max
+	}
+	return d
+}
+
+// attemptsMade is how many attempts the event has had before this message: the attempts header on retries, else 0.
+func attemptsMade(msg kafka.Message) int {
+	for _, h := range msg.Headers {
+		if h.Key == headerAttempts {
+			if n, err := strconv.Atoi(string(h.Value)); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// retryAt is when a message on the retry topic is due; zero if it carries no time.
+func retryAt(msg kafka.Message) time.Time {
+	for _, h := range msg.Headers {
+		if h.Key == headerRetryAt {
+			if at, err := time.Parse(time.RFC3339Nano, string(h.Value)); err == nil {
+				return at
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// retryMessage is msg as it goes on the retry topic after attempts failed attempts, due at.
+func retryMessage(msg kafka.Message, id string, attempts int, at time.Time) kafka.Message {
+	return kafka.Message{
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: []kafka.Header{
+			{Key: headerDeliveryID, Value: []byte(id)},
+			{Key: headerAttempts, Value: []byte(strconv.Itoa(attempts))},
+			{Key: headerRetryAt, Value: []byte(at.UTC().Format(time.RFC3339Nano))},
+		},
+	}
+}
+
+// Start runs the consumer loops. Call it in a goroutine.
 func (c *Consumer) Start() {
 	log.Println("notification consumer: starting read loop topic=notification-events")
+	ctx := context.Background()
+	go c.consume(ctx, c.retryReader, true)
+	c.consume(ctx, c.reader, false)
+}
+
+// consume reads r until the process exits. Messages on the retry topic are held until they are due; they are queued
+// in the order they failed and no backoff is longer than retryMaxDelay, so one is held behind another for at most
+// that long.
+func (c *Consumer) consume(ctx context.Context, r *kafka.Reader, retrying bool) {
 	for {
-		msg, err := c.reader.ReadMessage(context.Background())
+		msg, err := r.FetchMessage(ctx)
 		if err != nil {
 			log.Println("notification consumer: kafka error:", err)
 			continue
 		}
-
-		var event model.NotificationEvent
-		if err := json.Unmarshal(msg.Value, &event); err != nil {
-			log.Printf("notification consumer: invalid event err=%v raw_len=%d", err, len(msg.Value))
-			continue
-		}
-
-		log.Printf("notification consumer: received type=%s channel=%s metadata_keys=%v", event.Type, event.Channel, metadataKeys(event.Metadata))
-		if event.Channel == "email" && event.Metadata != nil {
-			if to, ok := event.Metadata["to"].(string); ok {
-				log.Printf("notification consumer: email to=%s subject=%v", to, event.Metadata["subject"])
+		if retrying {
+			if wait := time.Until(retryAt(msg)); wait > 0 {
+				time.Sleep(wait)
 			}
 		}
+		c.handle(ctx, msg)
+		if err := r.CommitMessages(ctx, msg); err != nil {
+			log.Printf("notification consumer: commit failed topic=%s offset=%d err=%v", msg.Topic, msg.Offset, err)
+		}
+	}
+}
 
-		if err := c.service.Process(event); err != nil {
-			log.Printf("notification consumer: process failed type=%s err=%v", event.Type, err)
-		} else {
+func (c *Consumer) handle(ctx context.Context, msg kafka.Message) {
+	id := deliveryID(msg)
+	var event model.NotificationEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		log.Printf("notification consumer: invalid event err=%v raw_len=%d", err, len(msg.Value))
+		c.deadLetter(ctx, msg, id, 0, fmt.Errorf("invalid event: %w", err))
+		return
+	}
+
+	log.Printf("notification consumer: received type=%s channel=%s delivery_id=%s metadata_keys=%v", event.Type, event.Channel, id, metadataKeys(event.Metadata))
+
+	info, _ := model.LookupType(event.Type)
+	var err error
+	attempt := attemptsMade(msg) + 1
+	for ; ; attempt++ {
+		if err = c.service.Deliver(ctx, id, event); err == nil {
 			log.Printf("notification consumer: process ok type=%s channel=%s", event.Type, event.Channel)
+			return
+		}
+		if !service.Retryable(err) || attempt >= c.maxAttempts {
+			break
+		}
+		wait := retryDelay(info.Secret, attempt)
+		log.Printf("notification consumer: attempt %d failed type=%s err=%v; retrying in %s", attempt, event.Type, err, wait)
+		if !info.Secret && c.requeue(ctx, msg, id, attempt, wait) {
+			return
+		}
+		time.Sleep(wait)
+	}
+	log.Printf("notification consumer: process failed type=%s attempts=%d err=%v", event.Type, attempt, err)
+	c.deadLetter(ctx, msg, id, attempt, err)
+	c.service.DeadLettered(ctx, id, err)
+}
+
+// requeue moves the event to the retry topic, due after wait. If that fails the caller retries in place rather than
+// lose the event.
+func (c *Consumer) requeue(ctx context.Context, msg kafka.Message, id string, attempts int, wait time.Duration) bool {
+	if err := c.retries.WriteMessages(ctx, retryMessage(msg, id, attempts, time.Now().Add(wait))); err != nil {
+		log.Printf("notification consumer: retry publish failed delivery_id=%s err=%v; retrying in place", id, err)
+		return false
+	}
+	return true
+}
+
+// deadLetter keeps trying until the event is parked: committing the offset without it would lose the event.
+func (c *Consumer) deadLetter(ctx context.Context, msg kafka.Message, id string, attempts int, cause error) {
+	if c.dlq == nil {
+		return
+	}
+	for i := 1; ; i++ {
+		err := c.dlq.Publish(ctx, msg, id, attempts, cause)
+		if err == nil {
+			return
 		}
+		log.Printf("notification consumer: dead-letter publish failed delivery_id=%s err=%v", id, err)
+		time.Sleep(backoff(i))
 	}
 }
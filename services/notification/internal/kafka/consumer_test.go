@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		secret  bool
+		attempt int
+		want    time.Duration
+	}{
+		{false, 1, time.Second},
+		{false, 3, 4 * time.Second},
+		{false, 6, 30 * time.Second},
+		{false, 80, 30 * time.Second},
+		{true, 1, 250 * time.Millisecond},
+		{true, 2, 500 * time.Millisecond},
+		{true, 4, time.Second},
+		{true, 80, time.Second},
+	}
+	for _, c := range cases {
+		if got := retryDelay(c.secret, c.attempt); got != c.want {
+			t.Errorf("retryDelay(secret=%v, %d) = %s, want %s", c.secret, c.attempt, got, c.want)
+		}
+	}
+}
+
+func TestRetryMessage(t *testing.T) {
+	orig := kafka.Message{Topic: topic, Partition: 2, Offset: 41, Key: []byte("user-1"), Value: []byte(`{"type":"transfer_success"}`)}
+	id := deliveryID(orig)
+	if attemptsMade(orig) != 0 || !retryAt(orig).IsZero() {
+		t.Fatalf("a fresh event has attempts %d, due %s", attemptsMade(orig), retryAt(orig))
+	}
+
+	due := time.Date(2026, 5, 1, 12, 0, 4, 500, time.UTC)
+	retry := retryMessage(orig, id, 3, due)
+	// The retry is a new message at a new offset; it keeps the delivery log row, the attempt count and the payload.
+	retry.Topic, retry.Offset = retryTopic, 7
+	if got := deliveryID(retry); got != id {
+		t.Errorf("delivery ID %s on the retry topic, want %s", got, id)
+	}
+	if attemptsMade(retry) != 3 || !retryAt(retry).Equal(due) {
+		t.Errorf("retry has attempts %d, due %s", attemptsMade(retry), retryAt(retry))
+	}
+	if string(retry.Key) != "user-1" || string(retry.Value) != string(orig.Value) {
+		t.Errorf("retry = %q %q", retry.Key, retry.Value)
+	}
+}
@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const dlqTopic = "notification-events-dlq"
+
+// replayGroupID tracks how far admins have replayed the dead-letter topic; each message is replayed once.
+const replayGroupID = "notification-dlq-replay"
+
+// Dead-letter message headers. headerDeliveryID is also carried on replayed and retried messages so the delivery log
+// keeps counting attempts on the same row; retried messages also carry headerAttempts and headerRetryAt.
+const (
+	headerDeliveryID = "delivery_id"
+	headerError      = "error"
+	headerAttempts   = "attempts"
+	headerFailedAt   = "failed_at"
+	headerSource     = "source"
+	headerRetryAt    = "retry_at"
+)
+
+// replayIdle is how long Replay waits for the next dead letter before deciding the topic is drained. The first fetch
+// waits longer because joining the consumer group takes a few seconds.
+const (
+	replayIdle      = 2 * time.Second
+	replayFirstIdle = 10 * time.Second
+)
+
+// DeadLetterQueue parks events the consumer gave up on in the notification-events-dlq topic and replays them.
+type DeadLetterQueue struct {
+	brokers []string
+	dlq     *kafka.Writer
+	events  *kafka.Writer
+	mu      sync.Mutex // one replay at a time
+}
+
+// NewDeadLetterQueue returns a queue writing to notification-events-dlq and replaying onto notification-events.
+func NewDeadLetterQueue(brokers []string) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		brokers: brokers,
+		dlq:     &kafka.Writer{Addr: kafka.TCP(brokers...), Topic: dlqTopic, Balancer: &kafka.LeastBytes{}},
+		events:  &kafka.Writer{Addr: kafka.TCP(brokers...), Topic: topic, Balancer: &kafka.LeastBytes{}},
+	}
+}
+
+// Publish parks the original message with why and after how many attempts it failed.
+func (q *DeadLetterQueue) Publish(ctx context.Context, msg kafka.Message, deliveryID string, attempts int, cause error) error {
+	return q.dlq.WriteMessages(ctx, kafka.Message{
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: []kafka.Header{
+			{Key: headerDeliveryID, Value: []byte(deliveryID)},
+			{Key: headerError, Value: []byte(cause.Error())},
+			{Key: headerAttempts, Value: []byte(strconv.Itoa(attempts))},
+			{Key: headerFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+			{Key: headerSource, Value: []byte(fmt.Sprintf("%s/%d/%d", msg.Topic, msg.Partition, msg.Offset))},
+		},
+	})
+}
+
+// Replay moves up to limit dead letters back onto notification-events, oldest first, and returns how many it moved.
+// It stops early once the topic has been idle for a moment.
+func (q *DeadLetterQueue) Replay(ctx context.Context, limit int) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     q.brokers,
+		Topic:       dlqTopic,
+		GroupID:     replayGroupID,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	replayed := 0
+	for replayed < limit {
+		idle := replayIdle
+		if replayed == 0 {
+			idle = replayFirstIdle
+		}
+		fetchCtx, cancel := context.WithTimeout(ctx, idle)
+		msg, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			break
+		}
+		if err != nil {
+			return replayed, err
+		}
+		var headers []kafka.Header
+		for _, h := range msg.Headers {
+			if h.Key == headerDeliveryID {
+				headers = append(headers, h)
+			}
+		}
+		if err := q.events.WriteMessages(ctx, kafka.Message{Key: msg.Key, Value: msg.Value, Headers: headers}); err != nil {
+			return replayed, err
+		}
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	log.Printf("notification: replayed %d dead letters", replayed)
+	return replayed, nil
+}
+
+// Close flushes and closes the writers.
+func (q *DeadLetterQueue) Close() error {
+	return errors.Join(q.dlq.Close(), q.events.Close())
+}
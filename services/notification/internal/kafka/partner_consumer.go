@@ -0,0 +1,140 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/service"
+	"github.com/abubakvr/payup-backend/services/notification/internal/webhooks"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Topics partner webhook events come from. Their producers are unchanged: each message that maps to a partner event
+// is turned into one here and the rest are ignored.
+const (
+	walletEventsTopic = "wallet-events"
+	kycEventsTopic    = "kyc-events"
+)
+
+// partnerGroupID has its own offsets, so partner webhooks neither slow nor depend on sending notifications.
+const partnerGroupID = "notification-partner-webhooks"
+
+// PartnerEventConsumer turns the events other services already publish into partner webhook events and queues them.
+type PartnerEventConsumer struct {
+	reader  *kafka.Reader
+	service *service.NotificationService
+}
+
+// NewPartnerEventConsumer creates a consumer for wallet-events, kyc-events and notification-events.
+func NewPartnerEventConsumer(brokers []string, svc *service.NotificationService) *PartnerEventConsumer {
+	return &PartnerEventConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			GroupID:     partnerGroupID,
+			GroupTopics: []string{walletEventsTopic, kycEventsTopic, topic},
+		}),
+		service: svc,
+	}
+}
+
+// Start runs the consumer loop. Call it in a goroutine. An event is committed only once it is queued; a database error
+// is retried until it clears, since committing would lose the event.
+func (c *PartnerEventConsumer) Start() {
+	log.Println("partner webhook consumer: starting read loop topics=wallet-events,kyc-events,notification-events")
+	ctx := context.Background()
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			log.Println("partner webhook consumer: kafka error:", err)
+			continue
+		}
+		if ev, ok := partnerEvent(msg); ok {
+			for i := 1; ; i++ {
+				n, err := c.service.PublishPartnerEvent(ctx, ev)
+				if err == nil {
+					if n > 0 {
+						log.Printf("partner webhook consumer: queued %s event=%s deliveries=%d", ev.Type, ev.ID, n)
+					}
+					break
+				}
+				log.Printf("partner webhook consumer: queue failed type=%s event=%s err=%v", ev.Type, ev.ID, err)
+				time.Sleep(backoff(i))
+			}
+		}
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			log.Printf("partner webhook consumer: commit failed topic=%s offset=%d err=%v", msg.Topic, msg.Offset, err)
+		}
+	}
+}
+
+// partnerEvent maps a Kafka message to the partner event it represents, if any:
+//
+//   - wallet-events transfer_success → transfer.success
+//   - wallet-events transfer_failed → transfer.failed (the bank refused the transfer; nothing was debited)
+//   - notification-events wallet_credit → wallet.credited (inbound transfers and admin credits both send it)
+//   - kyc-events kyc_approved → kyc.approved
+//
+// Transfers and credits are identified by their transaction reference, so a message consumed again (or a replayed
+// dead letter) keeps its event ID.
+func partnerEvent(msg kafka.Message) (webhooks.Event, bool) {
+	var raw struct {
+		EventType      string                 `json:"event_type"` // wallet-events, kyc-events
+		Type           string                 `json:"type"`       // notification-events
+		UserID         string                 `json:"user_id"`
+		TransactionRef string                 `json:"transaction_ref"`
+		Amount         float64                `json:"amount"`
+		ResponseCode   string                 `json:"response_code"`
+		Data           map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(msg.Value, &raw); err != nil || raw.UserID == "" {
+		return webhooks.Event{}, false
+	}
+	created := msg.Time.UTC()
+	if created.IsZero() {
+		created = time.Now().UTC()
+	}
+	ev := webhooks.Event{CreatedAt: created, UserID: raw.UserID, Data: map[string]interface{}{"user_id": raw.UserID}}
+	position := fmt.Sprintf("%s/%d/%d", msg.Topic, msg.Partition, msg.Offset)
+	switch {
+	case msg.Topic == walletEventsTopic && raw.EventType == "transfer_success":
+		ev.Type = webhooks.EventTransferSuccess
+		ev.Data["transaction_ref"] = raw.TransactionRef
+		ev.Data["amount"] = raw.Amount
+		ev.Data["currency"] = "NGN"
+		ev.ID = webhooks.EventID(ev.Type, refOr(raw.TransactionRef, position))
+	case msg.Topic == walletEventsTopic && raw.EventType == "transfer_failed":
+		ev.Type = webhooks.EventTransferFailed
+		ev.Data["transaction_ref"] = raw.TransactionRef
+		ev.Data["amount"] = raw.Amount
+		ev.Data["currency"] = "NGN"
+		ev.Data["response_code"] = raw.ResponseCode
+		ev.ID = webhooks.EventID(ev.Type, refOr(raw.TransactionRef, position))
+	case msg.Topic == topic && raw.Type == "wallet_credit":
+		ref, _ := raw.Data["transaction_ref"].(string)
+		ev.Type = webhooks.EventWalletCredited
+		ev.Data["transaction_ref"] = ref
+		ev.Data["amount"] = raw.Data["amount"]
+		ev.Data["currency"] = "NGN"
+		if narration, ok := raw.Data["narration"].(string); ok {
+			ev.Data["narration"] = narration
+		}
+		ev.ID = webhooks.EventID(ev.Type, refOr(ref, position))
+	case msg.Topic == kycEventsTopic && raw.EventType == "kyc_approved":
+		ev.Type = webhooks.EventKYCApproved
+		ev.ID = webhooks.EventID(ev.Type, position)
+	default:
+		return webhooks.Event{}, false
+	}
+	return ev, true
+}
+
+func refOr(ref, fallback string) string {
+	if ref != "" {
+		return ref
+	}
+	return fallback
+}
@@ -0,0 +1,30 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/webhooks"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestPartnerEventTransferFailed(t *testing.T) {
+	msg := kafka.Message{Topic: walletEventsTopic, Partition: 0, Offset: 12, Value: []byte(
+		`{"event_type":"transfer_failed","user_id":"user-1","transaction_ref":"TRF1","amount":2500,"response_code":"99"}`)}
+	ev, ok := partnerEvent(msg)
+	if !ok || ev.Type != webhooks.EventTransferFailed || ev.UserID != "user-1" {
+		t.Fatalf("event = %+v, %v", ev, ok)
+	}
+	if ev.Data["transaction_ref"] != "TRF1" || ev.Data["amount"] != 2500.0 || ev.Data["currency"] != "NGN" || ev.Data["response_code"] != "99" {
+		t.Errorf("data = %v", ev.Data)
+	}
+
+	// Consumed again at another offset, it is the same event; the transfer's success event would be another.
+	msg.Offset = 40
+	if again, _ := partnerEvent(msg); again.ID != ev.ID {
+		t.Errorf("redelivery has event ID %s, want %s", again.ID, ev.ID)
+	}
+	msg.Value = []byte(`{"event_type":"transfer_success","user_id":"user-1","transaction_ref":"TRF1","amount":2500}`)
+	if success, _ := partnerEvent(msg); success.Type != webhooks.EventTransferSuccess || success.ID == ev.ID {
+		t.Errorf("success event = %+v; want its own ID", success)
+	}
+}
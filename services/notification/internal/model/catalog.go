@@ -0,0 +1,98 @@
+package model
+
+import "strings"
+
+// Channels a notification can go out on. in_app is the inbox behind GET /notifications.
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+	ChannelPush     = "push"
+	ChannelInApp    = "in_app"
+)
+
+// Channels lists every channel a preference can be set for, in display order.
+var Channels = []string{ChannelEmail, ChannelSMS, ChannelWhatsApp, ChannelPush, ChannelInApp}
+
+// TypeInfo describes a notification type for the preference center. Critical types (codes, sign-in and account
+// security alerts) are always delivered; Secret ones carry a code or a one-time link and are never kept in the inbox.
+type TypeInfo struct {
+	Type     string
+	Category string
+	Critical bool
+	Secret   bool
+}
+
+// Catalog is every notification type the services send, grouped for the preference center.
+var Catalog = []TypeInfo{
+	{Type: "email_verification", Category: "security", Critical: true, Secret: true},
+	{Type: "password_reset", Category: "security", Critical: true, Secret: true},
+	{Type: "2fa_login_otp", Category: "security", Critical: true, Secret: true},
+	{Type: "step_up_otp", Category: "security", Critical: true, Secret: true},
+	{Type: "email_change_otp", Category: "security", Critical: true, Secret: true},
+	{Type: "phone_change_otp", Category: "security", Critical: true, Secret: true},
+	{Type: "kyc_phone_otp", Category: "security", Critical: true, Secret: true},
+	{Type: "new_device_login", Category: "security", Critical: true},
+	{Type: "email_change", Category: "security", Critical: true},
+	{Type: "phone_change", Category: "security", Critical: true},
+	{Type: "passkey_added", Category: "security", Critical: true},
+	{Type: "2fa_reset", Category: "security", Critical: true},
+	{Type: "2fa_recovery_code_used", Category: "security", Critical: true},
+	{Type: "user_restricted", Category: "security", Critical: true},
+	{Type: "limit_increase_requested", Category: "security", Critical: true},
+	{Type: "limit_increase_applied", Category: "security", Critical: true},
+	{Type: "account_deletion_scheduled", Category: "security", Critical: true},
+	{Type: "account_deletion_cancelled", Category: "security", Critical: true},
+	{Type: "account_deletion_held", Category: "account"},
+	{Type: "account_deleted", Category: "account", Critical: true},
+	{Type: "data_export_ready", Category: "account"},
+	{Type: "kyc_approved", Category: "account"},
+	{Type: "kyc_rejected", Category: "account"},
+	{Type: "wallet_opened", Category: "account"},
+	{Type: "wallet_status_changed", Category: "account", Critical: true},
+	{Type: "wallet_upgrade_submitted", Category: "account"},
+	{Type: "transfer_success", Category: "transactions"},
+	{Type: "wallet_credit", Category: "transactions"},
+	{Type: "wallet_debit", Category: "transactions"},
+	{Type: "wallet_max_balance_exceeded", Category: "transactions"},
+	{Type: "bill_payment_success", Category: "transactions"},
+	{Type: "bill_payment_reversed", Category: "transactions"},
+	{Type: "bill_token", Category: "transactions", Critical: true},
+	{Type: "payment_request_received", Category: "transactions"},
+	{Type: "payment_request_paid", Category: "transactions"},
+	{Type: "payment_request_declined", Category: "transactions"},
+	{Type: "dispute_opened", Category: "disputes"},
+	{Type: "dispute_awaiting_user", Category: "disputes"},
+	{Type: "dispute_rejected", Category: "disputes"},
+	{Type: "dispute_resolved", Category: "disputes"},
+}
+
+var catalogByType = func() map[string]TypeInfo {
+	m := make(map[string]TypeInfo, len(Catalog))
+	for _, t := range Catalog {
+		m[t.Type] = t
+	}
+	return m
+}()
+
+// LookupType returns the catalog entry for a type. Types missing from the catalog are treated as switchable
+// (non-critical) unless their name marks them as a code (suffix _otp).
+func LookupType(eventType string) (TypeInfo, bool) {
+	if t, ok := catalogByType[eventType]; ok {
+		return t, true
+	}
+	if strings.HasSuffix(eventType, "_otp") {
+		return TypeInfo{Type: eventType, Category: "security", Critical: true, Secret: true}, false
+	}
+	return TypeInfo{Type: eventType, Category: "other"}, false
+}
+
+// ValidChannel reports whether channel is one preferences can be set for.
+func ValidChannel(channel string) bool {
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
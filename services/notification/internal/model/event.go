@@ -1,10 +1,25 @@
 package model
 
 // NotificationEvent is the payload consumed from the notification-events Kafka topic.
-// Type identifies the kind of notification; Channel determines the provider (email, sms, whatsapp).
-// Metadata holds channel-specific fields (to, subject, body, template_id, params, etc.).
+// Type identifies the kind of notification; Channel determines the provider (email, sms, whatsapp, push).
+// Metadata holds channel-specific fields (to, subject, body, template_id, params, etc.). UserID, when the producer
+// knows it, files the notification in the user's inbox and applies their preferences. Templated events leave the
+// content out of Metadata and send Data instead: the service renders the template for Type and Channel in Locale, or in
+// the user's language setting when Locale is empty.
 type NotificationEvent struct {
+	UserID   string                 `json:"user_id,omitempty"`
 	Type     string                 `json:"type"`               // e.g. email_verification, sms_otp, whatsapp_alert, transfer_receipt
-	Channel  string                 `json:"channel"`             // email | sms | whatsapp
+	Channel  string                 `json:"channel"`             // email | sms | whatsapp | push
 	Metadata map[string]interface{} `json:"metadata"`             // to, subject, body, html, template_id, params, etc.
+	Data     map[string]interface{} `json:"data,omitempty"`      // template variables, e.g. amount, beneficiary, transaction_ref
+	Locale   string                 `json:"locale,omitempty"`    // overrides the user's language, e.g. for recipients with no account
+}
+
+// Recipient is how the user service says to reach a user and which language to write to them in.
+type Recipient struct {
+	Email     string
+	Phone     string
+	FirstName string
+	LastName  string
+	Language  string
 }
@@ -0,0 +1,137 @@
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ProductionURL = "https://api.push.apple.com"
+	SandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// Apple rejects provider tokens older than an hour and throttles ones refreshed more often than every 20 minutes.
+	providerTokenTTL = 50 * time.Minute
+)
+
+// Client sends push notifications to iOS devices through the APNs HTTP/2 API with token-based (.p8 key) auth.
+type Client struct {
+	KeyID      string
+	TeamID     string
+	Topic      string // app bundle ID
+	BaseURL    string
+	HTTPClient *http.Client
+
+	key *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+// NewClient returns an APNs client for the signing key (.p8 file contents). baseURL is ProductionURL, SandboxURL or a
+// test server.
+func NewClient(keyID, teamID, topic string, keyPEM []byte, baseURL string) (*Client, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("apns: parse key: %w", err)
+	}
+	if baseURL == "" {
+		baseURL = ProductionURL
+	}
+	return &Client{
+		KeyID:      keyID,
+		TeamID:     teamID,
+		Topic:      topic,
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		key:        key,
+	}, nil
+}
+
+type alert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type aps struct {
+	Alert alert  `json:"alert"`
+	Sound string `json:"sound"`
+}
+
+// Send delivers msg to one device token. A token APNs no longer accepts comes back as *push.InvalidTokenError.
+func (c *Client) Send(ctx context.Context, token string, msg push.Message) error {
+	providerToken, err := c.providerToken()
+	if err != nil {
+		return err
+	}
+	payload := map[string]interface{}{"aps": aps{Alert: alert{Title: msg.Title, Body: msg.Body}, Sound: "default"}}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", c.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	var e struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(respBody, &e)
+	switch {
+	case resp.StatusCode == http.StatusGone, e.Reason == "BadDeviceToken", e.Reason == "DeviceTokenNotForTopic":
+		return &push.InvalidTokenError{Reason: e.Reason}
+	case e.Reason == "ExpiredProviderToken", e.Reason == "InvalidProviderToken":
+		c.mu.Lock()
+		c.jwt = ""
+		c.mu.Unlock()
+	}
+	return fmt.Errorf("apns: status %d %s", resp.StatusCode, e.Reason)
+}
+
+// providerToken returns the signed provider JWT, re-signing it when it is older than providerTokenTTL.
+func (c *Client) providerToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.jwt != "" && time.Since(c.issuedAt) < providerTokenTTL {
+		return c.jwt, nil
+	}
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": c.TeamID, "iat": now.Unix()})
+	t.Header["kid"] = c.KeyID
+	signed, err := t.SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("apns: sign provider token: %w", err)
+	}
+	c.jwt, c.issuedAt = signed, now
+	return signed, nil
+}
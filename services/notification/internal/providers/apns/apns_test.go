@@ -0,0 +1,77 @@
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestSend(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fake APNs: checks the provider token and headers; "gone" was uninstalled, "bad" is malformed, "down" fails.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+		tok, err := jwt.Parse(bearer, func(tok *jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+		if err != nil || tok.Header["kid"] != "KEY123" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"reason":"InvalidProviderToken"}`))
+			return
+		}
+		if r.Header.Get("apns-topic") != "com.payup.app" || r.Header.Get("apns-push-type") != "alert" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered","timestamp":1700000000000}`))
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		case "down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"reason":"ServiceUnavailable"}`))
+		default:
+			var payload map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			alert := payload["aps"].(map[string]interface{})["alert"].(map[string]interface{})
+			if alert["title"] != "Wallet credited" || payload["transaction_ref"] != "INB1" {
+				t.Errorf("payload = %v", payload)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	c, err := NewClient("KEY123", "TEAM123", "com.payup.app", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := push.Message{Title: "Wallet credited", Body: "NGN 2,000 received", Data: map[string]string{"transaction_ref": "INB1"}}
+
+	if err := c.Send(context.Background(), "ok", msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	for _, token := range []string{"gone", "bad"} {
+		if _, ok := push.InvalidTokenReason(c.Send(context.Background(), token, msg)); !ok {
+			t.Fatalf("%s: want InvalidTokenError", token)
+		}
+	}
+	err = c.Send(context.Background(), "down", msg)
+	if _, ok := push.InvalidTokenReason(err); ok || err == nil {
+		t.Fatalf("unavailable: err=%v, want a plain error (token kept)", err)
+	}
+}
@@ -55,11 +55,27 @@ type To struct {
 	Name  string `json:"name,omitempty"`
 }
 
-// Send sends one transactional email. Prefer HTML or Text; TemplateID can be used with Params.
-func (c *Client) Send(toEmail, toName, subject, htmlBody, textBody string, templateID int64, params map[string]interface{}) error {
+// APIError is a non-2xx reply from Brevo. Temporary reports whether sending again can succeed (rate limit or a
+// Brevo-side failure); a rejected address or payload will fail the same way every time.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("brevo: unexpected status %d body=%s", e.StatusCode, e.Body)
+}
+
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Send sends one transactional email and returns Brevo's message ID, which delivery webhooks refer to. Prefer HTML or
+// Text; TemplateID can be used with Params.
+func (c *Client) Send(toEmail, toName, subject, htmlBody, textBody string, templateID int64, params map[string]interface{}) (string, error) {
 	if c.APIKey == "" {
 		log.Printf("brevo: Send aborted (API key empty)")
-		return fmt.Errorf("brevo: missing API key")
+		return "", fmt.Errorf("brevo: missing API key")
 	}
 	log.Printf("brevo: calling API url=%s to=%s from=%s subject=%s", c.BaseURL, toEmail, c.SenderEmail, subject)
 
@@ -79,11 +95,11 @@ func (c *Client) Send(toEmail, toName, subject, htmlBody, textBody string, templ
 	body, err := json.Marshal(req)
 	if err != nil {
 		log.Printf("brevo: marshal err=%v", err)
-		return err
+		return "", err
 	}
 	httpReq, err := http.NewRequest(http.MethodPost, c.BaseURL, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return "", err
 	}
 	httpReq.Header.Set("accept", "application/json")
 	httpReq.Header.Set("content-type", "application/json")
@@ -91,15 +107,19 @@ func (c *Client) Send(toEmail, toName, subject, htmlBody, textBody string, templ
 	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
 		log.Printf("brevo: request err=%v", err)
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
+	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
 		log.Printf("brevo: API error status=%d body=%s", resp.StatusCode, string(respBody))
-		return fmt.Errorf("brevo: unexpected status %d body=%s", resp.StatusCode, string(respBody))
+		return "", &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	var accepted struct {
+		MessageID string `json:"messageId"`
 	}
-	log.Printf("brevo: email accepted status=%d to=%s", resp.StatusCode, toEmail)
-	return nil
+	_ = json.Unmarshal(respBody, &accepted)
+	log.Printf("brevo: email accepted status=%d to=%s message_id=%s", resp.StatusCode, toEmail, accepted.MessageID)
+	return accepted.MessageID, nil
 }
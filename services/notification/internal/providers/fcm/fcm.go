@@ -0,0 +1,207 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultBaseURL  = "https://fcm.googleapis.com"
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	messagingScope  = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// ServiceAccount is the part of a Google service account key file the client needs.
+type ServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// Client sends push notifications to Android devices through the FCM HTTP v1 API. It signs in with the service
+// account (OAuth 2.0 JWT bearer grant) and reuses the access token until shortly before it expires.
+type Client struct {
+	ProjectID  string
+	BaseURL    string
+	HTTPClient *http.Client
+
+	clientEmail string
+	tokenURL    string
+	key         *rsa.PrivateKey
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewClient returns an FCM client from a service account key file's contents. baseURL overrides the FCM endpoint
+// (empty for production).
+func NewClient(serviceAccountJSON []byte, baseURL string) (*Client, error) {
+	var sa ServiceAccount
+	if err := json.Unmarshal(serviceAccountJSON, &sa); err != nil {
+		return nil, fmt.Errorf("fcm: parse service account: %w", err)
+	}
+	if sa.ProjectID == "" || sa.ClientEmail == "" || sa.PrivateKey == "" {
+		return nil, fmt.Errorf("fcm: service account needs project_id, client_email and private_key")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(sa.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("fcm: parse private key: %w", err)
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = defaultTokenURL
+	}
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		ProjectID:   sa.ProjectID,
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		clientEmail: sa.ClientEmail,
+		tokenURL:    sa.TokenURI,
+		key:         key,
+	}, nil
+}
+
+type sendRequest struct {
+	Message message `json:"message"`
+}
+
+type message struct {
+	Token        string            `json:"token"`
+	Notification notification      `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      androidConfig     `json:"android"`
+}
+
+type notification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type androidConfig struct {
+	Priority string `json:"priority"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			Type      string `json:"@type"`
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send delivers msg to one registration token. A token FCM no longer accepts comes back as *push.InvalidTokenError.
+func (c *Client) Send(ctx context.Context, token string, msg push.Message) error {
+	accessToken, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(sendRequest{Message: message{
+		Token:        token,
+		Notification: notification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+		Android:      androidConfig{Priority: "high"},
+	}})
+	if err != nil {
+		return err
+	}
+	endpoint := c.BaseURL + "/v1/projects/" + url.PathEscape(c.ProjectID) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.mu.Lock()
+		c.accessToken = ""
+		c.mu.Unlock()
+	}
+	var e errorResponse
+	_ = json.Unmarshal(respBody, &e)
+	code := e.Error.Status
+	for _, d := range e.Error.Details {
+		if d.ErrorCode != "" {
+			code = d.ErrorCode
+		}
+	}
+	switch {
+	case code == "UNREGISTERED", code == "SENDER_ID_MISMATCH":
+		return &push.InvalidTokenError{Reason: code}
+	case code == "INVALID_ARGUMENT" && strings.Contains(strings.ToLower(e.Error.Message), "registration token"):
+		return &push.InvalidTokenError{Reason: code}
+	}
+	return fmt.Errorf("fcm: status %d %s: %s", resp.StatusCode, code, e.Error.Message)
+}
+
+// token returns a cached OAuth access token, fetching a new one when it is missing or about to expire.
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken != "" && time.Until(c.expiresAt) > time.Minute {
+		return c.accessToken, nil
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   c.clientEmail,
+		"scope": messagingScope,
+		"aud":   c.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("fcm: sign assertion: %w", err)
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fcm: token: %w", err)
+	}
+	defer resp.Body.Close()
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || resp.StatusCode != http.StatusOK || out.AccessToken == "" {
+		return "", fmt.Errorf("fcm: token: status %d", resp.StatusCode)
+	}
+	c.accessToken = out.AccessToken
+	c.expiresAt = now.Add(time.Duration(out.ExpiresIn) * time.Second)
+	return c.accessToken, nil
+}
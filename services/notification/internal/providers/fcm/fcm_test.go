@@ -0,0 +1,101 @@
+package fcm
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeFCM serves the OAuth token endpoint and messages:send. Tokens named "gone" are UNREGISTERED.
+func fakeFCM(t *testing.T, pub *rsa.PublicKey, tokenCalls *int32, sent *[]string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(tokenCalls, 1)
+		assertion := r.PostFormValue("assertion")
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(assertion, claims, func(*jwt.Token) (interface{}, error) { return pub, nil }); err != nil {
+			t.Errorf("assertion: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if claims["iss"] != "push@payup.iam.gserviceaccount.com" || claims["aud"] != srv.URL+"/token" {
+			t.Errorf("assertion claims = %v", claims)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-1", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v1/projects/payup-test/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body sendRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		if body.Message.Token == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND",` +
+				`"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		if body.Message.Notification.Title != "Transfer successful" || body.Message.Data["transaction_ref"] != "TRF1" {
+			t.Errorf("message = %+v", body.Message)
+		}
+		*sent = append(*sent, body.Message.Token)
+		_, _ = w.Write([]byte(`{"name":"projects/payup-test/messages/1"}`))
+	})
+	return srv
+}
+
+func TestSend(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tokenCalls int32
+	var sent []string
+	srv := fakeFCM(t, &key.PublicKey, &tokenCalls, &sent)
+	defer srv.Close()
+
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	sa, _ := json.Marshal(ServiceAccount{
+		ProjectID:   "payup-test",
+		ClientEmail: "push@payup.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    srv.URL + "/token",
+	})
+	c, err := NewClient(sa, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := push.Message{Title: "Transfer successful", Body: "You sent NGN 5,000", Data: map[string]string{"transaction_ref": "TRF1"}}
+
+	for _, token := range []string{"device-a", "device-b"} {
+		if err := c.Send(context.Background(), token, msg); err != nil {
+			t.Fatalf("send %s: %v", token, err)
+		}
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent = %v, want both devices", sent)
+	}
+	if n := atomic.LoadInt32(&tokenCalls); n != 1 {
+		t.Fatalf("token endpoint called %d times, want 1 (access token cached)", n)
+	}
+
+	err = c.Send(context.Background(), "gone", msg)
+	if reason, ok := push.InvalidTokenReason(err); !ok || reason != "UNREGISTERED" {
+		t.Fatalf("unregistered token: err=%v, want InvalidTokenError UNREGISTERED", err)
+	}
+}
@@ -0,0 +1,49 @@
+package push
+
+import (
+	"context"
+	"errors"
+)
+
+// Platforms a device token can belong to. ios tokens go through APNs, android tokens through FCM.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// Message is what a push shows: a title, a body and string data the app reads when the notification is opened.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Device is one registered device token.
+type Device struct {
+	Token    string
+	Platform string
+}
+
+// Provider delivers a message to a single device token.
+type Provider interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// InvalidTokenError means the provider rejected the token for good (app uninstalled, token malformed or issued for
+// another app). The token should be removed; retrying will not help. Reason is the provider's code.
+type InvalidTokenError struct {
+	Reason string
+}
+
+func (e *InvalidTokenError) Error() string {
+	return "push: invalid device token: " + e.Reason
+}
+
+// InvalidTokenReason returns the provider's reason when err says the token is invalid.
+func InvalidTokenReason(err error) (string, bool) {
+	var invalid *InvalidTokenError
+	if errors.As(err, &invalid) {
+		return invalid.Reason, true
+	}
+	return "", false
+}
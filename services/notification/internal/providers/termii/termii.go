@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -38,10 +39,25 @@ type SendRequest struct {
 	Channel string `json:"channel"` // generic | dnd
 }
 
-// Send sends one SMS. Phone should be E.164 (e.g. 23490126727). Use channel "dnd" for OTP/transactional.
-func (c *Client) Send(phone, message, channel string) error {
+// APIError is a non-2xx reply from Termii. Temporary is true for rate limiting and server errors.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("termii: unexpected status %d body=%s", e.StatusCode, e.Body)
+}
+
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Send sends one SMS and returns Termii's message_id, which delivery reports refer to. Phone should be E.164 (e.g.
+// 23490126727). Use channel "dnd" for OTP/transactional.
+func (c *Client) Send(phone, message, channel string) (string, error) {
 	if c.APIKey == "" {
-		return fmt.Errorf("termii: missing API key")
+		return "", fmt.Errorf("termii: missing API key")
 	}
 	if channel == "" {
 		channel = "generic"
@@ -56,21 +72,26 @@ func (c *Client) Send(phone, message, channel string) error {
 	}
 	body, err := json.Marshal(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	url := c.BaseURL + "/api/sms/send"
 	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return "", err
 	}
 	httpReq.Header.Set("content-type", "application/json")
 	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("termii: unexpected status %d", resp.StatusCode)
+		return "", &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	var accepted struct {
+		MessageID string `json:"message_id"`
 	}
-	return nil
+	_ = json.Unmarshal(respBody, &accepted)
+	return accepted.MessageID, nil
 }
@@ -33,10 +33,11 @@ func NewClient(token, phoneID, apiVersion string) *Client {
 	}
 }
 
-// SendText sends a plain text message (only allowed within 24h customer reply window; otherwise use template).
-func (c *Client) SendText(toPhone, text string) error {
+// SendText sends a plain text message (only allowed within 24h customer reply window; otherwise use template). Like
+// every send it returns the WhatsApp message ID (wamid) that status webhooks refer to.
+func (c *Client) SendText(toPhone, text string) (string, error) {
 	if c.Token == "" || c.PhoneID == "" {
-		return fmt.Errorf("whatsapp: missing token or phone number id")
+		return "", fmt.Errorf("whatsapp: missing token or phone number id")
 	}
 	payload := map[string]interface{}{
 		"messaging_product": "whatsapp",
@@ -49,9 +50,9 @@ func (c *Client) SendText(toPhone, text string) error {
 }
 
 // SendTemplate sends a pre-approved template. components pass variables for {{1}}, {{2}}, etc.
-func (c *Client) SendTemplate(toPhone, templateName, langCode string, components []TemplateComponent) error {
+func (c *Client) SendTemplate(toPhone, templateName, langCode string, components []TemplateComponent) (string, error) {
 	if c.Token == "" || c.PhoneID == "" {
-		return fmt.Errorf("whatsapp: missing token or phone number id")
+		return "", fmt.Errorf("whatsapp: missing token or phone number id")
 	}
 	lang := langCode
 	if lang == "" {
@@ -74,9 +75,9 @@ func (c *Client) SendTemplate(toPhone, templateName, langCode string, components
 
 // SendOTP sends a template message with body (OTP text) and optional URL button (same OTP).
 // Template must have body {{1}} and optionally a URL button with dynamic part {{1}}.
-func (c *Client) SendOTP(toPhone, templateName, otp string) error {
+func (c *Client) SendOTP(toPhone, templateName, otp string) (string, error) {
 	if c.Token == "" || c.PhoneID == "" {
-		return fmt.Errorf("whatsapp: missing token or phone number id")
+		return "", fmt.Errorf("whatsapp: missing token or phone number id")
 	}
 	if templateName == "" {
 		templateName = "basic_otp"
@@ -102,23 +103,37 @@ type TemplateParam struct {
 	Text string `json:"text"`
 }
 
+// APIError is a non-2xx reply from the Graph API. Temporary is true for rate limiting and server errors.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
 // post sends POST to https://graph.facebook.com/{version}/{phone_id}/messages
 // with Authorization: Bearer <token> and JSON body (messaging_product, recipient_type, to, type, template).
-func (c *Client) post(payload map[string]interface{}) error {
+func (c *Client) post(payload map[string]interface{}) (string, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return "", err
 	}
 	reqURL := c.BaseURL + "/" + c.PhoneID + "/messages"
 	httpReq, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return "", err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+c.Token)
 	resp, err := c.HTTPClient.Do(httpReq)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -138,7 +153,16 @@ func (c *Client) post(payload map[string]interface{}) error {
 				msg = fmt.Sprintf("whatsapp: status %d body=%s", resp.StatusCode, string(body))
 			}
 		}
-		return fmt.Errorf("%s", msg)
+		return "", &APIError{StatusCode: resp.StatusCode, Message: msg}
+	}
+	var accepted struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&accepted)
+	if len(accepted.Messages) > 0 {
+		return accepted.Messages[0].ID, nil
 	}
-	return nil
+	return "", nil
 }
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Delivery statuses. pending is an attempt in flight; sent means the provider accepted it; delivered, read and failed
+// come from provider receipts; dead_letter means the consumer gave up and parked the event on the DLQ topic.
+const (
+	DeliveryPending    = "pending"
+	DeliverySent       = "sent"
+	DeliveryDelivered  = "delivered"
+	DeliveryRead       = "read"
+	DeliveryFailed     = "failed"
+	DeliverySkipped    = "skipped"
+	DeliveryDeadLetter = "dead_letter"
+)
+
+// Delivery is one notification event in the delivery log.
+type Delivery struct {
+	ID                string
+	UserID            string
+	Type              string
+	Channel           string
+	SentChannel       string
+	Provider          string
+	ProviderMessageID string
+	Status            string
+	Attempts          int
+	LastError         string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	SentAt            *time.Time
+	DeliveredAt       *time.Time
+	Route             []RouteHop
+	RecipientHash     string
+	RecipientMasked   string
+	Subject           string
+	// Payload is the event as JSON without its address, for resending; nil when the delivery cannot be resent.
+	Payload    json.RawMessage
+	ResentFrom string
+	ResentBy   string
+}
+
+// SentMessage is what MarkSent records about the message a provider accepted.
+type SentMessage struct {
+	Channel         string
+	Provider        string
+	MessageID       string
+	RecipientHash   string
+	RecipientMasked string
+	Subject         string
+}
+
+// RouteHop is one channel tried for a delivery: sent, failed (with the error) or skipped (circuit open, turned off).
+type RouteHop struct {
+	Channel  string    `json:"channel"`
+	Provider string    `json:"provider,omitempty"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// DeliveryFilter narrows List; empty fields match everything.
+type DeliveryFilter struct {
+	UserID        string
+	RecipientHash string
+	Type          string
+	Channel       string
+	Status        string
+}
+
+const deliveryColumns = `id, COALESCE(user_id::text, ''), type, channel, sent_channel, provider, provider_message_id, status,
+	attempts, last_error, created_at, updated_at, sent_at, delivered_at, route, recipient_hash, recipient_masked, subject,
+	payload, COALESCE(resent_from::text, ''), resent_by`
+
+// DeliveryRepository stores the delivery log.
+type DeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewDeliveryRepository returns a repository using db.
+func NewDeliveryRepository(db *sql.DB) *DeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+// StartAttempt records an attempt at delivering d: the first one inserts the row, later ones (retries, DLQ replays)
+// bump attempts and set it back to pending.
+func (r *DeliveryRepository) StartAttempt(ctx context.Context, d *Delivery, now time.Time) error {
+	var userID, payload, resentFrom interface{}
+	if d.UserID != "" {
+		userID = d.UserID
+	}
+	if len(d.Payload) > 0 {
+		payload = string(d.Payload)
+	}
+	if d.ResentFrom != "" {
+		resentFrom = d.ResentFrom
+	}
+	return r.db.QueryRowContext(ctx, `INSERT INTO notification_deliveries
+		(id, user_id, type, channel, status, attempts, payload, resent_from, resent_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'pending', 1, $5, $6, $7, $8, $8)
+		ON CONFLICT (id) DO UPDATE SET attempts = notification_deliveries.attempts + 1, status = 'pending', updated_at = $8
+		RETURNING attempts`,
+		d.ID, userID, d.Type, d.Channel, payload, resentFrom, d.ResentBy, now).Scan(&d.Attempts)
+}
+
+// MarkSent records the message a provider accepted. After a failover it replaces the earlier channel's provider,
+// message ID and recipient, so later receipts are for the channel that carried it.
+func (r *DeliveryRepository) MarkSent(ctx context.Context, id string, m SentMessage, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notification_deliveries
+		SET status = 'sent', sent_channel = $2, provider = $3, provider_message_id = $4, recipient_hash = $5,
+		    recipient_masked = $6, subject = $7, last_error = '', sent_at = $8, delivered_at = NULL, updated_at = $8
+		WHERE id = $1`, id, m.Channel, m.Provider, m.MessageID, m.RecipientHash, m.RecipientMasked, m.Subject, now)
+	return err
+}
+
+// AppendRoute adds the channels tried in one attempt or failover to the delivery's route.
+func (r *DeliveryRepository) AppendRoute(ctx context.Context, id string, hops []RouteHop) error {
+	if len(hops) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(hops)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE notification_deliveries SET route = route || $2::jsonb WHERE id = $1`, id, string(b))
+	return err
+}
+
+// Get returns one delivery, or nil when there is none.
+func (r *DeliveryRepository) Get(ctx context.Context, id string) (*Delivery, error) {
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM notification_deliveries WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// MarkOutcome sets a final status that did not come from a provider receipt: failed (attempt error), skipped or
+// dead_letter. reason is kept in last_error.
+func (r *DeliveryRepository) MarkOutcome(ctx context.Context, id, status, reason string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notification_deliveries SET status = $2, last_error = $3, updated_at = $4 WHERE id = $1`,
+		id, status, reason, now)
+	return err
+}
+
+// ApplyReceipt moves the delivery the provider knows as messageID to status (sent, delivered, read or failed). Receipts
+// can arrive out of order, so a status never goes backwards: delivered does not undo read, and failed only replaces
+// sent. Returns the delivery's ID, or "" when no delivery matched or the receipt was stale.
+func (r *DeliveryRepository) ApplyReceipt(ctx context.Context, provider, messageID, status, reason string, now time.Time) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `UPDATE notification_deliveries
+		SET status = $3,
+		    last_error = CASE WHEN $3 = 'failed' THEN $4 ELSE last_error END,
+		    delivered_at = CASE WHEN $3 IN ('delivered', 'read') THEN COALESCE(delivered_at, $5) ELSE delivered_at END,
+		    updated_at = $5
+		WHERE provider = $1 AND provider_message_id = $2
+		  AND (CASE status WHEN 'pending' THEN 0 WHEN 'sent' THEN 1 WHEN 'delivered' THEN 2 ELSE 3 END)
+		    < (CASE $3 WHEN 'sent' THEN 1 WHEN 'delivered' THEN 2 WHEN 'failed' THEN 2 ELSE 3 END)
+		RETURNING id`,
+		provider, messageID, status, reason, now).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return id, err
+}
+
+// List returns deliveries newest first and how many match the filter.
+func (r *DeliveryRepository) List(ctx context.Context, f DeliveryFilter, limit, offset int) ([]Delivery, int64, error) {
+	const where = `WHERE ($1 = '' OR user_id::text = $1) AND ($2 = '' OR recipient_hash = $2) AND ($3 = '' OR type = $3)
+		AND ($4 = '' OR channel = $4) AND ($5 = '' OR status = $5)`
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notification_deliveries `+where,
+		f.UserID, f.RecipientHash, f.Type, f.Channel, f.Status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT `+deliveryColumns+` FROM notification_deliveries `+where+`
+		ORDER BY created_at DESC LIMIT $6 OFFSET $7`,
+		f.UserID, f.RecipientHash, f.Type, f.Channel, f.Status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, *d)
+	}
+	return out, total, rows.Err()
+}
+
+func scanDelivery(row interface{ Scan(...any) error }) (*Delivery, error) {
+	var d Delivery
+	var sentAt, deliveredAt sql.NullTime
+	var route, payload []byte
+	if err := row.Scan(&d.ID, &d.UserID, &d.Type, &d.Channel, &d.SentChannel, &d.Provider, &d.ProviderMessageID, &d.Status,
+		&d.Attempts, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &sentAt, &deliveredAt, &route, &d.RecipientHash,
+		&d.RecipientMasked, &d.Subject, &payload, &d.ResentFrom, &d.ResentBy); err != nil {
+		return nil, err
+	}
+	if sentAt.Valid {
+		d.SentAt = &sentAt.Time
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	if len(payload) > 0 {
+		d.Payload = payload
+	}
+	if len(route) > 0 {
+		if err := json.Unmarshal(route, &d.Route); err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
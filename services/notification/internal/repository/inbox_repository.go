@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// InboxItem is one notification in a user's in-app inbox.
+type InboxItem struct {
+	ID        string
+	UserID    string
+	Type      string
+	Category  string
+	Title     string
+	Body      string
+	Data      json.RawMessage
+	Reference string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// InboxRepository stores the in-app inbox.
+type InboxRepository struct {
+	db *sql.DB
+}
+
+// NewInboxRepository returns a repository using db.
+func NewInboxRepository(db *sql.DB) *InboxRepository {
+	return &InboxRepository{db: db}
+}
+
+// Add files a notification in the user's inbox unless a notification of the same type and reference was filed for the
+// user within window (the same event fanned out to another channel). Returns false when it was a duplicate.
+func (r *InboxRepository) Add(ctx context.Context, item *InboxItem, window time.Duration) (bool, error) {
+	var data interface{}
+	if len(item.Data) > 0 {
+		data = []byte(item.Data)
+	}
+	err := r.db.QueryRowContext(ctx, `INSERT INTO inbox_notifications (user_id, type, category, title, body, data, reference)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE NOT EXISTS (
+			SELECT 1 FROM inbox_notifications
+			WHERE user_id = $1 AND type = $2 AND reference = $7 AND created_at > now() - make_interval(secs => $8)
+		)
+		RETURNING id, created_at`,
+		item.UserID, item.Type, item.Category, item.Title, item.Body, data, item.Reference, window.Seconds()).Scan(&item.ID, &item.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// List returns the user's notifications, newest first. unreadOnly leaves out read ones.
+func (r *InboxRepository) List(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]InboxItem, error) {
+	query := `SELECT id, user_id, type, category, title, body, data, reference, read_at, created_at
+		FROM inbox_notifications WHERE user_id = $1`
+	if unreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []InboxItem
+	for rows.Next() {
+		var it InboxItem
+		var data []byte
+		var readAt sql.NullTime
+		if err := rows.Scan(&it.ID, &it.UserID, &it.Type, &it.Category, &it.Title, &it.Body, &data, &it.Reference, &readAt, &it.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(data) > 0 {
+			it.Data = data
+		}
+		if readAt.Valid {
+			it.ReadAt = &readAt.Time
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
+
+// CountUnread returns how many of the user's notifications are unread.
+func (r *InboxRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&n)
+	return n, err
+}
+
+// SetRead marks one of the user's notifications read (read true) or unread. Returns false if the user has no such
+// notification.
+func (r *InboxRepository) SetRead(ctx context.Context, userID, id string, read bool, now time.Time) (bool, error) {
+	var readAt interface{}
+	if read {
+		readAt = now
+	}
+	var found string
+	err := r.db.QueryRowContext(ctx, `UPDATE inbox_notifications
+		SET read_at = CASE WHEN $3::timestamptz IS NULL THEN NULL ELSE COALESCE(read_at, $3::timestamptz) END
+		WHERE id = $1 AND user_id = $2
+		RETURNING id`, id, userID, readAt).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// MarkAllRead marks every unread notification of the user read. Returns how many changed.
+func (r *InboxRepository) MarkAllRead(ctx context.Context, userID string, now time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE inbox_notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL`, userID, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
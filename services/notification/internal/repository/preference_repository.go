@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Preference is a user's choice for one notification type on one channel.
+type Preference struct {
+	Type    string
+	Channel string
+	Enabled bool
+}
+
+// PreferenceRepository stores per-type, per-channel notification preferences. A missing row means enabled.
+type PreferenceRepository struct {
+	db *sql.DB
+}
+
+// NewPreferenceRepository returns a repository using db.
+func NewPreferenceRepository(db *sql.DB) *PreferenceRepository {
+	return &PreferenceRepository{db: db}
+}
+
+// Enabled reports whether the user wants type on channel (true when they never chose).
+func (r *PreferenceRepository) Enabled(ctx context.Context, userID, eventType, channel string) (bool, error) {
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, `SELECT enabled FROM notification_preferences WHERE user_id = $1 AND type = $2 AND channel = $3`,
+		userID, eventType, channel).Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	return enabled, err
+}
+
+// List returns the choices the user has made.
+func (r *PreferenceRepository) List(ctx context.Context, userID string) ([]Preference, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT type, channel, enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Preference
+	for rows.Next() {
+		var p Preference
+		if err := rows.Scan(&p.Type, &p.Channel, &p.Enabled); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// Set saves the given choices in one transaction.
+func (r *PreferenceRepository) Set(ctx context.Context, userID string, prefs []Preference, now time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, p := range prefs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO notification_preferences (user_id, type, channel, enabled, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, type, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at`,
+			userID, p.Type, p.Channel, p.Enabled, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Template statuses. Only one version per (type, channel, locale) is active at a time.
+const (
+	TemplateDraft   = "draft"
+	TemplateActive  = "active"
+	TemplateRetired = "retired"
+)
+
+// Template is one version of a notification template. Subject and Text are text/template sources, HTML is an
+// html/template source.
+type Template struct {
+	ID          string
+	Type        string
+	Channel     string
+	Locale      string
+	Version     int
+	Subject     string
+	HTML        string
+	Text        string
+	Status      string
+	CreatedBy   string
+	CreatedAt   time.Time
+	ActivatedAt *time.Time
+}
+
+const templateColumns = `id, type, channel, locale, version, subject, html, text, status, created_by, created_at, activated_at`
+
+// TemplateRepository stores template versions edited through the admin API.
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository returns a repository using db.
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Active returns the active version for type, channel and locale, or nil when there is none.
+func (r *TemplateRepository) Active(ctx context.Context, eventType, channel, locale string) (*Template, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+templateColumns+` FROM notification_templates
+		WHERE type = $1 AND channel = $2 AND locale = $3 AND status = 'active'`, eventType, channel, locale)
+	t, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return t, err
+}
+
+// Get returns the version with the given id, or nil.
+func (r *TemplateRepository) Get(ctx context.Context, id string) (*Template, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+templateColumns+` FROM notification_templates WHERE id = $1`, id)
+	t, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return t, err
+}
+
+// List returns every version, newest first, narrowed by whichever of type, channel and locale are non-empty.
+func (r *TemplateRepository) List(ctx context.Context, eventType, channel, locale string) ([]Template, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+templateColumns+` FROM notification_templates
+		WHERE ($1 = '' OR type = $1) AND ($2 = '' OR channel = $2) AND ($3 = '' OR locale = $3)
+		ORDER BY type, channel, locale, version DESC`, eventType, channel, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Template
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+// CreateDraft stores t as a draft with the next version number for its type, channel and locale, and fills in ID,
+// Version, Status and CreatedAt.
+func (r *TemplateRepository) CreateDraft(ctx context.Context, t *Template) error {
+	return r.db.QueryRowContext(ctx, `INSERT INTO notification_templates
+		(type, channel, locale, version, subject, html, text, status, created_by)
+		SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, $6, 'draft', $7
+		FROM notification_templates WHERE type = $1 AND channel = $2 AND locale = $3
+		RETURNING id, version, status, created_at`,
+		t.Type, t.Channel, t.Locale, t.Subject, t.HTML, t.Text, t.CreatedBy).Scan(&t.ID, &t.Version, &t.Status, &t.CreatedAt)
+}
+
+// Activate makes the version with the given id the active one for its type, channel and locale and retires the
+// version it replaces. Returns false when there is no such version.
+func (r *TemplateRepository) Activate(ctx context.Context, id string, now time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	var eventType, channel, locale string
+	err = tx.QueryRowContext(ctx, `SELECT type, channel, locale FROM notification_templates WHERE id = $1 FOR UPDATE`, id).
+		Scan(&eventType, &channel, &locale)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE notification_templates SET status = 'retired'
+		WHERE type = $1 AND channel = $2 AND locale = $3 AND status = 'active' AND id <> $4`,
+		eventType, channel, locale, id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE notification_templates SET status = 'active', activated_at = $2 WHERE id = $1`,
+		id, now); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func scanTemplate(row interface{ Scan(...any) error }) (*Template, error) {
+	var t Template
+	var activatedAt sql.NullTime
+	if err := row.Scan(&t.ID, &t.Type, &t.Channel, &t.Locale, &t.Version, &t.Subject, &t.HTML, &t.Text, &t.Status,
+		&t.CreatedBy, &t.CreatedAt, &activatedAt); err != nil {
+		return nil, err
+	}
+	if activatedAt.Valid {
+		t.ActivatedAt = &activatedAt.Time
+	}
+	return &t, nil
+}
@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/crypto"
+)
+
+// Partner webhook delivery statuses. pending covers both the first attempt and retries waiting on next_attempt_at.
+const (
+	WebhookPending   = "pending"
+	WebhookSucceeded = "succeeded"
+	WebhookFailed    = "failed"
+)
+
+// WebhookEndpoint is a URL an account registered for partner webhooks. Secret is the decrypted signing secret.
+type WebhookEndpoint struct {
+	ID          string
+	UserID      string
+	URL         string
+	Description string
+	Secret      string
+	EventTypes  []string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WebhookDelivery is one endpoint's copy of one event.
+type WebhookDelivery struct {
+	ID             string
+	EndpointID     string
+	UserID         string
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookAttempt is one POST of a delivery and how the endpoint answered.
+type WebhookAttempt struct {
+	Attempt      int
+	Manual       bool
+	StatusCode   int
+	Error        string
+	ResponseBody string
+	DurationMS   int
+	CreatedAt    time.Time
+}
+
+const webhookEndpointColumns = `id, user_id, url, description, secret_enc, event_types, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, user_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, created_at, updated_at, delivered_at`
+
+// WebhookRepository stores partner webhook endpoints, their deliveries and every attempt.
+type WebhookRepository struct {
+	db     *sql.DB
+	encKey string
+}
+
+// NewWebhookRepository returns a repository using db. encKey (64 hex chars) encrypts endpoint secrets.
+func NewWebhookRepository(db *sql.DB, encKey string) *WebhookRepository {
+	return &WebhookRepository{db: db, encKey: encKey}
+}
+
+// CreateEndpoint inserts e and fills in its ID and timestamps.
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, e *WebhookEndpoint) error {
+	secret, err := crypto.Encrypt([]byte(e.Secret), r.encKey)
+	if err != nil {
+		return err
+	}
+	types, err := json.Marshal(e.EventTypes)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, `INSERT INTO webhook_endpoints (user_id, url, description, secret_enc, event_types, active)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`,
+		e.UserID, e.URL, e.Description, secret, string(types), e.Active).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// ListEndpoints returns the account's endpoints, oldest first.
+func (r *WebhookRepository) ListEndpoints(ctx context.Context, userID string) ([]WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints WHERE user_id = $1
+		ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WebhookEndpoint
+	for rows.Next() {
+		e, err := r.scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *e)
+	}
+	return out, rows.Err()
+}
+
+// CountEndpoints returns how many endpoints the account has.
+func (r *WebhookRepository) CountEndpoints(ctx context.Context, userID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_endpoints WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+// GetEndpoint returns one endpoint, or nil when there is none. A non-empty userID must own it.
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, userID, id string) (*WebhookEndpoint, error) {
+	e, err := r.scanEndpoint(r.db.QueryRowContext(ctx, `SELECT `+webhookEndpointColumns+` FROM webhook_endpoints
+		WHERE id = $1 AND ($2 = '' OR user_id::text = $2)`, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+// UpdateEndpoint saves e's URL, description, event types and active flag. Returns false when the account has no such
+// endpoint.
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, e *WebhookEndpoint, now time.Time) (bool, error) {
+	types, err := json.Marshal(e.EventTypes)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE webhook_endpoints
+		SET url = $3, description = $4, event_types = $5, active = $6, updated_at = $7
+		WHERE id = $1 AND user_id = $2`, e.ID, e.UserID, e.URL, e.Description, string(types), e.Active, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SetSecret replaces the endpoint's signing secret. Returns false when the account has no such endpoint.
+func (r *WebhookRepository) SetSecret(ctx context.Context, userID, id, secret string, now time.Time) (bool, error) {
+	enc, err := crypto.Encrypt([]byte(secret), r.encKey)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE webhook_endpoints SET secret_enc = $3, updated_at = $4
+		WHERE id = $1 AND user_id = $2`, id, userID, enc, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteEndpoint removes the endpoint with its deliveries. Returns false when the account has no such endpoint.
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, userID, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Enqueue creates a pending delivery of the event for each of the account's active endpoints subscribed to its type,
+// due at now. An event already delivered to an endpoint is skipped. Returns how many deliveries were created.
+func (r *WebhookRepository) Enqueue(ctx context.Context, userID, eventID, eventType string, payload []byte, now time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO webhook_deliveries
+		(endpoint_id, user_id, event_id, event_type, payload, next_attempt_at, created_at, updated_at)
+		SELECT id, user_id, $2, $3, $4, $5, $5, $5 FROM webhook_endpoints
+		WHERE user_id = $1 AND active AND event_types ? $3
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+		userID, eventID, eventType, string(payload), now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ClaimDue returns up to limit pending deliveries due by now on active endpoints, and pushes their next_attempt_at
+// out by lease so another dispatcher (or replica) does not pick them up while they are being sent.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d JOIN webhook_endpoints e ON e.id = d.endpoint_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND e.active
+			ORDER BY d.next_attempt_at LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED)
+		RETURNING `+webhookDeliveryColumns, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// GetDelivery returns one delivery, or nil when there is none. A non-empty userID must own it.
+func (r *WebhookRepository) GetDelivery(ctx context.Context, userID, id string) (*WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE id = $1 AND ($2 = '' OR user_id::text = $2)`, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// ListDeliveries returns an endpoint's deliveries newest first and how many match. status is optional.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, userID, endpointID, status string, limit, offset int) ([]WebhookDelivery, int64, error) {
+	const where = `WHERE user_id = $1 AND endpoint_id = $2 AND ($3 = '' OR status = $3)`
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries `+where,
+		userID, endpointID, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries `+where+`
+		ORDER BY created_at DESC LIMIT $4 OFFSET $5`, userID, endpointID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, *d)
+	}
+	return out, total, rows.Err()
+}
+
+// RecordAttempt logs an attempt at delivery id and updates the delivery. decide gets the attempt's number and returns
+// the delivery's new status and, when it stays pending, when to try next.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id string, a WebhookAttempt, decide func(attempt int) (string, *time.Time), now time.Time) (*WebhookDelivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	var attempts int
+	if err := tx.QueryRowContext(ctx, `UPDATE webhook_deliveries SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`,
+		id).Scan(&attempts); err != nil {
+		return nil, err
+	}
+	status, next := decide(attempts)
+	var nextAt interface{}
+	if next != nil {
+		nextAt = *next
+	}
+	d, err := scanWebhookDelivery(tx.QueryRowContext(ctx, `UPDATE webhook_deliveries
+		SET status = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, updated_at = $6,
+		    delivered_at = CASE WHEN $2 = 'succeeded' THEN $6 ELSE delivered_at END
+		WHERE id = $1 RETURNING `+webhookDeliveryColumns, id, status, nextAt, a.StatusCode, a.Error, now))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO webhook_delivery_attempts
+		(delivery_id, attempt, manual, status_code, error, response_body, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		id, attempts, a.Manual, a.StatusCode, a.Error, a.ResponseBody, a.DurationMS, now); err != nil {
+		return nil, err
+	}
+	return d, tx.Commit()
+}
+
+// ListAttempts returns every attempt at a delivery, first to last.
+func (r *WebhookRepository) ListAttempts(ctx context.Context, deliveryID string) ([]WebhookAttempt, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT attempt, manual, status_code, error, response_body, duration_ms, created_at
+		FROM webhook_delivery_attempts WHERE delivery_id = $1 ORDER BY id`, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WebhookAttempt
+	for rows.Next() {
+		var a WebhookAttempt
+		if err := rows.Scan(&a.Attempt, &a.Manual, &a.StatusCode, &a.Error, &a.ResponseBody, &a.DurationMS, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *WebhookRepository) scanEndpoint(row interface{ Scan(...any) error }) (*WebhookEndpoint, error) {
+	var e WebhookEndpoint
+	var secret, types []byte
+	if err := row.Scan(&e.ID, &e.UserID, &e.URL, &e.Description, &secret, &types, &e.Active, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	plain, err := crypto.Decrypt(secret, r.encKey)
+	if err != nil {
+		return nil, err
+	}
+	e.Secret = string(plain)
+	if err := json.Unmarshal(types, &e.EventTypes); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func scanWebhookDelivery(row interface{ Scan(...any) error }) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	var next, deliveredAt sql.NullTime
+	var payload []byte
+	if err := row.Scan(&d.ID, &d.EndpointID, &d.UserID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &next,
+		&d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if next.Valid {
+		d.NextAttemptAt = &next.Time
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
@@ -10,5 +10,59 @@ import (
 func SetupRouter(ctrl *controller.Controller) *gin.Engine {
 	r := gin.Default()
 	r.GET("/health", ctrl.Health)
+
+	user := r.Group("/notifications", ctrl.RequireUser())
+	// User-authenticated (JWT). Inbox, newest first, with unread_count. Query: unread=true, limit (default 20, max 100), offset.
+	user.GET("", ctrl.ListNotifications)
+	// User-authenticated (JWT). Mark every unread notification read.
+	user.POST("/read-all", ctrl.MarkAllRead)
+	// User-authenticated (JWT). Every notification type with enabled flags per channel (email, sms, whatsapp, push, in_app).
+	user.GET("/preferences", ctrl.GetPreferences)
+	// User-authenticated (JWT). Body: preferences [{type, channel, enabled}]. Critical (security) types cannot be turned off.
+	user.PUT("/preferences", ctrl.UpdatePreferences)
+	// User-authenticated (JWT). Mark one notification read or unread.
+	user.POST("/:id/read", ctrl.MarkRead)
+	user.POST("/:id/unread", ctrl.MarkUnread)
+
+	// Partner webhooks (JWT): server-to-server callbacks for the account's events. Body for POST: url (https),
+	// description, event_types (transfer.success, wallet.credited, kyc.approved); the secret is returned once.
+	user.GET("/webhooks", ctrl.ListWebhookEndpoints)
+	user.POST("/webhooks", ctrl.CreateWebhookEndpoint)
+	user.GET("/webhooks/:id", ctrl.GetWebhookEndpoint)
+	// Body: any of url, description, event_types, active.
+	user.PATCH("/webhooks/:id", ctrl.UpdateWebhookEndpoint)
+	user.DELETE("/webhooks/:id", ctrl.DeleteWebhookEndpoint)
+	user.POST("/webhooks/:id/rotate-secret", ctrl.RotateWebhookSecret)
+	// Delivery log, newest first. Query: status (pending, succeeded, failed), limit, offset.
+	user.GET("/webhooks/:id/deliveries", ctrl.ListWebhookDeliveries)
+	// One delivery with its payload and every attempt; redeliver posts it again now.
+	user.GET("/webhooks/deliveries/:delivery_id", ctrl.GetWebhookDelivery)
+	user.POST("/webhooks/deliveries/:delivery_id/redeliver", ctrl.RedeliverWebhook)
+
+	// Provider delivery receipts. Public (nginx routes /v1/notification/webhooks/...); each checks its provider's
+	// token or signature.
+	webhooks := r.Group("/webhooks")
+	webhooks.POST("/brevo", ctrl.BrevoWebhook)
+	webhooks.POST("/termii", ctrl.TermiiWebhook)
+	webhooks.GET("/whatsapp", ctrl.WhatsAppWebhookVerify)
+	webhooks.POST("/whatsapp", ctrl.WhatsAppWebhook)
+
+	// Admin (X-Admin-Key). Internal only: nginx does not route /admin to this service.
+	admin := r.Group("/admin", ctrl.RequireAdminKey())
+	// Built-in defaults and stored versions. Query: type, channel, locale.
+	admin.GET("/templates", ctrl.ListTemplates)
+	// Body: type, channel, locale, subject, html, text, created_by. Stored as the next draft version.
+	admin.POST("/templates", ctrl.CreateTemplate)
+	// Body: id, or type + channel + locale; data (sample template variables). Renders without sending.
+	admin.POST("/templates/preview", ctrl.PreviewTemplate)
+	// Make a version live for its type, channel and locale; the previous one is retired. Also used to roll back.
+	admin.POST("/templates/:id/activate", ctrl.ActivateTemplate)
+	// Delivery log, newest first. Query: user_id, type, channel, status (pending, sent, delivered, read, failed,
+	// skipped, dead_letter), limit, offset.
+	admin.GET("/deliveries", ctrl.ListDeliveries)
+	// Body (optional): limit (default 100). Re-publishes parked events from notification-events-dlq.
+	admin.POST("/dlq/replay", ctrl.ReplayDeadLetters)
+	// Failover policy per type (channels in order, with the delay before each) and provider circuit breaker states.
+	admin.GET("/routing", ctrl.GetRouting)
 	return r
 }
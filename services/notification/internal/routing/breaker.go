@@ -0,0 +1,151 @@
+package routing
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling a provider whose breaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Breaker states.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Breaker stops traffic to a provider after threshold consecutive failures. Once cooldown has passed it lets one trial
+// request through (half open): success closes it, failure opens it for another cooldown. A trial whose outcome is never
+// reported expires after cooldown so the breaker cannot stick half open.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     string
+	failures  int
+	openedAt  time.Time
+	trialAt   time.Time
+	now       func() time.Time
+}
+
+// NewBreaker returns a closed breaker. threshold below 1 means 1.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown, state: StateClosed, now: time.Now}
+}
+
+// Allow reports whether a request may go to the provider now. A nil breaker allows everything.
+func (b *Breaker) Allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state, b.trialAt = StateHalfOpen, now
+		return true
+	case StateHalfOpen:
+		if now.Sub(b.trialAt) < b.cooldown {
+			return false
+		}
+		b.trialAt = now
+		return true
+	}
+	return true
+}
+
+// Success records a request the provider handled and closes the breaker.
+func (b *Breaker) Success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state, b.failures = StateClosed, 0
+}
+
+// Failure records a request the provider failed (network error, rate limit, 5xx).
+func (b *Breaker) Failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt = StateOpen, b.now()
+	}
+}
+
+// BreakerState is a breaker as shown on the admin API.
+type BreakerState struct {
+	Provider string     `json:"provider"`
+	State    string     `json:"state"`
+	Failures int        `json:"failures"`
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+}
+
+// Breakers holds one breaker per provider, created on first use.
+type Breakers struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	byName    map[string]*Breaker
+}
+
+// NewBreakers returns a set whose breakers open after threshold consecutive failures and stay open for cooldown.
+func NewBreakers(threshold int, cooldown time.Duration) *Breakers {
+	return &Breakers{threshold: threshold, cooldown: cooldown, byName: make(map[string]*Breaker)}
+}
+
+// For returns provider's breaker. A nil set returns a nil breaker, which allows everything.
+func (s *Breakers) For(provider string) *Breaker {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.byName[provider]
+	if !ok {
+		b = NewBreaker(s.threshold, s.cooldown)
+		s.byName[provider] = b
+	}
+	return b
+}
+
+// States returns every breaker used so far, by provider name.
+func (s *Breakers) States() []BreakerState {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	names := make([]string, 0, len(s.byName))
+	for name := range s.byName {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	sort.Strings(names)
+	out := make([]BreakerState, 0, len(names))
+	for _, name := range names {
+		b := s.For(name)
+		b.mu.Lock()
+		st := BreakerState{Provider: name, State: b.state, Failures: b.failures}
+		if b.state != StateClosed {
+			at := b.openedAt
+			st.OpenedAt = &at
+		}
+		b.mu.Unlock()
+		out = append(out, st)
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+// Package routing decides which channels a notification may fall back to and keeps the provider circuit breakers that
+// let a failing provider be skipped instead of waited on.
+package routing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+)
+
+// Step is one channel in a policy. After is how long the previous step may go without a delivery receipt before this
+// one is tried; an error on the previous step moves on at once. The first step's After is ignored.
+type Step struct {
+	Channel string        `json:"channel"`
+	After   time.Duration `json:"after"`
+}
+
+// Policy is the channel priority for one notification type.
+type Policy struct {
+	Type  string `json:"type"`
+	Steps []Step `json:"steps"`
+}
+
+// Policies are keyed by notification type. Types without one go out on the event's channel only.
+type Policies map[string]Policy
+
+// DefaultPolicies cover the one-time codes. WhatsApp is tried first because it is cheapest and most users have it,
+// but its template sends are the ones most often rejected. Login and step-up codes can fall back to email, both
+// addresses being already verified; codes that prove a phone number stay on the phone.
+func DefaultPolicies() Policies {
+	phone := []Step{
+		{Channel: model.ChannelWhatsApp},
+		{Channel: model.ChannelSMS, After: 30 * time.Second},
+	}
+	account := append(append([]Step(nil), phone...), Step{Channel: model.ChannelEmail, After: 30 * time.Second})
+	return Policies{
+		"kyc_phone_otp":    {Type: "kyc_phone_otp", Steps: phone},
+		"phone_change_otp": {Type: "phone_change_otp", Steps: phone},
+		"2fa_login_otp":    {Type: "2fa_login_otp", Steps: account},
+		"step_up_otp":      {Type: "step_up_otp", Steps: account},
+	}
+}
+
+// Next returns the steps that follow channel in the type's policy. An event sent on a channel that is not in the
+// policy has no fallbacks; one sent on a later channel (a user who picked SMS) skips the earlier ones.
+func (p Policies) Next(eventType, channel string) []Step {
+	policy, ok := p[eventType]
+	if !ok {
+		return nil
+	}
+	for i, st := range policy.Steps {
+		if st.Channel == channel {
+			return policy.Steps[i+1:]
+		}
+	}
+	return nil
+}
+
+// providerChannels lets a policy name the provider instead of its channel; each channel has one provider.
+var providerChannels = map[string]string{
+	"brevo":  model.ChannelEmail,
+	"termii": model.ChannelSMS,
+}
+
+// ParsePolicies reads policies in the NOTIFICATION_ROUTES format: semicolon-separated "type=step,step,...", each
+// step a channel (or provider) with an optional "@delay", e.g. "kyc_phone_otp=whatsapp,sms@30s,email@1m".
+func ParsePolicies(s string) (Policies, error) {
+	out := Policies{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		typ, steps, ok := strings.Cut(entry, "=")
+		typ = strings.TrimSpace(typ)
+		if !ok || typ == "" {
+			return nil, fmt.Errorf("route %q: want type=channel,channel@delay,...", entry)
+		}
+		policy := Policy{Type: typ}
+		seen := map[string]bool{}
+		for _, raw := range strings.Split(steps, ",") {
+			name, delay, hasDelay := strings.Cut(strings.TrimSpace(raw), "@")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if c, ok := providerChannels[name]; ok {
+				name = c
+			}
+			switch name {
+			case model.ChannelEmail, model.ChannelSMS, model.ChannelWhatsApp, model.ChannelPush:
+			default:
+				return nil, fmt.Errorf("route %s: unknown channel %q", typ, name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("route %s: %s listed twice", typ, name)
+			}
+			seen[name] = true
+			st := Step{Channel: name}
+			if hasDelay {
+				d, err := time.ParseDuration(strings.TrimSpace(delay))
+				if err != nil || d < 0 {
+					return nil, fmt.Errorf("route %s: bad delay %q", typ, delay)
+				}
+				st.After = d
+			}
+			policy.Steps = append(policy.Steps, st)
+		}
+		out[typ] = policy
+	}
+	return out, nil
+}
+
+// Merge returns p with every policy in override replacing p's for the same type.
+func (p Policies) Merge(override Policies) Policies {
+	out := make(Policies, len(p)+len(override))
+	for k, v := range p {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package routing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePolicies(t *testing.T) {
+	p, err := ParsePolicies("kyc_phone_otp=whatsapp, termii@45s ,brevo@1m; bill_token=sms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := p["kyc_phone_otp"].Steps
+	want := []Step{{Channel: "whatsapp"}, {Channel: "sms", After: 45 * time.Second}, {Channel: "email", After: time.Minute}}
+	if len(got) != len(want) {
+		t.Fatalf("steps = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(p["bill_token"].Steps) != 1 {
+		t.Errorf("bill_token = %+v", p["bill_token"])
+	}
+
+	for _, bad := range []string{"otp", "otp=fax", "otp=sms,sms", "otp=sms@soon"} {
+		if _, err := ParsePolicies(bad); err == nil {
+			t.Errorf("ParsePolicies(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	p := DefaultPolicies()
+	if next := p.Next("2fa_login_otp", "whatsapp"); len(next) != 2 || next[0].Channel != "sms" || next[1].Channel != "email" {
+		t.Errorf("from whatsapp: %+v", next)
+	}
+	if next := p.Next("2fa_login_otp", "sms"); len(next) != 1 || next[0].Channel != "email" {
+		t.Errorf("from sms: %+v", next)
+	}
+	if next := p.Next("kyc_phone_otp", "whatsapp"); len(next) != 1 || next[0].Channel != "sms" {
+		t.Errorf("phone codes must stay on the phone: %+v", next)
+	}
+	if next := p.Next("2fa_login_otp", "push"); next != nil {
+		t.Errorf("channel outside the policy: %+v", next)
+	}
+	if next := p.Next("wallet_credit", "email"); next != nil {
+		t.Errorf("type without a policy: %+v", next)
+	}
+	merged := p.Merge(Policies{"kyc_phone_otp": {Type: "kyc_phone_otp", Steps: []Step{{Channel: "sms"}}}})
+	if next := merged.Next("kyc_phone_otp", "whatsapp"); next != nil {
+		t.Errorf("override ignored: %+v", next)
+	}
+	if len(p["kyc_phone_otp"].Steps) != 2 {
+		t.Error("Merge modified the receiver")
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("opened below threshold")
+	}
+	b.Success()
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("success did not reset the count")
+	}
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("not open at threshold")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("no trial after cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("second request during the trial")
+	}
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("failed trial did not reopen")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("no trial after second cooldown")
+	}
+	b.Success()
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("successful trial did not close")
+	}
+
+	var nilBreaker *Breaker
+	if !nilBreaker.Allow() {
+		t.Error("nil breaker blocked")
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/routing"
+	"github.com/google/uuid"
+)
+
+// Providers as recorded in the delivery log and named by the receipt webhooks.
+const (
+	ProviderBrevo    = "brevo"
+	ProviderTermii   = "termii"
+	ProviderWhatsApp = "whatsapp"
+	ProviderPush     = "push"
+)
+
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// DeadLetterQueue re-publishes events parked on the dead-letter topic back onto notification-events.
+type DeadLetterQueue interface {
+	Replay(ctx context.Context, limit int) (int, error)
+}
+
+// DeliveryConfig wires the delivery log and the dead-letter queue. Without Log, events are sent but not recorded.
+// RecipientKey keys the recipient hashes in the log; see HashRecipient.
+type DeliveryConfig struct {
+	Log          *repository.DeliveryRepository
+	DeadLetters  DeadLetterQueue
+	RecipientKey []byte
+}
+
+// sendResult is what one attempt did: the channel and provider that accepted the message, its ID for receipts and
+// who it went to, or why nothing was sent. hops lists every channel tried; next holds the policy steps left for a timed
+// failover of event.
+type sendResult struct {
+	channel   string
+	provider  string
+	messageID string
+	recipient string
+	subject   string
+	skipped   string
+	hops      []repository.RouteHop
+	next      []routing.Step
+	event     model.NotificationEvent
+}
+
+// permanentError marks a failure that sending again cannot fix: a missing address, an unknown channel, a provider that
+// is not configured, a template the event's data does not satisfy.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retryable reports whether another attempt at the event might succeed. Provider errors say so themselves through
+// Temporary (rate limits and 5xx are retryable, a rejected request is not); network errors and anything else unknown
+// are retried.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var p *permanentError
+	if errors.As(err, &p) {
+		return false
+	}
+	var t interface{ Temporary() bool }
+	if errors.As(err, &t) {
+		return t.Temporary()
+	}
+	return true
+}
+
+// Deliver makes one attempt at the event and records it in the delivery log under deliveryID, which stays the same
+// across the consumer's retries and DLQ replays. The returned error is the attempt's; see Retryable.
+func (s *NotificationService) Deliver(ctx context.Context, deliveryID string, event model.NotificationEvent) error {
+	return s.deliver(ctx, &repository.Delivery{ID: deliveryID}, event)
+}
+
+func (s *NotificationService) deliver(ctx context.Context, d *repository.Delivery, event model.NotificationEvent) error {
+	logged := s.startAttempt(ctx, d, event)
+	res, err := s.process(ctx, event)
+	if !logged {
+		return err
+	}
+	now := time.Now().UTC()
+	if logErr := s.delivery.Log.AppendRoute(ctx, d.ID, res.hops); logErr != nil {
+		log.Printf("notification: delivery log update failed id=%s err=%v", d.ID, logErr)
+	}
+	var logErr error
+	switch {
+	case err != nil:
+		logErr = s.delivery.Log.MarkOutcome(ctx, d.ID, repository.DeliveryFailed, err.Error(), now)
+	case res.skipped != "":
+		logErr = s.delivery.Log.MarkOutcome(ctx, d.ID, repository.DeliverySkipped, res.skipped, now)
+	default:
+		logErr = s.delivery.Log.MarkSent(ctx, d.ID, s.sentMessage(res), now)
+		s.scheduleEscalation(d.ID, res)
+	}
+	if logErr != nil {
+		log.Printf("notification: delivery log update failed id=%s err=%v", d.ID, logErr)
+	}
+	return err
+}
+
+// DeadLettered records that the consumer gave up on the delivery and parked the event on the dead-letter topic.
+func (s *NotificationService) DeadLettered(ctx context.Context, deliveryID string, cause error) {
+	if s.delivery.Log == nil || deliveryID == "" {
+		return
+	}
+	if err := s.delivery.Log.MarkOutcome(ctx, deliveryID, repository.DeliveryDeadLetter, cause.Error(), time.Now().UTC()); err != nil {
+		log.Printf("notification: delivery log update failed id=%s err=%v", deliveryID, err)
+	}
+}
+
+// startAttempt fills in d from the event (keeping the payload for resending where allowed) and records the attempt.
+func (s *NotificationService) startAttempt(ctx context.Context, d *repository.Delivery, event model.NotificationEvent) bool {
+	if s.delivery.Log == nil || d.ID == "" {
+		return false
+	}
+	s.escalations.cancel(d.ID)
+	d.Type, d.Channel = event.Type, event.Channel
+	if _, err := uuid.Parse(event.UserID); err == nil {
+		d.UserID = event.UserID
+		d.Payload = resendPayload(event)
+	}
+	if err := s.delivery.Log.StartAttempt(ctx, d, time.Now().UTC()); err != nil {
+		log.Printf("notification: delivery log insert failed id=%s err=%v", d.ID, err)
+		return false
+	}
+	return true
+}
+
+// receiptStatuses maps each provider's delivery-report vocabulary (lowercased) onto the delivery log. Anything not
+// listed (Brevo deferred and soft_bounce, which Brevo retries itself; spam complaints; unsubscribes) leaves the status
+// alone.
+var receiptStatuses = map[string]map[string]string{
+	ProviderBrevo: {
+		"request":       repository.DeliverySent,
+		"delivered":     repository.DeliveryDelivered,
+		"opened":        repository.DeliveryRead,
+		"unique_opened": repository.DeliveryRead,
+		"click":         repository.DeliveryRead,
+		"hard_bounce":   repository.DeliveryFailed,
+		"invalid_email": repository.DeliveryFailed,
+		"blocked":       repository.DeliveryFailed,
+		"error":         repository.DeliveryFailed,
+	},
+	ProviderTermii: {
+		"message sent":               repository.DeliverySent,
+		"sent":                       repository.DeliverySent,
+		"delivered":                  repository.DeliveryDelivered,
+		"message failed":             repository.DeliveryFailed,
+		"failed":                     repository.DeliveryFailed,
+		"rejected":                   repository.DeliveryFailed,
+		"expired":                    repository.DeliveryFailed,
+		"dnd active on phone number": repository.DeliveryFailed,
+	},
+	ProviderWhatsApp: {
+		"sent":      repository.DeliverySent,
+		"delivered": repository.DeliveryDelivered,
+		"read":      repository.DeliveryRead,
+		"failed":    repository.DeliveryFailed,
+	},
+}
+
+// ReceiptStatus maps a provider's report status onto the delivery log; ok is false for statuses that change nothing.
+func ReceiptStatus(provider, providerStatus string) (string, bool) {
+	st, ok := receiptStatuses[provider][strings.ToLower(strings.TrimSpace(providerStatus))]
+	return st, ok
+}
+
+// ApplyReceipt records a provider delivery report against the delivery it sent as messageID.
+func (s *NotificationService) ApplyReceipt(ctx context.Context, provider, messageID, providerStatus, reason string) error {
+	if _, known := receiptStatuses[provider]; !known {
+		return ErrUnknownProvider
+	}
+	status, ok := ReceiptStatus(provider, providerStatus)
+	if !ok || messageID == "" || s.delivery.Log == nil {
+		return nil
+	}
+	if reason == "" && status == repository.DeliveryFailed {
+		reason = providerStatus
+	}
+	id, err := s.delivery.Log.ApplyReceipt(ctx, provider, messageID, status, reason, time.Now().UTC())
+	if err != nil || id == "" {
+		return err
+	}
+	log.Printf("notification: receipt provider=%s message_id=%s status=%s", provider, messageID, status)
+	// A failed message fails over now rather than when its delay runs out; a delivered one needs no fallback.
+	if status == repository.DeliveryFailed {
+		s.escalations.fire(id)
+	} else if status != repository.DeliverySent {
+		s.escalations.cancel(id)
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log newest first and the number matching, for support and to find dead letters.
+func (s *NotificationService) ListDeliveries(ctx context.Context, f repository.DeliveryFilter, limit, offset int) ([]repository.Delivery, int64, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.delivery.Log.List(ctx, f, limit, offset)
+}
+
+// ReplayDeadLetters re-publishes up to limit parked events onto notification-events, oldest first, and returns how
+// many were replayed. Each keeps its delivery ID, so the log shows the extra attempts on the same row.
+func (s *NotificationService) ReplayDeadLetters(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	if s.delivery.DeadLetters == nil {
+		return 0, errors.New("dead-letter queue not configured")
+	}
+	return s.delivery.DeadLetters.Replay(ctx, limit)
+}
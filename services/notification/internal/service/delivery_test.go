@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/brevo"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/whatsapp"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+)
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"network", errors.New("dial tcp: connection refused"), true},
+		{"permanent", Permanent(errors.New("missing phone")), false},
+		{"wrapped permanent", fmt.Errorf("send: %w", Permanent(errors.New("no provider"))), false},
+		{"rate limited", &brevo.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"provider down", fmt.Errorf("whatsapp: %w", &whatsapp.APIError{StatusCode: http.StatusBadGateway}), true},
+		{"rejected", &brevo.APIError{StatusCode: http.StatusBadRequest}, false},
+	}
+	for _, tc := range cases {
+		if got := Retryable(tc.err); got != tc.want {
+			t.Errorf("%s: Retryable = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReceiptStatus(t *testing.T) {
+	cases := []struct {
+		provider, status string
+		want             string
+		ok               bool
+	}{
+		{ProviderBrevo, "delivered", repository.DeliveryDelivered, true},
+		{ProviderBrevo, "hard_bounce", repository.DeliveryFailed, true},
+		{ProviderBrevo, "soft_bounce", "", false},
+		{ProviderTermii, "Delivered", repository.DeliveryDelivered, true},
+		{ProviderTermii, "DND Active on Phone Number", repository.DeliveryFailed, true},
+		{ProviderWhatsApp, "read", repository.DeliveryRead, true},
+		{ProviderWhatsApp, "deleted", "", false},
+		{ProviderPush, "delivered", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := ReceiptStatus(tc.provider, tc.status)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ReceiptStatus(%s, %q) = %q, %v; want %q, %v", tc.provider, tc.status, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestProcessPermanentErrors(t *testing.T) {
+	s := NewNotificationService(nil, nil, nil, "", nil, nil, PushConfig{}, TemplateConfig{}, DeliveryConfig{}, RoutingConfig{}, WebhookConfig{})
+	for _, ev := range []model.NotificationEvent{
+		{Type: "otp", Channel: "carrier_pigeon"},
+		{Type: "otp", Channel: "email", Metadata: map[string]interface{}{"to": "a@example.com", "subject": "x", "html": "y"}},
+		{Type: "otp", Channel: "sms", Metadata: map[string]interface{}{"message": "hi"}},
+	} {
+		_, err := s.process(context.Background(), ev)
+		if err == nil || Retryable(err) {
+			t.Errorf("%s/%s: err = %v, want a permanent error", ev.Type, ev.Channel, err)
+		}
+	}
+}
+
+func TestApplyReceiptUnknownProvider(t *testing.T) {
+	s := NewNotificationService(nil, nil, nil, "", nil, nil, PushConfig{}, TemplateConfig{}, DeliveryConfig{}, RoutingConfig{}, WebhookConfig{})
+	if err := s.ApplyReceipt(context.Background(), "pigeon", "m1", "delivered", ""); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("err = %v, want ErrUnknownProvider", err)
+	}
+	if err := s.ApplyReceipt(context.Background(), ProviderBrevo, "m1", "delivered", ""); err != nil {
+		t.Fatalf("without a delivery log: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrNotResendable    = errors.New("notification cannot be resent")
+)
+
+// GetDelivery returns one delivery from the log.
+func (s *NotificationService) GetDelivery(ctx context.Context, id string) (*repository.Delivery, error) {
+	if s.delivery.Log == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	d, err := s.delivery.Log.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+// Resend sends the delivery's event again, now and to the user's current address, as a new delivery linked to the
+// original. One-time codes and events without a user are never stored for resending. A failed send is not an error
+// here: the returned delivery says how it went.
+func (s *NotificationService) Resend(ctx context.Context, id, adminID string) (*repository.Delivery, error) {
+	orig, err := s.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(orig.Payload) == 0 {
+		return nil, fmt.Errorf("%w: one-time codes and notifications without a user are not kept", ErrNotResendable)
+	}
+	var event model.NotificationEvent
+	if err := json.Unmarshal(orig.Payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotResendable, err)
+	}
+	d := &repository.Delivery{ID: uuid.NewString(), ResentFrom: orig.ID, ResentBy: adminID}
+	_ = s.deliver(ctx, d, event)
+	return s.GetDelivery(ctx, d.ID)
+}
+
+// resendPayload is the event as kept for resending: nil for one-time codes (a resent code is stale, and codes are not
+// stored), otherwise without its address, which is looked up again when resending.
+func resendPayload(event model.NotificationEvent) []byte {
+	if info, _ := model.LookupType(event.Type); info.Secret {
+		return nil
+	}
+	stored := event
+	stored.Metadata = make(map[string]interface{}, len(event.Metadata))
+	for k, v := range event.Metadata {
+		if k != "to" && k != "to_name" {
+			stored.Metadata[k] = v
+		}
+	}
+	b, err := json.Marshal(stored)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// sentMessage is what the delivery log keeps about a sent message: the recipient only as a hash and a masked value,
+// and no subject for one-time codes.
+func (s *NotificationService) sentMessage(res sendResult) repository.SentMessage {
+	m := repository.SentMessage{Channel: res.channel, Provider: res.provider, MessageID: res.messageID}
+	if res.recipient != "" {
+		m.RecipientHash = s.HashRecipient(res.recipient)
+		m.RecipientMasked = maskRecipient(res.recipient)
+	}
+	if info, _ := model.LookupType(res.event.Type); !info.Secret {
+		m.Subject = truncate(res.subject, 255)
+	}
+	return m
+}
+
+// HashRecipient is the keyed hash the delivery log stores for an email address or phone number. Support looks a
+// recipient up by hashing what the customer gives them; the key stops anyone with the table enumerating phone numbers.
+func (s *NotificationService) HashRecipient(recipient string) string {
+	mac := hmac.New(sha256.New, s.delivery.RecipientKey)
+	mac.Write([]byte(normalizeRecipient(recipient)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// normalizeRecipient makes the forms one address is written in hash alike: emails lowercased, phone numbers as digits
+// in international form (08012345678 and +234 801 234 5678 both become 2348012345678).
+func normalizeRecipient(recipient string) string {
+	recipient = strings.TrimSpace(recipient)
+	if strings.Contains(recipient, "@") {
+		return strings.ToLower(recipient)
+	}
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, recipient)
+	if len(digits) == 11 && digits[0] == '0' {
+		digits = "234" + digits[1:]
+	}
+	return digits
+}
+
+// maskRecipient keeps enough to recognise an address: a***@example.com, or the last four digits of a phone number.
+func maskRecipient(recipient string) string {
+	recipient = strings.TrimSpace(recipient)
+	if at := strings.IndexByte(recipient, '@'); at >= 0 {
+		if at == 0 {
+			return "***" + recipient[at:]
+		}
+		return recipient[:1] + "***" + recipient[at:]
+	}
+	digits := normalizeRecipient(recipient)
+	if len(digits) <= 4 {
+		return "****"
+	}
+	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8Start(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func utf8Start(b byte) bool { return b&0xC0 != 0x80 }
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+)
+
+func TestHashRecipientNormalizes(t *testing.T) {
+	s := &NotificationService{delivery: DeliveryConfig{RecipientKey: []byte("test-key")}}
+	if s.HashRecipient("08012345678") != s.HashRecipient("+234 801 234 5678") {
+		t.Error("local and international forms of a phone number hash differently")
+	}
+	if s.HashRecipient("Ada@Example.com ") != s.HashRecipient("ada@example.com") {
+		t.Error("email hash is case sensitive")
+	}
+	other := &NotificationService{delivery: DeliveryConfig{RecipientKey: []byte("other-key")}}
+	if s.HashRecipient("ada@example.com") == other.HashRecipient("ada@example.com") {
+		t.Error("hash does not depend on the key")
+	}
+}
+
+func TestMaskRecipient(t *testing.T) {
+	cases := map[string]string{
+		"ada@example.com": "a***@example.com",
+		"08012345678":     "*********5678",
+		"+2348012345678":  "*********5678",
+		"123":             "****",
+	}
+	for in, want := range cases {
+		if got := maskRecipient(in); got != want {
+			t.Errorf("maskRecipient(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestResendPayload(t *testing.T) {
+	if p := resendPayload(model.NotificationEvent{Type: "2fa_login_otp", Metadata: map[string]interface{}{"otp": "123456"}}); p != nil {
+		t.Errorf("one-time code kept for resending: %s", p)
+	}
+	event := model.NotificationEvent{
+		UserID:   "6f1c1b8e-6a8e-4a4f-9d5e-1f1b1f1b1f1b",
+		Type:     "transfer_success",
+		Channel:  model.ChannelEmail,
+		Metadata: map[string]interface{}{"to": "ada@example.com", "to_name": "Ada", "subject": "Transfer sent"},
+	}
+	var stored model.NotificationEvent
+	if err := json.Unmarshal(resendPayload(event), &stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored.Metadata["to"]; ok {
+		t.Error("address kept in resend payload")
+	}
+	if _, ok := stored.Metadata["to_name"]; ok {
+		t.Error("name kept in resend payload")
+	}
+	if stored.Metadata["subject"] != "Transfer sent" || stored.UserID != event.UserID {
+		t.Errorf("stored event = %+v", stored)
+	}
+	if event.Metadata["to"] != "ada@example.com" {
+		t.Error("resendPayload changed the event being sent")
+	}
+}
+
+func TestSentMessageMinimizesRecipient(t *testing.T) {
+	s := &NotificationService{}
+	m := s.sentMessage(sendResult{
+		channel:   model.ChannelSMS,
+		recipient: "08012345678",
+		subject:   "Your code",
+		event:     model.NotificationEvent{Type: "kyc_phone_otp"},
+	})
+	if m.RecipientMasked != "*********5678" || m.RecipientHash == "" || m.RecipientHash == "08012345678" {
+		t.Errorf("recipient = %q / %q", m.RecipientMasked, m.RecipientHash)
+	}
+	if m.Subject != "" {
+		t.Errorf("subject kept for a one-time code: %q", m.Subject)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/google/uuid"
+)
+
+// inboxDedupeWindow is how long a stored notification suppresses copies of the same type and reference, so an alert
+// sent on email and WhatsApp shows up once in the inbox.
+const inboxDedupeWindow = 5 * time.Minute
+
+var (
+	ErrNotificationNotFound    = errors.New("notification not found")
+	ErrUnknownNotificationType = errors.New("unknown notification type")
+	ErrUnknownChannel          = errors.New("unknown channel")
+	ErrCriticalNotification    = errors.New("security notifications cannot be turned off")
+)
+
+// inboxDataKeys are the metadata fields copied into an inbox item for deep links. Recipient addresses, HTML and
+// template details stay out.
+var inboxDataKeys = []string{"amount", "transaction_ref", "reference", "request_ref", "dispute_ref", "bill_ref", "narration", "beneficiary"}
+
+// inboxRefKeys are the metadata fields, in order, that identify the subject of a notification for de-duplication.
+var inboxRefKeys = []string{"transaction_ref", "reference", "request_ref", "dispute_ref", "bill_ref"}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// inboxStore keeps the in-app inbox; *repository.InboxRepository is one.
+type inboxStore interface {
+	Add(ctx context.Context, item *repository.InboxItem, window time.Duration) (bool, error)
+	List(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]repository.InboxItem, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	SetRead(ctx context.Context, userID, id string, read bool, now time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID string, now time.Time) (int64, error)
+}
+
+// preferenceStore keeps the per-channel choices; *repository.PreferenceRepository is one.
+type preferenceStore interface {
+	Enabled(ctx context.Context, userID, eventType, channel string) (bool, error)
+	List(ctx context.Context, userID string) ([]repository.Preference, error)
+	Set(ctx context.Context, userID string, prefs []repository.Preference, now time.Time) error
+}
+
+// PreferenceView is one catalog type with the user's choice per channel.
+type PreferenceView struct {
+	Type     string          `json:"type"`
+	Category string          `json:"category"`
+	Critical bool            `json:"critical"`
+	Channels map[string]bool `json:"channels"`
+}
+
+// channelEnabled reports whether the user wants eventType on channel. A failed lookup sends anyway.
+func (s *NotificationService) channelEnabled(ctx context.Context, userID, eventType, channel string) bool {
+	if s.prefs == nil {
+		return true
+	}
+	enabled, err := s.prefs.Enabled(ctx, userID, eventType, channel)
+	if err != nil {
+		log.Printf("notification: preference lookup failed user=%s type=%s err=%v", userID, eventType, err)
+		return true
+	}
+	return enabled
+}
+
+// fileInInbox stores the notification in the user's inbox unless it carries a code or link, the user turned in_app off
+// for the type, or the same notification already arrived on another channel.
+func (s *NotificationService) fileInInbox(ctx context.Context, userID string, info model.TypeInfo, meta map[string]interface{}) {
+	if s.inbox == nil || info.Secret {
+		return
+	}
+	if !info.Critical && !s.channelEnabled(ctx, userID, info.Type, model.ChannelInApp) {
+		return
+	}
+	item := &repository.InboxItem{
+		UserID:   userID,
+		Type:     info.Type,
+		Category: info.Category,
+		Title:    getStr(meta, "subject"),
+		Body:     inboxBody(meta),
+	}
+	if item.Title == "" {
+		item.Title = humanizeType(info.Type)
+	}
+	if item.Body == "" {
+		item.Body = item.Title
+	}
+	data := make(map[string]interface{})
+	for _, k := range inboxDataKeys {
+		if v, ok := meta[k]; ok && v != nil && v != "" {
+			data[k] = v
+		}
+	}
+	if len(data) > 0 {
+		item.Data, _ = json.Marshal(data)
+	}
+	for _, k := range inboxRefKeys {
+		if v := getStr(meta, k); v != "" {
+			item.Reference = v
+			break
+		}
+	}
+	if _, err := s.inbox.Add(ctx, item, inboxDedupeWindow); err != nil {
+		log.Printf("notification: inbox store failed user=%s type=%s err=%v", userID, info.Type, err)
+	}
+}
+
+// inboxBody picks the plain-text body of an event: body, then message, then the HTML with tags stripped.
+func inboxBody(meta map[string]interface{}) string {
+	if b := getStr(meta, "body"); b != "" {
+		return b
+	}
+	if b := getStr(meta, "message"); b != "" {
+		return b
+	}
+	text := htmlTag.ReplaceAllString(getStr(meta, "html"), " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// humanizeType turns transfer_success into "Transfer success".
+func humanizeType(t string) string {
+	s := strings.ReplaceAll(t, "_", " ")
+	if s == "" {
+		return "Notification"
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// ListInbox returns the user's notifications (newest first) and their unread count.
+func (s *NotificationService) ListInbox(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]repository.InboxItem, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.inbox.List(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.inbox.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, unread, nil
+}
+
+// SetRead marks one of the user's notifications read or unread.
+func (s *NotificationService) SetRead(ctx context.Context, userID, id string, read bool) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrNotificationNotFound
+	}
+	found, err := s.inbox.SetRead(ctx, userID, id, read, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user read and returns how many changed.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return s.inbox.MarkAllRead(ctx, userID, time.Now().UTC())
+}
+
+// GetPreferences returns every catalog type with the user's choice per channel. Critical types are always on.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) ([]PreferenceView, error) {
+	saved, err := s.prefs.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	off := make(map[string]bool, len(saved))
+	for _, p := range saved {
+		if !p.Enabled {
+			off[p.Type+"/"+p.Channel] = true
+		}
+	}
+	out := make([]PreferenceView, 0, len(model.Catalog))
+	for _, t := range model.Catalog {
+		v := PreferenceView{Type: t.Type, Category: t.Category, Critical: t.Critical, Channels: make(map[string]bool, len(model.Channels))}
+		for _, c := range model.Channels {
+			v.Channels[c] = t.Critical || !off[t.Type+"/"+c]
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// UpdatePreferences saves the given choices. Types must be in the catalog; critical types can only be set to enabled.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, prefs []repository.Preference) error {
+	for _, p := range prefs {
+		info, known := model.LookupType(p.Type)
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownNotificationType, p.Type)
+		}
+		if !model.ValidChannel(p.Channel) {
+			return fmt.Errorf("%w: %s", ErrUnknownChannel, p.Channel)
+		}
+		if info.Critical && !p.Enabled {
+			return fmt.Errorf("%w: %s", ErrCriticalNotification, p.Type)
+		}
+	}
+	return s.prefs.Set(ctx, userID, prefs, time.Now().UTC())
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+)
+
+// memInbox files every item; de-duplication is the repository's job.
+type memInbox struct {
+	inboxStore
+	items []repository.InboxItem
+}
+
+func (m *memInbox) Add(ctx context.Context, item *repository.InboxItem, window time.Duration) (bool, error) {
+	m.items = append(m.items, *item)
+	return true, nil
+}
+
+// memPrefs keeps choices by type and channel; a missing one is enabled, as in the repository.
+type memPrefs map[prefKey]bool
+
+type prefKey struct{ typ, channel string }
+
+func (m memPrefs) Enabled(ctx context.Context, userID, eventType, channel string) (bool, error) {
+	enabled, ok := m[prefKey{eventType, channel}]
+	return enabled || !ok, nil
+}
+
+func (m memPrefs) List(ctx context.Context, userID string) ([]repository.Preference, error) {
+	var out []repository.Preference
+	for k, enabled := range m {
+		out = append(out, repository.Preference{Type: k.typ, Channel: k.channel, Enabled: enabled})
+	}
+	return out, nil
+}
+
+func (m memPrefs) Set(ctx context.Context, userID string, prefs []repository.Preference, now time.Time) error {
+	for _, p := range prefs {
+		m[prefKey{p.Type, p.Channel}] = p.Enabled
+	}
+	return nil
+}
+
+func fileEvent(s *NotificationService, eventType string, meta map[string]interface{}) {
+	info, _ := model.LookupType(eventType)
+	s.fileInInbox(context.Background(), "user-1", info, meta)
+}
+
+func TestFileInInbox(t *testing.T) {
+	inbox := &memInbox{}
+	s := &NotificationService{inbox: inbox, prefs: memPrefs{}}
+	fileEvent(s, "transfer_success", map[string]interface{}{
+		"to":              "ada@example.com",
+		"subject":         "Transfer sent",
+		"html":            "<p>You sent <strong>NGN 5,000.00</strong> to Tunde.</p>",
+		"amount":          5000.0,
+		"transaction_ref": "TRF0001",
+	})
+	if len(inbox.items) != 1 {
+		t.Fatalf("filed %d items, want 1", len(inbox.items))
+	}
+	item := inbox.items[0]
+	if item.Title != "Transfer sent" || item.Body != "You sent NGN 5,000.00 to Tunde." || item.Reference != "TRF0001" || item.Category != "transactions" {
+		t.Errorf("item = %+v", item)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(item.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := data["to"]; ok || data["transaction_ref"] != "TRF0001" || data["amount"] != 5000.0 {
+		t.Errorf("data = %v; want the deep-link fields without the address", data)
+	}
+}
+
+func TestFileInInboxSkips(t *testing.T) {
+	inbox := &memInbox{}
+	prefs := memPrefs{{"transfer_success", model.ChannelInApp}: false, {"new_device_login", model.ChannelInApp}: false}
+	s := &NotificationService{inbox: inbox, prefs: prefs}
+
+	fileEvent(s, "2fa_login_otp", map[string]interface{}{"otp": "123456"})
+	fileEvent(s, "kyc_email_otp", map[string]interface{}{"body": "Your code is 654321"})
+	if len(inbox.items) != 0 {
+		t.Fatalf("one-time codes filed: %+v", inbox.items)
+	}
+	fileEvent(s, "transfer_success", map[string]interface{}{"body": "You sent NGN 5,000.00"})
+	if len(inbox.items) != 0 {
+		t.Fatalf("filed a type the user turned off in the inbox: %+v", inbox.items)
+	}
+	fileEvent(s, "new_device_login", map[string]interface{}{"body": "New login on Pixel 8"})
+	if len(inbox.items) != 1 || inbox.items[0].Title != "New device login" {
+		t.Errorf("critical alert = %+v; want it filed with a title from its type", inbox.items)
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	prefs := memPrefs{}
+	s := &NotificationService{prefs: prefs}
+	ctx := context.Background()
+	refused := []struct {
+		pref repository.Preference
+		want error
+	}{
+		{repository.Preference{Type: "new_device_login", Channel: model.ChannelEmail}, ErrCriticalNotification},
+		{repository.Preference{Type: "no_such_type", Channel: model.ChannelEmail}, ErrUnknownNotificationType},
+		{repository.Preference{Type: "transfer_success", Channel: "pigeon"}, ErrUnknownChannel},
+	}
+	for _, c := range refused {
+		if err := s.UpdatePreferences(ctx, "user-1", []repository.Preference{c.pref}); !errors.Is(err, c.want) {
+			t.Errorf("%+v = %v, want %v", c.pref, err, c.want)
+		}
+	}
+	if len(prefs) != 0 {
+		t.Fatalf("refused updates were saved: %v", prefs)
+	}
+
+	if err := s.UpdatePreferences(ctx, "user-1", []repository.Preference{{Type: "transfer_success", Channel: model.ChannelSMS}}); err != nil {
+		t.Fatal(err)
+	}
+	// A choice saved before the type became critical does not turn it off.
+	prefs[prefKey{"new_device_login", model.ChannelSMS}] = false
+	views, err := s.GetPreferences(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range views {
+		switch v.Type {
+		case "transfer_success":
+			if v.Channels[model.ChannelSMS] || !v.Channels[model.ChannelEmail] {
+				t.Errorf("transfer_success channels = %v", v.Channels)
+			}
+		case "new_device_login":
+			if !v.Critical || !v.Channels[model.ChannelSMS] {
+				t.Errorf("new_device_login = %+v; want every channel on", v)
+			}
+		}
+	}
+}
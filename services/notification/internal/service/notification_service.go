@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -8,6 +10,8 @@ import (
 	"github.com/abubakvr/payup-backend/services/notification/internal/providers/brevo"
 	"github.com/abubakvr/payup-backend/services/notification/internal/providers/termii"
 	"github.com/abubakvr/payup-backend/services/notification/internal/providers/whatsapp"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
 )
 
 // NotificationService processes notification events and sends via the appropriate provider.
@@ -16,46 +20,95 @@ type NotificationService struct {
 	termii                *termii.Client
 	whatsapp              *whatsapp.Client
 	whatsappOTPTemplateName string
+	inbox                 inboxStore
+	prefs                 preferenceStore
+	push                  PushConfig
+	templates             TemplateConfig
+	delivery              DeliveryConfig
+	routing               RoutingConfig
+	hooks                 WebhookConfig
+	escalations           *escalations
 }
 
-// NewNotificationService builds the service with the given provider clients.
-func NewNotificationService(brevo *brevo.Client, termii *termii.Client, whatsapp *whatsapp.Client, whatsappOTPTemplateName string) *NotificationService {
-	return &NotificationService{
+// NewNotificationService builds the service with the given provider clients, the inbox and preference stores, the
+// push channel, the template registry, the delivery log and dead-letter queue, the failover policies, and partner
+// webhooks.
+func NewNotificationService(brevo *brevo.Client, termii *termii.Client, whatsapp *whatsapp.Client, whatsappOTPTemplateName string, inbox *repository.InboxRepository, prefs *repository.PreferenceRepository, push PushConfig, tmpl TemplateConfig, delivery DeliveryConfig, routes RoutingConfig, hooks WebhookConfig) *NotificationService {
+	s := &NotificationService{
 		brevo:                  brevo,
 		termii:                 termii,
 		whatsapp:               whatsapp,
 		whatsappOTPTemplateName: whatsappOTPTemplateName,
+		push:                   push,
+		templates:              tmpl,
+		delivery:               delivery,
+		routing:                routes,
+		hooks:                  hooks,
+		escalations:            newEscalations(),
 	}
+	if inbox != nil {
+		s.inbox = inbox
+	}
+	if prefs != nil {
+		s.prefs = prefs
+	}
+	return s
 }
 
-// Process handles one notification event: routes by channel and calls the right provider.
+// Process handles one notification event without recording it in the delivery log; see Deliver.
 func (s *NotificationService) Process(event model.NotificationEvent) error {
-	meta := event.Metadata
-	if meta == nil {
-		meta = make(map[string]interface{})
+	_, err := s.process(context.Background(), event)
+	return err
+}
+
+// process renders the event's template when the producer sent data instead of content, then routes by channel and
+// calls the right provider, falling over to the next channel in the type's policy if that fails. When the event names
+// a user, their preferences can turn the channel off (except for critical types) and the notification is filed in
+// their in-app inbox.
+func (s *NotificationService) process(ctx context.Context, event model.NotificationEvent) (sendResult, error) {
+	meta, err := s.prepare(ctx, event)
+	if errors.Is(err, templates.ErrRender) {
+		return sendResult{}, Permanent(err)
+	}
+	if err != nil {
+		return sendResult{}, err
+	}
+	info, _ := model.LookupType(event.Type)
+	if event.UserID != "" {
+		defer s.fileInInbox(ctx, event.UserID, info, meta)
+		if !info.Critical && !s.channelEnabled(ctx, event.UserID, event.Type, event.Channel) {
+			log.Printf("notification: %s skipped type=%s user=%s (turned off in preferences)", event.Channel, event.Type, event.UserID)
+			return sendResult{skipped: "turned off in preferences"}, nil
+		}
 	}
 
-	switch event.Channel {
-	case "email":
-		return s.sendEmail(event.Type, meta)
-	case "sms":
-		return s.sendSMS(event.Type, meta)
-	case "whatsapp":
-		return s.sendWhatsApp(event.Type, meta)
-	default:
-		return fmt.Errorf("unknown channel: %s", event.Channel)
+	res, err := s.send(ctx, event.Channel, event.Type, event.UserID, meta)
+	res.hops = []repository.RouteHop{routeHop(res, err)}
+	next := s.routing.Policies.Next(event.Type, event.Channel)
+	if err == nil {
+		res.next, res.event = next, event
+		return res, nil
+	}
+	if len(next) == 0 {
+		return res, err
 	}
+	fb, fbErr := s.failover(ctx, event, info, next)
+	fb.hops = append(res.hops, fb.hops...)
+	if fbErr != nil {
+		return fb, routeError([]error{err, fbErr})
+	}
+	return fb, nil
 }
 
-func (s *NotificationService) sendEmail(evType string, meta map[string]interface{}) error {
+func (s *NotificationService) sendEmail(evType string, meta map[string]interface{}) (string, error) {
 	if s.brevo == nil {
 		log.Printf("notification: email skipped type=%s (Brevo client not configured)", evType)
-		return fmt.Errorf("email provider not configured")
+		return "", Permanent(fmt.Errorf("email provider not configured"))
 	}
 	to := getStr(meta, "to")
 	if to == "" {
 		log.Printf("notification: email skipped type=%s (missing metadata.to)", evType)
-		return fmt.Errorf("email: missing metadata.to")
+		return "", Permanent(fmt.Errorf("email: missing metadata.to"))
 	}
 	subject := getStr(meta, "subject")
 	body := getStr(meta, "body")
@@ -67,51 +120,51 @@ func (s *NotificationService) sendEmail(evType string, meta map[string]interface
 	log.Printf("notification: sending email via Brevo type=%s to=%s subject=%s has_html=%v has_body=%v template_id=%d",
 		evType, to, subject, html != "", body != "", templateID)
 
-	err := s.brevo.Send(to, toName, subject, html, body, templateID, params)
+	messageID, err := s.brevo.Send(to, toName, subject, html, body, templateID, params)
 	if err != nil {
 		log.Printf("notification: email send failed type=%s to=%s err=%v", evType, to, err)
-		return err
+		return "", err
 	}
 	log.Printf("notification: email sent successfully type=%s to=%s", evType, to)
-	return nil
+	return messageID, nil
 }
 
-func (s *NotificationService) sendSMS(evType string, meta map[string]interface{}) error {
+func (s *NotificationService) sendSMS(evType string, meta map[string]interface{}) (string, error) {
 	if s.termii == nil {
-		return fmt.Errorf("sms provider not configured")
+		return "", Permanent(fmt.Errorf("sms provider not configured"))
 	}
 	to := getStr(meta, "to")
 	if to == "" {
-		return fmt.Errorf("sms: missing metadata.to")
+		return "", Permanent(fmt.Errorf("sms: missing metadata.to"))
 	}
 	message := getStr(meta, "body")
 	if message == "" {
 		message = getStr(meta, "message")
 	}
 	if message == "" {
-		return fmt.Errorf("sms: missing metadata.body or metadata.message")
+		return "", Permanent(fmt.Errorf("sms: missing metadata.body or metadata.message"))
 	}
 	channel := getStr(meta, "channel")
 	if channel == "" {
 		channel = "generic"
 	}
 
-	err := s.termii.Send(to, message, channel)
+	messageID, err := s.termii.Send(to, message, channel)
 	if err != nil {
 		log.Printf("notification: sms send failed type=%s to=%s err=%v", evType, to, err)
-		return err
+		return "", err
 	}
 	log.Printf("notification: sms sent type=%s to=%s", evType, to)
-	return nil
+	return messageID, nil
 }
 
-func (s *NotificationService) sendWhatsApp(evType string, meta map[string]interface{}) error {
+func (s *NotificationService) sendWhatsApp(evType string, meta map[string]interface{}) (string, error) {
 	if s.whatsapp == nil {
-		return fmt.Errorf("whatsapp provider not configured")
+		return "", Permanent(fmt.Errorf("whatsapp provider not configured"))
 	}
 	to := getStr(meta, "to")
 	if to == "" {
-		return fmt.Errorf("whatsapp: missing metadata.to")
+		return "", Permanent(fmt.Errorf("whatsapp: missing metadata.to"))
 	}
 	// Remove leading + for WhatsApp API
 	if len(to) > 0 && to[0] == '+' {
@@ -125,13 +178,13 @@ func (s *NotificationService) sendWhatsApp(evType string, meta map[string]interf
 		if templateName == "" {
 			templateName = s.whatsappOTPTemplateName
 		}
-		err := s.whatsapp.SendOTP(to, templateName, otp)
+		messageID, err := s.whatsapp.SendOTP(to, templateName, otp)
 		if err != nil {
 			log.Printf("notification: whatsapp otp send failed type=%s to=%s err=%v", evType, to, err)
-			return err
+			return "", err
 		}
 		log.Printf("notification: whatsapp otp sent type=%s to=%s", evType, to)
-		return nil
+		return messageID, nil
 	}
 
 	templateName := getStr(meta, "template_name")
@@ -151,13 +204,13 @@ func (s *NotificationService) sendWhatsApp(evType string, meta map[string]interf
 				}
 			}
 		}
-		err := s.whatsapp.SendTemplate(to, templateName, lang, components)
+		messageID, err := s.whatsapp.SendTemplate(to, templateName, lang, components)
 		if err != nil {
 			log.Printf("notification: whatsapp template send failed type=%s to=%s err=%v", evType, to, err)
-			return err
+			return "", err
 		}
 		log.Printf("notification: whatsapp template sent type=%s to=%s", evType, to)
-		return nil
+		return messageID, nil
 	}
 
 	text := getStr(meta, "body")
@@ -165,15 +218,15 @@ func (s *NotificationService) sendWhatsApp(evType string, meta map[string]interf
 		text = getStr(meta, "message")
 	}
 	if text == "" {
-		return fmt.Errorf("whatsapp: need metadata.otp, metadata.template_name, or metadata.body")
+		return "", Permanent(fmt.Errorf("whatsapp: need metadata.otp, metadata.template_name, or metadata.body"))
 	}
-	err := s.whatsapp.SendText(to, text)
+	messageID, err := s.whatsapp.SendText(to, text)
 	if err != nil {
 		log.Printf("notification: whatsapp text send failed type=%s to=%s err=%v", evType, to, err)
-		return err
+		return "", err
 	}
 	log.Printf("notification: whatsapp text sent type=%s to=%s", evType, to)
-	return nil
+	return messageID, nil
 }
 
 func getStr(m map[string]interface{}, key string) string {
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/webhooks"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhooksDisabled        = errors.New("partner webhooks are not enabled")
+	ErrWebhookNotFound         = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook          = errors.New("invalid webhook endpoint")
+	ErrTooManyWebhooks         = errors.New("webhook endpoint limit reached")
+)
+
+const (
+	// webhookDispatchBatch is how many due deliveries one dispatch pass claims, and webhookDispatchWorkers how many it
+	// sends at once.
+	webhookDispatchBatch   = 50
+	webhookDispatchWorkers = 8
+	// webhookLease keeps a claimed delivery from being claimed again while it is being sent. It must outlast the
+	// client timeout.
+	webhookLease = 2 * time.Minute
+	// webhookRedeliverTimeout bounds a redelivery the partner waits on.
+	webhookRedeliverTimeout = 30 * time.Second
+)
+
+// WebhookConfig wires partner webhooks: the store of endpoints and deliveries and the client deliveries are posted
+// with. Without Store, partner webhooks are off: the API answers ErrWebhooksDisabled and events are not recorded.
+// AllowHTTP and AllowPrivate accept plain-http and internal endpoint URLs, for local development only.
+type WebhookConfig struct {
+	Store        *repository.WebhookRepository
+	Client       *http.Client
+	MaxAttempts  int
+	MaxEndpoints int
+	AllowHTTP    bool
+	AllowPrivate bool
+}
+
+// WebhookEndpointUpdate changes an endpoint; nil fields are left as they are.
+type WebhookEndpointUpdate struct {
+	URL         *string
+	Description *string
+	EventTypes  *[]string
+	Active      *bool
+}
+
+// CreateWebhookEndpoint registers url for the account's events of the given types. The returned endpoint carries the
+// signing secret; it is not shown again.
+func (s *NotificationService) CreateWebhookEndpoint(ctx context.Context, userID, url, description string, eventTypes []string) (*repository.WebhookEndpoint, error) {
+	if s.hooks.Store == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	e := &repository.WebhookEndpoint{UserID: userID, Active: true}
+	if err := s.applyWebhookUpdate(e, WebhookEndpointUpdate{URL: &url, Description: &description, EventTypes: &eventTypes}); err != nil {
+		return nil, err
+	}
+	if s.hooks.MaxEndpoints > 0 {
+		n, err := s.hooks.Store.CountEndpoints(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if n >= s.hooks.MaxEndpoints {
+			return nil, fmt.Errorf("%w: at most %d per account", ErrTooManyWebhooks, s.hooks.MaxEndpoints)
+		}
+	}
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	e.Secret = secret
+	if err := s.hooks.Store.CreateEndpoint(ctx, e); err != nil {
+		return nil, err
+	}
+	log.Printf("notification: webhook endpoint created id=%s user=%s events=%v", e.ID, userID, e.EventTypes)
+	return e, nil
+}
+
+// ListWebhookEndpoints returns the account's endpoints.
+func (s *NotificationService) ListWebhookEndpoints(ctx context.Context, userID string) ([]repository.WebhookEndpoint, error) {
+	if s.hooks.Store == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	return s.hooks.Store.ListEndpoints(ctx, userID)
+}
+
+// GetWebhookEndpoint returns one of the account's endpoints.
+func (s *NotificationService) GetWebhookEndpoint(ctx context.Context, userID, id string) (*repository.WebhookEndpoint, error) {
+	if s.hooks.Store == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	e, err := s.hooks.Store.GetEndpoint(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return e, nil
+}
+
+// UpdateWebhookEndpoint changes an endpoint's URL, description, event types or active flag. A deactivated endpoint
+// gets no new events; deliveries already waiting resume if it is turned back on.
+func (s *NotificationService) UpdateWebhookEndpoint(ctx context.Context, userID, id string, u WebhookEndpointUpdate) (*repository.WebhookEndpoint, error) {
+	e, err := s.GetWebhookEndpoint(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyWebhookUpdate(e, u); err != nil {
+		return nil, err
+	}
+	ok, err := s.hooks.Store.UpdateEndpoint(ctx, e, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	return s.GetWebhookEndpoint(ctx, userID, id)
+}
+
+// RotateWebhookSecret gives the endpoint a new signing secret and returns the endpoint with it. Deliveries are signed
+// with the new secret from the next attempt.
+func (s *NotificationService) RotateWebhookSecret(ctx context.Context, userID, id string) (*repository.WebhookEndpoint, error) {
+	if _, err := s.GetWebhookEndpoint(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.hooks.Store.SetSecret(ctx, userID, id, secret, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	return s.GetWebhookEndpoint(ctx, userID, id)
+}
+
+// DeleteWebhookEndpoint removes an endpoint and its delivery log.
+func (s *NotificationService) DeleteWebhookEndpoint(ctx context.Context, userID, id string) error {
+	if _, err := s.GetWebhookEndpoint(ctx, userID, id); err != nil {
+		return err
+	}
+	ok, err := s.hooks.Store.DeleteEndpoint(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns an endpoint's deliveries newest first and how many match. status is optional.
+func (s *NotificationService) ListWebhookDeliveries(ctx context.Context, userID, endpointID, status string, limit, offset int) ([]repository.WebhookDelivery, int64, error) {
+	if _, err := s.GetWebhookEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.hooks.Store.ListDeliveries(ctx, userID, endpointID, status, limit, offset)
+}
+
+// GetWebhookDelivery returns one of the account's deliveries with every attempt at it.
+func (s *NotificationService) GetWebhookDelivery(ctx context.Context, userID, id string) (*repository.WebhookDelivery, []repository.WebhookAttempt, error) {
+	d, err := s.webhookDelivery(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := s.hooks.Store.ListAttempts(ctx, d.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, attempts, nil
+}
+
+// RedeliverWebhook posts a delivery again now, whatever its status, and returns it updated with the result. A failed
+// redelivery does not schedule retries of its own.
+func (s *NotificationService) RedeliverWebhook(ctx context.Context, userID, id string) (*repository.WebhookDelivery, error) {
+	d, err := s.webhookDelivery(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.hooks.Store.GetEndpoint(ctx, userID, d.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, ErrWebhookNotFound
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookRedeliverTimeout)
+	defer cancel()
+	return s.attemptWebhook(ctx, d, e, true)
+}
+
+func (s *NotificationService) webhookDelivery(ctx context.Context, userID, id string) (*repository.WebhookDelivery, error) {
+	if s.hooks.Store == nil {
+		return nil, ErrWebhooksDisabled
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	d, err := s.hooks.Store.GetDelivery(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return d, nil
+}
+
+// PublishPartnerEvent queues ev for every endpoint of its account subscribed to its type and returns how many
+// deliveries it queued. An event seen before (the same ID) is not queued again.
+func (s *NotificationService) PublishPartnerEvent(ctx context.Context, ev webhooks.Event) (int64, error) {
+	if s.hooks.Store == nil {
+		return 0, nil
+	}
+	if _, err := uuid.Parse(ev.UserID); err != nil {
+		return 0, nil
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return 0, err
+	}
+	return s.hooks.Store.Enqueue(ctx, ev.UserID, ev.ID, ev.Type, payload, time.Now().UTC())
+}
+
+// DispatchWebhooks sends deliveries that are due: new events and retries whose backoff has run out. Returns how many
+// it attempted. Run periodically; replicas can run it at the same time.
+func (s *NotificationService) DispatchWebhooks(ctx context.Context) (int, error) {
+	if s.hooks.Store == nil {
+		return 0, nil
+	}
+	due, err := s.hooks.Store.ClaimDue(ctx, time.Now().UTC(), webhookLease, webhookDispatchBatch)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, webhookDispatchWorkers)
+	for i := range due {
+		d := &due[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			e, err := s.hooks.Store.GetEndpoint(ctx, "", d.EndpointID)
+			if err != nil || e == nil {
+				log.Printf("notification: webhook endpoint lookup failed delivery=%s err=%v", d.ID, err)
+				return
+			}
+			if _, err := s.attemptWebhook(ctx, d, e, false); err != nil {
+				log.Printf("notification: webhook attempt not recorded delivery=%s err=%v", d.ID, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// attemptWebhook posts d to e and records the attempt. An automatic attempt that fails is retried after a backoff
+// until MaxAttempts; a manual one leaves the retry schedule as it was.
+func (s *NotificationService) attemptWebhook(ctx context.Context, d *repository.WebhookDelivery, e *repository.WebhookEndpoint, manual bool) (*repository.WebhookDelivery, error) {
+	a := webhooks.Send(ctx, s.hooks.Client, webhooks.Request{
+		URL:        e.URL,
+		Secret:     e.Secret,
+		DeliveryID: d.ID,
+		EventID:    d.EventID,
+		EventType:  d.EventType,
+		Body:       d.Payload,
+	}, time.Now())
+	rec := repository.WebhookAttempt{
+		Manual:       manual,
+		StatusCode:   a.StatusCode,
+		ResponseBody: a.Response,
+		DurationMS:   int(a.Duration.Milliseconds()),
+	}
+	if a.Err != nil {
+		rec.Error = truncate(a.Err.Error(), 500)
+	}
+	now := time.Now().UTC()
+	updated, err := s.hooks.Store.RecordAttempt(ctx, d.ID, rec, func(attempt int) (string, *time.Time) {
+		return webhookOutcome(a.OK(), manual, d, attempt, s.hooks.MaxAttempts, now)
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if a.OK() {
+		log.Printf("notification: webhook delivered delivery=%s event=%s status=%d", d.ID, d.EventType, a.StatusCode)
+	} else {
+		log.Printf("notification: webhook attempt %d failed delivery=%s status=%s err=%v", updated.Attempts, d.ID, updated.Status, a.Err)
+	}
+	return updated, nil
+}
+
+// webhookOutcome is a delivery's status after an attempt, and when to retry if it stays pending.
+func webhookOutcome(ok, manual bool, d *repository.WebhookDelivery, attempt, maxAttempts int, now time.Time) (string, *time.Time) {
+	switch {
+	case ok:
+		return repository.WebhookSucceeded, nil
+	case manual && d.Status == repository.WebhookPending:
+		return repository.WebhookPending, d.NextAttemptAt
+	case manual, attempt >= maxAttempts:
+		return repository.WebhookFailed, nil
+	}
+	next := now.Add(webhooks.Backoff(attempt))
+	return repository.WebhookPending, &next
+}
+
+// applyWebhookUpdate validates u and applies it to e.
+func (s *NotificationService) applyWebhookUpdate(e *repository.WebhookEndpoint, u WebhookEndpointUpdate) error {
+	if u.URL != nil {
+		url := strings.TrimSpace(*u.URL)
+		if err := webhooks.ValidateURL(url, s.hooks.AllowHTTP, s.hooks.AllowPrivate); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+		}
+		e.URL = url
+	}
+	if u.Description != nil {
+		desc := strings.TrimSpace(*u.Description)
+		if len(desc) > 255 {
+			return fmt.Errorf("%w: description longer than 255 characters", ErrInvalidWebhook)
+		}
+		e.Description = desc
+	}
+	if u.EventTypes != nil {
+		types, err := webhookEventTypes(*u.EventTypes)
+		if err != nil {
+			return err
+		}
+		e.EventTypes = types
+	}
+	if u.Active != nil {
+		e.Active = *u.Active
+	}
+	return nil
+}
+
+// webhookEventTypes checks a subscription list and drops duplicates.
+func webhookEventTypes(in []string) ([]string, error) {
+	out := make([]string, 0, len(in))
+	seen := make(map[string]bool, len(in))
+	for _, t := range in {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !webhooks.ValidEventType(t) {
+			return nil, fmt.Errorf("%w: unknown event type %q (want one of %s)", ErrInvalidWebhook, t, strings.Join(webhooks.EventTypes, ", "))
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	return out, nil
+}
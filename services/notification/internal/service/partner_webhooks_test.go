@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/webhooks"
+)
+
+func TestWebhookOutcome(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	scheduled := now.Add(time.Hour)
+	pending := &repository.WebhookDelivery{Status: repository.WebhookPending, NextAttemptAt: &scheduled}
+	failed := &repository.WebhookDelivery{Status: repository.WebhookFailed}
+
+	if status, next := webhookOutcome(true, false, pending, 1, 10, now); status != repository.WebhookSucceeded || next != nil {
+		t.Errorf("success = %s %v", status, next)
+	}
+	status, next := webhookOutcome(false, false, pending, 3, 10, now)
+	if status != repository.WebhookPending || next == nil || !next.Equal(now.Add(webhooks.Backoff(3))) {
+		t.Errorf("automatic failure = %s %v, want pending with backoff", status, next)
+	}
+	if status, _ := webhookOutcome(false, false, pending, 10, 10, now); status != repository.WebhookFailed {
+		t.Errorf("last attempt = %s, want failed", status)
+	}
+	// A failed manual redelivery leaves the automatic schedule alone.
+	if status, next := webhookOutcome(false, true, pending, 4, 10, now); status != repository.WebhookPending || next != &scheduled {
+		t.Errorf("manual failure while pending = %s %v", status, next)
+	}
+	if status, _ := webhookOutcome(false, true, failed, 11, 10, now); status != repository.WebhookFailed {
+		t.Errorf("manual failure after giving up = %s", status)
+	}
+	if status, _ := webhookOutcome(true, true, failed, 11, 10, now); status != repository.WebhookSucceeded {
+		t.Errorf("manual success after giving up = %s", status)
+	}
+}
+
+func TestWebhookEventTypes(t *testing.T) {
+	got, err := webhookEventTypes([]string{"Transfer.Success ", "kyc.approved", "transfer.success", "transfer.failed"})
+	if err != nil || len(got) != 3 || got[0] != webhooks.EventTransferSuccess || got[1] != webhooks.EventKYCApproved || got[2] != webhooks.EventTransferFailed {
+		t.Errorf("webhookEventTypes = %v, %v", got, err)
+	}
+	if _, err := webhookEventTypes([]string{"transfer.reversed"}); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("unknown type: err = %v", err)
+	}
+	if _, err := webhookEventTypes(nil); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("empty list: err = %v", err)
+	}
+}
+
+func TestWebhooksDisabledWithoutStore(t *testing.T) {
+	s := &NotificationService{}
+	if _, err := s.ListWebhookEndpoints(context.Background(), "user-1"); !errors.Is(err, ErrWebhooksDisabled) {
+		t.Errorf("ListWebhookEndpoints err = %v", err)
+	}
+	if n, err := s.PublishPartnerEvent(context.Background(), webhooks.Event{Type: webhooks.EventKYCApproved, UserID: "user-1"}); n != 0 || err != nil {
+		t.Errorf("PublishPartnerEvent = %d, %v; want a no-op", n, err)
+	}
+}
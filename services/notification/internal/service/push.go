@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+)
+
+// pushTimeout bounds one fan-out: token lookup, every device, and pruning.
+const pushTimeout = 20 * time.Second
+
+// PushTokenStore lists a user's devices and removes tokens a provider rejected. The user service owns the tokens.
+type PushTokenStore interface {
+	ListPushTokens(ctx context.Context, userID string) ([]push.Device, error)
+	RemovePushTokens(ctx context.Context, tokens []string, reason string) error
+}
+
+// PushConfig wires the push channel: where tokens come from and the provider for each platform (push.PlatformIOS,
+// push.PlatformAndroid). Leave Providers empty to turn push off.
+type PushConfig struct {
+	Tokens    PushTokenStore
+	Providers map[string]push.Provider
+}
+
+// sendPush fans the event out to every device of the user. Tokens a provider reports as invalid are removed from the
+// user service. Fails only when no device received it and at least one send failed for another reason.
+func (s *NotificationService) sendPush(ctx context.Context, userID, evType string, meta map[string]interface{}) error {
+	if len(s.push.Providers) == 0 || s.push.Tokens == nil {
+		return Permanent(fmt.Errorf("push provider not configured"))
+	}
+	if userID == "" {
+		return Permanent(fmt.Errorf("push: missing user_id"))
+	}
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+	devices, err := s.push.Tokens.ListPushTokens(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("push: list tokens: %w", err)
+	}
+	if len(devices) == 0 {
+		log.Printf("notification: push skipped type=%s user=%s (no registered devices)", evType, userID)
+		return nil
+	}
+	msg := pushMessage(evType, meta)
+	var (
+		sent    int
+		lastErr error
+		invalid []string
+		reasons = make(map[string]bool)
+	)
+	for _, d := range devices {
+		provider := s.push.Providers[d.Platform]
+		if provider == nil {
+			log.Printf("notification: push skipped device type=%s user=%s platform=%s (no provider)", evType, userID, d.Platform)
+			continue
+		}
+		err := provider.Send(ctx, d.Token, msg)
+		if reason, ok := push.InvalidTokenReason(err); ok {
+			invalid = append(invalid, d.Token)
+			reasons[reason] = true
+			continue
+		}
+		if err != nil {
+			log.Printf("notification: push send failed type=%s user=%s platform=%s err=%v", evType, userID, d.Platform, err)
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+	if len(invalid) > 0 {
+		reasonList := make([]string, 0, len(reasons))
+		for r := range reasons {
+			reasonList = append(reasonList, r)
+		}
+		sort.Strings(reasonList)
+		if err := s.push.Tokens.RemovePushTokens(ctx, invalid, strings.Join(reasonList, ",")); err != nil {
+			log.Printf("notification: push prune failed user=%s tokens=%d err=%v", userID, len(invalid), err)
+		} else {
+			log.Printf("notification: push pruned user=%s tokens=%d reason=%s", userID, len(invalid), strings.Join(reasonList, ","))
+		}
+	}
+	log.Printf("notification: push type=%s user=%s devices=%d sent=%d invalid=%d", evType, userID, len(devices), sent, len(invalid))
+	if sent == 0 && lastErr != nil {
+		return lastErr
+	}
+	return nil
+}
+
+// pushMessage builds the notification from the event: push_title/push_body when the producer set them, otherwise the
+// same title and plain-text body the inbox uses. Data carries the type and the deep-link fields as strings.
+func pushMessage(evType string, meta map[string]interface{}) push.Message {
+	title := getStr(meta, "push_title")
+	if title == "" {
+		title = getStr(meta, "subject")
+	}
+	if title == "" {
+		title = humanizeType(evType)
+	}
+	body := getStr(meta, "push_body")
+	if body == "" {
+		body = inboxBody(meta)
+	}
+	data := map[string]string{"type": evType}
+	for _, k := range inboxDataKeys {
+		if v, ok := meta[k]; ok && v != nil && v != "" {
+			data[k] = fmt.Sprint(v)
+		}
+	}
+	return push.Message{Title: title, Body: body, Data: data}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/apns"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/fcm"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/push"
+)
+
+type memTokens struct {
+	devices map[string][]push.Device
+	removed []string
+	reason  string
+}
+
+func (m *memTokens) ListPushTokens(_ context.Context, userID string) ([]push.Device, error) {
+	return m.devices[userID], nil
+}
+
+func (m *memTokens) RemovePushTokens(_ context.Context, tokens []string, reason string) error {
+	m.removed, m.reason = append(m.removed, tokens...), reason
+	return nil
+}
+
+// fakePushEndpoint stands in for both FCM and APNs. Tokens starting with "stale" are reported uninstalled, "down"
+// fails with a server error; every other delivery is recorded.
+func fakePushEndpoint(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var delivered []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var token string
+		switch {
+		case r.URL.Path == "/token":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "expires_in": 3600})
+			return
+		case strings.HasPrefix(r.URL.Path, "/3/device/"):
+			token = strings.TrimPrefix(r.URL.Path, "/3/device/")
+			if strings.HasPrefix(token, "stale") {
+				w.WriteHeader(http.StatusGone)
+				_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+				return
+			}
+		default:
+			var body struct {
+				Message struct {
+					Token string `json:"token"`
+				} `json:"message"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			token = body.Message.Token
+			if strings.HasPrefix(token, "stale") {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+		}
+		if strings.HasPrefix(token, "down") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		delivered = append(delivered, token)
+		mu.Unlock()
+	}))
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), delivered...)
+	}
+}
+
+func pushProviders(t *testing.T, baseURL string) map[string]push.Provider {
+	t.Helper()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaDER, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	sa, _ := json.Marshal(fcm.ServiceAccount{
+		ProjectID:   "payup-test",
+		ClientEmail: "push@payup-test.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rsaDER})),
+		TokenURI:    baseURL + "/token",
+	})
+	fcmClient, err := fcm.NewClient(sa, baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	apnsClient, err := apns.NewClient("KEY", "TEAM", "com.payup.app", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER}), baseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]push.Provider{push.PlatformAndroid: fcmClient, push.PlatformIOS: apnsClient}
+}
+
+func TestSendPushFansOutAndPrunesInvalidTokens(t *testing.T) {
+	srv, delivered := fakePushEndpoint(t)
+	defer srv.Close()
+	tokens := &memTokens{devices: map[string][]push.Device{"user-1": {
+		{Token: "phone", Platform: push.PlatformAndroid},
+		{Token: "ipad", Platform: push.PlatformIOS},
+		{Token: "stale-android", Platform: push.PlatformAndroid},
+		{Token: "stale-ios", Platform: push.PlatformIOS},
+	}}}
+	s := &NotificationService{push: PushConfig{Tokens: tokens, Providers: pushProviders(t, srv.URL)}}
+
+	err := s.Process(model.NotificationEvent{UserID: "user-1", Type: "transfer_success", Channel: "push", Metadata: map[string]interface{}{
+		"subject": "Transfer successful", "body": "You sent NGN 5,000", "transaction_ref": "TRF1",
+	}})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if got := delivered(); len(got) != 2 {
+		t.Fatalf("delivered to %v, want phone and ipad", got)
+	}
+	if len(tokens.removed) != 2 || tokens.removed[0] != "stale-android" || tokens.removed[1] != "stale-ios" {
+		t.Fatalf("removed = %v, want both stale tokens", tokens.removed)
+	}
+	if tokens.reason != "UNREGISTERED,Unregistered" {
+		t.Fatalf("reason = %q", tokens.reason)
+	}
+}
+
+func TestSendPushFailsWhenNoDeviceReached(t *testing.T) {
+	srv, _ := fakePushEndpoint(t)
+	defer srv.Close()
+	tokens := &memTokens{devices: map[string][]push.Device{
+		"user-1": {{Token: "down-1", Platform: push.PlatformAndroid}, {Token: "stale-1", Platform: push.PlatformIOS}},
+		"user-2": nil,
+	}}
+	s := &NotificationService{push: PushConfig{Tokens: tokens, Providers: pushProviders(t, srv.URL)}}
+
+	if err := s.sendPush(context.Background(), "user-1", "wallet_credit", nil); err == nil {
+		t.Fatal("want an error when every device failed")
+	}
+	if len(tokens.removed) != 1 || tokens.removed[0] != "stale-1" {
+		t.Fatalf("removed = %v, want the stale token pruned even though the send failed", tokens.removed)
+	}
+	if err := s.sendPush(context.Background(), "user-2", "wallet_credit", nil); err != nil {
+		t.Fatalf("user without devices: %v", err)
+	}
+}
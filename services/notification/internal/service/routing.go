@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/routing"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
+)
+
+// escalationTimeout bounds one timed failover: the delivery lookup, rendering and every fallback send.
+const escalationTimeout = 30 * time.Second
+
+// channelProviders is the provider behind each channel, for the delivery log and the circuit breakers.
+var channelProviders = map[string]string{
+	model.ChannelEmail:    ProviderBrevo,
+	model.ChannelSMS:      ProviderTermii,
+	model.ChannelWhatsApp: ProviderWhatsApp,
+	model.ChannelPush:     ProviderPush,
+}
+
+// RoutingConfig wires channel failover: the policy for each type and the provider circuit breakers. The zero value
+// sends every event on its own channel only and never opens a breaker.
+type RoutingConfig struct {
+	Policies routing.Policies
+	Breakers *routing.Breakers
+}
+
+// send calls the provider for channel unless its circuit breaker is open. Provider failures that retrying could fix
+// count against the breaker; rejected requests and missing details do not.
+func (s *NotificationService) send(ctx context.Context, channel, evType, userID string, meta map[string]interface{}) (sendResult, error) {
+	res := sendResult{
+		channel:   channel,
+		provider:  channelProviders[channel],
+		recipient: getStr(meta, "to"),
+		subject:   getStr(meta, "subject"),
+	}
+	if channel == model.ChannelPush {
+		return res, s.sendPush(ctx, userID, evType, meta)
+	}
+	b := s.routing.Breakers.For(res.provider)
+	if !b.Allow() {
+		return res, fmt.Errorf("%s: %w", res.provider, routing.ErrCircuitOpen)
+	}
+	var err error
+	switch channel {
+	case model.ChannelEmail:
+		res.messageID, err = s.sendEmail(evType, meta)
+	case model.ChannelSMS:
+		res.messageID, err = s.sendSMS(evType, meta)
+	case model.ChannelWhatsApp:
+		res.messageID, err = s.sendWhatsApp(evType, meta)
+	default:
+		return sendResult{}, Permanent(fmt.Errorf("unknown channel: %s", channel))
+	}
+	switch {
+	case err == nil:
+		b.Success()
+	case Retryable(err):
+		b.Failure()
+	}
+	return res, err
+}
+
+// failover tries steps in order until one sends. Each gets content rendered for its own channel, since the producer
+// wrote the event for the first one. Returns every step tried and, on success, the steps still left.
+func (s *NotificationService) failover(ctx context.Context, event model.NotificationEvent, info model.TypeInfo, steps []routing.Step) (sendResult, error) {
+	var hops []repository.RouteHop
+	var errs []error
+	for i, st := range steps {
+		if event.UserID != "" && !info.Critical && !s.channelEnabled(ctx, event.UserID, event.Type, st.Channel) {
+			hops = append(hops, repository.RouteHop{Channel: st.Channel, Status: repository.DeliverySkipped, Error: "turned off in preferences", At: time.Now().UTC()})
+			continue
+		}
+		log.Printf("notification: failing over type=%s user=%s to %s", event.Type, event.UserID, st.Channel)
+		fb := fallbackEvent(event, info, st.Channel)
+		res := sendResult{channel: st.Channel, provider: channelProviders[st.Channel]}
+		meta, err := s.prepareFallback(ctx, fb, event)
+		if err == nil {
+			res, err = s.send(ctx, st.Channel, fb.Type, fb.UserID, meta)
+		}
+		hops = append(hops, routeHop(res, err))
+		if err == nil {
+			res.hops, res.next, res.event = hops, steps[i+1:], event
+			return res, nil
+		}
+		log.Printf("notification: %s fallback failed type=%s err=%v", st.Channel, event.Type, err)
+		errs = append(errs, err)
+	}
+	return sendResult{hops: hops}, routeError(errs)
+}
+
+// fallbackEvent rewrites event for another channel. The producer's copy and address were written for the original
+// channel, so they are dropped (a phone number carries over between SMS and WhatsApp) and the code, if any, moves to
+// the template data.
+func fallbackEvent(event model.NotificationEvent, info model.TypeInfo, channel string) model.NotificationEvent {
+	fb := model.NotificationEvent{
+		UserID:   event.UserID,
+		Type:     event.Type,
+		Channel:  channel,
+		Locale:   event.Locale,
+		Metadata: make(map[string]interface{}),
+		Data:     make(map[string]interface{}, len(event.Data)+1),
+	}
+	for k, v := range event.Data {
+		fb.Data[k] = v
+	}
+	for k, v := range event.Metadata {
+		switch k {
+		case "to":
+			if phoneChannel(event.Channel) && phoneChannel(channel) {
+				fb.Metadata[k] = v
+			}
+		case "otp":
+			fb.Data[k] = v
+			if channel == model.ChannelWhatsApp {
+				fb.Metadata[k] = v
+			}
+		case "channel": // Termii route
+			if channel == model.ChannelSMS {
+				fb.Metadata[k] = v
+			}
+		case "to_name", "subject", "html", "body", "message", "template_id", "template_name", "template_language",
+			"template_params", "params":
+		default:
+			fb.Metadata[k] = v
+		}
+	}
+	if channel == model.ChannelSMS && info.Critical && getStr(fb.Metadata, "channel") == "" {
+		fb.Metadata["channel"] = "dnd"
+	}
+	return fb
+}
+
+// prepareFallback renders the fallback event's template. Without one, the producer's plain-text body is reused, which
+// keeps events that never had templates deliverable on another channel.
+func (s *NotificationService) prepareFallback(ctx context.Context, fb, source model.NotificationEvent) (map[string]interface{}, error) {
+	meta, err := s.prepare(ctx, fb)
+	if errors.Is(err, templates.ErrRender) {
+		return nil, Permanent(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !hasContent(meta) {
+		text := getStr(source.Metadata, "body")
+		if text == "" {
+			text = getStr(source.Metadata, "message")
+		}
+		if text != "" {
+			meta["body"] = text
+			if fb.Channel == model.ChannelEmail {
+				meta["subject"] = "PayUp: " + humanizeType(fb.Type)
+			}
+		}
+	}
+	if getStr(meta, "to") == "" {
+		fillAddress(meta, fb.Channel, s.recipient(ctx, fb.UserID))
+	}
+	return meta, nil
+}
+
+func phoneChannel(channel string) bool {
+	return channel == model.ChannelSMS || channel == model.ChannelWhatsApp
+}
+
+func routeHop(res sendResult, err error) repository.RouteHop {
+	hop := repository.RouteHop{Channel: res.channel, Provider: res.provider, Status: repository.DeliverySent, At: time.Now().UTC()}
+	switch {
+	case errors.Is(err, routing.ErrCircuitOpen):
+		hop.Status, hop.Error = repository.DeliverySkipped, err.Error()
+	case err != nil:
+		hop.Status, hop.Error = repository.DeliveryFailed, err.Error()
+	}
+	return hop
+}
+
+// routeError picks the error to report when every channel failed: the first one worth retrying, so the consumer
+// tries the route again, otherwise the last.
+func routeError(errs []error) error {
+	for _, err := range errs {
+		if Retryable(err) {
+			return err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[len(errs)-1]
+}
+
+// scheduleEscalation arms the timed failover after a send: if the provider has not reported the message delivered
+// when the next step's delay runs out, the next step is tried. Needs the delivery log to see receipts.
+func (s *NotificationService) scheduleEscalation(deliveryID string, res sendResult) {
+	if s.delivery.Log == nil || len(res.next) == 0 || res.next[0].After <= 0 {
+		return
+	}
+	s.escalations.schedule(deliveryID, res.next[0].After, func() { s.escalate(deliveryID, res) })
+}
+
+// escalate moves an undelivered notification on to its next channel.
+func (s *NotificationService) escalate(deliveryID string, res sendResult) {
+	ctx, cancel := context.WithTimeout(context.Background(), escalationTimeout)
+	defer cancel()
+	d, err := s.delivery.Log.Get(ctx, deliveryID)
+	if err != nil {
+		log.Printf("notification: escalation lookup failed id=%s err=%v", deliveryID, err)
+		return
+	}
+	if d == nil || (d.Status != repository.DeliverySent && d.Status != repository.DeliveryFailed) || d.ProviderMessageID != res.messageID {
+		return
+	}
+	log.Printf("notification: %s not delivered id=%s status=%s; escalating", res.channel, deliveryID, d.Status)
+	info, _ := model.LookupType(res.event.Type)
+	next, err := s.failover(ctx, res.event, info, res.next)
+	now := time.Now().UTC()
+	if logErr := s.delivery.Log.AppendRoute(ctx, deliveryID, next.hops); logErr != nil {
+		log.Printf("notification: delivery log update failed id=%s err=%v", deliveryID, logErr)
+	}
+	if err != nil {
+		// The earlier channel's message may still arrive; only a failed receipt for it makes the delivery failed.
+		log.Printf("notification: escalation failed id=%s err=%v", deliveryID, err)
+		return
+	}
+	if logErr := s.delivery.Log.MarkSent(ctx, deliveryID, s.sentMessage(next), now); logErr != nil {
+		log.Printf("notification: delivery log update failed id=%s err=%v", deliveryID, logErr)
+	}
+	s.scheduleEscalation(deliveryID, next)
+}
+
+// Routing returns the failover policies in force and the state of every provider breaker used so far.
+func (s *NotificationService) Routing() (routing.Policies, []routing.BreakerState) {
+	return s.routing.Policies, s.routing.Breakers.States()
+}
+
+// escalations are the timed failovers waiting on a delivery receipt, by delivery ID. They live in memory: after a
+// restart the first channel's message stands and no fallback is sent.
+type escalations struct {
+	mu     sync.Mutex
+	timers map[string]*escalation
+}
+
+type escalation struct {
+	timer *time.Timer
+	run   func()
+}
+
+func newEscalations() *escalations {
+	return &escalations{timers: make(map[string]*escalation)}
+}
+
+func (e *escalations) schedule(id string, after time.Duration, run func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if old := e.timers[id]; old != nil {
+		old.timer.Stop()
+	}
+	esc := &escalation{run: run}
+	esc.timer = time.AfterFunc(after, func() {
+		if e.take(id, esc) {
+			run()
+		}
+	})
+	e.timers[id] = esc
+}
+
+func (e *escalations) take(id string, esc *escalation) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.timers[id] != esc {
+		return false
+	}
+	delete(e.timers, id)
+	return true
+}
+
+// cancel drops a pending escalation: the message was delivered, or a new attempt replaces it.
+func (e *escalations) cancel(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if esc := e.timers[id]; esc != nil {
+		esc.timer.Stop()
+		delete(e.timers, id)
+	}
+}
+
+// fire runs a pending escalation now, for a provider that reported the message failed.
+func (e *escalations) fire(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if esc := e.timers[id]; esc != nil && esc.timer.Stop() {
+		delete(e.timers, id)
+		go esc.run()
+	}
+}
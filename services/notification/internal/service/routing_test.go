@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/providers/termii"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/routing"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
+)
+
+// fakeTermii records the SMS it is asked to send, or fails every request with status when it is non-zero.
+func fakeTermii(t *testing.T, status int) (*termii.Client, *[]termii.SendRequest) {
+	t.Helper()
+	var sent []termii.SendRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		var req termii.SendRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req)
+		_, _ = w.Write([]byte(`{"message_id":"sms-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return termii.NewClient("key", "PayUp", srv.URL), &sent
+}
+
+func TestFailoverToSMS(t *testing.T) {
+	client, sent := fakeTermii(t, 0)
+	registry, err := templates.NewRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// WhatsApp is not configured, so the code must go out by SMS, rendered from the kyc_phone_otp.sms default.
+	s := NewNotificationService(nil, client, nil, "", nil, nil, PushConfig{}, TemplateConfig{Registry: registry},
+		DeliveryConfig{}, RoutingConfig{Policies: routing.DefaultPolicies()}, WebhookConfig{})
+	res, err := s.process(context.Background(), model.NotificationEvent{
+		UserID:   "u1",
+		Type:     "kyc_phone_otp",
+		Channel:  model.ChannelWhatsApp,
+		Metadata: map[string]interface{}{"to": "2348012345678", "otp": "482913"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.channel != model.ChannelSMS || res.provider != ProviderTermii || res.messageID != "sms-1" {
+		t.Errorf("result = %+v", res)
+	}
+	if len(res.hops) != 2 || res.hops[0].Status != repository.DeliveryFailed || res.hops[1].Status != repository.DeliverySent {
+		t.Errorf("hops = %+v", res.hops)
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("sent %d SMS", len(*sent))
+	}
+	got := (*sent)[0]
+	if got.To != "2348012345678" || got.Channel != "dnd" || got.SMS != "Your PayUp verification code is 482913. It expires in a few minutes. Never share it with anyone." {
+		t.Errorf("sms = %+v", got)
+	}
+}
+
+func TestFailoverAllChannelsFail(t *testing.T) {
+	client, _ := fakeTermii(t, http.StatusServiceUnavailable)
+	s := NewNotificationService(nil, client, nil, "", nil, nil, PushConfig{}, TemplateConfig{}, DeliveryConfig{},
+		RoutingConfig{Policies: routing.DefaultPolicies()}, WebhookConfig{})
+	res, err := s.process(context.Background(), model.NotificationEvent{
+		Type:     "kyc_phone_otp",
+		Channel:  model.ChannelWhatsApp,
+		Metadata: map[string]interface{}{"to": "2348012345678", "otp": "482913", "body": "Your code is 482913"},
+	})
+	// WhatsApp is permanently unavailable but Termii is only down, so the consumer should retry the route.
+	if err == nil || !Retryable(err) {
+		t.Fatalf("err = %v, want a retryable error", err)
+	}
+	if len(res.hops) != 2 {
+		t.Errorf("hops = %+v", res.hops)
+	}
+}
+
+func TestCircuitBreakerSkipsProvider(t *testing.T) {
+	client, sent := fakeTermii(t, 0)
+	breakers := routing.NewBreakers(1, time.Hour)
+	breakers.For(ProviderTermii).Failure()
+	s := NewNotificationService(nil, client, nil, "", nil, nil, PushConfig{}, TemplateConfig{}, DeliveryConfig{},
+		RoutingConfig{Breakers: breakers}, WebhookConfig{})
+	_, err := s.process(context.Background(), model.NotificationEvent{
+		Type:     "bill_token",
+		Channel:  model.ChannelSMS,
+		Metadata: map[string]interface{}{"to": "2348012345678", "body": "Token 1234"},
+	})
+	if !errors.Is(err, routing.ErrCircuitOpen) || !Retryable(err) {
+		t.Fatalf("err = %v, want a retryable circuit open error", err)
+	}
+	if len(*sent) != 0 {
+		t.Error("request reached the provider behind an open breaker")
+	}
+}
+
+func TestFallbackEvent(t *testing.T) {
+	info, _ := model.LookupType("2fa_login_otp")
+	src := model.NotificationEvent{
+		UserID:   "u1",
+		Type:     "2fa_login_otp",
+		Channel:  model.ChannelSMS,
+		Metadata: map[string]interface{}{"to": "2348012345678", "body": "Your code is 1", "channel": "dnd", "otp": "1"},
+	}
+	wa := fallbackEvent(src, info, model.ChannelWhatsApp)
+	if wa.Metadata["to"] != "2348012345678" || wa.Metadata["otp"] != "1" || wa.Metadata["body"] != nil || wa.Metadata["channel"] != nil {
+		t.Errorf("whatsapp metadata = %v", wa.Metadata)
+	}
+	email := fallbackEvent(src, info, model.ChannelEmail)
+	if len(email.Metadata) != 0 || email.Data["otp"] != "1" {
+		t.Errorf("email = %+v", email)
+	}
+}
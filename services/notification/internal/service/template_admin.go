@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrInvalidTemplate  = errors.New("invalid template")
+)
+
+// templateChannels are the channels a template can be written for. The inbox reuses whatever the sending channel
+// rendered, so in_app has no templates of its own.
+var templateChannels = map[string]bool{
+	model.ChannelEmail: true, model.ChannelSMS: true, model.ChannelWhatsApp: true, model.ChannelPush: true,
+}
+
+// ListTemplates returns the built-in defaults and every stored version matching the non-empty filters: defaults first
+// (version 0, status builtin), then stored versions newest first.
+func (s *NotificationService) ListTemplates(ctx context.Context, eventType, channel, locale string) ([]repository.Template, error) {
+	locale = templates.NormalizeLocale(locale)
+	var out []repository.Template
+	for _, t := range s.templates.Registry.Builtins() {
+		if (eventType == "" || t.Type == eventType) && (channel == "" || t.Channel == channel) && (locale == "" || t.Locale == locale) {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		if out[i].Channel != out[j].Channel {
+			return out[i].Channel < out[j].Channel
+		}
+		return out[i].Locale < out[j].Locale
+	})
+	stored, err := s.templates.Store.List(ctx, eventType, channel, locale)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, stored...), nil
+}
+
+// CreateTemplate stores a new draft version of the template for t.Type, t.Channel and t.Locale. It goes live only when
+// activated.
+func (s *NotificationService) CreateTemplate(ctx context.Context, t *repository.Template) error {
+	t.Locale = templates.NormalizeLocale(t.Locale)
+	switch {
+	case t.Type == "":
+		return fmt.Errorf("%w: type is required", ErrInvalidTemplate)
+	case !templateChannels[t.Channel]:
+		return fmt.Errorf("%w: channel must be email, sms, whatsapp or push", ErrInvalidTemplate)
+	case t.Locale == "" || len(t.Locale) > 10:
+		return fmt.Errorf("%w: locale is required, e.g. en, ha, yo", ErrInvalidTemplate)
+	case t.Text == "" && t.HTML == "":
+		return fmt.Errorf("%w: text or html is required", ErrInvalidTemplate)
+	case t.Channel != model.ChannelEmail && t.HTML != "":
+		return fmt.Errorf("%w: html is only sent by email", ErrInvalidTemplate)
+	}
+	if err := templates.Validate(t); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return s.templates.Store.CreateDraft(ctx, t)
+}
+
+// ActivateTemplate makes a stored version live, retiring the one it replaces. Activating an older version rolls back.
+func (s *NotificationService) ActivateTemplate(ctx context.Context, id string) (*repository.Template, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	found, err := s.templates.Store.Activate(ctx, id, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTemplateNotFound
+	}
+	return s.templates.Store.Get(ctx, id)
+}
+
+// PreviewTemplate renders a stored version (id set, drafts included) or whatever is live for type, channel and locale
+// against data, without sending anything.
+func (s *NotificationService) PreviewTemplate(ctx context.Context, id, eventType, channel, locale string, data map[string]interface{}) (*templates.Rendered, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	if _, ok := data["first_name"]; !ok {
+		data["first_name"] = ""
+	}
+	if id == "" {
+		r, err := s.templates.Registry.Render(ctx, eventType, channel, locale, data)
+		if errors.Is(err, templates.ErrRender) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			return nil, ErrTemplateNotFound
+		}
+		return r, nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrTemplateNotFound
+	}
+	t, err := s.templates.Store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTemplateNotFound
+	}
+	r, err := templates.Execute(t, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
+)
+
+// recipientTimeout bounds the user service lookup for a templated event.
+const recipientTimeout = 5 * time.Second
+
+// contentKeys are the metadata fields that carry ready-made content. An event with any of them is sent as the producer
+// wrote it, so producers that still build their own copy keep working.
+var contentKeys = []string{"subject", "html", "body", "message", "otp", "template_id", "template_name"}
+
+// RecipientDirectory says where to reach a user and which language they use. The user service owns both.
+type RecipientDirectory interface {
+	GetNotificationRecipient(ctx context.Context, userID string) (*model.Recipient, error)
+}
+
+// TemplateConfig wires templated notifications: the registry that renders them, the store admins edit versions in,
+// and the directory that addresses and localizes them. Without Recipients, templated events need metadata.to and are
+// written in their Locale or English.
+type TemplateConfig struct {
+	Registry   *templates.Registry
+	Store      *repository.TemplateRepository
+	Recipients RecipientDirectory
+}
+
+// prepare returns the metadata to send for event. When the producer sent no content and a template exists for the
+// type and channel, the template is rendered in the recipient's language and the result fills subject, html and body;
+// the data keys are copied alongside for the inbox and push deep links, and a missing address comes from the user
+// service.
+func (s *NotificationService) prepare(ctx context.Context, event model.NotificationEvent) (map[string]interface{}, error) {
+	meta := make(map[string]interface{}, len(event.Metadata)+len(event.Data)+3)
+	for k, v := range event.Metadata {
+		meta[k] = v
+	}
+	if s.templates.Registry == nil || hasContent(meta) {
+		// Resent notifications are stored without their address.
+		if getStr(meta, "to") == "" && event.UserID != "" && event.Channel != model.ChannelPush {
+			fillAddress(meta, event.Channel, s.recipient(ctx, event.UserID))
+		}
+		return meta, nil
+	}
+	recipient := s.recipient(ctx, event.UserID)
+	locale := event.Locale
+	if locale == "" && recipient != nil {
+		locale = recipient.Language
+	}
+	data := make(map[string]interface{}, len(event.Data)+1)
+	for k, v := range event.Data {
+		data[k] = v
+	}
+	if _, ok := data["first_name"]; !ok {
+		data["first_name"] = ""
+		if recipient != nil {
+			data["first_name"] = recipient.FirstName
+		}
+	}
+	rendered, err := s.templates.Registry.Render(ctx, event.Type, event.Channel, locale, data)
+	if err != nil {
+		log.Printf("notification: template render failed type=%s channel=%s locale=%s err=%v", event.Type, event.Channel, locale, err)
+		return nil, err
+	}
+	if rendered == nil {
+		return meta, nil
+	}
+	meta["subject"] = rendered.Subject
+	meta["body"] = rendered.Text
+	if rendered.HTML != "" {
+		meta["html"] = rendered.HTML
+	}
+	for k, v := range event.Data {
+		if _, ok := meta[k]; !ok {
+			meta[k] = v
+		}
+	}
+	if getStr(meta, "to") == "" {
+		fillAddress(meta, event.Channel, recipient)
+	}
+	log.Printf("notification: rendered template type=%s channel=%s locale=%s version=%d", event.Type, event.Channel, rendered.Locale, rendered.Version)
+	return meta, nil
+}
+
+// recipient looks the user up for a templated event. A failed lookup is logged and the event goes out in the default
+// locale, as long as the producer supplied an address.
+func (s *NotificationService) recipient(ctx context.Context, userID string) *model.Recipient {
+	if userID == "" || s.templates.Recipients == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, recipientTimeout)
+	defer cancel()
+	r, err := s.templates.Recipients.GetNotificationRecipient(ctx, userID)
+	if err != nil {
+		log.Printf("notification: recipient lookup failed user=%s err=%v", userID, err)
+		return nil
+	}
+	return r
+}
+
+// fillAddress sets the recipient's address for channel: email (and a display name), or the phone for SMS and WhatsApp.
+func fillAddress(meta map[string]interface{}, channel string, recipient *model.Recipient) {
+	if recipient == nil {
+		return
+	}
+	switch channel {
+	case model.ChannelEmail:
+		meta["to"] = recipient.Email
+		if getStr(meta, "to_name") == "" {
+			meta["to_name"] = strings.TrimSpace(recipient.FirstName + " " + recipient.LastName)
+		}
+	case model.ChannelSMS, model.ChannelWhatsApp:
+		meta["to"] = recipient.Phone
+	}
+}
+
+func hasContent(meta map[string]interface{}) bool {
+	for _, k := range contentKeys {
+		if v, ok := meta[k]; ok && v != nil && v != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/model"
+	"github.com/abubakvr/payup-backend/services/notification/internal/templates"
+)
+
+type memRecipients map[string]*model.Recipient
+
+func (m memRecipients) GetNotificationRecipient(_ context.Context, userID string) (*model.Recipient, error) {
+	return m[userID], nil
+}
+
+func TestPrepareRendersTemplatedEvent(t *testing.T) {
+	reg, err := templates.NewRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &NotificationService{templates: TemplateConfig{Registry: reg, Recipients: memRecipients{
+		"u1": {Email: "ada@example.com", FirstName: "Ada", LastName: "Obi", Language: "ha"},
+	}}}
+	meta, err := s.prepare(context.Background(), model.NotificationEvent{
+		UserID: "u1", Type: "transfer_success", Channel: "email",
+		Data: map[string]interface{}{"amount": 5000.0, "beneficiary": "Tunde", "transaction_ref": "TRF9"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta["to"] != "ada@example.com" || meta["to_name"] != "Ada Obi" {
+		t.Errorf("address from user service: to=%v to_name=%v", meta["to"], meta["to_name"])
+	}
+	if meta["subject"] != "Transfer successful" || !strings.Contains(getStr(meta, "html"), "NGN 5,000.00") {
+		t.Errorf("rendered: subject=%v html=%v", meta["subject"], meta["html"])
+	}
+	if meta["transaction_ref"] != "TRF9" {
+		t.Errorf("data keys should be copied for the inbox: %v", meta)
+	}
+}
+
+func TestPrepareKeepsProducerContent(t *testing.T) {
+	reg, err := templates.NewRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &NotificationService{templates: TemplateConfig{Registry: reg}}
+	meta, err := s.prepare(context.Background(), model.NotificationEvent{
+		Type: "transfer_success", Channel: "email",
+		Metadata: map[string]interface{}{"to": "a@b.c", "subject": "Custom", "html": "<p>hi</p>"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta["subject"] != "Custom" || meta["html"] != "<p>hi</p>" {
+		t.Errorf("producer content should win: %v", meta)
+	}
+}
@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+)
+
+//go:embed defaults
+var defaultFS embed.FS
+
+// loadDefaults reads defaults/<locale>/<type>.<channel>.tmpl. Each file is split into parts by "-- subject --",
+// "-- html --" and "-- text --" marker lines; parts a channel does not use are left out. Lines before the first marker
+// are a note for whoever edits the file (usually the data keys the producer sends) and are not rendered.
+func loadDefaults() (map[string]repository.Template, error) {
+	out := make(map[string]repository.Template)
+	err := fs.WalkDir(defaultFS, "defaults", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".tmpl" {
+			return err
+		}
+		locale := path.Base(path.Dir(p))
+		name := strings.TrimSuffix(path.Base(p), ".tmpl")
+		i := strings.LastIndex(name, ".")
+		if i <= 0 {
+			return fmt.Errorf("templates: %s: want <type>.<channel>.tmpl", p)
+		}
+		src, err := defaultFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		t := repository.Template{Type: name[:i], Channel: name[i+1:], Locale: locale, Status: "builtin"}
+		if err := splitParts(string(src), &t); err != nil {
+			return fmt.Errorf("templates: %s: %w", p, err)
+		}
+		if err := Validate(&t); err != nil {
+			return fmt.Errorf("templates: %s: %w", p, err)
+		}
+		out[key(t.Type, t.Channel, t.Locale)] = t
+		return nil
+	})
+	return out, err
+}
+
+func splitParts(src string, t *repository.Template) error {
+	var part *string
+	var b strings.Builder
+	flush := func() {
+		if part != nil {
+			*part = strings.TrimSpace(b.String())
+		}
+		b.Reset()
+	}
+	sc := bufio.NewScanner(strings.NewReader(src))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "-- ") && strings.HasSuffix(line, " --") {
+			flush()
+			switch strings.TrimSpace(line[3 : len(line)-3]) {
+			case "subject":
+				part = &t.Subject
+			case "html":
+				part = &t.HTML
+			case "text":
+				part = &t.Text
+			default:
+				return fmt.Errorf("unknown part %q", line)
+			}
+			continue
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	flush()
+	return sc.Err()
+}
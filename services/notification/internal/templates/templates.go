@@ -0,0 +1,227 @@
+// Package templates renders notification copy from versioned, localized templates keyed by event type, channel and
+// locale. Built-in defaults ship with the service (defaults/<locale>/<type>.<channel>.tmpl); versions activated through
+// the admin API override them without a deploy.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+)
+
+// ErrRender is returned when a template fails to execute, usually because the event did not carry a key it uses.
+var ErrRender = errors.New("template render failed")
+
+// DefaultLocale is used when the user has no language set, or nothing is written in theirs.
+const DefaultLocale = "en"
+
+// Store returns the active admin-edited version for a type, channel and locale (nil when there is none).
+type Store interface {
+	Active(ctx context.Context, eventType, channel, locale string) (*repository.Template, error)
+}
+
+// Rendered is a template executed against an event's data. Version 0 means the built-in default was used.
+type Rendered struct {
+	Subject string
+	HTML    string
+	Text    string
+	Locale  string
+	Version int
+}
+
+// Registry looks templates up in the store first and the built-in defaults second, falling back from the user's
+// locale to its base language and then to DefaultLocale.
+type Registry struct {
+	store    Store
+	defaults map[string]repository.Template
+}
+
+// NewRegistry loads the built-in defaults. store may be nil, in which case only the defaults are used.
+func NewRegistry(store Store) (*Registry, error) {
+	defaults, err := loadDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{store: store, defaults: defaults}, nil
+}
+
+// Render executes the template for type and channel in the best match for locale. Returns nil, nil when no template
+// exists; a template that references data the event did not carry is an error.
+func (r *Registry) Render(ctx context.Context, eventType, channel, locale string, data map[string]interface{}) (*Rendered, error) {
+	t, err := r.lookup(ctx, eventType, channel, locale)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	out, err := Execute(t, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s/%s/%s v%d: %v", ErrRender, t.Type, t.Channel, t.Locale, t.Version, err)
+	}
+	return out, nil
+}
+
+// Builtin returns the default shipped for type, channel and locale, or nil.
+func (r *Registry) Builtin(eventType, channel, locale string) *repository.Template {
+	if t, ok := r.defaults[key(eventType, channel, locale)]; ok {
+		return &t
+	}
+	return nil
+}
+
+// Builtins returns every shipped default.
+func (r *Registry) Builtins() []repository.Template {
+	out := make([]repository.Template, 0, len(r.defaults))
+	for _, t := range r.defaults {
+		out = append(out, t)
+	}
+	return out
+}
+
+func (r *Registry) lookup(ctx context.Context, eventType, channel, locale string) (*repository.Template, error) {
+	for _, loc := range Candidates(locale) {
+		if r.store != nil {
+			t, err := r.store.Active(ctx, eventType, channel, loc)
+			if err != nil {
+				return nil, err
+			}
+			if t != nil {
+				return t, nil
+			}
+		}
+		if t := r.Builtin(eventType, channel, loc); t != nil {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// Candidates lists the locales to try for a user's language setting, most specific first: "yo-NG" gives yo-ng, yo, en.
+func Candidates(locale string) []string {
+	locale = NormalizeLocale(locale)
+	var out []string
+	if locale != "" {
+		out = append(out, locale)
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			out = append(out, locale[:i])
+		}
+	}
+	if locale != DefaultLocale {
+		out = append(out, DefaultLocale)
+	}
+	return out
+}
+
+// NormalizeLocale lowercases a language tag and uses '-' as the separator.
+func NormalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// Execute renders t against data. Subject and Text use text/template; HTML uses html/template so values are escaped.
+func Execute(t *repository.Template, data map[string]interface{}) (*Rendered, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	out := &Rendered{Locale: t.Locale, Version: t.Version}
+	var err error
+	if out.Subject, err = executeText("subject", t.Subject, data); err != nil {
+		return nil, err
+	}
+	if out.Text, err = executeText("text", t.Text, data); err != nil {
+		return nil, err
+	}
+	if t.HTML != "" {
+		tmpl, err := htmltemplate.New("html").Option("missingkey=error").Funcs(htmltemplate.FuncMap(funcs)).Parse(t.HTML)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		out.HTML = buf.String()
+	}
+	return out, nil
+}
+
+// Validate parses every part of t without executing it, so a broken template is rejected before it is stored.
+func Validate(t *repository.Template) error {
+	if _, err := texttemplate.New("subject").Funcs(funcs).Parse(t.Subject); err != nil {
+		return err
+	}
+	if _, err := texttemplate.New("text").Funcs(funcs).Parse(t.Text); err != nil {
+		return err
+	}
+	if _, err := htmltemplate.New("html").Funcs(htmltemplate.FuncMap(funcs)).Parse(t.HTML); err != nil {
+		return err
+	}
+	return nil
+}
+
+func executeText(name, src string, data map[string]interface{}) (string, error) {
+	if src == "" {
+		return "", nil
+	}
+	tmpl, err := texttemplate.New(name).Option("missingkey=error").Funcs(funcs).Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// funcs are available to every template: money formats an amount as 12,500.00; default substitutes a fallback for an
+// empty value ({{default "there" .first_name}}).
+var funcs = texttemplate.FuncMap{
+	"money":   money,
+	"default": defaultValue,
+}
+
+func money(v interface{}) string {
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case float32:
+		f = float64(n)
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case string:
+		f, _ = strconv.ParseFloat(n, 64)
+	}
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + frac
+}
+
+func defaultValue(fallback string, v interface{}) interface{} {
+	if v == nil || v == "" {
+		return fallback
+	}
+	return v
+}
+
+func key(eventType, channel, locale string) string {
+	return eventType + "/" + channel + "/" + locale
+}
@@ -0,0 +1,124 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/notification/internal/repository"
+)
+
+type memStore map[string]*repository.Template
+
+func (m memStore) Active(_ context.Context, eventType, channel, locale string) (*repository.Template, error) {
+	return m[key(eventType, channel, locale)], nil
+}
+
+func TestDefaultsRender(t *testing.T) {
+	reg, err := NewRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reg.Render(context.Background(), "transfer_success", "email", "en", map[string]interface{}{
+		"first_name": "Ada", "amount": 12500.5, "beneficiary": "<Tunde>", "transaction_ref": "TRF1",
+	})
+	if err != nil || r == nil {
+		t.Fatalf("render: %v %v", r, err)
+	}
+	if r.Subject != "Transfer successful" || r.Version != 0 {
+		t.Errorf("subject=%q version=%d", r.Subject, r.Version)
+	}
+	if !strings.Contains(r.HTML, "NGN 12,500.50") || !strings.Contains(r.HTML, "&lt;Tunde&gt;") {
+		t.Errorf("html not formatted or not escaped: %s", r.HTML)
+	}
+	if !strings.Contains(r.Text, "to <Tunde> was successful") || !strings.HasPrefix(r.Text, "Hi Ada,") {
+		t.Errorf("text: %s", r.Text)
+	}
+}
+
+func TestDefaultsOptionalParts(t *testing.T) {
+	reg, err := NewRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispute := map[string]interface{}{
+		"first_name": "Ada", "dispute_ref": "DSP1", "transaction_ref": "TRF1", "sla_due_at": "01 May 2026 12:00 UTC",
+		"outcome": "resolved", "amount": 0.0, "resolution_txn_ref": "", "note": "",
+	}
+	r, err := reg.Render(context.Background(), "dispute_resolved", "email", "en", dispute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(r.HTML, "Amount:") || strings.Contains(r.Text, "Amount:") {
+		t.Errorf("unpaid dispute shows an amount: %s", r.Text)
+	}
+	dispute["amount"], dispute["resolution_txn_ref"], dispute["note"] = 5000.0, "RFD1", "Refunded <in full>"
+	if r, err = reg.Render(context.Background(), "dispute_resolved", "email", "en", dispute); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(r.HTML, "NGN 5,000.00 (reference RFD1)") || !strings.Contains(r.HTML, "Refunded &lt;in full&gt;") {
+		t.Errorf("html: %s", r.HTML)
+	}
+
+	change := map[string]interface{}{"first_name": "Ada", "new_value": "a***@example.com", "applied": false}
+	if r, err = reg.Render(context.Background(), "email_change", "email", "en", change); err != nil || r.Subject != "A change to your PayUp email was requested" {
+		t.Fatalf("requested: %+v %v", r, err)
+	}
+	change["applied"] = true
+	if r, err = reg.Render(context.Background(), "email_change", "email", "en", change); err != nil || r.Subject != "Your PayUp email was changed" {
+		t.Fatalf("applied: %+v %v", r, err)
+	}
+}
+
+func TestLocaleFallbackAndOverride(t *testing.T) {
+	store := memStore{
+		key("kyc_approved", "email", "ha"): {Type: "kyc_approved", Channel: "email", Locale: "ha", Version: 3,
+			Subject: "An amince da KYC ɗinka", Text: "Sannu {{.first_name}}"},
+	}
+	reg, err := NewRegistry(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]interface{}{"first_name": "Musa"}
+	r, err := reg.Render(context.Background(), "kyc_approved", "email", "ha-NG", data)
+	if err != nil || r == nil || r.Locale != "ha" || r.Version != 3 || r.Text != "Sannu Musa" {
+		t.Fatalf("ha-NG should use the stored ha version: %+v %v", r, err)
+	}
+	r, err = reg.Render(context.Background(), "kyc_approved", "email", "yo", data)
+	if err != nil || r == nil || r.Locale != "en" || r.Version != 0 {
+		t.Fatalf("yo should fall back to the built-in en: %+v %v", r, err)
+	}
+	if r, err := reg.Render(context.Background(), "no_such_type", "email", "en", data); r != nil || err != nil {
+		t.Fatalf("unknown type: %+v %v", r, err)
+	}
+}
+
+func TestMissingDataIsAnError(t *testing.T) {
+	reg, err := NewRegistry(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = reg.Render(context.Background(), "wallet_opened", "email", "en", map[string]interface{}{"first_name": ""})
+	if !errors.Is(err, ErrRender) {
+		t.Fatalf("want ErrRender, got %v", err)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	got := strings.Join(Candidates("Yo_NG"), ",")
+	if got != "yo-ng,yo,en" {
+		t.Errorf("Candidates(Yo_NG) = %s", got)
+	}
+	if got := strings.Join(Candidates(""), ","); got != "en" {
+		t.Errorf("Candidates(\"\") = %s", got)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	for in, want := range map[interface{}]string{1234567.891: "1,234,567.89", 100: "100.00", -2500.0: "-2,500.00", "999.5": "999.50"} {
+		if got := money(in); got != want {
+			t.Errorf("money(%v) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when an endpoint resolves to an address inside our network.
+var ErrPrivateAddress = errors.New("endpoint resolves to a non-public address")
+
+// maxResponseBody is how much of an endpoint's response is kept in the delivery log.
+const maxResponseBody = 1024
+
+// NewClient returns the HTTP client deliveries are posted with. Unless allowPrivate, it refuses to connect to
+// loopback, private, link-local and other non-public addresses, checked on the address actually dialled so a
+// hostname cannot be pointed inside the network after registration. Redirects are not followed: a partner's endpoint
+// answers for itself.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConnsPerHost:   2,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Attempt is the outcome of posting one delivery.
+type Attempt struct {
+	StatusCode int
+	Response   string // start of the response body
+	Err        error  // transport error or non-2xx status
+	Duration   time.Duration
+}
+
+// OK reports whether the endpoint accepted the delivery (any 2xx).
+func (a Attempt) OK() bool {
+	return a.Err == nil
+}
+
+// Request is one delivery to post: the event body is signed with the endpoint's secret at the time of sending.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	EventID    string
+	EventType  string
+	Body       []byte
+}
+
+// Send posts r and reports how the endpoint answered. Only a 2xx counts as delivered.
+func Send(ctx context.Context, client *http.Client, r Request, now time.Time) Attempt {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return Attempt{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PayUp-Webhooks/1.0")
+	req.Header.Set(HeaderSignature, Sign(r.Secret, r.Body, now))
+	req.Header.Set(HeaderEventID, r.EventID)
+	req.Header.Set(HeaderEventType, r.EventType)
+	req.Header.Set(HeaderDeliveryID, r.DeliveryID)
+	resp, err := client.Do(req)
+	if err != nil {
+		return Attempt{Err: err, Duration: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	a := Attempt{StatusCode: resp.StatusCode, Response: strings.ToValidUTF8(string(body), ""), Duration: time.Since(start)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		a.Err = fmt.Errorf("endpoint answered %d", resp.StatusCode)
+	}
+	return a
+}
+
+func parseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsAbs() {
+		return nil, errors.New("must be an absolute URL")
+	}
+	return u, nil
+}
+
+// publicHost rejects IP literals and names that are obviously internal. Other names are checked when dialled.
+func publicHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return publicIP(ip)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") ||
+		strings.HasSuffix(host, ".internal") || !strings.Contains(host, ".") {
+		return false
+	}
+	return true
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), not covered by net.IP.IsPrivate.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
@@ -0,0 +1,177 @@
+// Package webhooks is the partner side of outbound webhooks: the events partners can subscribe to, the envelope and
+// signature every delivery carries, the retry schedule, and an HTTP client that only reaches public addresses.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types partners can subscribe to. Each comes from an event a service already publishes to Kafka.
+const (
+	EventTransferSuccess = "transfer.success"
+	EventTransferFailed  = "transfer.failed"
+	EventWalletCredited  = "wallet.credited"
+	EventKYCApproved     = "kyc.approved"
+)
+
+// EventTypes lists every type an endpoint may subscribe to, in display order.
+var EventTypes = []string{EventTransferSuccess, EventTransferFailed, EventWalletCredited, EventKYCApproved}
+
+// ValidEventType reports whether t can be subscribed to.
+func ValidEventType(t string) bool {
+	for _, e := range EventTypes {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Headers sent with every delivery. The event ID is the same on every attempt and redelivery, so partners can drop
+// duplicates; the delivery ID is one endpoint's copy of the event.
+const (
+	HeaderSignature  = "PayUp-Signature"
+	HeaderEventID    = "PayUp-Event-Id"
+	HeaderEventType  = "PayUp-Event-Type"
+	HeaderDeliveryID = "PayUp-Delivery-Id"
+)
+
+// Event is the JSON body of a delivery. UserID is the account the event belongs to; only that account's endpoints
+// receive it.
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+	UserID    string                 `json:"-"`
+}
+
+// eventNamespace scopes event IDs so they cannot collide with the delivery log's.
+var eventNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("payup:partner-webhooks"))
+
+// EventID derives an event's ID from what identifies it in its source (a transaction reference, or a Kafka position).
+// The same source event always gets the same ID, which is what keeps a re-consumed Kafka message from being delivered
+// twice.
+func EventID(eventType, key string) string {
+	return uuid.NewSHA1(eventNamespace, []byte(eventType+"/"+key)).String()
+}
+
+// NewSecret returns a signing secret for a new endpoint, or a rotated one.
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the PayUp-Signature value for body sent at t: "t=<unix seconds>,v1=<hex HMAC-SHA256>", where the MAC
+// covers "<unix seconds>.<body>". Signing the timestamp lets partners reject replays of old deliveries.
+func Sign(secret string, body []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+// Verify checks a PayUp-Signature value the way a partner should: the MAC must match and the timestamp must be
+// within tolerance of now.
+func Verify(secret string, body []byte, header string, now time.Time, tolerance time.Duration) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return errors.New("malformed signature header")
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return errors.New("signature timestamp outside tolerance")
+	}
+	want := mac(secret, ts, body)
+	for _, s := range sigs {
+		if hmac.Equal([]byte(s), []byte(want)) {
+			return nil
+		}
+	}
+	return errors.New("signature mismatch")
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Backoff between automatic attempts at one delivery: 30s, 1m, 2m, 4m, ... capped at 6h.
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 6 * time.Hour
+)
+
+// Backoff returns how long to wait after the attempt-th failed attempt.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 20 {
+		return retryMaxDelay
+	}
+	d := retryBaseDelay << (attempt - 1)
+	if d > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return d
+}
+
+// ErrInvalidURL is returned for endpoint URLs that cannot be registered.
+var ErrInvalidURL = errors.New("invalid endpoint url")
+
+// maxURLLength bounds a registered URL.
+const maxURLLength = 2048
+
+// ValidateURL checks an endpoint URL before it is stored: absolute, https (http only when allowHTTP, for local
+// development), no credentials, and not an address inside our network. Hostnames are checked again when dialled,
+// since DNS can change after registration.
+func ValidateURL(raw string, allowHTTP, allowPrivate bool) error {
+	if len(raw) > maxURLLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidURL, maxURLLength)
+	}
+	u, err := parseURL(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && allowHTTP:
+	default:
+		return fmt.Errorf("%w: must use https", ErrInvalidURL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: must not contain credentials", ErrInvalidURL)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if !allowPrivate && !publicHost(u.Hostname()) {
+		return fmt.Errorf("%w: host is not publicly reachable", ErrInvalidURL)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+-- In-app inbox: one row per notification that named a user, whatever channel it went out on. When the same notification
+-- fans out to several channels (email and WhatsApp, say), only the first copy is stored: same type and reference within
+-- a few minutes. Events carrying a code or one-time link (OTPs, verification and reset emails) are not stored.
+CREATE TABLE inbox_notifications (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL,
+  type VARCHAR(60) NOT NULL,
+  category VARCHAR(30) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  body TEXT NOT NULL,
+  data JSONB, -- non-personal event details for deep links, e.g. transaction_ref, amount
+  reference VARCHAR(100) NOT NULL DEFAULT '', -- transaction or request reference from the event, '' when none
+  read_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_inbox_notifications_user ON inbox_notifications(user_id, created_at DESC);
+CREATE INDEX idx_inbox_notifications_dedupe ON inbox_notifications(user_id, type, reference, created_at);
+CREATE INDEX idx_inbox_notifications_unread ON inbox_notifications(user_id) WHERE read_at IS NULL;
+
+-- Per-type, per-channel opt-outs. A missing row means enabled; critical types ignore this table.
+CREATE TABLE notification_preferences (
+  user_id UUID NOT NULL,
+  type VARCHAR(60) NOT NULL,
+  channel VARCHAR(20) NOT NULL, -- email, sms, whatsapp, push, in_app
+  enabled BOOLEAN NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, type, channel)
+);
@@ -0,0 +1,20 @@
+-- Template versions edited through the admin API. Each (type, channel, locale) has numbered versions; at most one is
+-- active and it takes precedence over the built-in default shipped with the service. Drafts can be previewed before
+-- they are activated, and activating an older version is how a bad copy change is rolled back.
+CREATE TABLE notification_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  type VARCHAR(60) NOT NULL,
+  channel VARCHAR(20) NOT NULL, -- email, sms, whatsapp, push, in_app
+  locale VARCHAR(10) NOT NULL, -- en, ha, yo, ig, ... (lowercase)
+  version INT NOT NULL,
+  subject TEXT NOT NULL DEFAULT '', -- email subject, push and inbox title (text/template)
+  html TEXT NOT NULL DEFAULT '', -- email HTML body (html/template)
+  text TEXT NOT NULL DEFAULT '', -- plain-text body: email fallback, SMS, WhatsApp, push and inbox (text/template)
+  status VARCHAR(10) NOT NULL DEFAULT 'draft', -- draft, active, retired
+  created_by VARCHAR(100) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  activated_at TIMESTAMPTZ,
+  UNIQUE (type, channel, locale, version)
+);
+
+CREATE UNIQUE INDEX idx_notification_templates_active ON notification_templates(type, channel, locale) WHERE status = 'active';
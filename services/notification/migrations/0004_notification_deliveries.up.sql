@@ -0,0 +1,22 @@
+-- Delivery log: one row per consumed notification event. The consumer retries a failed send a few times (attempts)
+-- before dead-lettering it; provider delivery webhooks then move a sent row to delivered, read or failed by
+-- (provider, provider_message_id). A replayed dead letter keeps its id, so its attempts carry on counting.
+CREATE TABLE notification_deliveries (
+  id UUID PRIMARY KEY,
+  user_id UUID,
+  type VARCHAR(60) NOT NULL,
+  channel VARCHAR(20) NOT NULL,
+  provider VARCHAR(20) NOT NULL DEFAULT '', -- brevo, termii, whatsapp, push
+  provider_message_id VARCHAR(255) NOT NULL DEFAULT '',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, sent, delivered, read, failed, skipped, dead_letter
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at TIMESTAMPTZ,
+  delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_notification_deliveries_provider_msg ON notification_deliveries(provider, provider_message_id) WHERE provider_message_id <> '';
+CREATE INDEX idx_notification_deliveries_user ON notification_deliveries(user_id, created_at DESC);
+CREATE INDEX idx_notification_deliveries_status ON notification_deliveries(status, created_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS route;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS sent_channel;
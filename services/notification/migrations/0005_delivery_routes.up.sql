@@ -0,0 +1,4 @@
+-- Channel failover: channel stays what the producer asked for; sent_channel is the one that finally carried the
+-- notification, and route lists every channel tried in order ([{channel, provider, status, error, at}]).
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS sent_channel VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS route JSONB NOT NULL DEFAULT '[]';
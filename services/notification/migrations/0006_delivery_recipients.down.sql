@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_notification_deliveries_recipient;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS resent_by;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS resent_from;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS payload;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS subject;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS recipient_masked;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS recipient_hash;
@@ -0,0 +1,11 @@
+-- Support history. Recipients are kept only as a keyed hash (to find "did +234... get it?") and a masked value to
+-- show. payload is the event minus its address so a delivery can be resent; it stays NULL for one-time codes and
+-- events without a user.
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS recipient_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS recipient_masked VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS subject VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS payload JSONB;
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS resent_from UUID REFERENCES notification_deliveries(id) ON DELETE SET NULL;
+ALTER TABLE notification_deliveries ADD COLUMN IF NOT EXISTS resent_by VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_recipient ON notification_deliveries(recipient_hash, created_at DESC) WHERE recipient_hash <> '';
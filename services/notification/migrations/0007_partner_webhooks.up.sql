@@ -0,0 +1,53 @@
+-- Partner webhooks: endpoints an account registers for server-to-server callbacks, and one delivery per endpoint per
+-- event. The signing secret is stored encrypted (AES-GCM, NOTIFICATION_ENCRYPTION_KEY). A delivery is retried with
+-- backoff (next_attempt_at) until the endpoint answers 2xx or attempts run out; every attempt is kept for the partner
+-- to debug their endpoint.
+CREATE TABLE webhook_endpoints (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL,
+  url TEXT NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  secret_enc BYTEA NOT NULL,
+  event_types JSONB NOT NULL DEFAULT '[]', -- e.g. ["transfer.success", "wallet.credited"]
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_endpoints_user ON webhook_endpoints(user_id, created_at);
+
+-- event_id is derived from the source event, so a Kafka message consumed twice does not deliver twice.
+CREATE TABLE webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL,
+  event_id UUID NOT NULL,
+  event_type VARCHAR(40) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, succeeded, failed
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ,
+  last_status_code INT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at TIMESTAMPTZ,
+  UNIQUE (endpoint_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
+
+CREATE TABLE webhook_delivery_attempts (
+  id BIGSERIAL PRIMARY KEY,
+  delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+  attempt INT NOT NULL,
+  manual BOOLEAN NOT NULL DEFAULT false, -- a redelivery the partner asked for
+  status_code INT NOT NULL DEFAULT 0,    -- 0 when no response (timeout, connection refused)
+  error TEXT NOT NULL DEFAULT '',
+  response_body TEXT NOT NULL DEFAULT '', -- first 1 KB
+  duration_ms INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id, attempt);
@@ -1,17 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"github.com/abubakvr/payup-backend/services/payment/internal/bills"
 	"github.com/abubakvr/payup-backend/services/payment/internal/config"
 	"github.com/abubakvr/payup-backend/services/payment/internal/controller"
 	"github.com/abubakvr/payup-backend/services/payment/internal/clients"
 	paymentgrpc "github.com/abubakvr/payup-backend/services/payment/internal/grpc"
 	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
 	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
 	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
 	"github.com/abubakvr/payup-backend/services/payment/internal/router"
@@ -44,6 +48,10 @@ func main() {
 	webhookEventsRepo := repository.NewWebhookEventsRepository(db, cfg.EncryptionKey)
 	transactionRepo := repository.NewTransactionRepository(db, cfg.EncryptionKey)
 	authRepo := repository.NewAuthTokenRepository(db, cfg.EncryptionKey)
+	disputeRepo := repository.NewDisputeRepository(db)
+	paymentRequestRepo := repository.NewPaymentRequestRepository(db, cfg.EncryptionKey)
+	pocketRepo := repository.NewPocketRepository(db)
+	billRepo := repository.NewBillRepository(db, cfg.EncryptionKey)
 
 	var kycClient *clients.KYCClient
 	if cfg.KYCServiceGrpcAddr != "" {
@@ -69,9 +77,89 @@ func main() {
 		log.Printf("payment: 9PSB or encryption key not set; wallet creation disabled")
 	}
 
-	svc := service.NewPaymentService(repo, walletRepo, walletUpgradeRepo, webhookEventsRepo, transactionRepo, producer, producer, kycClient, userClient, psbProvider)
+	var billsProvider bills.Provider
+	switch cfg.BillsProvider {
+	case "":
+		log.Printf("payment: BILLS_PROVIDER not set; bill payments disabled")
+	case "stub":
+		billsProvider = bills.NewStubProvider()
+	default:
+		log.Printf("payment: unknown BILLS_PROVIDER %q; bill payments disabled", cfg.BillsProvider)
+	}
+
+	var pocketInterest service.PocketInterestPolicy
+	if cfg.PocketInterestRatePercent > 0 {
+		pocketInterest = service.FlatPocketInterest(cfg.PocketInterestRatePercent / 100)
+	}
+
+	limitPolicy, err := limits.ParsePolicy(cfg.TierLimitsJSON)
+	if err != nil {
+		log.Fatalf("payment: %v", err)
+	}
+
+	svc := service.NewPaymentService(repo, walletRepo, walletUpgradeRepo, webhookEventsRepo, transactionRepo, disputeRepo, paymentRequestRepo, pocketRepo, pocketInterest, billRepo, time.Duration(cfg.DisputeSLAHours)*time.Hour, cfg.ReceiptSigningKey, cfg.ReceiptVerifyBaseURL, producer, producer, kycClient, userClient, psbProvider, billsProvider, limitPolicy)
+
+	// Keep the phone number and email on wallets in step with changes made in the user service.
+	userConsumer := kafka.NewUserEventsConsumer(brokers, func(ctx context.Context, ev kafka.UserContactChangedEvent) {
+		if err := svc.SyncUserContact(ctx, ev.UserID, ev.Field, ev.PhoneNumber, ev.Email); err != nil {
+			log.Printf("payment: sync wallet contact user=%s: %v", ev.UserID, err)
+		}
+	})
+	if userConsumer != nil {
+		go userConsumer.Start()
+	}
 	ctrl := controller.NewPaymentController(svc, cfg)
 
+	// Expire payment requests past their expiry so listings and status filters stay accurate.
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := svc.ExpirePaymentRequests(context.Background()); err != nil {
+				log.Printf("payment: expire payment requests: %v", err)
+			} else if n > 0 {
+				log.Printf("payment: expired %d payment request(s)", n)
+			}
+		}
+	}()
+
+	// Requery bill payments whose vend outcome is unknown; settles them as success or reversal.
+	if billsProvider != nil {
+		go func() {
+			ticker := time.NewTicker(time.Minute)
+			defer ticker.Stop()
+			for range ticker.C {
+				if n, err := svc.RequeryBillPayments(context.Background()); err != nil {
+					log.Printf("payment: requery bill payments: %v", err)
+				} else if n > 0 {
+					log.Printf("payment: settled %d bill payment(s) on requery", n)
+				}
+			}
+		}()
+	}
+
+	// Accrue savings pocket interest for the previous UTC day, then pay out what has accrued; accrual is once per pocket
+	// per day and payout once per pocket per month, so hourly runs are safe.
+	if pocketInterest != nil {
+		go func() {
+			ticker := time.NewTicker(time.Hour)
+			defer ticker.Stop()
+			for ; ; <-ticker.C {
+				day := time.Now().UTC().AddDate(0, 0, -1)
+				if n, err := svc.AccruePocketInterest(context.Background(), day); err != nil {
+					log.Printf("payment: accrue pocket interest: %v", err)
+				} else if n > 0 {
+					log.Printf("payment: accrued interest on %d pocket(s) for %s", n, day.Format("2006-01-02"))
+				}
+				if n, err := svc.PayPocketInterest(context.Background(), time.Now()); err != nil {
+					log.Printf("payment: pay pocket interest: %v", err)
+				} else if n > 0 {
+					log.Printf("payment: paid interest to %d pocket(s)", n)
+				}
+			}
+		}()
+	}
+
 	r := router.Setup(ctrl)
 
 	// gRPC server
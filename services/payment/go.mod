@@ -3,12 +3,15 @@ module github.com/abubakvr/payup-backend/services/payment
 go 1.25.0
 
 require (
+	github.com/abubakvr/payup-backend/pkg v0.0.0
 	github.com/abubakvr/payup-backend/proto v0.0.0
 	github.com/gin-gonic/gin v1.11.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/golang-jwt/jwt/v5 v5.3.1
 	github.com/google/uuid v1.6.0
 	github.com/jackc/pgx/v5 v5.8.0
 	github.com/redis/go-redis/v9 v9.18.0
+	golang.org/x/image v0.34.0
 	google.golang.org/grpc v1.79.0
 )
 
@@ -42,7 +45,7 @@ require (
 	go.uber.org/atomic v1.11.0 // indirect
 	go.uber.org/mock v0.5.0 // indirect
 	golang.org/x/arch v0.20.0 // indirect
-	golang.org/x/crypto v0.47.0 // indirect
+	golang.org/x/crypto v0.48.0 // indirect
 	golang.org/x/mod v0.32.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/tools v0.41.0 // indirect
@@ -59,4 +62,6 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
 )
 
+replace github.com/abubakvr/payup-backend/pkg => ../../pkg
+
 replace github.com/abubakvr/payup-backend/proto => ../../proto
@@ -0,0 +1,115 @@
+// Package bills defines the biller aggregator boundary for airtime, data, electricity and TV payments.
+// The payment service talks to an aggregator only through Provider; StubProvider is a local implementation for
+// development and tests.
+package bills
+
+import (
+	"context"
+	"errors"
+)
+
+// Biller categories.
+const (
+	CategoryAirtime     = "AIRTIME"
+	CategoryData        = "DATA"
+	CategoryElectricity = "ELECTRICITY"
+	CategoryCableTV     = "CABLE_TV"
+)
+
+// Vend outcomes. Pending means the aggregator accepted the order but has not confirmed delivery; Requery later.
+const (
+	VendSuccess = "SUCCESS"
+	VendPending = "PENDING"
+	VendFailed  = "FAILED"
+)
+
+var (
+	ErrBillerNotFound  = errors.New("biller not found")
+	ErrItemNotFound    = errors.New("biller item not found")
+	ErrInvalidCustomer = errors.New("customer could not be validated")
+	ErrOrderNotFound   = errors.New("order not found at biller")
+)
+
+// Item is a product a biller sells: a data bundle, a TV bouquet, or the open-amount item for airtime and electricity.
+type Item struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"` // fixed price; 0 = customer chooses within the biller's min/max
+}
+
+// Biller is one entry in the catalogue.
+type Biller struct {
+	Code               string  `json:"code"`
+	Name               string  `json:"name"`
+	Category           string  `json:"category"`
+	CustomerIDLabel    string  `json:"customer_id_label"` // e.g. "Phone number", "Meter number", "Smartcard number"
+	RequiresValidation bool    `json:"requires_validation"`
+	MinAmount          float64 `json:"min_amount"`
+	MaxAmount          float64 `json:"max_amount"`
+	Items              []Item  `json:"items"`
+}
+
+// Item returns the biller's item with the given code.
+func (b *Biller) Item(code string) (*Item, error) {
+	for i := range b.Items {
+		if b.Items[i].Code == code {
+			return &b.Items[i], nil
+		}
+	}
+	return nil, ErrItemNotFound
+}
+
+// Customer is the result of a meter/smartcard/phone lookup.
+type Customer struct {
+	CustomerID string `json:"customer_id"`
+	Name       string `json:"name"`
+	Address    string `json:"address,omitempty"`
+	Details    string `json:"details,omitempty"` // e.g. current bouquet, meter type
+}
+
+// VendRequest is one purchase. Reference is ours and must be unique; aggregators deduplicate on it.
+type VendRequest struct {
+	Reference  string
+	BillerCode string
+	ItemCode   string
+	CustomerID string
+	Amount     float64
+	Phone      string // customer phone some billers require for the receipt
+}
+
+// VendResult is the aggregator's answer to a vend or requery.
+type VendResult struct {
+	Status      string // VendSuccess, VendPending or VendFailed
+	ProviderRef string
+	Token       string // electricity token, if any
+	Units       string // e.g. "45.3 kWh"
+	Message     string
+}
+
+// Provider is a biller aggregator.
+type Provider interface {
+	// Name identifies the aggregator in stored payments.
+	Name() string
+	// Catalogue lists the billers and items on sale.
+	Catalogue(ctx context.Context) ([]Biller, error)
+	// ValidateCustomer looks up the customer (meter, smartcard, phone) for a biller before payment.
+	ValidateCustomer(ctx context.Context, billerCode, itemCode, customerID string) (*Customer, error)
+	// Vend buys the item. A transport error means the outcome is unknown and the order must be requeried.
+	Vend(ctx context.Context, req *VendRequest) (*VendResult, error)
+	// Requery returns the current outcome of an earlier Vend by our reference.
+	Requery(ctx context.Context, reference string) (*VendResult, error)
+}
+
+// FindBiller returns the catalogue entry with the given code.
+func FindBiller(ctx context.Context, p Provider, code string) (*Biller, error) {
+	list, err := p.Catalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Code == code {
+			return &list[i], nil
+		}
+	}
+	return nil, ErrBillerNotFound
+}
@@ -0,0 +1,155 @@
+package bills
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// StubProvider is an in-process aggregator with a fixed Nigerian catalogue. Customer IDs steer the outcome so every
+// lifecycle path can be exercised without a real aggregator:
+//   - ending in 0000: customer validation fails
+//   - ending in 8888: vend fails
+//   - ending in 9999: vend is pending; the first requery succeeds
+//   - anything else: vend succeeds (electricity returns a 20-digit token)
+type StubProvider struct {
+	mu     sync.Mutex
+	orders map[string]*VendResult
+}
+
+// NewStubProvider returns a stub aggregator.
+func NewStubProvider() *StubProvider {
+	return &StubProvider{orders: make(map[string]*VendResult)}
+}
+
+var stubCatalogue = []Biller{
+	{Code: "MTN-AIRTIME", Name: "MTN Airtime", Category: CategoryAirtime, CustomerIDLabel: "Phone number", MinAmount: 50, MaxAmount: 50000,
+		Items: []Item{{Code: "AIRTIME", Name: "Airtime"}}},
+	{Code: "AIRTEL-AIRTIME", Name: "Airtel Airtime", Category: CategoryAirtime, CustomerIDLabel: "Phone number", MinAmount: 50, MaxAmount: 50000,
+		Items: []Item{{Code: "AIRTIME", Name: "Airtime"}}},
+	{Code: "GLO-AIRTIME", Name: "Glo Airtime", Category: CategoryAirtime, CustomerIDLabel: "Phone number", MinAmount: 50, MaxAmount: 50000,
+		Items: []Item{{Code: "AIRTIME", Name: "Airtime"}}},
+	{Code: "9MOBILE-AIRTIME", Name: "9mobile Airtime", Category: CategoryAirtime, CustomerIDLabel: "Phone number", MinAmount: 50, MaxAmount: 50000,
+		Items: []Item{{Code: "AIRTIME", Name: "Airtime"}}},
+	{Code: "MTN-DATA", Name: "MTN Data", Category: CategoryData, CustomerIDLabel: "Phone number",
+		Items: []Item{
+			{Code: "MTN-1GB-1D", Name: "1GB - 1 day", Amount: 350},
+			{Code: "MTN-2GB-7D", Name: "2GB - 7 days", Amount: 1000},
+			{Code: "MTN-10GB-30D", Name: "10GB - 30 days", Amount: 4500},
+		}},
+	{Code: "AIRTEL-DATA", Name: "Airtel Data", Category: CategoryData, CustomerIDLabel: "Phone number",
+		Items: []Item{
+			{Code: "AIRTEL-1.5GB-7D", Name: "1.5GB - 7 days", Amount: 1000},
+			{Code: "AIRTEL-10GB-30D", Name: "10GB - 30 days", Amount: 4500},
+		}},
+	{Code: "IKEDC-PREPAID", Name: "Ikeja Electric Prepaid", Category: CategoryElectricity, CustomerIDLabel: "Meter number",
+		RequiresValidation: true, MinAmount: 500, MaxAmount: 500000,
+		Items: []Item{{Code: "PREPAID", Name: "Prepaid token"}}},
+	{Code: "EKEDC-PREPAID", Name: "Eko Electric Prepaid", Category: CategoryElectricity, CustomerIDLabel: "Meter number",
+		RequiresValidation: true, MinAmount: 500, MaxAmount: 500000,
+		Items: []Item{{Code: "PREPAID", Name: "Prepaid token"}}},
+	{Code: "AEDC-POSTPAID", Name: "Abuja Electric Postpaid", Category: CategoryElectricity, CustomerIDLabel: "Account number",
+		RequiresValidation: true, MinAmount: 500, MaxAmount: 1000000,
+		Items: []Item{{Code: "POSTPAID", Name: "Bill payment"}}},
+	{Code: "DSTV", Name: "DStv", Category: CategoryCableTV, CustomerIDLabel: "Smartcard number", RequiresValidation: true,
+		Items: []Item{
+			{Code: "DSTV-PADI", Name: "DStv Padi", Amount: 4400},
+			{Code: "DSTV-COMPACT", Name: "DStv Compact", Amount: 15700},
+			{Code: "DSTV-PREMIUM", Name: "DStv Premium", Amount: 37000},
+		}},
+	{Code: "GOTV", Name: "GOtv", Category: CategoryCableTV, CustomerIDLabel: "IUC number", RequiresValidation: true,
+		Items: []Item{
+			{Code: "GOTV-JINJA", Name: "GOtv Jinja", Amount: 3900},
+			{Code: "GOTV-MAX", Name: "GOtv Max", Amount: 8500},
+		}},
+}
+
+// Name implements Provider.
+func (p *StubProvider) Name() string { return "stub" }
+
+// Catalogue implements Provider.
+func (p *StubProvider) Catalogue(ctx context.Context) ([]Biller, error) {
+	out := make([]Biller, len(stubCatalogue))
+	copy(out, stubCatalogue)
+	return out, nil
+}
+
+// ValidateCustomer implements Provider.
+func (p *StubProvider) ValidateCustomer(ctx context.Context, billerCode, itemCode, customerID string) (*Customer, error) {
+	b, err := FindBiller(ctx, p, billerCode)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := b.Item(itemCode); err != nil {
+		return nil, err
+	}
+	if len(customerID) < 6 || strings.HasSuffix(customerID, "0000") {
+		return nil, ErrInvalidCustomer
+	}
+	c := &Customer{CustomerID: customerID, Name: "Test Customer " + customerID[len(customerID)-4:]}
+	switch b.Category {
+	case CategoryElectricity:
+		c.Address = "12 Stub Street, Lagos"
+		c.Details = b.Items[0].Name
+	case CategoryCableTV:
+		c.Details = "Current bouquet: " + b.Items[0].Name
+	}
+	return c, nil
+}
+
+// Vend implements Provider.
+func (p *StubProvider) Vend(ctx context.Context, req *VendRequest) (*VendResult, error) {
+	b, err := FindBiller(ctx, p, req.BillerCode)
+	if err != nil {
+		return nil, err
+	}
+	res := &VendResult{Status: VendSuccess, ProviderRef: "STUB-" + req.Reference}
+	switch {
+	case strings.HasSuffix(req.CustomerID, "8888"):
+		res.Status, res.Message = VendFailed, "biller declined the order"
+	case strings.HasSuffix(req.CustomerID, "9999"):
+		res.Status, res.Message = VendPending, "awaiting biller confirmation"
+	}
+	if b.Category == CategoryElectricity && b.Items[0].Code == "PREPAID" && res.Status != VendFailed {
+		res.Token = stubToken()
+		res.Units = fmt.Sprintf("%.1f kWh", req.Amount/68.0)
+	}
+	p.mu.Lock()
+	stored := *res
+	p.orders[req.Reference] = &stored
+	p.mu.Unlock()
+	if res.Status == VendPending {
+		res.Token, res.Units = "", ""
+	}
+	return res, nil
+}
+
+// Requery implements Provider. Pending orders complete on the first requery.
+func (p *StubProvider) Requery(ctx context.Context, reference string) (*VendResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	res, ok := p.orders[reference]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	if res.Status == VendPending {
+		res.Status, res.Message = VendSuccess, ""
+	}
+	out := *res
+	return &out, nil
+}
+
+func stubToken() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	var sb strings.Builder
+	for i, c := range b {
+		if i > 0 && i%4 == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte('0' + c%10)
+	}
+	return sb.String()
+}
@@ -44,6 +44,18 @@ func (c *KYCClient) GetKYCForWallet(ctx context.Context, userID string) (*kycpb.
 	return c.client.GetKYCForWallet(ctx, &kycpb.GetKYCForWalletRequest{UserId: userID})
 }
 
+// GetKYCLevel returns the user's KYC level (0 when KYC was never started). Returns 0 and no error if client is nil.
+func (c *KYCClient) GetKYCLevel(ctx context.Context, userID string) (int, error) {
+	if c == nil || c.client == nil {
+		return 0, nil
+	}
+	resp, err := c.client.GetKYCStatus(ctx, &kycpb.GetKYCStatusRequest{UserId: userID})
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.GetKycLevel()), nil
+}
+
 // GetKYCForWalletUpgrade returns KYC data and image bytes for 9PSB wallet_upgrade_file_upload. Returns nil if not found or client is nil.
 func (c *KYCClient) GetKYCForWalletUpgrade(ctx context.Context, userID string) (*kycpb.GetKYCForWalletUpgradeResponse, error) {
 	if c == nil || c.client == nil {
@@ -46,10 +46,13 @@ func (c *UserClient) GetUserForKYC(ctx context.Context, userID string) (*userpb.
 	return c.kycClient.GetUserForKYC(ctx, &userpb.GetUserForKYCRequest{UserId: userID})
 }
 
-// ValidateTransfer checks user is allowed to transfer (PIN, restricted, transfers paused). Daily/monthly limits checked by payment.
-func (c *UserClient) ValidateTransfer(ctx context.Context, userID string, amount float64, pin string) (*userpb.ValidateTransferResponse, error) {
+// ValidateTransfer checks user is allowed to transfer (PIN or passkey approval, restricted, transfers paused, step-up
+// for large amounts). passkeyAssertion replaces the PIN when set and must have been begun for the same payee
+// (beneficiaryBank/beneficiaryAccount; biller code and customer ID for bills). Daily/monthly limits checked by payment.
+func (c *UserClient) ValidateTransfer(ctx context.Context, userID string, amount float64, pin, stepUpToken, passkeyAssertion, beneficiaryBank, beneficiaryAccount string) (*userpb.ValidateTransferResponse, error) {
 	if c == nil || c.paymentClient == nil {
 		return &userpb.ValidateTransferResponse{Allowed: false, Message: "user service not configured"}, nil
 	}
-	return c.paymentClient.ValidateTransfer(ctx, &userpb.ValidateTransferRequest{UserId: userID, Amount: amount, Pin: pin})
+	return c.paymentClient.ValidateTransfer(ctx, &userpb.ValidateTransferRequest{UserId: userID, Amount: amount, Pin: pin, StepUpToken: stepUpToken,
+		PasskeyAssertion: passkeyAssertion, BeneficiaryBankCode: beneficiaryBank, BeneficiaryAccountNumber: beneficiaryAccount})
 }
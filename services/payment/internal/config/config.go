@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
@@ -26,15 +28,34 @@ type Config struct {
 	PsbPassword     string
 	PsbClientID     string
 	PsbClientSecret string
+	// Shared secret 9PSB signs webhooks with (PSB_WEBHOOK_SECRET). Webhooks are refused while it is empty.
+	PsbWebhookSecret string
 
 	// 64 hex chars (32 bytes) for AES-256; encrypts auth tokens at rest in auth_tokens
 	EncryptionKey string
 
-	// JWT secret for decoding user_id from Bearer token on transfer route (same as user service).
-	JWTSecret string
+	// User service JWKS used to verify Bearer tokens (e.g. http://user-service:8001/.well-known/jwks.json).
+	JWKSURL string
 
 	KYCServiceGrpcAddr  string // e.g. kyc-service:9002
 	UserServiceGrpcAddr string // e.g. user-service:9001
+
+	// Hours from opening until a transaction dispute breaches its resolution SLA (DISPUTE_SLA_HOURS, default 72).
+	DisputeSLAHours int
+
+	// HMAC key for verification codes printed on transaction receipts (RECEIPT_SIGNING_KEY). Receipts are disabled if empty.
+	ReceiptSigningKey string
+	// Public URL prefix printed on receipts, e.g. https://payup.ng/receipts/verify/ (RECEIPT_VERIFY_BASE_URL, optional).
+	ReceiptVerifyBaseURL string
+
+	// Annual interest accrued on savings pockets, in percent (POCKET_INTEREST_RATE_PERCENT, default 0 = no interest).
+	PocketInterestRatePercent float64
+
+	// Biller aggregator for airtime, data, electricity and TV (BILLS_PROVIDER): "stub" for the local stub; empty disables bills.
+	BillsProvider string
+
+	// Tier limit overrides as JSON keyed by tier (TIER_LIMITS_JSON); tiers left out keep the CBN defaults. See limits.ParsePolicy.
+	TierLimitsJSON string
 }
 
 func Load() *Config {
@@ -62,24 +83,48 @@ func Load() *Config {
 	if kafkaBroker == "" {
 		kafkaBroker = "redpanda:9092"
 	}
+	disputeSLAHours := 72
+	if v := os.Getenv("DISPUTE_SLA_HOURS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			disputeSLAHours = n
+		}
+	}
+	var pocketInterestRate float64
+	if v := os.Getenv("POCKET_INTEREST_RATE_PERCENT"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+			pocketInterestRate = f
+		}
+	}
+	jwksURL := os.Getenv("USER_JWKS_URL")
+	if jwksURL == "" {
+		jwksURL = "http://user-service:8001/.well-known/jwks.json"
+	}
 	return &Config{
-		Port:                port,
-		GrpcPort:            grpcPort,
-		DatabaseURL:         dbURL,
-		KafkaBroker:         kafkaBroker,
-		RedisAddr:           os.Getenv("REDIS_ADDR"),
-		RedisPassword:       os.Getenv("REDIS_PASSWORD"),
-		PsbBaseURL:          os.Getenv("PSB_BASE_URL"),
-		PsbBaseURL2:         os.Getenv("PSB_BASE_URL"), // if empty, use PsbBaseURL for wallet_other_banks
-		PsbWaasBaseURL:      os.Getenv("PSB_BASE_URL"), // e.g. http://102.216.128.75:9090/waas for debit/credit
-		PsbUsername:         os.Getenv("PSB_USERNAME"),
-		PsbPassword:         os.Getenv("PSB_PASSWORD"),
-		PsbClientID:         os.Getenv("PSB_CLIENT_ID"),
-		PsbClientSecret:     os.Getenv("PSB_CLIENT_SECRET"),
-		EncryptionKey:       os.Getenv("PAYMENT_ENCRYPTION_KEY"),
-		JWTSecret:           os.Getenv("JWT_SECRET"),
-		KYCServiceGrpcAddr:  os.Getenv("KYC_SERVICE_GRPC_ADDR"),
-		UserServiceGrpcAddr: os.Getenv("USER_SERVICE_GRPC_ADDR"),
+		Port:                 port,
+		GrpcPort:             grpcPort,
+		DatabaseURL:          dbURL,
+		KafkaBroker:          kafkaBroker,
+		RedisAddr:            os.Getenv("REDIS_ADDR"),
+		RedisPassword:        os.Getenv("REDIS_PASSWORD"),
+		PsbBaseURL:           os.Getenv("PSB_BASE_URL"),
+		PsbBaseURL2:          os.Getenv("PSB_BASE_URL"), // if empty, use PsbBaseURL for wallet_other_banks
+		PsbWaasBaseURL:       os.Getenv("PSB_BASE_URL"), // e.g. http://102.216.128.75:9090/waas for debit/credit
+		PsbUsername:          os.Getenv("PSB_USERNAME"),
+		PsbPassword:          os.Getenv("PSB_PASSWORD"),
+		PsbClientID:          os.Getenv("PSB_CLIENT_ID"),
+		PsbClientSecret:      os.Getenv("PSB_CLIENT_SECRET"),
+		PsbWebhookSecret:     os.Getenv("PSB_WEBHOOK_SECRET"),
+		EncryptionKey:        os.Getenv("PAYMENT_ENCRYPTION_KEY"),
+		JWKSURL:              jwksURL,
+		KYCServiceGrpcAddr:   os.Getenv("KYC_SERVICE_GRPC_ADDR"),
+		UserServiceGrpcAddr:  os.Getenv("USER_SERVICE_GRPC_ADDR"),
+		DisputeSLAHours:      disputeSLAHours,
+		ReceiptSigningKey:    os.Getenv("RECEIPT_SIGNING_KEY"),
+		ReceiptVerifyBaseURL: os.Getenv("RECEIPT_VERIFY_BASE_URL"),
+
+		PocketInterestRatePercent: pocketInterestRate,
+		BillsProvider:             strings.ToLower(strings.TrimSpace(os.Getenv("BILLS_PROVIDER"))),
+		TierLimitsJSON:            os.Getenv("TIER_LIMITS_JSON"),
 	}
 }
 
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/auth"
+	"github.com/abubakvr/payup-backend/services/payment/internal/bills"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/abubakvr/payup-backend/services/payment/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateBillCustomerRequest is the JSON body for POST /wallet/bills/validate.
+type ValidateBillCustomerRequest struct {
+	BillerCode string `json:"biller_code" binding:"required"`
+	ItemCode   string `json:"item_code" binding:"required"`
+	CustomerID string `json:"customer_id" binding:"required"`
+}
+
+// PayBillRequest is the JSON body for POST /wallet/bills/pay.
+type PayBillRequest struct {
+	BillerCode      string          `json:"biller_code" binding:"required"`
+	ItemCode        string          `json:"item_code" binding:"required"`
+	CustomerID      string          `json:"customer_id" binding:"required"`
+	Amount          float64         `json:"amount"`           // required for open-amount items (airtime, electricity)
+	Phone           string          `json:"phone"`            // token recipient; defaults to the account phone
+	DeliveryChannel string          `json:"delivery_channel"` // sms (default) or whatsapp
+	Pin             string          `json:"pin" binding:"omitempty,len=4"`
+	Passkey         json.RawMessage `json:"passkey"` // instead of pin
+}
+
+// GetBillers returns the biller catalogue. Query: category (AIRTIME, DATA, ELECTRICITY, CABLE_TV). Requires JWT.
+func (c *PaymentController) GetBillers(ctx *gin.Context) {
+	if _, err := auth.UserIDFromContext(ctx); err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	list, err := c.svc.BillCatalogue(ctx.Request.Context(), ctx.Query("category"))
+	if err != nil {
+		billError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"billers": list})
+}
+
+// ValidateBillCustomer looks up a meter, smartcard or phone number before payment. Requires JWT.
+func (c *PaymentController) ValidateBillCustomer(ctx *gin.Context) {
+	if _, err := auth.UserIDFromContext(ctx); err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body ValidateBillCustomerRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: biller_code, item_code and customer_id required", CodeBadRequest)
+		return
+	}
+	cust, err := c.svc.ValidateBillCustomer(ctx.Request.Context(), body.BillerCode, body.ItemCode, body.CustomerID)
+	if err != nil {
+		billError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, cust)
+}
+
+// PayBill buys airtime, data, electricity or TV from the wallet. Requires JWT and X-Idempotency-Key. Responds 201 when the
+// vend succeeded and 202 while the outcome is pending (poll GET /wallet/bills/:bill_ref).
+func (c *PaymentController) PayBill(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	idempotencyKey := strings.TrimSpace(ctx.GetHeader("X-Idempotency-Key"))
+	if idempotencyKey == "" {
+		Error(ctx, http.StatusBadRequest, "X-Idempotency-Key header is required for bill payments", CodeBadRequest)
+		return
+	}
+	if cached, ok := c.idem.Get(ctx.Request.Context(), userID, idempotencyKey); ok && len(cached) > 0 {
+		ctx.Data(http.StatusOK, "application/json", cached)
+		return
+	}
+	var body PayBillRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: biller_code, item_code, customer_id and pin (4 digits) required", CodeBadRequest)
+		return
+	}
+	if msg := checkApproval(body.Pin, body.Passkey); msg != "" {
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+		return
+	}
+	b, err := c.svc.PayBill(ctx.Request.Context(), &service.PayBillParams{
+		UserID:           userID,
+		BillerCode:       body.BillerCode,
+		ItemCode:         body.ItemCode,
+		CustomerID:       body.CustomerID,
+		Amount:           body.Amount,
+		Phone:            body.Phone,
+		DeliveryChannel:  body.DeliveryChannel,
+		Pin:              body.Pin,
+		StepUpToken:      ctx.GetHeader("X-Step-Up-Token"),
+		PasskeyAssertion: string(body.Passkey),
+		IdempotencyKey:   idempotencyKey,
+	})
+	if err != nil {
+		billError(ctx, err)
+		return
+	}
+	status, msg := http.StatusCreated, "Bill payment successful"
+	switch b.Status {
+	case repository.BillStatusPending:
+		status, msg = http.StatusAccepted, "Bill payment is processing"
+	case repository.BillStatusReversed, repository.BillStatusFailed:
+		status, msg = http.StatusOK, "Bill payment failed"
+	}
+	resp := ApiResponse{Status: "success", Message: msg, ResponseCode: CodeSuccess, Data: billPaymentJSON(b)}
+	if b.Status != repository.BillStatusPending {
+		bodyBytes, _ := json.Marshal(resp)
+		c.idem.Set(ctx.Request.Context(), userID, idempotencyKey, bodyBytes)
+	}
+	ctx.JSON(status, resp)
+}
+
+// ListBillPayments returns the user's bill payments. Query: category, limit (default 20, max 100), offset. Requires JWT.
+func (c *PaymentController) ListBillPayments(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	list, err := c.svc.ListMyBillPayments(ctx.Request.Context(), userID, ctx.Query("category"), limit, offset)
+	if err != nil {
+		billError(ctx, err)
+		return
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, billPaymentJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"bill_payments": out})
+}
+
+// GetBillPayment returns one bill payment, including the token for electricity. Requires JWT.
+func (c *PaymentController) GetBillPayment(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	b, err := c.svc.GetMyBillPayment(ctx.Request.Context(), userID, ctx.Param("bill_ref"))
+	if err != nil {
+		billError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, billPaymentJSON(b))
+}
+
+func billError(ctx *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, service.ErrBillsNotConfigured):
+		Error(ctx, http.StatusServiceUnavailable, msg, CodeInternal)
+	case errors.Is(err, service.ErrBillPaymentNotFound), errors.Is(err, bills.ErrBillerNotFound), errors.Is(err, bills.ErrItemNotFound),
+		strings.Contains(msg, "no active wallet"):
+		Error(ctx, http.StatusNotFound, msg, CodeConflict)
+	case errors.Is(err, bills.ErrInvalidCustomer), strings.Contains(msg, "invalid user_id"), strings.Contains(msg, "must be"):
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+	default:
+		transferError(ctx, err)
+	}
+}
+
+// billPaymentJSON is the user-facing view of a bill payment.
+func billPaymentJSON(b *repository.BillPaymentRow) gin.H {
+	return gin.H{
+		"bill_ref":           b.BillRef,
+		"transaction_ref":    b.BillRef,
+		"category":           b.Category,
+		"biller_code":        b.BillerCode,
+		"biller_name":        b.BillerName,
+		"item_code":          b.ItemCode,
+		"item_name":          b.ItemName,
+		"customer_id":        b.CustomerID,
+		"customer_name":      b.CustomerName,
+		"amount":             b.Amount,
+		"status":             b.Status,
+		"transaction_status": b.TransactionStatus,
+		"token":              b.Token,
+		"units":              b.Units,
+		"failure_reason":     b.FailureReason,
+		"delivery_channel":   b.DeliveryChannel,
+		"token_delivered_at": formatTimePtr(b.TokenDeliveredAt),
+		"completed_at":       formatTimePtr(b.CompletedAt),
+		"created_at":         b.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -9,13 +9,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/pkg/jwtauth"
 	"github.com/abubakvr/payup-backend/services/payment/internal/auth"
 	"github.com/abubakvr/payup-backend/services/payment/internal/config"
 	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
 	"github.com/abubakvr/payup-backend/services/payment/internal/idempotency"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
+	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
 	"github.com/abubakvr/payup-backend/services/payment/internal/service"
 	"github.com/abubakvr/payup-backend/services/payment/internal/validator"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 const idempotencyTTL = 24 * time.Hour
@@ -25,12 +31,27 @@ type PaymentController struct {
 	svc    *service.PaymentService
 	cfg    *config.Config
 	idem   *idempotency.Store
+	authn  *authn.Authenticator
 }
 
 // NewPaymentController returns a new controller.
 func NewPaymentController(svc *service.PaymentService, cfg *config.Config) *PaymentController {
 	idem := idempotency.NewStore(cfg.RedisAddr, cfg.RedisPassword, idempotencyTTL)
-	return &PaymentController{svc: svc, cfg: cfg, idem: idem}
+	// The deny-list is written by the user service (logout, session revocation, restriction) in the shared Redis.
+	var rdb redis.UniversalClient
+	if cfg.RedisAddr != "" {
+		rdb = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword})
+	}
+	tokens := authn.NewAuthenticator(jwtauth.NewUserVerifier(cfg.JWKSURL), authn.NewDenyList(rdb, time.Hour))
+	return &PaymentController{svc: svc, cfg: cfg, idem: idem, authn: tokens}
+}
+
+// RequireUser is the middleware for user routes: a valid, non-revoked access token (not a 2FA pending token).
+// Handlers read the user with auth.UserIDFromContext.
+func (c *PaymentController) RequireUser() gin.HandlerFunc {
+	return c.authn.Middleware(func(ctx *gin.Context, err error) {
+		AbortUnauthorized(ctx, "invalid or missing token")
+	})
 }
 
 // Health returns 200 if the service and DB are healthy.
@@ -85,7 +106,7 @@ func (c *PaymentController) OpenWallet(ctx *gin.Context) {
 
 // GetWallet returns the authenticated user's wallet details from the database (account number, account name, status). Requires JWT.
 func (c *PaymentController) GetWallet(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -112,7 +133,7 @@ func (c *PaymentController) GetWallet(ctx *gin.Context) {
 
 // GetWalletTransactions returns the authenticated user's wallet transaction history. Query: limit (default 20, max 100), offset (default 0). Requires JWT.
 func (c *PaymentController) GetWalletTransactions(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -162,7 +183,7 @@ func (c *PaymentController) GetWalletTransactions(ctx *gin.Context) {
 
 // GetTransactionDetail returns a single transaction by transaction_ref for the authenticated user's wallet. Requires JWT.
 func (c *PaymentController) GetTransactionDetail(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -189,6 +210,10 @@ func (c *PaymentController) GetTransactionDetail(ctx *gin.Context) {
 		Error(ctx, http.StatusNotFound, "transaction not found", CodeConflict)
 		return
 	}
+	var dispute gin.H
+	if row.DisputeRef != "" {
+		dispute = gin.H{"dispute_ref": row.DisputeRef, "status": row.DisputeStatus}
+	}
 	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{
 		"transaction_ref":   row.TransactionRef,
 		"type":             row.Type,
@@ -201,12 +226,13 @@ func (c *PaymentController) GetTransactionDetail(ctx *gin.Context) {
 		"beneficiary_bank": row.BeneficiaryBank,
 		"beneficiary_name": row.BeneficiaryName,
 		"created_at":       row.CreatedAt.Format(time.RFC3339),
+		"dispute":          dispute,
 	})
 }
 
-// GetBalance returns the authenticated user's wallet balance (live from 9PSB wallet_enquiry). Requires JWT.
+// GetBalance returns the authenticated user's wallet balance (live from 9PSB wallet_enquiry), with savings pockets split out. Requires JWT.
 func (c *PaymentController) GetBalance(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -227,6 +253,7 @@ func (c *PaymentController) GetBalance(ctx *gin.Context) {
 	data := gin.H{
 		"available_balance": result.AvailableBalance,
 		"ledger_balance":    result.LedgerBalance,
+		"savings_balance":   result.SavingsBalance,
 		"account_number":    result.Nuban,
 		"name":              result.Name,
 		"status":            result.Status,
@@ -234,9 +261,51 @@ func (c *PaymentController) GetBalance(ctx *gin.Context) {
 	Success(ctx, http.StatusOK, "Successful", CodeSuccess, data)
 }
 
+// GetWalletLimits returns the tier caps on the user's wallet (single transaction, daily, per channel, maximum balance),
+// today's spend against them and any balance on hold above the maximum.
+func (c *PaymentController) GetWalletLimits(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	result, err := c.svc.GetWalletLimits(ctx.Request.Context(), userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "no active wallet") {
+			Error(ctx, http.StatusNotFound, err.Error(), CodeConflict)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid user_id") {
+			Error(ctx, http.StatusBadRequest, err.Error(), CodeBadRequest)
+			return
+		}
+		Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		return
+	}
+	channels := gin.H{}
+	for ch, caps := range result.Caps.Channels {
+		channels[string(ch)] = gin.H{"single_transaction": caps.SingleTransaction, "daily": caps.Daily}
+	}
+	data := gin.H{
+		"tier":                 result.Tier,
+		"wallet_tier":          result.WalletTier,
+		"single_transaction":   result.Caps.SingleTransaction,
+		"daily":                result.Caps.Daily,
+		"max_balance":          result.Caps.MaxBalance,
+		"channels":             channels,
+		"daily_spent":          result.DailySpent,
+		"daily_remaining":      result.DailyRemaining,
+		"daily_spent_transfer": result.DailyByChannel[limits.ChannelTransfer],
+		"daily_spent_bills":    result.DailyByChannel[limits.ChannelBills],
+		"balance":              result.Balance,
+		"held_balance":         result.HeldAboveMaximum,
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, data)
+}
+
 // GetWaasTransactions returns 9PSB WaaS transaction history for the authenticated user's wallet. Query: from_date, to_date (YYYY-MM-DD), limit (default 20). Max 31 days range.
 func (c *PaymentController) GetWaasTransactions(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -294,7 +363,7 @@ func (c *PaymentController) GetWaasTransactions(ctx *gin.Context) {
 
 // GetWaasWalletStatus returns 9PSB WaaS wallet status for the authenticated user's wallet.
 func (c *PaymentController) GetWaasWalletStatus(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -324,7 +393,7 @@ func (c *PaymentController) GetWaasWalletStatus(ctx *gin.Context) {
 
 // GetWalletUpgradeStatus returns wallet upgrade status from 9PSB upgrade_status API (source of truth). Requires JWT. Used by user and admin.
 func (c *PaymentController) GetWalletUpgradeStatus(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -387,21 +456,50 @@ type NinePSBWebhookPayload struct {
 	Data          struct {
 		AccountNumber string `json:"accountNumber"`
 	} `json:"data"`
+
+	// Inbound transfer (event=transfer or credit): same customer/order/transaction shape as wallet_other_banks.
+	Narration string `json:"narration"`
+	Customer  struct {
+		Account struct {
+			Number              string `json:"number"`
+			Name                string `json:"name"`
+			SenderAccountNumber string `json:"senderaccountnumber"`
+			SenderName          string `json:"sendername"`
+		} `json:"account"`
+	} `json:"customer"`
+	Order struct {
+		Amount      string `json:"amount"`
+		Description string `json:"description"`
+	} `json:"order"`
+	Transaction struct {
+		SessionID string `json:"sessionid"`
+		Reference string `json:"reference"`
+	} `json:"transaction"`
 }
 
-// Handle9PSBWebhook POST /webhooks/9psb — receives 9PSB webhooks. For event=wallet-upgrade: inserts webhook_events, finds upgrade request by account_number_hash, finalizes (final_status + wallet tier on APPROVED), marks webhook PROCESSED. Returns 200 so 9PSB does not retry.
+// Handle9PSBWebhook POST /webhooks/9psb — receives 9PSB webhooks. For event=transfer|credit: credits the wallet (see handleInboundCreditWebhook). For event=wallet-upgrade: inserts webhook_events, finds upgrade request by account_number_hash, finalizes (final_status + wallet tier on APPROVED), marks webhook PROCESSED. Returns 200 so 9PSB does not retry.
+// Bodies without a valid X-9PSB-Signature (HMAC of the raw body under PSB_WEBHOOK_SECRET) get 401; with no secret configured every webhook is refused.
 func (c *PaymentController) Handle9PSBWebhook(ctx *gin.Context) {
 	rawBody, err := io.ReadAll(ctx.Request.Body)
 	if err != nil {
 		Error(ctx, http.StatusBadRequest, "invalid body", CodeBadRequest)
 		return
 	}
+	if !psb.VerifyWebhook(c.cfg.PsbWebhookSecret, rawBody, ctx.GetHeader(psb.WebhookSignatureHeader)) {
+		Error(ctx, http.StatusUnauthorized, "invalid webhook signature", CodeUnauthorized)
+		return
+	}
 	var payload NinePSBWebhookPayload
 	if err := json.Unmarshal(rawBody, &payload); err != nil {
 		Error(ctx, http.StatusBadRequest, "invalid JSON", CodeBadRequest)
 		return
 	}
-	if strings.TrimSpace(strings.ToLower(payload.Event)) != "wallet-upgrade" {
+	switch strings.TrimSpace(strings.ToLower(payload.Event)) {
+	case "wallet-upgrade":
+	case "transfer", "credit":
+		c.handleInboundCreditWebhook(ctx, &payload, rawBody)
+		return
+	default:
 		Success(ctx, http.StatusOK, "ok", CodeSuccess, gin.H{"processed": false})
 		return
 	}
@@ -431,6 +529,49 @@ func (c *PaymentController) Handle9PSBWebhook(ctx *gin.Context) {
 	Success(ctx, http.StatusOK, "ok", CodeSuccess, gin.H{"processed": true})
 }
 
+// handleInboundCreditWebhook credits the wallet for an inbound transfer notification and matches it to a payment request.
+// 200 so 9PSB does not retry, except 503 when the credit is not (yet) in the wallet's 9PSB history, which 9PSB retries;
+// duplicates (same session ID) are acknowledged without effect.
+func (c *PaymentController) handleInboundCreditWebhook(ctx *gin.Context, payload *NinePSBWebhookPayload, rawBody []byte) {
+	accountNumber := strings.TrimSpace(payload.Customer.Account.Number)
+	if accountNumber == "" {
+		accountNumber = strings.TrimSpace(nonEmpty(payload.AccountNumber, payload.Data.AccountNumber))
+	}
+	amount, _ := strconv.ParseFloat(strings.TrimSpace(payload.Order.Amount), 64)
+	narration := strings.TrimSpace(nonEmpty(payload.Narration, payload.Order.Description))
+	result, err := c.svc.ProcessInboundCredit(ctx.Request.Context(), &service.InboundCreditParams{
+		AccountNumber: accountNumber,
+		Amount:        amount,
+		SessionID:     strings.TrimSpace(payload.Transaction.SessionID),
+		Narration:     narration,
+		SenderAccount: strings.TrimSpace(payload.Customer.Account.SenderAccountNumber),
+		SenderName:    strings.TrimSpace(payload.Customer.Account.SenderName),
+		RawPayload:    rawBody,
+	})
+	if errors.Is(err, service.ErrInboundCreditUnconfirmed) {
+		ctx.Error(err)
+		Error(ctx, http.StatusServiceUnavailable, err.Error(), CodeInternal)
+		return
+	}
+	if err != nil {
+		ctx.Error(err)
+		Success(ctx, http.StatusOK, "ok", CodeSuccess, gin.H{"processed": false, "error": err.Error()})
+		return
+	}
+	if result.Duplicate {
+		Success(ctx, http.StatusOK, "ok", CodeSuccess, gin.H{"processed": false, "duplicate": true})
+		return
+	}
+	Success(ctx, http.StatusOK, "ok", CodeSuccess, gin.H{"processed": true, "transaction_ref": result.TransactionRef})
+}
+
+func nonEmpty(a, b string) string {
+	if strings.TrimSpace(a) != "" {
+		return a
+	}
+	return b
+}
+
 // BeneficiaryEnquiryRequest is the JSON body for POST /wallet/beneficiary-enquiry (resolve account name for display).
 type BeneficiaryEnquiryRequest struct {
 	BankCode      string `json:"bank_code" binding:"required"`
@@ -439,7 +580,7 @@ type BeneficiaryEnquiryRequest struct {
 
 // BeneficiaryEnquiry resolves beneficiary name: 9PSB (120001) uses wallet_enquiry, other banks use other_banks_enquiry. Requires JWT.
 func (c *PaymentController) BeneficiaryEnquiry(ctx *gin.Context) {
-	_, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	_, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -475,12 +616,14 @@ type TransferRequest struct {
 	BankCode                  string  `json:"bank_code" binding:"required"`
 	BeneficiaryName           string  `json:"beneficiary_name" binding:"required"`
 	BeneficiaryAccountNumber string  `json:"beneficiary_account_number" binding:"required"`
-	Pin                       string  `json:"pin" binding:"required,len=4"`
+	Pin                       string  `json:"pin" binding:"omitempty,len=4"`
+	// Passkey is an approval over a challenge from the user service's POST /passkeys/transaction/begin, instead of pin.
+	Passkey json.RawMessage `json:"passkey"`
 }
 
 // TransferToOtherBank handles POST /transfers. Requires JWT; optional X-Idempotency-Key.
 func (c *PaymentController) TransferToOtherBank(ctx *gin.Context) {
-	userID, err := auth.DecodeUserIDFromRequest(ctx.GetHeader("Authorization"), c.cfg.JWTSecret)
+	userID, err := auth.UserIDFromContext(ctx)
 	if err != nil {
 		AbortUnauthorized(ctx, "invalid or missing token")
 		return
@@ -490,7 +633,7 @@ func (c *PaymentController) TransferToOtherBank(ctx *gin.Context) {
 		Error(ctx, http.StatusBadRequest, "X-Idempotency-Key header is required for transfer requests", CodeBadRequest)
 		return
 	}
-	if cached, ok := c.idem.Get(ctx.Request.Context(), idempotencyKey); ok && len(cached) > 0 {
+	if cached, ok := c.idem.Get(ctx.Request.Context(), userID, idempotencyKey); ok && len(cached) > 0 {
 		ctx.Data(http.StatusOK, "application/json", cached)
 		return
 	}
@@ -499,8 +642,8 @@ func (c *PaymentController) TransferToOtherBank(ctx *gin.Context) {
 		Error(ctx, http.StatusBadRequest, "invalid body: amount, bank_code, beneficiary_name, beneficiary_account_number, pin (4 digits) required", CodeBadRequest)
 		return
 	}
-	if len(body.Pin) != 4 {
-		Error(ctx, http.StatusBadRequest, "pin must be exactly 4 digits", CodeBadRequest)
+	if msg := checkApproval(body.Pin, body.Passkey); msg != "" {
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
 		return
 	}
 	result, err := c.svc.TransferToOtherBank(ctx.Request.Context(), &service.TransferToOtherBankParams{
@@ -510,36 +653,72 @@ func (c *PaymentController) TransferToOtherBank(ctx *gin.Context) {
 		BeneficiaryName:          body.BeneficiaryName,
 		BeneficiaryAccountNumber: body.BeneficiaryAccountNumber,
 		Pin:                      body.Pin,
+		StepUpToken:              ctx.GetHeader("X-Step-Up-Token"),
+		PasskeyAssertion:         string(body.Passkey),
 		IdempotencyKey:           idempotencyKey,
 	})
 	if err != nil {
-		msg := err.Error()
-		if strings.Contains(msg, "insufficient balance") {
-			Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
-			return
-		}
-		if strings.Contains(msg, "invalid PIN") || strings.Contains(msg, "PIN not set") || strings.Contains(msg, "PIN required") {
-			Error(ctx, http.StatusUnauthorized, msg, CodeUnauthorized)
-			return
-		}
-		if strings.Contains(msg, "account restricted") || strings.Contains(msg, "transfers paused") {
-			Error(ctx, http.StatusForbidden, msg, CodeForbidden)
-			return
-		}
-		if strings.Contains(msg, "daily transfer limit") || strings.Contains(msg, "monthly transfer limit") {
-			Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
-			return
-		}
-		if strings.Contains(msg, "beneficiary name does not match") {
-			Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
-			return
-		}
-		Error(ctx, http.StatusInternalServerError, msg, CodeInternal)
+		transferError(ctx, err)
 		return
 	}
 	data := gin.H{"transaction_ref": result.TransactionRef, "session_id": result.SessionID}
 	resp := ApiResponse{Status: "success", Message: "Transfer successful", ResponseCode: CodeSuccess, Data: data}
 	bodyBytes, _ := json.Marshal(resp)
-	c.idem.Set(ctx.Request.Context(), idempotencyKey, bodyBytes)
+	c.idem.Set(ctx.Request.Context(), userID, idempotencyKey, bodyBytes)
 	ctx.JSON(http.StatusCreated, resp)
 }
+
+// checkApproval requires a 4-digit PIN or a passkey approval in a payment body. Returns the error message, or "".
+func checkApproval(pin string, passkey json.RawMessage) string {
+	if len(passkey) > 0 && string(passkey) != "null" {
+		return ""
+	}
+	if len(pin) != 4 {
+		return "pin (4 digits) or passkey required"
+	}
+	return ""
+}
+
+// transferError maps transfer failures (balance, PIN, restrictions, limits, name check) to HTTP responses.
+func transferError(ctx *gin.Context, err error) {
+	msg := err.Error()
+	if errors.Is(err, repository.ErrIdempotencyConflict) {
+		Error(ctx, http.StatusConflict, msg, CodeConflict)
+		return
+	}
+	if strings.Contains(msg, "insufficient balance") {
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+		return
+	}
+	if strings.HasPrefix(msg, "step-up required") {
+		Error(ctx, http.StatusForbidden, msg, CodeStepUpRequired)
+		return
+	}
+	if strings.HasPrefix(msg, "PIN locked") {
+		Error(ctx, http.StatusTooManyRequests, msg, CodeForbidden)
+		return
+	}
+	if strings.Contains(msg, "passkey payments not enabled") {
+		Error(ctx, http.StatusForbidden, msg, CodeForbidden)
+		return
+	}
+	if strings.Contains(msg, "invalid PIN") || strings.Contains(msg, "PIN not set") || strings.Contains(msg, "PIN required") ||
+		strings.HasPrefix(msg, "passkey") {
+		Error(ctx, http.StatusUnauthorized, msg, CodeUnauthorized)
+		return
+	}
+	if strings.Contains(msg, "account restricted") || strings.Contains(msg, "transfers paused") {
+		Error(ctx, http.StatusForbidden, msg, CodeForbidden)
+		return
+	}
+	var limitErr *limits.Error
+	if errors.As(err, &limitErr) {
+		Error(ctx, http.StatusBadRequest, msg, CodeLimitExceeded)
+		return
+	}
+	if strings.Contains(msg, "beneficiary name does not match") {
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+		return
+	}
+	Error(ctx, http.StatusInternalServerError, msg, CodeInternal)
+}
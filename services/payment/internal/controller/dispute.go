@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/auth"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/abubakvr/payup-backend/services/payment/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenDisputeRequest is the JSON body for POST /wallet/transactions/:transaction_ref/disputes.
+type OpenDisputeRequest struct {
+	Reason      string `json:"reason" binding:"required"` // NOT_RECEIVED, WRONG_AMOUNT, DUPLICATE, UNAUTHORIZED, OTHER
+	Description string `json:"description" binding:"required,max=2000"`
+}
+
+// DisputeNoteRequest is the JSON body for POST /wallet/disputes/:dispute_ref/notes.
+type DisputeNoteRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// DisputeAttachmentRequest is the JSON body for POST /wallet/disputes/:dispute_ref/attachments. The file itself is uploaded
+// to storage by the client first; only its location is recorded here.
+type DisputeAttachmentRequest struct {
+	FileName    string `json:"file_name" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,max=100"`
+	URL         string `json:"url" binding:"required"`
+}
+
+// OpenDispute opens a dispute against the given transaction of the authenticated user's wallet. Requires JWT.
+func (c *PaymentController) OpenDispute(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	transactionRef := ctx.Param("transaction_ref")
+	if transactionRef == "" {
+		Error(ctx, http.StatusBadRequest, "transaction_ref is required", CodeBadRequest)
+		return
+	}
+	var body OpenDisputeRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: reason and description (max 2000 chars) required", CodeBadRequest)
+		return
+	}
+	d, err := c.svc.OpenDispute(ctx.Request.Context(), userID, transactionRef, body.Reason, body.Description)
+	if err != nil {
+		c.disputeError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Dispute opened", CodeSuccess, disputeJSON(d))
+}
+
+// ListDisputes returns the authenticated user's disputes (newest first). Query: limit (default 20, max 100), offset. Requires JWT.
+func (c *PaymentController) ListDisputes(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	list, err := c.svc.ListMyDisputes(ctx.Request.Context(), userID, limit, offset)
+	if err != nil {
+		c.disputeError(ctx, err)
+		return
+	}
+	disputes := make([]gin.H, 0, len(list))
+	for i := range list {
+		disputes = append(disputes, disputeJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"disputes": disputes})
+}
+
+// GetDispute returns one of the authenticated user's disputes by dispute_ref, with notes and attachments. Requires JWT.
+func (c *PaymentController) GetDispute(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	detail, err := c.svc.GetMyDispute(ctx.Request.Context(), userID, ctx.Param("dispute_ref"))
+	if err != nil {
+		c.disputeError(ctx, err)
+		return
+	}
+	data := disputeJSON(&detail.Dispute)
+	notes := make([]gin.H, 0, len(detail.Notes))
+	for _, n := range detail.Notes {
+		notes = append(notes, gin.H{
+			"id":          n.ID,
+			"author_type": n.AuthorType,
+			"body":        n.Body,
+			"created_at":  n.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	attachments := make([]gin.H, 0, len(detail.Attachments))
+	for _, a := range detail.Attachments {
+		attachments = append(attachments, gin.H{
+			"id":               a.ID,
+			"uploaded_by_type": a.UploadedByType,
+			"file_name":        a.FileName,
+			"content_type":     a.ContentType,
+			"url":              a.URL,
+			"created_at":       a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	data["notes"] = notes
+	data["attachments"] = attachments
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, data)
+}
+
+// AddDisputeNote adds a note from the authenticated user to their dispute. Requires JWT.
+func (c *PaymentController) AddDisputeNote(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body DisputeNoteRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: body (max 2000 chars) required", CodeBadRequest)
+		return
+	}
+	note, err := c.svc.AddUserDisputeNote(ctx.Request.Context(), userID, ctx.Param("dispute_ref"), body.Body)
+	if err != nil {
+		c.disputeError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Note added", CodeSuccess, gin.H{
+		"id":          note.ID,
+		"author_type": note.AuthorType,
+		"body":        note.Body,
+		"created_at":  note.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+// AddDisputeAttachment records an evidence file on the authenticated user's dispute. Requires JWT.
+func (c *PaymentController) AddDisputeAttachment(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body DisputeAttachmentRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: file_name, content_type and url required", CodeBadRequest)
+		return
+	}
+	a, err := c.svc.AddUserDisputeAttachment(ctx.Request.Context(), userID, ctx.Param("dispute_ref"), body.FileName, body.ContentType, body.URL)
+	if err != nil {
+		c.disputeError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Attachment added", CodeSuccess, gin.H{
+		"id":           a.ID,
+		"file_name":    a.FileName,
+		"content_type": a.ContentType,
+		"url":          a.URL,
+		"created_at":   a.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+func (c *PaymentController) disputeError(ctx *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, service.ErrDisputeNotFound), strings.Contains(msg, "transaction not found"), strings.Contains(msg, "no active wallet"):
+		Error(ctx, http.StatusNotFound, msg, CodeConflict)
+	case errors.Is(err, repository.ErrDisputeAlreadyOpen), errors.Is(err, repository.ErrDisputeStatusConflict), errors.Is(err, service.ErrDisputeClosed):
+		Error(ctx, http.StatusConflict, msg, CodeConflict)
+	case errors.Is(err, service.ErrTransactionNotDisputable), strings.Contains(msg, "invalid"), strings.Contains(msg, "required"), strings.Contains(msg, "must be"):
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+	default:
+		Error(ctx, http.StatusInternalServerError, msg, CodeInternal)
+	}
+}
+
+// disputeJSON is the user-facing view of a dispute (no assignee or internal fields).
+func disputeJSON(d *repository.DisputeRow) gin.H {
+	return gin.H{
+		"dispute_ref":        d.DisputeRef,
+		"transaction_ref":    d.TransactionRef,
+		"reason":             d.Reason,
+		"description":        d.Description,
+		"status":             d.Status,
+		"sla_due_at":         d.SLADueAt.Format(time.RFC3339),
+		"resolution":         d.Resolution,
+		"resolution_amount":  d.ResolutionAmount,
+		"resolution_txn_ref": d.ResolutionTxnRef,
+		"resolution_note":    d.ResolutionNote,
+		"resolved_at":        formatTimePtr(d.ResolvedAt),
+		"created_at":         d.CreatedAt.Format(time.RFC3339),
+		"updated_at":         d.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/auth"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/abubakvr/payup-backend/services/payment/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePaymentRequestRequest is the JSON body for POST /wallet/payment-requests.
+type CreatePaymentRequestRequest struct {
+	Amount             float64 `json:"amount" binding:"required,gt=0"`
+	Note               string  `json:"note" binding:"max=255"`
+	PayerAccountNumber string  `json:"payer_account_number"` // omit for an open pay link
+	ExpiresInHours     int     `json:"expires_in_hours"`     // default 168 (7 days), max 720
+}
+
+// PayPaymentRequestRequest is the JSON body for POST /wallet/payment-requests/:request_ref/pay.
+type PayPaymentRequestRequest struct {
+	Pin     string          `json:"pin" binding:"omitempty,len=4"`
+	Passkey json.RawMessage `json:"passkey"` // instead of pin
+}
+
+// DeclinePaymentRequestRequest is the JSON body for POST /wallet/payment-requests/:request_ref/decline.
+type DeclinePaymentRequestRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// CreatePaymentRequest requests money from another PayUp user (payer_account_number) or creates an open pay link. Requires JWT.
+func (c *PaymentController) CreatePaymentRequest(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body CreatePaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: amount (positive) required; note max 255 chars", CodeBadRequest)
+		return
+	}
+	if body.ExpiresInHours < 0 {
+		Error(ctx, http.StatusBadRequest, "expires_in_hours must be positive", CodeBadRequest)
+		return
+	}
+	req, err := c.svc.CreatePaymentRequest(ctx.Request.Context(), &service.CreatePaymentRequestParams{
+		UserID:             userID,
+		Amount:             body.Amount,
+		Note:               body.Note,
+		PayerAccountNumber: body.PayerAccountNumber,
+		ExpiresIn:          time.Duration(body.ExpiresInHours) * time.Hour,
+	})
+	if err != nil {
+		paymentRequestError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Payment request created", CodeSuccess, paymentRequestJSON(req))
+}
+
+// ListPaymentRequests returns the user's payment requests, newest first. Query: role (sent default, or received), status,
+// limit (default 20, max 100), offset. Requires JWT.
+func (c *PaymentController) ListPaymentRequests(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	list, err := c.svc.ListMyPaymentRequests(ctx.Request.Context(), userID, ctx.Query("role"), ctx.Query("status"), limit, offset)
+	if err != nil {
+		paymentRequestError(ctx, err)
+		return
+	}
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		out = append(out, paymentRequestJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"payment_requests": out})
+}
+
+// GetPaymentRequest returns one payment request the user sent or was asked to pay. Requires JWT.
+func (c *PaymentController) GetPaymentRequest(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	req, err := c.svc.GetMyPaymentRequest(ctx.Request.Context(), userID, ctx.Param("request_ref"))
+	if err != nil {
+		paymentRequestError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, paymentRequestJSON(req))
+}
+
+// PayPaymentRequest pays a request from the user's wallet. Requires JWT and X-Idempotency-Key (same semantics as /transfers).
+func (c *PaymentController) PayPaymentRequest(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	idempotencyKey := strings.TrimSpace(ctx.GetHeader("X-Idempotency-Key"))
+	if idempotencyKey == "" {
+		Error(ctx, http.StatusBadRequest, "X-Idempotency-Key header is required for payments", CodeBadRequest)
+		return
+	}
+	// Cache the response per request: the same key sent to pay another request (or for a transfer) is not a retry of this one.
+	cacheKey := "payment-request:" + strings.ToUpper(ctx.Param("request_ref")) + ":" + idempotencyKey
+	if cached, ok := c.idem.Get(ctx.Request.Context(), userID, cacheKey); ok && len(cached) > 0 {
+		ctx.Data(http.StatusOK, "application/json", cached)
+		return
+	}
+	var body PayPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: pin (4 digits) required", CodeBadRequest)
+		return
+	}
+	if msg := checkApproval(body.Pin, body.Passkey); msg != "" {
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+		return
+	}
+	req, result, err := c.svc.PayPaymentRequest(ctx.Request.Context(), &service.PayPaymentRequestParams{
+		UserID:           userID,
+		RequestRef:       ctx.Param("request_ref"),
+		Pin:              body.Pin,
+		StepUpToken:      ctx.GetHeader("X-Step-Up-Token"),
+		PasskeyAssertion: string(body.Passkey),
+		IdempotencyKey:   idempotencyKey,
+	})
+	if err != nil {
+		paymentRequestError(ctx, err)
+		return
+	}
+	data := paymentRequestJSON(req)
+	data["transaction_ref"] = result.TransactionRef
+	data["session_id"] = result.SessionID
+	resp := ApiResponse{Status: "success", Message: "Payment request paid", ResponseCode: CodeSuccess, Data: data}
+	bodyBytes, _ := json.Marshal(resp)
+	c.idem.Set(ctx.Request.Context(), userID, cacheKey, bodyBytes)
+	ctx.JSON(http.StatusCreated, resp)
+}
+
+// DeclinePaymentRequest declines a request addressed to the user. Requires JWT.
+func (c *PaymentController) DeclinePaymentRequest(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body DeclinePaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: reason max 255 chars", CodeBadRequest)
+		return
+	}
+	req, err := c.svc.DeclinePaymentRequest(ctx.Request.Context(), userID, ctx.Param("request_ref"), body.Reason)
+	if err != nil {
+		paymentRequestError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Payment request declined", CodeSuccess, paymentRequestJSON(req))
+}
+
+// ResolvePayLink is the public view of a pay link (no auth): requester name, PayUp account and bank for funding by bank
+// transfer, amount and status. The reference must be included in the transfer narration to settle the request.
+func (c *PaymentController) ResolvePayLink(ctx *gin.Context) {
+	link, err := c.svc.ResolvePayLink(ctx.Request.Context(), ctx.Param("request_ref"))
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentRequestNotFound) {
+			Error(ctx, http.StatusNotFound, err.Error(), CodeConflict)
+			return
+		}
+		Error(ctx, http.StatusInternalServerError, "could not resolve pay link", CodeInternal)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{
+		"request_ref":    link.RequestRef,
+		"requester_name": link.RequesterName,
+		"account_number": link.AccountNumber,
+		"bank_name":      link.BankName,
+		"bank_code":      link.BankCode,
+		"amount":         link.Amount,
+		"note":           link.Note,
+		"status":         link.Status,
+		"expires_at":     link.ExpiresAt.Format(time.RFC3339),
+		"narration":      link.RequestRef,
+	})
+}
+
+func paymentRequestError(ctx *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, service.ErrPaymentRequestNotFound), strings.Contains(msg, "no active wallet"), strings.Contains(msg, "payer account not found"):
+		Error(ctx, http.StatusNotFound, msg, CodeConflict)
+	case errors.Is(err, repository.ErrPaymentRequestNotPending), errors.Is(err, service.ErrPaymentRequestExpired):
+		Error(ctx, http.StatusConflict, msg, CodeConflict)
+	case strings.Contains(msg, "invalid user_id"), strings.Contains(msg, "must be"), strings.Contains(msg, "cannot"), strings.Contains(msg, "expiry"):
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+	default:
+		transferError(ctx, err)
+	}
+}
+
+// paymentRequestJSON is the user-facing view of a payment request.
+func paymentRequestJSON(r *repository.PaymentRequestRow) gin.H {
+	var payer gin.H
+	if r.PayerUserID != "" {
+		payer = gin.H{"user_id": r.PayerUserID, "name": r.PayerName}
+	}
+	return gin.H{
+		"request_ref":    r.RequestRef,
+		"requester_id":   r.RequesterUserID,
+		"requester_name": r.RequesterName,
+		"payer":          payer,
+		"amount":         r.Amount,
+		"note":           r.Note,
+		"status":         r.Status,
+		"expires_at":     r.ExpiresAt.Format(time.RFC3339),
+		"paid_amount":    r.PaidAmount,
+		"paid_txn_ref":   r.PaidTxnRef,
+		"paid_via":       r.PaidVia,
+		"paid_at":        formatTimePtr(r.PaidAt),
+		"decline_reason": r.DeclineReason,
+		"declined_at":    formatTimePtr(r.DeclinedAt),
+		"created_at":     r.CreatedAt.Format(time.RFC3339),
+		"updated_at":     r.UpdatedAt.Format(time.RFC3339),
+	}
+}
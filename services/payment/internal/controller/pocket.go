@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/auth"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/abubakvr/payup-backend/services/payment/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePocketRequest is the JSON body for POST /wallet/pockets.
+type CreatePocketRequest struct {
+	Name                   string     `json:"name" binding:"required,max=60"`
+	GoalAmount             *float64   `json:"goal_amount"`
+	LockedUntil            *time.Time `json:"locked_until"` // RFC3339; withdrawals refused before this
+	AutosaveInboundPercent float64    `json:"autosave_inbound_percent"`
+	AutosaveRoundupTo      float64    `json:"autosave_roundup_to"`
+}
+
+// UpdatePocketRequest is the JSON body for PATCH /wallet/pockets/:pocket_ref. Omitted fields are unchanged.
+type UpdatePocketRequest struct {
+	Name                   *string    `json:"name"`
+	GoalAmount             *float64   `json:"goal_amount"` // 0 clears the goal
+	LockedUntil            *time.Time `json:"locked_until"`
+	AutosaveInboundPercent *float64   `json:"autosave_inbound_percent"`
+	AutosaveRoundupTo      *float64   `json:"autosave_roundup_to"`
+}
+
+// PocketMoveRequest is the JSON body for pocket deposit and withdraw.
+type PocketMoveRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// CreatePocket opens a savings pocket on the user's wallet. Requires JWT.
+func (c *PaymentController) CreatePocket(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body CreatePocketRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: name (max 60 chars) required; locked_until must be RFC3339", CodeBadRequest)
+		return
+	}
+	pocket, err := c.svc.CreatePocket(ctx.Request.Context(), &service.CreatePocketParams{
+		UserID:                 userID,
+		Name:                   body.Name,
+		GoalAmount:             body.GoalAmount,
+		LockedUntil:            body.LockedUntil,
+		AutosaveInboundPercent: body.AutosaveInboundPercent,
+		AutosaveRoundupTo:      body.AutosaveRoundupTo,
+	})
+	if err != nil {
+		pocketError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusCreated, "Pocket created", CodeSuccess, pocketJSON(pocket))
+}
+
+// ListPockets returns the user's pockets and their total. Query: include_closed=true to include closed pockets. Requires JWT.
+func (c *PaymentController) ListPockets(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	list, err := c.svc.ListMyPockets(ctx.Request.Context(), userID, ctx.Query("include_closed") == "true")
+	if err != nil {
+		pocketError(ctx, err)
+		return
+	}
+	var total float64
+	out := make([]gin.H, 0, len(list))
+	for i := range list {
+		total += list[i].Balance
+		out = append(out, pocketJSON(&list[i]))
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, gin.H{"pockets": out, "savings_balance": total})
+}
+
+// GetPocket returns one of the user's pockets. Requires JWT.
+func (c *PaymentController) GetPocket(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	pocket, err := c.svc.GetMyPocket(ctx.Request.Context(), userID, ctx.Param("pocket_ref"))
+	if err != nil {
+		pocketError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Successful", CodeSuccess, pocketJSON(pocket))
+}
+
+// UpdatePocket changes pocket settings. Requires JWT.
+func (c *PaymentController) UpdatePocket(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	var body UpdatePocketRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: locked_until must be RFC3339", CodeBadRequest)
+		return
+	}
+	pocket, err := c.svc.UpdatePocket(ctx.Request.Context(), userID, ctx.Param("pocket_ref"), &repository.UpdatePocketParams{
+		Name:                   body.Name,
+		GoalAmount:             body.GoalAmount,
+		LockedUntil:            body.LockedUntil,
+		AutosaveInboundPercent: body.AutosaveInboundPercent,
+		AutosaveRoundupTo:      body.AutosaveRoundupTo,
+	})
+	if err != nil {
+		pocketError(ctx, err)
+		return
+	}
+	Success(ctx, http.StatusOK, "Pocket updated", CodeSuccess, pocketJSON(pocket))
+}
+
+// DepositToPocket moves money from the main balance into a pocket. Requires JWT and X-Idempotency-Key.
+func (c *PaymentController) DepositToPocket(ctx *gin.Context) {
+	c.movePocketFunds(ctx, true)
+}
+
+// WithdrawFromPocket moves money from a pocket back to the main balance. Requires JWT and X-Idempotency-Key.
+func (c *PaymentController) WithdrawFromPocket(ctx *gin.Context) {
+	c.movePocketFunds(ctx, false)
+}
+
+func (c *PaymentController) movePocketFunds(ctx *gin.Context, toPocket bool) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	idempotencyKey := strings.TrimSpace(ctx.GetHeader("X-Idempotency-Key"))
+	if idempotencyKey == "" {
+		Error(ctx, http.StatusBadRequest, "X-Idempotency-Key header is required for pocket moves", CodeBadRequest)
+		return
+	}
+	if cached, ok := c.idem.Get(ctx.Request.Context(), userID, idempotencyKey); ok && len(cached) > 0 {
+		ctx.Data(http.StatusOK, "application/json", cached)
+		return
+	}
+	var body PocketMoveRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		Error(ctx, http.StatusBadRequest, "invalid body: amount (positive) required", CodeBadRequest)
+		return
+	}
+	result, err := c.svc.MovePocketFunds(ctx.Request.Context(), userID, ctx.Param("pocket_ref"), body.Amount, toPocket)
+	if err != nil {
+		pocketError(ctx, err)
+		return
+	}
+	msg := "Moved to pocket"
+	if !toPocket {
+		msg = "Moved to main balance"
+	}
+	data := pocketJSON(result.Pocket)
+	data["transaction_ref"] = result.TransactionRef
+	resp := ApiResponse{Status: "success", Message: msg, ResponseCode: CodeSuccess, Data: data}
+	bodyBytes, _ := json.Marshal(resp)
+	c.idem.Set(ctx.Request.Context(), userID, idempotencyKey, bodyBytes)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// ClosePocket returns a pocket's balance to the main balance and closes it. Requires JWT.
+func (c *PaymentController) ClosePocket(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	result, err := c.svc.ClosePocket(ctx.Request.Context(), userID, ctx.Param("pocket_ref"))
+	if err != nil {
+		pocketError(ctx, err)
+		return
+	}
+	data := pocketJSON(result.Pocket)
+	data["transaction_ref"] = result.TransactionRef
+	Success(ctx, http.StatusOK, "Pocket closed", CodeSuccess, data)
+}
+
+func pocketError(ctx *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, service.ErrPocketNotFound), strings.Contains(msg, "no active wallet"):
+		Error(ctx, http.StatusNotFound, msg, CodeConflict)
+	case errors.Is(err, repository.ErrPocketLocked), errors.Is(err, repository.ErrPocketClosed), errors.Is(err, repository.ErrPocketNameTaken):
+		Error(ctx, http.StatusConflict, msg, CodeConflict)
+	case errors.Is(err, repository.ErrPocketInsufficientBalance), strings.Contains(msg, "insufficient balance"):
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+	case strings.Contains(msg, "invalid user_id"), strings.Contains(msg, "must be"), strings.Contains(msg, "cannot"):
+		Error(ctx, http.StatusBadRequest, msg, CodeBadRequest)
+	default:
+		Error(ctx, http.StatusInternalServerError, msg, CodeInternal)
+	}
+}
+
+// pocketJSON is the user-facing view of a savings pocket.
+func pocketJSON(p *repository.PocketRow) gin.H {
+	var progress interface{}
+	if p.GoalAmount != nil && *p.GoalAmount > 0 {
+		progress = p.Balance / *p.GoalAmount * 100
+	}
+	return gin.H{
+		"pocket_ref":               p.PocketRef,
+		"name":                     p.Name,
+		"balance":                  p.Balance,
+		"goal_amount":              p.GoalAmount,
+		"goal_progress_percent":    progress,
+		"locked_until":             formatTimePtr(p.LockedUntil),
+		"locked":                   p.Locked(time.Now()),
+		"status":                   p.Status,
+		"autosave_inbound_percent": p.AutosaveInboundPercent,
+		"autosave_roundup_to":      p.AutosaveRoundupTo,
+		"interest_accrued":         p.InterestAccrued,
+		"interest_paid":            p.InterestPaid,
+		"closed_at":                formatTimePtr(p.ClosedAt),
+		"created_at":               p.CreatedAt.Format(time.RFC3339),
+		"updated_at":               p.UpdatedAt.Format(time.RFC3339),
+	}
+}
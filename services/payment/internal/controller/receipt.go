@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/auth"
+	"github.com/abubakvr/payup-backend/services/payment/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTransactionReceipt returns a branded receipt for a transaction on the authenticated user's wallet as a file download.
+// Query: format (pdf default, or png). Requires JWT.
+func (c *PaymentController) GetTransactionReceipt(ctx *gin.Context) {
+	userID, err := auth.UserIDFromContext(ctx)
+	if err != nil {
+		AbortUnauthorized(ctx, "invalid or missing token")
+		return
+	}
+	transactionRef := ctx.Param("transaction_ref")
+	if transactionRef == "" {
+		Error(ctx, http.StatusBadRequest, "transaction_ref is required", CodeBadRequest)
+		return
+	}
+	rc, err := c.svc.GetTransactionReceipt(ctx.Request.Context(), userID, transactionRef, ctx.Query("format"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReceiptsNotConfigured):
+			Error(ctx, http.StatusServiceUnavailable, err.Error(), CodeInternal)
+		case strings.Contains(err.Error(), "no active wallet"):
+			Error(ctx, http.StatusNotFound, err.Error(), CodeConflict)
+		case strings.Contains(err.Error(), "invalid user_id"), strings.Contains(err.Error(), "format must be"):
+			Error(ctx, http.StatusBadRequest, err.Error(), CodeBadRequest)
+		default:
+			Error(ctx, http.StatusInternalServerError, err.Error(), CodeInternal)
+		}
+		return
+	}
+	if rc == nil {
+		Error(ctx, http.StatusNotFound, "transaction not found", CodeConflict)
+		return
+	}
+	ctx.Header("Content-Disposition", "attachment; filename="+strconv.Quote(rc.FileName))
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Data(http.StatusOK, rc.ContentType, rc.Content)
+}
+
+// VerifyReceipt checks the verification code printed on a receipt. Public (no JWT): anyone handed a receipt can confirm
+// it is genuine. Only masked account numbers are returned.
+func (c *PaymentController) VerifyReceipt(ctx *gin.Context) {
+	v, err := c.svc.VerifyReceipt(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReceiptsNotConfigured):
+			Error(ctx, http.StatusServiceUnavailable, err.Error(), CodeInternal)
+		case errors.Is(err, service.ErrInvalidReceiptCode):
+			Error(ctx, http.StatusNotFound, err.Error(), CodeConflict)
+		default:
+			Error(ctx, http.StatusInternalServerError, "verification failed", CodeInternal)
+		}
+		return
+	}
+	Success(ctx, http.StatusOK, "Receipt is genuine", CodeSuccess, gin.H{
+		"valid":               true,
+		"transaction_ref":     v.TransactionRef,
+		"amount":              v.Amount,
+		"status":              v.Status,
+		"direction":           v.Direction,
+		"sender_name":         v.SenderName,
+		"sender_account":      v.SenderAccount,
+		"beneficiary_name":    v.BeneficiaryName,
+		"beneficiary_bank":    v.BeneficiaryBank,
+		"beneficiary_account": v.BeneficiaryAccount,
+		"created_at":          v.CreatedAt,
+	})
+}
@@ -13,6 +13,11 @@ const (
 	CodeUnauthorized = "02"
 	CodeForbidden   = "03"
 	CodeConflict    = "04"
+	// CodeStepUpRequired: the client must get a token from the user service's POST /auth/step-up (action "transfer")
+	// and retry with X-Step-Up-Token.
+	CodeStepUpRequired = "05"
+	// CodeLimitExceeded: a tier cap or the user's own limit refused the payment; the message names the limit and what is left.
+	CodeLimitExceeded = "06"
 	CodeInternal    = "99"
 )
 
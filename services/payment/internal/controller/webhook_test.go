@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/config"
+	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
+	"github.com/gin-gonic/gin"
+)
+
+// The controller has no service: a refused webhook must not get as far as crediting anything.
+func TestHandle9PSBWebhookRejectsUnsigned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	credit := `{"event":"credit","customer":{"account":{"number":"1100000001"}},"order":{"amount":"1000000"},"transaction":{"sessionid":"S1"}}`
+	cases := []struct {
+		name   string
+		secret string
+		sig    string
+	}{
+		{"unsigned", "psb-secret", ""},
+		{"forged", "psb-secret", psb.SignWebhook("guessed-secret", []byte(credit))},
+		{"no secret configured", "", psb.SignWebhook("", []byte(credit))},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := gin.New()
+			r.POST("/webhooks/9psb", (&PaymentController{cfg: &config.Config{PsbWebhookSecret: c.secret}}).Handle9PSBWebhook)
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/9psb", strings.NewReader(credit))
+			if c.sig != "" {
+				req.Header.Set(psb.WebhookSignatureHeader, c.sig)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401; body %s", w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestHandle9PSBWebhookAcceptsSigned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"event":"ping"}`
+	r := gin.New()
+	r.POST("/webhooks/9psb", (&PaymentController{cfg: &config.Config{PsbWebhookSecret: "psb-secret"}}).Handle9PSBWebhook)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/9psb", strings.NewReader(body))
+	req.Header.Set(psb.WebhookSignatureHeader, psb.SignWebhook("psb-secret", []byte(body)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200; body %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+)
+
+// ExportUserData returns the user's payment records as JSON for a data-subject access request (User service).
+func (s *Server) ExportUserData(ctx context.Context, req *paymentpb.ExportUserDataRequest) (*paymentpb.ExportUserDataResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &paymentpb.ExportUserDataResponse{Found: false}, nil
+	}
+	data, err := s.svc.ExportUserData(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &paymentpb.ExportUserDataResponse{Found: false}, nil
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentpb.ExportUserDataResponse{Found: true, JsonPayload: string(payload)}, nil
+}
+
+// EraseUserData anonymises the user's wallets for account deletion, or reports why it cannot yet (User service).
+func (s *Server) EraseUserData(ctx context.Context, req *paymentpb.EraseUserDataRequest) (*paymentpb.EraseUserDataResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &paymentpb.EraseUserDataResponse{}, nil
+	}
+	res, err := s.svc.EraseUserData(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentpb.EraseUserDataResponse{
+		Held:        res.Held,
+		HoldReasons: res.HoldReasons,
+		Erased:      res.Erased,
+		Retained:    res.Retained,
+	}, nil
+}
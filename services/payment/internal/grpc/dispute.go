@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/abubakvr/payup-backend/services/payment/internal/service"
+)
+
+// ListDisputes returns the admin dispute queue (optional status filter), most urgent SLA first.
+func (s *Server) ListDisputes(ctx context.Context, req *paymentpb.ListDisputesRequest) (*paymentpb.ListDisputesResponse, error) {
+	list, total, err := s.svc.ListDisputesForAdmin(ctx, req.GetStatus(), int(req.GetLimit()), int(req.GetOffset()))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := make([]*paymentpb.DisputeItem, 0, len(list))
+	for i := range list {
+		out = append(out, disputeItem(&list[i], now))
+	}
+	return &paymentpb.ListDisputesResponse{Disputes: out, Total: total}, nil
+}
+
+// GetDispute returns one dispute by id with all notes and attachments.
+func (s *Server) GetDispute(ctx context.Context, req *paymentpb.GetDisputeRequest) (*paymentpb.GetDisputeResponse, error) {
+	if req == nil || req.Id == "" {
+		return &paymentpb.GetDisputeResponse{Found: false}, nil
+	}
+	detail, err := s.svc.GetDisputeForAdmin(ctx, req.Id)
+	if err != nil || detail == nil {
+		return &paymentpb.GetDisputeResponse{Found: false}, nil
+	}
+	out := &paymentpb.GetDisputeResponse{Found: true, Item: disputeItem(&detail.Dispute, time.Now())}
+	for i := range detail.Notes {
+		out.Notes = append(out.Notes, disputeNote(&detail.Notes[i]))
+	}
+	for i := range detail.Attachments {
+		out.Attachments = append(out.Attachments, disputeAttachment(&detail.Attachments[i]))
+	}
+	return out, nil
+}
+
+// UpdateDisputeStatus moves a dispute to UNDER_REVIEW, AWAITING_USER or REJECTED.
+func (s *Server) UpdateDisputeStatus(ctx context.Context, req *paymentpb.UpdateDisputeStatusRequest) (*paymentpb.UpdateDisputeStatusResponse, error) {
+	if req == nil || req.Id == "" {
+		return &paymentpb.UpdateDisputeStatusResponse{Success: false, ErrorMessage: "id required"}, nil
+	}
+	d, err := s.svc.UpdateDisputeStatus(ctx, req.Id, req.Status, req.InitiatedBy, req.Note)
+	if err != nil {
+		return &paymentpb.UpdateDisputeStatusResponse{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return &paymentpb.UpdateDisputeStatusResponse{Success: true, Item: disputeItem(d, time.Now())}, nil
+}
+
+// AddDisputeNote adds an admin note to a dispute.
+func (s *Server) AddDisputeNote(ctx context.Context, req *paymentpb.AddDisputeNoteRequest) (*paymentpb.AddDisputeNoteResponse, error) {
+	if req == nil || req.Id == "" {
+		return &paymentpb.AddDisputeNoteResponse{Success: false, ErrorMessage: "id required"}, nil
+	}
+	n, err := s.svc.AddAdminDisputeNote(ctx, req.Id, req.InitiatedBy, req.Body, req.IsInternal)
+	if err != nil {
+		return &paymentpb.AddDisputeNoteResponse{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return &paymentpb.AddDisputeNoteResponse{Success: true, Note: disputeNote(n)}, nil
+}
+
+// AddDisputeAttachment records an evidence file on a dispute.
+func (s *Server) AddDisputeAttachment(ctx context.Context, req *paymentpb.AddDisputeAttachmentRequest) (*paymentpb.AddDisputeAttachmentResponse, error) {
+	if req == nil || req.Id == "" {
+		return &paymentpb.AddDisputeAttachmentResponse{Success: false, ErrorMessage: "id required"}, nil
+	}
+	a, err := s.svc.AddAdminDisputeAttachment(ctx, req.Id, req.InitiatedBy, req.FileName, req.ContentType, req.Url)
+	if err != nil {
+		return &paymentpb.AddDisputeAttachmentResponse{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return &paymentpb.AddDisputeAttachmentResponse{Success: true, Attachment: disputeAttachment(a)}, nil
+}
+
+// ResolveDispute closes a dispute as RESOLVED, posting a reversal or adjustment when requested.
+func (s *Server) ResolveDispute(ctx context.Context, req *paymentpb.ResolveDisputeRequest) (*paymentpb.ResolveDisputeResponse, error) {
+	if req == nil || req.Id == "" {
+		return &paymentpb.ResolveDisputeResponse{Success: false, ErrorMessage: "id required"}, nil
+	}
+	d, err := s.svc.ResolveDispute(ctx, service.ResolveDisputeParams{
+		DisputeID:  req.Id,
+		Resolution: req.Resolution,
+		Amount:     req.Amount,
+		IsCredit:   req.IsCredit,
+		Note:       req.Note,
+		AdminID:    req.InitiatedBy,
+	})
+	if err != nil {
+		return &paymentpb.ResolveDisputeResponse{Success: false, ErrorMessage: err.Error()}, nil
+	}
+	return &paymentpb.ResolveDisputeResponse{Success: true, Item: disputeItem(d, time.Now())}, nil
+}
+
+func disputeItem(d *repository.DisputeRow, now time.Time) *paymentpb.DisputeItem {
+	return &paymentpb.DisputeItem{
+		Id:                   d.ID,
+		DisputeRef:           d.DisputeRef,
+		TransactionRef:       d.TransactionRef,
+		TransactionAmount:    d.TransactionAmount,
+		TransactionStatus:    d.TransactionStatus,
+		TransactionDirection: d.TransactionDirection,
+		WalletId:             d.WalletID,
+		UserId:               d.UserID,
+		Reason:               d.Reason,
+		Description:          d.Description,
+		Status:               d.Status,
+		AssignedTo:           d.AssignedTo,
+		SlaDueAt:             d.SLADueAt.Format("2006-01-02T15:04:05Z07:00"),
+		SlaBreached:          service.DisputeSLABreached(d, now),
+		FirstResponseAt:      formatTimePtr(d.FirstResponseAt),
+		Resolution:           d.Resolution,
+		ResolutionAmount:     d.ResolutionAmount,
+		ResolutionTxnRef:     d.ResolutionTxnRef,
+		ResolutionNote:       d.ResolutionNote,
+		ResolvedBy:           d.ResolvedBy,
+		ResolvedAt:           formatTimePtr(d.ResolvedAt),
+		CreatedAt:            d.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:            d.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func disputeNote(n *repository.DisputeNoteRow) *paymentpb.DisputeNote {
+	return &paymentpb.DisputeNote{
+		Id:         n.ID,
+		AuthorType: n.AuthorType,
+		AuthorId:   n.AuthorID,
+		Body:       n.Body,
+		IsInternal: n.IsInternal,
+		CreatedAt:  n.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func disputeAttachment(a *repository.DisputeAttachmentRow) *paymentpb.DisputeAttachment {
+	return &paymentpb.DisputeAttachment{
+		Id:             a.ID,
+		UploadedByType: a.UploadedByType,
+		UploadedBy:     a.UploadedBy,
+		FileName:       a.FileName,
+		ContentType:    a.ContentType,
+		Url:            a.URL,
+		CreatedAt:      a.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
@@ -92,7 +92,7 @@ func (s *Server) DebitCreditWallet(ctx context.Context, req *paymentpb.DebitCred
 	if req.Narration == "" {
 		return &paymentpb.DebitCreditWalletResponse{Success: false, ErrorMessage: "narration is required"}, nil
 	}
-	result, err := s.svc.WalletDebitCredit(ctx, req.UserId, req.Amount, req.IsCredit, req.Narration, req.InitiatedBy)
+	result, err := s.svc.WalletDebitCredit(ctx, req.UserId, req.Amount, req.IsCredit, req.Narration, req.InitiatedBy, req.GetIdempotencyKey())
 	if err != nil {
 		msg := err.Error()
 		if strings.Contains(msg, "insufficient balance") {
@@ -103,7 +103,7 @@ func (s *Server) DebitCreditWallet(ctx context.Context, req *paymentpb.DebitCred
 		}
 		return &paymentpb.DebitCreditWalletResponse{Success: false, ErrorMessage: msg}, nil
 	}
-	return &paymentpb.DebitCreditWalletResponse{Success: true, TransactionRef: result.TransactionRef}, nil
+	return &paymentpb.DebitCreditWalletResponse{Success: true, TransactionRef: result.TransactionRef, Duplicate: result.Duplicate}, nil
 }
 
 // GetWaasTransactionHistory returns 9PSB WaaS transaction history for the given user's wallet (admin). Date range max 31 days.
@@ -32,12 +32,17 @@ func NewStore(redisAddr, redisPassword string, ttl time.Duration) *Store {
 	return &Store{client: client, ttl: ttl}
 }
 
-// Get returns the cached response body for the idempotency key, or (nil, false) if not found or store disabled.
-func (s *Store) Get(ctx context.Context, idempotencyKey string) (responseBody []byte, found bool) {
+// cacheKey scopes an idempotency key to the user who sent it; keys are client-chosen and two users may pick the same one.
+func cacheKey(userID, idempotencyKey string) string {
+	return keyPrefix + userID + ":" + idempotencyKey
+}
+
+// Get returns the cached response body for the user's idempotency key, or (nil, false) if not found or store disabled.
+func (s *Store) Get(ctx context.Context, userID, idempotencyKey string) (responseBody []byte, found bool) {
 	if s.client == nil {
 		return nil, false
 	}
-	key := keyPrefix + idempotencyKey
+	key := cacheKey(userID, idempotencyKey)
 	data, err := s.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
 		return nil, false
@@ -48,12 +53,12 @@ func (s *Store) Get(ctx context.Context, idempotencyKey string) (responseBody []
 	return data, true
 }
 
-// Set stores the response body for the idempotency key with TTL. No-op if store disabled.
-func (s *Store) Set(ctx context.Context, idempotencyKey string, responseBody []byte) {
+// Set stores the response body for the user's idempotency key with TTL. No-op if store disabled.
+func (s *Store) Set(ctx context.Context, userID, idempotencyKey string, responseBody []byte) {
 	if s.client == nil {
 		return
 	}
-	key := keyPrefix + idempotencyKey
+	key := cacheKey(userID, idempotencyKey)
 	ttl := s.ttl
 	if ttl <= 0 {
 		ttl = defaultTTL
@@ -16,10 +16,15 @@ const (
 )
 
 // NotificationEvent matches the payload consumed by the notification service (SMS, email, etc.).
+// UserID is the wallet owner the message is for; set it so the notification lands in their inbox.
+// Types with a template in the notification service send Data (amount, transaction_ref, ...) and no Metadata: the
+// copy, the address and the language come from there.
 type NotificationEvent struct {
+	UserID   string                 `json:"user_id,omitempty"`
 	Type     string                 `json:"type"`
 	Channel  string                 `json:"channel"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
 }
 
 // WalletCreatedEvent is published to wallet-events when a wallet is created. KYC service consumes to set kyc_level=1.
@@ -28,6 +33,25 @@ type WalletCreatedEvent struct {
 	UserID    string `json:"user_id"`
 }
 
+// TransferSuccessEvent is published to wallet-events when an outbound transfer settles. User service consumes it for
+// referral rewards.
+type TransferSuccessEvent struct {
+	EventType      string  `json:"event_type"` // "transfer_success"
+	UserID         string  `json:"user_id"`
+	TransactionRef string  `json:"transaction_ref"`
+	Amount         float64 `json:"amount"`
+}
+
+// TransferFailedEvent is published to wallet-events when the bank refuses an outbound transfer; nothing was debited.
+// ResponseCode is the bank's, when it sent one.
+type TransferFailedEvent struct {
+	EventType      string  `json:"event_type"` // "transfer_failed"
+	UserID         string  `json:"user_id"`
+	TransactionRef string  `json:"transaction_ref"`
+	Amount         float64 `json:"amount"`
+	ResponseCode   string  `json:"response_code,omitempty"`
+}
+
 // Producer sends to audit-events, notification-events, and wallet-events.
 type Producer struct {
 	auditWriter        *kafka.Writer
@@ -123,6 +147,36 @@ func (p *Producer) PublishWalletCreated(ctx context.Context, userID string) erro
 	return p.walletWriter.WriteMessages(cctx, kafka.Message{Value: payload})
 }
 
+// PublishTransferSuccess sends a transfer_success event to wallet-events.
+func (p *Producer) PublishTransferSuccess(ctx context.Context, userID, transactionRef string, amount float64) error {
+	if p == nil || p.walletWriter == nil {
+		return nil
+	}
+	ev := TransferSuccessEvent{EventType: "transfer_success", UserID: userID, TransactionRef: transactionRef, Amount: amount}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return p.walletWriter.WriteMessages(cctx, kafka.Message{Value: payload})
+}
+
+// PublishTransferFailed sends a transfer_failed event to wallet-events.
+func (p *Producer) PublishTransferFailed(ctx context.Context, userID, transactionRef string, amount float64, responseCode string) error {
+	if p == nil || p.walletWriter == nil {
+		return nil
+	}
+	ev := TransferFailedEvent{EventType: "transfer_failed", UserID: userID, TransactionRef: transactionRef, Amount: amount, ResponseCode: responseCode}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return p.walletWriter.WriteMessages(cctx, kafka.Message{Value: payload})
+}
+
 func ptr(s string) *string {
 	if s == "" {
 		return nil
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	userEventsTopic   = "user-events"
+	userEventsGroupID = "payment-service"
+)
+
+// UserContactChangedEvent is published by the user service on user-events when a user's email or phone number
+// changes. Email and PhoneNumber are the values after the change.
+type UserContactChangedEvent struct {
+	EventType   string `json:"event_type"` // "user_contact_changed"
+	UserID      string `json:"user_id"`
+	Field       string `json:"field"` // email or phone
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// UserEventsConsumer consumes user-events and calls onContactChanged for each user_contact_changed event.
+type UserEventsConsumer struct {
+	reader           *kafka.Reader
+	onContactChanged func(ctx context.Context, ev UserContactChangedEvent)
+}
+
+// NewUserEventsConsumer returns nil when there are no brokers or no handler.
+func NewUserEventsConsumer(brokers []string, onContactChanged func(ctx context.Context, ev UserContactChangedEvent)) *UserEventsConsumer {
+	if len(brokers) == 0 || onContactChanged == nil {
+		return nil
+	}
+	return &UserEventsConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: brokers,
+			Topic:   userEventsTopic,
+			GroupID: userEventsGroupID,
+		}),
+		onContactChanged: onContactChanged,
+	}
+}
+
+// Start runs the consumer loop. Call in a goroutine.
+func (c *UserEventsConsumer) Start() {
+	if c == nil {
+		return
+	}
+	for {
+		msg, err := c.reader.ReadMessage(context.Background())
+		if err != nil {
+			log.Printf("payment user-events consumer: %v", err)
+			continue
+		}
+		var ev UserContactChangedEvent
+		if err := json.Unmarshal(msg.Value, &ev); err != nil {
+			log.Printf("payment user-events: invalid JSON: %v", err)
+			continue
+		}
+		if ev.EventType != "user_contact_changed" || ev.UserID == "" || ev.PhoneNumber == "" {
+			continue
+		}
+		c.onContactChanged(context.Background(), ev)
+	}
+}
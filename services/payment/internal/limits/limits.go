@@ -0,0 +1,208 @@
+// Package limits is the spending policy for wallets: CBN tier caps (single transaction, daily total, per channel and
+// maximum balance) combined with the lower limits a user may choose in the user service. Check explains every refusal
+// in its error message, so the API can return it as is.
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Channel is a way money leaves a wallet; each may carry its own caps on top of the tier's.
+type Channel string
+
+const (
+	ChannelTransfer Channel = "transfer" // bank transfers, including payment requests paid by transfer
+	ChannelBills    Channel = "bills"    // airtime, data, electricity and TV
+)
+
+// ChannelCaps are extra caps for one channel. 0 means no cap beyond the tier's.
+type ChannelCaps struct {
+	SingleTransaction float64 `json:"single_transaction"`
+	Daily             float64 `json:"daily"`
+}
+
+// Caps are the regulatory limits for one tier. 0 means no cap.
+type Caps struct {
+	SingleTransaction float64                 `json:"single_transaction"`
+	Daily             float64                 `json:"daily"`
+	MaxBalance        float64                 `json:"max_balance"`
+	Channels          map[Channel]ChannelCaps `json:"channels,omitempty"`
+}
+
+// Policy maps wallet tiers (1-3) to their caps.
+type Policy struct {
+	Tiers map[int]Caps
+}
+
+// DefaultPolicy is the CBN three-tier KYC framework: tier 1 sends up to NGN 50,000 a day and holds at most
+// NGN 300,000; tier 2 sends up to NGN 200,000 a day and holds NGN 500,000; tier 3 sends up to NGN 5,000,000 a day
+// with no balance cap.
+func DefaultPolicy() Policy {
+	return Policy{Tiers: map[int]Caps{
+		1: {SingleTransaction: 50000, Daily: 50000, MaxBalance: 300000},
+		2: {SingleTransaction: 100000, Daily: 200000, MaxBalance: 500000},
+		3: {SingleTransaction: 5000000, Daily: 5000000},
+	}}
+}
+
+// ParsePolicy reads tier overrides as JSON keyed by tier, e.g. {"1":{"single_transaction":20000,"daily":50000,
+// "max_balance":300000,"channels":{"bills":{"daily":10000}}}}. A tier given replaces that tier's defaults whole;
+// tiers left out keep the defaults. Empty input returns DefaultPolicy.
+func ParsePolicy(raw string) (Policy, error) {
+	p := DefaultPolicy()
+	if strings.TrimSpace(raw) == "" {
+		return p, nil
+	}
+	var overrides map[string]Caps
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		return Policy{}, fmt.Errorf("tier limits: %w", err)
+	}
+	for key, caps := range overrides {
+		tier, err := strconv.Atoi(key)
+		if err != nil || tier < 1 || tier > 3 {
+			return Policy{}, fmt.Errorf("tier limits: tier %q must be 1, 2 or 3", key)
+		}
+		p.Tiers[tier] = caps
+	}
+	return p, nil
+}
+
+// EffectiveTier is the tier whose caps apply: the wallet tier, lowered to the KYC level when KYC is behind (for
+// example after an upgrade was approved at 9PSB before KYC caught up). kycLevel 0 means unknown and is ignored.
+func EffectiveTier(walletTier string, kycLevel int) int {
+	tier, err := strconv.Atoi(strings.TrimSpace(walletTier))
+	if err != nil || tier < 1 {
+		tier = 1
+	}
+	if tier > 3 {
+		tier = 3
+	}
+	if kycLevel > 0 && kycLevel < tier {
+		tier = kycLevel
+	}
+	return tier
+}
+
+// Caps returns the caps for tier; unknown tiers get tier 1's.
+func (p Policy) Caps(tier int) Caps {
+	if c, ok := p.Tiers[tier]; ok {
+		return c
+	}
+	return p.Tiers[1]
+}
+
+// Spend is what the wallet has already sent in the current windows, counting payments still in flight.
+type Spend struct {
+	Daily          float64
+	DailyByChannel map[Channel]float64
+	Monthly        float64
+}
+
+// UserLimits are the limits the user chose in the user service. 0 means none.
+type UserLimits struct {
+	Daily   float64
+	Monthly float64
+}
+
+// Request is one outbound payment to check.
+type Request struct {
+	Tier    int
+	Channel Channel
+	Amount  float64
+	Spend   Spend
+	User    UserLimits
+}
+
+// Error is a refused payment. Rule names the limit hit (single_transaction, daily, monthly, channel_single,
+// channel_daily); Limit is its value and Remaining what is still allowed in its window.
+type Error struct {
+	Rule      string
+	Tier      int
+	Channel   Channel
+	Limit     float64
+	Remaining float64
+	UserSet   bool // the user's own limit, not the tier's
+}
+
+func (e *Error) Error() string {
+	what := "transfer"
+	if e.Channel == ChannelBills {
+		what = "bill payment"
+	}
+	limit, remaining := Naira(e.Limit), Naira(e.Remaining)
+	switch {
+	case e.UserSet:
+		period := strings.TrimPrefix(e.Rule, "user_")
+		return fmt.Sprintf("%s transfer limit exceeded: your %s limit is %s and %s is left; you can change it in Settings > Limits",
+			period, period, limit, remaining)
+	case e.Rule == "single_transaction" || e.Rule == "channel_single":
+		return fmt.Sprintf("single transaction limit exceeded: tier %d wallets can send at most %s per %s; upgrade your wallet to raise it",
+			e.Tier, limit, what)
+	case e.Rule == "channel_daily":
+		return fmt.Sprintf("daily %s limit exceeded: tier %d wallets can spend %s a day on %ss and %s is left today; upgrade your wallet to raise it",
+			what, e.Tier, limit, what, remaining)
+	default:
+		return fmt.Sprintf("daily transfer limit exceeded: tier %d wallets can send %s a day and %s is left today; upgrade your wallet to raise it",
+			e.Tier, limit, remaining)
+	}
+}
+
+// Check refuses r when it breaks a tier cap or a user limit, checking the narrowest rule first.
+func (p Policy) Check(r Request) error {
+	caps := p.Caps(r.Tier)
+	ch := caps.Channels[r.Channel]
+	refuse := func(rule string, limit, spent float64, userSet bool) error {
+		return &Error{Rule: rule, Tier: r.Tier, Channel: r.Channel, Limit: limit, Remaining: math.Max(0, limit-spent), UserSet: userSet}
+	}
+	if ch.SingleTransaction > 0 && r.Amount > ch.SingleTransaction {
+		return refuse("channel_single", ch.SingleTransaction, 0, false)
+	}
+	if caps.SingleTransaction > 0 && r.Amount > caps.SingleTransaction {
+		return refuse("single_transaction", caps.SingleTransaction, 0, false)
+	}
+	if spent := r.Spend.DailyByChannel[r.Channel]; ch.Daily > 0 && spent+r.Amount > ch.Daily {
+		return refuse("channel_daily", ch.Daily, spent, false)
+	}
+	if caps.Daily > 0 && r.Spend.Daily+r.Amount > caps.Daily {
+		return refuse("daily", caps.Daily, r.Spend.Daily, false)
+	}
+	if r.User.Daily > 0 && r.Spend.Daily+r.Amount > r.User.Daily {
+		return refuse("user_daily", r.User.Daily, r.Spend.Daily, true)
+	}
+	if r.User.Monthly > 0 && r.Spend.Monthly+r.Amount > r.User.Monthly {
+		return refuse("user_monthly", r.User.Monthly, r.Spend.Monthly, true)
+	}
+	return nil
+}
+
+// Held is the part of balance above the tier's maximum balance. Inbound credits are never bounced (9PSB has already
+// settled them), so the excess stays in the wallet but cannot be spent until the wallet is upgraded.
+func (p Policy) Held(tier int, balance float64) float64 {
+	max := p.Caps(tier).MaxBalance
+	if max <= 0 || balance <= max {
+		return 0
+	}
+	return balance - max
+}
+
+// Naira formats an amount for messages, e.g. NGN 50,000.00.
+func Naira(amount float64) string {
+	s := strconv.FormatFloat(math.Abs(amount), 'f', 2, 64)
+	whole, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	return sign + "NGN " + b.String() + frac
+}
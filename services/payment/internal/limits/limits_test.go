@@ -0,0 +1,184 @@
+package limits
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	p := DefaultPolicy()
+	daily := func(transfers, bills float64) Spend {
+		return Spend{Daily: transfers + bills, DailyByChannel: map[Channel]float64{ChannelTransfer: transfers, ChannelBills: bills}}
+	}
+	cases := []struct {
+		name     string
+		req      Request
+		wantRule string // "" means allowed
+	}{
+		{"tier 1 single at cap", Request{Tier: 1, Channel: ChannelTransfer, Amount: 50000}, ""},
+		{"tier 1 single over cap", Request{Tier: 1, Channel: ChannelTransfer, Amount: 50000.01}, "single_transaction"},
+		{"tier 1 daily reaches cap", Request{Tier: 1, Channel: ChannelTransfer, Amount: 10000, Spend: daily(40000, 0)}, ""},
+		{"tier 1 daily over cap", Request{Tier: 1, Channel: ChannelTransfer, Amount: 10000.01, Spend: daily(40000, 0)}, "daily"},
+		{"tier 1 daily counts bills", Request{Tier: 1, Channel: ChannelTransfer, Amount: 5000, Spend: daily(30000, 15000.01)}, "daily"},
+		{"tier 2 single at cap", Request{Tier: 2, Channel: ChannelTransfer, Amount: 100000}, ""},
+		{"tier 2 single over cap", Request{Tier: 2, Channel: ChannelTransfer, Amount: 100000.01}, "single_transaction"},
+		{"tier 2 daily reaches cap", Request{Tier: 2, Channel: ChannelBills, Amount: 100000, Spend: daily(100000, 0)}, ""},
+		{"tier 2 daily over cap", Request{Tier: 2, Channel: ChannelBills, Amount: 100000, Spend: daily(100000, 0.01)}, "daily"},
+		{"tier 3 single at cap", Request{Tier: 3, Channel: ChannelTransfer, Amount: 5000000}, ""},
+		{"tier 3 single over cap", Request{Tier: 3, Channel: ChannelTransfer, Amount: 5000000.01}, "single_transaction"},
+		{"tier 3 daily over cap", Request{Tier: 3, Channel: ChannelTransfer, Amount: 1, Spend: daily(5000000, 0)}, "daily"},
+		{"unknown tier gets tier 1", Request{Tier: 7, Channel: ChannelTransfer, Amount: 50000.01}, "single_transaction"},
+		{"user daily below tier", Request{Tier: 3, Channel: ChannelTransfer, Amount: 20000, User: UserLimits{Daily: 100000}, Spend: daily(80000.01, 0)}, "user_daily"},
+		{"user daily reached exactly", Request{Tier: 3, Channel: ChannelTransfer, Amount: 20000, User: UserLimits{Daily: 100000}, Spend: daily(80000, 0)}, ""},
+		{"user monthly", Request{Tier: 3, Channel: ChannelTransfer, Amount: 1000, User: UserLimits{Monthly: 500000}, Spend: Spend{Monthly: 499500}}, "user_monthly"},
+		{"tier cap before user limit", Request{Tier: 1, Channel: ChannelTransfer, Amount: 60000, User: UserLimits{Daily: 10000}}, "single_transaction"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := p.Check(c.req)
+			if c.wantRule == "" {
+				if err != nil {
+					t.Fatalf("Check = %v, want allowed", err)
+				}
+				return
+			}
+			var le *Error
+			if !errors.As(err, &le) || le.Rule != c.wantRule {
+				t.Fatalf("Check = %v, want rule %s", err, c.wantRule)
+			}
+		})
+	}
+}
+
+func TestPolicyCheckChannelCaps(t *testing.T) {
+	p := DefaultPolicy()
+	caps := p.Tiers[2]
+	caps.Channels = map[Channel]ChannelCaps{ChannelBills: {SingleTransaction: 20000, Daily: 30000}}
+	p.Tiers[2] = caps
+	spend := Spend{Daily: 25000, DailyByChannel: map[Channel]float64{ChannelBills: 25000}}
+
+	cases := []struct {
+		name      string
+		req       Request
+		wantRule  string
+		remaining float64
+	}{
+		{"bills single over channel cap", Request{Tier: 2, Channel: ChannelBills, Amount: 20000.01}, "channel_single", 20000},
+		{"bills daily over channel cap", Request{Tier: 2, Channel: ChannelBills, Amount: 5000.01, Spend: spend}, "channel_daily", 5000},
+		{"bills daily at channel cap", Request{Tier: 2, Channel: ChannelBills, Amount: 5000, Spend: spend}, "", 0},
+		{"transfers ignore bills caps", Request{Tier: 2, Channel: ChannelTransfer, Amount: 50000, Spend: spend}, "", 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := p.Check(c.req)
+			if c.wantRule == "" {
+				if err != nil {
+					t.Fatalf("Check = %v, want allowed", err)
+				}
+				return
+			}
+			var le *Error
+			if !errors.As(err, &le) || le.Rule != c.wantRule || le.Remaining != c.remaining {
+				t.Fatalf("Check = %+v, want rule %s with %.2f remaining", err, c.wantRule, c.remaining)
+			}
+		})
+	}
+}
+
+func TestPolicyHeld(t *testing.T) {
+	p := DefaultPolicy()
+	cases := []struct {
+		tier    int
+		balance float64
+		want    float64
+	}{
+		{1, 300000, 0},
+		{1, 300000.5, 0.5},
+		{1, 450000, 150000},
+		{2, 500000, 0},
+		{2, 650000, 150000},
+		{3, 90000000, 0},
+		{0, 350000, 50000}, // unknown tier gets tier 1's cap
+	}
+	for _, c := range cases {
+		if got := p.Held(c.tier, c.balance); got != c.want {
+			t.Errorf("Held(%d, %.2f) = %.2f, want %.2f", c.tier, c.balance, got, c.want)
+		}
+	}
+}
+
+func TestEffectiveTier(t *testing.T) {
+	cases := []struct {
+		walletTier string
+		kycLevel   int
+		want       int
+	}{
+		{"1", 0, 1},
+		{"2", 0, 2},
+		{" 3 ", 0, 3},
+		{"3", 2, 2}, // KYC behind the wallet
+		{"2", 1, 1},
+		{"1", 3, 1}, // KYC ahead: the wallet tier still applies
+		{"", 0, 1},
+		{"TIER2", 3, 1},
+		{"0", 0, 1},
+		{"4", 0, 3},
+	}
+	for _, c := range cases {
+		if got := EffectiveTier(c.walletTier, c.kycLevel); got != c.want {
+			t.Errorf("EffectiveTier(%q, %d) = %d, want %d", c.walletTier, c.kycLevel, got, c.want)
+		}
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("  ")
+	if err != nil || p.Caps(1).Daily != 50000 || p.Caps(3).SingleTransaction != 5000000 {
+		t.Fatalf("ParsePolicy(blank) = %+v, %v; want the defaults", p, err)
+	}
+
+	p, err = ParsePolicy(`{"1":{"single_transaction":20000,"daily":40000,"max_balance":300000,"channels":{"bills":{"daily":10000}}}}`)
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	if c := p.Caps(1); c.SingleTransaction != 20000 || c.Daily != 40000 || c.MaxBalance != 300000 || c.Channels[ChannelBills].Daily != 10000 {
+		t.Errorf("tier 1 = %+v", c)
+	}
+	if c := p.Caps(2); c.SingleTransaction != 100000 || c.Daily != 200000 || c.MaxBalance != 500000 {
+		t.Errorf("tier 2 = %+v, want the defaults", c)
+	}
+
+	// A tier given replaces its defaults whole: fields left out become 0 (no cap).
+	p, err = ParsePolicy(`{"3":{"daily":1000000}}`)
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	if c := p.Caps(3); c.SingleTransaction != 0 || c.Daily != 1000000 {
+		t.Errorf("tier 3 = %+v", c)
+	}
+
+	for _, raw := range []string{`{"4":{"daily":1}}`, `{"0":{}}`, `{"gold":{}}`, `{"1":`, `[]`} {
+		if _, err := ParsePolicy(raw); err == nil {
+			t.Errorf("ParsePolicy(%s) accepted", raw)
+		}
+	}
+}
+
+func TestNaira(t *testing.T) {
+	cases := map[float64]string{
+		0:          "NGN 0.00",
+		5:          "NGN 5.00",
+		999.5:      "NGN 999.50",
+		1000:       "NGN 1,000.00",
+		50000:      "NGN 50,000.00",
+		300000:     "NGN 300,000.00",
+		5000000:    "NGN 5,000,000.00",
+		1234567.89: "NGN 1,234,567.89",
+		-2500:      "-NGN 2,500.00",
+	}
+	for amount, want := range cases {
+		if got := Naira(amount); got != want {
+			t.Errorf("Naira(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package psb
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+)
+
+// WebhookSignatureHeader carries 9PSB's signature of a webhook: hex HMAC-SHA512 of the raw body under the secret
+// agreed at onboarding.
+const WebhookSignatureHeader = "X-9PSB-Signature"
+
+// SignWebhook returns the signature 9PSB sends for body.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook reports whether signature is 9PSB's signature of body. It is false when secret is empty, so an
+// unconfigured service accepts nothing.
+func VerifyWebhook(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(strings.ToLower(strings.TrimSpace(signature))), []byte(SignWebhook(secret, body)))
+}
+
+// IsCredit reports whether the item is money received into the wallet.
+func (t *WaasWalletTransactionItem) IsCredit() bool {
+	switch strings.ToUpper(strings.TrimSpace(t.PostingType)) {
+	case "C", "CR", "CREDIT":
+		return true
+	case "D", "DR", "DEBIT":
+		return false
+	}
+	c := strings.TrimSpace(t.Credit)
+	return c != "" && c != "0" && c != "0.00"
+}
@@ -0,0 +1,47 @@
+package psb
+
+import "testing"
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"event":"credit","order":{"amount":"5000"},"transaction":{"sessionid":"S1"}}`)
+	sig := SignWebhook("psb-secret", body)
+
+	if !VerifyWebhook("psb-secret", body, sig) {
+		t.Fatal("valid signature rejected")
+	}
+	cases := map[string]struct {
+		secret string
+		body   string
+		sig    string
+	}{
+		"unsigned":       {"psb-secret", string(body), ""},
+		"wrong secret":   {"other-secret", string(body), sig},
+		"tampered body":  {"psb-secret", `{"event":"credit","order":{"amount":"500000"},"transaction":{"sessionid":"S1"}}`, sig},
+		"no secret":      {"", string(body), SignWebhook("", body)},
+		"garbage header": {"psb-secret", string(body), "not-hex"},
+	}
+	for name, c := range cases {
+		if VerifyWebhook(c.secret, []byte(c.body), c.sig) {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestIsCredit(t *testing.T) {
+	cases := []struct {
+		item WaasWalletTransactionItem
+		want bool
+	}{
+		{WaasWalletTransactionItem{PostingType: "CR"}, true},
+		{WaasWalletTransactionItem{PostingType: "credit"}, true},
+		{WaasWalletTransactionItem{PostingType: "D", Credit: "5000"}, false},
+		{WaasWalletTransactionItem{Credit: "5000"}, true},
+		{WaasWalletTransactionItem{Credit: "0.00", Debit: "5000"}, false},
+		{WaasWalletTransactionItem{}, false},
+	}
+	for _, c := range cases {
+		if got := c.item.IsCredit(); got != c.want {
+			t.Errorf("IsCredit(%+v) = %v, want %v", c.item, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package receipt
+
+import (
+	"bytes"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Brand colours (RGB) shared by the PDF and PNG renderers.
+var (
+	brandColor = [3]int{11, 95, 255}
+	mutedColor = [3]int{110, 117, 130}
+	textColor  = [3]int{20, 24, 31}
+)
+
+// RenderPDF renders the receipt as a single-page PDF (A6 portrait, core Helvetica font).
+func RenderPDF(r *Receipt) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A6", "")
+	pdf.SetTitle("PayUp receipt "+r.TransactionRef, false)
+	pdf.SetCreator("PayUp", false)
+	pdf.SetCreationDate(r.CreatedAt)
+	pdf.SetMargins(8, 8, 8)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	pageW, _ := pdf.GetPageSize()
+	contentW := pageW - 16
+
+	// Header band
+	pdf.SetFillColor(brandColor[0], brandColor[1], brandColor[2])
+	pdf.Rect(0, 0, pageW, 24, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetXY(8, 6)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(contentW, 7, "PayUp", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(contentW, 5, "Transaction Receipt", "", 1, "L", false, 0, "")
+
+	// Amount
+	pdf.SetXY(8, 30)
+	pdf.SetTextColor(mutedColor[0], mutedColor[1], mutedColor[2])
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.CellFormat(contentW, 4, "Amount", "", 1, "C", false, 0, "")
+	pdf.SetTextColor(textColor[0], textColor[1], textColor[2])
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentW, 9, "NGN "+FormatAmount(r.Amount), "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+	pdf.SetDrawColor(220, 223, 230)
+	pdf.Line(8, pdf.GetY(), pageW-8, pdf.GetY())
+	pdf.Ln(2)
+
+	// Details
+	labelW := 32.0
+	for _, f := range r.Fields() {
+		pdf.SetFont("Helvetica", "", 7.5)
+		pdf.SetTextColor(mutedColor[0], mutedColor[1], mutedColor[2])
+		pdf.CellFormat(labelW, 5, f.Label, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 7.5)
+		pdf.SetTextColor(textColor[0], textColor[1], textColor[2])
+		pdf.MultiCell(contentW-labelW, 5, f.Value, "", "R", false)
+	}
+
+	// Verification
+	pdf.Ln(2)
+	pdf.Line(8, pdf.GetY(), pageW-8, pdf.GetY())
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetTextColor(mutedColor[0], mutedColor[1], mutedColor[2])
+	pdf.CellFormat(contentW, 4, "Verification code", "", 1, "C", false, 0, "")
+	pdf.SetFont("Courier", "B", 8)
+	pdf.SetTextColor(textColor[0], textColor[1], textColor[2])
+	pdf.MultiCell(contentW, 4, r.VerificationCode, "", "C", false)
+	if r.VerifyURL != "" {
+		pdf.SetFont("Helvetica", "", 6.5)
+		pdf.SetTextColor(mutedColor[0], mutedColor[1], mutedColor[2])
+		pdf.MultiCell(contentW, 3.5, "Verify at "+r.VerifyURL, "", "C", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
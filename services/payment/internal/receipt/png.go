@@ -0,0 +1,150 @@
+package receipt
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	pngWidth   = 360 // logical width before scaling
+	pngScale   = 2   // output is upscaled so the bitmap font stays legible on phones
+	pngPadding = 16
+	lineHeight = 18
+)
+
+// RenderPNG renders the receipt as a PNG image using the built-in 7x13 bitmap font.
+func RenderPNG(r *Receipt) ([]byte, error) {
+	fields := r.Fields()
+	maxChars := (pngWidth - 2*pngPadding) / basicfont.Face7x13.Advance
+
+	// Pre-wrap values so the canvas height is known up front.
+	type line struct{ label, value string }
+	var lines []line
+	for _, f := range fields {
+		room := maxChars - len(f.Label) - 2
+		wrapped := wrap(f.Value, room)
+		for i, w := range wrapped {
+			l := line{value: w}
+			if i == 0 {
+				l.label = f.Label
+			}
+			lines = append(lines, l)
+		}
+	}
+	codeLines := wrap(r.VerificationCode, maxChars)
+	var urlLines []string
+	if r.VerifyURL != "" {
+		urlLines = wrap("Verify at "+r.VerifyURL, maxChars)
+	}
+	height := 56 + 60 + len(lines)*lineHeight + 30 + (len(codeLines)+len(urlLines))*lineHeight + pngPadding
+
+	img := image.NewRGBA(image.Rect(0, 0, pngWidth, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	brand := rgb(brandColor)
+	muted := rgb(mutedColor)
+	text := rgb(textColor)
+
+	// Header band
+	draw.Draw(img, image.Rect(0, 0, pngWidth, 56), image.NewUniform(brand), image.Point{}, draw.Src)
+	drawText(img, pngPadding, 24, "PayUp", color.White)
+	drawText(img, pngPadding, 42, "Transaction Receipt", color.White)
+
+	// Amount
+	y := 56 + 22
+	drawCentered(img, y, "Amount", muted)
+	y += 20
+	drawCentered(img, y, "NGN "+FormatAmount(r.Amount), text)
+	y += 18
+	hline(img, y, color.RGBA{220, 223, 230, 255})
+	y += 20
+
+	for _, l := range lines {
+		if l.label != "" {
+			drawText(img, pngPadding, y, l.label, muted)
+		}
+		drawRight(img, y, l.value, text)
+		y += lineHeight
+	}
+
+	y += 4
+	hline(img, y, color.RGBA{220, 223, 230, 255})
+	y += 20
+	drawCentered(img, y, "Verification code", muted)
+	y += lineHeight
+	for _, c := range codeLines {
+		drawCentered(img, y, c, text)
+		y += lineHeight
+	}
+	for _, u := range urlLines {
+		drawCentered(img, y, u, muted)
+		y += lineHeight
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, pngWidth*pngScale, height*pngScale))
+	draw.NearestNeighbor.Scale(out, out.Bounds(), img, img.Bounds(), draw.Src, nil)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func rgb(c [3]int) color.RGBA {
+	return color.RGBA{uint8(c[0]), uint8(c[1]), uint8(c[2]), 255}
+}
+
+func drawText(img *image.RGBA, x, y int, s string, c color.Color) {
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: basicfont.Face7x13, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+func textWidth(s string) int {
+	return len(s) * basicfont.Face7x13.Advance
+}
+
+func drawCentered(img *image.RGBA, y int, s string, c color.Color) {
+	drawText(img, (pngWidth-textWidth(s))/2, y, s, c)
+}
+
+func drawRight(img *image.RGBA, y int, s string, c color.Color) {
+	drawText(img, pngWidth-pngPadding-textWidth(s), y, s, c)
+}
+
+func hline(img *image.RGBA, y int, c color.Color) {
+	draw.Draw(img, image.Rect(pngPadding, y, pngWidth-pngPadding, y+1), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// wrap splits s into lines of at most n characters, breaking on spaces where possible.
+// The bitmap font is ASCII-only, so non-ASCII runes are replaced with '?'.
+func wrap(s string, n int) []string {
+	s = strings.Map(func(r rune) rune {
+		if r > 126 {
+			return '?'
+		}
+		return r
+	}, s)
+	if n <= 0 {
+		n = 1
+	}
+	var out []string
+	for len(s) > n {
+		cut := strings.LastIndex(s[:n+1], " ")
+		if cut <= 0 {
+			cut = n
+		}
+		out = append(out, strings.TrimSpace(s[:cut]))
+		s = strings.TrimSpace(s[cut:])
+	}
+	if s != "" || len(out) == 0 {
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+// Package receipt renders shareable transaction receipts (PDF and PNG) and signs the verification code printed on them.
+package receipt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Receipt is the data printed on a transaction receipt. Account numbers must already be masked.
+type Receipt struct {
+	TransactionRef     string
+	SessionID          string
+	Type               string // e.g. OUTBOUND_TRANSFER, CREDIT
+	Direction          string // IN or OUT
+	Status             string
+	Amount             float64
+	FeeAmount          float64
+	Narration          string
+	SenderName         string
+	SenderAccount      string // masked
+	BeneficiaryName    string
+	BeneficiaryBank    string
+	BeneficiaryAccount string // masked
+	CreatedAt          time.Time
+	VerificationCode   string
+	VerifyURL          string // optional; printed under the code so a recipient can check it
+}
+
+// Field is one label/value line on the receipt, in print order.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Fields returns the receipt lines in print order, skipping empty values.
+func (r *Receipt) Fields() []Field {
+	all := []Field{
+		{"Status", r.Status},
+		{"Date", r.CreatedAt.Format("02 Jan 2006, 15:04 MST")},
+		{"Type", humanType(r.Type, r.Direction)},
+		{"Fee", "NGN " + FormatAmount(r.FeeAmount)},
+		{"Sender", r.SenderName},
+		{"Sender account", r.SenderAccount},
+		{"Beneficiary", r.BeneficiaryName},
+		{"Beneficiary bank", r.BeneficiaryBank},
+		{"Beneficiary account", r.BeneficiaryAccount},
+		{"Narration", r.Narration},
+		{"Transaction ref", r.TransactionRef},
+		{"Session ID", r.SessionID},
+	}
+	out := make([]Field, 0, len(all))
+	for _, f := range all {
+		if strings.TrimSpace(f.Value) != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func humanType(txnType, direction string) string {
+	switch txnType {
+	case "OUTBOUND_TRANSFER":
+		return "Bank transfer"
+	case "REVERSAL":
+		return "Reversal"
+	}
+	if direction == "IN" {
+		return "Wallet credit"
+	}
+	return "Wallet debit"
+}
+
+// MaskAccount keeps the last 4 digits of an account number, e.g. 0123456789 -> ******6789.
+func MaskAccount(acct string) string {
+	acct = strings.TrimSpace(acct)
+	if len(acct) <= 4 {
+		return acct
+	}
+	return strings.Repeat("*", len(acct)-4) + acct[len(acct)-4:]
+}
+
+// FormatAmount formats an amount with thousands separators and 2 decimals, e.g. 1234567.5 -> 1,234,567.50.
+func FormatAmount(amount float64) string {
+	s := fmt.Sprintf("%.2f", amount)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if neg {
+		return "-" + b.String() + frac
+	}
+	return b.String() + frac
+}
+
+// codeSeparator separates the transaction ref from the signature. Transaction refs are alphanumeric.
+const codeSeparator = "."
+
+var sigEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Sign returns the verification code for a transaction: "<transaction_ref>.<signature>". The signature is an HMAC-SHA256
+// over the ref, amount and creation time, so a code cannot be forged or moved to a different amount without the key.
+func Sign(key, transactionRef string, amount float64, createdAt time.Time) string {
+	return transactionRef + codeSeparator + signature(key, transactionRef, amount, createdAt)
+}
+
+// ParseCode splits a verification code into the transaction ref and signature. ok is false if the code is malformed.
+func ParseCode(code string) (transactionRef, sig string, ok bool) {
+	i := strings.LastIndex(code, codeSeparator)
+	if i <= 0 || i == len(code)-1 {
+		return "", "", false
+	}
+	return code[:i], code[i+1:], true
+}
+
+// Verify reports whether code is a valid signature for the given transaction.
+func Verify(key, code string, amount float64, createdAt time.Time) bool {
+	ref, sig, ok := ParseCode(code)
+	if !ok {
+		return false
+	}
+	expected := signature(key, ref, amount, createdAt)
+	return hmac.Equal([]byte(strings.ToUpper(sig)), []byte(expected))
+}
+
+func signature(key, transactionRef string, amount float64, createdAt time.Time) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	fmt.Fprintf(mac, "%s|%.2f|%d", transactionRef, amount, createdAt.Unix())
+	return sigEncoding.EncodeToString(mac.Sum(nil))[:16]
+}
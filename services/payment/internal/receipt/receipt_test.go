@@ -0,0 +1,65 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	created := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	code := Sign("receipt-key", "TXN20260304103000ab12cd34", 2500, created)
+
+	ref, sig, ok := ParseCode(code)
+	if !ok || ref != "TXN20260304103000ab12cd34" || len(sig) != 16 {
+		t.Fatalf("ParseCode(%q) = %q, %q, %v", code, ref, sig, ok)
+	}
+	if !Verify("receipt-key", code, 2500, created) {
+		t.Fatal("valid code rejected")
+	}
+	if !Verify("receipt-key", ref+codeSeparator+strings.ToLower(sig), 2500, created) {
+		t.Error("lower-cased code rejected")
+	}
+
+	flipped := "A" + sig[1:]
+	if sig[0] == 'A' {
+		flipped = "B" + sig[1:]
+	}
+	cases := map[string]struct {
+		key     string
+		code    string
+		amount  float64
+		created time.Time
+	}{
+		"wrong key":          {"other-key", code, 2500, created},
+		"changed amount":     {"receipt-key", code, 25000, created},
+		"changed time":       {"receipt-key", code, 2500, created.Add(time.Second)},
+		"changed ref":        {"receipt-key", "TXN20260304103000ab12cd35" + codeSeparator + sig, 2500, created},
+		"changed sig":        {"receipt-key", ref + codeSeparator + flipped, 2500, created},
+		"missing sig":        {"receipt-key", ref, 2500, created},
+		"empty sig":          {"receipt-key", ref + codeSeparator, 2500, created},
+		"missing ref":        {"receipt-key", codeSeparator + sig, 2500, created},
+		"empty code":         {"receipt-key", "", 2500, created},
+		"signed without key": {"receipt-key", Sign("", ref, 2500, created), 2500, created},
+	}
+	for name, c := range cases {
+		if Verify(c.key, c.code, c.amount, c.created) {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestMaskAccountAndFormatAmount(t *testing.T) {
+	if got := MaskAccount(" 0123456789 "); got != "******6789" {
+		t.Errorf("MaskAccount = %q", got)
+	}
+	if got := MaskAccount("6789"); got != "6789" {
+		t.Errorf("MaskAccount(short) = %q", got)
+	}
+	amounts := map[float64]string{0: "0.00", 999.5: "999.50", 1234567.5: "1,234,567.50", -1000: "-1,000.00"}
+	for in, want := range amounts {
+		if got := FormatAmount(in); got != want {
+			t.Errorf("FormatAmount(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
+	"github.com/google/uuid"
+)
+
+// ErrBillPaymentNotPending is returned when a bill payment has already been completed, failed or reversed.
+var ErrBillPaymentNotPending = errors.New("bill payment is no longer pending")
+
+// Bill payment statuses (bill_payment_status enum).
+const (
+	BillStatusPending  = "PENDING"
+	BillStatusSuccess  = "SUCCESS"
+	BillStatusFailed   = "FAILED"
+	BillStatusReversed = "REVERSED"
+)
+
+// BillRepository persists bill_payments and their wallet transactions. Tokens are encrypted at rest.
+type BillRepository struct {
+	db     *sql.DB
+	encKey string
+}
+
+// NewBillRepository returns a new bill payment repository. encKey must be 64 hex chars.
+func NewBillRepository(db *sql.DB, encKey string) *BillRepository {
+	return &BillRepository{db: db, encKey: encKey}
+}
+
+// BillPaymentRow is one bill payment with its wallet transaction's status.
+type BillPaymentRow struct {
+	ID                uuid.UUID
+	BillRef           string
+	UserID            uuid.UUID
+	WalletID          uuid.UUID
+	TransactionID     uuid.UUID
+	TransactionStatus string
+	RequeryCount      int
+	Provider          string
+	Category          string
+	BillerCode        string
+	BillerName        string
+	ItemCode          string
+	ItemName          string
+	CustomerID        string
+	CustomerName      string
+	Phone             string
+	Amount            float64
+	Status            string
+	DebitRef          string
+	DebitedAt         *time.Time
+	ProviderRef       string
+	Token             string
+	Units             string
+	FailureReason     string
+	DeliveryChannel   string
+	TokenDeliveredAt  *time.Time
+	CompletedAt       *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// CreateBillPaymentParams are inputs for a new PENDING bill payment.
+type CreateBillPaymentParams struct {
+	BillRef         string
+	UserID          uuid.UUID
+	WalletID        uuid.UUID
+	Provider        string
+	Category        string
+	BillerCode      string
+	BillerName      string
+	ItemCode        string
+	ItemName        string
+	CustomerID      string
+	CustomerName    string
+	Phone           string
+	Amount          float64
+	Narration       string
+	DeliveryChannel string
+	IdempotencyKey  string
+	Guard           *SpendGuard // optional; re-checks the spending limits under the wallet lock
+}
+
+const billSelect = `SELECT b.id, b.bill_ref, b.user_id, b.wallet_id, b.transaction_id, t.status::text, t.requery_count,
+	b.provider, b.category, b.biller_code, b.biller_name, b.item_code, b.item_name, b.customer_id,
+	COALESCE(b.customer_name, ''), COALESCE(b.phone, ''), b.amount, b.status::text,
+	COALESCE(b.debit_ref, ''), b.debited_at, COALESCE(b.provider_ref, ''), b.enc_token, COALESCE(b.units, ''),
+	COALESCE(b.failure_reason, ''), b.delivery_channel, b.token_delivered_at, b.completed_at, b.created_at, b.updated_at
+	FROM bill_payments b
+	JOIN transactions t ON t.id = b.transaction_id`
+
+func (r *BillRepository) scan(s rowScanner) (*BillPaymentRow, error) {
+	var b BillPaymentRow
+	var encToken []byte
+	err := s.Scan(&b.ID, &b.BillRef, &b.UserID, &b.WalletID, &b.TransactionID, &b.TransactionStatus, &b.RequeryCount,
+		&b.Provider, &b.Category, &b.BillerCode, &b.BillerName, &b.ItemCode, &b.ItemName, &b.CustomerID,
+		&b.CustomerName, &b.Phone, &b.Amount, &b.Status,
+		&b.DebitRef, &b.DebitedAt, &b.ProviderRef, &encToken, &b.Units,
+		&b.FailureReason, &b.DeliveryChannel, &b.TokenDeliveredAt, &b.CompletedAt, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(encToken) > 0 && r.encKey != "" {
+		if dec, err := crypto.Decrypt(encToken, r.encKey); err == nil {
+			b.Token = string(dec)
+		}
+	}
+	return &b, nil
+}
+
+func (r *BillRepository) get(ctx context.Context, where string, args ...interface{}) (*BillPaymentRow, error) {
+	row, err := r.scan(r.db.QueryRowContext(ctx, billSelect+` WHERE `+where, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return row, nil
+}
+
+// GetByID returns a bill payment by id, or nil if not found.
+func (r *BillRepository) GetByID(ctx context.Context, id uuid.UUID) (*BillPaymentRow, error) {
+	return r.get(ctx, `b.id = $1`, id)
+}
+
+// GetByRefForUser returns the user's bill payment by bill_ref, or nil if not found.
+func (r *BillRepository) GetByRefForUser(ctx context.Context, userID uuid.UUID, ref string) (*BillPaymentRow, error) {
+	return r.get(ctx, `b.bill_ref = $1 AND b.user_id = $2`, ref, userID)
+}
+
+// GetByIdempotencyKey returns the user's bill payment created with the given idempotency key, or nil. Another user's
+// payment under the same key is never returned.
+func (r *BillRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*BillPaymentRow, error) {
+	return r.get(ctx, `t.idempotency_key = $1 AND b.user_id = $2`, key, userID)
+}
+
+// ListByUser returns the user's bill payments, newest first. category filters when non-empty.
+func (r *BillRepository) ListByUser(ctx context.Context, userID uuid.UUID, category string, limit, offset int) ([]BillPaymentRow, error) {
+	limit, offset = clampPage(limit, offset, 20)
+	return r.list(ctx, `b.user_id = $1 AND ($2 = '' OR b.category = $2) ORDER BY b.created_at DESC LIMIT $3 OFFSET $4`,
+		userID, category, limit, offset)
+}
+
+// ListForRequery returns debited PENDING payments whose outcome is unknown: those marked REQUIRES_REQUERY whose backoff
+// (2^requery_count minutes, capped at an hour) has elapsed, and those left PENDING for more than five minutes (the process
+// stopped mid-vend). Payments requeried maxRequeries times are left for manual review.
+func (r *BillRepository) ListForRequery(ctx context.Context, maxRequeries, limit int) ([]BillPaymentRow, error) {
+	return r.list(ctx, `b.status = 'PENDING' AND b.debited_at IS NOT NULL AND t.requery_count < $1 AND (
+		(t.status = 'REQUIRES_REQUERY' AND (t.last_requeried_at IS NULL
+			OR t.last_requeried_at < NOW() - make_interval(mins => LEAST(POWER(2, t.requery_count)::int, 60))))
+		OR (t.status = 'PENDING' AND b.updated_at < NOW() - INTERVAL '5 minutes'))
+		ORDER BY b.created_at LIMIT $2`, maxRequeries, limit)
+}
+
+func (r *BillRepository) list(ctx context.Context, where string, args ...interface{}) ([]BillPaymentRow, error) {
+	rows, err := r.db.QueryContext(ctx, billSelect+` WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []BillPaymentRow
+	for rows.Next() {
+		b, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *b)
+	}
+	return list, rows.Err()
+}
+
+// CreatePending inserts the PENDING wallet transaction (DEBIT/OUT, transaction_ref = bill_ref) and the PENDING bill payment
+// in one DB transaction, under the wallet row lock and after p.Guard (if any) passes. Nothing is posted to the ledger
+// until MarkDebited.
+func (r *BillRepository) CreatePending(ctx context.Context, p *CreateBillPaymentParams) (row *BillPaymentRow, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if err = lockWallet(ctx, tx, p.WalletID); err != nil {
+		return nil, err
+	}
+	if err = p.Guard.check(ctx, tx, p.WalletID); err != nil {
+		return nil, err
+	}
+	var txnID, billID uuid.UUID
+	if err = tx.QueryRowContext(ctx, `INSERT INTO transactions (
+		wallet_id, transaction_ref, type, direction, amount, fee_amount,
+		narration, status, channel, initiated_by, idempotency_key
+	) VALUES ($1,$2,'DEBIT','OUT',$3,0,$4,'PENDING','API',$5,$6)
+	RETURNING id`,
+		p.WalletID, p.BillRef, p.Amount, p.Narration, p.UserID.String(), optStr(p.IdempotencyKey),
+	).Scan(&txnID); err != nil {
+		return nil, err
+	}
+	if err = tx.QueryRowContext(ctx, `INSERT INTO bill_payments (
+		bill_ref, user_id, wallet_id, transaction_id, provider, category, biller_code, biller_name, item_code, item_name,
+		customer_id, customer_name, phone, amount, delivery_channel
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
+	RETURNING id`,
+		p.BillRef, p.UserID, p.WalletID, txnID, p.Provider, p.Category, p.BillerCode, p.BillerName, p.ItemCode, p.ItemName,
+		p.CustomerID, nullStr(p.CustomerName), nullStr(p.Phone), p.Amount, p.DeliveryChannel,
+	).Scan(&billID); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, billID)
+}
+
+// MarkDebited posts the DEBIT ledger entry for the payment's transaction (after 9PSB debited the wallet) and records the
+// 9PSB reference. The transaction stays PENDING until the vend outcome is known.
+func (r *BillRepository) MarkDebited(ctx context.Context, b *BillPaymentRow, debitRef, narration string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	var ledgerID uuid.UUID
+	if err = tx.QueryRowContext(ctx, `SELECT post_ledger_entry($1, $2, 'DEBIT'::ledger_entry_type, $3, 'NGN', $4)`,
+		b.TransactionID, b.WalletID, b.Amount, optStr(narration)).Scan(&ledgerID); err != nil {
+		return fmt.Errorf("post_ledger_entry: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE bill_payments SET debit_ref = $1, debited_at = NOW() WHERE id = $2 AND debited_at IS NULL`,
+		optStr(debitRef), b.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkSuccess completes a PENDING payment: bill SUCCESS with the aggregator reference and (encrypted) token, transaction SUCCESS.
+func (r *BillRepository) MarkSuccess(ctx context.Context, b *BillPaymentRow, providerRef, token, units string) error {
+	var encToken []byte
+	if token != "" && r.encKey != "" {
+		var err error
+		if encToken, err = crypto.Encrypt([]byte(token), r.encKey); err != nil {
+			return err
+		}
+	}
+	return r.finish(ctx, b, func(tx *sql.Tx) error {
+		if err := r.updateBill(ctx, tx, b.ID, `status = 'SUCCESS', provider_ref = COALESCE($2, provider_ref), enc_token = $3,
+			units = $4, completed_at = NOW()`, optStr(providerRef), encToken, optStr(units)); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE transactions SET status = 'SUCCESS', provider_ref = COALESCE($1, provider_ref), updated_at = NOW() WHERE id = $2`,
+			optStr(providerRef), b.TransactionID)
+		return err
+	})
+}
+
+// MarkRequery flags a debited payment whose vend outcome is unknown; the requery worker picks it up.
+func (r *BillRepository) MarkRequery(ctx context.Context, b *BillPaymentRow, providerRef string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE transactions SET status = 'REQUIRES_REQUERY', provider_ref = COALESCE($1, provider_ref), updated_at = NOW()
+		WHERE id = $2 AND status IN ('PENDING', 'REQUIRES_REQUERY')`, optStr(providerRef), b.TransactionID)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE bill_payments SET provider_ref = COALESCE($1, provider_ref) WHERE id = $2`, optStr(providerRef), b.ID)
+	return err
+}
+
+// RecordRequery counts a requery attempt on the payment's transaction.
+func (r *BillRepository) RecordRequery(ctx context.Context, b *BillPaymentRow) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE transactions SET requery_count = requery_count + 1, last_requeried_at = NOW(),
+		status = CASE WHEN status = 'PENDING' THEN 'REQUIRES_REQUERY'::txn_status ELSE status END, updated_at = NOW() WHERE id = $1`,
+		b.TransactionID)
+	return err
+}
+
+// MarkFailed fails a PENDING payment that was never debited: bill and transaction FAILED, nothing on the ledger.
+func (r *BillRepository) MarkFailed(ctx context.Context, b *BillPaymentRow, reason string) error {
+	return r.finish(ctx, b, func(tx *sql.Tx) error {
+		if err := r.updateBill(ctx, tx, b.ID, `status = 'FAILED', failure_reason = $2, completed_at = NOW()`, truncate(reason, 255)); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE transactions SET status = 'FAILED', updated_at = NOW() WHERE id = $1`, b.TransactionID)
+		return err
+	})
+}
+
+// Reverse refunds a debited PENDING payment whose vend failed: inserts a SUCCESS REVERSAL transaction (parent = original)
+// with a CREDIT ledger entry, and marks the original transaction and the bill REVERSED. creditRef is the 9PSB reference of
+// the refund credit, if any.
+func (r *BillRepository) Reverse(ctx context.Context, b *BillPaymentRow, reversalRef, creditRef, reason string) error {
+	return r.finish(ctx, b, func(tx *sql.Tx) error {
+		var reversalID uuid.UUID
+		narration := truncate("Reversal: "+b.BillerName+" "+b.CustomerID, 255)
+		if err := tx.QueryRowContext(ctx, `INSERT INTO transactions (
+			wallet_id, transaction_ref, provider_ref, type, direction, amount, fee_amount,
+			narration, status, channel, parent_txn_id, initiated_by
+		) VALUES ($1,$2,$3,'REVERSAL','IN',$4,0,$5,'SUCCESS','API',$6,'system:bills')
+		RETURNING id`,
+			b.WalletID, reversalRef, optStr(creditRef), b.Amount, narration, b.TransactionID,
+		).Scan(&reversalID); err != nil {
+			return err
+		}
+		var ledgerID uuid.UUID
+		if err := tx.QueryRowContext(ctx, `SELECT post_ledger_entry($1, $2, 'CREDIT'::ledger_entry_type, $3, 'NGN', $4)`,
+			reversalID, b.WalletID, b.Amount, narration).Scan(&ledgerID); err != nil {
+			return fmt.Errorf("post_ledger_entry: %w", err)
+		}
+		if err := r.updateBill(ctx, tx, b.ID, `status = 'REVERSED', failure_reason = $2, reversal_txn_id = $3, completed_at = NOW()`,
+			truncate(reason, 255), reversalID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE transactions SET status = 'REVERSED', updated_at = NOW() WHERE id = $1`, b.TransactionID)
+		return err
+	})
+}
+
+// MarkTokenDelivered records that the token was handed to the notification service.
+func (r *BillRepository) MarkTokenDelivered(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE bill_payments SET token_delivered_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// finish runs fn in a DB transaction after locking the bill row; fn must move it out of PENDING.
+func (r *BillRepository) finish(ctx context.Context, b *BillPaymentRow, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	var status string
+	if err = tx.QueryRowContext(ctx, `SELECT status::text FROM bill_payments WHERE id = $1 FOR UPDATE`, b.ID).Scan(&status); err != nil {
+		return err
+	}
+	if status != BillStatusPending {
+		return ErrBillPaymentNotPending
+	}
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateBill applies set (which may reference $2...) to the bill with id $1.
+func (r *BillRepository) updateBill(ctx context.Context, tx *sql.Tx, id uuid.UUID, set string, args ...interface{}) error {
+	_, err := tx.ExecContext(ctx, `UPDATE bill_payments SET `+set+` WHERE id = $1`, append([]interface{}{id}, args...)...)
+	return err
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDisputeAlreadyOpen is returned when the transaction already has an unresolved dispute.
+var ErrDisputeAlreadyOpen = errors.New("transaction already has an open dispute")
+
+// ErrDisputeStatusConflict is returned when a status change is attempted from a status that no longer matches (already resolved or changed concurrently).
+var ErrDisputeStatusConflict = errors.New("dispute status has changed")
+
+// Dispute statuses (dispute_status enum).
+const (
+	DisputeStatusOpen         = "OPEN"
+	DisputeStatusUnderReview  = "UNDER_REVIEW"
+	DisputeStatusAwaitingUser = "AWAITING_USER"
+	// DisputeStatusPaymentPending is a resolution whose wallet payment is in flight or was not confirmed (9PSB timed
+	// out, say). It is retried with the same idempotency key rather than reopened, so the payment cannot be made twice.
+	DisputeStatusPaymentPending = "PAYMENT_PENDING"
+	DisputeStatusResolved       = "RESOLVED"
+	DisputeStatusRejected       = "REJECTED"
+)
+
+// Dispute actors (dispute_actor enum) for notes and attachments.
+const (
+	DisputeActorUser   = "USER"
+	DisputeActorAdmin  = "ADMIN"
+	DisputeActorSystem = "SYSTEM"
+)
+
+// DisputeRepository persists transaction_disputes, dispute_notes and dispute_attachments.
+type DisputeRepository struct {
+	db *sql.DB
+}
+
+// NewDisputeRepository returns a new dispute repository.
+func NewDisputeRepository(db *sql.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// DisputeRow is one dispute joined with the disputed transaction (ref, amount, status, direction).
+type DisputeRow struct {
+	ID                   string
+	DisputeRef           string
+	TransactionID        string
+	TransactionRef       string
+	TransactionAmount    float64
+	TransactionStatus    string
+	TransactionDirection string
+	WalletID             string
+	UserID               string
+	Reason               string
+	Description          string
+	Status               string
+	AssignedTo           string
+	SLADueAt             time.Time
+	FirstResponseAt      *time.Time
+	Resolution           string
+	ResolutionAmount     float64
+	ResolutionIsCredit   bool // whether the resolution payment credits (true) or debits the wallet
+	ResolutionTxnRef     string
+	ResolutionNote       string
+	ResolvedBy           string
+	ResolvedAt           *time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// DisputeNoteRow is one note on a dispute.
+type DisputeNoteRow struct {
+	ID         string
+	AuthorType string
+	AuthorID   string
+	Body       string
+	IsInternal bool
+	CreatedAt  time.Time
+}
+
+// DisputeAttachmentRow is one attachment (evidence) on a dispute.
+type DisputeAttachmentRow struct {
+	ID             string
+	UploadedByType string
+	UploadedBy     string
+	FileName       string
+	ContentType    string
+	URL            string
+	CreatedAt      time.Time
+}
+
+// CreateDisputeParams are inputs for opening a dispute.
+type CreateDisputeParams struct {
+	TransactionID uuid.UUID
+	WalletID      uuid.UUID
+	UserID        uuid.UUID
+	Reason        string
+	Description   string
+	SLADueAt      time.Time
+}
+
+const disputeSelect = `SELECT d.id::text, d.dispute_ref, d.transaction_id::text, t.transaction_ref, t.amount, t.status::text, t.direction::text,
+	d.wallet_id::text, d.user_id::text, d.reason, d.description, d.status::text, COALESCE(d.assigned_to, ''),
+	d.sla_due_at, d.first_response_at, COALESCE(d.resolution::text, ''), COALESCE(d.resolution_amount, 0),
+	COALESCE(d.resolution_is_credit, true), COALESCE(d.resolution_txn_ref, ''), COALESCE(d.resolution_note, ''), COALESCE(d.resolved_by, ''), d.resolved_at,
+	d.created_at, d.updated_at
+	FROM transaction_disputes d
+	JOIN transactions t ON t.id = d.transaction_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDispute(s rowScanner) (*DisputeRow, error) {
+	var d DisputeRow
+	err := s.Scan(&d.ID, &d.DisputeRef, &d.TransactionID, &d.TransactionRef, &d.TransactionAmount, &d.TransactionStatus, &d.TransactionDirection,
+		&d.WalletID, &d.UserID, &d.Reason, &d.Description, &d.Status, &d.AssignedTo,
+		&d.SLADueAt, &d.FirstResponseAt, &d.Resolution, &d.ResolutionAmount,
+		&d.ResolutionIsCredit, &d.ResolutionTxnRef, &d.ResolutionNote, &d.ResolvedBy, &d.ResolvedAt,
+		&d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Create inserts an OPEN dispute and returns it. Returns ErrDisputeAlreadyOpen if the transaction already has an unresolved dispute.
+// dispute_ref format: DSPyyyymmdd + 8-char unique.
+func (r *DisputeRepository) Create(ctx context.Context, p *CreateDisputeParams) (*DisputeRow, error) {
+	disputeRef := "DSP" + time.Now().Format("20060102") + strings.ToUpper(uuid.New().String()[:8])
+	var id uuid.UUID
+	err := r.db.QueryRowContext(ctx, `INSERT INTO transaction_disputes (
+		dispute_ref, transaction_id, wallet_id, user_id, reason, description, status, sla_due_at
+	) VALUES ($1,$2,$3,$4,$5,$6,'OPEN',$7)
+	ON CONFLICT (transaction_id) WHERE status IN ('OPEN', 'UNDER_REVIEW', 'AWAITING_USER', 'PAYMENT_PENDING') DO NOTHING
+	RETURNING id`,
+		disputeRef, p.TransactionID, p.WalletID, p.UserID, p.Reason, p.Description, p.SLADueAt,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDisputeAlreadyOpen
+		}
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByID returns one dispute by id, or nil if not found.
+func (r *DisputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*DisputeRow, error) {
+	d, err := scanDispute(r.db.QueryRowContext(ctx, disputeSelect+` WHERE d.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// GetByRefAndUserID returns one dispute by dispute_ref owned by the user, or nil if not found.
+func (r *DisputeRepository) GetByRefAndUserID(ctx context.Context, disputeRef string, userID uuid.UUID) (*DisputeRow, error) {
+	d, err := scanDispute(r.db.QueryRowContext(ctx, disputeSelect+` WHERE d.dispute_ref = $1 AND d.user_id = $2`, disputeRef, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// GetLatestByTransactionID returns the most recent dispute for the transaction, or nil if it was never disputed.
+func (r *DisputeRepository) GetLatestByTransactionID(ctx context.Context, transactionID uuid.UUID) (*DisputeRow, error) {
+	d, err := scanDispute(r.db.QueryRowContext(ctx, disputeSelect+` WHERE d.transaction_id = $1 ORDER BY d.created_at DESC LIMIT 1`, transactionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// ListByUserID returns the user's disputes, newest first.
+func (r *DisputeRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]DisputeRow, error) {
+	limit, offset = clampPage(limit, offset, 20)
+	rows, err := r.db.QueryContext(ctx, disputeSelect+` WHERE d.user_id = $1 ORDER BY d.created_at DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectDisputes(rows)
+}
+
+// CountOpenByUserID returns the user's disputes that are still being worked (OPEN, UNDER_REVIEW, AWAITING_USER,
+// PAYMENT_PENDING).
+func (r *DisputeRepository) CountOpenByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var n int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM transaction_disputes WHERE user_id = $1 AND status IN ($2, $3, $4, $5)`,
+		userID, DisputeStatusOpen, DisputeStatusUnderReview, DisputeStatusAwaitingUser, DisputeStatusPaymentPending).Scan(&n)
+	return n, err
+}
+
+// ListForAdmin returns the admin dispute queue. status filters by dispute status (empty = all).
+// Ordered by SLA deadline (most urgent first) so breached and near-breach cases surface at the top.
+func (r *DisputeRepository) ListForAdmin(ctx context.Context, status string, limit, offset int) ([]DisputeRow, error) {
+	limit, offset = clampPage(limit, offset, 50)
+	query := disputeSelect + ` WHERE ($1 = '' OR d.status::text = $1) ORDER BY d.sla_due_at ASC, d.created_at ASC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectDisputes(rows)
+}
+
+// CountForAdmin returns the number of disputes with the given status (empty = all).
+func (r *DisputeRepository) CountForAdmin(ctx context.Context, status string) (int64, error) {
+	var n int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM transaction_disputes WHERE ($1 = '' OR status::text = $1)`, status).Scan(&n)
+	return n, err
+}
+
+func collectDisputes(rows *sql.Rows) ([]DisputeRow, error) {
+	var list []DisputeRow
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *d)
+	}
+	return list, rows.Err()
+}
+
+func clampPage(limit, offset, defaultLimit int) (int, int) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// UpdateStatus moves the dispute from fromStatus to toStatus. assignedTo is set when non-empty; first_response_at is stamped
+// on the first admin action. Returns ErrDisputeStatusConflict if the dispute is no longer in fromStatus.
+func (r *DisputeRepository) UpdateStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus, assignedTo string, adminAction bool) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE transaction_disputes SET
+		status = $1::dispute_status,
+		assigned_to = COALESCE($2, assigned_to),
+		first_response_at = CASE WHEN $3 AND first_response_at IS NULL THEN NOW() ELSE first_response_at END
+		WHERE id = $4 AND status = $5::dispute_status`,
+		toStatus, nullStr(assignedTo), adminAction, id, fromStatus)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrDisputeStatusConflict
+	}
+	return nil
+}
+
+// ClaimResolution atomically moves an unresolved dispute to toStatus (RESOLVED or REJECTED, or PAYMENT_PENDING when the
+// resolution moves money) and records the outcome. Only one caller can claim a dispute, so a resolution payment is never
+// posted twice. Returns ErrDisputeStatusConflict if the dispute left fromStatus.
+func (r *DisputeRepository) ClaimResolution(ctx context.Context, id uuid.UUID, fromStatus, toStatus, resolution string, amount float64, isCredit bool, note, resolvedBy string) error {
+	var amt, credit interface{}
+	if amount > 0 {
+		amt, credit = amount, isCredit
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE transaction_disputes SET
+		status = $1::dispute_status,
+		resolution = $2::dispute_resolution,
+		resolution_amount = $3,
+		resolution_is_credit = $4,
+		resolution_note = $5,
+		resolved_by = $6,
+		resolved_at = NOW(),
+		first_response_at = COALESCE(first_response_at, NOW())
+		WHERE id = $7 AND status = $8::dispute_status`,
+		toStatus, nullStr(resolution), amt, credit, nullStr(note), nullStr(resolvedBy), id, fromStatus)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrDisputeStatusConflict
+	}
+	return nil
+}
+
+// ReleaseResolution undoes ClaimResolution when the resolution payment certainly moved no money, putting a
+// PAYMENT_PENDING dispute back in fromStatus.
+func (r *DisputeRepository) ReleaseResolution(ctx context.Context, id uuid.UUID, fromStatus string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE transaction_disputes SET
+		status = $1::dispute_status, resolution = NULL, resolution_amount = NULL, resolution_is_credit = NULL,
+		resolution_note = NULL, resolved_by = NULL, resolved_at = NULL
+		WHERE id = $2 AND status = $3::dispute_status AND resolution_txn_ref IS NULL`, fromStatus, id, DisputeStatusPaymentPending)
+	return err
+}
+
+// CompleteResolution records the transaction_ref of the resolution payment and moves the dispute from PAYMENT_PENDING to
+// RESOLVED. Returns ErrDisputeStatusConflict if it is no longer PAYMENT_PENDING.
+func (r *DisputeRepository) CompleteResolution(ctx context.Context, id uuid.UUID, txnRef string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE transaction_disputes SET status = $1::dispute_status, resolution_txn_ref = $2, resolved_at = NOW()
+		WHERE id = $3 AND status = $4::dispute_status`, DisputeStatusResolved, txnRef, id, DisputeStatusPaymentPending)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrDisputeStatusConflict
+	}
+	return nil
+}
+
+// AddNote inserts a note. Admin notes also stamp first_response_at.
+func (r *DisputeRepository) AddNote(ctx context.Context, disputeID uuid.UUID, authorType, authorID, body string, internal bool) (*DisputeNoteRow, error) {
+	var n DisputeNoteRow
+	err := r.db.QueryRowContext(ctx, `INSERT INTO dispute_notes (dispute_id, author_type, author_id, body, is_internal)
+		VALUES ($1, $2::dispute_actor, $3, $4, $5)
+		RETURNING id::text, author_type::text, COALESCE(author_id, ''), body, is_internal, created_at`,
+		disputeID, authorType, nullStr(authorID), body, internal,
+	).Scan(&n.ID, &n.AuthorType, &n.AuthorID, &n.Body, &n.IsInternal, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if authorType == DisputeActorAdmin {
+		if _, err := r.db.ExecContext(ctx, `UPDATE transaction_disputes SET first_response_at = NOW() WHERE id = $1 AND first_response_at IS NULL`, disputeID); err != nil {
+			return nil, fmt.Errorf("stamp first response: %w", err)
+		}
+	}
+	return &n, nil
+}
+
+// ListNotes returns the dispute's notes oldest first. includeInternal=false hides admin-only notes (user view).
+func (r *DisputeRepository) ListNotes(ctx context.Context, disputeID uuid.UUID, includeInternal bool) ([]DisputeNoteRow, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id::text, author_type::text, COALESCE(author_id, ''), body, is_internal, created_at
+		FROM dispute_notes WHERE dispute_id = $1 AND ($2 OR is_internal = FALSE) ORDER BY created_at ASC`, disputeID, includeInternal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []DisputeNoteRow
+	for rows.Next() {
+		var n DisputeNoteRow
+		if err := rows.Scan(&n.ID, &n.AuthorType, &n.AuthorID, &n.Body, &n.IsInternal, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, rows.Err()
+}
+
+// AddAttachment records an uploaded evidence file for the dispute.
+func (r *DisputeRepository) AddAttachment(ctx context.Context, disputeID uuid.UUID, uploadedByType, uploadedBy, fileName, contentType, url string) (*DisputeAttachmentRow, error) {
+	var a DisputeAttachmentRow
+	err := r.db.QueryRowContext(ctx, `INSERT INTO dispute_attachments (dispute_id, uploaded_by_type, uploaded_by, file_name, content_type, url)
+		VALUES ($1, $2::dispute_actor, $3, $4, $5, $6)
+		RETURNING id::text, uploaded_by_type::text, COALESCE(uploaded_by, ''), file_name, content_type, url, created_at`,
+		disputeID, uploadedByType, nullStr(uploadedBy), fileName, contentType, url,
+	).Scan(&a.ID, &a.UploadedByType, &a.UploadedBy, &a.FileName, &a.ContentType, &a.URL, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAttachments returns the dispute's attachments oldest first.
+func (r *DisputeRepository) ListAttachments(ctx context.Context, disputeID uuid.UUID) ([]DisputeAttachmentRow, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id::text, uploaded_by_type::text, COALESCE(uploaded_by, ''), file_name, content_type, url, created_at
+		FROM dispute_attachments WHERE dispute_id = $1 ORDER BY created_at ASC`, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []DisputeAttachmentRow
+	for rows.Next() {
+		var a DisputeAttachmentRow
+		if err := rows.Scan(&a.ID, &a.UploadedByType, &a.UploadedBy, &a.FileName, &a.ContentType, &a.URL, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
+	"github.com/google/uuid"
+)
+
+// ErrPaymentRequestNotPending is returned when a request is no longer PENDING (paid, declined, expired) or is already being paid.
+var ErrPaymentRequestNotPending = errors.New("payment request is no longer pending")
+
+// Payment request statuses (payment_request_status enum).
+const (
+	PaymentRequestStatusPending  = "PENDING"
+	PaymentRequestStatusPaid     = "PAID"
+	PaymentRequestStatusDeclined = "DECLINED"
+	PaymentRequestStatusExpired  = "EXPIRED"
+)
+
+// How a payment request was settled (payment_requests.paid_via).
+const (
+	PaymentRequestPaidInApp        = "IN_APP"
+	PaymentRequestPaidBankTransfer = "BANK_TRANSFER"
+)
+
+// payClaimTTL is how long an in-app payment claim blocks other settlements (Postgres interval). A claim older than this is
+// treated as abandoned (e.g. the process died mid-transfer) so the request does not stay stuck.
+const payClaimTTL = "2 minutes"
+
+// PaymentRequestRepository persists payment_requests. Requester and payer wallet names/account numbers are decrypted on read.
+type PaymentRequestRepository struct {
+	db     *sql.DB
+	encKey string
+}
+
+// NewPaymentRequestRepository returns a new payment request repository. encKey must be 64 hex chars.
+func NewPaymentRequestRepository(db *sql.DB, encKey string) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db: db, encKey: encKey}
+}
+
+// PaymentRequestRow is one payment request joined with the requester (and payer, if any) wallet.
+type PaymentRequestRow struct {
+	ID                     string
+	RequestRef             string
+	RequesterUserID        string
+	RequesterWalletID      string
+	RequesterName          string
+	RequesterAccountNumber string
+	PayerUserID            string // empty for open pay links
+	PayerWalletID          string
+	PayerName              string
+	Amount                 float64
+	Note                   string
+	Status                 string
+	ExpiresAt              time.Time
+	PaidByUserID           string
+	PaidAmount             float64
+	PaidTxnRef             string
+	PaidVia                string
+	PaidAt                 *time.Time
+	DeclineReason          string
+	DeclinedAt             *time.Time
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// CreatePaymentRequestParams are inputs for creating a payment request. PayerUserID/PayerWalletID are uuid.Nil for an open link.
+type CreatePaymentRequestParams struct {
+	RequesterUserID   uuid.UUID
+	RequesterWalletID uuid.UUID
+	PayerUserID       uuid.UUID
+	PayerWalletID     uuid.UUID
+	Amount            float64
+	Note              string
+	ExpiresAt         time.Time
+}
+
+const paymentRequestSelect = `SELECT pr.id::text, pr.request_ref, pr.requester_user_id::text, pr.requester_wallet_id::text,
+	rw.enc_full_name, rw.enc_account_number,
+	COALESCE(pr.payer_user_id::text, ''), COALESCE(pr.payer_wallet_id::text, ''), pw.enc_full_name,
+	pr.amount, pr.note, pr.status::text, pr.expires_at,
+	COALESCE(pr.paid_by_user_id::text, ''), COALESCE(pr.paid_amount, 0), COALESCE(pr.paid_txn_ref, ''), COALESCE(pr.paid_via, ''), pr.paid_at,
+	COALESCE(pr.decline_reason, ''), pr.declined_at, pr.created_at, pr.updated_at
+	FROM payment_requests pr
+	JOIN wallets rw ON rw.id = pr.requester_wallet_id
+	LEFT JOIN wallets pw ON pw.id = pr.payer_wallet_id`
+
+func (r *PaymentRequestRepository) scan(s rowScanner) (*PaymentRequestRow, error) {
+	var p PaymentRequestRow
+	var encRequesterName, encRequesterAccount, encPayerName []byte
+	err := s.Scan(&p.ID, &p.RequestRef, &p.RequesterUserID, &p.RequesterWalletID,
+		&encRequesterName, &encRequesterAccount,
+		&p.PayerUserID, &p.PayerWalletID, &encPayerName,
+		&p.Amount, &p.Note, &p.Status, &p.ExpiresAt,
+		&p.PaidByUserID, &p.PaidAmount, &p.PaidTxnRef, &p.PaidVia, &p.PaidAt,
+		&p.DeclineReason, &p.DeclinedAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.RequesterName = r.decryptStr(encRequesterName)
+	p.RequesterAccountNumber = r.decryptStr(encRequesterAccount)
+	p.PayerName = r.decryptStr(encPayerName)
+	return &p, nil
+}
+
+func (r *PaymentRequestRepository) decryptStr(enc []byte) string {
+	if len(enc) == 0 || r.encKey == "" {
+		return ""
+	}
+	dec, err := crypto.Decrypt(enc, r.encKey)
+	if err != nil {
+		return ""
+	}
+	return string(dec)
+}
+
+// requestRefAlphabet omits 0/O and 1/I so refs survive being read aloud or retyped into a bank app narration.
+const requestRefAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// NewPaymentRequestRef returns a public request ref: "PR" + 8 random characters, e.g. PR7K2M9QXD.
+func NewPaymentRequestRef() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	out := make([]byte, 0, 10)
+	out = append(out, 'P', 'R')
+	for _, c := range b {
+		out = append(out, requestRefAlphabet[int(c)%len(requestRefAlphabet)])
+	}
+	return string(out)
+}
+
+// Create inserts a PENDING payment request and returns it.
+func (r *PaymentRequestRepository) Create(ctx context.Context, p *CreatePaymentRequestParams) (*PaymentRequestRow, error) {
+	var payerUser, payerWallet interface{}
+	if p.PayerUserID != uuid.Nil {
+		payerUser, payerWallet = p.PayerUserID, p.PayerWalletID
+	}
+	ref := NewPaymentRequestRef()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO payment_requests (
+		request_ref, requester_user_id, requester_wallet_id, payer_user_id, payer_wallet_id, amount, note, status, expires_at
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,'PENDING',$8)`,
+		ref, p.RequesterUserID, p.RequesterWalletID, payerUser, payerWallet, p.Amount, p.Note, p.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByRef(ctx, ref)
+}
+
+// GetByRef returns a payment request by request_ref, or nil if not found.
+func (r *PaymentRequestRepository) GetByRef(ctx context.Context, ref string) (*PaymentRequestRow, error) {
+	row, err := r.scan(r.db.QueryRowContext(ctx, paymentRequestSelect+` WHERE pr.request_ref = $1`, ref))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return row, nil
+}
+
+// ListForUser returns requests the user sent (role "sent") or was asked to pay (role "received"), newest first.
+// status filters when non-empty.
+func (r *PaymentRequestRepository) ListForUser(ctx context.Context, userID uuid.UUID, role, status string, limit, offset int) ([]PaymentRequestRow, error) {
+	limit, offset = clampPage(limit, offset, 20)
+	col := "pr.requester_user_id"
+	if role == "received" {
+		col = "pr.payer_user_id"
+	}
+	rows, err := r.db.QueryContext(ctx, paymentRequestSelect+` WHERE `+col+` = $1 AND ($2 = '' OR pr.status::text = $2)
+		ORDER BY pr.created_at DESC LIMIT $3 OFFSET $4`, userID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []PaymentRequestRow
+	for rows.Next() {
+		p, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *p)
+	}
+	return list, rows.Err()
+}
+
+// ClaimForPayment marks a PENDING, unexpired request as being paid in-app. Only one caller can hold the claim at a time;
+// returns ErrPaymentRequestNotPending otherwise. Call MarkPaid on success or ReleaseClaim if the transfer fails.
+func (r *PaymentRequestRepository) ClaimForPayment(ctx context.Context, ref string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE payment_requests SET pay_claimed_at = NOW()
+		WHERE request_ref = $1 AND status = 'PENDING' AND expires_at > NOW()
+		AND (pay_claimed_at IS NULL OR pay_claimed_at < NOW() - $2::interval)`,
+		ref, payClaimTTL)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPaymentRequestNotPending
+	}
+	return nil
+}
+
+// ReleaseClaim clears an in-app payment claim after the transfer failed so the request can be paid again.
+func (r *PaymentRequestRepository) ReleaseClaim(ctx context.Context, ref string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE payment_requests SET pay_claimed_at = NULL WHERE request_ref = $1 AND status = 'PENDING'`, ref)
+	return err
+}
+
+// MarkPaid settles a PENDING request. For IN_APP the caller must hold the claim (ClaimForPayment); for BANK_TRANSFER the
+// request must not be mid-payment in-app. Returns ErrPaymentRequestNotPending if the request was settled or declined meanwhile.
+func (r *PaymentRequestRepository) MarkPaid(ctx context.Context, ref string, paidBy uuid.UUID, amount float64, txnRef, via string) error {
+	var paidByVal interface{}
+	if paidBy != uuid.Nil {
+		paidByVal = paidBy
+	}
+	// In-app: the caller's claim must still be in place. Bank transfer: no in-app payment may be in flight.
+	claimCond := `pay_claimed_at IS NOT NULL`
+	if via == PaymentRequestPaidBankTransfer {
+		claimCond = `(pay_claimed_at IS NULL OR pay_claimed_at < NOW() - '` + payClaimTTL + `'::interval)`
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE payment_requests SET
+		status = 'PAID', paid_by_user_id = $1, paid_amount = $2, paid_txn_ref = $3, paid_via = $4, paid_at = NOW(), pay_claimed_at = NULL
+		WHERE request_ref = $5 AND status = 'PENDING' AND `+claimCond,
+		paidByVal, amount, txnRef, via, ref)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPaymentRequestNotPending
+	}
+	return nil
+}
+
+// Decline moves a PENDING request to DECLINED. Returns ErrPaymentRequestNotPending if it is no longer pending or is being paid.
+func (r *PaymentRequestRepository) Decline(ctx context.Context, ref, reason string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE payment_requests SET status = 'DECLINED', decline_reason = $1, declined_at = NOW()
+		WHERE request_ref = $2 AND status = 'PENDING' AND (pay_claimed_at IS NULL OR pay_claimed_at < NOW() - $3::interval)`,
+		nullStr(reason), ref, payClaimTTL)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPaymentRequestNotPending
+	}
+	return nil
+}
+
+// ExpireDue moves PENDING requests past expires_at (and not mid-payment) to EXPIRED. Returns the number of rows updated.
+func (r *PaymentRequestRepository) ExpireDue(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE payment_requests SET status = 'EXPIRED'
+		WHERE status = 'PENDING' AND expires_at <= NOW() AND (pay_claimed_at IS NULL OR pay_claimed_at < NOW() - $1::interval)`,
+		payClaimTTL)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,503 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pocket errors mapped from post_pocket_transfer and the savings_pockets constraints.
+var (
+	ErrPocketLocked              = errors.New("pocket is locked")
+	ErrPocketClosed              = errors.New("pocket is closed")
+	ErrPocketInsufficientBalance = errors.New("insufficient pocket balance")
+	ErrPocketNameTaken           = errors.New("a pocket with this name already exists")
+)
+
+// Savings pocket statuses (savings_pocket_status enum).
+const (
+	PocketStatusActive = "ACTIVE"
+	PocketStatusClosed = "CLOSED"
+)
+
+// PocketRepository persists savings_pockets and posts moves through post_pocket_transfer.
+type PocketRepository struct {
+	db *sql.DB
+}
+
+// NewPocketRepository returns a new savings pocket repository.
+func NewPocketRepository(db *sql.DB) *PocketRepository {
+	return &PocketRepository{db: db}
+}
+
+// PocketRow is one savings pocket.
+type PocketRow struct {
+	ID                     uuid.UUID
+	PocketRef              string
+	WalletID               uuid.UUID
+	UserID                 uuid.UUID
+	Name                   string
+	GoalAmount             *float64
+	Balance                float64
+	LockedUntil            *time.Time
+	Status                 string
+	AutosaveInboundPercent float64
+	AutosaveRoundupTo      float64
+	InterestAccrued        float64 // accrued, not yet set aside for a payout
+	InterestAccruedThrough *time.Time
+	InterestPaid           float64
+	ClosedAt               *time.Time
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// Locked reports whether withdrawals are refused at t.
+func (p *PocketRow) Locked(t time.Time) bool {
+	return p.LockedUntil != nil && p.LockedUntil.After(t)
+}
+
+// CreatePocketParams are inputs for creating a pocket. Zero autosave values leave the rule off.
+type CreatePocketParams struct {
+	WalletID               uuid.UUID
+	UserID                 uuid.UUID
+	Name                   string
+	GoalAmount             *float64
+	LockedUntil            *time.Time
+	AutosaveInboundPercent float64
+	AutosaveRoundupTo      float64
+}
+
+// UpdatePocketParams changes pocket settings. Nil fields are left unchanged. A lock can be extended but never shortened.
+type UpdatePocketParams struct {
+	Name                   *string
+	GoalAmount             *float64 // 0 clears the goal
+	LockedUntil            *time.Time
+	AutosaveInboundPercent *float64
+	AutosaveRoundupTo      *float64
+}
+
+// PocketTransferParams are inputs for a move between the main balance and a pocket.
+type PocketTransferParams struct {
+	PocketID       uuid.UUID
+	WalletID       uuid.UUID
+	TransactionRef string
+	ToPocket       bool // true: main -> pocket; false: pocket -> main
+	Amount         float64
+	Narration      string
+	InitiatedBy    string
+	IgnoreLock     bool // only for system moves that must bypass the lock
+}
+
+const pocketSelect = `SELECT id, pocket_ref, wallet_id, user_id, name, goal_amount, balance, locked_until, status::text,
+	autosave_inbound_percent, autosave_roundup_to, interest_accrued, interest_accrued_through, interest_paid, closed_at, created_at, updated_at
+	FROM savings_pockets`
+
+func scanPocket(s rowScanner) (*PocketRow, error) {
+	var p PocketRow
+	var goal sql.NullFloat64
+	err := s.Scan(&p.ID, &p.PocketRef, &p.WalletID, &p.UserID, &p.Name, &goal, &p.Balance, &p.LockedUntil, &p.Status,
+		&p.AutosaveInboundPercent, &p.AutosaveRoundupTo, &p.InterestAccrued, &p.InterestAccruedThrough, &p.InterestPaid,
+		&p.ClosedAt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if goal.Valid {
+		p.GoalAmount = &goal.Float64
+	}
+	return &p, nil
+}
+
+func (r *PocketRepository) list(ctx context.Context, where string, args ...interface{}) ([]PocketRow, error) {
+	rows, err := r.db.QueryContext(ctx, pocketSelect+` WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []PocketRow
+	for rows.Next() {
+		p, err := scanPocket(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *p)
+	}
+	return list, rows.Err()
+}
+
+// NewPocketRef returns a public pocket ref: "SP" + 8 random characters.
+func NewPocketRef() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	out := make([]byte, 0, 10)
+	out = append(out, 'S', 'P')
+	for _, c := range b {
+		out = append(out, requestRefAlphabet[int(c)%len(requestRefAlphabet)])
+	}
+	return string(out)
+}
+
+// Create inserts an ACTIVE pocket with zero balance and returns it. Returns ErrPocketNameTaken if the wallet already has
+// an active pocket with the same name (case-insensitive).
+func (r *PocketRepository) Create(ctx context.Context, p *CreatePocketParams) (*PocketRow, error) {
+	var goal interface{}
+	if p.GoalAmount != nil {
+		goal = *p.GoalAmount
+	}
+	row, err := scanPocket(r.db.QueryRowContext(ctx, `INSERT INTO savings_pockets (
+		pocket_ref, wallet_id, user_id, name, goal_amount, locked_until, autosave_inbound_percent, autosave_roundup_to
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	RETURNING id, pocket_ref, wallet_id, user_id, name, goal_amount, balance, locked_until, status::text,
+		autosave_inbound_percent, autosave_roundup_to, interest_accrued, interest_accrued_through, interest_paid, closed_at, created_at, updated_at`,
+		NewPocketRef(), p.WalletID, p.UserID, p.Name, goal, p.LockedUntil, p.AutosaveInboundPercent, p.AutosaveRoundupTo))
+	if err != nil {
+		return nil, mapPocketError(err)
+	}
+	return row, nil
+}
+
+// GetByRef returns the user's pocket by pocket_ref, or nil if not found.
+func (r *PocketRepository) GetByRef(ctx context.Context, userID uuid.UUID, ref string) (*PocketRow, error) {
+	row, err := scanPocket(r.db.QueryRowContext(ctx, pocketSelect+` WHERE pocket_ref = $1 AND user_id = $2`, ref, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return row, nil
+}
+
+// ListByUser returns the user's pockets, oldest first. Closed pockets are included only when includeClosed is set.
+func (r *PocketRepository) ListByUser(ctx context.Context, userID uuid.UUID, includeClosed bool) ([]PocketRow, error) {
+	return r.list(ctx, `user_id = $1 AND ($2 OR status = 'ACTIVE') ORDER BY created_at`, userID, includeClosed)
+}
+
+// ListAutosave returns the wallet's active pockets with an inbound-credit (inbound=true) or round-up (inbound=false) rule.
+func (r *PocketRepository) ListAutosave(ctx context.Context, walletID uuid.UUID, inbound bool) ([]PocketRow, error) {
+	col := "autosave_roundup_to"
+	if inbound {
+		col = "autosave_inbound_percent"
+	}
+	return r.list(ctx, `wallet_id = $1 AND status = 'ACTIVE' AND `+col+` > 0 ORDER BY created_at`, walletID)
+}
+
+// SumBalancesByWallet returns the total held in the wallet's active pockets.
+func (r *PocketRepository) SumBalancesByWallet(ctx context.Context, walletID uuid.UUID) (float64, error) {
+	var sum float64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(balance), 0) FROM savings_pockets WHERE wallet_id = $1 AND status = 'ACTIVE'`, walletID,
+	).Scan(&sum)
+	return sum, err
+}
+
+// Update applies settings changes to an active pocket and returns it.
+func (r *PocketRepository) Update(ctx context.Context, id uuid.UUID, p *UpdatePocketParams) (*PocketRow, error) {
+	sets := []string{}
+	args := []interface{}{id}
+	add := func(expr string, v interface{}) {
+		args = append(args, v)
+		sets = append(sets, fmt.Sprintf(expr, len(args)))
+	}
+	if p.Name != nil {
+		add("name = $%d", *p.Name)
+	}
+	if p.GoalAmount != nil {
+		var goal interface{}
+		if *p.GoalAmount > 0 {
+			goal = *p.GoalAmount
+		}
+		add("goal_amount = $%d", goal)
+	}
+	if p.LockedUntil != nil {
+		add("locked_until = GREATEST(locked_until, $%d::timestamptz)", *p.LockedUntil)
+	}
+	if p.AutosaveInboundPercent != nil {
+		add("autosave_inbound_percent = $%d", *p.AutosaveInboundPercent)
+	}
+	if p.AutosaveRoundupTo != nil {
+		add("autosave_roundup_to = $%d", *p.AutosaveRoundupTo)
+	}
+	if len(sets) > 0 {
+		res, err := r.db.ExecContext(ctx, `UPDATE savings_pockets SET `+strings.Join(sets, ", ")+` WHERE id = $1 AND status = 'ACTIVE'`, args...)
+		if err != nil {
+			return nil, mapPocketError(err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, ErrPocketClosed
+		}
+	}
+	row, err := scanPocket(r.db.QueryRowContext(ctx, pocketSelect+` WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// Transfer records a SUCCESS transaction (pocket_id set, no provider call) and posts it through post_pocket_transfer in one
+// DB transaction. The transaction is a DEBIT/OUT of the main balance when moving into the pocket and a CREDIT/IN when
+// moving out. Returns ErrPocketLocked, ErrPocketClosed or ErrPocketInsufficientBalance when the move is refused; a short
+// main balance surfaces as post_ledger_entry's "Insufficient balance" error.
+func (r *PocketRepository) Transfer(ctx context.Context, p *PocketTransferParams) (txnID uuid.UUID, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if err = r.insertPocketTransaction(ctx, tx, p, &txnID); err != nil {
+		return uuid.Nil, err
+	}
+	if err = postPocketTransfer(ctx, tx, txnID, p); err != nil {
+		return uuid.Nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return txnID, nil
+}
+
+// Close moves any remaining balance back to the main balance and marks the pocket CLOSED, in one DB transaction.
+// Returns the transaction id of the final move (uuid.Nil if the pocket was empty). Honours the lock.
+func (r *PocketRepository) Close(ctx context.Context, p *PocketTransferParams) (txnID uuid.UUID, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	var balance float64
+	var status string
+	var lockedUntil *time.Time
+	if err = tx.QueryRowContext(ctx, `SELECT balance, status::text, locked_until FROM savings_pockets WHERE id = $1 FOR UPDATE`,
+		p.PocketID).Scan(&balance, &status, &lockedUntil); err != nil {
+		return uuid.Nil, err
+	}
+	if status != PocketStatusActive {
+		return uuid.Nil, ErrPocketClosed
+	}
+	if !p.IgnoreLock && lockedUntil != nil && lockedUntil.After(time.Now()) {
+		return uuid.Nil, ErrPocketLocked
+	}
+	if balance > 0 {
+		p.ToPocket = false
+		p.Amount = balance
+		if err = r.insertPocketTransaction(ctx, tx, p, &txnID); err != nil {
+			return uuid.Nil, err
+		}
+		if err = postPocketTransfer(ctx, tx, txnID, p); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE savings_pockets SET status = 'CLOSED', closed_at = NOW(),
+		autosave_inbound_percent = 0, autosave_roundup_to = 0 WHERE id = $1`, p.PocketID); err != nil {
+		return uuid.Nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return txnID, nil
+}
+
+func (r *PocketRepository) insertPocketTransaction(ctx context.Context, tx *sql.Tx, p *PocketTransferParams, txnID *uuid.UUID) error {
+	txnType, direction := "DEBIT", "OUT"
+	if !p.ToPocket {
+		txnType, direction = "CREDIT", "IN"
+	}
+	return tx.QueryRowContext(ctx, `INSERT INTO transactions (
+		wallet_id, transaction_ref, type, direction, amount, fee_amount,
+		narration, status, channel, initiated_by, pocket_id
+	) VALUES ($1,$2,$3::txn_type,$4::txn_direction,$5,0,$6,'SUCCESS','API',$7,$8)
+	RETURNING id`,
+		p.WalletID, p.TransactionRef, txnType, direction, p.Amount, p.Narration, optStr(p.InitiatedBy), p.PocketID,
+	).Scan(txnID)
+}
+
+func postPocketTransfer(ctx context.Context, tx *sql.Tx, txnID uuid.UUID, p *PocketTransferParams) error {
+	var ledgerID uuid.UUID
+	err := tx.QueryRowContext(ctx, `SELECT post_pocket_transfer($1, $2, $3, $4, $5, $6)`,
+		txnID, p.PocketID, p.ToPocket, p.Amount, optStr(p.Narration), p.IgnoreLock).Scan(&ledgerID)
+	if err != nil {
+		if mapped := mapPocketError(err); mapped != err {
+			return mapped
+		}
+		return fmt.Errorf("post_pocket_transfer: %w", err)
+	}
+	return nil
+}
+
+func mapPocketError(err error) error {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "Pocket is locked"):
+		return ErrPocketLocked
+	case strings.Contains(msg, "Pocket is closed"):
+		return ErrPocketClosed
+	case strings.Contains(msg, "Insufficient pocket balance"):
+		return ErrPocketInsufficientBalance
+	case strings.Contains(msg, "idx_savings_pockets_wallet_name"):
+		return ErrPocketNameTaken
+	}
+	return err
+}
+
+// AccrueInterest adds one day's interest for every active pocket with a positive balance that has not yet accrued for
+// day (a UTC date). dailyRate(p) returns the fraction of the balance to accrue (e.g. 0.10/365); a non-positive rate skips
+// the pocket. Accrual is recorded on the pocket only; see ReserveInterestPayouts for paying it. Returns pockets accrued.
+func (r *PocketRepository) AccrueInterest(ctx context.Context, day time.Time, dailyRate func(p *PocketRow) float64) (int, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	list, err := r.list(ctx, `status = 'ACTIVE' AND balance > 0 AND (interest_accrued_through IS NULL OR interest_accrued_through < $1)
+		ORDER BY created_at`, day)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range list {
+		p := &list[i]
+		rate := dailyRate(p)
+		if rate <= 0 {
+			continue
+		}
+		res, err := r.db.ExecContext(ctx, `UPDATE savings_pockets SET interest_accrued = interest_accrued + ROUND(balance * $1::numeric, 4),
+			interest_accrued_through = $2
+			WHERE id = $3 AND status = 'ACTIVE' AND (interest_accrued_through IS NULL OR interest_accrued_through < $2)`,
+			rate, day, p.ID)
+		if err != nil {
+			return n, err
+		}
+		if k, _ := res.RowsAffected(); k > 0 {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// PocketInterestPayout is interest set aside for one pocket for one month, waiting for (or after) its 9PSB credit.
+type PocketInterestPayout struct {
+	ID             uuid.UUID
+	PocketID       uuid.UUID
+	WalletID       uuid.UUID
+	UserID         uuid.UUID
+	PocketRef      string
+	PocketName     string
+	PocketStatus   string
+	Period         string
+	Amount         float64
+	TransactionRef string
+}
+
+// ReserveInterestPayouts creates a PENDING payout for period (YYYY-MM) for every pocket, open or closed, with at least
+// NGN 0.01 of accrued interest and no payout for period yet, and takes the whole-kobo amount off interest_accrued in the
+// same statement; the sub-kobo rest keeps accruing. The payout's transaction ref is "INT" + pocket ref + YYYYMM.
+// Returns payouts created.
+func (r *PocketRepository) ReserveInterestPayouts(ctx context.Context, period string) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+		WITH due AS (
+			SELECT id, wallet_id, user_id, pocket_ref, FLOOR(interest_accrued * 100) / 100 AS amount
+			FROM savings_pockets p
+			WHERE interest_accrued >= 0.01
+				AND NOT EXISTS (SELECT 1 FROM pocket_interest_payouts x WHERE x.pocket_id = p.id AND x.period = $1)
+			FOR UPDATE
+		), payouts AS (
+			INSERT INTO pocket_interest_payouts (pocket_id, wallet_id, user_id, period, amount, transaction_ref)
+			SELECT id, wallet_id, user_id, $1, amount, 'INT' || pocket_ref || REPLACE($1, '-', '') FROM due
+			ON CONFLICT (pocket_id, period) DO NOTHING
+			RETURNING pocket_id, amount
+		)
+		UPDATE savings_pockets p SET interest_accrued = p.interest_accrued - payouts.amount
+		FROM payouts WHERE p.id = payouts.pocket_id`, period)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// ListPendingInterestPayouts returns up to limit payouts still waiting for their credit, oldest first.
+func (r *PocketRepository) ListPendingInterestPayouts(ctx context.Context, limit int) ([]PocketInterestPayout, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT x.id, x.pocket_id, x.wallet_id, x.user_id, p.pocket_ref, p.name, p.status::text,
+		x.period, x.amount, x.transaction_ref
+		FROM pocket_interest_payouts x JOIN savings_pockets p ON p.id = x.pocket_id
+		WHERE x.status = 'PENDING' ORDER BY x.created_at LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var list []PocketInterestPayout
+	for rows.Next() {
+		var x PocketInterestPayout
+		if err := rows.Scan(&x.ID, &x.PocketID, &x.WalletID, &x.UserID, &x.PocketRef, &x.PocketName, &x.PocketStatus,
+			&x.Period, &x.Amount, &x.TransactionRef); err != nil {
+			return nil, err
+		}
+		list = append(list, x)
+	}
+	return list, rows.Err()
+}
+
+// CompleteInterestPayout posts a payout 9PSB has credited, in one DB transaction: a SUCCESS CREDIT to the main balance
+// under the payout's transaction ref, a move of the same amount into the pocket while it is open (post_pocket_transfer),
+// interest_paid on the pocket and the payout marked PAID. A payout already PAID is left alone.
+func (r *PocketRepository) CompleteInterestPayout(ctx context.Context, x *PocketInterestPayout, providerRef string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	var status, pocketStatus string
+	if err = tx.QueryRowContext(ctx, `SELECT x.status::text, p.status::text
+		FROM pocket_interest_payouts x JOIN savings_pockets p ON p.id = x.pocket_id
+		WHERE x.id = $1 FOR UPDATE OF x, p`, x.ID).Scan(&status, &pocketStatus); err != nil {
+		return err
+	}
+	if status != "PENDING" {
+		return tx.Rollback()
+	}
+	narration := truncate("Interest on "+x.PocketName, 255)
+	var txnID uuid.UUID
+	if err = tx.QueryRowContext(ctx, `INSERT INTO transactions (
+		wallet_id, transaction_ref, provider_ref, type, direction, amount, fee_amount,
+		narration, status, channel, initiated_by, pocket_id
+	) VALUES ($1,$2,$3,'CREDIT','IN',$4,0,$5,'SUCCESS','API','system:interest',$6)
+	RETURNING id`,
+		x.WalletID, x.TransactionRef, optStr(providerRef), x.Amount, narration, x.PocketID,
+	).Scan(&txnID); err != nil {
+		return err
+	}
+	var ledgerID uuid.UUID
+	if err = tx.QueryRowContext(ctx, `SELECT post_ledger_entry($1, $2, 'CREDIT'::ledger_entry_type, $3, 'NGN', $4)`,
+		txnID, x.WalletID, x.Amount, narration).Scan(&ledgerID); err != nil {
+		return fmt.Errorf("post_ledger_entry: %w", err)
+	}
+	if pocketStatus == PocketStatusActive {
+		if err = postPocketTransfer(ctx, tx, txnID, &PocketTransferParams{
+			PocketID: x.PocketID, ToPocket: true, Amount: x.Amount, Narration: narration,
+		}); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE savings_pockets SET interest_paid = interest_paid + $2 WHERE id = $1`,
+		x.PocketID, x.Amount); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE pocket_interest_payouts SET status = 'PAID', provider_ref = $2, transaction_id = $3,
+		paid_at = NOW() WHERE id = $1`, x.ID, optStr(providerRef), txnID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
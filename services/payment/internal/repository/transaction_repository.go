@@ -5,12 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
 	"github.com/google/uuid"
 )
 
+// queryRower is a *sql.DB or *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // TransactionRepository persists transactions and ledger entries.
 type TransactionRepository struct {
 	db     *sql.DB
@@ -37,10 +43,11 @@ type CreateTransferParams struct {
 	IdempotencyKey        string
 	InitiatedBy           string
 	PsbRequestJSON         []byte
+	Guard                 *SpendGuard // optional; re-checks the spending limits under the wallet lock
 }
 
-// CreateTransfer inserts a PENDING transaction row. Encrypts beneficiary/sender/psb_request. Returns transaction ID.
-func (r *TransactionRepository) CreateTransfer(ctx context.Context, p *CreateTransferParams) (uuid.UUID, error) {
+// createTransfer inserts a PENDING transaction row through q. Encrypts beneficiary/sender/psb_request. Returns transaction ID.
+func (r *TransactionRepository) createTransfer(ctx context.Context, q queryRower, p *CreateTransferParams) (uuid.UUID, error) {
 	if r.encKey == "" {
 		return uuid.Nil, ErrEncryptionKeyMissing
 	}
@@ -81,7 +88,7 @@ func (r *TransactionRepository) CreateTransfer(ctx context.Context, p *CreateTra
 		$7,$8,$9,$10,$11,$12,$13,$14,$15)
 	RETURNING id`
 	var id uuid.UUID
-	err = r.db.QueryRowContext(ctx, query,
+	err = q.QueryRowContext(ctx, query,
 		p.WalletID, p.TransactionRef, p.Amount, p.FeeAmount, feeAccount, p.Narration,
 		encBeneficiaryName, p.BeneficiaryBank, encBeneficiaryAcct, beneficiaryAcctHash,
 		encSenderAccount, senderAccountHash,
@@ -103,6 +110,7 @@ type CreateInternalDebitCreditParams struct {
 	Narration      string
 	InitiatedBy    string
 	ProviderRef    string // optional; 9PSB WaaS reference from debit/credit response
+	IdempotencyKey string // optional; unique per wallet
 }
 
 // CreateInternalDebitCredit inserts a SUCCESS transaction row for internal debit/credit. No provider or beneficiary fields.
@@ -137,11 +145,11 @@ func (r *TransactionRepository) CreateInternalDebitCreditAndPostLedger(ctx conte
 	}()
 	insertQuery := `INSERT INTO transactions (
 		wallet_id, transaction_ref, type, direction, amount, fee_amount,
-		narration, status, channel, initiated_by, provider_ref
-	) VALUES ($1,$2,$3::txn_type,$4::txn_direction,$5,0,$6,'SUCCESS','API',$7,$8)
+		narration, status, channel, initiated_by, provider_ref, idempotency_key
+	) VALUES ($1,$2,$3::txn_type,$4::txn_direction,$5,0,$6,'SUCCESS','API',$7,$8,$9)
 	RETURNING id`
 	if err = tx.QueryRowContext(ctx, insertQuery,
-		p.WalletID, p.TransactionRef, p.Type, p.Direction, p.Amount, p.Narration, optStr(p.InitiatedBy), optStr(p.ProviderRef),
+		p.WalletID, p.TransactionRef, p.Type, p.Direction, p.Amount, p.Narration, optStr(p.InitiatedBy), optStr(p.ProviderRef), optStr(p.IdempotencyKey),
 	).Scan(&txnID); err != nil {
 		return uuid.Nil, err
 	}
@@ -157,6 +165,55 @@ func (r *TransactionRepository) CreateInternalDebitCreditAndPostLedger(ctx conte
 	return txnID, nil
 }
 
+// CreateInboundCreditParams are inputs for recording a credit pushed by 9PSB (inbound bank transfer into a wallet).
+type CreateInboundCreditParams struct {
+	WalletID       uuid.UUID
+	TransactionRef string
+	ProviderRef    string // 9PSB session ID
+	Amount         float64
+	Narration      string
+	SenderAccount  string // optional
+}
+
+// CreateInboundCreditAndPostLedger records a SUCCESS CREDIT (channel WEBHOOK) and posts the CREDIT ledger entry in one DB transaction.
+func (r *TransactionRepository) CreateInboundCreditAndPostLedger(ctx context.Context, p *CreateInboundCreditParams) (txnID uuid.UUID, err error) {
+	var encSender []byte
+	var senderHash interface{}
+	if p.SenderAccount != "" && r.encKey != "" {
+		if encSender, err = crypto.Encrypt([]byte(p.SenderAccount), r.encKey); err != nil {
+			return uuid.Nil, err
+		}
+		senderHash = crypto.FieldHash(p.SenderAccount)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if err = tx.QueryRowContext(ctx, `INSERT INTO transactions (
+		wallet_id, transaction_ref, provider_ref, type, direction, amount, fee_amount,
+		narration, status, channel, enc_sender_account, sender_account_hash
+	) VALUES ($1,$2,$3,'CREDIT','IN',$4,0,$5,'SUCCESS','WEBHOOK',$6,$7)
+	RETURNING id`,
+		p.WalletID, p.TransactionRef, optStr(p.ProviderRef), p.Amount, p.Narration, encSender, senderHash,
+	).Scan(&txnID); err != nil {
+		return uuid.Nil, err
+	}
+	var _ledgerID uuid.UUID
+	if err = tx.QueryRowContext(ctx, `SELECT post_ledger_entry($1, $2, 'CREDIT'::ledger_entry_type, $3, 'NGN', $4)`,
+		txnID, p.WalletID, p.Amount, optStr(p.Narration)).Scan(&_ledgerID); err != nil {
+		return uuid.Nil, fmt.Errorf("post_ledger_entry: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return txnID, nil
+}
+
 // UpdateTransferAfterAPI updates status, provider_ref, enc_psb_response, psb_response_code after 9PSB call.
 func (r *TransactionRepository) UpdateTransferAfterAPI(ctx context.Context, txnID uuid.UUID, status string, providerRef string, psbResponseJSON []byte, responseCode string) error {
 	var encPsbResponse []byte
@@ -210,12 +267,14 @@ func (r *TransactionRepository) GetByRef(ctx context.Context, transactionRef str
 	return id, status, nil
 }
 
-// GetByIdempotencyKey returns transaction id and status if a row exists with the given idempotency key.
-func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (id uuid.UUID, status string, err error) {
+// GetByIdempotencyKey returns transaction id and status if the wallet has a row with the given idempotency key.
+// Keys are unique per wallet only.
+func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, walletID uuid.UUID, idempotencyKey string) (id uuid.UUID, status string, err error) {
 	if idempotencyKey == "" {
 		return uuid.Nil, "", nil
 	}
-	err = r.db.QueryRowContext(ctx, `SELECT id, status FROM transactions WHERE idempotency_key = $1`, idempotencyKey).Scan(&id, &status)
+	err = r.db.QueryRowContext(ctx, `SELECT id, status FROM transactions WHERE wallet_id = $1 AND idempotency_key = $2`,
+		walletID, idempotencyKey).Scan(&id, &status)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return uuid.Nil, "", nil
@@ -225,6 +284,41 @@ func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, idempot
 	return id, status, nil
 }
 
+// IdempotentTransfer is the transaction recorded on a wallet under an idempotency key, with what it paid and to whom.
+type IdempotentTransfer struct {
+	ID                  uuid.UUID
+	Status              string
+	Type                string
+	Amount              float64
+	BeneficiaryBank     string
+	BeneficiaryAcctHash string
+}
+
+// SameTransfer reports whether t is an outbound transfer of amount to account at bank, i.e. whether a request under the
+// same idempotency key is a retry of it.
+func (t *IdempotentTransfer) SameTransfer(amount float64, bank, account string) bool {
+	return t.Type == "OUTBOUND_TRANSFER" && math.Abs(t.Amount-amount) < 0.005 && t.BeneficiaryBank == bank &&
+		t.BeneficiaryAcctHash == crypto.FieldHash(account)
+}
+
+// GetTransferByIdempotencyKey returns the wallet's transaction recorded under idempotencyKey, or nil if there is none.
+func (r *TransactionRepository) GetTransferByIdempotencyKey(ctx context.Context, walletID uuid.UUID, idempotencyKey string) (*IdempotentTransfer, error) {
+	if idempotencyKey == "" {
+		return nil, nil
+	}
+	var t IdempotentTransfer
+	err := r.db.QueryRowContext(ctx, `SELECT id, status, type, amount, COALESCE(beneficiary_bank, ''), COALESCE(beneficiary_acct_hash, '')
+		FROM transactions WHERE wallet_id = $1 AND idempotency_key = $2`, walletID, idempotencyKey).
+		Scan(&t.ID, &t.Status, &t.Type, &t.Amount, &t.BeneficiaryBank, &t.BeneficiaryAcctHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
 // GetRefAndProviderRefByID returns transaction_ref and provider_ref for the given transaction id.
 func (r *TransactionRepository) GetRefAndProviderRefByID(ctx context.Context, txnID uuid.UUID) (transactionRef, providerRef string, err error) {
 	err = r.db.QueryRowContext(ctx, `SELECT transaction_ref, COALESCE(provider_ref, '') FROM transactions WHERE id = $1`, txnID).Scan(&transactionRef, &providerRef)
@@ -250,6 +344,13 @@ type TransactionHistoryRow struct {
 	BeneficiaryBank  string
 	BeneficiaryName  string
 	CreatedAt        time.Time
+	// Populated by single-transaction lookups (GetByRefAndWalletID, GetHistoryRowByRef) only.
+	ProviderRef        string // 9PSB session ID
+	BeneficiaryAccount string
+	SenderAccount      string
+	SenderName         string
+	DisputeRef         string // latest dispute on this transaction (empty if never disputed)
+	DisputeStatus      string
 }
 
 // ListByWalletID returns transactions for the given wallet, newest first. limit/offset for pagination. Decrypts beneficiary name.
@@ -300,58 +401,170 @@ func (r *TransactionRepository) ListByWalletID(ctx context.Context, walletID uui
 	return list, rows.Err()
 }
 
-// GetByRefAndWalletID returns one transaction by transaction_ref and wallet_id, or nil if not found. Decrypts beneficiary name.
+// GetByRefAndWalletID returns one transaction by transaction_ref and wallet_id, or nil if not found. Decrypts beneficiary and sender fields.
 func (r *TransactionRepository) GetByRefAndWalletID(ctx context.Context, transactionRef string, walletID uuid.UUID) (*TransactionHistoryRow, error) {
-	query := `SELECT transaction_ref, type::text, direction::text, amount, fee_amount, narration, status::text, channel::text,
-		COALESCE(beneficiary_bank, ''), enc_beneficiary_name, created_at
-		FROM transactions WHERE transaction_ref = $1 AND wallet_id = $2`
-	var ref, txnType, direction, narration, status, channel, beneficiaryBank string
-	var amount, feeAmount float64
-	var encBeneficiaryName []byte
-	var createdAt time.Time
-	err := r.db.QueryRowContext(ctx, query, transactionRef, walletID).Scan(&ref, &txnType, &direction, &amount, &feeAmount, &narration, &status, &channel, &beneficiaryBank, &encBeneficiaryName, &createdAt)
+	return r.getHistoryRow(ctx, `t.transaction_ref = $1 AND t.wallet_id = $2`, transactionRef, walletID)
+}
+
+// GetHistoryRowByRef returns one transaction by transaction_ref regardless of wallet, or nil if not found.
+// Used by public receipt verification, which is authorised by the signed code rather than by wallet ownership.
+func (r *TransactionRepository) GetHistoryRowByRef(ctx context.Context, transactionRef string) (*TransactionHistoryRow, error) {
+	return r.getHistoryRow(ctx, `t.transaction_ref = $1`, transactionRef)
+}
+
+func (r *TransactionRepository) getHistoryRow(ctx context.Context, where string, args ...interface{}) (*TransactionHistoryRow, error) {
+	query := `SELECT t.transaction_ref, t.type::text, t.direction::text, t.amount, t.fee_amount, t.narration, t.status::text, t.channel::text,
+		COALESCE(t.beneficiary_bank, ''), t.enc_beneficiary_name, t.enc_beneficiary_acct, t.enc_sender_account, w.enc_full_name, w.enc_account_number,
+		COALESCE(t.provider_ref, ''), t.created_at,
+		COALESCE(d.dispute_ref, ''), COALESCE(d.status::text, '')
+		FROM transactions t
+		JOIN wallets w ON w.id = t.wallet_id
+		LEFT JOIN LATERAL (
+			SELECT dispute_ref, status FROM transaction_disputes
+			WHERE transaction_id = t.id ORDER BY created_at DESC LIMIT 1
+		) d ON TRUE
+		WHERE ` + where
+	var row TransactionHistoryRow
+	var encBeneficiaryName, encBeneficiaryAcct, encSenderAccount, encWalletName, encWalletAccount []byte
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&row.TransactionRef, &row.Type, &row.Direction, &row.Amount, &row.FeeAmount, &row.Narration, &row.Status, &row.Channel,
+		&row.BeneficiaryBank, &encBeneficiaryName, &encBeneficiaryAcct, &encSenderAccount, &encWalletName, &encWalletAccount,
+		&row.ProviderRef, &row.CreatedAt, &row.DisputeRef, &row.DisputeStatus)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	beneficiaryName := ""
-	if len(encBeneficiaryName) > 0 && r.encKey != "" {
-		if dec, err := crypto.Decrypt(encBeneficiaryName, r.encKey); err == nil {
-			beneficiaryName = string(dec)
+	row.BeneficiaryName = r.decryptStr(encBeneficiaryName)
+	row.BeneficiaryAccount = r.decryptStr(encBeneficiaryAcct)
+	row.SenderAccount = r.decryptStr(encSenderAccount)
+	// The wallet owner is the sender of outbound transactions and the beneficiary of inbound ones.
+	walletName, walletAccount := r.decryptStr(encWalletName), r.decryptStr(encWalletAccount)
+	if row.Direction == "OUT" {
+		row.SenderName = walletName
+		if row.SenderAccount == "" {
+			row.SenderAccount = walletAccount
+		}
+	} else {
+		if row.BeneficiaryName == "" {
+			row.BeneficiaryName = walletName
+		}
+		if row.BeneficiaryAccount == "" {
+			row.BeneficiaryAccount = walletAccount
 		}
 	}
-	return &TransactionHistoryRow{
-		TransactionRef:  ref,
-		Type:            txnType,
-		Direction:       direction,
-		Amount:          amount,
-		FeeAmount:       feeAmount,
-		Narration:       narration,
-		Status:          status,
-		Channel:         channel,
-		BeneficiaryBank: beneficiaryBank,
-		BeneficiaryName: beneficiaryName,
-		CreatedAt:       createdAt,
-	}, nil
-}
-
-// SumSuccessfulOutboundAmountByWalletAndWindow returns the sum of amount for SUCCESS OUT transactions
-// for the given wallet in the time window (e.g. today for daily, this month for monthly).
-func (r *TransactionRepository) SumSuccessfulOutboundAmountByWalletAndWindow(ctx context.Context, walletID uuid.UUID, since, until string) (float64, error) {
-	var sum sql.NullFloat64
+	return &row, nil
+}
+
+func (r *TransactionRepository) decryptStr(enc []byte) string {
+	if len(enc) == 0 || r.encKey == "" {
+		return ""
+	}
+	dec, err := crypto.Decrypt(enc, r.encKey)
+	if err != nil {
+		return ""
+	}
+	return string(dec)
+}
+
+// DisputableTransaction is the minimal transaction view needed to open a dispute.
+type DisputableTransaction struct {
+	ID        uuid.UUID
+	Status    string
+	Direction string
+	Amount    float64
+	FeeAmount float64
+}
+
+// GetDisputableByRefAndWalletID returns the transaction id, status, direction and amounts by transaction_ref and wallet_id, or nil if not found.
+func (r *TransactionRepository) GetDisputableByRefAndWalletID(ctx context.Context, transactionRef string, walletID uuid.UUID) (*DisputableTransaction, error) {
+	var t DisputableTransaction
 	err := r.db.QueryRowContext(ctx,
-		`SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE wallet_id = $1 AND type = 'OUTBOUND_TRANSFER' AND direction = 'OUT' AND status = 'SUCCESS' AND created_at >= $2 AND created_at < $3`,
-		walletID, since, until,
-	).Scan(&sum)
+		`SELECT id, status::text, direction::text, amount, fee_amount FROM transactions WHERE transaction_ref = $1 AND wallet_id = $2`,
+		transactionRef, walletID,
+	).Scan(&t.ID, &t.Status, &t.Direction, &t.Amount, &t.FeeAmount)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	if sum.Valid {
-		return sum.Float64, nil
+	return &t, nil
+}
+
+// OutboundSpend is money sent from a wallet since the start of the day and of the month, by limits channel.
+type OutboundSpend struct {
+	DailyTransfers float64
+	DailyBills     float64
+	Monthly        float64 // transfers and bills
+}
+
+// SumOutboundSpend totals bank transfers (OUTBOUND_TRANSFER) and bill payments (DEBIT rows owned by bill_payments)
+// since dayStart and monthStart. In-flight payments (PENDING, REQUIRES_REQUERY) count; pocket moves and admin debits
+// do not. The total is only a snapshot: inserts guarded by a SpendGuard re-check it under the wallet lock.
+func (r *TransactionRepository) SumOutboundSpend(ctx context.Context, walletID uuid.UUID, dayStart, monthStart time.Time) (*OutboundSpend, error) {
+	return sumOutboundSpend(ctx, r.db, walletID, dayStart, monthStart)
+}
+
+func sumOutboundSpend(ctx context.Context, q queryRower, walletID uuid.UUID, dayStart, monthStart time.Time) (*OutboundSpend, error) {
+	var out OutboundSpend
+	err := q.QueryRowContext(ctx, `
+		WITH spend AS (
+			SELECT t.amount, t.created_at,
+				CASE WHEN t.type = 'OUTBOUND_TRANSFER' THEN 'transfer' ELSE 'bills' END AS channel
+			FROM transactions t
+			WHERE t.wallet_id = $1 AND t.direction = 'OUT' AND t.status IN ('PENDING', 'SUCCESS', 'REQUIRES_REQUERY')
+				AND t.created_at >= $3
+				AND (t.type = 'OUTBOUND_TRANSFER' OR EXISTS (SELECT 1 FROM bill_payments b WHERE b.transaction_id = t.id))
+		)
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE channel = 'transfer' AND created_at >= $2), 0),
+			COALESCE(SUM(amount) FILTER (WHERE channel = 'bills' AND created_at >= $2), 0),
+			COALESCE(SUM(amount), 0)
+		FROM spend`,
+		walletID, dayStart, monthStart,
+	).Scan(&out.DailyTransfers, &out.DailyBills, &out.Monthly)
+	if err != nil {
+		return nil, err
 	}
-	return 0, nil
+	return &out, nil
+}
+
+// SpendGuard re-checks an outbound payment against the wallet's spend inside the transaction that inserts it. The
+// wallet row is locked first, so concurrent payments from one wallet are checked one after the other and cannot both
+// use the same headroom. An error from Check aborts the insert and is returned as is.
+type SpendGuard struct {
+	DayStart   time.Time
+	MonthStart time.Time
+	Check      func(*OutboundSpend) error
+}
+
+// lockWallet locks the wallet row until tx ends.
+func lockWallet(ctx context.Context, tx *sql.Tx, walletID uuid.UUID) error {
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM wallets WHERE id = $1 FOR UPDATE`, walletID).Scan(&id); err != nil {
+		return fmt.Errorf("lock wallet: %w", err)
+	}
+	return nil
+}
+
+// check runs the guard on the wallet's spend as tx sees it; a nil guard allows everything.
+func (g *SpendGuard) check(ctx context.Context, tx *sql.Tx, walletID uuid.UUID) error {
+	if g == nil || g.Check == nil {
+		return nil
+	}
+	spend, err := sumOutboundSpend(ctx, tx, walletID, g.DayStart, g.MonthStart)
+	if err != nil {
+		return err
+	}
+	return g.Check(spend)
+}
+
+// CountInFlight returns the wallet's transactions whose outcome is not yet final (PENDING, REQUIRES_REQUERY).
+func (r *TransactionRepository) CountInFlight(ctx context.Context, walletID uuid.UUID) (int64, error) {
+	var n int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM transactions WHERE wallet_id = $1 AND status IN ('PENDING', 'REQUIRES_REQUERY')`, walletID).Scan(&n)
+	return n, err
 }
 
 func optStr(s string) interface{} {
@@ -365,19 +578,39 @@ func optStr(s string) interface{} {
 var ErrIdempotencyConflict = errors.New("idempotency key already used")
 
 // CreateTransferWithIdempotency creates a PENDING transfer only if idempotencyKey is new; otherwise returns existing txn id and status.
+// The wallet row stays locked from the key lookup to the insert, and p.Guard (if any) is checked in between.
 func (r *TransactionRepository) CreateTransferWithIdempotency(ctx context.Context, p *CreateTransferParams) (txnID uuid.UUID, existingStatus string, created bool, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, "", false, err
+	}
+	defer func() {
+		if err != nil || !created {
+			_ = tx.Rollback()
+		}
+	}()
+	if err = lockWallet(ctx, tx, p.WalletID); err != nil {
+		return uuid.Nil, "", false, err
+	}
 	if p.IdempotencyKey != "" {
-		existingID, existingStatus, err := r.GetByIdempotencyKey(ctx, p.IdempotencyKey)
-		if err != nil {
-			return uuid.Nil, "", false, err
+		var status string
+		err = tx.QueryRowContext(ctx, `SELECT id, status FROM transactions WHERE wallet_id = $1 AND idempotency_key = $2`,
+			p.WalletID, p.IdempotencyKey).Scan(&txnID, &status)
+		if err == nil {
+			return txnID, status, false, nil
 		}
-		if existingID != uuid.Nil {
-			return existingID, existingStatus, false, nil
+		if !errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, "", false, err
 		}
 	}
-	id, err := r.CreateTransfer(ctx, p)
-	if err != nil {
+	if err = p.Guard.check(ctx, tx, p.WalletID); err != nil {
+		return uuid.Nil, "", false, err
+	}
+	if txnID, err = r.createTransfer(ctx, tx, p); err != nil {
+		return uuid.Nil, "", false, err
+	}
+	if err = tx.Commit(); err != nil {
 		return uuid.Nil, "", false, err
 	}
-	return id, "", true, nil
+	return txnID, "", true, nil
 }
@@ -97,6 +97,36 @@ func (r *WalletRepository) GetActiveByUserID(ctx context.Context, userID uuid.UU
 	}, nil
 }
 
+// WalletByAccount is an active wallet resolved from its account number (decrypted name).
+type WalletByAccount struct {
+	WalletID      uuid.UUID
+	UserID        uuid.UUID
+	AccountNumber string
+	FullName      string
+}
+
+// GetActiveByAccountNumber returns the ACTIVE wallet with the given account number (looked up by account_number_hash), or nil if none.
+func (r *WalletRepository) GetActiveByAccountNumber(ctx context.Context, accountNumber string) (*WalletByAccount, error) {
+	if r.encKey == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+	var w WalletByAccount
+	var encFullName []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, user_id, enc_full_name FROM wallets WHERE account_number_hash = $1 AND status = 'ACTIVE' LIMIT 1`,
+		crypto.FieldHash(accountNumber),
+	).Scan(&w.WalletID, &w.UserID, &encFullName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	w.AccountNumber = accountNumber
+	w.FullName, _ = r.decrypt(encFullName)
+	return &w, nil
+}
+
 // WalletForStatusChange is the wallet row needed to change status (id, account_number). User has at most one non-CLOSED wallet.
 type WalletForStatusChange struct {
 	WalletID      uuid.UUID
@@ -244,6 +274,26 @@ func (r *WalletRepository) ListForAdmin(ctx context.Context, limit, offset int)
 		return nil, err
 	}
 	defer rows.Close()
+	return r.collectAdminRows(rows)
+}
+
+// ListByUserID returns every wallet the user has held, in any status, decrypted. Used by data-subject export and erasure.
+func (r *WalletRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]WalletAdminRow, error) {
+	if r.encKey == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+	query := `SELECT id, user_id, enc_account_number, enc_customer_id, order_ref, enc_full_name, enc_phone, enc_email,
+		mfb_code, tier, status, ledger_balance, available_balance, provider, created_at, updated_at
+		FROM wallets WHERE user_id = $1 ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.collectAdminRows(rows)
+}
+
+func (r *WalletRepository) collectAdminRows(rows *sql.Rows) ([]WalletAdminRow, error) {
 	var list []WalletAdminRow
 	for rows.Next() {
 		var id, userID string
@@ -294,6 +344,44 @@ func (r *WalletRepository) UpdateTier(ctx context.Context, walletID uuid.UUID, t
 	return err
 }
 
+// UpdateContact replaces the phone number and email kept on the user's wallets (encrypted, with lookup hashes) after
+// the user changed them in the user service. Returns the number of wallets updated.
+func (r *WalletRepository) UpdateContact(ctx context.Context, userID uuid.UUID, phone, email string) (int64, error) {
+	if r.encKey == "" {
+		return 0, ErrEncryptionKeyMissing
+	}
+	encPhone, err := crypto.Encrypt([]byte(phone), r.encKey)
+	if err != nil {
+		return 0, err
+	}
+	encEmail, err := crypto.Encrypt([]byte(email), r.encKey)
+	if err != nil {
+		return 0, err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE wallets SET enc_phone = $2, phone_hash = $3, enc_email = $4, email_hash = $5 WHERE user_id = $1`,
+		userID, encPhone, crypto.FieldHash(phone), encEmail, crypto.FieldHash(email))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// EraseContact replaces the phone number and email on a wallet for account deletion. The phone is stored as an empty
+// value with a hash no real number can produce (phone_hash is NOT NULL); the email is removed. The account holder's
+// name stays: it is part of the financial record and matches what 9PSB holds.
+func (r *WalletRepository) EraseContact(ctx context.Context, walletID uuid.UUID) error {
+	if r.encKey == "" {
+		return ErrEncryptionKeyMissing
+	}
+	encPhone, err := crypto.Encrypt([]byte(""), r.encKey)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `UPDATE wallets SET enc_phone = $2, phone_hash = $3, enc_email = NULL, email_hash = NULL WHERE id = $1`,
+		walletID, encPhone, crypto.FieldHash("erased:"+walletID.String()))
+	return err
+}
+
 func (r *WalletRepository) decrypt(b []byte) (string, error) {
 	if len(b) == 0 {
 		return "", nil
@@ -21,8 +21,10 @@ func NewWebhookEventsRepository(db *sql.DB, encKey string) *WebhookEventsReposit
 }
 
 const eventTypeWalletUpgrade = "WALLET_UPGRADE"
+const eventTypeTransfer = "TRANSFER"
 const procStatusPending = "PENDING"
 const procStatusProcessed = "PROCESSED"
+const procStatusFailed = "FAILED"
 
 // InsertWebhookEvent inserts a webhook event row. eventStatus e.g. "APPROVED" or "DECLINED" for WALLET_UPGRADE. rawPayload is encrypted before storage.
 func (r *WebhookEventsRepository) InsertWebhookEvent(ctx context.Context, eventType, eventStatus, providerRef, accountNumberHash string, rawPayload []byte) (id uuid.UUID, err error) {
@@ -52,3 +54,36 @@ func (r *WebhookEventsRepository) MarkProcessed(ctx context.Context, id uuid.UUI
 	_, err := r.db.ExecContext(ctx, `UPDATE webhook_events SET processing_status = $1::webhook_proc_status, processed_at = $2 WHERE id = $3`, procStatusProcessed, now, id)
 	return err
 }
+
+// InsertTransferEvent logs an inbound transfer (credit) notification. The 9PSB session ID is the deduplication key:
+// duplicate is true (and id uuid.Nil) when an event with the same providerRef was already received.
+func (r *WebhookEventsRepository) InsertTransferEvent(ctx context.Context, eventStatus, providerRef, accountNumberHash string, amount float64, rawPayload []byte) (id uuid.UUID, duplicate bool, err error) {
+	var encPayload []byte
+	if len(rawPayload) > 0 && r.encKey != "" {
+		encPayload, err = crypto.Encrypt(rawPayload, r.encKey)
+		if err != nil {
+			return uuid.Nil, false, err
+		}
+	}
+	id = uuid.New()
+	now := time.Now()
+	res, err := r.db.ExecContext(ctx, `INSERT INTO webhook_events (
+		id, event_type, event_status, provider_ref, account_number_hash, amount, enc_raw_payload, processing_status, received_at, created_at
+	) VALUES ($1,$2::webhook_event_type,$3,$4,$5,$6,$7,$8::webhook_proc_status,$9,$10)
+	ON CONFLICT (provider_ref) WHERE provider_ref IS NOT NULL DO NOTHING`,
+		id, eventTypeTransfer, eventStatus, providerRef, nullStr(accountNumberHash), amount, encPayload, procStatusPending, now, now)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return uuid.Nil, true, nil
+	}
+	return id, false, nil
+}
+
+// MarkFailed sets processing_status = FAILED with the reason so the event can be reprocessed.
+func (r *WebhookEventsRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE webhook_events SET processing_status = $1::webhook_proc_status, failure_reason = $2, processed_at = NOW() WHERE id = $3`,
+		procStatusFailed, reason, id)
+	return err
+}
@@ -15,25 +15,81 @@ func Setup(ctrl *controller.PaymentController) *gin.Engine {
 	// 9PSB inbound webhook (no auth). Event=wallet-upgrade: finalize upgrade request and update wallet tier on APPROVED.
 	r.POST("/webhooks/9psb", ctrl.Handle9PSBWebhook)
 
+	// Public (no auth). Checks the verification code printed on a receipt; returns masked transaction details.
+	r.GET("/receipts/verify/:code", ctrl.VerifyReceipt)
+	// Public (no auth). Pay link: requester name, account and bank for funding by transfer, amount and status.
+	r.GET("/pay-links/:request_ref", ctrl.ResolvePayLink)
+
 	r.POST("/wallets", ctrl.OpenWallet)
+
+	// Everything below requires a user access token (checked once here, including the revocation deny-list).
+	user := r.Group("", ctrl.RequireUser())
 	// User-authenticated (JWT). Returns wallet details from DB (account number, account name, status).
-	r.GET("/wallet", ctrl.GetWallet)
+	user.GET("/wallet", ctrl.GetWallet)
 	// User-authenticated (JWT). Returns live balance from 9PSB wallet_enquiry.
-	r.GET("/wallet/balance", ctrl.GetBalance)
+	user.GET("/wallet/balance", ctrl.GetBalance)
+	// User-authenticated (JWT). Tier limits: single transaction, daily, per channel, maximum balance, and today's usage.
+	user.GET("/wallet/limits", ctrl.GetWalletLimits)
 	// User-authenticated (JWT). 9PSB WaaS transaction history. Query: from_date, to_date (YYYY-MM-DD, max 31 days), limit (default 20).
-	r.GET("/wallet/waas/transactions", ctrl.GetWaasTransactions)
+	user.GET("/wallet/waas/transactions", ctrl.GetWaasTransactions)
 	// User-authenticated (JWT). 9PSB WaaS wallet status.
-	r.GET("/wallet/waas/status", ctrl.GetWaasWalletStatus)
+	user.GET("/wallet/waas/status", ctrl.GetWaasWalletStatus)
 	// User-authenticated (JWT). Returns wallet upgrade status (latest upgrade request if any).
-	r.GET("/wallet/upgrade-status", ctrl.GetWalletUpgradeStatus)
+	user.GET("/wallet/upgrade-status", ctrl.GetWalletUpgradeStatus)
 	// User-authenticated (JWT). Returns wallet transaction history (newest first). Query: limit (default 20, max 100), offset.
-	r.GET("/wallet/transactions", ctrl.GetWalletTransactions)
+	user.GET("/wallet/transactions", ctrl.GetWalletTransactions)
 	// User-authenticated (JWT). Returns a single transaction by transaction_ref (path param). 404 if not found or not owned.
-	r.GET("/wallet/transactions/:transaction_ref", ctrl.GetTransactionDetail)
+	user.GET("/wallet/transactions/:transaction_ref", ctrl.GetTransactionDetail)
+	// User-authenticated (JWT). Branded receipt download. Query: format (pdf default, or png).
+	user.GET("/wallet/transactions/:transaction_ref/receipt", ctrl.GetTransactionReceipt)
+	// User-authenticated (JWT). Open a dispute on a transaction. Body: reason (NOT_RECEIVED|WRONG_AMOUNT|DUPLICATE|UNAUTHORIZED|OTHER), description.
+	user.POST("/wallet/transactions/:transaction_ref/disputes", ctrl.OpenDispute)
+	// User-authenticated (JWT). Disputes raised by the user (newest first). Query: limit, offset.
+	user.GET("/wallet/disputes", ctrl.ListDisputes)
+	// User-authenticated (JWT). One dispute with user-visible notes and attachments.
+	user.GET("/wallet/disputes/:dispute_ref", ctrl.GetDispute)
+	// User-authenticated (JWT). Reply on a dispute; moves AWAITING_USER back to UNDER_REVIEW. Body: body.
+	user.POST("/wallet/disputes/:dispute_ref/notes", ctrl.AddDisputeNote)
+	// User-authenticated (JWT). Attach evidence already uploaded to storage. Body: file_name, content_type, url (https).
+	user.POST("/wallet/disputes/:dispute_ref/attachments", ctrl.AddDisputeAttachment)
+	// User-authenticated (JWT). Request money from a PayUp user (payer_account_number) or create an open pay link. Body: amount, note, payer_account_number, expires_in_hours.
+	user.POST("/wallet/payment-requests", ctrl.CreatePaymentRequest)
+	// User-authenticated (JWT). Payment requests sent (role=sent, default) or received (role=received). Query: status, limit, offset.
+	user.GET("/wallet/payment-requests", ctrl.ListPaymentRequests)
+	// User-authenticated (JWT). One payment request the user sent or was asked to pay.
+	user.GET("/wallet/payment-requests/:request_ref", ctrl.GetPaymentRequest)
+	// User-authenticated (JWT); X-Idempotency-Key required. Pay a request from the wallet. Body: pin.
+	user.POST("/wallet/payment-requests/:request_ref/pay", ctrl.PayPaymentRequest)
+	// User-authenticated (JWT). Decline a request addressed to the user. Body: reason (optional).
+	user.POST("/wallet/payment-requests/:request_ref/decline", ctrl.DeclinePaymentRequest)
+	// User-authenticated (JWT). Create a savings pocket. Body: name, goal_amount, locked_until, autosave_inbound_percent, autosave_roundup_to.
+	user.POST("/wallet/pockets", ctrl.CreatePocket)
+	// User-authenticated (JWT). List savings pockets with the main/savings split. Query: include_closed.
+	user.GET("/wallet/pockets", ctrl.ListPockets)
+	// User-authenticated (JWT). One savings pocket.
+	user.GET("/wallet/pockets/:pocket_ref", ctrl.GetPocket)
+	// User-authenticated (JWT). Change pocket name, goal, lock (extend only) or auto-save rules.
+	user.PATCH("/wallet/pockets/:pocket_ref", ctrl.UpdatePocket)
+	// User-authenticated (JWT); X-Idempotency-Key required. Move money from the main balance into the pocket. Body: amount.
+	user.POST("/wallet/pockets/:pocket_ref/deposit", ctrl.DepositToPocket)
+	// User-authenticated (JWT); X-Idempotency-Key required. Move money back to the main balance (refused while locked). Body: amount.
+	user.POST("/wallet/pockets/:pocket_ref/withdraw", ctrl.WithdrawFromPocket)
+	// User-authenticated (JWT). Return the balance to the main balance and close the pocket (refused while locked).
+	user.POST("/wallet/pockets/:pocket_ref/close", ctrl.ClosePocket)
+	// User-authenticated (JWT). Biller catalogue for airtime, data, electricity and TV. Query: category.
+	user.GET("/wallet/bills/billers", ctrl.GetBillers)
+	// User-authenticated (JWT). Look up a meter/smartcard/phone before paying. Body: biller_code, item_code, customer_id.
+	user.POST("/wallet/bills/validate", ctrl.ValidateBillCustomer)
+	// User-authenticated (JWT); X-Idempotency-Key required. Pay a bill from the wallet; 202 while the vend is pending.
+	user.POST("/wallet/bills/pay", ctrl.PayBill)
+	// User-authenticated (JWT). List bill payments. Query: category, limit, offset.
+	user.GET("/wallet/bills", ctrl.ListBillPayments)
+	// User-authenticated (JWT). One bill payment with status and electricity token.
+	user.GET("/wallet/bills/:bill_ref", ctrl.GetBillPayment)
 	// Resolve beneficiary name: 9PSB (120001) = wallet_enquiry, other banks = other_banks_enquiry. Body: bank_code, account_number.
-	r.POST("/wallet/beneficiary-enquiry", ctrl.BeneficiaryEnquiry)
+	user.POST("/wallet/beneficiary-enquiry", ctrl.BeneficiaryEnquiry)
 	// User-authenticated (JWT); optional X-Idempotency-Key. Gateway should use auth_request for /v1/payment/* or /v1/transfers.
-	r.POST("/transfers", ctrl.TransferToOtherBank)
+	user.POST("/transfers", ctrl.TransferToOtherBank)
 
 	return r
 }
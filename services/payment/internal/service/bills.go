@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/bills"
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// MaxBillRequeries is how many times the requery worker asks the aggregator about a payment before leaving it for ops.
+const MaxBillRequeries = 10
+
+var (
+	ErrBillsNotConfigured  = errors.New("bill payments not configured")
+	ErrBillPaymentNotFound = errors.New("bill payment not found")
+)
+
+// nigerianMSISDN matches a local 11-digit mobile number (e.g. 08031234567).
+var nigerianMSISDN = regexp.MustCompile(`^0[789][01]\d{8}$`)
+
+// PayBillParams are the inputs for a bill payment.
+type PayBillParams struct {
+	UserID           string
+	BillerCode       string
+	ItemCode         string
+	CustomerID       string  // phone, meter, smartcard or account number
+	Amount           float64 // ignored for fixed-price items
+	Phone            string  // where tokens are sent; defaults to the user's phone
+	DeliveryChannel  string  // "sms" (default) or "whatsapp"
+	Pin              string
+	StepUpToken      string
+	PasskeyAssertion string
+	IdempotencyKey   string
+}
+
+// BillCatalogue lists the aggregator's billers, optionally for one category.
+func (s *PaymentService) BillCatalogue(ctx context.Context, category string) ([]bills.Biller, error) {
+	if s.billsProvider == nil {
+		return nil, ErrBillsNotConfigured
+	}
+	list, err := s.billsProvider.Catalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if category == "" {
+		return list, nil
+	}
+	out := make([]bills.Biller, 0, len(list))
+	for _, b := range list {
+		if strings.EqualFold(b.Category, category) {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// existingBill returns the user's bill payment already made under idempotencyKey, or nil. A key reused for a different
+// purchase is refused with repository.ErrIdempotencyConflict rather than answered with the earlier payment.
+func (s *PaymentService) existingBill(ctx context.Context, userID uuid.UUID, idempotencyKey, billerCode, itemCode, customerID string, amount float64) (*repository.BillPaymentRow, error) {
+	if idempotencyKey == "" {
+		return nil, nil
+	}
+	b, err := s.billRepo.GetByIdempotencyKey(ctx, userID, idempotencyKey)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	if b.BillerCode != billerCode || b.ItemCode != itemCode || b.CustomerID != customerID || math.Abs(b.Amount-amount) > 0.005 {
+		return nil, repository.ErrIdempotencyConflict
+	}
+	return b, nil
+}
+
+// ValidateBillCustomer looks up a meter, smartcard or phone number with the aggregator before payment.
+func (s *PaymentService) ValidateBillCustomer(ctx context.Context, billerCode, itemCode, customerID string) (*bills.Customer, error) {
+	if s.billsProvider == nil {
+		return nil, ErrBillsNotConfigured
+	}
+	biller, err := bills.FindBiller(ctx, s.billsProvider, billerCode)
+	if err != nil {
+		return nil, err
+	}
+	customerID = strings.TrimSpace(customerID)
+	if err := validateBillCustomerID(biller, customerID); err != nil {
+		return nil, err
+	}
+	return s.billsProvider.ValidateCustomer(ctx, billerCode, itemCode, customerID)
+}
+
+// PayBill buys airtime, data, electricity or TV. The flow mirrors other wallet debits: a PENDING transaction is created,
+// the wallet is debited at 9PSB and on the ledger, then the aggregator vends. A failed vend is reversed; an unknown outcome
+// is left REQUIRES_REQUERY for RequeryBillPayments. Electricity tokens are sent by SMS or WhatsApp.
+func (s *PaymentService) PayBill(ctx context.Context, p *PayBillParams) (*repository.BillPaymentRow, error) {
+	if s.billsProvider == nil || s.billRepo == nil {
+		return nil, ErrBillsNotConfigured
+	}
+	biller, err := bills.FindBiller(ctx, s.billsProvider, p.BillerCode)
+	if err != nil {
+		return nil, err
+	}
+	item, err := biller.Item(p.ItemCode)
+	if err != nil {
+		return nil, err
+	}
+	customerID := strings.TrimSpace(p.CustomerID)
+	if err := validateBillCustomerID(biller, customerID); err != nil {
+		return nil, err
+	}
+	amount := item.Amount
+	if amount == 0 {
+		amount = p.Amount
+		if amount <= 0 {
+			return nil, fmt.Errorf("amount must be positive")
+		}
+		if (biller.MinAmount > 0 && amount < biller.MinAmount) || (biller.MaxAmount > 0 && amount > biller.MaxAmount) {
+			return nil, fmt.Errorf("amount must be between %.0f and %.0f", biller.MinAmount, biller.MaxAmount)
+		}
+	}
+	channel := strings.ToLower(nonBlank(p.DeliveryChannel, "sms"))
+	if channel != "sms" && channel != "whatsapp" {
+		return nil, fmt.Errorf("delivery_channel must be sms or whatsapp")
+	}
+
+	wallet, uid, err := s.activeWallet(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := s.existingBill(ctx, uid, p.IdempotencyKey, biller.Code, item.Code, customerID, amount); err != nil || existing != nil {
+		return existing, err
+	}
+
+	var customerName string
+	if biller.RequiresValidation {
+		cust, err := s.billsProvider.ValidateCustomer(ctx, biller.Code, item.Code, customerID)
+		if err != nil {
+			return nil, err
+		}
+		customerName = cust.Name
+	}
+	var userLimits limits.UserLimits
+	if s.userClient != nil {
+		resp, err := s.userClient.ValidateTransfer(ctx, p.UserID, amount, p.Pin, p.StepUpToken, p.PasskeyAssertion, biller.Code, customerID)
+		if err != nil {
+			return nil, fmt.Errorf("validate payment: %w", err)
+		}
+		if resp != nil && !resp.Allowed {
+			return nil, fmt.Errorf("%s", resp.Message)
+		}
+		if resp != nil {
+			userLimits = limits.UserLimits{Daily: resp.DailyTransferLimit, Monthly: resp.MonthlyTransferLimit}
+		}
+	}
+	tier := s.walletTier(ctx, p.UserID, wallet.Tier)
+	if err := s.checkSpendingLimits(ctx, wallet, tier, limits.ChannelBills, amount, userLimits); err != nil {
+		return nil, err
+	}
+	if err := s.checkSpendable(ctx, wallet, tier, amount); err != nil {
+		return nil, err
+	}
+
+	narration := truncateStr(biller.Name+" "+item.Name+" "+customerID, 255)
+	b, err := s.billRepo.CreatePending(ctx, &repository.CreateBillPaymentParams{
+		BillRef:         generateTrackingRef("BIL"),
+		UserID:          uid,
+		WalletID:        wallet.WalletID,
+		Provider:        s.billsProvider.Name(),
+		Category:        biller.Category,
+		BillerCode:      biller.Code,
+		BillerName:      biller.Name,
+		ItemCode:        item.Code,
+		ItemName:        item.Name,
+		CustomerID:      customerID,
+		CustomerName:    customerName,
+		Phone:           strings.TrimSpace(p.Phone),
+		Amount:          amount,
+		Narration:       narration,
+		DeliveryChannel: channel,
+		IdempotencyKey:  p.IdempotencyKey,
+		Guard:           s.spendGuard(tier, limits.ChannelBills, amount, userLimits),
+	})
+	if err != nil {
+		// A concurrent request with the same key won the insert; answer as its replay.
+		if existing, _ := s.existingBill(ctx, uid, p.IdempotencyKey, biller.Code, item.Code, customerID, amount); existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	// Debit: 9PSB first, then the ledger. Nothing is vended until both succeed.
+	var debitRef string
+	if s.psbProvider != nil {
+		debitRef, err = s.psbProvider.WaasDebitTransfer(ctx, wallet.AccountNumber, narration, amount, b.BillRef)
+		if err != nil {
+			_ = s.billRepo.MarkFailed(ctx, b, "wallet debit failed: "+err.Error())
+			return nil, fmt.Errorf("9PSB: %w", err)
+		}
+	}
+	if err := s.billRepo.MarkDebited(ctx, b, debitRef, narration); err != nil {
+		if s.psbProvider != nil {
+			if _, cerr := s.psbProvider.WaasCreditTransfer(ctx, wallet.AccountNumber, "Refund: "+narration, amount, "REV"+b.BillRef); cerr != nil {
+				log.Printf("payment: bill %s debited at 9PSB but ledger failed and refund failed: %v / %v", b.BillRef, err, cerr)
+			}
+		}
+		_ = s.billRepo.MarkFailed(ctx, b, "ledger debit failed")
+		if strings.Contains(err.Error(), "Insufficient balance") {
+			return nil, fmt.Errorf("insufficient balance")
+		}
+		return nil, err
+	}
+	s.auditBill(b, "bill_payment_initiated", nil)
+
+	res, err := s.billsProvider.Vend(ctx, &bills.VendRequest{
+		Reference:  b.BillRef,
+		BillerCode: biller.Code,
+		ItemCode:   item.Code,
+		CustomerID: customerID,
+		Amount:     amount,
+		Phone:      b.Phone,
+	})
+	if err != nil {
+		log.Printf("payment: vend %s: %v; will requery", b.BillRef, err)
+		_ = s.billRepo.MarkRequery(ctx, b, "")
+	} else {
+		s.completeBill(ctx, b, res)
+	}
+	return s.billRepo.GetByID(ctx, b.ID)
+}
+
+// ListMyBillPayments returns the user's bill payments, newest first.
+func (s *PaymentService) ListMyBillPayments(ctx context.Context, userID, category string, limit, offset int) ([]repository.BillPaymentRow, error) {
+	if s.billRepo == nil {
+		return nil, ErrBillsNotConfigured
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	return s.billRepo.ListByUser(ctx, uid, strings.ToUpper(category), limit, offset)
+}
+
+// GetMyBillPayment returns one of the user's bill payments (including any token).
+func (s *PaymentService) GetMyBillPayment(ctx context.Context, userID, ref string) (*repository.BillPaymentRow, error) {
+	if s.billRepo == nil {
+		return nil, ErrBillsNotConfigured
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	b, err := s.billRepo.GetByRefForUser(ctx, uid, strings.TrimSpace(ref))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, ErrBillPaymentNotFound
+	}
+	return b, nil
+}
+
+// RequeryBillPayments asks the aggregator about debited payments whose outcome is unknown and settles them: success,
+// reversal, or another requery later. An order the aggregator never received is reversed. Run periodically.
+func (s *PaymentService) RequeryBillPayments(ctx context.Context) (int, error) {
+	if s.billsProvider == nil || s.billRepo == nil {
+		return 0, nil
+	}
+	list, err := s.billRepo.ListForRequery(ctx, MaxBillRequeries, 50)
+	if err != nil {
+		return 0, err
+	}
+	settled := 0
+	for i := range list {
+		b := &list[i]
+		if err := s.billRepo.RecordRequery(ctx, b); err != nil {
+			log.Printf("payment: record requery %s: %v", b.BillRef, err)
+			continue
+		}
+		res, err := s.billsProvider.Requery(ctx, b.BillRef)
+		if errors.Is(err, bills.ErrOrderNotFound) {
+			res = &bills.VendResult{Status: bills.VendFailed, Message: "order not received by biller"}
+		} else if err != nil {
+			log.Printf("payment: requery %s: %v", b.BillRef, err)
+			continue
+		}
+		if res.Status != bills.VendPending {
+			settled++
+		}
+		s.completeBill(ctx, b, res)
+	}
+	return settled, nil
+}
+
+// completeBill applies a vend or requery outcome to a debited PENDING payment.
+func (s *PaymentService) completeBill(ctx context.Context, b *repository.BillPaymentRow, res *bills.VendResult) {
+	switch res.Status {
+	case bills.VendSuccess:
+		if err := s.billRepo.MarkSuccess(ctx, b, res.ProviderRef, res.Token, res.Units); err != nil {
+			log.Printf("payment: mark bill %s success: %v", b.BillRef, err)
+			return
+		}
+		updated, _ := s.billRepo.GetByID(ctx, b.ID)
+		if updated == nil {
+			return
+		}
+		s.auditBill(updated, "bill_payment_success", map[string]interface{}{"provider_ref": res.ProviderRef})
+		s.notifyBillSuccess(ctx, updated)
+	case bills.VendFailed:
+		s.reverseBill(ctx, b, nonBlank(res.Message, "biller declined the order"))
+	default:
+		if err := s.billRepo.MarkRequery(ctx, b, res.ProviderRef); err != nil {
+			log.Printf("payment: mark bill %s for requery: %v", b.BillRef, err)
+		}
+	}
+}
+
+// reverseBill refunds a debited payment: 9PSB credit (reference REV+bill_ref, so a retry cannot refund twice), then a
+// REVERSAL transaction and CREDIT ledger entry. If the credit fails the payment stays pending and is retried on requery.
+func (s *PaymentService) reverseBill(ctx context.Context, b *repository.BillPaymentRow, reason string) {
+	reversalRef := "REV" + b.BillRef
+	var creditRef string
+	if s.psbProvider != nil {
+		wallet, err := s.walletRepo.GetActiveByUserID(ctx, b.UserID)
+		if err != nil || wallet == nil {
+			log.Printf("payment: reverse bill %s: no active wallet: %v", b.BillRef, err)
+			_ = s.billRepo.MarkRequery(ctx, b, "")
+			return
+		}
+		creditRef, err = s.psbProvider.WaasCreditTransfer(ctx, wallet.AccountNumber, "Reversal: "+b.BillerName, b.Amount, reversalRef)
+		if err != nil && !strings.Contains(err.Error(), "Duplicate") {
+			log.Printf("payment: reverse bill %s: 9PSB credit: %v", b.BillRef, err)
+			_ = s.billRepo.MarkRequery(ctx, b, "")
+			return
+		}
+	}
+	if err := s.billRepo.Reverse(ctx, b, reversalRef, creditRef, reason); err != nil {
+		log.Printf("payment: reverse bill %s: %v", b.BillRef, err)
+		return
+	}
+	s.auditBill(b, "bill_payment_reversed", map[string]interface{}{"reason": reason, "reversal_ref": reversalRef})
+	s.notifyBillReversed(b, reason)
+}
+
+// checkSpendable refuses a debit larger than the wallet's spendable balance (live 9PSB balance less savings pockets and
+// any amount above the tier maximum).
+func (s *PaymentService) checkSpendable(ctx context.Context, wallet *repository.ActiveWalletForTransfer, tier int, amount float64) error {
+	if s.psbProvider == nil {
+		if wallet.AvailableBalance < amount {
+			return fmt.Errorf("insufficient balance")
+		}
+		return nil
+	}
+	enquiry, err := s.psbProvider.WalletEnquiry(ctx, wallet.AccountNumber)
+	if err != nil {
+		return fmt.Errorf("could not verify balance: %w", err)
+	}
+	return s.checkBalance(ctx, wallet, tier, enquiry.AvailableBalance, amount)
+}
+
+func validateBillCustomerID(b *bills.Biller, customerID string) error {
+	if customerID == "" {
+		return fmt.Errorf("customer_id must be provided (%s)", strings.ToLower(b.CustomerIDLabel))
+	}
+	if (b.Category == bills.CategoryAirtime || b.Category == bills.CategoryData) && !nigerianMSISDN.MatchString(customerID) {
+		return fmt.Errorf("customer_id must be an 11-digit phone number")
+	}
+	return nil
+}
+
+// notifyBillSuccess sends a bill's token to the phone it was bought for and emails the receipt, which the notification
+// service renders from the bill_payment_success template.
+func (s *PaymentService) notifyBillSuccess(ctx context.Context, b *repository.BillPaymentRow) {
+	if b.Token != "" {
+		phone := b.Phone
+		if phone == "" && s.userClient != nil {
+			if u, _ := s.userClient.GetUserForKYC(ctx, b.UserID.String()); u != nil && u.Found {
+				phone = u.PhoneNumber
+			}
+		}
+		if phone != "" {
+			msg := fmt.Sprintf("PayUp: %s token for meter %s: %s", b.BillerName, b.CustomerID, b.Token)
+			if b.Units != "" {
+				msg += " (" + b.Units + ")"
+			}
+			msg += ". Ref " + b.BillRef
+			if err := s.SendNotification(kafka.NotificationEvent{
+				UserID:  b.UserID.String(),
+				Type:    "bill_token",
+				Channel: b.DeliveryChannel,
+				Metadata: map[string]interface{}{
+					"to":       phone,
+					"body":     msg,
+					"bill_ref": b.BillRef,
+				},
+			}); err == nil {
+				_ = s.billRepo.MarkTokenDelivered(ctx, b.ID)
+			}
+		}
+	}
+	_ = s.SendNotification(kafka.NotificationEvent{
+		UserID:  b.UserID.String(),
+		Type:    "bill_payment_success",
+		Channel: "email",
+		Data: map[string]interface{}{
+			"biller":          b.BillerName,
+			"item":            b.ItemName,
+			"customer_id":     b.CustomerID,
+			"customer_name":   b.CustomerName,
+			"amount":          b.Amount,
+			"token":           b.Token,
+			"units":           b.Units,
+			"bill_ref":        b.BillRef,
+			"transaction_ref": b.BillRef,
+		},
+	})
+}
+
+// notifyBillReversed emails the user that their bill failed and was refunded.
+func (s *PaymentService) notifyBillReversed(b *repository.BillPaymentRow, reason string) {
+	_ = s.SendNotification(kafka.NotificationEvent{
+		UserID:  b.UserID.String(),
+		Type:    "bill_payment_reversed",
+		Channel: "email",
+		Data: map[string]interface{}{
+			"biller":          b.BillerName,
+			"amount":          b.Amount,
+			"customer_id":     b.CustomerID,
+			"reason":          reason,
+			"bill_ref":        b.BillRef,
+			"transaction_ref": b.BillRef,
+		},
+	})
+}
+
+func (s *PaymentService) auditBill(b *repository.BillPaymentRow, action string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["bill_ref"] = b.BillRef
+	metadata["biller"] = b.BillerCode
+	metadata["amount"] = b.Amount
+	userID := b.UserID.String()
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Action:   action,
+		Entity:   "transaction",
+		EntityID: b.TransactionID.String(),
+		UserID:   &userID,
+		Metadata: metadata,
+	})
+}
+
+func truncateStr(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/bills"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// withBills wires the stub aggregator and an in-memory bill store into the fixture.
+func (f *testFixture) withBills() *fakeBills {
+	store := newFakeBills()
+	f.svc.billRepo = store
+	f.svc.billsProvider = bills.NewStubProvider()
+	return store
+}
+
+func (f *testFixture) airtime(customerID string, amount float64, key string) *PayBillParams {
+	return &PayBillParams{UserID: f.userID.String(), BillerCode: "MTN-AIRTIME", ItemCode: "AIRTIME", CustomerID: customerID, Amount: amount, IdempotencyKey: key}
+}
+
+func TestPayBill(t *testing.T) {
+	f := newTestFixture()
+	f.withBills()
+	b, err := f.svc.PayBill(context.Background(), f.airtime("08031234567", 1000, "bill-1"))
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	if b.Status != repository.BillStatusSuccess || b.DebitRef != "DR"+b.BillRef {
+		t.Errorf("bill = %+v", b)
+	}
+	if got := f.bank.balances[testAccount]; got != testOpeningBalance-1000 {
+		t.Errorf("balance = %.2f, want %d", got, testOpeningBalance-1000)
+	}
+
+	again, err := f.svc.PayBill(context.Background(), f.airtime("08031234567", 1000, "bill-1"))
+	if err != nil || again.BillRef != b.BillRef {
+		t.Fatalf("replay = %+v, %v; want %s", again, err, b.BillRef)
+	}
+	if got := f.bank.balances[testAccount]; got != testOpeningBalance-1000 {
+		t.Errorf("replay debited again: balance %.2f", got)
+	}
+}
+
+func TestPayBillVendFailed(t *testing.T) {
+	f := newTestFixture()
+	f.withBills()
+	b, err := f.svc.PayBill(context.Background(), f.airtime("08031238888", 1000, "bill-1"))
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	if b.Status != repository.BillStatusReversed {
+		t.Errorf("status = %s, want REVERSED", b.Status)
+	}
+	if f.bank.credits["REV"+b.BillRef] != 1000 || f.bank.balances[testAccount] != testOpeningBalance {
+		t.Errorf("refund: credits %v, balance %.2f", f.bank.credits, f.bank.balances[testAccount])
+	}
+}
+
+func TestPayBillKeyScopedToUser(t *testing.T) {
+	f := newTestFixture()
+	store := f.withBills()
+	first, err := f.svc.PayBill(context.Background(), f.airtime("08031234567", 1000, "bill-1"))
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+
+	other, _ := f.addUser("1100000002")
+	p := f.airtime("08039876543", 500, "bill-1")
+	p.UserID = other.String()
+	b, err := f.svc.PayBill(context.Background(), p)
+	if err != nil {
+		t.Fatalf("other user's payment: %v", err)
+	}
+	if b.BillRef == first.BillRef || b.UserID != other || b.CustomerID != "08039876543" {
+		t.Errorf("other user got bill %+v", b)
+	}
+	if f.bank.balances["1100000002"] != testOpeningBalance-500 || len(store.bills) != 2 {
+		t.Errorf("other user's payment: balance %.2f, %d bills", f.bank.balances["1100000002"], len(store.bills))
+	}
+	if found, _ := store.GetByIdempotencyKey(context.Background(), uuid.New(), "bill-1"); found != nil {
+		t.Errorf("lookup by a stranger returned %s", found.BillRef)
+	}
+}
+
+func TestPayBillKeyReusedForAnotherPurchase(t *testing.T) {
+	f := newTestFixture()
+	store := f.withBills()
+	if _, err := f.svc.PayBill(context.Background(), f.airtime("08031234567", 1000, "bill-1")); err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	for name, p := range map[string]*PayBillParams{
+		"another customer": f.airtime("08039876543", 1000, "bill-1"),
+		"another amount":   f.airtime("08031234567", 2000, "bill-1"),
+	} {
+		if _, err := f.svc.PayBill(context.Background(), p); !errors.Is(err, repository.ErrIdempotencyConflict) {
+			t.Errorf("%s: err = %v, want ErrIdempotencyConflict", name, err)
+		}
+	}
+	if len(store.bills) != 1 || f.bank.balances[testAccount] != testOpeningBalance-1000 {
+		t.Errorf("conflicting requests moved money: %d bills, balance %.2f", len(store.bills), f.bank.balances[testAccount])
+	}
+}
+
+// A passkey approval is bound to its payee, so payment must name the payee it is approving for.
+func TestPaymentApprovalNamesPayee(t *testing.T) {
+	f := newTestFixture()
+	f.withBills()
+	users := &fakeUsers{}
+	f.svc.userClient = users
+	if _, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1")); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if _, err := f.svc.PayBill(context.Background(), f.airtime(" 08031234567 ", 1000, "bill-1")); err != nil {
+		t.Fatalf("pay bill: %v", err)
+	}
+	want := []string{testBeneficiaryBank + "/" + testBeneficiaryAcct, "MTN-AIRTIME/08031234567"}
+	if len(users.payees) != 2 || users.payees[0] != want[0] || users.payees[1] != want[1] {
+		t.Errorf("approvals asked for %v, want %v", users.payees, want)
+	}
+}
+
+func TestIdempotentTrackingRefPerWallet(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	if idempotentTrackingRef("ADJ", a, "key-1") != idempotentTrackingRef("ADJ", a, "key-1") {
+		t.Error("reference not stable for a wallet's key")
+	}
+	if idempotentTrackingRef("ADJ", a, "key-1") == idempotentTrackingRef("ADJ", b, "key-1") {
+		t.Error("two wallets share a reference for the same key")
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/google/uuid"
+)
+
+// SyncUserContact copies a user's new phone number and email (user_contact_changed on user-events) to their wallets.
+// The details 9PSB holds for the wallet are not changed.
+func (s *PaymentService) SyncUserContact(ctx context.Context, userID, field, phone, email string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+	n, err := s.walletRepo.UpdateContact(ctx, uid, phone, email)
+	if err != nil || n == 0 {
+		return err
+	}
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Service:  serviceName,
+		Action:   "wallet_contact_updated",
+		Entity:   "wallet",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"field": field, "wallets": n},
+	})
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// UserDataExport is what the payment service holds about a user, for a data-subject access request.
+type UserDataExport struct {
+	Wallets      []repository.WalletAdminRow `json:"wallets"`
+	Transactions []WalletTransactionExport   `json:"transactions"`
+	Pockets      []repository.PocketRow      `json:"pockets"`
+	Disputes     []repository.DisputeRow     `json:"disputes"`
+}
+
+// WalletTransactionExport is a transaction history row tagged with the wallet it belongs to.
+type WalletTransactionExport struct {
+	WalletID string `json:"wallet_id"`
+	repository.TransactionHistoryRow
+}
+
+// ErasureResult reports what EraseUserData did. Held is set (and nothing changed) while HoldReasons apply.
+type ErasureResult struct {
+	Held        bool
+	HoldReasons []string
+	Erased      []string
+	Retained    []string
+}
+
+// ExportUserData gathers the user's wallets, full transaction history, pockets and disputes. Returns nil if the user
+// never had a wallet.
+func (s *PaymentService) ExportUserData(ctx context.Context, userID string) (*UserDataExport, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	wallets, err := s.walletRepo.ListByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(wallets) == 0 {
+		return nil, nil
+	}
+	out := &UserDataExport{Wallets: wallets}
+	for _, w := range wallets {
+		walletID, err := uuid.Parse(w.ID)
+		if err != nil {
+			return nil, err
+		}
+		for offset := 0; ; offset += 100 {
+			page, err := s.transactionRepo.ListByWalletID(ctx, walletID, 100, offset)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range page {
+				out.Transactions = append(out.Transactions, WalletTransactionExport{WalletID: w.ID, TransactionHistoryRow: t})
+			}
+			if len(page) < 100 {
+				break
+			}
+		}
+	}
+	if out.Pockets, err = s.pocketRepo.ListByUser(ctx, uid, true); err != nil {
+		return nil, err
+	}
+	for offset := 0; ; offset += 100 {
+		page, err := s.disputeRepo.ListByUserID(ctx, uid, 100, offset)
+		if err != nil {
+			return nil, err
+		}
+		out.Disputes = append(out.Disputes, page...)
+		if len(page) < 100 {
+			break
+		}
+	}
+	return out, nil
+}
+
+// EraseUserData handles account deletion. Wallets and transactions are financial records kept for the statutory
+// retention period, so they are retained; what goes is the contact copy (phone, email) and the ability to transact:
+// each open wallet is suspended at 9PSB and locally. Nothing is changed while the user still has money with us or a
+// payment or dispute is unresolved; the caller reports the hold reasons to the user.
+func (s *PaymentService) EraseUserData(ctx context.Context, userID string) (*ErasureResult, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	wallets, err := s.walletRepo.ListByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(wallets) == 0 {
+		return &ErasureResult{}, nil
+	}
+	holds, err := s.erasureHolds(ctx, uid, wallets)
+	if err != nil {
+		return nil, err
+	}
+	if len(holds) > 0 {
+		_ = s.SendAuditLog(kafka.AuditLogParams{
+			Service:  serviceName,
+			Action:   "wallet_erasure_held",
+			Entity:   "wallet",
+			EntityID: userID,
+			UserID:   &userID,
+			Metadata: map[string]interface{}{"reasons": holds},
+		})
+		return &ErasureResult{Held: true, HoldReasons: holds}, nil
+	}
+	for _, w := range wallets {
+		walletID, err := uuid.Parse(w.ID)
+		if err != nil {
+			return nil, err
+		}
+		if w.Status != "SUSPENDED" && w.Status != "CLOSED" {
+			if s.psbProvider != nil {
+				if _, err := s.psbProvider.WaasChangeWalletStatus(ctx, w.AccountNumber, "SUSPENDED"); err != nil {
+					return nil, fmt.Errorf("suspend wallet at 9PSB: %w", err)
+				}
+			}
+			if err := s.walletRepo.UpdateStatus(ctx, walletID, "SUSPENDED"); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.walletRepo.EraseContact(ctx, walletID); err != nil {
+			return nil, err
+		}
+	}
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Service:  serviceName,
+		Action:   "wallet_erased",
+		Entity:   "wallet",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"wallets": len(wallets)},
+	})
+	return &ErasureResult{Erased: []string{"wallet_contact"}, Retained: []string{"wallet", "transactions"}}, nil
+}
+
+// erasureHolds lists what blocks erasure: money left in a wallet or pocket, payments without a final outcome, and
+// open disputes.
+func (s *PaymentService) erasureHolds(ctx context.Context, userID uuid.UUID, wallets []repository.WalletAdminRow) ([]string, error) {
+	var holds []string
+	var inFlight int64
+	for _, w := range wallets {
+		if w.LedgerBalance != 0 || w.AvailableBalance != 0 {
+			holds = append(holds, fmt.Sprintf("wallet balance of NGN %.2f must be withdrawn", w.LedgerBalance))
+		}
+		walletID, err := uuid.Parse(w.ID)
+		if err != nil {
+			return nil, err
+		}
+		n, err := s.transactionRepo.CountInFlight(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		inFlight += n
+	}
+	pockets, err := s.pocketRepo.ListByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	var saved float64
+	for _, p := range pockets {
+		saved += p.Balance
+	}
+	if saved > 0 {
+		holds = append(holds, fmt.Sprintf("savings pockets hold NGN %.2f", saved))
+	}
+	if inFlight > 0 {
+		holds = append(holds, fmt.Sprintf("%d payment(s) are still pending", inFlight))
+	}
+	open, err := s.disputeRepo.CountOpenByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if open > 0 {
+		holds = append(holds, fmt.Sprintf("%d dispute(s) are still open", open))
+	}
+	return holds, nil
+}
@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDisputeNotFound          = errors.New("dispute not found")
+	ErrDisputeClosed            = errors.New("dispute is already closed")
+	ErrTransactionNotDisputable = errors.New("transaction cannot be disputed")
+	ErrInvalidDisputeTransition = errors.New("invalid dispute status transition")
+	// ErrDisputePaymentUnconfirmed means the resolution payment may or may not have moved money. The dispute stays
+	// PAYMENT_PENDING; resolving it again retries the same payment under the same idempotency key.
+	ErrDisputePaymentUnconfirmed = errors.New("resolution payment not confirmed; resolve again to retry it")
+)
+
+// DefaultDisputeSLA is the resolution deadline applied when DISPUTE_SLA_HOURS is not set.
+const DefaultDisputeSLA = 72 * time.Hour
+
+// Dispute reasons accepted from users (transaction_disputes.reason).
+var disputeReasons = map[string]bool{
+	"NOT_RECEIVED": true,
+	"WRONG_AMOUNT": true,
+	"DUPLICATE":    true,
+	"UNAUTHORIZED": true,
+	"OTHER":        true,
+}
+
+// Dispute resolutions (dispute_resolution enum).
+const (
+	DisputeResolutionReversal   = "REVERSAL"
+	DisputeResolutionAdjustment = "ADJUSTMENT"
+	DisputeResolutionNoAction   = "NO_ACTION"
+)
+
+// disputeTransitions lists the statuses an admin may move a dispute to from each unresolved status.
+// RESOLVED is reached only through ResolveDispute so that any wallet movement is recorded with it.
+var disputeTransitions = map[string]map[string]bool{
+	repository.DisputeStatusOpen:         {repository.DisputeStatusUnderReview: true, repository.DisputeStatusAwaitingUser: true, repository.DisputeStatusRejected: true},
+	repository.DisputeStatusUnderReview:  {repository.DisputeStatusAwaitingUser: true, repository.DisputeStatusRejected: true},
+	repository.DisputeStatusAwaitingUser: {repository.DisputeStatusUnderReview: true, repository.DisputeStatusRejected: true},
+}
+
+// DisputeDetail is a dispute with its notes and attachments.
+type DisputeDetail struct {
+	Dispute     repository.DisputeRow
+	Notes       []repository.DisputeNoteRow
+	Attachments []repository.DisputeAttachmentRow
+}
+
+// DisputeSLABreached reports whether an unresolved dispute is past its SLA deadline.
+func DisputeSLABreached(d *repository.DisputeRow, now time.Time) bool {
+	if d == nil || isDisputeClosed(d.Status) {
+		return false
+	}
+	return now.After(d.SLADueAt)
+}
+
+func isDisputeClosed(status string) bool {
+	return status == repository.DisputeStatusResolved || status == repository.DisputeStatusRejected
+}
+
+// OpenDispute opens a dispute against one of the user's transactions (by transaction_ref). Failed and reversed transactions
+// cannot be disputed; only one unresolved dispute per transaction is allowed.
+func (s *PaymentService) OpenDispute(ctx context.Context, userID, transactionRef, reason, description string) (*repository.DisputeRow, error) {
+	reason = strings.ToUpper(strings.TrimSpace(reason))
+	if !disputeReasons[reason] {
+		return nil, fmt.Errorf("invalid reason: must be one of NOT_RECEIVED, WRONG_AMOUNT, DUPLICATE, UNAUTHORIZED, OTHER")
+	}
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil, fmt.Errorf("description is required")
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	wallet, err := s.walletRepo.GetActiveByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if wallet == nil {
+		return nil, fmt.Errorf("no active wallet")
+	}
+	txn, err := s.transactionRepo.GetDisputableByRefAndWalletID(ctx, transactionRef, wallet.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if txn == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+	if txn.Status == "FAILED" || txn.Status == "REVERSED" {
+		return nil, ErrTransactionNotDisputable
+	}
+	d, err := s.disputeRepo.Create(ctx, &repository.CreateDisputeParams{
+		TransactionID: txn.ID,
+		WalletID:      wallet.WalletID,
+		UserID:        uid,
+		Reason:        reason,
+		Description:   description,
+		SLADueAt:      time.Now().Add(s.disputeSLA),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditDispute(d, "dispute_opened", userID, map[string]interface{}{
+		"reason": reason, "transaction_amount": txn.Amount, "transaction_status": txn.Status,
+	})
+	s.notifyDispute(d, "dispute_opened", "")
+	return d, nil
+}
+
+// ListMyDisputes returns the user's disputes, newest first.
+func (s *PaymentService) ListMyDisputes(ctx context.Context, userID string, limit, offset int) ([]repository.DisputeRow, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	return s.disputeRepo.ListByUserID(ctx, uid, limit, offset)
+}
+
+// GetMyDispute returns one of the user's disputes by dispute_ref with notes (internal admin notes excluded) and attachments.
+func (s *PaymentService) GetMyDispute(ctx context.Context, userID, disputeRef string) (*DisputeDetail, error) {
+	d, err := s.getUserDispute(ctx, userID, disputeRef)
+	if err != nil {
+		return nil, err
+	}
+	return s.disputeDetail(ctx, d, false)
+}
+
+// AddUserDisputeNote adds a user note. A dispute AWAITING_USER goes back to UNDER_REVIEW once the user responds.
+func (s *PaymentService) AddUserDisputeNote(ctx context.Context, userID, disputeRef, body string) (*repository.DisputeNoteRow, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("note body is required")
+	}
+	d, err := s.getUserDispute(ctx, userID, disputeRef)
+	if err != nil {
+		return nil, err
+	}
+	if isDisputeClosed(d.Status) {
+		return nil, ErrDisputeClosed
+	}
+	did, _ := uuid.Parse(d.ID)
+	note, err := s.disputeRepo.AddNote(ctx, did, repository.DisputeActorUser, userID, body, false)
+	if err != nil {
+		return nil, err
+	}
+	s.auditDispute(d, "dispute_note_added", userID, map[string]interface{}{"author_type": repository.DisputeActorUser, "note_id": note.ID})
+	if d.Status == repository.DisputeStatusAwaitingUser {
+		if err := s.disputeRepo.UpdateStatus(ctx, did, d.Status, repository.DisputeStatusUnderReview, "", false); err == nil {
+			s.auditDispute(d, "dispute_status_changed", userID, map[string]interface{}{
+				"from": d.Status, "to": repository.DisputeStatusUnderReview, "trigger": "user_response",
+			})
+		}
+	}
+	return note, nil
+}
+
+// AddUserDisputeAttachment records an evidence file uploaded by the user.
+func (s *PaymentService) AddUserDisputeAttachment(ctx context.Context, userID, disputeRef, fileName, contentType, url string) (*repository.DisputeAttachmentRow, error) {
+	d, err := s.getUserDispute(ctx, userID, disputeRef)
+	if err != nil {
+		return nil, err
+	}
+	return s.addDisputeAttachment(ctx, d, repository.DisputeActorUser, userID, fileName, contentType, url)
+}
+
+func (s *PaymentService) getUserDispute(ctx context.Context, userID, disputeRef string) (*repository.DisputeRow, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	d, err := s.disputeRepo.GetByRefAndUserID(ctx, disputeRef, uid)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDisputeNotFound
+	}
+	return d, nil
+}
+
+func (s *PaymentService) disputeDetail(ctx context.Context, d *repository.DisputeRow, includeInternal bool) (*DisputeDetail, error) {
+	did, _ := uuid.Parse(d.ID)
+	notes, err := s.disputeRepo.ListNotes(ctx, did, includeInternal)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.disputeRepo.ListAttachments(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	return &DisputeDetail{Dispute: *d, Notes: notes, Attachments: attachments}, nil
+}
+
+func (s *PaymentService) addDisputeAttachment(ctx context.Context, d *repository.DisputeRow, actorType, actorID, fileName, contentType, url string) (*repository.DisputeAttachmentRow, error) {
+	fileName, contentType, url = strings.TrimSpace(fileName), strings.TrimSpace(contentType), strings.TrimSpace(url)
+	if fileName == "" || contentType == "" || url == "" {
+		return nil, fmt.Errorf("file_name, content_type and url are required")
+	}
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("url must be https")
+	}
+	if isDisputeClosed(d.Status) {
+		return nil, ErrDisputeClosed
+	}
+	did, _ := uuid.Parse(d.ID)
+	a, err := s.disputeRepo.AddAttachment(ctx, did, actorType, actorID, fileName, contentType, url)
+	if err != nil {
+		return nil, err
+	}
+	s.auditDispute(d, "dispute_attachment_added", actorID, map[string]interface{}{
+		"uploaded_by_type": actorType, "attachment_id": a.ID, "file_name": fileName, "content_type": contentType,
+	})
+	return a, nil
+}
+
+// ListDisputesForAdmin returns the admin dispute queue (status filter optional), most urgent SLA first, plus the total count.
+func (s *PaymentService) ListDisputesForAdmin(ctx context.Context, status string, limit, offset int) ([]repository.DisputeRow, int64, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	list, err := s.disputeRepo.ListForAdmin(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.disputeRepo.CountForAdmin(ctx, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// GetDisputeForAdmin returns one dispute by id with all notes (including internal) and attachments.
+func (s *PaymentService) GetDisputeForAdmin(ctx context.Context, disputeID string) (*DisputeDetail, error) {
+	d, err := s.getDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.disputeDetail(ctx, d, true)
+}
+
+func (s *PaymentService) getDisputeByID(ctx context.Context, disputeID string) (*repository.DisputeRow, error) {
+	did, err := uuid.Parse(disputeID)
+	if err != nil {
+		return nil, ErrDisputeNotFound
+	}
+	d, err := s.disputeRepo.GetByID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDisputeNotFound
+	}
+	return d, nil
+}
+
+// UpdateDisputeStatus moves a dispute to UNDER_REVIEW, AWAITING_USER or REJECTED (admin). The caller is recorded as assignee.
+// An optional note is added as a user-visible note (e.g. what evidence is needed, or why the dispute was rejected).
+func (s *PaymentService) UpdateDisputeStatus(ctx context.Context, disputeID, newStatus, adminID, note string) (*repository.DisputeRow, error) {
+	newStatus = strings.ToUpper(strings.TrimSpace(newStatus))
+	d, err := s.getDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if isDisputeClosed(d.Status) {
+		return nil, ErrDisputeClosed
+	}
+	if !disputeTransitions[d.Status][newStatus] {
+		return nil, ErrInvalidDisputeTransition
+	}
+	did, _ := uuid.Parse(d.ID)
+	if newStatus == repository.DisputeStatusRejected {
+		if strings.TrimSpace(note) == "" {
+			return nil, fmt.Errorf("note is required when rejecting a dispute")
+		}
+		err = s.disputeRepo.ClaimResolution(ctx, did, d.Status, newStatus, "", 0, false, note, adminID)
+	} else {
+		err = s.disputeRepo.UpdateStatus(ctx, did, d.Status, newStatus, adminID, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		if _, err := s.disputeRepo.AddNote(ctx, did, repository.DisputeActorAdmin, adminID, note, false); err != nil {
+			return nil, err
+		}
+	}
+	s.auditDispute(d, "dispute_status_changed", adminID, map[string]interface{}{"from": d.Status, "to": newStatus, "admin_id": adminID})
+	updated, err := s.disputeRepo.GetByID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	switch newStatus {
+	case repository.DisputeStatusAwaitingUser:
+		s.notifyDispute(updated, "dispute_awaiting_user", note)
+	case repository.DisputeStatusRejected:
+		s.notifyDispute(updated, "dispute_rejected", updated.ResolutionNote)
+	}
+	return updated, nil
+}
+
+// AddAdminDisputeNote adds an admin note. internal=true keeps it out of the user's view.
+func (s *PaymentService) AddAdminDisputeNote(ctx context.Context, disputeID, adminID, body string, internal bool) (*repository.DisputeNoteRow, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("note body is required")
+	}
+	d, err := s.getDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	did, _ := uuid.Parse(d.ID)
+	note, err := s.disputeRepo.AddNote(ctx, did, repository.DisputeActorAdmin, adminID, body, internal)
+	if err != nil {
+		return nil, err
+	}
+	s.auditDispute(d, "dispute_note_added", adminID, map[string]interface{}{
+		"author_type": repository.DisputeActorAdmin, "note_id": note.ID, "internal": internal, "admin_id": adminID,
+	})
+	return note, nil
+}
+
+// AddAdminDisputeAttachment records an evidence file attached by an admin (e.g. provider statement).
+func (s *PaymentService) AddAdminDisputeAttachment(ctx context.Context, disputeID, adminID, fileName, contentType, url string) (*repository.DisputeAttachmentRow, error) {
+	d, err := s.getDisputeByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.addDisputeAttachment(ctx, d, repository.DisputeActorAdmin, adminID, fileName, contentType, url)
+}
+
+// ResolveDisputeParams are the admin inputs for resolving a dispute.
+type ResolveDisputeParams struct {
+	DisputeID  string
+	Resolution string  // REVERSAL, ADJUSTMENT or NO_ACTION
+	Amount     float64 // ADJUSTMENT only; REVERSAL always uses the original amount plus fee
+	IsCredit   bool    // ADJUSTMENT only; true = credit the user, false = debit
+	Note       string
+	AdminID    string
+}
+
+// ResolveDispute closes a dispute as RESOLVED. REVERSAL credits the original amount plus fee back to the wallet (outbound
+// transactions only); ADJUSTMENT credits or debits the given amount; NO_ACTION moves no money. Wallet movements go through
+// WalletDebitCredit so they hit 9PSB and the ledger like any other adjustment.
+//
+// A resolution that moves money first claims the dispute as PAYMENT_PENDING, so a concurrent resolve cannot pay twice. The
+// claim is released only if the payment certainly moved no money. Any other failure (a 9PSB timeout, say) leaves the
+// dispute PAYMENT_PENDING and returns ErrDisputePaymentUnconfirmed; resolving it again retries the recorded payment with
+// the same idempotency key, whatever resolution the retry asks for.
+func (s *PaymentService) ResolveDispute(ctx context.Context, p ResolveDisputeParams) (*repository.DisputeRow, error) {
+	resolution := strings.ToUpper(strings.TrimSpace(p.Resolution))
+	d, err := s.getDisputeByID(ctx, p.DisputeID)
+	if err != nil {
+		return nil, err
+	}
+	if isDisputeClosed(d.Status) {
+		return nil, ErrDisputeClosed
+	}
+	if d.Status == repository.DisputeStatusPaymentPending {
+		return s.payDisputeResolution(ctx, d, "", p.AdminID)
+	}
+	var amount float64
+	isCredit := true
+	switch resolution {
+	case DisputeResolutionReversal:
+		if d.TransactionDirection != "OUT" {
+			return nil, fmt.Errorf("only outbound transactions can be reversed")
+		}
+		wid, _ := uuid.Parse(d.WalletID)
+		txn, err := s.transactionRepo.GetDisputableByRefAndWalletID(ctx, d.TransactionRef, wid)
+		if err != nil {
+			return nil, err
+		}
+		if txn == nil {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		amount = txn.Amount + txn.FeeAmount
+	case DisputeResolutionAdjustment:
+		if p.Amount <= 0 {
+			return nil, fmt.Errorf("amount must be positive")
+		}
+		amount = p.Amount
+		isCredit = p.IsCredit
+	case DisputeResolutionNoAction:
+	default:
+		return nil, fmt.Errorf("invalid resolution: must be REVERSAL, ADJUSTMENT or NO_ACTION")
+	}
+	did, _ := uuid.Parse(d.ID)
+	toStatus := repository.DisputeStatusResolved
+	if amount > 0 {
+		toStatus = repository.DisputeStatusPaymentPending
+	}
+	if err := s.disputeRepo.ClaimResolution(ctx, did, d.Status, toStatus, resolution, amount, isCredit, p.Note, p.AdminID); err != nil {
+		return nil, err
+	}
+	claimed, err := s.disputeRepo.GetByID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	if amount > 0 {
+		return s.payDisputeResolution(ctx, claimed, d.Status, p.AdminID)
+	}
+	return s.finishDisputeResolution(ctx, claimed, d.Status, p.AdminID)
+}
+
+// payDisputeResolution makes the wallet payment recorded on a PAYMENT_PENDING dispute and resolves it. releaseTo is the
+// status the dispute was claimed from; it is empty on a retry, whose claim is never released because an earlier attempt
+// may have moved money.
+func (s *PaymentService) payDisputeResolution(ctx context.Context, d *repository.DisputeRow, releaseTo, adminID string) (*repository.DisputeRow, error) {
+	did, _ := uuid.Parse(d.ID)
+	narration := fmt.Sprintf("Dispute %s %s", d.DisputeRef, strings.ToLower(d.Resolution))
+	res, err := s.WalletDebitCredit(ctx, d.UserID, d.ResolutionAmount, d.ResolutionIsCredit, narration, adminID, disputeResolutionKey(d))
+	if err != nil {
+		metadata := map[string]interface{}{
+			"resolution": d.Resolution, "amount": d.ResolutionAmount, "is_credit": d.ResolutionIsCredit, "error": err.Error(), "admin_id": adminID,
+		}
+		if releaseTo != "" && isNotPosted(err) {
+			if rerr := s.disputeRepo.ReleaseResolution(ctx, did, releaseTo); rerr != nil {
+				return nil, rerr
+			}
+			s.auditDispute(d, "dispute_resolution_failed", adminID, metadata)
+			return nil, fmt.Errorf("resolution payment failed: %w", err)
+		}
+		s.auditDispute(d, "dispute_resolution_unconfirmed", adminID, metadata)
+		return nil, fmt.Errorf("%w: %v", ErrDisputePaymentUnconfirmed, err)
+	}
+	if err := s.disputeRepo.CompleteResolution(ctx, did, res.TransactionRef); err != nil {
+		return nil, err
+	}
+	from := releaseTo
+	if from == "" {
+		from = d.Status
+	}
+	return s.finishDisputeResolution(ctx, d, from, adminID)
+}
+
+// finishDisputeResolution records the resolution note, audits and notifies the user once a dispute is RESOLVED.
+func (s *PaymentService) finishDisputeResolution(ctx context.Context, d *repository.DisputeRow, from, adminID string) (*repository.DisputeRow, error) {
+	did, _ := uuid.Parse(d.ID)
+	updated, err := s.disputeRepo.GetByID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	if note := strings.TrimSpace(updated.ResolutionNote); note != "" {
+		if _, err := s.disputeRepo.AddNote(ctx, did, repository.DisputeActorAdmin, adminID, note, false); err != nil {
+			return nil, err
+		}
+	}
+	s.auditDispute(d, "dispute_resolved", adminID, map[string]interface{}{
+		"from": from, "resolution": updated.Resolution, "amount": updated.ResolutionAmount, "is_credit": updated.ResolutionIsCredit,
+		"resolution_txn_ref": updated.ResolutionTxnRef, "admin_id": adminID,
+	})
+	s.notifyDispute(updated, "dispute_resolved", updated.ResolutionNote)
+	return updated, nil
+}
+
+// disputeResolutionKey is the idempotency key of a dispute's resolution payment: one payment per dispute, however often
+// it is retried.
+func disputeResolutionKey(d *repository.DisputeRow) string {
+	return "dispute:" + d.ID
+}
+
+// auditDispute emits a dispute audit event. actorID is the user or admin who made the change.
+func (s *PaymentService) auditDispute(d *repository.DisputeRow, action, actorID string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["dispute_ref"] = d.DisputeRef
+	metadata["transaction_ref"] = d.TransactionRef
+	metadata["actor_id"] = actorID
+	userID := d.UserID
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Action:   action,
+		Entity:   "dispute",
+		EntityID: d.ID,
+		UserID:   &userID,
+		Metadata: metadata,
+	})
+}
+
+// notifyDispute emails the user about their dispute; the notification service renders it from the evType template.
+func (s *PaymentService) notifyDispute(d *repository.DisputeRow, evType, note string) {
+	if d == nil {
+		return
+	}
+	_ = s.SendNotification(kafka.NotificationEvent{
+		UserID:  d.UserID,
+		Type:    evType,
+		Channel: "email",
+		Data: map[string]interface{}{
+			"dispute_ref":        d.DisputeRef,
+			"transaction_ref":    d.TransactionRef,
+			"sla_due_at":         d.SLADueAt.Format("02 Jan 2006 15:04 MST"),
+			"outcome":            strings.ToLower(d.Status),
+			"amount":             d.ResolutionAmount,
+			"resolution_txn_ref": d.ResolutionTxnRef,
+			"note":               note,
+		},
+	})
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// withDispute gives the fixture an UNDER_REVIEW dispute raised by userID and returns its store.
+func (f *testFixture) withDispute(userID uuid.UUID) (*fakeDisputes, *repository.DisputeRow) {
+	d := &repository.DisputeRow{
+		ID: uuid.New().String(), DisputeRef: "DSP0001", TransactionRef: "TRF0001", TransactionDirection: "OUT",
+		UserID: userID.String(), Status: repository.DisputeStatusUnderReview,
+	}
+	store := &fakeDisputes{rows: map[uuid.UUID]*repository.DisputeRow{uuid.MustParse(d.ID): d}}
+	f.svc.disputeRepo = store
+	return store, d
+}
+
+func adjustment(d *repository.DisputeRow, amount float64) ResolveDisputeParams {
+	return ResolveDisputeParams{DisputeID: d.ID, Resolution: DisputeResolutionAdjustment, Amount: amount, IsCredit: true, Note: "Refunded", AdminID: "admin-1"}
+}
+
+func TestResolveDispute(t *testing.T) {
+	f := newTestFixture()
+	store, d := f.withDispute(f.userID)
+	got, err := f.svc.ResolveDispute(context.Background(), adjustment(d, 2500))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got.Status != repository.DisputeStatusResolved || got.ResolutionTxnRef == "" || len(store.notes) != 1 {
+		t.Errorf("dispute = %+v, notes %v", got, store.notes)
+	}
+	if f.bank.balances[testAccount] != testOpeningBalance+2500 || len(f.bank.credits) != 1 {
+		t.Errorf("balance %.2f after credits %v", f.bank.balances[testAccount], f.bank.credits)
+	}
+
+	if _, err := f.svc.ResolveDispute(context.Background(), adjustment(d, 2500)); !errors.Is(err, ErrDisputeClosed) {
+		t.Errorf("second resolve = %v, want ErrDisputeClosed", err)
+	}
+	if len(f.bank.credits) != 1 {
+		t.Errorf("second resolve paid again: credits %v", f.bank.credits)
+	}
+}
+
+func TestResolveDisputeConcurrent(t *testing.T) {
+	f := newTestFixture()
+	store, d := f.withDispute(f.userID)
+	// Another admin resolves the dispute after this call has read it but before it claims it.
+	var otherErr error
+	store.beforeClaim = func() { _, otherErr = f.svc.ResolveDispute(context.Background(), adjustment(d, 2500)) }
+
+	_, err := f.svc.ResolveDispute(context.Background(), adjustment(d, 2500))
+	if otherErr != nil {
+		t.Fatalf("first resolve: %v", otherErr)
+	}
+	if !errors.Is(err, repository.ErrDisputeStatusConflict) {
+		t.Errorf("losing resolve = %v, want ErrDisputeStatusConflict", err)
+	}
+	if len(f.bank.credits) != 1 || f.bank.balances[testAccount] != testOpeningBalance+2500 {
+		t.Errorf("credits %v, balance %.2f; want one payment", f.bank.credits, f.bank.balances[testAccount])
+	}
+}
+
+func TestResolveDisputeUnconfirmedPayment(t *testing.T) {
+	cases := []struct {
+		name    string
+		breakIt func(f *testFixture)
+	}{
+		// 9PSB fails the call, which may or may not have credited the wallet.
+		{"provider error", func(f *testFixture) {
+			f.bank.creditErr = fmt.Errorf("9PSB WaaS credit_transfer: context deadline exceeded")
+		}},
+		// 9PSB credited the wallet but recording it failed.
+		{"ledger error", func(f *testFixture) { f.txns.ledgerErr = fmt.Errorf("connection reset by peer") }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestFixture()
+			store, d := f.withDispute(f.userID)
+			c.breakIt(f)
+			if _, err := f.svc.ResolveDispute(context.Background(), adjustment(d, 2500)); !errors.Is(err, ErrDisputePaymentUnconfirmed) {
+				t.Fatalf("resolve = %v, want ErrDisputePaymentUnconfirmed", err)
+			}
+			pending := store.rows[uuid.MustParse(d.ID)]
+			if pending.Status != repository.DisputeStatusPaymentPending || pending.ResolutionAmount != 2500 {
+				t.Fatalf("dispute = %+v; want PAYMENT_PENDING with the resolution kept", pending)
+			}
+			if _, err := f.svc.UpdateDisputeStatus(context.Background(), d.ID, repository.DisputeStatusUnderReview, "admin-2", ""); !errors.Is(err, ErrInvalidDisputeTransition) {
+				t.Errorf("reopening a pending dispute = %v, want ErrInvalidDisputeTransition", err)
+			}
+
+			// The retry pays what was claimed, not what it asks for, under the same key.
+			got, err := f.svc.ResolveDispute(context.Background(), adjustment(d, 9000))
+			if err != nil {
+				t.Fatalf("retry: %v", err)
+			}
+			if got.Status != repository.DisputeStatusResolved || got.ResolutionAmount != 2500 {
+				t.Errorf("dispute = %+v", got)
+			}
+			if f.bank.balances[testAccount] != testOpeningBalance+2500 || len(f.bank.credits) != 1 {
+				t.Errorf("balance %.2f after credits %v; want exactly one credit of 2500", f.bank.balances[testAccount], f.bank.credits)
+			}
+			if _, txn := f.txns.byKey(f.wallet.WalletID, disputeResolutionKey(d)); txn == nil || txn.ref != got.ResolutionTxnRef {
+				t.Errorf("recorded transaction %+v, want ref %s", txn, got.ResolutionTxnRef)
+			}
+		})
+	}
+}
+
+func TestResolveDisputePaymentNotPosted(t *testing.T) {
+	f := newTestFixture()
+	// The user has no wallet, so the payment fails before 9PSB is called.
+	store, d := f.withDispute(uuid.New())
+	_, err := f.svc.ResolveDispute(context.Background(), adjustment(d, 2500))
+	if err == nil || errors.Is(err, ErrDisputePaymentUnconfirmed) {
+		t.Fatalf("resolve = %v, want a definite failure", err)
+	}
+	if got := store.rows[uuid.MustParse(d.ID)]; got.Status != repository.DisputeStatusUnderReview || got.Resolution != "" {
+		t.Errorf("dispute = %+v; want the claim released", got)
+	}
+	if len(f.bank.credits) != 0 || len(store.notes) != 0 {
+		t.Errorf("credits %v, notes %v", f.bank.credits, store.notes)
+	}
+}
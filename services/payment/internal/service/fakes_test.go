@@ -0,0 +1,641 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	userpb "github.com/abubakvr/payup-backend/proto/user"
+	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
+	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// In-memory stand-ins for the repositories and 9PSB. Each embeds its interface, so a method a test did not expect
+// panics instead of silently returning zero values.
+
+type fakeWallets struct {
+	walletStore
+	byUser map[uuid.UUID]*repository.ActiveWalletForTransfer
+}
+
+func (f *fakeWallets) GetActiveByUserID(_ context.Context, userID uuid.UUID) (*repository.ActiveWalletForTransfer, error) {
+	w, ok := f.byUser[userID]
+	if !ok {
+		return nil, nil
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (f *fakeWallets) GetActiveByAccountNumber(_ context.Context, accountNumber string) (*repository.WalletByAccount, error) {
+	for uid, w := range f.byUser {
+		if w.AccountNumber == accountNumber {
+			return &repository.WalletByAccount{WalletID: w.WalletID, UserID: uid, AccountNumber: w.AccountNumber, FullName: w.FullName}, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeTxn struct {
+	walletID    uuid.UUID
+	ref         string
+	providerRef string
+	status      string
+	typ         string
+	amount      float64
+	beneficiary string // "bank/account" of an outbound transfer
+	key         string
+}
+
+type fakeTransactions struct {
+	transactionStore
+	rows      map[uuid.UUID]*fakeTxn
+	ledger    []uuid.UUID // transactions with a ledger entry
+	ledgerErr error       // fails the next CreateInternalDebitCreditAndPostLedger
+	spend     repository.OutboundSpend
+	// raced is spent by concurrent payments after the early limits check, seen only by the guard at insert
+	raced repository.OutboundSpend
+}
+
+func newFakeTransactions() *fakeTransactions {
+	return &fakeTransactions{rows: map[uuid.UUID]*fakeTxn{}}
+}
+
+// byKey finds the wallet's transaction with the idempotency key; keys are unique per wallet.
+func (f *fakeTransactions) byKey(walletID uuid.UUID, key string) (uuid.UUID, *fakeTxn) {
+	for id, t := range f.rows {
+		if key != "" && t.walletID == walletID && t.key == key {
+			return id, t
+		}
+	}
+	return uuid.Nil, nil
+}
+
+func (f *fakeTransactions) SumOutboundSpend(context.Context, uuid.UUID, time.Time, time.Time) (*repository.OutboundSpend, error) {
+	out := f.spend
+	return &out, nil
+}
+
+func (f *fakeTransactions) GetByIdempotencyKey(_ context.Context, walletID uuid.UUID, key string) (uuid.UUID, string, error) {
+	id, t := f.byKey(walletID, key)
+	if t == nil {
+		return uuid.Nil, "", nil
+	}
+	return id, t.status, nil
+}
+
+func (f *fakeTransactions) GetTransferByIdempotencyKey(_ context.Context, walletID uuid.UUID, key string) (*repository.IdempotentTransfer, error) {
+	id, t := f.byKey(walletID, key)
+	if t == nil {
+		return nil, nil
+	}
+	bank, account, _ := strings.Cut(t.beneficiary, "/")
+	return &repository.IdempotentTransfer{ID: id, Status: t.status, Type: t.typ, Amount: t.amount, BeneficiaryBank: bank, BeneficiaryAcctHash: crypto.FieldHash(account)}, nil
+}
+
+func (f *fakeTransactions) GetRefAndProviderRefByID(_ context.Context, id uuid.UUID) (string, string, error) {
+	t, ok := f.rows[id]
+	if !ok {
+		return "", "", fmt.Errorf("transaction %s not found", id)
+	}
+	return t.ref, t.providerRef, nil
+}
+
+func (f *fakeTransactions) CreateTransferWithIdempotency(_ context.Context, p *repository.CreateTransferParams) (uuid.UUID, string, bool, error) {
+	if id, t := f.byKey(p.WalletID, p.IdempotencyKey); t != nil {
+		return id, t.status, false, nil
+	}
+	if p.Guard != nil {
+		spend := repository.OutboundSpend{
+			DailyTransfers: f.spend.DailyTransfers + f.raced.DailyTransfers,
+			DailyBills:     f.spend.DailyBills + f.raced.DailyBills,
+			Monthly:        f.spend.Monthly + f.raced.Monthly,
+		}
+		if err := p.Guard.Check(&spend); err != nil {
+			return uuid.Nil, "", false, err
+		}
+	}
+	id := uuid.New()
+	f.rows[id] = &fakeTxn{walletID: p.WalletID, ref: p.TransactionRef, status: "PENDING", typ: "OUTBOUND_TRANSFER", amount: p.Amount,
+		beneficiary: p.BeneficiaryBank + "/" + p.BeneficiaryAcct, key: p.IdempotencyKey}
+	return id, "", true, nil
+}
+
+func (f *fakeTransactions) UpdateTransferAfterAPI(_ context.Context, id uuid.UUID, status, providerRef string, _ []byte, _ string) error {
+	t, ok := f.rows[id]
+	if !ok {
+		return fmt.Errorf("transaction %s not found", id)
+	}
+	t.status, t.providerRef = status, providerRef
+	return nil
+}
+
+func (f *fakeTransactions) CreateInboundCreditAndPostLedger(_ context.Context, p *repository.CreateInboundCreditParams) (uuid.UUID, error) {
+	id := uuid.New()
+	f.rows[id] = &fakeTxn{walletID: p.WalletID, ref: p.TransactionRef, providerRef: p.ProviderRef, status: "SUCCESS", amount: p.Amount}
+	f.ledger = append(f.ledger, id)
+	return id, nil
+}
+
+func (f *fakeTransactions) CreateInternalDebitCreditAndPostLedger(_ context.Context, p *repository.CreateInternalDebitCreditParams) (uuid.UUID, error) {
+	if err := f.ledgerErr; err != nil {
+		f.ledgerErr = nil
+		return uuid.Nil, err
+	}
+	if _, t := f.byKey(p.WalletID, p.IdempotencyKey); t != nil {
+		return uuid.Nil, fmt.Errorf("duplicate key value violates unique constraint \"ux_transactions_wallet_idempotency\"")
+	}
+	id := uuid.New()
+	f.rows[id] = &fakeTxn{walletID: p.WalletID, ref: p.TransactionRef, providerRef: p.ProviderRef, status: "SUCCESS", typ: p.Type, amount: p.Amount, key: p.IdempotencyKey}
+	f.ledger = append(f.ledger, id)
+	return id, nil
+}
+
+func (f *fakeTransactions) PostLedgerEntryAfterSync(_ context.Context, id, _ uuid.UUID, _ float64, _ string, _, _ float64) (uuid.UUID, error) {
+	f.ledger = append(f.ledger, id)
+	return uuid.New(), nil
+}
+
+// fakePockets holds the pockets of the fixture's wallet and mimics post_pocket_transfer and the interest payout SQL.
+type fakePockets struct {
+	pocketStore
+	pockets     map[uuid.UUID]*repository.PocketRow
+	main        float64 // the wallet's main (spendable) balance
+	payouts     []*fakePayout
+	completeErr error // fails the next CompleteInterestPayout
+}
+
+type fakePayout struct {
+	repository.PocketInterestPayout
+	status string
+}
+
+func (f *fakePockets) GetByRef(_ context.Context, userID uuid.UUID, ref string) (*repository.PocketRow, error) {
+	for _, p := range f.pockets {
+		if p.UserID == userID && p.PocketRef == ref {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakePockets) SumBalancesByWallet(_ context.Context, walletID uuid.UUID) (float64, error) {
+	var sum float64
+	for _, p := range f.pockets {
+		if p.WalletID == walletID && p.Status == repository.PocketStatusActive {
+			sum += p.Balance
+		}
+	}
+	return sum, nil
+}
+
+func (f *fakePockets) ListAutosave(context.Context, uuid.UUID, bool) ([]repository.PocketRow, error) {
+	return nil, nil
+}
+
+func (f *fakePockets) Transfer(_ context.Context, p *repository.PocketTransferParams) (uuid.UUID, error) {
+	pocket := f.pockets[p.PocketID]
+	switch {
+	case pocket.Status != repository.PocketStatusActive:
+		return uuid.Nil, repository.ErrPocketClosed
+	case p.ToPocket && f.main < p.Amount:
+		return uuid.Nil, fmt.Errorf("post_ledger_entry: Insufficient balance")
+	case !p.ToPocket && !p.IgnoreLock && pocket.Locked(time.Now()):
+		return uuid.Nil, repository.ErrPocketLocked
+	case !p.ToPocket && pocket.Balance < p.Amount:
+		return uuid.Nil, repository.ErrPocketInsufficientBalance
+	}
+	if p.ToPocket {
+		f.main, pocket.Balance = f.main-p.Amount, pocket.Balance+p.Amount
+	} else {
+		f.main, pocket.Balance = f.main+p.Amount, pocket.Balance-p.Amount
+	}
+	return uuid.New(), nil
+}
+
+func (f *fakePockets) AccrueInterest(_ context.Context, day time.Time, dailyRate func(p *repository.PocketRow) float64) (int, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	n := 0
+	for _, p := range f.pockets {
+		if p.Status != repository.PocketStatusActive || p.Balance <= 0 || (p.InterestAccruedThrough != nil && !p.InterestAccruedThrough.Before(day)) {
+			continue
+		}
+		if rate := dailyRate(p); rate > 0 {
+			p.InterestAccrued += math.Round(p.Balance*rate*10000) / 10000
+			d := day
+			p.InterestAccruedThrough = &d
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakePockets) ReserveInterestPayouts(_ context.Context, period string) (int, error) {
+	n := 0
+	for _, p := range f.pockets {
+		amount := math.Floor(p.InterestAccrued*100) / 100
+		if amount < 0.01 || f.payoutFor(p.ID, period) != nil {
+			continue
+		}
+		p.InterestAccrued -= amount
+		f.payouts = append(f.payouts, &fakePayout{status: "PENDING", PocketInterestPayout: repository.PocketInterestPayout{
+			ID: uuid.New(), PocketID: p.ID, WalletID: p.WalletID, UserID: p.UserID, PocketRef: p.PocketRef, PocketName: p.Name,
+			PocketStatus: p.Status, Period: period, Amount: amount, TransactionRef: "INT" + p.PocketRef + strings.ReplaceAll(period, "-", ""),
+		}})
+		n++
+	}
+	return n, nil
+}
+
+func (f *fakePockets) payoutFor(pocketID uuid.UUID, period string) *fakePayout {
+	for _, x := range f.payouts {
+		if x.PocketID == pocketID && x.Period == period {
+			return x
+		}
+	}
+	return nil
+}
+
+func (f *fakePockets) ListPendingInterestPayouts(context.Context, int) ([]repository.PocketInterestPayout, error) {
+	var list []repository.PocketInterestPayout
+	for _, x := range f.payouts {
+		if x.status == "PENDING" {
+			list = append(list, x.PocketInterestPayout)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakePockets) CompleteInterestPayout(_ context.Context, x *repository.PocketInterestPayout, _ string) error {
+	if err := f.completeErr; err != nil {
+		f.completeErr = nil
+		return err
+	}
+	payout := f.payoutFor(x.PocketID, x.Period)
+	if payout.status != "PENDING" {
+		return nil
+	}
+	pocket := f.pockets[x.PocketID]
+	if pocket.Status == repository.PocketStatusActive {
+		pocket.Balance += x.Amount
+	} else {
+		f.main += x.Amount
+	}
+	pocket.InterestPaid += x.Amount
+	payout.status = "PAID"
+	return nil
+}
+
+// fakeBills is bill_payments with their transactions, unique on (wallet, idempotency key) like the transactions index.
+type fakeBills struct {
+	billStore
+	bills map[uuid.UUID]*repository.BillPaymentRow
+	keys  map[uuid.UUID]string // idempotency key by bill id
+}
+
+func newFakeBills() *fakeBills {
+	return &fakeBills{bills: map[uuid.UUID]*repository.BillPaymentRow{}, keys: map[uuid.UUID]string{}}
+}
+
+func (f *fakeBills) CreatePending(_ context.Context, p *repository.CreateBillPaymentParams) (*repository.BillPaymentRow, error) {
+	for id, key := range f.keys {
+		if p.IdempotencyKey != "" && key == p.IdempotencyKey && f.bills[id].WalletID == p.WalletID {
+			return nil, fmt.Errorf("duplicate key value violates unique constraint \"ux_transactions_wallet_idempotency\"")
+		}
+	}
+	b := &repository.BillPaymentRow{
+		ID: uuid.New(), BillRef: p.BillRef, UserID: p.UserID, WalletID: p.WalletID, TransactionID: uuid.New(), TransactionStatus: "PENDING",
+		Provider: p.Provider, Category: p.Category, BillerCode: p.BillerCode, BillerName: p.BillerName, ItemCode: p.ItemCode,
+		ItemName: p.ItemName, CustomerID: p.CustomerID, Phone: p.Phone, Amount: p.Amount, Status: repository.BillStatusPending,
+		DeliveryChannel: p.DeliveryChannel,
+	}
+	f.bills[b.ID], f.keys[b.ID] = b, p.IdempotencyKey
+	cp := *b
+	return &cp, nil
+}
+
+func (f *fakeBills) GetByID(_ context.Context, id uuid.UUID) (*repository.BillPaymentRow, error) {
+	b, ok := f.bills[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (f *fakeBills) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*repository.BillPaymentRow, error) {
+	for id, k := range f.keys {
+		if k == key && f.bills[id].UserID == userID {
+			return f.GetByID(ctx, id)
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeBills) MarkDebited(_ context.Context, b *repository.BillPaymentRow, debitRef, _ string) error {
+	f.bills[b.ID].DebitRef = debitRef
+	return nil
+}
+
+func (f *fakeBills) MarkSuccess(_ context.Context, b *repository.BillPaymentRow, providerRef, token, units string) error {
+	row := f.bills[b.ID]
+	row.Status, row.TransactionStatus, row.ProviderRef, row.Token, row.Units = repository.BillStatusSuccess, "SUCCESS", providerRef, token, units
+	return nil
+}
+
+func (f *fakeBills) MarkFailed(_ context.Context, b *repository.BillPaymentRow, reason string) error {
+	row := f.bills[b.ID]
+	row.Status, row.TransactionStatus, row.FailureReason = repository.BillStatusFailed, "FAILED", reason
+	return nil
+}
+
+func (f *fakeBills) Reverse(_ context.Context, b *repository.BillPaymentRow, _, _, reason string) error {
+	row := f.bills[b.ID]
+	row.Status, row.TransactionStatus, row.FailureReason = repository.BillStatusReversed, "REVERSED", reason
+	return nil
+}
+
+// fakeDisputes is transaction_disputes, with ClaimResolution's compare-and-set on the status.
+type fakeDisputes struct {
+	disputeStore
+	rows  map[uuid.UUID]*repository.DisputeRow
+	notes []string
+	// beforeClaim runs inside the next ClaimResolution before the status is compared, as a concurrent resolve would
+	beforeClaim func()
+}
+
+func (f *fakeDisputes) GetByID(_ context.Context, id uuid.UUID) (*repository.DisputeRow, error) {
+	d, ok := f.rows[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *d
+	return &cp, nil
+}
+
+func (f *fakeDisputes) ClaimResolution(_ context.Context, id uuid.UUID, fromStatus, toStatus, resolution string, amount float64, isCredit bool, note, resolvedBy string) error {
+	if hook := f.beforeClaim; hook != nil {
+		f.beforeClaim = nil
+		hook()
+	}
+	d := f.rows[id]
+	if d.Status != fromStatus {
+		return repository.ErrDisputeStatusConflict
+	}
+	d.Status, d.Resolution, d.ResolutionAmount, d.ResolutionIsCredit, d.ResolutionNote, d.ResolvedBy = toStatus, resolution, amount, isCredit, note, resolvedBy
+	return nil
+}
+
+func (f *fakeDisputes) ReleaseResolution(_ context.Context, id uuid.UUID, fromStatus string) error {
+	d := f.rows[id]
+	if d.Status == repository.DisputeStatusPaymentPending && d.ResolutionTxnRef == "" {
+		d.Status, d.Resolution, d.ResolutionAmount, d.ResolutionIsCredit, d.ResolutionNote, d.ResolvedBy = fromStatus, "", 0, false, "", ""
+	}
+	return nil
+}
+
+func (f *fakeDisputes) CompleteResolution(_ context.Context, id uuid.UUID, txnRef string) error {
+	d := f.rows[id]
+	if d.Status != repository.DisputeStatusPaymentPending {
+		return repository.ErrDisputeStatusConflict
+	}
+	d.Status, d.ResolutionTxnRef = repository.DisputeStatusResolved, txnRef
+	return nil
+}
+
+func (f *fakeDisputes) AddNote(_ context.Context, disputeID uuid.UUID, authorType, authorID, body string, internal bool) (*repository.DisputeNoteRow, error) {
+	f.notes = append(f.notes, body)
+	return &repository.DisputeNoteRow{Body: body}, nil
+}
+
+// fakePaymentRequests is payment_requests with the in-app payment claim (pay_claimed_at) that ClaimForPayment takes.
+type fakePaymentRequests struct {
+	paymentRequestStore
+	rows    map[string]*repository.PaymentRequestRow // by request ref
+	claimed map[string]bool
+}
+
+func (f *fakePaymentRequests) GetByRef(_ context.Context, ref string) (*repository.PaymentRequestRow, error) {
+	r, ok := f.rows[ref]
+	if !ok {
+		return nil, nil
+	}
+	cp := *r
+	return &cp, nil
+}
+
+func (f *fakePaymentRequests) ClaimForPayment(_ context.Context, ref string) error {
+	if f.rows[ref].Status != repository.PaymentRequestStatusPending || f.claimed[ref] {
+		return repository.ErrPaymentRequestNotPending
+	}
+	f.claimed[ref] = true
+	return nil
+}
+
+func (f *fakePaymentRequests) ReleaseClaim(_ context.Context, ref string) error {
+	delete(f.claimed, ref)
+	return nil
+}
+
+func (f *fakePaymentRequests) MarkPaid(_ context.Context, ref string, paidBy uuid.UUID, amount float64, txnRef, via string) error {
+	r := f.rows[ref]
+	if r.Status != repository.PaymentRequestStatusPending || !f.claimed[ref] {
+		return repository.ErrPaymentRequestNotPending
+	}
+	r.Status, r.PaidByUserID, r.PaidAmount, r.PaidTxnRef, r.PaidVia = repository.PaymentRequestStatusPaid, paidBy.String(), amount, txnRef, via
+	delete(f.claimed, ref)
+	return nil
+}
+
+// fakeWebhookEvents is webhook_events, deduplicating transfer events by provider ref as the unique index does.
+type fakeWebhookEvents struct {
+	webhookEventStore
+	byRef  map[string]uuid.UUID
+	status map[uuid.UUID]string
+}
+
+func newFakeWebhookEvents() *fakeWebhookEvents {
+	return &fakeWebhookEvents{byRef: map[string]uuid.UUID{}, status: map[uuid.UUID]string{}}
+}
+
+func (f *fakeWebhookEvents) InsertTransferEvent(_ context.Context, _, providerRef, _ string, _ float64, _ []byte) (uuid.UUID, bool, error) {
+	if id, ok := f.byRef[providerRef]; ok {
+		return id, true, nil
+	}
+	id := uuid.New()
+	f.byRef[providerRef], f.status[id] = id, "RECEIVED"
+	return id, false, nil
+}
+
+func (f *fakeWebhookEvents) MarkProcessed(_ context.Context, id uuid.UUID) error {
+	f.status[id] = "PROCESSED"
+	return nil
+}
+
+func (f *fakeWebhookEvents) MarkFailed(_ context.Context, id uuid.UUID, _ string) error {
+	f.status[id] = "FAILED"
+	return nil
+}
+
+// fakePublisher is the Kafka producer. Audit logs are dropped; wallet-events are kept in order.
+type fakePublisher struct {
+	walletEvents []interface{}
+}
+
+func (f *fakePublisher) SendAuditLog(kafka.AuditLogParams) error { return nil }
+
+func (f *fakePublisher) PublishWalletCreated(_ context.Context, userID string) error {
+	f.walletEvents = append(f.walletEvents, kafka.WalletCreatedEvent{EventType: "wallet_created", UserID: userID})
+	return nil
+}
+
+func (f *fakePublisher) PublishTransferSuccess(_ context.Context, userID, transactionRef string, amount float64) error {
+	f.walletEvents = append(f.walletEvents, kafka.TransferSuccessEvent{EventType: "transfer_success", UserID: userID, TransactionRef: transactionRef, Amount: amount})
+	return nil
+}
+
+func (f *fakePublisher) PublishTransferFailed(_ context.Context, userID, transactionRef string, amount float64, responseCode string) error {
+	f.walletEvents = append(f.walletEvents, kafka.TransferFailedEvent{EventType: "transfer_failed", UserID: userID, TransactionRef: transactionRef, Amount: amount, ResponseCode: responseCode})
+	return nil
+}
+
+// fakeUsers is the user service. It approves every payment and records the payee each approval was asked for.
+type fakeUsers struct {
+	userDirectory
+	payees []string // "bank/account" per ValidateTransfer call
+}
+
+func (f *fakeUsers) ValidateTransfer(_ context.Context, _ string, _ float64, _, _, _, bank, account string) (*userpb.ValidateTransferResponse, error) {
+	f.payees = append(f.payees, bank+"/"+account)
+	return &userpb.ValidateTransferResponse{Allowed: true}, nil
+}
+
+func (f *fakeUsers) GetUserForKYC(context.Context, string) (*userpb.GetUserForKYCResponse, error) {
+	return &userpb.GetUserForKYCResponse{}, nil
+}
+
+// fakeBank is 9PSB: account balances and names, and the transfers it was asked to make.
+type fakeBank struct {
+	bankProvider
+	balances    map[string]float64
+	names       map[string]string // "bank/account" for other banks, account alone for 9PSB wallets
+	transferErr error
+	transfers   []*psb.WalletOtherBanksPayload
+	history     map[string][]psb.WaasWalletTransactionItem // wallet_transactions by account
+	creditErr   error                                      // fails the next WaasCreditTransfer
+	credits     map[string]float64                         // WaaS credits by transactionId
+}
+
+func (f *fakeBank) WaasCreditTransfer(_ context.Context, accountNo, _ string, amount float64, transactionID string) (string, error) {
+	if err := f.creditErr; err != nil {
+		f.creditErr = nil
+		return "", err
+	}
+	if _, ok := f.credits[transactionID]; ok {
+		return "", fmt.Errorf("9PSB WaaS credit_transfer: Duplicate transaction")
+	}
+	f.credits[transactionID] = amount
+	f.balances[accountNo] += amount
+	return "CR" + transactionID, nil
+}
+
+func (f *fakeBank) WaasDebitTransfer(_ context.Context, accountNo, _ string, amount float64, transactionID string) (string, error) {
+	if f.balances[accountNo] < amount {
+		return "", fmt.Errorf("9PSB WaaS debit_transfer: Insufficient balance")
+	}
+	f.balances[accountNo] -= amount
+	return "DR" + transactionID, nil
+}
+
+func (f *fakeBank) WaasWalletTransactions(_ context.Context, accountNumber, _, _, _ string) (*psb.WaasWalletTransactionsResponse, error) {
+	out := &psb.WaasWalletTransactionsResponse{Status: "SUCCESS"}
+	out.Data.Successful = true
+	out.Data.Message = f.history[accountNumber]
+	return out, nil
+}
+
+func (f *fakeBank) WalletEnquiry(_ context.Context, accountNo string) (*psb.WalletEnquiryResult, error) {
+	name, ok := f.names[accountNo]
+	if !ok {
+		return nil, fmt.Errorf("account %s not found", accountNo)
+	}
+	bal := f.balances[accountNo]
+	return &psb.WalletEnquiryResult{AvailableBalance: bal, LedgerBalance: bal, Nuban: accountNo, Name: name, IsSuccessful: true}, nil
+}
+
+func (f *fakeBank) OtherBanksEnquiry(_ context.Context, bankCode, accountNumber string) (string, error) {
+	name, ok := f.names[bankCode+"/"+accountNumber]
+	if !ok {
+		return "", fmt.Errorf("account %s at %s not found", accountNumber, bankCode)
+	}
+	return name, nil
+}
+
+func (f *fakeBank) WalletOtherBanks(_ context.Context, p *psb.WalletOtherBanksPayload) ([]byte, string, string, error) {
+	if f.transferErr != nil {
+		return []byte(`{"status":"FAILED"}`), "", "99", f.transferErr
+	}
+	f.transfers = append(f.transfers, p)
+	var amount float64
+	fmt.Sscan(p.Order.Amount, &amount)
+	f.balances[p.Customer.Account.SenderAccountNumber] -= amount
+	return []byte(`{"status":"SUCCESS"}`), fmt.Sprintf("SESSION%d", len(f.transfers)), "00", nil
+}
+
+// testFixture is a service with one tier 1 wallet holding NGN 20,000 and a beneficiary at another bank.
+type testFixture struct {
+	svc       *PaymentService
+	userID    uuid.UUID
+	wallet    *repository.ActiveWalletForTransfer
+	txns      *fakeTransactions
+	events    *fakeWebhookEvents
+	bank      *fakeBank
+	published *fakePublisher
+}
+
+const (
+	testAccount         = "1100000001"
+	testBeneficiaryBank = "000013"
+	testBeneficiaryAcct = "0123456789"
+	testBeneficiaryName = "Ada Obi"
+	testOpeningBalance  = 20000
+)
+
+func newTestFixture() *testFixture {
+	userID := uuid.New()
+	wallet := &repository.ActiveWalletForTransfer{WalletID: uuid.New(), AccountNumber: testAccount, FullName: "Musa Bello", Tier: "1", AvailableBalance: testOpeningBalance}
+	txns := newFakeTransactions()
+	events := newFakeWebhookEvents()
+	published := &fakePublisher{}
+	bank := &fakeBank{
+		balances: map[string]float64{testAccount: testOpeningBalance},
+		names:    map[string]string{testAccount: "Musa Bello", testBeneficiaryBank + "/" + testBeneficiaryAcct: testBeneficiaryName},
+		history:  map[string][]psb.WaasWalletTransactionItem{},
+		credits:  map[string]float64{},
+	}
+	svc := &PaymentService{
+		walletRepo:        &fakeWallets{byUser: map[uuid.UUID]*repository.ActiveWalletForTransfer{userID: wallet}},
+		transactionRepo:   txns,
+		webhookEventsRepo: events,
+		psbProvider:       bank,
+		audit:             published,
+		limits:            limits.DefaultPolicy(),
+	}
+	return &testFixture{svc: svc, userID: userID, wallet: wallet, txns: txns, events: events, bank: bank, published: published}
+}
+
+// addUser gives the fixture a second user with a tier 1 wallet holding the opening balance at account.
+func (f *testFixture) addUser(account string) (uuid.UUID, *repository.ActiveWalletForTransfer) {
+	userID := uuid.New()
+	wallet := &repository.ActiveWalletForTransfer{WalletID: uuid.New(), AccountNumber: account, FullName: "Chidi Okafor", Tier: "1", AvailableBalance: testOpeningBalance}
+	f.svc.walletRepo.(*fakeWallets).byUser[userID] = wallet
+	f.bank.balances[account], f.bank.names[account] = testOpeningBalance, wallet.FullName
+	return userID, wallet
+}
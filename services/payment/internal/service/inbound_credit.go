@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+)
+
+// ErrInboundCreditUnconfirmed is returned when the wallet's 9PSB history does not (yet) show the credit a notification
+// claims, or cannot be read. Nothing is recorded, so 9PSB's retry is processed normally.
+var ErrInboundCreditUnconfirmed = errors.New("inbound credit not confirmed by 9PSB")
+
+// InboundCreditParams is a credit notification from 9PSB: money received into a PayUp wallet by bank transfer.
+type InboundCreditParams struct {
+	AccountNumber string // credited wallet
+	Amount        float64
+	SessionID     string // 9PSB session ID; deduplication key
+	Narration     string
+	SenderAccount string
+	SenderName    string
+	RawPayload    []byte
+}
+
+// InboundCreditResult reports what happened to a credit notification.
+type InboundCreditResult struct {
+	Duplicate      bool
+	TransactionRef string
+}
+
+// ProcessInboundCredit records an inbound transfer: confirms it against the wallet's 9PSB history, logs the webhook
+// (deduplicated by session ID), posts a CREDIT to the wallet, notifies the owner (also when the balance passes the tier
+// maximum), settles a matching payment request and applies auto-save rules. A repeated notification is acknowledged
+// without effect.
+func (s *PaymentService) ProcessInboundCredit(ctx context.Context, p *InboundCreditParams) (*InboundCreditResult, error) {
+	if s.webhookEventsRepo == nil || s.transactionRepo == nil || s.psbProvider == nil {
+		return nil, fmt.Errorf("inbound credits not configured")
+	}
+	if p.SessionID == "" || p.AccountNumber == "" || p.Amount <= 0 {
+		return nil, fmt.Errorf("session id, account number and positive amount are required")
+	}
+	// Before the dedupe insert: a notification that is refused here must not mark its session ID as seen.
+	if err := s.confirmInboundCredit(ctx, p); err != nil {
+		return nil, err
+	}
+	eventID, duplicate, err := s.webhookEventsRepo.InsertTransferEvent(ctx, "SUCCESS", p.SessionID, crypto.FieldHash(p.AccountNumber), p.Amount, p.RawPayload)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		return &InboundCreditResult{Duplicate: true}, nil
+	}
+	wallet, err := s.walletRepo.GetActiveByAccountNumber(ctx, p.AccountNumber)
+	if err != nil {
+		_ = s.webhookEventsRepo.MarkFailed(ctx, eventID, err.Error())
+		return nil, err
+	}
+	if wallet == nil {
+		_ = s.webhookEventsRepo.MarkFailed(ctx, eventID, "no active wallet for account")
+		return nil, fmt.Errorf("no active wallet for account")
+	}
+	narration := strings.TrimSpace(p.Narration)
+	if narration == "" {
+		narration = "Transfer from " + nonBlank(p.SenderName, "bank account")
+	}
+	if len(narration) > 255 {
+		narration = narration[:255]
+	}
+	txnRef := generateTrackingRef("INB")
+	txnID, err := s.transactionRepo.CreateInboundCreditAndPostLedger(ctx, &repository.CreateInboundCreditParams{
+		WalletID:       wallet.WalletID,
+		TransactionRef: txnRef,
+		ProviderRef:    p.SessionID,
+		Amount:         p.Amount,
+		Narration:      narration,
+		SenderAccount:  p.SenderAccount,
+	})
+	if err != nil {
+		_ = s.webhookEventsRepo.MarkFailed(ctx, eventID, err.Error())
+		return nil, err
+	}
+	_ = s.webhookEventsRepo.MarkProcessed(ctx, eventID)
+
+	userID := wallet.UserID.String()
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Action:   "wallet_inbound_credit",
+		Entity:   "transaction",
+		EntityID: txnID.String(),
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"amount": p.Amount, "transaction_ref": txnRef, "provider_ref": p.SessionID},
+	})
+	data := map[string]interface{}{"amount": p.Amount, "narration": narration, "transaction_ref": txnRef}
+	_ = s.SendNotification(kafka.NotificationEvent{UserID: userID, Type: "wallet_credit", Channel: "email", Data: data})
+	s.sendPushAlert(userID, "wallet_credit", data)
+	s.flagBalanceAboveMaximum(ctx, wallet.UserID, p.Amount)
+	s.matchInboundCreditToPaymentRequest(ctx, wallet.WalletID, p.Amount, narration, txnRef)
+	s.autosaveInboundCredit(ctx, wallet.WalletID, p.Amount)
+	return &InboundCreditResult{TransactionRef: txnRef}, nil
+}
+
+// confirmInboundCredit requeries the credit: the wallet's 9PSB history for yesterday and today (a transfer can land
+// just before midnight) must hold a credit with the notification's session ID and amount that was not reversed.
+func (s *PaymentService) confirmInboundCredit(ctx context.Context, p *InboundCreditParams) error {
+	now := time.Now()
+	history, err := s.psbProvider.WaasWalletTransactions(ctx, p.AccountNumber, now.AddDate(0, 0, -1).Format("2006-01-02"), now.Format("2006-01-02"), "100")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInboundCreditUnconfirmed, err)
+	}
+	for i := range history.Data.Message {
+		item := &history.Data.Message[i]
+		if item.ReferenceID != p.SessionID && item.UniqueIdentifier != p.SessionID {
+			continue
+		}
+		if !item.IsCredit() || item.IsReversed {
+			return fmt.Errorf("%w: session %s is not a standing credit", ErrInboundCreditUnconfirmed, p.SessionID)
+		}
+		if math.Abs(item.Amount-p.Amount) >= 0.005 {
+			return fmt.Errorf("%w: 9PSB credited %.2f, notification says %.2f", ErrInboundCreditUnconfirmed, item.Amount, p.Amount)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: session %s not in wallet history", ErrInboundCreditUnconfirmed, p.SessionID)
+}
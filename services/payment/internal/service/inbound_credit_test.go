@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
+)
+
+// settle puts a credit into the wallet's 9PSB history, as 9PSB does before it sends the notification.
+func (f *testFixture) settle(sessionID string, amount float64) {
+	f.bank.history[testAccount] = append(f.bank.history[testAccount], psb.WaasWalletTransactionItem{
+		Amount: amount, ReferenceID: sessionID, PostingType: "C", Credit: "credit",
+	})
+}
+
+func (f *testFixture) inboundCredit(sessionID string, amount float64) *InboundCreditParams {
+	return &InboundCreditParams{AccountNumber: testAccount, Amount: amount, SessionID: sessionID, SenderName: "Ada Obi", RawPayload: []byte(`{}`)}
+}
+
+func TestProcessInboundCredit(t *testing.T) {
+	f := newTestFixture()
+	f.settle("SESSION-1", 5000)
+	res, err := f.svc.ProcessInboundCredit(context.Background(), f.inboundCredit("SESSION-1", 5000))
+	if err != nil {
+		t.Fatalf("credit: %v", err)
+	}
+	if res.Duplicate || res.TransactionRef == "" {
+		t.Fatalf("result = %+v", res)
+	}
+	if len(f.txns.ledger) != 1 {
+		t.Fatalf("ledger entries = %d, want 1", len(f.txns.ledger))
+	}
+	if row := f.txns.rows[f.txns.ledger[0]]; row.amount != 5000 || row.providerRef != "SESSION-1" || row.ref != res.TransactionRef {
+		t.Errorf("credit row = %+v", row)
+	}
+	if st := f.events.status[f.events.byRef["SESSION-1"]]; st != "PROCESSED" {
+		t.Errorf("webhook event status = %q, want PROCESSED", st)
+	}
+
+	again, err := f.svc.ProcessInboundCredit(context.Background(), f.inboundCredit("SESSION-1", 5000))
+	if err != nil || !again.Duplicate {
+		t.Fatalf("replay = %+v, %v; want duplicate", again, err)
+	}
+	if len(f.txns.ledger) != 1 {
+		t.Errorf("replay credited again: %d ledger entries", len(f.txns.ledger))
+	}
+}
+
+func TestProcessInboundCreditUnconfirmed(t *testing.T) {
+	cases := map[string]func(f *testFixture){
+		"not in history": func(f *testFixture) { f.settle("SESSION-OTHER", 5000) },
+		"amount differs": func(f *testFixture) { f.settle("SESSION-1", 50) },
+		"reversed": func(f *testFixture) {
+			f.settle("SESSION-1", 5000)
+			f.bank.history[testAccount][0].IsReversed = true
+		},
+		"a debit": func(f *testFixture) {
+			f.bank.history[testAccount] = []psb.WaasWalletTransactionItem{{Amount: 5000, ReferenceID: "SESSION-1", PostingType: "D"}}
+		},
+	}
+	for name, setup := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := newTestFixture()
+			setup(f)
+			_, err := f.svc.ProcessInboundCredit(context.Background(), f.inboundCredit("SESSION-1", 5000))
+			if !errors.Is(err, ErrInboundCreditUnconfirmed) {
+				t.Fatalf("err = %v, want ErrInboundCreditUnconfirmed", err)
+			}
+			if len(f.txns.ledger) != 0 || len(f.events.byRef) != 0 {
+				t.Errorf("unconfirmed credit left %d ledger entries and %d webhook events", len(f.txns.ledger), len(f.events.byRef))
+			}
+		})
+	}
+}
+
+func TestProcessInboundCreditUnknownWallet(t *testing.T) {
+	f := newTestFixture()
+	f.bank.history["2200000002"] = []psb.WaasWalletTransactionItem{{Amount: 5000, ReferenceID: "SESSION-1", PostingType: "C"}}
+	p := f.inboundCredit("SESSION-1", 5000)
+	p.AccountNumber = "2200000002"
+	if _, err := f.svc.ProcessInboundCredit(context.Background(), p); err == nil {
+		t.Fatal("credit to an unknown wallet accepted")
+	}
+	if st := f.events.status[f.events.byRef["SESSION-1"]]; st != "FAILED" {
+		t.Errorf("webhook event status = %q, want FAILED", st)
+	}
+	if len(f.txns.ledger) != 0 {
+		t.Errorf("ledger entries = %d, want 0", len(f.txns.ledger))
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// WalletLimits is the user-facing view of the tier caps on a wallet and how much of them is used today.
+type WalletLimits struct {
+	Tier             int
+	WalletTier       string
+	Caps             limits.Caps
+	DailySpent       float64
+	DailyRemaining   *float64 // nil when the tier has no daily cap
+	DailyByChannel   map[limits.Channel]float64
+	Balance          float64
+	HeldAboveMaximum float64 // balance above the tier maximum; not spendable until upgrade
+}
+
+// walletTier is the tier whose caps apply to the wallet: its own tier, lowered to the user's KYC level when KYC is
+// behind. If the KYC service cannot be reached the wallet tier is used.
+func (s *PaymentService) walletTier(ctx context.Context, userID, walletTier string) int {
+	level := 0
+	if s.kycClient != nil {
+		l, err := s.kycClient.GetKYCLevel(ctx, userID)
+		if err != nil {
+			log.Printf("payment: kyc level for %s: %v; using wallet tier %s", userID, err, walletTier)
+		}
+		level = l
+	}
+	return limits.EffectiveTier(walletTier, level)
+}
+
+// spendWindows returns the starts of today and of this month (UTC), the windows the spending limits apply to.
+func spendWindows() (dayStart, monthStart time.Time) {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func toSpend(sum *repository.OutboundSpend) limits.Spend {
+	return limits.Spend{
+		Daily:          sum.DailyTransfers + sum.DailyBills,
+		DailyByChannel: map[limits.Channel]float64{limits.ChannelTransfer: sum.DailyTransfers, limits.ChannelBills: sum.DailyBills},
+		Monthly:        sum.Monthly,
+	}
+}
+
+// outboundSpend returns what the wallet has sent today and this month.
+func (s *PaymentService) outboundSpend(ctx context.Context, walletID uuid.UUID) (limits.Spend, error) {
+	dayStart, monthStart := spendWindows()
+	sum, err := s.transactionRepo.SumOutboundSpend(ctx, walletID, dayStart, monthStart)
+	if err != nil {
+		return limits.Spend{}, err
+	}
+	return toSpend(sum), nil
+}
+
+// checkSpendingLimits refuses an outbound payment that breaks the tier caps or the user's own daily/monthly limits.
+// It reads a snapshot of the spend to refuse early; spendGuard repeats the check when the payment is inserted.
+func (s *PaymentService) checkSpendingLimits(ctx context.Context, wallet *repository.ActiveWalletForTransfer, tier int, channel limits.Channel, amount float64, user limits.UserLimits) error {
+	spend, err := s.outboundSpend(ctx, wallet.WalletID)
+	if err != nil {
+		return fmt.Errorf("check limits: %w", err)
+	}
+	return s.limits.Check(limits.Request{Tier: tier, Channel: channel, Amount: amount, Spend: spend, User: user})
+}
+
+// spendGuard is checkSpendingLimits for the repository to run under the wallet lock, against the spend including
+// payments that were inserted since the first check.
+func (s *PaymentService) spendGuard(tier int, channel limits.Channel, amount float64, user limits.UserLimits) *repository.SpendGuard {
+	dayStart, monthStart := spendWindows()
+	return &repository.SpendGuard{
+		DayStart:   dayStart,
+		MonthStart: monthStart,
+		Check: func(sum *repository.OutboundSpend) error {
+			return s.limits.Check(limits.Request{Tier: tier, Channel: channel, Amount: amount, Spend: toSpend(sum), User: user})
+		},
+	}
+}
+
+// checkBalance refuses amount when it is more than the spendable balance: the 9PSB balance less savings pockets and
+// anything above the tier's maximum balance.
+func (s *PaymentService) checkBalance(ctx context.Context, wallet *repository.ActiveWalletForTransfer, tier int, balance, amount float64) error {
+	pockets, err := s.pocketHeld(ctx, wallet.WalletID)
+	if err != nil {
+		return err
+	}
+	held := s.limits.Held(tier, balance)
+	if balance-pockets-held >= amount {
+		return nil
+	}
+	if held > 0 && balance-pockets >= amount {
+		return fmt.Errorf("insufficient balance: %s is above the tier %d maximum balance of %s and is on hold until you upgrade your wallet",
+			limits.Naira(held), tier, limits.Naira(s.limits.Caps(tier).MaxBalance))
+	}
+	return fmt.Errorf("insufficient balance")
+}
+
+// GetWalletLimits returns the tier caps that apply to the user's wallet and today's usage.
+func (s *PaymentService) GetWalletLimits(ctx context.Context, userID string) (*WalletLimits, error) {
+	wallet, _, err := s.activeWallet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tier := s.walletTier(ctx, userID, wallet.Tier)
+	spend, err := s.outboundSpend(ctx, wallet.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	caps := s.limits.Caps(tier)
+	out := &WalletLimits{
+		Tier:             tier,
+		WalletTier:       wallet.Tier,
+		Caps:             caps,
+		DailySpent:       spend.Daily,
+		DailyByChannel:   spend.DailyByChannel,
+		Balance:          wallet.AvailableBalance,
+		HeldAboveMaximum: s.limits.Held(tier, wallet.AvailableBalance),
+	}
+	if caps.Daily > 0 {
+		remaining := caps.Daily - spend.Daily
+		if remaining < 0 {
+			remaining = 0
+		}
+		out.DailyRemaining = &remaining
+	}
+	return out, nil
+}
+
+// flagBalanceAboveMaximum tells the owner when an inbound credit took the wallet past its tier's maximum balance. The
+// credit stands, but the excess cannot be spent until the wallet is upgraded.
+func (s *PaymentService) flagBalanceAboveMaximum(ctx context.Context, userID uuid.UUID, credited float64) {
+	wallet, err := s.walletRepo.GetActiveByUserID(ctx, userID)
+	if err != nil || wallet == nil {
+		return
+	}
+	tier := s.walletTier(ctx, userID.String(), wallet.Tier)
+	held := s.limits.Held(tier, wallet.AvailableBalance)
+	if held <= 0 {
+		return
+	}
+	uid := userID.String()
+	max := s.limits.Caps(tier).MaxBalance
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Action:   "wallet_max_balance_exceeded",
+		Entity:   "wallet",
+		EntityID: wallet.WalletID.String(),
+		UserID:   &uid,
+		Metadata: map[string]interface{}{"tier": tier, "max_balance": max, "balance": wallet.AvailableBalance, "held": held, "credited": credited},
+	})
+	_ = s.SendNotification(kafka.NotificationEvent{
+		UserID:  uid,
+		Type:    "wallet_max_balance_exceeded",
+		Channel: "email",
+		Data: map[string]interface{}{
+			"balance":     wallet.AvailableBalance,
+			"max_balance": max,
+			"tier":        tier,
+			"held":        held,
+		},
+	})
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Payment request lifetimes. Requests expire after DefaultPaymentRequestTTL unless the requester picks another duration.
+const (
+	DefaultPaymentRequestTTL = 7 * 24 * time.Hour
+	MaxPaymentRequestTTL     = 30 * 24 * time.Hour
+)
+
+// psbBankCode is 9PSB's NIP code; every PayUp wallet is a 9PSB account.
+const psbBankCode = "120001"
+
+const psbBankName = "9PSB"
+
+var (
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	ErrPaymentRequestExpired  = errors.New("payment request has expired")
+)
+
+// requestRefPattern finds a payment request ref (see repository.NewPaymentRequestRef) inside a bank transfer narration.
+var requestRefPattern = regexp.MustCompile(`\bPR[2-9A-HJ-NP-Z]{8}\b`)
+
+// CreatePaymentRequestParams are the inputs for requesting money.
+type CreatePaymentRequestParams struct {
+	UserID             string
+	Amount             float64
+	Note               string
+	PayerAccountNumber string        // PayUp wallet account number of the payer; empty for an open pay link
+	ExpiresIn          time.Duration // 0 = DefaultPaymentRequestTTL
+}
+
+// PayPaymentRequestParams are the inputs for paying a request in-app.
+type PayPaymentRequestParams struct {
+	UserID           string
+	RequestRef       string
+	Pin              string
+	StepUpToken      string
+	PasskeyAssertion string
+	IdempotencyKey   string
+}
+
+// PayLink is the public view of a payment request: who to pay, how much, and the bank details for funding by transfer.
+type PayLink struct {
+	RequestRef    string
+	RequesterName string
+	AccountNumber string
+	BankName      string
+	BankCode      string
+	Amount        float64
+	Note          string
+	Status        string
+	ExpiresAt     time.Time
+}
+
+// CreatePaymentRequest creates a PENDING request. With a payer account number the request is addressed to that PayUp user
+// (who is notified); without one it is an open pay link anyone can settle in-app or by bank transfer.
+func (s *PaymentService) CreatePaymentRequest(ctx context.Context, p *CreatePaymentRequestParams) (*repository.PaymentRequestRow, error) {
+	if p.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	ttl := p.ExpiresIn
+	if ttl <= 0 {
+		ttl = DefaultPaymentRequestTTL
+	}
+	if ttl > MaxPaymentRequestTTL {
+		return nil, fmt.Errorf("expiry cannot exceed %d days", int(MaxPaymentRequestTTL.Hours()/24))
+	}
+	uid, err := uuid.Parse(p.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	wallet, err := s.walletRepo.GetActiveByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if wallet == nil {
+		return nil, fmt.Errorf("no active wallet")
+	}
+	params := &repository.CreatePaymentRequestParams{
+		RequesterUserID:   uid,
+		RequesterWalletID: wallet.WalletID,
+		Amount:            p.Amount,
+		Note:              strings.TrimSpace(p.Note),
+		ExpiresAt:         time.Now().Add(ttl),
+	}
+	if acct := strings.TrimSpace(p.PayerAccountNumber); acct != "" {
+		payer, err := s.walletRepo.GetActiveByAccountNumber(ctx, acct)
+		if err != nil {
+			return nil, err
+		}
+		if payer == nil {
+			return nil, fmt.Errorf("payer account not found")
+		}
+		if payer.UserID == uid {
+			return nil, fmt.Errorf("cannot request money from yourself")
+		}
+		params.PayerUserID = payer.UserID
+		params.PayerWalletID = payer.WalletID
+	}
+	req, err := s.paymentRequestRepo.Create(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	s.auditPaymentRequest(req, "payment_request_created", p.UserID, map[string]interface{}{
+		"amount": req.Amount, "open_link": req.PayerUserID == "",
+	})
+	if req.PayerUserID != "" {
+		s.notifyPaymentRequest(req, req.PayerUserID, "payment_request_received")
+	}
+	return req, nil
+}
+
+// ListMyPaymentRequests returns requests the user sent (role "sent", default) or received (role "received"), newest first.
+func (s *PaymentService) ListMyPaymentRequests(ctx context.Context, userID, role, status string, limit, offset int) ([]repository.PaymentRequestRow, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	if role != "received" {
+		role = "sent"
+	}
+	return s.paymentRequestRepo.ListForUser(ctx, uid, role, strings.ToUpper(strings.TrimSpace(status)), limit, offset)
+}
+
+// GetMyPaymentRequest returns a request the user sent or was asked to pay. Open links are visible to their requester only;
+// anyone else uses the public pay link.
+func (s *PaymentService) GetMyPaymentRequest(ctx context.Context, userID, ref string) (*repository.PaymentRequestRow, error) {
+	req, err := s.getPaymentRequest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if req.RequesterUserID != userID && req.PayerUserID != userID {
+		return nil, ErrPaymentRequestNotFound
+	}
+	return req, nil
+}
+
+// getPaymentRequest loads a request by ref, reporting a PENDING request past its expiry as EXPIRED even if the sweep has not run yet.
+func (s *PaymentService) getPaymentRequest(ctx context.Context, ref string) (*repository.PaymentRequestRow, error) {
+	req, err := s.paymentRequestRepo.GetByRef(ctx, strings.ToUpper(strings.TrimSpace(ref)))
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrPaymentRequestNotFound
+	}
+	if req.Status == repository.PaymentRequestStatusPending && !time.Now().Before(req.ExpiresAt) {
+		req.Status = repository.PaymentRequestStatusExpired
+	}
+	return req, nil
+}
+
+// PayPaymentRequest pays a request from the user's wallet using the outbound transfer path (PayUp wallets are 9PSB accounts).
+// The request is claimed first so it cannot be paid twice concurrently; the claim is released if the transfer fails.
+func (s *PaymentService) PayPaymentRequest(ctx context.Context, p *PayPaymentRequestParams) (*repository.PaymentRequestRow, *TransferResult, error) {
+	req, err := s.getPaymentRequest(ctx, p.RequestRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.PayerUserID != "" && req.PayerUserID != p.UserID {
+		return nil, nil, ErrPaymentRequestNotFound
+	}
+	if req.RequesterUserID == p.UserID {
+		return nil, nil, fmt.Errorf("cannot pay your own payment request")
+	}
+	if req.Status == repository.PaymentRequestStatusExpired {
+		return nil, nil, ErrPaymentRequestExpired
+	}
+	if req.Status != repository.PaymentRequestStatusPending {
+		return nil, nil, repository.ErrPaymentRequestNotPending
+	}
+	payerID, err := uuid.Parse(p.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid user_id")
+	}
+	// Name enquiry up front: TransferToOtherBank requires the beneficiary name to match the provider's record exactly.
+	beneficiary, err := s.ResolveBeneficiary(ctx, psbBankCode, req.RequesterAccountNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("beneficiary enquiry: %w", err)
+	}
+	if err := s.paymentRequestRepo.ClaimForPayment(ctx, req.RequestRef); err != nil {
+		return nil, nil, err
+	}
+	result, err := s.TransferToOtherBank(ctx, &TransferToOtherBankParams{
+		UserID:                   p.UserID,
+		Amount:                   req.Amount,
+		BankCode:                 psbBankCode,
+		BeneficiaryName:          beneficiary.Name,
+		BeneficiaryAccountNumber: req.RequesterAccountNumber,
+		Pin:                      p.Pin,
+		StepUpToken:              p.StepUpToken,
+		PasskeyAssertion:         p.PasskeyAssertion,
+		IdempotencyKey:           p.IdempotencyKey,
+	})
+	if err != nil {
+		_ = s.paymentRequestRepo.ReleaseClaim(ctx, req.RequestRef)
+		return nil, nil, err
+	}
+	if err := s.paymentRequestRepo.MarkPaid(ctx, req.RequestRef, payerID, req.Amount, result.TransactionRef, repository.PaymentRequestPaidInApp); err != nil {
+		// The money has moved; the claim was lost (expired lease). Keep the transfer result and leave the request for support.
+		log.Printf("payment: request %s paid by %s but not marked PAID: %v", req.RequestRef, result.TransactionRef, err)
+	}
+	updated, _ := s.paymentRequestRepo.GetByRef(ctx, req.RequestRef)
+	if updated == nil {
+		updated = req
+	}
+	s.auditPaymentRequest(updated, "payment_request_paid", p.UserID, map[string]interface{}{
+		"amount": req.Amount, "paid_via": repository.PaymentRequestPaidInApp, "transaction_ref": result.TransactionRef,
+	})
+	s.notifyPaymentRequest(updated, updated.RequesterUserID, "payment_request_paid")
+	return updated, result, nil
+}
+
+// DeclinePaymentRequest lets the addressed payer decline a PENDING request. Open pay links cannot be declined.
+func (s *PaymentService) DeclinePaymentRequest(ctx context.Context, userID, ref, reason string) (*repository.PaymentRequestRow, error) {
+	req, err := s.getPaymentRequest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if req.PayerUserID != userID {
+		return nil, ErrPaymentRequestNotFound
+	}
+	if req.Status == repository.PaymentRequestStatusExpired {
+		return nil, ErrPaymentRequestExpired
+	}
+	if err := s.paymentRequestRepo.Decline(ctx, req.RequestRef, strings.TrimSpace(reason)); err != nil {
+		return nil, err
+	}
+	updated, err := s.paymentRequestRepo.GetByRef(ctx, req.RequestRef)
+	if err != nil || updated == nil {
+		return nil, err
+	}
+	s.auditPaymentRequest(updated, "payment_request_declined", userID, map[string]interface{}{"reason": reason})
+	s.notifyPaymentRequest(updated, updated.RequesterUserID, "payment_request_declined")
+	return updated, nil
+}
+
+// ResolvePayLink returns the public view of a pay link: the requester's PayUp account for bank-transfer funding. Payers
+// must put the request ref in the transfer narration so the credit can be matched back to the request.
+func (s *PaymentService) ResolvePayLink(ctx context.Context, ref string) (*PayLink, error) {
+	req, err := s.getPaymentRequest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &PayLink{
+		RequestRef:    req.RequestRef,
+		RequesterName: req.RequesterName,
+		AccountNumber: req.RequesterAccountNumber,
+		BankName:      psbBankName,
+		BankCode:      psbBankCode,
+		Amount:        req.Amount,
+		Note:          req.Note,
+		Status:        req.Status,
+		ExpiresAt:     req.ExpiresAt,
+	}, nil
+}
+
+// ExpirePaymentRequests moves PENDING requests past their expiry to EXPIRED. Run periodically.
+func (s *PaymentService) ExpirePaymentRequests(ctx context.Context) (int64, error) {
+	return s.paymentRequestRepo.ExpireDue(ctx)
+}
+
+// matchInboundCreditToPaymentRequest settles the PENDING request whose ref appears in the narration of an inbound credit
+// to the requester's wallet, if the amount covers it. Unmatched credits are simply wallet funding.
+func (s *PaymentService) matchInboundCreditToPaymentRequest(ctx context.Context, walletID uuid.UUID, amount float64, narration, txnRef string) {
+	if s.paymentRequestRepo == nil {
+		return
+	}
+	ref := requestRefPattern.FindString(strings.ToUpper(narration))
+	if ref == "" {
+		return
+	}
+	req, err := s.paymentRequestRepo.GetByRef(ctx, ref)
+	if err != nil || req == nil {
+		return
+	}
+	if req.RequesterWalletID != walletID.String() || req.Status != repository.PaymentRequestStatusPending || amount < req.Amount {
+		return
+	}
+	if err := s.paymentRequestRepo.MarkPaid(ctx, req.RequestRef, uuid.Nil, amount, txnRef, repository.PaymentRequestPaidBankTransfer); err != nil {
+		return
+	}
+	updated, _ := s.paymentRequestRepo.GetByRef(ctx, req.RequestRef)
+	if updated == nil {
+		return
+	}
+	s.auditPaymentRequest(updated, "payment_request_paid", "", map[string]interface{}{
+		"amount": amount, "paid_via": repository.PaymentRequestPaidBankTransfer, "transaction_ref": txnRef,
+	})
+	s.notifyPaymentRequest(updated, updated.RequesterUserID, "payment_request_paid")
+}
+
+func (s *PaymentService) auditPaymentRequest(req *repository.PaymentRequestRow, action, actorID string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["request_ref"] = req.RequestRef
+	metadata["status"] = req.Status
+	if actorID != "" {
+		metadata["actor_id"] = actorID
+	}
+	userID := req.RequesterUserID
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Action:   action,
+		Entity:   "payment_request",
+		EntityID: req.ID,
+		UserID:   &userID,
+		Metadata: metadata,
+	})
+}
+
+// notifyPaymentRequest emails toUserID about req; the notification service renders it from the evType template.
+func (s *PaymentService) notifyPaymentRequest(req *repository.PaymentRequestRow, toUserID, evType string) {
+	if req == nil || toUserID == "" {
+		return
+	}
+	amount := req.Amount
+	if evType == "payment_request_paid" {
+		amount = req.PaidAmount
+	}
+	_ = s.SendNotification(kafka.NotificationEvent{
+		UserID:  toUserID,
+		Type:    evType,
+		Channel: "email",
+		Data: map[string]interface{}{
+			"request_ref":     req.RequestRef,
+			"amount":          amount,
+			"status":          req.Status,
+			"requester_name":  req.RequesterName,
+			"note":            req.Note,
+			"expires_at":      req.ExpiresAt.Format("02 Jan 2006 15:04 MST"),
+			"payer_name":      req.PayerName,
+			"transaction_ref": req.PaidTxnRef,
+			"reason":          req.DeclineReason,
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// withPaymentRequest has a second user ask the fixture's user for amount and returns the request store.
+func (f *testFixture) withPaymentRequest(amount float64) (*fakePaymentRequests, *repository.PaymentRequestRow) {
+	requester, wallet := f.addUser("1100000002")
+	req := &repository.PaymentRequestRow{
+		RequestRef: "PRQ0001", RequesterUserID: requester.String(), RequesterWalletID: wallet.WalletID.String(),
+		RequesterAccountNumber: wallet.AccountNumber, PayerUserID: f.userID.String(), Amount: amount,
+		Status: repository.PaymentRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour),
+	}
+	store := &fakePaymentRequests{rows: map[string]*repository.PaymentRequestRow{req.RequestRef: req}, claimed: map[string]bool{}}
+	f.svc.paymentRequestRepo = store
+	return store, req
+}
+
+func TestPayPaymentRequest(t *testing.T) {
+	f := newTestFixture()
+	store, req := f.withPaymentRequest(3000)
+	paid, res, err := f.svc.PayPaymentRequest(context.Background(), &PayPaymentRequestParams{UserID: f.userID.String(), RequestRef: req.RequestRef, IdempotencyKey: "pay-1"})
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	if paid.Status != repository.PaymentRequestStatusPaid || paid.PaidTxnRef != res.TransactionRef || store.claimed[req.RequestRef] {
+		t.Errorf("request = %+v, result %+v", paid, res)
+	}
+	if len(f.bank.transfers) != 1 || f.bank.transfers[0].Customer.Account.Number != req.RequesterAccountNumber || f.bank.transfers[0].Order.Amount != "3000" {
+		t.Errorf("9PSB transfers = %+v", f.bank.transfers)
+	}
+}
+
+func TestPayPaymentRequestReusedKey(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount float64
+		bank   string
+		acct   string
+	}{
+		{"different beneficiary", 3000, testBeneficiaryBank, testBeneficiaryAcct},
+		{"different amount", 1000, psbBankCode, "1100000002"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestFixture()
+			store, req := f.withPaymentRequest(3000)
+			// An earlier transfer used the key the payer's client now sends again.
+			earlier := &fakeTxn{walletID: f.wallet.WalletID, ref: "TXN-EARLIER", status: "SUCCESS", typ: "OUTBOUND_TRANSFER", amount: c.amount, beneficiary: c.bank + "/" + c.acct, key: "pay-1"}
+			f.txns.rows[uuid.New()] = earlier
+
+			_, _, err := f.svc.PayPaymentRequest(context.Background(), &PayPaymentRequestParams{UserID: f.userID.String(), RequestRef: req.RequestRef, IdempotencyKey: "pay-1"})
+			if !errors.Is(err, repository.ErrIdempotencyConflict) {
+				t.Fatalf("pay = %v, want ErrIdempotencyConflict", err)
+			}
+			if got := store.rows[req.RequestRef]; got.Status != repository.PaymentRequestStatusPending || got.PaidTxnRef != "" || store.claimed[req.RequestRef] {
+				t.Errorf("request = %+v, claimed %v; want it pending and released", got, store.claimed[req.RequestRef])
+			}
+			if len(f.bank.transfers) != 0 {
+				t.Errorf("9PSB transfers = %+v", f.bank.transfers)
+			}
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Pocket limits. A wallet may hold MaxPocketsPerWallet active pockets; locks run at most MaxPocketLock ahead.
+const (
+	MaxPocketsPerWallet       = 10
+	MaxPocketLock             = 5 * 365 * 24 * time.Hour
+	MaxAutosaveInboundPercent = 50
+)
+
+// PocketRoundupSteps are the multiples a round-up rule may round transfers up to.
+var PocketRoundupSteps = []float64{10, 50, 100, 500, 1000}
+
+var ErrPocketNotFound = errors.New("pocket not found")
+
+// PocketInterestPolicy decides the annual interest rate (a fraction, e.g. 0.08) a pocket earns. It is the hook for
+// interest products: accrual calls it once per pocket per day; 0 means the pocket earns nothing.
+type PocketInterestPolicy interface {
+	AnnualRate(p *repository.PocketRow) float64
+}
+
+// FlatPocketInterest pays the same annual rate on every pocket.
+type FlatPocketInterest float64
+
+// AnnualRate implements PocketInterestPolicy.
+func (r FlatPocketInterest) AnnualRate(*repository.PocketRow) float64 { return float64(r) }
+
+// CreatePocketParams are the inputs for creating a savings pocket.
+type CreatePocketParams struct {
+	UserID                 string
+	Name                   string
+	GoalAmount             *float64
+	LockedUntil            *time.Time
+	AutosaveInboundPercent float64
+	AutosaveRoundupTo      float64
+}
+
+// PocketMoveResult is a completed move between the main balance and a pocket.
+type PocketMoveResult struct {
+	Pocket         *repository.PocketRow
+	TransactionRef string
+}
+
+// CreatePocket opens an empty pocket on the user's wallet.
+func (s *PaymentService) CreatePocket(ctx context.Context, p *CreatePocketParams) (*repository.PocketRow, error) {
+	if s.pocketRepo == nil {
+		return nil, fmt.Errorf("pockets not configured")
+	}
+	name := strings.TrimSpace(p.Name)
+	if name == "" || len(name) > 60 {
+		return nil, fmt.Errorf("name must be 1-60 characters")
+	}
+	if p.GoalAmount != nil && *p.GoalAmount <= 0 {
+		return nil, fmt.Errorf("goal_amount must be positive")
+	}
+	if err := validatePocketLock(p.LockedUntil); err != nil {
+		return nil, err
+	}
+	if err := validatePocketAutosave(&p.AutosaveInboundPercent, &p.AutosaveRoundupTo); err != nil {
+		return nil, err
+	}
+	wallet, uid, err := s.activeWallet(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.pocketRepo.ListByUser(ctx, uid, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxPocketsPerWallet {
+		return nil, fmt.Errorf("cannot have more than %d active pockets", MaxPocketsPerWallet)
+	}
+	pocket, err := s.pocketRepo.Create(ctx, &repository.CreatePocketParams{
+		WalletID:               wallet.WalletID,
+		UserID:                 uid,
+		Name:                   name,
+		GoalAmount:             p.GoalAmount,
+		LockedUntil:            p.LockedUntil,
+		AutosaveInboundPercent: p.AutosaveInboundPercent,
+		AutosaveRoundupTo:      p.AutosaveRoundupTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditPocket(pocket, "pocket_created", nil)
+	return pocket, nil
+}
+
+// ListMyPockets returns the user's pockets (active only unless includeClosed).
+func (s *PaymentService) ListMyPockets(ctx context.Context, userID string, includeClosed bool) ([]repository.PocketRow, error) {
+	if s.pocketRepo == nil {
+		return nil, fmt.Errorf("pockets not configured")
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	return s.pocketRepo.ListByUser(ctx, uid, includeClosed)
+}
+
+// GetMyPocket returns one of the user's pockets by ref.
+func (s *PaymentService) GetMyPocket(ctx context.Context, userID, ref string) (*repository.PocketRow, error) {
+	if s.pocketRepo == nil {
+		return nil, fmt.Errorf("pockets not configured")
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id")
+	}
+	pocket, err := s.pocketRepo.GetByRef(ctx, uid, strings.ToUpper(strings.TrimSpace(ref)))
+	if err != nil {
+		return nil, err
+	}
+	if pocket == nil {
+		return nil, ErrPocketNotFound
+	}
+	return pocket, nil
+}
+
+// UpdatePocket changes a pocket's name, goal, lock (extend only) or auto-save rules.
+func (s *PaymentService) UpdatePocket(ctx context.Context, userID, ref string, p *repository.UpdatePocketParams) (*repository.PocketRow, error) {
+	pocket, err := s.GetMyPocket(ctx, userID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" || len(name) > 60 {
+			return nil, fmt.Errorf("name must be 1-60 characters")
+		}
+		p.Name = &name
+	}
+	if p.GoalAmount != nil && *p.GoalAmount < 0 {
+		return nil, fmt.Errorf("goal_amount must be positive (0 clears it)")
+	}
+	if p.LockedUntil != nil {
+		if err := validatePocketLock(p.LockedUntil); err != nil {
+			return nil, err
+		}
+		if pocket.LockedUntil != nil && p.LockedUntil.Before(*pocket.LockedUntil) {
+			return nil, fmt.Errorf("locked_until cannot be brought forward")
+		}
+	}
+	if err := validatePocketAutosave(p.AutosaveInboundPercent, p.AutosaveRoundupTo); err != nil {
+		return nil, err
+	}
+	updated, err := s.pocketRepo.Update(ctx, pocket.ID, p)
+	if err != nil {
+		return nil, err
+	}
+	s.auditPocket(updated, "pocket_updated", nil)
+	return updated, nil
+}
+
+// MovePocketFunds moves amount from the main balance into the pocket (toPocket) or back out. Moving out is refused while
+// the pocket is locked. No provider call is made: the money stays in the same 9PSB account.
+func (s *PaymentService) MovePocketFunds(ctx context.Context, userID, ref string, amount float64, toPocket bool) (*PocketMoveResult, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	amount = math.Round(amount*100) / 100
+	pocket, err := s.GetMyPocket(ctx, userID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if pocket.Status != repository.PocketStatusActive {
+		return nil, repository.ErrPocketClosed
+	}
+	if !toPocket && pocket.Locked(time.Now()) {
+		return nil, repository.ErrPocketLocked
+	}
+	narration := "Moved to " + pocket.Name
+	if !toPocket {
+		narration = "Moved from " + pocket.Name
+	}
+	txnRef, err := s.pocketTransfer(ctx, pocket, amount, toPocket, narration, userID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.GetMyPocket(ctx, userID, pocket.PocketRef)
+	if err != nil {
+		return nil, err
+	}
+	action := "pocket_deposit"
+	if !toPocket {
+		action = "pocket_withdrawal"
+	}
+	s.auditPocket(updated, action, map[string]interface{}{"amount": amount, "transaction_ref": txnRef})
+	return &PocketMoveResult{Pocket: updated, TransactionRef: txnRef}, nil
+}
+
+// ClosePocket returns any balance to the main balance and closes the pocket. Refused while locked.
+func (s *PaymentService) ClosePocket(ctx context.Context, userID, ref string) (*PocketMoveResult, error) {
+	pocket, err := s.GetMyPocket(ctx, userID, ref)
+	if err != nil {
+		return nil, err
+	}
+	txnID, err := s.pocketRepo.Close(ctx, &repository.PocketTransferParams{
+		PocketID:       pocket.ID,
+		WalletID:       pocket.WalletID,
+		TransactionRef: generateTrackingRef("PKT"),
+		Narration:      "Closed " + pocket.Name,
+		InitiatedBy:    userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	txnRef := ""
+	if txnID != uuid.Nil {
+		txnRef, _, _ = s.transactionRepo.GetRefAndProviderRefByID(ctx, txnID)
+	}
+	updated, err := s.GetMyPocket(ctx, userID, pocket.PocketRef)
+	if err != nil {
+		return nil, err
+	}
+	s.auditPocket(updated, "pocket_closed", map[string]interface{}{"amount": pocket.Balance, "transaction_ref": txnRef})
+	return &PocketMoveResult{Pocket: updated, TransactionRef: txnRef}, nil
+}
+
+// AccruePocketInterest records one day's interest for day on every funded pocket, using the configured
+// PocketInterestPolicy. It is idempotent per pocket and day, so it can run on any schedule. No-op without a policy.
+func (s *PaymentService) AccruePocketInterest(ctx context.Context, day time.Time) (int, error) {
+	if s.pocketRepo == nil || s.pocketInterest == nil {
+		return 0, nil
+	}
+	return s.pocketRepo.AccrueInterest(ctx, day, func(p *repository.PocketRow) float64 {
+		return s.pocketInterest.AnnualRate(p) / 365
+	})
+}
+
+// PayPocketInterest pays accrued pocket interest, once per pocket per calendar month (UTC) of now: it sets the
+// whole-kobo interest aside in a payout, has 9PSB credit it to the wallet from the interest funding account under the
+// payout's fixed reference, then posts it into the pocket (or the main balance if the pocket has been closed). A payout
+// whose credit or posting fails stays pending and is retried on the next run; 9PSB rejects the repeated reference as a
+// duplicate, which counts as credited. Returns payouts completed. No-op without an interest policy.
+func (s *PaymentService) PayPocketInterest(ctx context.Context, now time.Time) (int, error) {
+	if s.pocketRepo == nil || s.pocketInterest == nil {
+		return 0, nil
+	}
+	if s.psbProvider == nil || s.walletRepo == nil {
+		return 0, fmt.Errorf("pocket interest payout not configured")
+	}
+	if _, err := s.pocketRepo.ReserveInterestPayouts(ctx, now.UTC().Format("2006-01")); err != nil {
+		return 0, fmt.Errorf("reserve interest payouts: %w", err)
+	}
+	due, err := s.pocketRepo.ListPendingInterestPayouts(ctx, 100)
+	if err != nil {
+		return 0, err
+	}
+	paid := 0
+	for i := range due {
+		x := &due[i]
+		wallet, err := s.walletRepo.GetActiveByUserID(ctx, x.UserID)
+		if err != nil || wallet == nil || wallet.WalletID != x.WalletID {
+			log.Printf("payment: interest payout %s: wallet not active: %v", x.TransactionRef, err)
+			continue
+		}
+		creditRef, err := s.psbProvider.WaasCreditTransfer(ctx, wallet.AccountNumber, "Interest on "+x.PocketName, x.Amount, x.TransactionRef)
+		if err != nil && !strings.Contains(err.Error(), "Duplicate") {
+			log.Printf("payment: interest payout %s: 9PSB credit: %v", x.TransactionRef, err)
+			continue
+		}
+		if err := s.pocketRepo.CompleteInterestPayout(ctx, x, creditRef); err != nil {
+			log.Printf("payment: interest payout %s: %v", x.TransactionRef, err)
+			continue
+		}
+		paid++
+		userID := x.UserID.String()
+		_ = s.SendAuditLog(kafka.AuditLogParams{
+			Action:   "pocket_interest_paid",
+			Entity:   "savings_pocket",
+			EntityID: x.PocketID.String(),
+			UserID:   &userID,
+			Metadata: map[string]interface{}{"pocket_ref": x.PocketRef, "amount": x.Amount, "period": x.Period, "transaction_ref": x.TransactionRef},
+		})
+	}
+	return paid, nil
+}
+
+// pocketHeld returns the total in the wallet's pockets. Provider balances include it; spendable balance does not.
+func (s *PaymentService) pocketHeld(ctx context.Context, walletID uuid.UUID) (float64, error) {
+	if s.pocketRepo == nil {
+		return 0, nil
+	}
+	return s.pocketRepo.SumBalancesByWallet(ctx, walletID)
+}
+
+// autosaveInboundCredit sweeps each inbound-credit rule's share of amount into its pocket. Best effort: a failed sweep
+// leaves the credit in the main balance.
+func (s *PaymentService) autosaveInboundCredit(ctx context.Context, walletID uuid.UUID, amount float64) {
+	if s.pocketRepo == nil {
+		return
+	}
+	pockets, err := s.pocketRepo.ListAutosave(ctx, walletID, true)
+	if err != nil {
+		log.Printf("payment: autosave pockets for wallet %s: %v", walletID, err)
+		return
+	}
+	for i := range pockets {
+		p := &pockets[i]
+		share := math.Floor(amount*p.AutosaveInboundPercent) / 100
+		if share <= 0 {
+			continue
+		}
+		s.autosave(ctx, p, share, fmt.Sprintf("Auto-save %s%% to %s", trimPercent(p.AutosaveInboundPercent), p.Name))
+	}
+}
+
+// autosaveRoundUp sweeps the difference between a transfer amount and the next multiple of each round-up rule into its pocket.
+func (s *PaymentService) autosaveRoundUp(ctx context.Context, walletID uuid.UUID, amount float64) {
+	if s.pocketRepo == nil {
+		return
+	}
+	pockets, err := s.pocketRepo.ListAutosave(ctx, walletID, false)
+	if err != nil {
+		log.Printf("payment: round-up pockets for wallet %s: %v", walletID, err)
+		return
+	}
+	for i := range pockets {
+		p := &pockets[i]
+		diff := math.Round((math.Ceil(amount/p.AutosaveRoundupTo)*p.AutosaveRoundupTo-amount)*100) / 100
+		if diff <= 0 {
+			continue
+		}
+		s.autosave(ctx, p, diff, "Round-up to "+p.Name)
+	}
+}
+
+func (s *PaymentService) autosave(ctx context.Context, p *repository.PocketRow, amount float64, narration string) {
+	txnRef, err := s.pocketTransfer(ctx, p, amount, true, narration, "system:autosave")
+	if err != nil {
+		log.Printf("payment: autosave %.2f to pocket %s: %v", amount, p.PocketRef, err)
+		return
+	}
+	s.auditPocket(p, "pocket_autosave", map[string]interface{}{"amount": amount, "transaction_ref": txnRef})
+}
+
+func (s *PaymentService) pocketTransfer(ctx context.Context, p *repository.PocketRow, amount float64, toPocket bool, narration, initiatedBy string) (string, error) {
+	txnRef := generateTrackingRef("PKT")
+	_, err := s.pocketRepo.Transfer(ctx, &repository.PocketTransferParams{
+		PocketID:       p.ID,
+		WalletID:       p.WalletID,
+		TransactionRef: txnRef,
+		ToPocket:       toPocket,
+		Amount:         amount,
+		Narration:      narration,
+		InitiatedBy:    initiatedBy,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "Insufficient balance") {
+			return "", fmt.Errorf("insufficient balance")
+		}
+		return "", err
+	}
+	return txnRef, nil
+}
+
+// activeWallet resolves the user's active wallet.
+func (s *PaymentService) activeWallet(ctx context.Context, userID string) (*repository.ActiveWalletForTransfer, uuid.UUID, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("invalid user_id")
+	}
+	wallet, err := s.walletRepo.GetActiveByUserID(ctx, uid)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	if wallet == nil {
+		return nil, uuid.Nil, fmt.Errorf("no active wallet")
+	}
+	return wallet, uid, nil
+}
+
+func validatePocketLock(t *time.Time) error {
+	if t == nil {
+		return nil
+	}
+	now := time.Now()
+	if !t.After(now) {
+		return fmt.Errorf("locked_until must be in the future")
+	}
+	if t.After(now.Add(MaxPocketLock)) {
+		return fmt.Errorf("locked_until must be within 5 years")
+	}
+	return nil
+}
+
+func validatePocketAutosave(percent, roundupTo *float64) error {
+	if percent != nil && (*percent < 0 || *percent > MaxAutosaveInboundPercent) {
+		return fmt.Errorf("autosave_inbound_percent must be between 0 and %d", MaxAutosaveInboundPercent)
+	}
+	if roundupTo != nil && *roundupTo != 0 {
+		for _, step := range PocketRoundupSteps {
+			if *roundupTo == step {
+				return nil
+			}
+		}
+		return fmt.Errorf("autosave_roundup_to must be 0 or one of 10, 50, 100, 500, 1000")
+	}
+	return nil
+}
+
+func trimPercent(v float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+func (s *PaymentService) auditPocket(p *repository.PocketRow, action string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["pocket_ref"] = p.PocketRef
+	metadata["balance"] = p.Balance
+	userID := p.UserID.String()
+	_ = s.SendAuditLog(kafka.AuditLogParams{
+		Action:   action,
+		Entity:   "savings_pocket",
+		EntityID: p.ID.String(),
+		UserID:   &userID,
+		Metadata: metadata,
+	})
+}
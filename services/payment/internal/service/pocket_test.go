@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// withPocket gives the fixture's wallet a pocket holding balance out of the opening balance.
+func (f *testFixture) withPocket(balance float64, lockedUntil *time.Time) (*fakePockets, *repository.PocketRow) {
+	pockets, ok := f.svc.pocketRepo.(*fakePockets)
+	if !ok {
+		pockets = &fakePockets{pockets: map[uuid.UUID]*repository.PocketRow{}, main: testOpeningBalance}
+		f.svc.pocketRepo = pockets
+	}
+	p := &repository.PocketRow{
+		ID: uuid.New(), PocketRef: "SP" + strings.ToUpper(uuid.NewString()[:8]), WalletID: f.wallet.WalletID, UserID: f.userID,
+		Name: "Rent", Balance: balance, LockedUntil: lockedUntil, Status: repository.PocketStatusActive,
+	}
+	pockets.pockets[p.ID] = p
+	pockets.main -= balance
+	return pockets, p
+}
+
+func TestMovePocketFunds(t *testing.T) {
+	f := newTestFixture()
+	pockets, pocket := f.withPocket(0, nil)
+	uid := f.userID.String()
+
+	res, err := f.svc.MovePocketFunds(context.Background(), uid, strings.ToLower(pocket.PocketRef), 5000, true)
+	if err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	if res.Pocket.Balance != 5000 || pockets.main != 15000 || res.TransactionRef == "" {
+		t.Errorf("after deposit: pocket %.2f, main %.2f, ref %q", res.Pocket.Balance, pockets.main, res.TransactionRef)
+	}
+	if res, err = f.svc.MovePocketFunds(context.Background(), uid, pocket.PocketRef, 2000, false); err != nil {
+		t.Fatalf("withdrawal: %v", err)
+	}
+	if res.Pocket.Balance != 3000 || pockets.main != 17000 {
+		t.Errorf("after withdrawal: pocket %.2f, main %.2f", res.Pocket.Balance, pockets.main)
+	}
+	if _, err := f.svc.MovePocketFunds(context.Background(), uid, pocket.PocketRef, 50000, true); err == nil || !strings.Contains(err.Error(), "insufficient") {
+		t.Errorf("deposit above main balance: err = %v", err)
+	}
+	if _, err := f.svc.MovePocketFunds(context.Background(), uuid.NewString(), pocket.PocketRef, 100, false); !errors.Is(err, ErrPocketNotFound) {
+		t.Errorf("another user's pocket: err = %v", err)
+	}
+}
+
+func TestMovePocketFundsLocked(t *testing.T) {
+	f := newTestFixture()
+	until := time.Now().Add(30 * 24 * time.Hour)
+	pockets, pocket := f.withPocket(4000, &until)
+	uid := f.userID.String()
+
+	if _, err := f.svc.MovePocketFunds(context.Background(), uid, pocket.PocketRef, 1000, false); !errors.Is(err, repository.ErrPocketLocked) {
+		t.Fatalf("withdrawal from a locked pocket: err = %v", err)
+	}
+	if res, err := f.svc.MovePocketFunds(context.Background(), uid, pocket.PocketRef, 1000, true); err != nil || res.Pocket.Balance != 5000 {
+		t.Fatalf("deposit into a locked pocket = %+v, %v", res, err)
+	}
+	if pockets.main != 15000 {
+		t.Errorf("main balance = %.2f, want 15000", pockets.main)
+	}
+}
+
+func TestTransferExcludesPocketBalance(t *testing.T) {
+	f := newTestFixture()
+	f.withPocket(15000, nil)
+	_, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(6000, "key-1"))
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Fatalf("transfer of pocket money: err = %v", err)
+	}
+	if len(f.bank.transfers) != 0 {
+		t.Error("9PSB transfer made")
+	}
+}
+
+func TestAccruePocketInterest(t *testing.T) {
+	f := newTestFixture()
+	f.svc.pocketInterest = FlatPocketInterest(0.365) // 0.1% a day
+	pockets, pocket := f.withPocket(10000, nil)
+	_, empty := f.withPocket(0, nil)
+	day := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	if n, err := f.svc.AccruePocketInterest(context.Background(), day); err != nil || n != 1 {
+		t.Fatalf("accrue = %d, %v; want 1 pocket", n, err)
+	}
+	if n, _ := f.svc.AccruePocketInterest(context.Background(), day); n != 0 {
+		t.Errorf("second run for the same day accrued %d pocket(s)", n)
+	}
+	if got := pockets.pockets[pocket.ID].InterestAccrued; got != 10 {
+		t.Errorf("accrued = %.4f, want 10", got)
+	}
+	if got := pockets.pockets[empty.ID].InterestAccrued; got != 0 {
+		t.Errorf("empty pocket accrued %.4f", got)
+	}
+	if got := pockets.pockets[pocket.ID].Balance; got != 10000 {
+		t.Errorf("accrual changed the balance to %.2f", got)
+	}
+}
+
+func TestPayPocketInterest(t *testing.T) {
+	f := newTestFixture()
+	f.svc.pocketInterest = FlatPocketInterest(0.1)
+	pockets, pocket := f.withPocket(10000, nil)
+	pocket.InterestAccrued = 10.0049
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	ref := "INT" + pocket.PocketRef + "202603"
+
+	if n, err := f.svc.PayPocketInterest(context.Background(), now); err != nil || n != 1 {
+		t.Fatalf("pay = %d, %v; want 1", n, err)
+	}
+	if f.bank.credits[ref] != 10 {
+		t.Errorf("9PSB credits = %v, want 10.00 under %s", f.bank.credits, ref)
+	}
+	if pocket.Balance != 10010 || pocket.InterestPaid != 10 || pocket.InterestAccrued < 0.0048 || pocket.InterestAccrued > 0.005 {
+		t.Errorf("pocket after payout: balance %.2f, paid %.2f, accrued %.4f", pocket.Balance, pocket.InterestPaid, pocket.InterestAccrued)
+	}
+
+	// Interest accrued later in the month waits for next month's payout.
+	pocket.InterestAccrued += 5
+	if n, err := f.svc.PayPocketInterest(context.Background(), now.Add(time.Hour)); err != nil || n != 0 {
+		t.Errorf("second run in the month = %d, %v; want 0", n, err)
+	}
+	if len(f.bank.credits) != 1 || pocket.InterestPaid != 10 {
+		t.Errorf("paid twice in a month: credits %v, paid %.2f", f.bank.credits, pocket.InterestPaid)
+	}
+	if n, _ := f.svc.PayPocketInterest(context.Background(), now.AddDate(0, 1, 0)); n != 1 || pocket.InterestPaid != 15 {
+		t.Errorf("next month = %d payout(s), paid %.2f; want 1 and 15", n, pocket.InterestPaid)
+	}
+	if len(pockets.payouts) != 2 {
+		t.Errorf("payouts = %d, want 2", len(pockets.payouts))
+	}
+}
+
+func TestPayPocketInterestRetry(t *testing.T) {
+	f := newTestFixture()
+	f.svc.pocketInterest = FlatPocketInterest(0.1)
+	pockets, pocket := f.withPocket(10000, nil)
+	pocket.InterestAccrued = 25
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	// 9PSB refuses the credit: nothing is posted and the payout stays pending.
+	f.bank.creditErr = errors.New("9PSB WaaS credit_transfer: service unavailable")
+	if n, err := f.svc.PayPocketInterest(context.Background(), now); err != nil || n != 0 {
+		t.Fatalf("pay with 9PSB down = %d, %v", n, err)
+	}
+	if pocket.Balance != 10000 || pocket.InterestAccrued != 0 || len(pockets.payouts) != 1 {
+		t.Fatalf("after failed credit: balance %.2f, accrued %.2f, %d payouts", pocket.Balance, pocket.InterestAccrued, len(pockets.payouts))
+	}
+
+	// 9PSB credits but posting fails: the next run sees the duplicate reference and posts without a second credit.
+	pockets.completeErr = errors.New("connection reset")
+	if n, _ := f.svc.PayPocketInterest(context.Background(), now.Add(time.Hour)); n != 0 {
+		t.Fatalf("pay with posting failure = %d", n)
+	}
+	if n, err := f.svc.PayPocketInterest(context.Background(), now.Add(2*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("retry = %d, %v; want 1", n, err)
+	}
+	if len(f.bank.credits) != 1 || pocket.Balance != 10025 || pocket.InterestPaid != 25 {
+		t.Errorf("after retry: credits %v, balance %.2f, paid %.2f", f.bank.credits, pocket.Balance, pocket.InterestPaid)
+	}
+}
+
+func TestPayPocketInterestClosedPocket(t *testing.T) {
+	f := newTestFixture()
+	f.svc.pocketInterest = FlatPocketInterest(0.1)
+	pockets, pocket := f.withPocket(0, nil)
+	pocket.Status = repository.PocketStatusClosed
+	pocket.InterestAccrued = 3.5
+	main := pockets.main
+
+	if n, err := f.svc.PayPocketInterest(context.Background(), time.Now()); err != nil || n != 1 {
+		t.Fatalf("pay = %d, %v", n, err)
+	}
+	if pocket.Balance != 0 || pockets.main != main+3.5 {
+		t.Errorf("closed pocket payout: pocket %.2f, main %.2f; want it in the main balance", pocket.Balance, pockets.main)
+	}
+}
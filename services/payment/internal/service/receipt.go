@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/receipt"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+)
+
+// Receipt formats accepted by GetTransactionReceipt.
+const (
+	ReceiptFormatPDF = "pdf"
+	ReceiptFormatPNG = "png"
+)
+
+var (
+	ErrReceiptsNotConfigured = errors.New("receipts not configured")
+	ErrInvalidReceiptCode    = errors.New("invalid or unrecognised verification code")
+)
+
+// TransactionReceipt is a rendered receipt ready to be served.
+type TransactionReceipt struct {
+	Content     []byte
+	ContentType string
+	FileName    string
+}
+
+// ReceiptVerification is what the public verify endpoint discloses about a transaction. Accounts are masked.
+type ReceiptVerification struct {
+	TransactionRef     string
+	Amount             float64
+	Status             string
+	Direction          string
+	SenderName         string
+	SenderAccount      string
+	BeneficiaryName    string
+	BeneficiaryBank    string
+	BeneficiaryAccount string
+	CreatedAt          string
+}
+
+// GetTransactionReceipt renders a receipt (format pdf or png) for a transaction on the user's wallet. Returns nil if the
+// transaction does not exist or is not owned by the user.
+func (s *PaymentService) GetTransactionReceipt(ctx context.Context, userID, transactionRef, format string) (*TransactionReceipt, error) {
+	if s.receiptKey == "" {
+		return nil, ErrReceiptsNotConfigured
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = ReceiptFormatPDF
+	}
+	if format != ReceiptFormatPDF && format != ReceiptFormatPNG {
+		return nil, fmt.Errorf("format must be pdf or png")
+	}
+	row, err := s.GetTransactionDetail(ctx, userID, transactionRef)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	rc := s.buildReceipt(row)
+	out := &TransactionReceipt{FileName: "payup-receipt-" + row.TransactionRef + "." + format}
+	if format == ReceiptFormatPNG {
+		out.Content, err = receipt.RenderPNG(rc)
+		out.ContentType = "image/png"
+	} else {
+		out.Content, err = receipt.RenderPDF(rc)
+		out.ContentType = "application/pdf"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("render receipt: %w", err)
+	}
+	s.SendAuditLog(kafka.AuditLogParams{
+		Action:   "receipt_generated",
+		Entity:   "transaction",
+		EntityID: row.TransactionRef,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"format": format},
+	})
+	return out, nil
+}
+
+// VerifyReceipt checks a verification code printed on a receipt and returns the masked transaction summary it vouches for.
+// Returns ErrInvalidReceiptCode for malformed, forged or unknown codes without distinguishing between them.
+func (s *PaymentService) VerifyReceipt(ctx context.Context, code string) (*ReceiptVerification, error) {
+	if s.receiptKey == "" {
+		return nil, ErrReceiptsNotConfigured
+	}
+	code = strings.TrimSpace(code)
+	ref, _, ok := receipt.ParseCode(code)
+	if !ok {
+		return nil, ErrInvalidReceiptCode
+	}
+	row, err := s.transactionRepo.GetHistoryRowByRef(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil || !receipt.Verify(s.receiptKey, code, row.Amount, row.CreatedAt) {
+		return nil, ErrInvalidReceiptCode
+	}
+	return &ReceiptVerification{
+		TransactionRef:     row.TransactionRef,
+		Amount:             row.Amount,
+		Status:             row.Status,
+		Direction:          row.Direction,
+		SenderName:         row.SenderName,
+		SenderAccount:      receipt.MaskAccount(row.SenderAccount),
+		BeneficiaryName:    row.BeneficiaryName,
+		BeneficiaryBank:    row.BeneficiaryBank,
+		BeneficiaryAccount: receipt.MaskAccount(row.BeneficiaryAccount),
+		CreatedAt:          row.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *PaymentService) buildReceipt(row *repository.TransactionHistoryRow) *receipt.Receipt {
+	code := receipt.Sign(s.receiptKey, row.TransactionRef, row.Amount, row.CreatedAt)
+	verifyURL := ""
+	if s.receiptVerifyURL != "" {
+		verifyURL = strings.TrimRight(s.receiptVerifyURL, "/") + "/" + code
+	}
+	return &receipt.Receipt{
+		TransactionRef:     row.TransactionRef,
+		SessionID:          row.ProviderRef,
+		Type:               row.Type,
+		Direction:          row.Direction,
+		Status:             row.Status,
+		Amount:             row.Amount,
+		FeeAmount:          row.FeeAmount,
+		Narration:          row.Narration,
+		SenderName:         row.SenderName,
+		SenderAccount:      receipt.MaskAccount(row.SenderAccount),
+		BeneficiaryName:    row.BeneficiaryName,
+		BeneficiaryBank:    row.BeneficiaryBank,
+		BeneficiaryAccount: receipt.MaskAccount(row.BeneficiaryAccount),
+		CreatedAt:          row.CreatedAt,
+		VerificationCode:   code,
+		VerifyURL:          verifyURL,
+	}
+}
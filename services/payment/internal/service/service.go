@@ -2,15 +2,18 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"html"
 	"strings"
 	"time"
 
+	"github.com/abubakvr/payup-backend/services/payment/internal/bills"
 	"github.com/abubakvr/payup-backend/services/payment/internal/clients"
 	"github.com/abubakvr/payup-backend/services/payment/internal/crypto"
 	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
 	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
 	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
 	"github.com/abubakvr/payup-backend/services/payment/internal/validator"
@@ -27,31 +30,82 @@ var (
 // PaymentService is the template for payment business logic. Wire in audit logging and SMS (via Kafka) here.
 type PaymentService struct {
 	repo                *repository.PaymentRepository
-	walletRepo          *repository.WalletRepository
+	walletRepo          walletStore
 	walletUpgradeRepo   *repository.WalletUpgradeRepository
-	webhookEventsRepo   *repository.WebhookEventsRepository
-	transactionRepo     *repository.TransactionRepository
-	audit               *kafka.Producer
+	webhookEventsRepo   webhookEventStore
+	transactionRepo     transactionStore
+	disputeRepo         disputeStore
+	paymentRequestRepo  paymentRequestStore
+	pocketRepo          pocketStore
+	pocketInterest      PocketInterestPolicy
+	billRepo            billStore
+	disputeSLA          time.Duration
+	receiptKey          string
+	receiptVerifyURL    string
+	audit               eventPublisher
 	notifier            *kafka.Producer
-	kycClient           *clients.KYCClient
-	userClient          *clients.UserClient
-	psbProvider         *psb.TokenProvider
+	kycClient           kycDirectory
+	userClient          userDirectory
+	psbProvider         bankProvider
+	billsProvider       bills.Provider
+	limits              limits.Policy
 }
 
 // NewPaymentService returns a new payment service.
-func NewPaymentService(repo *repository.PaymentRepository, walletRepo *repository.WalletRepository, walletUpgradeRepo *repository.WalletUpgradeRepository, webhookEventsRepo *repository.WebhookEventsRepository, transactionRepo *repository.TransactionRepository, audit *kafka.Producer, notifier *kafka.Producer, kycClient *clients.KYCClient, userClient *clients.UserClient, psbProvider *psb.TokenProvider) *PaymentService {
-	return &PaymentService{
+// disputeSLA is the resolution deadline for new disputes (DefaultDisputeSLA if zero).
+// receiptKey signs receipt verification codes (receipts disabled if empty); receiptVerifyURL is printed on receipts if set.
+// pocketInterest sets the savings pocket interest rate (nil: pockets earn no interest). billsProvider is the biller
+// aggregator (nil: bill payments disabled). limitPolicy holds the tier caps enforced on outbound payments and balances.
+func NewPaymentService(repo *repository.PaymentRepository, walletRepo *repository.WalletRepository, walletUpgradeRepo *repository.WalletUpgradeRepository, webhookEventsRepo *repository.WebhookEventsRepository, transactionRepo *repository.TransactionRepository, disputeRepo *repository.DisputeRepository, paymentRequestRepo *repository.PaymentRequestRepository, pocketRepo *repository.PocketRepository, pocketInterest PocketInterestPolicy, billRepo *repository.BillRepository, disputeSLA time.Duration, receiptKey, receiptVerifyURL string, audit *kafka.Producer, notifier *kafka.Producer, kycClient *clients.KYCClient, userClient *clients.UserClient, psbProvider *psb.TokenProvider, billsProvider bills.Provider, limitPolicy limits.Policy) *PaymentService {
+	if disputeSLA <= 0 {
+		disputeSLA = DefaultDisputeSLA
+	}
+	s := &PaymentService{
 		repo:              repo,
-		walletRepo:        walletRepo,
 		walletUpgradeRepo: walletUpgradeRepo,
-		webhookEventsRepo: webhookEventsRepo,
-		transactionRepo:   transactionRepo,
-		audit:             audit,
+		pocketInterest:    pocketInterest,
+		disputeSLA:        disputeSLA,
+		receiptKey:        receiptKey,
+		receiptVerifyURL:  receiptVerifyURL,
 		notifier:          notifier,
-		kycClient:         kycClient,
-		userClient:        userClient,
-		psbProvider:       psbProvider,
+		billsProvider:     billsProvider,
+		limits:            limitPolicy,
 	}
+	// A nil pointer in an interface field is not nil, so only set what was provided; the "not configured" checks rely on it.
+	if audit != nil {
+		s.audit = audit
+	}
+	if walletRepo != nil {
+		s.walletRepo = walletRepo
+	}
+	if webhookEventsRepo != nil {
+		s.webhookEventsRepo = webhookEventsRepo
+	}
+	if transactionRepo != nil {
+		s.transactionRepo = transactionRepo
+	}
+	if disputeRepo != nil {
+		s.disputeRepo = disputeRepo
+	}
+	if paymentRequestRepo != nil {
+		s.paymentRequestRepo = paymentRequestRepo
+	}
+	if pocketRepo != nil {
+		s.pocketRepo = pocketRepo
+	}
+	if billRepo != nil {
+		s.billRepo = billRepo
+	}
+	if kycClient != nil {
+		s.kycClient = kycClient
+	}
+	if userClient != nil {
+		s.userClient = userClient
+	}
+	if psbProvider != nil {
+		s.psbProvider = psbProvider
+	}
+	return s
 }
 
 // Health returns nil if the service and DB are healthy.
@@ -124,8 +178,15 @@ func (s *PaymentService) GetWalletDetails(ctx context.Context, userID string) (*
 	}, nil
 }
 
+// WalletBalance is the user's balance: the live 9PSB balance split into the spendable main balance and savings pockets.
+type WalletBalance struct {
+	psb.WalletEnquiryResult
+	SavingsBalance float64 // held in savings pockets; excluded from AvailableBalance and LedgerBalance
+}
+
 // GetWalletBalance returns the authenticated user's live balance from 9PSB (wallet_enquiry). Uses user_id to resolve account number from wallet.
-func (s *PaymentService) GetWalletBalance(ctx context.Context, userID string) (*psb.WalletEnquiryResult, error) {
+// Funds in savings pockets are reported separately from the main balance.
+func (s *PaymentService) GetWalletBalance(ctx context.Context, userID string) (*WalletBalance, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user_id")
@@ -140,7 +201,18 @@ func (s *PaymentService) GetWalletBalance(ctx context.Context, userID string) (*
 	if s.psbProvider == nil {
 		return nil, fmt.Errorf("wallet enquiry not configured")
 	}
-	return s.psbProvider.WalletEnquiry(ctx, wallet.AccountNumber)
+	enquiry, err := s.psbProvider.WalletEnquiry(ctx, wallet.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.pocketHeld(ctx, wallet.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	bal := &WalletBalance{WalletEnquiryResult: *enquiry, SavingsBalance: held}
+	bal.AvailableBalance -= held
+	bal.LedgerBalance -= held
+	return bal, nil
 }
 
 // GetWalletTransactionHistory returns the authenticated user's wallet transaction history (newest first). limit/offset for pagination.
@@ -283,6 +355,7 @@ func (s *PaymentService) ChangeWalletStatus(ctx context.Context, userID, newStat
 			html = buildWalletStatusSuspendedEmailHTML()
 		}
 		_ = s.SendNotification(kafka.NotificationEvent{
+			UserID:  userID,
 			Type:    "wallet_status_changed",
 			Channel: "email",
 			Metadata: map[string]interface{}{
@@ -459,6 +532,7 @@ func (s *PaymentService) SubmitWalletUpgrade(ctx context.Context, userID, initia
 	}
 	if toEmail != "" && s.notifier != nil {
 		_ = s.SendNotification(kafka.NotificationEvent{
+			UserID:  userID,
 			Type:    "wallet_upgrade_submitted",
 			Channel: "email",
 			Metadata: map[string]interface{}{
@@ -622,6 +696,13 @@ func (s *PaymentService) SendNotification(ev kafka.NotificationEvent) error {
 	return s.notifier.SendNotification(ev)
 }
 
+// sendPushAlert sends an instant alert to the user's phones; the notification service renders it from the push template
+// for evType and fans it out to every registered device. data holds the template variables, which double as the
+// deep-link fields (amount, transaction_ref, ...).
+func (s *PaymentService) sendPushAlert(userID, evType string, data map[string]interface{}) {
+	_ = s.SendNotification(kafka.NotificationEvent{UserID: userID, Type: evType, Channel: "push", Data: data})
+}
+
 // CreateWallet creates a 9PSB wallet for the user. Fetches KYC via gRPC, calls 9PSB open_wallet, stores in DB only on success.
 func (s *PaymentService) CreateWallet(ctx context.Context, userID string) (accountNumber string, err error) {
 	uid, err := uuid.Parse(userID)
@@ -703,13 +784,11 @@ func (s *PaymentService) CreateWallet(ctx context.Context, userID string) (accou
 	// Congratulatory email: wallet opened, account number, top up and login
 	if req.Email != "" {
 		_ = s.SendNotification(kafka.NotificationEvent{
+			UserID:  userID,
 			Type:    "wallet_opened",
 			Channel: "email",
-			Metadata: map[string]interface{}{
-				"to":      req.Email,
-				"subject": "Your PayUp wallet is ready",
-				"html":    buildWalletOpenedEmailHTML(result.Data.AccountNumber),
-			},
+			Metadata: map[string]interface{}{"to": req.Email},
+			Data:     map[string]interface{}{"account_number": result.Data.AccountNumber},
 		})
 		_ = s.SendAuditLog(kafka.AuditLogParams{
 			Service:  "payment",
@@ -729,18 +808,6 @@ func (s *PaymentService) CreateWallet(ctx context.Context, userID string) (accou
 	return result.Data.AccountNumber, nil
 }
 
-// buildWalletOpenedEmailHTML returns HTML body for the wallet-opened congratulatory email.
-func buildWalletOpenedEmailHTML(accountNumber string) string {
-	return `<p>Congratulations! Your PayUp wallet has been successfully opened.</p>` +
-		`<p><strong>Your account number:</strong> ` + accountNumber + `</p>` +
-		`<p>You can now:</p>` +
-		`<ul>` +
-		`<li>Top up your wallet to start making payments</li>` +
-		`<li>Log in to your account to view your balance and transaction history</li>` +
-		`</ul>` +
-		`<p>Thank you for choosing PayUp.</p>`
-}
-
 func generateTrackingRef(prefix string) string {
 	return prefix + time.Now().Format("20060102150405") + uuid.New().String()[:8]
 }
@@ -748,31 +815,56 @@ func generateTrackingRef(prefix string) string {
 // WalletDebitCreditResult is the result of an internal debit or credit.
 type WalletDebitCreditResult struct {
 	TransactionRef string
+	Duplicate      bool // idempotency key already used; TransactionRef is the original transaction
 }
 
 // WalletDebitCredit performs an internal debit or credit on the user's wallet (e.g. airtime, data, electricity, DSTV, admin adjust).
 // Calls 9PSB WaaS debit/credit API first; updates our transactions and ledger only when 9PSB returns success.
-func (s *PaymentService) WalletDebitCredit(ctx context.Context, userID string, amount float64, isCredit bool, narration string, initiatedBy string) (*WalletDebitCreditResult, error) {
+// With a non-empty idempotencyKey the transaction_ref is derived from the key, so a retry after a crash is rejected by
+// 9PSB as a duplicate reference and a retry after success returns the original transaction without moving money.
+func (s *PaymentService) WalletDebitCredit(ctx context.Context, userID string, amount float64, isCredit bool, narration string, initiatedBy string, idempotencyKey string) (*WalletDebitCreditResult, error) {
 	if amount <= 0 {
-		return nil, fmt.Errorf("amount must be positive")
+		return nil, notPosted(fmt.Errorf("amount must be positive"))
 	}
 	if narration == "" {
-		return nil, fmt.Errorf("narration is required")
+		return nil, notPosted(fmt.Errorf("narration is required"))
 	}
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user_id")
+		return nil, notPosted(fmt.Errorf("invalid user_id"))
 	}
 	wallet, err := s.walletRepo.GetActiveByUserID(ctx, uid)
 	if err != nil {
-		return nil, err
+		return nil, notPosted(err)
 	}
 	if wallet == nil {
-		return nil, fmt.Errorf("no active wallet")
+		return nil, notPosted(fmt.Errorf("no active wallet"))
 	}
 	txnRef := generateTrackingRef("ADJ")
+	if idempotencyKey != "" {
+		if existing, err := s.existingDebitCredit(ctx, wallet.WalletID, idempotencyKey); err != nil {
+			return nil, notPosted(err)
+		} else if existing != nil {
+			return existing, nil
+		}
+		txnRef = idempotentTrackingRef("ADJ", wallet.WalletID, idempotencyKey)
+	}
 	// 1) Call 9PSB WaaS debit or credit; do not update our ledger until 9PSB approves
 	if s.psbProvider != nil {
+		// 9PSB would let a debit eat into savings pockets; refuse it before the provider moves any money.
+		if !isCredit {
+			if held, err := s.pocketHeld(ctx, wallet.WalletID); err != nil {
+				return nil, notPosted(err)
+			} else if held > 0 {
+				enquiry, err := s.psbProvider.WalletEnquiry(ctx, wallet.AccountNumber)
+				if err != nil {
+					return nil, notPosted(fmt.Errorf("could not verify balance: %w", err))
+				}
+				if enquiry.AvailableBalance-held < amount {
+					return nil, notPosted(fmt.Errorf("insufficient balance"))
+				}
+			}
+		}
 		var providerRef string
 		if isCredit {
 			providerRef, err = s.psbProvider.WaasCreditTransfer(ctx, wallet.AccountNumber, narration, amount, txnRef)
@@ -781,9 +873,13 @@ func (s *PaymentService) WalletDebitCredit(ctx context.Context, userID string, a
 		}
 		if err != nil {
 			if strings.Contains(err.Error(), "Duplicate") {
-				return nil, fmt.Errorf("duplicate transaction reference: %w", err)
+				// An earlier attempt with this key reached 9PSB but never recorded its row; record it now.
+				if idempotencyKey == "" {
+					return nil, fmt.Errorf("duplicate transaction reference: %w", err)
+				}
+			} else {
+				return nil, fmt.Errorf("9PSB: %w", err)
 			}
-			return nil, fmt.Errorf("9PSB: %w", err)
 		}
 		// 2) 9PSB success: update our transactions and ledger
 		txnType := "DEBIT"
@@ -801,9 +897,13 @@ func (s *PaymentService) WalletDebitCredit(ctx context.Context, userID string, a
 			Narration:      narration,
 			InitiatedBy:    initiatedBy,
 			ProviderRef:    providerRef,
+			IdempotencyKey: idempotencyKey,
 		}
 		_, err = s.transactionRepo.CreateInternalDebitCreditAndPostLedger(ctx, params)
 		if err != nil {
+			if existing, _ := s.existingDebitCredit(ctx, wallet.WalletID, idempotencyKey); existing != nil {
+				return existing, nil
+			}
 			if strings.Contains(err.Error(), "Insufficient balance") {
 				return nil, fmt.Errorf("insufficient balance: %w", err)
 			}
@@ -825,59 +925,64 @@ func (s *PaymentService) WalletDebitCredit(ctx context.Context, userID string, a
 			Amount:         amount,
 			Narration:      narration,
 			InitiatedBy:    initiatedBy,
+			IdempotencyKey: idempotencyKey,
 		}
 		_, err = s.transactionRepo.CreateInternalDebitCreditAndPostLedger(ctx, params)
 		if err != nil {
+			if existing, _ := s.existingDebitCredit(ctx, wallet.WalletID, idempotencyKey); existing != nil {
+				return existing, nil
+			}
+			// Without 9PSB the ledger is the only record, and its insert rolled back.
 			if strings.Contains(err.Error(), "Insufficient balance") {
-				return nil, fmt.Errorf("insufficient balance: %w", err)
+				return nil, notPosted(fmt.Errorf("insufficient balance: %w", err))
 			}
-			return nil, err
+			return nil, notPosted(err)
 		}
 	}
-	// Send email to user on successful debit or credit
-	var toEmail string
-	if s.userClient != nil {
-		if u, _ := s.userClient.GetUserForKYC(ctx, userID); u != nil && u.Found {
-			toEmail = u.Email
-		}
-	}
-	if toEmail != "" && s.notifier != nil {
-		evType := "wallet_debit"
-		subject := "Your PayUp wallet was debited"
-		html := buildWalletDebitEmailHTML(amount, narration, txnRef)
-		if isCredit {
-			evType = "wallet_credit"
-			subject = "Your PayUp wallet was credited"
-			html = buildWalletCreditEmailHTML(amount, narration, txnRef)
-		}
-		_ = s.SendNotification(kafka.NotificationEvent{
-			Type:    evType,
-			Channel: "email",
-			Metadata: map[string]interface{}{
-				"to":             toEmail,
-				"subject":        subject,
-				"html":           html,
-				"amount":         amount,
-				"narration":      narration,
-				"transaction_ref": txnRef,
-			},
-		})
+	// Email and push the user about the debit or credit
+	evType := "wallet_debit"
+	if isCredit {
+		evType = "wallet_credit"
 	}
+	data := map[string]interface{}{"amount": amount, "narration": narration, "transaction_ref": txnRef}
+	_ = s.SendNotification(kafka.NotificationEvent{UserID: userID, Type: evType, Channel: "email", Data: data})
+	s.sendPushAlert(userID, evType, data)
 	return &WalletDebitCreditResult{TransactionRef: txnRef}, nil
 }
 
-func buildWalletDebitEmailHTML(amount float64, narration, txnRef string) string {
-	return `<p>Your PayUp wallet was debited.</p>` +
-		`<p><strong>Amount:</strong> NGN ` + fmt.Sprintf("%.2f", amount) + `</p>` +
-		`<p><strong>Narration:</strong> ` + html.EscapeString(narration) + `</p>` +
-		`<p><strong>Reference:</strong> ` + html.EscapeString(txnRef) + `</p>` +
-		`<p>Thank you for using PayUp.</p>`
+// notPostedError is a WalletDebitCredit failure that certainly moved no money: it happened before 9PSB was called, or
+// the ledger insert rolled back with no 9PSB configured. Any other error may hide a debit or credit that 9PSB made, so
+// the payment must be retried with the same idempotency key rather than abandoned.
+type notPostedError struct{ err error }
+
+func (e *notPostedError) Error() string { return e.err.Error() }
+func (e *notPostedError) Unwrap() error { return e.err }
+
+func notPosted(err error) error { return &notPostedError{err: err} }
+
+// isNotPosted reports whether err is a WalletDebitCredit failure that moved no money.
+func isNotPosted(err error) bool {
+	var np *notPostedError
+	return errors.As(err, &np)
+}
+
+// existingDebitCredit returns the transaction already recorded on the wallet under idempotencyKey, or nil.
+func (s *PaymentService) existingDebitCredit(ctx context.Context, walletID uuid.UUID, idempotencyKey string) (*WalletDebitCreditResult, error) {
+	id, _, err := s.transactionRepo.GetByIdempotencyKey(ctx, walletID, idempotencyKey)
+	if err != nil || id == uuid.Nil {
+		return nil, err
+	}
+	ref, _, err := s.transactionRepo.GetRefAndProviderRefByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletDebitCreditResult{TransactionRef: ref, Duplicate: true}, nil
 }
 
-func buildWalletCreditEmailHTML(amount float64, narration, txnRef string) string {
-	return `<p>Your PayUp wallet was credited.</p>` +
-		`<p><strong>Amount:</strong> NGN ` + fmt.Sprintf("%.2f", amount) + `</p>` +
-		`<p><strong>Narration:</strong> ` + html.EscapeString(narration) + `</p>` +
-		`<p><strong>Reference:</strong> ` + html.EscapeString(txnRef) + `</p>` +
-		`<p>Thank you for using PayUp.</p>`
+// idempotentTrackingRef derives a stable transaction_ref (at most 60 chars) from a wallet's idempotency key. The wallet is
+// part of the hash: two users sending the same key must not share a reference, or 9PSB would report the second user's
+// debit as a duplicate of the first.
+func idempotentTrackingRef(prefix string, walletID uuid.UUID, idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(walletID.String() + ":" + idempotencyKey))
+	return prefix + hex.EncodeToString(sum[:])[:32]
 }
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	kycpb "github.com/abubakvr/payup-backend/proto/kyc"
+	userpb "github.com/abubakvr/payup-backend/proto/user"
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+	"github.com/google/uuid"
+)
+
+// The interfaces below are what PaymentService needs from its repositories and clients. The repository and client
+// types satisfy them; tests substitute fakes.
+
+type walletStore interface {
+	Create(ctx context.Context, w *repository.WalletRow) error
+	EraseContact(ctx context.Context, walletID uuid.UUID) error
+	GetActiveByAccountNumber(ctx context.Context, accountNumber string) (*repository.WalletByAccount, error)
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*repository.ActiveWalletForTransfer, error)
+	GetByUserIDForStatusChange(ctx context.Context, userID uuid.UUID) (*repository.WalletForStatusChange, error)
+	HasActiveWallet(ctx context.Context, userID uuid.UUID) (bool, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]repository.WalletAdminRow, error)
+	ListForAdmin(ctx context.Context, limit, offset int) ([]repository.WalletAdminRow, error)
+	UpdateContact(ctx context.Context, userID uuid.UUID, phone, email string) (int64, error)
+	UpdateStatus(ctx context.Context, walletID uuid.UUID, status string) error
+}
+
+type transactionStore interface {
+	CountInFlight(ctx context.Context, walletID uuid.UUID) (int64, error)
+	CreateInboundCreditAndPostLedger(ctx context.Context, p *repository.CreateInboundCreditParams) (uuid.UUID, error)
+	CreateInternalDebitCreditAndPostLedger(ctx context.Context, p *repository.CreateInternalDebitCreditParams) (uuid.UUID, error)
+	CreateTransferWithIdempotency(ctx context.Context, p *repository.CreateTransferParams) (txnID uuid.UUID, existingStatus string, created bool, err error)
+	GetByIdempotencyKey(ctx context.Context, walletID uuid.UUID, idempotencyKey string) (id uuid.UUID, status string, err error)
+	GetByRefAndWalletID(ctx context.Context, transactionRef string, walletID uuid.UUID) (*repository.TransactionHistoryRow, error)
+	GetDisputableByRefAndWalletID(ctx context.Context, transactionRef string, walletID uuid.UUID) (*repository.DisputableTransaction, error)
+	GetHistoryRowByRef(ctx context.Context, transactionRef string) (*repository.TransactionHistoryRow, error)
+	GetRefAndProviderRefByID(ctx context.Context, txnID uuid.UUID) (transactionRef, providerRef string, err error)
+	GetTransferByIdempotencyKey(ctx context.Context, walletID uuid.UUID, idempotencyKey string) (*repository.IdempotentTransfer, error)
+	ListByWalletID(ctx context.Context, walletID uuid.UUID, limit, offset int) ([]repository.TransactionHistoryRow, error)
+	PostLedgerEntryAfterSync(ctx context.Context, transactionID, walletID uuid.UUID, amount float64, narrative string, postDebitAvailable, postDebitLedger float64) (uuid.UUID, error)
+	SumOutboundSpend(ctx context.Context, walletID uuid.UUID, dayStart, monthStart time.Time) (*repository.OutboundSpend, error)
+	UpdateTransferAfterAPI(ctx context.Context, txnID uuid.UUID, status string, providerRef string, psbResponseJSON []byte, responseCode string) error
+}
+
+type pocketStore interface {
+	AccrueInterest(ctx context.Context, day time.Time, dailyRate func(p *repository.PocketRow) float64) (int, error)
+	Close(ctx context.Context, p *repository.PocketTransferParams) (uuid.UUID, error)
+	CompleteInterestPayout(ctx context.Context, x *repository.PocketInterestPayout, providerRef string) error
+	Create(ctx context.Context, p *repository.CreatePocketParams) (*repository.PocketRow, error)
+	GetByRef(ctx context.Context, userID uuid.UUID, ref string) (*repository.PocketRow, error)
+	ListAutosave(ctx context.Context, walletID uuid.UUID, inbound bool) ([]repository.PocketRow, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, includeClosed bool) ([]repository.PocketRow, error)
+	ListPendingInterestPayouts(ctx context.Context, limit int) ([]repository.PocketInterestPayout, error)
+	ReserveInterestPayouts(ctx context.Context, period string) (int, error)
+	SumBalancesByWallet(ctx context.Context, walletID uuid.UUID) (float64, error)
+	Transfer(ctx context.Context, p *repository.PocketTransferParams) (uuid.UUID, error)
+	Update(ctx context.Context, id uuid.UUID, p *repository.UpdatePocketParams) (*repository.PocketRow, error)
+}
+
+type billStore interface {
+	CreatePending(ctx context.Context, p *repository.CreateBillPaymentParams) (*repository.BillPaymentRow, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*repository.BillPaymentRow, error)
+	GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*repository.BillPaymentRow, error)
+	GetByRefForUser(ctx context.Context, userID uuid.UUID, ref string) (*repository.BillPaymentRow, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, category string, limit, offset int) ([]repository.BillPaymentRow, error)
+	ListForRequery(ctx context.Context, maxRequeries, limit int) ([]repository.BillPaymentRow, error)
+	MarkDebited(ctx context.Context, b *repository.BillPaymentRow, debitRef, narration string) error
+	MarkFailed(ctx context.Context, b *repository.BillPaymentRow, reason string) error
+	MarkRequery(ctx context.Context, b *repository.BillPaymentRow, providerRef string) error
+	MarkSuccess(ctx context.Context, b *repository.BillPaymentRow, providerRef, token, units string) error
+	MarkTokenDelivered(ctx context.Context, id uuid.UUID) error
+	RecordRequery(ctx context.Context, b *repository.BillPaymentRow) error
+	Reverse(ctx context.Context, b *repository.BillPaymentRow, reversalRef, creditRef, reason string) error
+}
+
+type disputeStore interface {
+	AddAttachment(ctx context.Context, disputeID uuid.UUID, uploadedByType, uploadedBy, fileName, contentType, url string) (*repository.DisputeAttachmentRow, error)
+	AddNote(ctx context.Context, disputeID uuid.UUID, authorType, authorID, body string, internal bool) (*repository.DisputeNoteRow, error)
+	ClaimResolution(ctx context.Context, id uuid.UUID, fromStatus, toStatus, resolution string, amount float64, isCredit bool, note, resolvedBy string) error
+	CompleteResolution(ctx context.Context, id uuid.UUID, txnRef string) error
+	CountForAdmin(ctx context.Context, status string) (int64, error)
+	CountOpenByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	Create(ctx context.Context, p *repository.CreateDisputeParams) (*repository.DisputeRow, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*repository.DisputeRow, error)
+	GetByRefAndUserID(ctx context.Context, disputeRef string, userID uuid.UUID) (*repository.DisputeRow, error)
+	ListAttachments(ctx context.Context, disputeID uuid.UUID) ([]repository.DisputeAttachmentRow, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]repository.DisputeRow, error)
+	ListForAdmin(ctx context.Context, status string, limit, offset int) ([]repository.DisputeRow, error)
+	ListNotes(ctx context.Context, disputeID uuid.UUID, includeInternal bool) ([]repository.DisputeNoteRow, error)
+	ReleaseResolution(ctx context.Context, id uuid.UUID, fromStatus string) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, fromStatus, toStatus, assignedTo string, adminAction bool) error
+}
+
+type webhookEventStore interface {
+	InsertTransferEvent(ctx context.Context, eventStatus, providerRef, accountNumberHash string, amount float64, rawPayload []byte) (id uuid.UUID, duplicate bool, err error)
+	InsertWebhookEvent(ctx context.Context, eventType, eventStatus, providerRef, accountNumberHash string, rawPayload []byte) (uuid.UUID, error)
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+}
+
+type paymentRequestStore interface {
+	ClaimForPayment(ctx context.Context, ref string) error
+	Create(ctx context.Context, p *repository.CreatePaymentRequestParams) (*repository.PaymentRequestRow, error)
+	Decline(ctx context.Context, ref, reason string) error
+	ExpireDue(ctx context.Context) (int64, error)
+	GetByRef(ctx context.Context, ref string) (*repository.PaymentRequestRow, error)
+	ListForUser(ctx context.Context, userID uuid.UUID, role, status string, limit, offset int) ([]repository.PaymentRequestRow, error)
+	MarkPaid(ctx context.Context, ref string, paidBy uuid.UUID, amount float64, txnRef, via string) error
+	ReleaseClaim(ctx context.Context, ref string) error
+}
+
+// bankProvider is the 9PSB API (psb.TokenProvider).
+type bankProvider interface {
+	OpenWallet(ctx context.Context, body psb.OpenWalletRequest) (*psb.OpenWalletResult, error)
+	OtherBanksEnquiry(ctx context.Context, bankCode, accountNumber string) (string, error)
+	WaasChangeWalletStatus(ctx context.Context, accountNumber, accountStatus string) (*psb.WaasChangeWalletStatusResponse, error)
+	WaasCreditTransfer(ctx context.Context, accountNo, narration string, totalAmount float64, transactionID string) (string, error)
+	WaasDebitTransfer(ctx context.Context, accountNo, narration string, totalAmount float64, transactionID string) (string, error)
+	WaasUpgradeStatus(ctx context.Context, accountNumber string) (*psb.WaasUpgradeStatusResponse, error)
+	WaasWalletStatus(ctx context.Context, accountNo string) (*psb.WaasWalletStatusResponse, error)
+	WaasWalletTransactions(ctx context.Context, accountNumber, fromDate, toDate, numberOfItems string) (*psb.WaasWalletTransactionsResponse, error)
+	WaasWalletUpgradeFileUpload(ctx context.Context, form *psb.WaasWalletUpgradeFormFields, idFrontImage, idBackImage, customerImage, utilityBillImage, proofOfAddressVerificationImage []byte) (*psb.WaasWalletUpgradeFileUploadResponse, error)
+	WalletEnquiry(ctx context.Context, accountNo string) (*psb.WalletEnquiryResult, error)
+	WalletOtherBanks(ctx context.Context, payload *psb.WalletOtherBanksPayload) (rawResponse []byte, sessionID string, responseCode string, err error)
+}
+
+// eventPublisher is the Kafka producer for audit logs and wallet-events (kafka.Producer).
+type eventPublisher interface {
+	SendAuditLog(params kafka.AuditLogParams) error
+	PublishWalletCreated(ctx context.Context, userID string) error
+	PublishTransferSuccess(ctx context.Context, userID, transactionRef string, amount float64) error
+	PublishTransferFailed(ctx context.Context, userID, transactionRef string, amount float64, responseCode string) error
+}
+
+// userDirectory is the user service (clients.UserClient).
+type userDirectory interface {
+	GetUserForKYC(ctx context.Context, userID string) (*userpb.GetUserForKYCResponse, error)
+	ValidateTransfer(ctx context.Context, userID string, amount float64, pin, stepUpToken, passkeyAssertion, beneficiaryBank, beneficiaryAccount string) (*userpb.ValidateTransferResponse, error)
+}
+
+// kycDirectory is the KYC service (clients.KYCClient).
+type kycDirectory interface {
+	GetKYCForWallet(ctx context.Context, userID string) (*kycpb.GetKYCForWalletResponse, error)
+	GetKYCForWalletUpgrade(ctx context.Context, userID string) (*kycpb.GetKYCForWalletUpgradeResponse, error)
+	GetKYCLevel(ctx context.Context, userID string) (int, error)
+}
@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/limits"
 	"github.com/abubakvr/payup-backend/services/payment/internal/psb"
 	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
 	"github.com/google/uuid"
@@ -27,9 +27,28 @@ type TransferToOtherBankParams struct {
 	BeneficiaryName         string
 	BeneficiaryAccountNumber string
 	Pin                     string
+	StepUpToken             string // X-Step-Up-Token; needed at or above the user service's step-up threshold
+	PasskeyAssertion        string // passkey approval (JSON) from the client, used instead of Pin
 	IdempotencyKey          string
 }
 
+// existingTransfer returns the wallet's transaction already recorded under p.IdempotencyKey, or nil. A key reused for a
+// different amount or beneficiary is refused with repository.ErrIdempotencyConflict rather than answered with the
+// earlier transaction, which would report a payment that was never made.
+func (s *PaymentService) existingTransfer(ctx context.Context, walletID uuid.UUID, p *TransferToOtherBankParams) (*repository.IdempotentTransfer, error) {
+	if p.IdempotencyKey == "" {
+		return nil, nil
+	}
+	t, err := s.transactionRepo.GetTransferByIdempotencyKey(ctx, walletID, p.IdempotencyKey)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	if !t.SameTransfer(p.Amount, p.BankCode, p.BeneficiaryAccountNumber) {
+		return nil, repository.ErrIdempotencyConflict
+	}
+	return t, nil
+}
+
 // TransferToOtherBank runs the full flow: validate user, enquiry, create txn, call 9PSB, post DEBIT, send email.
 // Returns (result, nil) on success; (nil, error) on failure. On idempotency hit (existing success), returns existing result.
 func (s *PaymentService) TransferToOtherBank(ctx context.Context, p *TransferToOtherBankParams) (*TransferResult, error) {
@@ -49,50 +68,44 @@ func (s *PaymentService) TransferToOtherBank(ctx context.Context, p *TransferToO
 		return nil, fmt.Errorf("no active wallet")
 	}
 
+	tier := s.walletTier(ctx, p.UserID, wallet.Tier)
+
 	// Get current balance from 9PSB (wallet_enquiry) before proceeding
 	enquiry, err := s.psbProvider.WalletEnquiry(ctx, wallet.AccountNumber)
 	if err != nil {
 		return nil, fmt.Errorf("could not verify balance: %w", err)
 	}
-	if enquiry.AvailableBalance < p.Amount {
-		return nil, fmt.Errorf("insufficient balance")
+	// Savings pockets sit in the same 9PSB account but are not spendable, nor is anything above the tier maximum
+	if err := s.checkBalance(ctx, wallet, tier, enquiry.AvailableBalance, p.Amount); err != nil {
+		return nil, err
 	}
 
-	// 1) User validation (PIN, restricted, paused, limits)
+	// 1) User validation (PIN, restricted, paused), then tier caps and the user's own limits
+	var userLimits limits.UserLimits
 	if s.userClient != nil {
-		resp, err := s.userClient.ValidateTransfer(ctx, p.UserID, p.Amount, p.Pin)
+		resp, err := s.userClient.ValidateTransfer(ctx, p.UserID, p.Amount, p.Pin, p.StepUpToken, p.PasskeyAssertion, p.BankCode, p.BeneficiaryAccountNumber)
 		if err != nil {
 			return nil, fmt.Errorf("validate transfer: %w", err)
 		}
 		if resp != nil && !resp.Allowed {
 			return nil, fmt.Errorf("%s", resp.Message)
 		}
-		if resp != nil && (resp.DailyTransferLimit > 0 || resp.MonthlyTransferLimit > 0) {
-			now := time.Now().UTC()
-			todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
-			monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
-			until := now.Format(time.RFC3339)
-			dailySpend, _ := s.transactionRepo.SumSuccessfulOutboundAmountByWalletAndWindow(ctx, wallet.WalletID, todayStart, until)
-			monthlySpend, _ := s.transactionRepo.SumSuccessfulOutboundAmountByWalletAndWindow(ctx, wallet.WalletID, monthStart, until)
-			if resp.DailyTransferLimit > 0 && (p.Amount+dailySpend) > resp.DailyTransferLimit {
-				return nil, fmt.Errorf("daily transfer limit exceeded")
-			}
-			if resp.MonthlyTransferLimit > 0 && (p.Amount+monthlySpend) > resp.MonthlyTransferLimit {
-				return nil, fmt.Errorf("monthly transfer limit exceeded")
-			}
+		if resp != nil {
+			userLimits = limits.UserLimits{Daily: resp.DailyTransferLimit, Monthly: resp.MonthlyTransferLimit}
 		}
 	}
+	if err := s.checkSpendingLimits(ctx, wallet, tier, limits.ChannelTransfer, p.Amount, userLimits); err != nil {
+		return nil, err
+	}
 
-	// 2) Idempotency: if key provided and we already have a SUCCESS row, return it
-	if p.IdempotencyKey != "" {
-		existingID, existingStatus, err := s.transactionRepo.GetByIdempotencyKey(ctx, p.IdempotencyKey)
-		if err != nil {
-			return nil, err
-		}
-		if existingID != uuid.Nil && existingStatus == "SUCCESS" {
-			ref, provRef, _ := s.transactionRepo.GetRefAndProviderRefByID(ctx, existingID)
-			return &TransferResult{TransactionRef: ref, SessionID: provRef}, nil
-		}
+	// 2) Idempotency: if key provided and we already have a SUCCESS row for this transfer, return it
+	existing, err := s.existingTransfer(ctx, wallet.WalletID, p)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == "SUCCESS" {
+		ref, provRef, _ := s.transactionRepo.GetRefAndProviderRefByID(ctx, existing.ID)
+		return &TransferResult{TransactionRef: ref, SessionID: provRef}, nil
 	}
 
 	// 3) Beneficiary name enquiry: 9PSB (120001) use wallet_enquiry; other banks use other_banks_enquiry
@@ -152,11 +165,18 @@ func (s *PaymentService) TransferToOtherBank(ctx context.Context, p *TransferToO
 		IdempotencyKey:       p.IdempotencyKey,
 		InitiatedBy:          p.UserID,
 		PsbRequestJSON:        psbReqJSON,
+		Guard:                 s.spendGuard(tier, limits.ChannelTransfer, p.Amount, userLimits),
 	}
 	txnID, existingStatus, created, err := s.transactionRepo.CreateTransferWithIdempotency(ctx, createParams)
 	if err != nil {
 		return nil, err
 	}
+	if !created {
+		// The key was taken since step 2; it must still name this transfer.
+		if _, err := s.existingTransfer(ctx, wallet.WalletID, p); err != nil {
+			return nil, err
+		}
+	}
 	if !created && existingStatus == "SUCCESS" {
 		ref, provRef, _ := s.transactionRepo.GetRefAndProviderRefByID(ctx, txnID)
 		return &TransferResult{TransactionRef: ref, SessionID: provRef}, nil
@@ -170,6 +190,9 @@ func (s *PaymentService) TransferToOtherBank(ctx context.Context, p *TransferToO
 	rawResp, sessionID, responseCode, err := s.psbProvider.WalletOtherBanks(ctx, payload)
 	if err != nil {
 		_ = s.transactionRepo.UpdateTransferAfterAPI(ctx, txnID, "FAILED", "", rawResp, responseCode)
+		if s.audit != nil {
+			_ = s.audit.PublishTransferFailed(ctx, p.UserID, txnRef, p.Amount, responseCode)
+		}
 		return nil, err
 	}
 
@@ -189,27 +212,13 @@ func (s *PaymentService) TransferToOtherBank(ctx context.Context, p *TransferToO
 		return nil, fmt.Errorf("ledger entry: %w", err)
 	}
 
-	// 9) Success email
-	var toEmail string
-	if s.userClient != nil {
-		if u, _ := s.userClient.GetUserForKYC(ctx, p.UserID); u != nil && u.Found {
-			toEmail = u.Email
-		}
-	}
-	if toEmail != "" {
-		_ = s.SendNotification(kafka.NotificationEvent{
-			Type:    "transfer_success",
-			Channel: "email",
-			Metadata: map[string]interface{}{
-				"to":       toEmail,
-				"subject":  "Transfer successful",
-				"html":     buildTransferSuccessEmailHTML(p.Amount, p.BeneficiaryName, txnRef),
-				"amount":   p.Amount,
-				"beneficiary": p.BeneficiaryName,
-				"transaction_ref": txnRef,
-			},
-		})
-	}
+	// 9) Round-up auto-save rules sweep the change into their pockets
+	s.autosaveRoundUp(ctx, wallet.WalletID, p.Amount)
+
+	// 10) Success email and push; the notification service renders both from its transfer_success templates
+	transferData := map[string]interface{}{"amount": p.Amount, "beneficiary": p.BeneficiaryName, "transaction_ref": txnRef}
+	_ = s.SendNotification(kafka.NotificationEvent{UserID: p.UserID, Type: "transfer_success", Channel: "email", Data: transferData})
+	s.sendPushAlert(p.UserID, "transfer_success", transferData)
 	_ = s.SendAuditLog(kafka.AuditLogParams{
 		Action:   "transfer_success",
 		Entity:   "transaction",
@@ -220,6 +229,10 @@ func (s *PaymentService) TransferToOtherBank(ctx context.Context, p *TransferToO
 			"transaction_ref": txnRef, "provider_ref": sessionID,
 		},
 	})
+	// User service evaluates referral rewards on the referee's first transfer
+	if s.audit != nil {
+		_ = s.audit.PublishTransferSuccess(ctx, p.UserID, txnRef, p.Amount)
+	}
 
 	return &TransferResult{TransactionRef: txnRef, SessionID: sessionID}, nil
 }
@@ -230,11 +243,3 @@ func formatAmount(amount float64) string {
 	}
 	return fmt.Sprintf("%.2f", amount)
 }
-
-func buildTransferSuccessEmailHTML(amount float64, beneficiary, txnRef string) string {
-	return `<p>Your transfer was successful.</p>` +
-		`<p><strong>Amount:</strong> NGN ` + fmt.Sprintf("%.2f", amount) + `</p>` +
-		`<p><strong>Beneficiary:</strong> ` + beneficiary + `</p>` +
-		`<p><strong>Reference:</strong> ` + txnRef + `</p>` +
-		`<p>Thank you for using PayUp.</p>`
-}
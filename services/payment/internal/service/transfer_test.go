@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/payment/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/payment/internal/repository"
+)
+
+func (f *testFixture) transferParams(amount float64, key string) *TransferToOtherBankParams {
+	return &TransferToOtherBankParams{
+		UserID:                   f.userID.String(),
+		Amount:                   amount,
+		BankCode:                 testBeneficiaryBank,
+		BeneficiaryName:          testBeneficiaryName,
+		BeneficiaryAccountNumber: testBeneficiaryAcct,
+		IdempotencyKey:           key,
+	}
+}
+
+func TestTransferToOtherBank(t *testing.T) {
+	f := newTestFixture()
+	res, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1"))
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if len(f.bank.transfers) != 1 || f.bank.transfers[0].Order.Amount != "2500" {
+		t.Fatalf("9PSB transfers = %+v", f.bank.transfers)
+	}
+	id, row := f.txns.byKey(f.wallet.WalletID, "key-1")
+	if row == nil || row.status != "SUCCESS" || row.ref != res.TransactionRef || row.providerRef != res.SessionID {
+		t.Errorf("transaction = %+v, result = %+v", row, res)
+	}
+	if len(f.txns.ledger) != 1 || f.txns.ledger[0] != id {
+		t.Errorf("ledger entries = %v, want one for %s", f.txns.ledger, id)
+	}
+}
+
+func TestTransferToOtherBankReplay(t *testing.T) {
+	f := newTestFixture()
+	first, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1"))
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	again, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1"))
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if again.TransactionRef != first.TransactionRef || again.SessionID != first.SessionID {
+		t.Errorf("replay = %+v, want %+v", again, first)
+	}
+	if len(f.bank.transfers) != 1 || len(f.txns.ledger) != 1 || len(f.txns.rows) != 1 {
+		t.Errorf("replay moved money again: %d transfers, %d ledger entries, %d rows", len(f.bank.transfers), len(f.txns.ledger), len(f.txns.rows))
+	}
+}
+
+func TestTransferToOtherBankKeyReusedForAnotherTransfer(t *testing.T) {
+	f := newTestFixture()
+	first, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1"))
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	f.bank.names["000014/0987654321"] = "Bola Ade"
+	other := &TransferToOtherBankParams{
+		UserID: f.userID.String(), Amount: 2500, BankCode: "000014", BeneficiaryName: "Bola Ade",
+		BeneficiaryAccountNumber: "0987654321", IdempotencyKey: "key-1",
+	}
+	for _, p := range []*TransferToOtherBankParams{f.transferParams(3000, "key-1"), other} {
+		res, err := f.svc.TransferToOtherBank(context.Background(), p)
+		if !errors.Is(err, repository.ErrIdempotencyConflict) {
+			t.Errorf("%.2f to %s/%s = %+v, %v; want ErrIdempotencyConflict", p.Amount, p.BankCode, p.BeneficiaryAccountNumber, res, err)
+		}
+	}
+	if len(f.bank.transfers) != 1 || len(f.txns.rows) != 1 {
+		t.Errorf("%d 9PSB transfers, %d rows after %s; want the first only", len(f.bank.transfers), len(f.txns.rows), first.TransactionRef)
+	}
+}
+
+func TestTransferToOtherBankKeyScopedToUser(t *testing.T) {
+	f := newTestFixture()
+	first, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1"))
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	other, _ := f.addUser("1100000002")
+	p := f.transferParams(1000, "key-1")
+	p.UserID = other.String()
+	res, err := f.svc.TransferToOtherBank(context.Background(), p)
+	if err != nil {
+		t.Fatalf("other user's transfer: %v", err)
+	}
+	if res.TransactionRef == first.TransactionRef || len(f.bank.transfers) != 2 {
+		t.Errorf("other user got the first user's transfer: %+v, %d 9PSB transfers", res, len(f.bank.transfers))
+	}
+	if f.bank.transfers[1].Customer.Account.SenderAccountNumber != "1100000002" {
+		t.Errorf("second transfer sent from %s", f.bank.transfers[1].Customer.Account.SenderAccountNumber)
+	}
+}
+
+func TestTransferToOtherBankProviderFailure(t *testing.T) {
+	f := newTestFixture()
+	f.bank.transferErr = errors.New("9psb: beneficiary bank unavailable")
+	if _, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(2500, "key-1")); err == nil {
+		t.Fatal("transfer succeeded although 9PSB failed")
+	}
+	_, row := f.txns.byKey(f.wallet.WalletID, "key-1")
+	if row == nil || row.status != "FAILED" {
+		t.Fatalf("transaction = %+v, want FAILED", row)
+	}
+	if len(f.txns.ledger) != 0 {
+		t.Errorf("ledger entries = %v, want none", f.txns.ledger)
+	}
+	want := kafka.TransferFailedEvent{EventType: "transfer_failed", UserID: f.userID.String(), TransactionRef: row.ref, Amount: 2500, ResponseCode: "99"}
+	if len(f.published.walletEvents) != 1 || f.published.walletEvents[0] != want {
+		t.Errorf("wallet-events = %+v, want %+v", f.published.walletEvents, want)
+	}
+}
+
+func TestTransferToOtherBankRefusals(t *testing.T) {
+	cases := []struct {
+		name   string
+		amount float64
+		setup  func(*testFixture)
+		want   string
+	}{
+		{"insufficient balance", 25000, nil, "insufficient balance"},
+		{"tier 1 single limit", 50000.01, func(f *testFixture) { f.bank.balances[testAccount] = 100000 }, "single transaction limit"},
+		{"tier 1 daily limit", 5000, func(f *testFixture) { f.txns.spend.DailyTransfers = 46000 }, "daily transfer limit"},
+		{"daily limit used up concurrently", 5000, func(f *testFixture) { f.txns.raced.DailyTransfers = 46000 }, "daily transfer limit"},
+		{"name mismatch", 2500, func(f *testFixture) { f.bank.names[testBeneficiaryBank+"/"+testBeneficiaryAcct] = "Someone Else" }, "does not match"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newTestFixture()
+			if c.setup != nil {
+				c.setup(f)
+			}
+			_, err := f.svc.TransferToOtherBank(context.Background(), f.transferParams(c.amount, "key-1"))
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("err = %v, want %q", err, c.want)
+			}
+			if len(f.txns.rows) != 0 || len(f.bank.transfers) != 0 {
+				t.Errorf("refused transfer left %d rows and %d 9PSB transfers", len(f.txns.rows), len(f.bank.transfers))
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_transaction_disputes_updated_at ON transaction_disputes;
+DROP TABLE IF EXISTS dispute_attachments;
+DROP TABLE IF EXISTS dispute_notes;
+DROP TABLE IF EXISTS transaction_disputes;
+DROP TYPE IF EXISTS dispute_actor;
+DROP TYPE IF EXISTS dispute_resolution;
+DROP TYPE IF EXISTS dispute_status;
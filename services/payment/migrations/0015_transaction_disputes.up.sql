@@ -0,0 +1,95 @@
+CREATE TYPE dispute_status     AS ENUM ('OPEN', 'UNDER_REVIEW', 'AWAITING_USER', 'RESOLVED', 'REJECTED');
+CREATE TYPE dispute_resolution AS ENUM ('REVERSAL', 'ADJUSTMENT', 'NO_ACTION');
+CREATE TYPE dispute_actor      AS ENUM ('USER', 'ADMIN', 'SYSTEM');
+
+CREATE TABLE transaction_disputes (
+    id                      UUID                NOT NULL DEFAULT gen_random_uuid(),
+    dispute_ref             VARCHAR(60)         NOT NULL,
+    transaction_id          UUID                NOT NULL,
+    wallet_id               UUID                NOT NULL,
+    user_id                 UUID                NOT NULL,
+
+    reason                  VARCHAR(30)         NOT NULL,
+    description             TEXT                NOT NULL,
+    status                  dispute_status      NOT NULL DEFAULT 'OPEN',
+    assigned_to             VARCHAR(100),
+
+    sla_due_at              TIMESTAMPTZ         NOT NULL,
+    first_response_at       TIMESTAMPTZ,
+
+    resolution              dispute_resolution,
+    resolution_amount       DECIMAL(18,2),
+    resolution_txn_ref      VARCHAR(60),
+    resolution_note         TEXT,
+    resolved_by             VARCHAR(100),
+    resolved_at             TIMESTAMPTZ,
+
+    created_at              TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT transaction_disputes_pkey            PRIMARY KEY (id),
+    CONSTRAINT transaction_disputes_ref_unique      UNIQUE (dispute_ref),
+    CONSTRAINT transaction_disputes_reason_valid    CHECK (reason IN ('NOT_RECEIVED', 'WRONG_AMOUNT', 'DUPLICATE', 'UNAUTHORIZED', 'OTHER')),
+    CONSTRAINT transaction_disputes_amount_positive CHECK (resolution_amount IS NULL OR resolution_amount > 0),
+    CONSTRAINT transaction_disputes_txn_fk          FOREIGN KEY (transaction_id)
+                                                        REFERENCES transactions (id)
+                                                        ON DELETE RESTRICT,
+    CONSTRAINT transaction_disputes_wallet_fk       FOREIGN KEY (wallet_id)
+                                                        REFERENCES wallets (id)
+                                                        ON DELETE RESTRICT
+);
+
+COMMENT ON TABLE transaction_disputes IS 'User-raised disputes against a transaction. Admin queue works OPEN -> UNDER_REVIEW/AWAITING_USER -> RESOLVED/REJECTED.';
+COMMENT ON COLUMN transaction_disputes.sla_due_at IS 'Deadline for resolution; set at open time from DISPUTE_SLA_HOURS';
+COMMENT ON COLUMN transaction_disputes.first_response_at IS 'First admin status change or note (response-time tracking)';
+COMMENT ON COLUMN transaction_disputes.resolution_txn_ref IS 'transaction_ref of the wallet credit/debit posted by the resolution, if any';
+
+-- At most one unresolved dispute per transaction
+CREATE UNIQUE INDEX ux_disputes_open_per_txn ON transaction_disputes (transaction_id)
+    WHERE status IN ('OPEN', 'UNDER_REVIEW', 'AWAITING_USER');
+CREATE INDEX idx_disputes_status_sla ON transaction_disputes (status, sla_due_at);
+CREATE INDEX idx_disputes_user_date ON transaction_disputes (user_id, created_at DESC);
+CREATE INDEX idx_disputes_txn ON transaction_disputes (transaction_id, created_at DESC);
+
+CREATE TABLE dispute_notes (
+    id                      UUID                NOT NULL DEFAULT gen_random_uuid(),
+    dispute_id              UUID                NOT NULL,
+    author_type             dispute_actor       NOT NULL,
+    author_id               VARCHAR(100),
+    body                    TEXT                NOT NULL,
+    is_internal             BOOLEAN             NOT NULL DEFAULT FALSE,
+    created_at              TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT dispute_notes_pkey                   PRIMARY KEY (id),
+    CONSTRAINT dispute_notes_dispute_fk             FOREIGN KEY (dispute_id)
+                                                        REFERENCES transaction_disputes (id)
+                                                        ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN dispute_notes.is_internal IS 'TRUE = admin-only note, never returned to the user';
+
+CREATE INDEX idx_dispute_notes_dispute ON dispute_notes (dispute_id, created_at);
+
+CREATE TABLE dispute_attachments (
+    id                      UUID                NOT NULL DEFAULT gen_random_uuid(),
+    dispute_id              UUID                NOT NULL,
+    uploaded_by_type        dispute_actor       NOT NULL,
+    uploaded_by             VARCHAR(100),
+    file_name               VARCHAR(255)        NOT NULL,
+    content_type            VARCHAR(100)        NOT NULL,
+    url                     TEXT                NOT NULL,
+    created_at              TIMESTAMPTZ         NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT dispute_attachments_pkey             PRIMARY KEY (id),
+    CONSTRAINT dispute_attachments_dispute_fk       FOREIGN KEY (dispute_id)
+                                                        REFERENCES transaction_disputes (id)
+                                                        ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN dispute_attachments.url IS 'Location of the uploaded evidence (receipt, screenshot, bank statement)';
+
+CREATE INDEX idx_dispute_attachments_dispute ON dispute_attachments (dispute_id, created_at);
+
+CREATE TRIGGER trg_transaction_disputes_updated_at
+    BEFORE UPDATE ON transaction_disputes
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
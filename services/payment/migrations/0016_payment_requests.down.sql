@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_payment_requests_updated_at ON payment_requests;
+DROP TABLE IF EXISTS payment_requests;
+DROP TYPE IF EXISTS payment_request_status;
@@ -0,0 +1,54 @@
+CREATE TYPE payment_request_status AS ENUM ('PENDING', 'PAID', 'DECLINED', 'EXPIRED');
+
+CREATE TABLE payment_requests (
+    id                      UUID                    NOT NULL DEFAULT gen_random_uuid(),
+    request_ref             VARCHAR(20)             NOT NULL,
+
+    requester_user_id       UUID                    NOT NULL,
+    requester_wallet_id     UUID                    NOT NULL,
+    payer_user_id           UUID,
+    payer_wallet_id         UUID,
+
+    amount                  DECIMAL(18,2)           NOT NULL,
+    note                    VARCHAR(255)            NOT NULL DEFAULT '',
+    status                  payment_request_status  NOT NULL DEFAULT 'PENDING',
+    expires_at              TIMESTAMPTZ             NOT NULL,
+    pay_claimed_at          TIMESTAMPTZ,
+
+    paid_by_user_id         UUID,
+    paid_amount             DECIMAL(18,2),
+    paid_txn_ref            VARCHAR(60),
+    paid_via                VARCHAR(20),
+    paid_at                 TIMESTAMPTZ,
+
+    decline_reason          VARCHAR(255),
+    declined_at             TIMESTAMPTZ,
+
+    created_at              TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT payment_requests_pkey                PRIMARY KEY (id),
+    CONSTRAINT payment_requests_ref_unique          UNIQUE (request_ref),
+    CONSTRAINT payment_requests_amount_positive     CHECK (amount > 0),
+    CONSTRAINT payment_requests_paid_via_valid      CHECK (paid_via IS NULL OR paid_via IN ('IN_APP', 'BANK_TRANSFER')),
+    CONSTRAINT payment_requests_not_self            CHECK (payer_user_id IS NULL OR payer_user_id <> requester_user_id),
+    CONSTRAINT payment_requests_requester_wallet_fk FOREIGN KEY (requester_wallet_id)
+                                                        REFERENCES wallets (id)
+                                                        ON DELETE RESTRICT,
+    CONSTRAINT payment_requests_payer_wallet_fk     FOREIGN KEY (payer_wallet_id)
+                                                        REFERENCES wallets (id)
+                                                        ON DELETE RESTRICT
+);
+
+COMMENT ON TABLE payment_requests IS 'Money requests. PENDING -> PAID | DECLINED | EXPIRED. A NULL payer is an open pay link anyone can settle.';
+COMMENT ON COLUMN payment_requests.request_ref IS 'Public code used in the pay link and as the narration reference for bank-transfer funding';
+COMMENT ON COLUMN payment_requests.pay_claimed_at IS 'Set while an in-app payment is in flight so the request cannot be settled twice; cleared if the transfer fails';
+COMMENT ON COLUMN payment_requests.paid_txn_ref IS 'transaction_ref of the settling transfer (IN_APP) or inbound credit (BANK_TRANSFER)';
+
+CREATE INDEX idx_payment_requests_requester ON payment_requests (requester_user_id, created_at DESC);
+CREATE INDEX idx_payment_requests_payer ON payment_requests (payer_user_id, created_at DESC) WHERE payer_user_id IS NOT NULL;
+CREATE INDEX idx_payment_requests_pending_expiry ON payment_requests (expires_at) WHERE status = 'PENDING';
+
+CREATE TRIGGER trg_payment_requests_updated_at
+    BEFORE UPDATE ON payment_requests
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,210 @@
+CREATE TYPE savings_pocket_status AS ENUM ('ACTIVE', 'CLOSED');
+
+CREATE TABLE savings_pockets (
+    id                          UUID                    NOT NULL DEFAULT gen_random_uuid(),
+    pocket_ref                  VARCHAR(20)             NOT NULL,
+    wallet_id                   UUID                    NOT NULL,
+    user_id                     UUID                    NOT NULL,
+
+    name                        VARCHAR(60)             NOT NULL,
+    goal_amount                 DECIMAL(18,2),
+    balance                     DECIMAL(18,2)           NOT NULL DEFAULT 0.00,
+    locked_until                TIMESTAMPTZ,
+    status                      savings_pocket_status   NOT NULL DEFAULT 'ACTIVE',
+
+    autosave_inbound_percent    DECIMAL(5,2)            NOT NULL DEFAULT 0,
+    autosave_roundup_to         DECIMAL(18,2)           NOT NULL DEFAULT 0,
+
+    interest_accrued            DECIMAL(18,4)           NOT NULL DEFAULT 0,
+    interest_accrued_through    DATE,
+
+    closed_at                   TIMESTAMPTZ,
+    created_at                  TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    updated_at                  TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT savings_pockets_pkey                 PRIMARY KEY (id),
+    CONSTRAINT savings_pockets_ref_unique           UNIQUE (pocket_ref),
+    CONSTRAINT savings_pockets_balance_nn           CHECK (balance >= 0),
+    CONSTRAINT savings_pockets_goal_positive        CHECK (goal_amount IS NULL OR goal_amount > 0),
+    CONSTRAINT savings_pockets_inbound_pct_valid    CHECK (autosave_inbound_percent >= 0 AND autosave_inbound_percent <= 50),
+    CONSTRAINT savings_pockets_roundup_valid        CHECK (autosave_roundup_to IN (0, 10, 50, 100, 500, 1000)),
+    CONSTRAINT savings_pockets_closed_empty         CHECK (status = 'ACTIVE' OR balance = 0),
+    CONSTRAINT savings_pockets_wallet_fk            FOREIGN KEY (wallet_id)
+                                                        REFERENCES wallets (id)
+                                                        ON DELETE RESTRICT
+);
+
+COMMENT ON TABLE savings_pockets IS 'Goal-based sub-balances of a wallet. Funds in a pocket are part of the 9PSB balance but not of wallets.available_balance.';
+COMMENT ON COLUMN savings_pockets.balance IS 'Only changed by post_pocket_transfer(); guarded like wallet balances';
+COMMENT ON COLUMN savings_pockets.locked_until IS 'Withdrawals (and closing) are refused before this time';
+COMMENT ON COLUMN savings_pockets.autosave_inbound_percent IS 'Share of every inbound credit swept into the pocket (0 = off)';
+COMMENT ON COLUMN savings_pockets.autosave_roundup_to IS 'Round outbound transfers up to this multiple and sweep the difference into the pocket (0 = off)';
+COMMENT ON COLUMN savings_pockets.interest_accrued IS 'Interest accrued but not yet paid out; not part of balance';
+
+CREATE UNIQUE INDEX idx_savings_pockets_wallet_name ON savings_pockets (wallet_id, lower(name)) WHERE status = 'ACTIVE';
+CREATE INDEX idx_savings_pockets_user ON savings_pockets (user_id, created_at);
+CREATE INDEX idx_savings_pockets_autosave ON savings_pockets (wallet_id)
+    WHERE status = 'ACTIVE' AND (autosave_inbound_percent > 0 OR autosave_roundup_to > 0);
+
+CREATE TRIGGER trg_savings_pockets_updated_at
+    BEFORE UPDATE ON savings_pockets
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE OR REPLACE FUNCTION guard_pocket_balance_update()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.balance IS DISTINCT FROM OLD.balance
+    AND current_setting('app.allow_balance_update', true) IS DISTINCT FROM 'true'
+    THEN
+        RAISE EXCEPTION
+            'Direct pocket balance update is forbidden. Use post_pocket_transfer() instead. '
+            'pocket_id=%, attempted balance=%',
+            NEW.id, NEW.balance;
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_savings_pockets_balance_guard
+    BEFORE UPDATE ON savings_pockets
+    FOR EACH ROW EXECUTE FUNCTION guard_pocket_balance_update();
+
+-- Pocket sub-account entries live in the main ledger next to the wallet entry they offset, so a move is a balanced
+-- DEBIT/CREDIT pair under one transaction.
+ALTER TABLE transaction_ledger ADD COLUMN pocket_id UUID REFERENCES savings_pockets (id) ON DELETE RESTRICT;
+COMMENT ON COLUMN transaction_ledger.pocket_id IS 'Set for pocket sub-account entries; balance_before/after then refer to savings_pockets.balance';
+CREATE INDEX idx_ledger_pocket_date ON transaction_ledger (pocket_id, created_at DESC) WHERE pocket_id IS NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN pocket_id UUID REFERENCES savings_pockets (id) ON DELETE RESTRICT;
+COMMENT ON COLUMN transactions.pocket_id IS 'Set for moves between the main balance and a savings pocket (no 9PSB call)';
+
+CREATE OR REPLACE FUNCTION post_pocket_transfer(
+    p_transaction_id    UUID,
+    p_pocket_id         UUID,
+    p_to_pocket         BOOLEAN,
+    p_amount            DECIMAL(18,2),
+    p_narrative         VARCHAR(255) DEFAULT NULL,
+    p_ignore_lock       BOOLEAN DEFAULT FALSE
+)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_wallet_id         UUID;
+    v_status            savings_pocket_status;
+    v_locked_until      TIMESTAMPTZ;
+    v_balance_before    DECIMAL(18,2);
+    v_balance_after     DECIMAL(18,2);
+    v_ledger_id         UUID;
+BEGIN
+    SELECT wallet_id, status, locked_until, balance
+    INTO   v_wallet_id, v_status, v_locked_until, v_balance_before
+    FROM   savings_pockets
+    WHERE  id = p_pocket_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Pocket not found: %', p_pocket_id;
+    END IF;
+    IF v_status <> 'ACTIVE' THEN
+        RAISE EXCEPTION 'Pocket is closed: %', p_pocket_id;
+    END IF;
+
+    IF p_to_pocket THEN
+        -- Main balance first: post_ledger_entry raises if it would go negative.
+        PERFORM post_ledger_entry(p_transaction_id, v_wallet_id, 'DEBIT', p_amount, 'NGN', p_narrative);
+        v_balance_after := v_balance_before + p_amount;
+    ELSE
+        IF NOT p_ignore_lock AND v_locked_until IS NOT NULL AND v_locked_until > NOW() THEN
+            RAISE EXCEPTION 'Pocket is locked until %', v_locked_until;
+        END IF;
+        v_balance_after := v_balance_before - p_amount;
+        IF v_balance_after < 0 THEN
+            RAISE EXCEPTION
+                'Insufficient pocket balance. pocket_id=%, balance=%, debit_amount=%',
+                p_pocket_id, v_balance_before, p_amount;
+        END IF;
+    END IF;
+
+    INSERT INTO transaction_ledger
+        (transaction_id, wallet_id, pocket_id, entry_type, amount,
+         balance_before, balance_after, currency, narrative)
+    VALUES
+        (p_transaction_id, v_wallet_id, p_pocket_id,
+         CASE WHEN p_to_pocket THEN 'CREDIT' ELSE 'DEBIT' END::ledger_entry_type, p_amount,
+         v_balance_before, v_balance_after, 'NGN', p_narrative)
+    RETURNING id INTO v_ledger_id;
+
+    SET LOCAL app.allow_balance_update = 'true';
+
+    UPDATE savings_pockets
+    SET    balance = v_balance_after
+    WHERE  id = p_pocket_id;
+
+    IF NOT p_to_pocket THEN
+        PERFORM post_ledger_entry(p_transaction_id, v_wallet_id, 'CREDIT', p_amount, 'NGN', p_narrative);
+    END IF;
+
+    RETURN v_ledger_id;
+END;
+$$;
+
+COMMENT ON FUNCTION post_pocket_transfer IS
+    'The ONLY authorised way to move funds between a wallet main balance and one of its savings pockets. '
+    'Posts the pocket entry (pocket_id set) and the offsetting wallet entry via post_ledger_entry() in the caller''s transaction, '
+    'so available_balance + SUM(pocket balances) is unchanged. Raises if either side would go negative or the pocket is locked.';
+
+-- The provider balance includes pocket funds; the main balance synced from it must not.
+CREATE OR REPLACE FUNCTION post_ledger_entry_from_provider(
+    p_transaction_id       UUID,
+    p_wallet_id            UUID,
+    p_amount               DECIMAL(18,2),
+    p_available_after      DECIMAL(18,2),
+    p_ledger_after         DECIMAL(18,2) DEFAULT NULL,
+    p_narrative            VARCHAR(255) DEFAULT NULL
+)
+RETURNS UUID
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    v_pockets           DECIMAL(18,2);
+    v_balance_before    DECIMAL(18,2);
+    v_balance_after     DECIMAL(18,2);
+    v_ledger_after      DECIMAL(18,2);
+    v_ledger_id         UUID;
+BEGIN
+    PERFORM 1 FROM wallets WHERE id = p_wallet_id FOR UPDATE;
+
+    SELECT COALESCE(SUM(balance), 0)
+    INTO   v_pockets
+    FROM   savings_pockets
+    WHERE  wallet_id = p_wallet_id AND status = 'ACTIVE';
+
+    v_balance_after := p_available_after - v_pockets;
+    v_balance_before := v_balance_after + p_amount;
+    v_ledger_after := COALESCE(p_ledger_after, p_available_after) - v_pockets;
+
+    INSERT INTO transaction_ledger
+        (transaction_id, wallet_id, entry_type, amount,
+         balance_before, balance_after, currency, narrative)
+    VALUES
+        (p_transaction_id, p_wallet_id, 'DEBIT', p_amount,
+         v_balance_before, v_balance_after, 'NGN', p_narrative)
+    RETURNING id INTO v_ledger_id;
+
+    SET LOCAL app.allow_balance_update = 'true';
+
+    UPDATE wallets
+    SET    available_balance = v_balance_after,
+           ledger_balance    = v_ledger_after,
+           updated_at        = NOW()
+    WHERE  id = p_wallet_id;
+
+    RETURN v_ledger_id;
+END;
+$$;
+
+COMMENT ON FUNCTION post_ledger_entry_from_provider IS
+    'Posts a DEBIT using provider (9PSB) post-debit balances, less the wallet''s savings pocket balances. Use when transfer succeeded at provider but local balance was stale. Sets app.allow_balance_update so guard allows the update.';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_bill_payments_updated_at ON bill_payments;
+DROP TABLE IF EXISTS bill_payments;
+DROP TYPE IF EXISTS bill_payment_status;
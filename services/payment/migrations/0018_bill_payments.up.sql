@@ -0,0 +1,63 @@
+CREATE TYPE bill_payment_status AS ENUM ('PENDING', 'SUCCESS', 'FAILED', 'REVERSED');
+
+CREATE TABLE bill_payments (
+    id                  UUID                    NOT NULL DEFAULT gen_random_uuid(),
+    bill_ref            VARCHAR(60)             NOT NULL,
+    user_id             UUID                    NOT NULL,
+    wallet_id           UUID                    NOT NULL,
+    transaction_id      UUID                    NOT NULL,
+
+    provider            VARCHAR(30)             NOT NULL,
+    category            VARCHAR(20)             NOT NULL,
+    biller_code         VARCHAR(50)             NOT NULL,
+    biller_name         VARCHAR(100)            NOT NULL,
+    item_code           VARCHAR(50)             NOT NULL,
+    item_name           VARCHAR(100)            NOT NULL,
+    customer_id         VARCHAR(50)             NOT NULL,
+    customer_name       VARCHAR(150),
+    phone               VARCHAR(20),
+    amount              DECIMAL(18,2)           NOT NULL,
+
+    status              bill_payment_status     NOT NULL DEFAULT 'PENDING',
+    debit_ref           VARCHAR(100),
+    debited_at          TIMESTAMPTZ,
+    provider_ref        VARCHAR(100),
+    enc_token           BYTEA,
+    units               VARCHAR(50),
+    failure_reason      VARCHAR(255),
+    reversal_txn_id     UUID,
+    delivery_channel    VARCHAR(10)             NOT NULL DEFAULT 'sms',
+    token_delivered_at  TIMESTAMPTZ,
+    completed_at        TIMESTAMPTZ,
+
+    created_at          TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT bill_payments_pkey               PRIMARY KEY (id),
+    CONSTRAINT bill_payments_ref_unique         UNIQUE (bill_ref),
+    CONSTRAINT bill_payments_txn_unique         UNIQUE (transaction_id),
+    CONSTRAINT bill_payments_amount_positive    CHECK (amount > 0),
+    CONSTRAINT bill_payments_delivery_valid     CHECK (delivery_channel IN ('sms', 'whatsapp')),
+    CONSTRAINT bill_payments_wallet_fk          FOREIGN KEY (wallet_id)
+                                                    REFERENCES wallets (id)
+                                                    ON DELETE RESTRICT,
+    CONSTRAINT bill_payments_txn_fk             FOREIGN KEY (transaction_id)
+                                                    REFERENCES transactions (id)
+                                                    ON DELETE RESTRICT,
+    CONSTRAINT bill_payments_reversal_txn_fk    FOREIGN KEY (reversal_txn_id)
+                                                    REFERENCES transactions (id)
+                                                    ON DELETE RESTRICT
+);
+
+COMMENT ON TABLE bill_payments IS 'Airtime, data, electricity and TV purchases. PENDING -> SUCCESS | FAILED (never debited) | REVERSED (debited, then refunded).';
+COMMENT ON COLUMN bill_payments.bill_ref IS 'Our order reference sent to the aggregator; equals transactions.transaction_ref';
+COMMENT ON COLUMN bill_payments.debit_ref IS '9PSB WaaS reference of the wallet debit';
+COMMENT ON COLUMN bill_payments.debited_at IS 'Set once the wallet debit is on the ledger; a failed vend after this must be reversed';
+COMMENT ON COLUMN bill_payments.enc_token IS 'Encrypted electricity token (AES-256-GCM)';
+
+CREATE INDEX idx_bill_payments_user ON bill_payments (user_id, created_at DESC);
+CREATE INDEX idx_bill_payments_pending ON bill_payments (created_at) WHERE status = 'PENDING';
+
+CREATE TRIGGER trg_bill_payments_updated_at
+    BEFORE UPDATE ON bill_payments
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,4 @@
+COMMENT ON COLUMN savings_pockets.interest_accrued IS 'Interest accrued but not yet paid out; not part of balance';
+ALTER TABLE savings_pockets DROP COLUMN IF EXISTS interest_paid;
+DROP TABLE IF EXISTS pocket_interest_payouts;
+DROP TYPE IF EXISTS pocket_interest_payout_status;
@@ -0,0 +1,40 @@
+-- Accrued pocket interest is paid once a month. The whole-kobo part of interest_accrued is first set aside in a payout
+-- row, which fixes the amount and the 9PSB reference; 9PSB then credits the wallet from the interest funding account and
+-- the payout is posted into the pocket. A retry after a crash pays the same amount under the same reference.
+CREATE TYPE pocket_interest_payout_status AS ENUM ('PENDING', 'PAID');
+
+CREATE TABLE pocket_interest_payouts (
+    id                  UUID                            NOT NULL DEFAULT gen_random_uuid(),
+    pocket_id           UUID                            NOT NULL,
+    wallet_id           UUID                            NOT NULL,
+    user_id             UUID                            NOT NULL,
+    period              CHAR(7)                         NOT NULL,
+    amount              DECIMAL(18,2)                   NOT NULL,
+    transaction_ref     VARCHAR(60)                     NOT NULL,
+    status              pocket_interest_payout_status   NOT NULL DEFAULT 'PENDING',
+    provider_ref        VARCHAR(100),
+    transaction_id      UUID,
+    created_at          TIMESTAMPTZ                     NOT NULL DEFAULT NOW(),
+    paid_at             TIMESTAMPTZ,
+
+    CONSTRAINT pocket_interest_payouts_pkey             PRIMARY KEY (id),
+    CONSTRAINT pocket_interest_payouts_period_unique    UNIQUE (pocket_id, period),
+    CONSTRAINT pocket_interest_payouts_ref_unique       UNIQUE (transaction_ref),
+    CONSTRAINT pocket_interest_payouts_amount_positive  CHECK (amount > 0),
+    CONSTRAINT pocket_interest_payouts_pocket_fk        FOREIGN KEY (pocket_id)
+                                                            REFERENCES savings_pockets (id)
+                                                            ON DELETE RESTRICT,
+    CONSTRAINT pocket_interest_payouts_txn_fk           FOREIGN KEY (transaction_id)
+                                                            REFERENCES transactions (id)
+                                                            ON DELETE RESTRICT
+);
+
+COMMENT ON TABLE pocket_interest_payouts IS 'Monthly pocket interest payouts; the amount is taken off savings_pockets.interest_accrued when the row is created';
+COMMENT ON COLUMN pocket_interest_payouts.period IS 'YYYY-MM the payout was made for; one payout per pocket per month';
+COMMENT ON COLUMN pocket_interest_payouts.transaction_ref IS 'Sent to 9PSB as the credit transactionId and reused on retry';
+
+CREATE INDEX idx_pocket_interest_payouts_pending ON pocket_interest_payouts (created_at) WHERE status = 'PENDING';
+
+ALTER TABLE savings_pockets ADD COLUMN interest_paid DECIMAL(18,2) NOT NULL DEFAULT 0;
+COMMENT ON COLUMN savings_pockets.interest_paid IS 'Interest paid out to date: into the pocket, or to the main balance once the pocket is closed';
+COMMENT ON COLUMN savings_pockets.interest_accrued IS 'Interest accrued and not yet set aside for a payout; not part of balance';
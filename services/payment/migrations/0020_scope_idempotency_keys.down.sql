@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ux_transactions_wallet_idempotency;
+CREATE UNIQUE INDEX ux_transactions_idempotency ON transactions (idempotency_key) WHERE idempotency_key IS NOT NULL;
+ALTER TABLE transactions ADD CONSTRAINT transactions_idempotency_unique UNIQUE (idempotency_key);
@@ -0,0 +1,6 @@
+-- Idempotency keys are chosen by the client, so two users may send the same one. Keys were unique across all
+-- transactions, which let one user's key find (and replay) another user's transaction or bill. Each wallet belongs to
+-- one user, so keys are now unique per wallet and every lookup is scoped to the caller's wallet.
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_idempotency_unique;
+DROP INDEX IF EXISTS ux_transactions_idempotency;
+CREATE UNIQUE INDEX ux_transactions_wallet_idempotency ON transactions (wallet_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Postgres cannot drop an enum value, so PAYMENT_PENDING stays in dispute_status. Pending resolutions go back to
+-- UNDER_REVIEW with their resolution kept, for an admin to check the wallet before resolving again.
+UPDATE transaction_disputes SET status = 'UNDER_REVIEW' WHERE status = 'PAYMENT_PENDING';
+ALTER TABLE transaction_disputes DROP COLUMN IF EXISTS resolution_is_credit;
@@ -0,0 +1,6 @@
+-- A resolution whose wallet payment was attempted but not confirmed (a 9PSB timeout, say) stays PAYMENT_PENDING and is
+-- retried with the same idempotency key; releasing it back to the admin queue could pay the user twice.
+ALTER TYPE dispute_status ADD VALUE IF NOT EXISTS 'PAYMENT_PENDING' AFTER 'AWAITING_USER';
+ALTER TABLE transaction_disputes ADD COLUMN IF NOT EXISTS resolution_is_credit BOOLEAN;
+
+COMMENT ON COLUMN transaction_disputes.resolution_is_credit IS 'Whether the resolution payment credits (true) or debits (false) the wallet; NULL when no money moves';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ux_disputes_open_per_txn;
+CREATE UNIQUE INDEX ux_disputes_open_per_txn ON transaction_disputes (transaction_id)
+    WHERE status IN ('OPEN', 'UNDER_REVIEW', 'AWAITING_USER');
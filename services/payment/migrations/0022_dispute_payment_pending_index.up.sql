@@ -0,0 +1,5 @@
+-- A dispute whose resolution payment is pending is still unresolved: no second dispute may be opened on its transaction.
+-- Separate from 0021 because a new enum value cannot be used in the transaction that adds it.
+DROP INDEX IF EXISTS ux_disputes_open_per_txn;
+CREATE UNIQUE INDEX ux_disputes_open_per_txn ON transaction_disputes (transaction_id)
+    WHERE status IN ('OPEN', 'UNDER_REVIEW', 'AWAITING_USER', 'PAYMENT_PENDING');
@@ -2,10 +2,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/gateway"
+	"github.com/abubakvr/payup-backend/services/user/internal/clients"
 	"github.com/abubakvr/payup-backend/services/user/internal/config"
 	"github.com/abubakvr/payup-backend/services/user/internal/controller"
 	"github.com/abubakvr/payup-backend/services/user/internal/grpc"
@@ -13,6 +16,7 @@ import (
 	"github.com/abubakvr/payup-backend/services/user/internal/repository"
 	"github.com/abubakvr/payup-backend/services/user/internal/router"
 	"github.com/abubakvr/payup-backend/services/user/internal/service"
+	"github.com/abubakvr/payup-backend/services/user/internal/webauthn"
 	"github.com/abubakvr/payup-backend/services/user/redis"
 	userpb "github.com/abubakvr/payup-backend/proto/user"
 	grpclib "google.golang.org/grpc"
@@ -30,11 +34,82 @@ func main() {
 	userRepo := repository.NewUserRepository(db, tokenGen)
 	producer := kafka.NewProducer([]string{cfg.KafkaBroker})
 	userExistsTTL := time.Duration(cfg.UserExistsCacheTTLSeconds) * time.Second
-	userSvc := service.NewUserService(userRepo, tokenGen, producer, cfg.EmailVerificationBaseURL, cfg.PasswordResetBaseURL, userExistsTTL)
+	redis.InitRedis()
+	stepUpActions := cfg.StepUpActions
+	if stepUpActions == nil {
+		stepUpActions = service.DefaultStepUpActions
+	}
+	stepUp := service.NewStepUpPolicy(stepUpActions, cfg.StepUpTransferThreshold)
+	kycClient, err := clients.NewKYCClient(cfg.KYCServiceGrpcAddr)
+	if err != nil {
+		log.Fatalf("kyc client: %v", err)
+	}
+	defer kycClient.Close()
+	paymentClient, err := clients.NewPaymentClient(cfg.PaymentServiceGrpcAddr)
+	if err != nil {
+		log.Fatalf("payment client: %v", err)
+	}
+	defer paymentClient.Close()
+	auditClient, err := clients.NewAuditClient(cfg.AuditServiceGrpcAddr)
+	if err != nil {
+		log.Fatalf("audit client: %v", err)
+	}
+	defer auditClient.Close()
+	privacy := service.PrivacyConfig{KYC: kycClient, Payment: paymentClient, Audit: auditClient, DeletionGrace: cfg.AccountDeletionGrace}
+	referralRules := cfg.ReferralRules
+	if referralRules == nil {
+		referralRules = service.DefaultReferralRules()
+	}
+	referrals := service.ReferralConfig{Rules: referralRules, MaxRewardedPerReferrer: cfg.ReferralMaxRewardedPerReferrer, Payment: paymentClient}
+	userSvc := service.NewUserService(userRepo, tokenGen, producer, cfg.EmailVerificationBaseURL, cfg.PasswordResetBaseURL, userExistsTTL, redis.Client(), stepUp,
+		webauthn.Config{RPID: cfg.WebAuthnRPID, RPName: cfg.WebAuthnRPName, Origins: cfg.WebAuthnOrigins}, cfg.LimitIncreaseDelay, cfg.ContactChangeCooldown, privacy, referrals)
 	userCtrl := controller.NewUserController(userSvc)
-	r := router.SetupRouter(cfg, userCtrl)
+	r := router.SetupRouter(cfg, userCtrl, userSvc.Authenticator())
+	if err := gateway.TrustProxies(r, cfg.TrustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 
-	redis.InitRedis()
+	// Apply transfer limit increases whose cooling-off has ended and notify the users.
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := userSvc.ApplyDueLimitChanges(context.Background()); err != nil {
+				log.Printf("user: apply limit increases: %v", err)
+			} else if n > 0 {
+				log.Printf("user: applied %d limit increase(s)", n)
+			}
+		}
+	}()
+
+	// Build data exports and run account deletions whose grace period has ended.
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := userSvc.ProcessDataSubjectRequests(context.Background()); err != nil {
+				log.Printf("user: process data-subject requests: %v", err)
+			} else if n > 0 {
+				log.Printf("user: finished %d data-subject request(s)", n)
+			}
+		}
+	}()
+
+	// Referral milestones: kyc_approved from KYC, transfer_success from payment.
+	go kafka.NewMilestoneConsumer([]string{cfg.KafkaBroker}, userSvc.HandleReferralEvent).Start()
+
+	// Pay referral rewards queued by the milestone consumer.
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := userSvc.ProcessReferralRewards(context.Background()); err != nil {
+				log.Printf("user: pay referral rewards: %v", err)
+			} else if n > 0 {
+				log.Printf("user: paid %d referral reward(s)", n)
+			}
+		}
+	}()
 
 	// gRPC server for KYC service (GetUserForKYC)
 	go func() {
@@ -46,6 +121,7 @@ func main() {
 		userpb.RegisterUserServiceForKYCServer(srv, grpc.NewKYCUserServer(userRepo))
 		userpb.RegisterUserServiceForAdminServer(srv, grpc.NewAdminUserServer(userRepo, userSvc))
 		userpb.RegisterUserServiceForPaymentServer(srv, grpc.NewPaymentUserServer(userSvc))
+		userpb.RegisterUserServiceForNotificationServer(srv, grpc.NewNotificationUserServer(userSvc))
 		log.Printf("User gRPC (KYC) listening on port %s", cfg.GrpcPort)
 		if err := srv.Serve(lis); err != nil {
 			log.Fatalf("grpc serve: %v", err)
@@ -3,6 +3,7 @@ module github.com/abubakvr/payup-backend/services/user
 go 1.25.0
 
 require (
+	github.com/abubakvr/payup-backend/pkg v0.0.0
 	github.com/abubakvr/payup-backend/proto v0.0.0
 	github.com/gin-gonic/gin v1.11.0
 	github.com/go-playground/validator/v10 v10.27.0
@@ -10,8 +11,9 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/jackc/pgx/v5 v5.8.0
 	github.com/pquerna/otp v1.5.0
-	github.com/redis/go-redis/v9 v9.17.3
+	github.com/redis/go-redis/v9 v9.18.0
 	github.com/segmentio/kafka-go v0.4.50
+	github.com/ugorji/go/codec v1.3.0
 	golang.org/x/crypto v0.48.0
 	google.golang.org/grpc v1.79.0
 )
@@ -44,7 +46,7 @@ require (
 	github.com/quic-go/qpack v0.5.1 // indirect
 	github.com/quic-go/quic-go v0.54.0 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
-	github.com/ugorji/go/codec v1.3.0 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
 	go.uber.org/mock v0.5.0 // indirect
 	golang.org/x/arch v0.20.0 // indirect
 	golang.org/x/mod v0.32.0 // indirect
@@ -57,4 +59,6 @@ require (
 	google.golang.org/protobuf v1.36.11 // indirect
 )
 
+replace github.com/abubakvr/payup-backend/pkg => ../../pkg
+
 replace github.com/abubakvr/payup-backend/proto => ../../proto
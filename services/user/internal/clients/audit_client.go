@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"context"
+
+	auditpb "github.com/abubakvr/payup-backend/proto/audit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// AuditClient calls the audit service gRPC (GetUserAudits for data exports).
+type AuditClient struct {
+	client auditpb.AuditServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewAuditClient dials the audit service. Returns nil, nil if addr is empty. Call Close when done.
+func NewAuditClient(addr string) (*AuditClient, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &AuditClient{client: auditpb.NewAuditServiceClient(conn), conn: conn}, nil
+}
+
+func (c *AuditClient) Close() error {
+	if c != nil && c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// GetUserAudits returns one page of the user's audit trail, newest first, with the total count.
+func (c *AuditClient) GetUserAudits(ctx context.Context, userID string, limit, offset int32) (*auditpb.AuditResponse, error) {
+	return c.client.GetUserAudits(ctx, &auditpb.UserAuditRequest{UserId: userID, Limit: limit, Offset: offset})
+}
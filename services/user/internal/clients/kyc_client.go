@@ -0,0 +1,44 @@
+package clients
+
+import (
+	"context"
+
+	kycpb "github.com/abubakvr/payup-backend/proto/kyc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// KYCClient calls the KYC service gRPC (ExportUserData, EraseUserData for data-subject requests).
+type KYCClient struct {
+	client kycpb.KYCServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewKYCClient dials the KYC service. Returns nil, nil if addr is empty. Call Close when done.
+func NewKYCClient(addr string) (*KYCClient, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &KYCClient{client: kycpb.NewKYCServiceClient(conn), conn: conn}, nil
+}
+
+func (c *KYCClient) Close() error {
+	if c != nil && c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// ExportUserData returns the user's KYC records as JSON (Found false if KYC was never started).
+func (c *KYCClient) ExportUserData(ctx context.Context, userID string) (*kycpb.ExportUserDataResponse, error) {
+	return c.client.ExportUserData(ctx, &kycpb.ExportUserDataRequest{UserId: userID})
+}
+
+// EraseUserData deletes the user's KYC records, or reports them retained under AML record keeping.
+func (c *KYCClient) EraseUserData(ctx context.Context, userID string) (*kycpb.EraseUserDataResponse, error) {
+	return c.client.EraseUserData(ctx, &kycpb.EraseUserDataRequest{UserId: userID})
+}
@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"context"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// PaymentClient calls the payment service gRPC (ExportUserData, EraseUserData for data-subject requests; CreditWallet
+// for referral rewards).
+type PaymentClient struct {
+	client paymentpb.PaymentServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewPaymentClient dials the payment service. Returns nil, nil if addr is empty. Call Close when done.
+func NewPaymentClient(addr string) (*PaymentClient, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentClient{client: paymentpb.NewPaymentServiceClient(conn), conn: conn}, nil
+}
+
+func (c *PaymentClient) Close() error {
+	if c != nil && c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// ExportUserData returns the user's wallets, transactions, pockets and disputes as JSON (Found false without a wallet).
+func (c *PaymentClient) ExportUserData(ctx context.Context, userID string) (*paymentpb.ExportUserDataResponse, error) {
+	return c.client.ExportUserData(ctx, &paymentpb.ExportUserDataRequest{UserId: userID})
+}
+
+// EraseUserData anonymises the user's wallets, or reports the holds (balance, pending payments, open disputes) that
+// prevent it.
+func (c *PaymentClient) EraseUserData(ctx context.Context, userID string) (*paymentpb.EraseUserDataResponse, error) {
+	return c.client.EraseUserData(ctx, &paymentpb.EraseUserDataRequest{UserId: userID})
+}
+
+// CreditWallet credits the user's wallet. A repeat with the same idempotencyKey returns the original transaction.
+func (c *PaymentClient) CreditWallet(ctx context.Context, userID string, amount float64, narration, idempotencyKey string) (*paymentpb.DebitCreditWalletResponse, error) {
+	return c.client.DebitCreditWallet(ctx, &paymentpb.DebitCreditWalletRequest{
+		UserId:         userID,
+		Amount:         amount,
+		IsCredit:       true,
+		Narration:      narration,
+		InitiatedBy:    "system",
+		IdempotencyKey: idempotencyKey,
+	})
+}
@@ -7,6 +7,7 @@ const (
 	ValidationError      ErrorCode = "02"
 	AuthenticationFailed ErrorCode = "AUTH_401"
 	AuthorizationFailed  ErrorCode = "AUTH_403"
+	StepUpRequired       ErrorCode = "STEP_UP_403"
 	ResourceNotFound     ErrorCode = "04"
 	InternalServerError  ErrorCode = "99"
 )
@@ -2,10 +2,15 @@ package config
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/gateway"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -19,6 +24,39 @@ type Config struct {
 	AdminAPIKey              string // If set, admin routes require X-Admin-Key header
 	// UserExistsCacheTTLSeconds is the TTL for Redis cache "user exists" (auth validate). Default 900 (15 min).
 	UserExistsCacheTTLSeconds int
+	// StepUpActions lists the actions that need a step-up token (STEP_UP_ACTIONS, comma-separated; "none" for none).
+	// Nil means the service defaults.
+	StepUpActions []string
+	// StepUpTransferThreshold is the transfer amount (naira) from which a step-up token is needed. 0 disables it.
+	StepUpTransferThreshold float64
+	// WebAuthn relying party for passkeys: RP ID is the domain passkeys are bound to (WEBAUTHN_RP_ID); origins are the
+	// web and app origins ceremonies may come from (WEBAUTHN_ORIGINS, comma-separated).
+	WebAuthnRPID    string
+	WebAuthnRPName  string
+	WebAuthnOrigins []string
+	// LimitIncreaseDelay is the cooling-off before raised transfer limits take effect (LIMIT_INCREASE_DELAY_HOURS,
+	// default 24; 0 applies increases at once).
+	LimitIncreaseDelay time.Duration
+	// ContactChangeCooldown is the minimum time between two email or two phone number changes
+	// (CONTACT_CHANGE_COOLDOWN_HOURS, default 168; 0 disables it).
+	ContactChangeCooldown time.Duration
+	// gRPC addresses a data export gathers from and an account deletion erases in (KYC_SERVICE_GRPC_ADDR,
+	// PAYMENT_SERVICE_GRPC_ADDR, AUDIT_SERVICE_GRPC_ADDR). Empty leaves that service out of exports and holds deletions.
+	KYCServiceGrpcAddr     string
+	PaymentServiceGrpcAddr string
+	AuditServiceGrpcAddr   string
+	// AccountDeletionGrace is how long a deletion request waits, cancellable, before it runs
+	// (ACCOUNT_DELETION_GRACE_HOURS, default 168).
+	AccountDeletionGrace time.Duration
+	// ReferralRules are the referral reward rules (REFERRAL_REWARD_RULES, a JSON array of {name, requires,
+	// referrer_amount, referee_amount}; "none" for no rewards). Nil means the service defaults.
+	ReferralRules []model.ReferralRule
+	// ReferralMaxRewardedPerReferrer caps how many of one user's referrals are rewarded
+	// (REFERRAL_MAX_REWARDED_PER_REFERRER, default 50; 0 for no cap).
+	ReferralMaxRewardedPerReferrer int
+	// TrustedProxies are the gateway addresses or CIDRs whose X-Real-IP header is taken as the client IP for per-IP
+	// attempt limits (TRUSTED_PROXIES, comma-separated). Empty means the private ranges of the compose network.
+	TrustedProxies []string
 }
 
 func LoadConfig() *Config {
@@ -56,6 +94,68 @@ func LoadConfig() *Config {
 			ttl = n
 		}
 	}
+	var stepUpActions []string
+	if s := strings.TrimSpace(os.Getenv("STEP_UP_ACTIONS")); s == "none" {
+		stepUpActions = []string{}
+	} else if s != "" {
+		stepUpActions = strings.Split(s, ",")
+	}
+	stepUpThreshold := 100000.0
+	if s := os.Getenv("STEP_UP_TRANSFER_THRESHOLD"); s != "" {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && f >= 0 {
+			stepUpThreshold = f
+		}
+	}
+	rpID := os.Getenv("WEBAUTHN_RP_ID")
+	if rpID == "" {
+		rpID = "localhost"
+	}
+	rpName := os.Getenv("WEBAUTHN_RP_NAME")
+	if rpName == "" {
+		rpName = "PayUp"
+	}
+	var origins []string
+	for _, o := range strings.Split(os.Getenv("WEBAUTHN_ORIGINS"), ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	if len(origins) == 0 {
+		origins = []string{"http://localhost:3000"}
+	}
+	limitDelay := 24 * time.Hour
+	if s := os.Getenv("LIMIT_INCREASE_DELAY_HOURS"); s != "" {
+		if h, err := strconv.ParseFloat(s, 64); err == nil && h >= 0 {
+			limitDelay = time.Duration(h * float64(time.Hour))
+		}
+	}
+	contactCooldown := 7 * 24 * time.Hour
+	if s := os.Getenv("CONTACT_CHANGE_COOLDOWN_HOURS"); s != "" {
+		if h, err := strconv.ParseFloat(s, 64); err == nil && h >= 0 {
+			contactCooldown = time.Duration(h * float64(time.Hour))
+		}
+	}
+	deletionGrace := 7 * 24 * time.Hour
+	if s := os.Getenv("ACCOUNT_DELETION_GRACE_HOURS"); s != "" {
+		if h, err := strconv.ParseFloat(s, 64); err == nil && h >= 0 {
+			deletionGrace = time.Duration(h * float64(time.Hour))
+		}
+	}
+	var referralRules []model.ReferralRule
+	if s := strings.TrimSpace(os.Getenv("REFERRAL_REWARD_RULES")); s == "none" {
+		referralRules = []model.ReferralRule{}
+	} else if s != "" {
+		var rules []model.ReferralRule
+		if err := json.Unmarshal([]byte(s), &rules); err == nil {
+			referralRules = rules
+		}
+	}
+	maxRewarded := 50
+	if s := os.Getenv("REFERRAL_MAX_REWARDED_PER_REFERRER"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			maxRewarded = n
+		}
+	}
 	return &Config{
 		Port:                     port,
 		GrpcPort:                 grpcPort,
@@ -65,6 +165,20 @@ func LoadConfig() *Config {
 		PasswordResetBaseURL:     os.Getenv("PASSWORD_RESET_BASE_URL"),
 		AdminAPIKey:              adminKey,
 		UserExistsCacheTTLSeconds: ttl,
+		StepUpActions:            stepUpActions,
+		StepUpTransferThreshold:  stepUpThreshold,
+		WebAuthnRPID:             rpID,
+		WebAuthnRPName:           rpName,
+		WebAuthnOrigins:          origins,
+		LimitIncreaseDelay:       limitDelay,
+		ContactChangeCooldown:    contactCooldown,
+		KYCServiceGrpcAddr:       os.Getenv("KYC_SERVICE_GRPC_ADDR"),
+		PaymentServiceGrpcAddr:   os.Getenv("PAYMENT_SERVICE_GRPC_ADDR"),
+		AuditServiceGrpcAddr:     os.Getenv("AUDIT_SERVICE_GRPC_ADDR"),
+		AccountDeletionGrace:     deletionGrace,
+		ReferralRules:            referralRules,
+		ReferralMaxRewardedPerReferrer: maxRewarded,
+		TrustedProxies:           gateway.ParseProxies(os.Getenv("TRUSTED_PROXIES")),
 	}
 }
 
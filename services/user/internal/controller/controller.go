@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/abubakvr/payup-backend/pkg/attempts"
+	"github.com/abubakvr/payup-backend/pkg/authn"
 	"github.com/abubakvr/payup-backend/services/user/internal/auth"
 	"github.com/abubakvr/payup-backend/services/user/internal/common/response"
 	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
 	"github.com/abubakvr/payup-backend/services/user/internal/repository"
 	"github.com/abubakvr/payup-backend/services/user/internal/service"
 	"github.com/abubakvr/payup-backend/services/user/internal/validation"
@@ -22,7 +26,8 @@ var publicAuthPaths = []string{
 	"/register", "/login",
 	"/password-reset", "/forgot-password", "/reset-password",
 	"/verify-email", "/resend-verification", "/auth/validate",
-	"/2fa/verify-login",
+	"/2fa/verify-login", "/2fa/send-otp", "/auth/refresh", "/auth/logout",
+	"/passkeys/login/begin", "/passkeys/login/finish",
 }
 
 // UserController holds the user service and exposes HTTP handlers.
@@ -35,6 +40,12 @@ func NewUserController(svc *service.UserService) *UserController {
 	return &UserController{svc: svc}
 }
 
+// JWKS handles GET /.well-known/jwks.json (no auth). Caches are kept short so a rotated-in key spreads quickly.
+func (c *UserController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, service.JWKS())
+}
+
 // AuthValidate is called by the API gateway (nginx auth_request) to validate the request. Returns 200 to allow, 401 to deny.
 // Flow: JWT verify → Redis cache for user exists → if not in cache, DB check. If user does not exist, return 401 to avoid
 // downstream "KYC not started" or similar; invalid/deleted user IDs get a clear 401.
@@ -53,7 +64,7 @@ func (c *UserController) AuthValidate(ctx *gin.Context) {
 		ctx.Status(http.StatusOK)
 		return
 	}
-	claims, err := auth.DecodeJWTFromContext(ctx)
+	claims, err := c.svc.Authenticator().Authenticate(ctx.Request.Context(), ctx.GetHeader("Authorization"))
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
@@ -66,6 +77,18 @@ func (c *UserController) AuthValidate(ctx *gin.Context) {
 	ctx.Status(http.StatusOK)
 }
 
+// deviceFromRequest reads the client's device headers (X-Device-ID, X-Device-Name, X-Device-Platform, X-App-Version).
+func deviceFromRequest(ctx *gin.Context) model.DeviceInfo {
+	return model.DeviceInfo{
+		DeviceID:   ctx.GetHeader("X-Device-ID"),
+		DeviceName: ctx.GetHeader("X-Device-Name"),
+		Platform:   ctx.GetHeader("X-Device-Platform"),
+		AppVersion: ctx.GetHeader("X-App-Version"),
+		IPAddress:  ctx.ClientIP(),
+		UserAgent:  ctx.GetHeader("User-Agent"),
+	}
+}
+
 func isPublicPath(uri string) bool {
 	uri = strings.TrimSuffix(uri, "/")
 	for _, p := range publicAuthPaths {
@@ -83,8 +106,12 @@ func (c *UserController) RegisterUser(ctx *gin.Context) {
 		return
 	}
 
-	_, err := c.svc.CreateUser(ctx.Request.Context(), req.Email, req.Password, req.FirstName, req.LastName, req.PhoneNumber)
+	_, err := c.svc.CreateUser(ctx.Request.Context(), req.Email, req.Password, req.FirstName, req.LastName, req.PhoneNumber, req.ReferralCode)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidReferralCode) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError)})
+			return
+		}
 		// ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
 		return
@@ -105,15 +132,25 @@ func (c *UserController) Login(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.svc.Login(ctx.Request.Context(), req.Email, req.Password)
+	login := loginName(req)
+	result, err := c.svc.Login(ctx.Request.Context(), req.Email, req.PhoneNumber, req.Password, deviceFromRequest(ctx))
 	if err != nil {
+		if le, locked := attempts.IsLocked(err); locked {
+			log.Printf("user login failed login=%s ip=%s device=%s reason=locked policy=%s", login, clientIP, userAgent, le.Policy)
+			respondLocked(ctx, le)
+			return
+		}
 		if errors.Is(err, repository.ErrInvalidCredentials) {
-			log.Printf("user login failed email=%s ip=%s device=%s reason=invalid_credentials", req.Email, clientIP, userAgent)
-			response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), "invalid email or password")
+			log.Printf("user login failed login=%s ip=%s device=%s reason=invalid_credentials", login, clientIP, userAgent)
+			msg := "invalid email or password"
+			if req.Email == "" {
+				msg = "invalid phone number or password"
+			}
+			response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), msg)
 			return
 		}
 		if errors.Is(err, service.ErrEmailNotVerified) {
-			log.Printf("user login failed email=%s ip=%s device=%s reason=email_not_verified", req.Email, clientIP, userAgent)
+			log.Printf("user login failed login=%s ip=%s device=%s reason=email_not_verified", login, clientIP, userAgent)
 			ctx.JSON(http.StatusForbidden, gin.H{
 				"status":       "error",
 				"message":      "Please verify your email before logging in",
@@ -122,7 +159,7 @@ func (c *UserController) Login(ctx *gin.Context) {
 			})
 			return
 		}
-		log.Printf("user login failed email=%s ip=%s device=%s err=%v", req.Email, clientIP, userAgent, err)
+		log.Printf("user login failed login=%s ip=%s device=%s err=%v", login, clientIP, userAgent, err)
 		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
 		return
 	}
@@ -131,14 +168,150 @@ func (c *UserController) Login(ctx *gin.Context) {
 		return
 	}
 	if result.Requires2FA != nil {
-		log.Printf("user login 2fa_required email=%s ip=%s device=%s", req.Email, clientIP, userAgent)
+		log.Printf("user login 2fa_required login=%s ip=%s device=%s", login, clientIP, userAgent)
 		ctx.JSON(http.StatusOK, result.Requires2FA)
 		return
 	}
-	log.Printf("user login success email=%s ip=%s device=%s", req.Email, clientIP, userAgent)
+	log.Printf("user login success login=%s ip=%s device=%s", login, clientIP, userAgent)
 	ctx.JSON(http.StatusOK, result.Success)
 }
 
+// loginName is how login logs name the account: the email, or the last four digits of the phone number.
+func loginName(req dto.LoginRequest) string {
+	if req.Email != "" || len(req.PhoneNumber) < 4 {
+		return req.Email
+	}
+	return "phone:***" + req.PhoneNumber[len(req.PhoneNumber)-4:]
+}
+
+// sensitiveAuth collects what the caller sent to confirm a sensitive change: the body password and the X-Step-Up-Token
+// header, with the session of the access token the step-up token must have been issued in.
+func sensitiveAuth(ctx *gin.Context, claims *authn.Claims, password string) service.SensitiveAuth {
+	return service.SensitiveAuth{
+		Password:    password,
+		StepUpToken: ctx.GetHeader("X-Step-Up-Token"),
+		SessionID:   claims.SessionID,
+	}
+}
+
+// respondStepUpError answers 403 when a sensitive change needs (or was sent a bad) step-up token, and 400 when no
+// password was sent. Reports whether it wrote a response.
+func respondStepUpError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrStepUpRequired), errors.Is(err, service.ErrStepUpInvalid), errors.Is(err, service.ErrStepUpOneAction):
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.StepUpRequired),
+		})
+		return true
+	case err.Error() == "password required":
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status": "error", "message": "Password is required", "responseCode": string(response.ValidationError),
+		})
+		return true
+	}
+	return false
+}
+
+// respondLocked answers 429 while too many failed attempts keep the account (or IP) locked out.
+func respondLocked(ctx *gin.Context, le *attempts.LockedError) {
+	ctx.Header("Retry-After", strconv.Itoa(int(le.RetryAfter.Seconds())))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{
+		"status":       "error",
+		"message":      le.Error(),
+		"responseCode": string(response.AuthenticationFailed),
+		"data":         nil,
+	})
+}
+
+// RefreshToken handles POST /auth/refresh (no JWT; the access token may already be expired). Returns a new access token
+// and a new refresh token; the presented refresh token stops working.
+func (c *UserController) RefreshToken(ctx *gin.Context) {
+	clientIP := ctx.ClientIP()
+	var req dto.RefreshTokenRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.RefreshToken(ctx.Request.Context(), req.RefreshToken, deviceFromRequest(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			log.Printf("user refresh failed ip=%s reason=token_reuse", clientIP)
+			response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), err.Error())
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Logout handles POST /auth/logout (JWT optional). Revokes the refresh token and all tokens rotated from the same login;
+// a still-valid access token sent in Authorization is revoked as well.
+func (c *UserController) Logout(ctx *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	access, _ := c.svc.Authenticator().Authenticate(ctx.Request.Context(), ctx.GetHeader("Authorization"))
+	if err := c.svc.Logout(ctx.Request.Context(), req.RefreshToken, access); err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Logged out.", nil)
+}
+
+// ListSessions handles GET /sessions (authenticated). Lists devices the user is logged in on; the caller's is marked current.
+func (c *UserController) ListSessions(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	sessions, err := c.svc.ListSessions(ctx.Request.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Sessions retrieved", sessions)
+}
+
+// RevokeSession handles DELETE /sessions/:id (authenticated). Logs that device out; its refresh token stops working.
+func (c *UserController) RevokeSession(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.RevokeSession(ctx.Request.Context(), claims.UserID, ctx.Param("id"), repository.RevokeReasonSessionRevoked); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.ResourceNotFound),
+			})
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Session logged out.", nil)
+}
+
+// LogOutEverywhere handles POST /sessions/logout-all (authenticated). Revokes every session, including this one.
+func (c *UserController) LogOutEverywhere(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.LogOutEverywhere(ctx.Request.Context(), claims.UserID); err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Logged out of all devices.", nil)
+}
+
 // VerifyEmail handles POST /verify-email with token from the verification link.
 func (c *UserController) VerifyEmail(ctx *gin.Context) {
 	var req struct {
@@ -245,6 +418,296 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 	response.SuccessResponse(ctx, string(response.Success), "Password has been changed.", nil)
 }
 
+// RequestEmailChange handles POST /account/email (authenticated). Body: newEmail and password (or X-Step-Up-Token).
+// Sends a code to the new address; the email changes on POST /account/email/confirm.
+func (c *UserController) RequestEmailChange(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.ChangeEmailRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.RequestEmailChange(ctx.Request.Context(), claims.UserID, req.NewEmail, sensitiveAuth(ctx, claims, req.Password))
+	if err != nil {
+		respondContactChangeError(ctx, err)
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Code sent to your new email.", resp)
+}
+
+// RequestPhoneChange handles POST /account/phone (authenticated). Body: newPhoneNumber, channel (sms or whatsapp) and
+// password (or X-Step-Up-Token). Sends a code to the new number; it changes on POST /account/phone/confirm.
+func (c *UserController) RequestPhoneChange(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.ChangePhoneRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.RequestPhoneChange(ctx.Request.Context(), claims.UserID, req.NewPhoneNumber, req.Channel, sensitiveAuth(ctx, claims, req.Password))
+	if err != nil {
+		respondContactChangeError(ctx, err)
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Code sent to your new phone number.", resp)
+}
+
+// ConfirmEmailChange handles POST /account/email/confirm (authenticated). Body: code. The user is signed out everywhere
+// and signs in again with the new email.
+func (c *UserController) ConfirmEmailChange(ctx *gin.Context) {
+	c.confirmContactChange(ctx, model.ContactEmail, "Email changed. Sign in again with your new email.")
+}
+
+// ConfirmPhoneChange handles POST /account/phone/confirm (authenticated). Body: code.
+func (c *UserController) ConfirmPhoneChange(ctx *gin.Context) {
+	c.confirmContactChange(ctx, model.ContactPhone, "Phone number changed.")
+}
+
+func (c *UserController) confirmContactChange(ctx *gin.Context, kind, message string) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.ConfirmContactChangeRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	if err := c.svc.ConfirmContactChange(ctx.Request.Context(), claims.UserID, kind, req.Code); err != nil {
+		respondContactChangeError(ctx, err)
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), message, nil)
+}
+
+// respondContactChangeError maps email and phone change errors to responses.
+func respondContactChangeError(ctx *gin.Context, err error) {
+	if le, locked := attempts.IsLocked(err); locked {
+		respondLocked(ctx, le)
+		return
+	}
+	if respondStepUpError(ctx, err) {
+		return
+	}
+	var cooldown *service.ContactCooldownError
+	switch {
+	case errors.As(err, &cooldown):
+		ctx.JSON(http.StatusTooManyRequests, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
+			"data": gin.H{"retryAfter": cooldown.Until},
+		})
+	case err.Error() == "invalid password", errors.Is(err, service.ErrInvalidChangeCode):
+		response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), err.Error())
+	case errors.Is(err, service.ErrEmailInUse), errors.Is(err, service.ErrPhoneInUse):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
+		})
+	case errors.Is(err, service.ErrContactUnchanged), errors.Is(err, service.ErrNoContactChange):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
+		})
+	case errors.Is(err, service.ErrFallbackUnavailable):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.InternalServerError),
+		})
+	case err.Error() == "user not found":
+		ctx.AbortWithStatus(http.StatusNotFound)
+	default:
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+	}
+}
+
+// RequestDataExport handles POST /privacy/export (authenticated). Body: password (or X-Step-Up-Token). Queues a copy of
+// everything held about the user; it is listed on GET /privacy/requests once ready.
+func (c *UserController) RequestDataExport(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.DataSubjectAuthRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.RequestDataExport(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password))
+	if err != nil {
+		respondDataSubjectError(ctx, err)
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Your data export is being prepared. We'll email you when it is ready.", resp)
+}
+
+// ListDataSubjectRequests handles GET /privacy/requests (authenticated): the user's exports and deletion requests.
+func (c *UserController) ListDataSubjectRequests(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	list, err := c.svc.ListDataSubjectRequests(claims.UserID)
+	if err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Privacy requests retrieved.", list)
+}
+
+// DownloadDataExport handles GET /privacy/exports/:id/download (authenticated). Returns the export zip.
+func (c *UserController) DownloadDataExport(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	archive, err := c.svc.DownloadDataExport(ctx.Request.Context(), claims.UserID, ctx.Param("id"))
+	if err != nil {
+		respondDataSubjectError(ctx, err)
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="payup-data-export.zip"`)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "application/zip", archive)
+}
+
+// RequestAccountDeletion handles POST /privacy/deletion (authenticated). Body: password (or X-Step-Up-Token). The
+// account is deleted after ACCOUNT_DELETION_GRACE_HOURS unless cancelled with DELETE /privacy/deletion.
+func (c *UserController) RequestAccountDeletion(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.DataSubjectAuthRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.RequestAccountDeletion(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password))
+	if err != nil {
+		respondDataSubjectError(ctx, err)
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Your account is scheduled for deletion.", resp)
+}
+
+// CancelAccountDeletion handles DELETE /privacy/deletion (authenticated) during the grace period.
+func (c *UserController) CancelAccountDeletion(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	resp, err := c.svc.CancelAccountDeletion(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		respondDataSubjectError(ctx, err)
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Account deletion cancelled.", resp)
+}
+
+func respondDataSubjectError(ctx *gin.Context, err error) {
+	if respondStepUpError(ctx, err) {
+		return
+	}
+	switch {
+	case err.Error() == "invalid password":
+		response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), err.Error())
+	case errors.Is(err, service.ErrDataExportPending), errors.Is(err, service.ErrDeletionPending):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
+		})
+	case errors.Is(err, service.ErrNoDeletionRequest), errors.Is(err, service.ErrDataExportNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ResourceNotFound),
+		})
+	case err.Error() == "user not found":
+		ctx.AbortWithStatus(http.StatusNotFound)
+	default:
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+	}
+}
+
+// GetReferrals handles GET /referrals (authenticated): the user's referral code, reward rules and referred users.
+func (c *UserController) GetReferrals(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	summary, err := c.svc.GetReferralSummary(claims.UserID)
+	if err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Referrals retrieved.", summary)
+}
+
+// RegisterPushToken handles POST /push-tokens (authenticated). Body: token, platform (ios or android), deviceId
+// (defaults to the X-Device-ID header). Call it on every app start; the token can change.
+func (c *UserController) RegisterPushToken(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.RegisterPushTokenRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	if req.DeviceID == "" {
+		req.DeviceID = ctx.GetHeader("X-Device-ID")
+	}
+	if err := c.svc.RegisterPushToken(claims.UserID, req); err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Push token registered.", nil)
+}
+
+// ListPushTokens handles GET /push-tokens (authenticated): devices registered for push.
+func (c *UserController) ListPushTokens(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	tokens, err := c.svc.ListPushTokens(claims.UserID)
+	if err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Push tokens retrieved.", tokens)
+}
+
+// DeletePushToken handles DELETE /push-tokens (authenticated). Body: token. The app calls it on sign-out.
+func (c *UserController) DeletePushToken(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.DeletePushTokenRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	if err := c.svc.DeletePushToken(claims.UserID, req.Token); err != nil {
+		if errors.Is(err, service.ErrPushTokenNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.ResourceNotFound),
+			})
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Push token removed.", nil)
+}
+
 // GetSettings returns the authenticated user's settings (GET /settings). Requires JWT.
 func (c *UserController) GetSettings(ctx *gin.Context) {
 	claims, err := auth.DecodeJWTFromContext(ctx)
@@ -264,7 +727,8 @@ func (c *UserController) GetSettings(ctx *gin.Context) {
 	response.SuccessResponse(ctx, string(response.Success), "Settings retrieved.", settings)
 }
 
-// UpdateSettings applies a partial update (PATCH /settings). Theme and language only do not require password; other fields require password.
+// UpdateSettings applies a partial update (PATCH /settings). Theme and language only do not require password; other fields require password
+// or, for raising limits, resuming transfers and turning 2FA off, an X-Step-Up-Token when the step-up policy asks for one.
 func (c *UserController) UpdateSettings(ctx *gin.Context) {
 	claims, err := auth.DecodeJWTFromContext(ctx)
 	if err != nil {
@@ -275,8 +739,11 @@ func (c *UserController) UpdateSettings(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	settings, err := c.svc.UpdateSettings(ctx.Request.Context(), claims.UserID, &req)
+	settings, err := c.svc.UpdateSettings(ctx.Request.Context(), claims.UserID, &req, sensitiveAuth(ctx, claims, ""))
 	if err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
 		if err.Error() == "user settings not found" || err.Error() == "user not found" {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
@@ -306,8 +773,11 @@ func (c *UserController) SetPin(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	settings, err := c.svc.SetPin(ctx.Request.Context(), claims.UserID, req.Password, req.CurrentPin, req.Pin)
+	settings, err := c.svc.SetPin(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password), req.CurrentPin, req.Pin)
 	if err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
 		if err.Error() == "user not found" || err.Error() == "user settings not found" {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
@@ -330,7 +800,8 @@ func (c *UserController) SetPin(ctx *gin.Context) {
 	response.SuccessResponse(ctx, string(response.Success), "PIN set.", settings)
 }
 
-// SetLimits handles PUT /settings/limits (authenticated). Body: { "password", "dailyTransferLimit", "monthlyTransferLimit" }. Requires password.
+// SetLimits handles PUT /settings/limits (authenticated). Body: { "password", "dailyTransferLimit", "monthlyTransferLimit" }. Requires password;
+// raising a limit may need an X-Step-Up-Token instead. Increases come back under pendingLimitChange until they take effect.
 func (c *UserController) SetLimits(ctx *gin.Context) {
 	claims, err := auth.DecodeJWTFromContext(ctx)
 	if err != nil {
@@ -345,8 +816,11 @@ func (c *UserController) SetLimits(ctx *gin.Context) {
 		response.ErrorResponse(ctx, string(response.ValidationError), "provide at least one of dailyTransferLimit or monthlyTransferLimit")
 		return
 	}
-	settings, err := c.svc.SetLimits(ctx.Request.Context(), claims.UserID, &req)
+	settings, err := c.svc.SetLimits(ctx.Request.Context(), claims.UserID, &req, sensitiveAuth(ctx, claims, ""))
 	if err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
 		if err.Error() == "user not found" || err.Error() == "user settings not found" {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
@@ -361,6 +835,28 @@ func (c *UserController) SetLimits(ctx *gin.Context) {
 	response.SuccessResponse(ctx, string(response.Success), "Limits updated.", settings)
 }
 
+// CancelLimitIncrease handles DELETE /settings/limits/pending (authenticated). Drops the increase still in its
+// cooling-off period; current limits are unchanged.
+func (c *UserController) CancelLimitIncrease(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	settings, err := c.svc.CancelLimitIncrease(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, service.ErrNoPendingLimitChange) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.ResourceNotFound),
+			})
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Limit increase cancelled.", settings)
+}
+
 // PauseAccount handles POST /settings/pause-account (authenticated). Body: { "password" }. Disables transfers.
 func (c *UserController) PauseAccount(ctx *gin.Context) {
 	claims, err := auth.DecodeJWTFromContext(ctx)
@@ -372,8 +868,11 @@ func (c *UserController) PauseAccount(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	settings, err := c.svc.PauseAccount(ctx.Request.Context(), claims.UserID, req.Password)
+	settings, err := c.svc.PauseAccount(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password))
 	if err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
 		if err.Error() == "user not found" || err.Error() == "user settings not found" {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
@@ -399,8 +898,11 @@ func (c *UserController) ResumeAccount(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	settings, err := c.svc.ResumeAccount(ctx.Request.Context(), claims.UserID, req.Password)
+	settings, err := c.svc.ResumeAccount(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password))
 	if err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
 		if err.Error() == "user not found" || err.Error() == "user settings not found" {
 			ctx.AbortWithStatus(http.StatusNotFound)
 			return
@@ -440,7 +942,7 @@ func (c *UserController) Setup2FA(ctx *gin.Context) {
 	})
 }
 
-// VerifySetup2FA handles POST /2fa/verify-setup (authenticated). Verifies TOTP code and enables 2FA.
+// VerifySetup2FA handles POST /2fa/verify-setup (authenticated). Verifies TOTP code, enables 2FA and returns recovery codes.
 func (c *UserController) VerifySetup2FA(ctx *gin.Context) {
 	claims, err := auth.DecodeJWTFromContext(ctx)
 	if err != nil {
@@ -451,7 +953,8 @@ func (c *UserController) VerifySetup2FA(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	if err := c.svc.VerifySetup2FA(ctx.Request.Context(), claims.UserID, req.Code); err != nil {
+	codes, err := c.svc.VerifySetup2FA(ctx.Request.Context(), claims.UserID, req.Code)
+	if err != nil {
 		if errors.Is(err, service.ErrInvalidTOTPCode) {
 			ctx.JSON(http.StatusBadRequest, gin.H{
 				"status": "error", "message": "Invalid or expired code", "responseCode": string(response.ValidationError),
@@ -461,10 +964,12 @@ func (c *UserController) VerifySetup2FA(ctx *gin.Context) {
 		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
 		return
 	}
-	response.SuccessResponse(ctx, string(response.Success), "Two-factor authentication is now enabled.", nil)
+	response.SuccessResponse(ctx, string(response.Success), "Two-factor authentication is now enabled. Store these recovery codes somewhere safe; each works once.",
+		dto.VerifySetup2FAResponse{RecoveryCodes: codes})
 }
 
-// VerifyLogin2FA handles POST /2fa/verify-login (no JWT; uses twoFactorToken from login response). Returns access and refresh tokens.
+// VerifyLogin2FA handles POST /2fa/verify-login (no JWT; uses twoFactorToken from login response). The code is a TOTP
+// code, a recovery code or a code from POST /2fa/send-otp, per method. Returns access and refresh tokens.
 func (c *UserController) VerifyLogin2FA(ctx *gin.Context) {
 	clientIP := ctx.ClientIP()
 	userAgent := strings.TrimSpace(ctx.GetHeader("User-Agent"))
@@ -475,8 +980,13 @@ func (c *UserController) VerifyLogin2FA(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	resp, err := c.svc.VerifyLogin2FA(ctx.Request.Context(), req.TwoFactorToken, req.Code)
+	resp, err := c.svc.VerifyLogin2FA(ctx.Request.Context(), req.TwoFactorToken, req.Method, req.Code, deviceFromRequest(ctx))
 	if err != nil {
+		if le, locked := attempts.IsLocked(err); locked {
+			log.Printf("user login 2fa_verify failed ip=%s device=%s reason=locked", clientIP, userAgent)
+			respondLocked(ctx, le)
+			return
+		}
 		if errors.Is(err, service.ErrInvalidTOTPCode) {
 			log.Printf("user login 2fa_verify failed ip=%s device=%s reason=invalid_code", clientIP, userAgent)
 			ctx.JSON(http.StatusBadRequest, gin.H{
@@ -484,6 +994,12 @@ func (c *UserController) VerifyLogin2FA(ctx *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, service.ErrFallbackUnavailable) {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.InternalServerError),
+			})
+			return
+		}
 		log.Printf("user login 2fa_verify failed ip=%s device=%s err=%v", clientIP, userAgent, err)
 		ctx.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": err.Error()})
 		return
@@ -503,7 +1019,10 @@ func (c *UserController) Disable2FA(ctx *gin.Context) {
 	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
 		return
 	}
-	if err := c.svc.Disable2FA(ctx.Request.Context(), claims.UserID, req.Password); err != nil {
+	if err := c.svc.Disable2FA(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password)); err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
 		if errors.Is(err, service.Err2FANotEnabled) {
 			ctx.JSON(http.StatusBadRequest, gin.H{
 				"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
@@ -520,6 +1039,173 @@ func (c *UserController) Disable2FA(ctx *gin.Context) {
 	response.SuccessResponse(ctx, string(response.Success), "Two-factor authentication has been disabled.", nil)
 }
 
+// Send2FAOTP handles POST /2fa/send-otp (no JWT; uses twoFactorToken from login response). Sends a one-time login code
+// by SMS, WhatsApp or email for users without their authenticator app; verify it with method "otp".
+func (c *UserController) Send2FAOTP(ctx *gin.Context) {
+	var req dto.Send2FAOTPRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.SendLogin2FAOTP(ctx.Request.Context(), req.TwoFactorToken, req.Channel)
+	if err != nil {
+		if le, locked := attempts.IsLocked(err); locked {
+			respondLocked(ctx, le)
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoFallbackDestination):
+			response.ErrorResponse(ctx, string(response.ValidationError), err.Error())
+		case errors.Is(err, service.ErrFallbackUnavailable):
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.InternalServerError),
+			})
+		default:
+			ctx.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": err.Error()})
+		}
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Login code sent.", resp)
+}
+
+// Get2FAStatus handles GET /2fa/status (authenticated). Returns whether 2FA is on, the fallback channel and the number
+// of unused recovery codes.
+func (c *UserController) Get2FAStatus(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	status, err := c.svc.TwoFactorStatus(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Two-factor status retrieved.", status)
+}
+
+// RegenerateRecoveryCodes handles POST /2fa/recovery-codes (authenticated). Requires password; previous codes stop working.
+func (c *UserController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.PasswordConfirmRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	codes, err := c.svc.RegenerateRecoveryCodes(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password))
+	if err != nil {
+		if respondStepUpError(ctx, err) {
+			return
+		}
+		if errors.Is(err, service.Err2FANotEnabled) {
+			response.ErrorResponse(ctx, string(response.ValidationError), err.Error())
+			return
+		}
+		if err.Error() == "invalid password" {
+			response.ErrorResponse(ctx, string(response.AuthenticationFailed), "Invalid password")
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "New recovery codes generated; the old ones no longer work.",
+		dto.VerifySetup2FAResponse{RecoveryCodes: codes})
+}
+
+// StepUp handles POST /auth/step-up (authenticated). Body: action, password, code and optionally method (totp or otp).
+// Returns a short-lived token to send as X-Step-Up-Token with the sensitive request, or with a large transfer.
+func (c *UserController) StepUp(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.StepUpRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.StepUp(ctx.Request.Context(), claims.UserID, claims.SessionID, &req)
+	if err != nil {
+		if le, locked := attempts.IsLocked(err); locked {
+			respondLocked(ctx, le)
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrInvalidTOTPCode):
+			response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), "Invalid password or code")
+		case errors.Is(err, service.ErrFallbackUnavailable):
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.InternalServerError),
+			})
+		case err.Error() == "user not found":
+			ctx.AbortWithStatus(http.StatusNotFound)
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
+			})
+		}
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Step-up confirmed.", resp)
+}
+
+// SendStepUpOTP handles POST /auth/step-up/send-otp (authenticated). Sends a one-time code for POST /auth/step-up
+// (method otp) by SMS, WhatsApp or email.
+func (c *UserController) SendStepUpOTP(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.SendStepUpOTPRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.SendStepUpOTP(ctx.Request.Context(), claims.UserID, req.Channel)
+	if err != nil {
+		if le, locked := attempts.IsLocked(err); locked {
+			respondLocked(ctx, le)
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNoFallbackDestination):
+			response.ErrorResponse(ctx, string(response.ValidationError), err.Error())
+		case errors.Is(err, service.ErrFallbackUnavailable):
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{
+				"status": "error", "message": err.Error(), "responseCode": string(response.InternalServerError),
+			})
+		default:
+			response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		}
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Confirmation code sent.", resp)
+}
+
+// Set2FAFallback handles PUT /2fa/fallback (authenticated). Body: channel (sms, whatsapp, email or none).
+func (c *UserController) Set2FAFallback(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.SetTwoFactorFallbackRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	if err := c.svc.SetTwoFactorFallback(ctx.Request.Context(), claims.UserID, req.Channel); err != nil {
+		if errors.Is(err, service.ErrNoFallbackDestination) {
+			response.ErrorResponse(ctx, string(response.ValidationError), err.Error())
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Fallback channel updated.", nil)
+}
+
 // AdminListUsers handles GET /admin/users (requires X-Admin-Key). Query: limit, offset.
 func (c *UserController) AdminListUsers(ctx *gin.Context) {
 	limit := 50
@@ -591,3 +1277,179 @@ func parseInt(s string) (int, error) {
 	_, err := fmt.Sscanf(s, "%d", &n)
 	return n, err
 }
+
+// respondPasskeyError maps passkey ceremony errors. Reports whether it wrote a response.
+func respondPasskeyError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrPasskeyInvalid), errors.Is(err, service.ErrPasskeyChallenge):
+		response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), err.Error())
+	case errors.Is(err, service.ErrPasskeyPaymentsOff):
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.AuthorizationFailed),
+		})
+	case errors.Is(err, service.ErrPasskeyNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ResourceNotFound),
+		})
+	case errors.Is(err, repository.ErrPasskeyExists):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.ValidationError),
+		})
+	case errors.Is(err, service.ErrPasskeyUnavailable):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "error", "message": err.Error(), "responseCode": string(response.InternalServerError),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// BeginPasskeyRegistration handles POST /passkeys/register/begin (authenticated). Body: { "password" } or an
+// X-Step-Up-Token for register_passkey. Returns options for navigator.credentials.create.
+func (c *UserController) BeginPasskeyRegistration(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.PasskeyRegisterBeginRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.BeginPasskeyRegistration(ctx.Request.Context(), claims.UserID, sensitiveAuth(ctx, claims, req.Password))
+	if err != nil {
+		if respondStepUpError(ctx, err) || respondPasskeyError(ctx, err) {
+			return
+		}
+		if err.Error() == "invalid password" {
+			response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), "Invalid password")
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Passkey registration started.", resp)
+}
+
+// FinishPasskeyRegistration handles POST /passkeys/register/finish (authenticated). Body: challengeId, name and the
+// credential from navigator.credentials.create.
+func (c *UserController) FinishPasskeyRegistration(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.PasskeyRegisterFinishRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	passkey, err := c.svc.FinishPasskeyRegistration(ctx.Request.Context(), claims.UserID, &req)
+	if err != nil {
+		if respondPasskeyError(ctx, err) {
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Passkey added.", passkey)
+}
+
+// ListPasskeys handles GET /passkeys (authenticated).
+func (c *UserController) ListPasskeys(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	passkeys, err := c.svc.ListPasskeys(ctx.Request.Context(), claims.UserID)
+	if err != nil {
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Passkeys retrieved.", passkeys)
+}
+
+// DeletePasskey handles DELETE /passkeys/:id (authenticated). Removing the last passkey turns off passkey payments.
+func (c *UserController) DeletePasskey(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if err := c.svc.DeletePasskey(ctx.Request.Context(), claims.UserID, ctx.Param("id")); err != nil {
+		if respondPasskeyError(ctx, err) {
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Passkey removed.", nil)
+}
+
+// BeginPasskeyLogin handles POST /passkeys/login/begin (public). Returns options for navigator.credentials.get.
+func (c *UserController) BeginPasskeyLogin(ctx *gin.Context) {
+	resp, err := c.svc.BeginPasskeyLogin(ctx.Request.Context())
+	if err != nil {
+		if respondPasskeyError(ctx, err) {
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Passkey login started.", resp)
+}
+
+// FinishPasskeyLogin handles POST /passkeys/login/finish (public). Body: challengeId and the credential from
+// navigator.credentials.get. Returns the same tokens as POST /login.
+func (c *UserController) FinishPasskeyLogin(ctx *gin.Context) {
+	var req dto.PasskeyAssertionRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.FinishPasskeyLogin(ctx.Request.Context(), &req, deviceFromRequest(ctx))
+	if err != nil {
+		if le, locked := attempts.IsLocked(err); locked {
+			respondLocked(ctx, le)
+			return
+		}
+		if respondPasskeyError(ctx, err) {
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"status": "error", "message": "Please verify your email before logging in", "responseCode": string(response.AuthenticationFailed),
+			})
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	log.Printf("user login success (passkey) ip=%s", ctx.ClientIP())
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// BeginPasskeyTransaction handles POST /passkeys/transaction/begin (authenticated). Body: { "amount",
+// "beneficiaryBankCode", "beneficiaryAccountNumber" }. The signed result goes to payment as "passkey" instead of "pin".
+func (c *UserController) BeginPasskeyTransaction(ctx *gin.Context) {
+	claims, err := auth.DecodeJWTFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var req dto.PasskeyTransactionBeginRequest
+	if !validation.BindAndValidate(ctx, string(response.ValidationError), &req) {
+		return
+	}
+	resp, err := c.svc.BeginPasskeyTransaction(ctx.Request.Context(), claims.UserID, req.Amount, service.Payee{
+		BankCode: req.BeneficiaryBankCode, AccountNumber: req.BeneficiaryAccountNumber,
+	})
+	if err != nil {
+		if respondPasskeyError(ctx, err) {
+			return
+		}
+		response.ErrorResponse(ctx, string(response.InternalServerError), err.Error())
+		return
+	}
+	response.SuccessResponse(ctx, string(response.Success), "Approve the payment with your passkey.", resp)
+}
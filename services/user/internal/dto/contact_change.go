@@ -0,0 +1,29 @@
+package dto
+
+// ChangeEmailRequest is the body for POST /account/email. A code is sent to newEmail; the email changes once it is
+// confirmed. Password is required unless an X-Step-Up-Token is sent.
+type ChangeEmailRequest struct {
+	NewEmail string `json:"newEmail" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"omitempty,max=72"`
+}
+
+// ChangePhoneRequest is the body for POST /account/phone. A code is sent to newPhoneNumber by SMS (default) or
+// WhatsApp. Password is required unless an X-Step-Up-Token is sent.
+type ChangePhoneRequest struct {
+	NewPhoneNumber string `json:"newPhoneNumber" binding:"required,min=10,max=20"`
+	Channel        string `json:"channel" binding:"omitempty,oneof=sms whatsapp"`
+	Password       string `json:"password" binding:"omitempty,max=72"`
+}
+
+// ConfirmContactChangeRequest is the body for POST /account/email/confirm and POST /account/phone/confirm.
+type ConfirmContactChangeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// ContactChangeResponse is returned when a change code was sent: where it went (masked) and for how long it is valid.
+type ContactChangeResponse struct {
+	Kind        string `json:"kind"`
+	Channel     string `json:"channel"`
+	Destination string `json:"destination"`
+	ExpiresIn   int    `json:"expiresIn"` // seconds
+}
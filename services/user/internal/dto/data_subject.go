@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DataSubjectAuthRequest is the body for POST /privacy/export and POST /privacy/deletion. Password is required unless
+// an X-Step-Up-Token is sent.
+type DataSubjectAuthRequest struct {
+	Password string `json:"password" binding:"omitempty,max=72"`
+}
+
+// DataSubjectRequestResponse describes a data export or account deletion request. DownloadExpiresAt is set while a
+// finished export can be downloaded (GET /privacy/exports/:id/download); Result carries what each service exported,
+// erased or retained, and the hold reasons of a held deletion.
+type DataSubjectRequestResponse struct {
+	ID                string          `json:"id"`
+	Kind              string          `json:"kind"`
+	Status            string          `json:"status"`
+	RequestedAt       time.Time       `json:"requestedAt"`
+	ScheduledFor      *time.Time      `json:"scheduledFor,omitempty"` // pending only: when it will be processed
+	CompletedAt       *time.Time      `json:"completedAt,omitempty"`
+	DownloadExpiresAt *time.Time      `json:"downloadExpiresAt,omitempty"`
+	Result            json.RawMessage `json:"result,omitempty"`
+}
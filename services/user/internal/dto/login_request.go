@@ -1,6 +1,9 @@
 package dto
 
+// LoginRequest is the body for POST /login. The account is named by email or by phoneNumber (the number it was
+// registered with); send one of them.
 type LoginRequest struct {
-	Email    string `json:"email"    binding:"required,email,max=255"`
-	Password string `json:"password" binding:"required,max=72"`
+	Email       string `json:"email"       binding:"required_without=PhoneNumber,excluded_with=PhoneNumber,omitempty,email,max=255"`
+	PhoneNumber string `json:"phoneNumber" binding:"required_without=Email,omitempty,min=10,max=20"`
+	Password    string `json:"password"    binding:"required,max=72"`
 }
@@ -0,0 +1,48 @@
+package dto
+
+import "github.com/abubakvr/payup-backend/services/user/internal/webauthn"
+
+// PasskeyRegisterBeginRequest is the body for POST /passkeys/register/begin. Password may be omitted with an
+// X-Step-Up-Token for action register_passkey.
+type PasskeyRegisterBeginRequest struct {
+	Password string `json:"password"`
+}
+
+// PasskeyCeremonyResponse starts a ceremony: pass publicKey to navigator.credentials.create/get and send the result
+// back with challengeId.
+type PasskeyCeremonyResponse struct {
+	ChallengeID string      `json:"challengeId"`
+	PublicKey   interface{} `json:"publicKey"`
+}
+
+// PasskeyRegisterFinishRequest is the body for POST /passkeys/register/finish.
+type PasskeyRegisterFinishRequest struct {
+	ChallengeID string                              `json:"challengeId" binding:"required"`
+	Name        string                              `json:"name" binding:"max=100"`
+	Credential  webauthn.CredentialCreationResponse `json:"credential" binding:"required"`
+}
+
+// PasskeyAssertionRequest carries a navigator.credentials.get result: the body for POST /passkeys/login/finish, and
+// the "passkey" object payment forwards in place of the PIN.
+type PasskeyAssertionRequest struct {
+	ChallengeID string                               `json:"challengeId" binding:"required"`
+	Credential  webauthn.CredentialAssertionResponse `json:"credential" binding:"required"`
+}
+
+// PasskeyTransactionBeginRequest is the body for POST /passkeys/transaction/begin; the challenge is bound to the amount
+// and the payee: bank code and account number for a transfer, biller code and customer ID for a bill payment.
+type PasskeyTransactionBeginRequest struct {
+	Amount                   float64 `json:"amount" binding:"required,gt=0"`
+	BeneficiaryBankCode      string  `json:"beneficiaryBankCode" binding:"required"`
+	BeneficiaryAccountNumber string  `json:"beneficiaryAccountNumber" binding:"required"`
+}
+
+// PasskeyResponse describes a registered passkey.
+type PasskeyResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Transports []string `json:"transports,omitempty"`
+	BackedUp   bool     `json:"backedUp"`
+	CreatedAt  string   `json:"createdAt"`
+	LastUsedAt *string  `json:"lastUsedAt,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+// RegisterPushTokenRequest is the body for POST /push-tokens. Token is the FCM registration token (android) or the
+// APNs device token (ios). DeviceID is the same X-Device-ID the app sends on login.
+type RegisterPushTokenRequest struct {
+	Token    string `json:"token" binding:"required,max=4096"`
+	Platform string `json:"platform" binding:"required,oneof=ios android"`
+	DeviceID string `json:"deviceId" binding:"max=255"`
+}
+
+// DeletePushTokenRequest is the body for DELETE /push-tokens.
+type DeletePushTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// PushTokenResponse is one entry of GET /push-tokens. The token itself is not echoed back.
+type PushTokenResponse struct {
+	ID         string `json:"id"`
+	Platform   string `json:"platform"`
+	DeviceID   string `json:"deviceId,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// ReferralSummaryResponse is the body of GET /referrals: the user's code, what referrals earn, and the people they
+// referred. Flagged referrals are reported as under_review.
+type ReferralSummaryResponse struct {
+	Code        string                 `json:"code"`
+	Rules       []ReferralRuleResponse `json:"rules"`
+	TotalEarned float64                `json:"totalEarned"` // referral rewards paid to the user, as referrer or referee
+	Referrals   []ReferralResponse     `json:"referrals"`
+}
+
+// ReferralRuleResponse describes one reward: what the referee must do and what each side receives.
+type ReferralRuleResponse struct {
+	Name           string   `json:"name"`
+	Requires       []string `json:"requires"` // kyc_approved, first_transfer
+	ReferrerAmount float64  `json:"referrerAmount"`
+	RefereeAmount  float64  `json:"refereeAmount"`
+}
+
+// ReferralResponse is one referred user. The referee is not named.
+type ReferralResponse struct {
+	ID            string    `json:"id"`
+	Status        string    `json:"status"` // pending, rewarded, under_review
+	KYCApproved   bool      `json:"kycApproved"`
+	FirstTransfer bool      `json:"firstTransfer"`
+	Earned        float64   `json:"earned"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// RefreshTokenRequest is the body for POST /auth/refresh and POST /auth/logout.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+}
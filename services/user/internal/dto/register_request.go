@@ -12,4 +12,6 @@ type RegisterRequest struct {
 	FirstName   string `json:"firstName"   binding:"required,min=1,max=100"`
 	LastName    string `json:"lastName"    binding:"required,min=1,max=100"`
 	PhoneNumber string `json:"phoneNumber" binding:"required,min=10,max=20"`
+	// ReferralCode is the code of the user who invited them; optional.
+	ReferralCode string `json:"referralCode" binding:"omitempty,max=16"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+// SessionResponse is one entry of GET /sessions: a login on a device that can still refresh its tokens.
+type SessionResponse struct {
+	ID         string `json:"id"`
+	DeviceID   string `json:"deviceId"`
+	DeviceName string `json:"deviceName,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+	AppVersion string `json:"appVersion,omitempty"`
+	IPAddress  string `json:"ipAddress,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	Current    bool   `json:"current"` // true for the session the request's access token belongs to
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+}
@@ -28,23 +28,35 @@ type SettingsResponse struct {
 	Theme                    *string  `json:"theme,omitempty"`
 	CreatedAt                string   `json:"createdAt"`
 	UpdatedAt                string   `json:"updatedAt"`
+	// PendingLimitChange is a limit increase still in its cooling-off period; cancel it with DELETE /settings/limits/pending.
+	PendingLimitChange *PendingLimitChangeResponse `json:"pendingLimitChange,omitempty"`
 }
 
-// SetPinRequest is the body for PUT /settings/pin. Requires password; when updating (user already has PIN), currentPin is required. New PIN is exactly 4 digits; hashed server-side.
+// PendingLimitChangeResponse is a scheduled transfer limit increase. Only the limits being raised are set; 0 means no limit.
+type PendingLimitChangeResponse struct {
+	DailyTransferLimit   *float64 `json:"dailyTransferLimit,omitempty"`
+	MonthlyTransferLimit *float64 `json:"monthlyTransferLimit,omitempty"`
+	RequestedAt          string   `json:"requestedAt"`
+	EffectiveAt          string   `json:"effectiveAt"`
+}
+
+// SetPinRequest is the body for PUT /settings/pin. Requires password (or X-Step-Up-Token); when updating (user already has PIN), currentPin is required. New PIN is exactly 4 digits; hashed server-side.
 type SetPinRequest struct {
-	Password   string  `json:"password" binding:"required"`
+	Password   string  `json:"password"`
 	CurrentPin *string `json:"currentPin,omitempty" binding:"omitempty,len=4,numeric"` // required when changing an existing PIN
 	Pin        string  `json:"pin" binding:"required,len=4,numeric"`
 }
 
-// SetLimitsRequest is the body for PUT /settings/limits. Requires password.
+// SetLimitsRequest is the body for PUT /settings/limits. Requires password, or X-Step-Up-Token when limits go up.
+// Decreases apply at once; increases (including 0, no limit) take effect after the cooling-off period.
 type SetLimitsRequest struct {
-	Password             string   `json:"password" binding:"required"`
+	Password             string   `json:"password"`
 	DailyTransferLimit   *float64 `json:"dailyTransferLimit,omitempty" binding:"omitempty,gte=0"`
 	MonthlyTransferLimit *float64 `json:"monthlyTransferLimit,omitempty" binding:"omitempty,gte=0"`
 }
 
-// PasswordConfirmRequest is the body for operations that require password (e.g. pause/resume account).
+// PasswordConfirmRequest is the body for operations that require password (e.g. pause/resume account). The password
+// may be omitted when the request carries an X-Step-Up-Token for the action.
 type PasswordConfirmRequest struct {
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password"`
 }
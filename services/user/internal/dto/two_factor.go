@@ -12,13 +12,70 @@ type VerifySetup2FARequest struct {
 	Code string `json:"code" binding:"required,len=6,numeric"`
 }
 
-// VerifyLogin2FARequest is the body for POST /2fa/verify-login.
+// VerifySetup2FAResponse is returned when 2FA is enabled and when recovery codes are regenerated. The codes are shown
+// once; only their hashes are stored.
+type VerifySetup2FAResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// VerifyLogin2FARequest is the body for POST /2fa/verify-login. Method selects what code is: the authenticator app
+// code (totp, default), a recovery code, or the one-time code from POST /2fa/send-otp (otp).
 type VerifyLogin2FARequest struct {
 	TwoFactorToken string `json:"twoFactorToken" binding:"required"`
-	Code           string `json:"code" binding:"required,len=6,numeric"`
+	Code           string `json:"code" binding:"required,max=20"`
+	Method         string `json:"method" binding:"omitempty,oneof=totp recovery_code otp"`
+}
+
+// Send2FAOTPRequest is the body for POST /2fa/send-otp. Channel defaults to the user's fallback preference, then email.
+type Send2FAOTPRequest struct {
+	TwoFactorToken string `json:"twoFactorToken" binding:"required"`
+	Channel        string `json:"channel" binding:"omitempty,oneof=sms whatsapp email"`
+}
+
+// Send2FAOTPResponse tells the client where the code went (masked) and how long it is valid.
+type Send2FAOTPResponse struct {
+	Channel     string `json:"channel"`
+	Destination string `json:"destination"`
+	ExpiresIn   int    `json:"expiresInSeconds"`
+}
+
+// SetTwoFactorFallbackRequest is the body for PUT /2fa/fallback. none clears the preference.
+type SetTwoFactorFallbackRequest struct {
+	Channel string `json:"channel" binding:"required,oneof=sms whatsapp email none"`
 }
 
-// Disable2FARequest is the body for POST /2fa/disable.
+// TwoFactorStatusResponse is returned from GET /2fa/status.
+type TwoFactorStatusResponse struct {
+	Enabled                bool   `json:"enabled"`
+	FallbackChannel        string `json:"fallbackChannel,omitempty"`
+	RecoveryCodesRemaining int    `json:"recoveryCodesRemaining"`
+}
+
+// Disable2FARequest is the body for POST /2fa/disable. Password may be omitted with an X-Step-Up-Token.
 type Disable2FARequest struct {
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password"`
+}
+
+// StepUpRequest is the body for POST /auth/step-up: the password plus the authenticator code (method totp, default
+// when 2FA is on) or a code from POST /auth/step-up/send-otp (method otp). Action is checked by the service against
+// the step-up actions. A transfer step-up names the largest amount (naira) it approves.
+type StepUpRequest struct {
+	Action   string  `json:"action" binding:"required"`
+	Password string  `json:"password" binding:"required"`
+	Code     string  `json:"code" binding:"required,len=6,numeric"`
+	Method   string  `json:"method" binding:"omitempty,oneof=totp otp"`
+	Amount   float64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// StepUpResponse carries the token to send as X-Step-Up-Token with the sensitive request.
+type StepUpResponse struct {
+	StepUpToken string  `json:"stepUpToken"`
+	Action      string  `json:"action"`
+	Amount      float64 `json:"amount,omitempty"`
+	ExpiresAt   string  `json:"expiresAt"`
+}
+
+// SendStepUpOTPRequest is the body for POST /auth/step-up/send-otp. Channel defaults to the fallback preference, then email.
+type SendStepUpOTPRequest struct {
+	Channel string `json:"channel" binding:"omitempty,oneof=sms whatsapp email"`
 }
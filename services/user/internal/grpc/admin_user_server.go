@@ -8,7 +8,7 @@ import (
 	"github.com/abubakvr/payup-backend/services/user/internal/service"
 )
 
-// AdminUserServer implements user.UserServiceForAdmin for Admin service (list users, get user, set restricted).
+// AdminUserServer implements user.UserServiceForAdmin for Admin service (list users, get user, set restricted, sessions).
 type AdminUserServer struct {
 	userpb.UnimplementedUserServiceForAdminServer
 	userRepo *repository.UserRepository
@@ -90,3 +90,73 @@ func (s *AdminUserServer) SetUserRestricted(ctx context.Context, req *userpb.Set
 	}
 	return &userpb.SetUserRestrictedResponse{Success: true}, nil
 }
+
+func (s *AdminUserServer) ListUserSessions(ctx context.Context, req *userpb.ListUserSessionsRequest) (*userpb.ListUserSessionsResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &userpb.ListUserSessionsResponse{}, nil
+	}
+	sessions, err := s.userSvc.ListSessions(ctx, req.UserId, "")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*userpb.UserSession, len(sessions))
+	for i := range sessions {
+		out[i] = &userpb.UserSession{
+			Id:         sessions[i].ID,
+			DeviceId:   sessions[i].DeviceID,
+			DeviceName: sessions[i].DeviceName,
+			Platform:   sessions[i].Platform,
+			AppVersion: sessions[i].AppVersion,
+			IpAddress:  sessions[i].IPAddress,
+			UserAgent:  sessions[i].UserAgent,
+			CreatedAt:  sessions[i].CreatedAt,
+			LastSeenAt: sessions[i].LastSeenAt,
+		}
+	}
+	return &userpb.ListUserSessionsResponse{Sessions: out}, nil
+}
+
+func (s *AdminUserServer) RevokeUserSessions(ctx context.Context, req *userpb.RevokeUserSessionsRequest) (*userpb.RevokeUserSessionsResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &userpb.RevokeUserSessionsResponse{Success: false, Message: "user_id required"}, nil
+	}
+	if err := s.userSvc.AdminRevokeSessions(ctx, req.UserId, req.SessionId); err != nil {
+		if err == repository.ErrUserNotFound {
+			return &userpb.RevokeUserSessionsResponse{Success: false, Message: "user not found"}, nil
+		}
+		if err == service.ErrSessionNotFound {
+			return &userpb.RevokeUserSessionsResponse{Success: false, Message: "session not found"}, nil
+		}
+		return &userpb.RevokeUserSessionsResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &userpb.RevokeUserSessionsResponse{Success: true}, nil
+}
+
+// UnlockUser clears the user's attempt lockouts (login, 2FA, PIN) before they expire.
+func (s *AdminUserServer) UnlockUser(ctx context.Context, req *userpb.UnlockUserRequest) (*userpb.UnlockUserResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &userpb.UnlockUserResponse{Success: false, Message: "user_id required"}, nil
+	}
+	wasLocked, err := s.userSvc.UnlockUser(ctx, req.UserId)
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return &userpb.UnlockUserResponse{Success: false, Message: "user not found"}, nil
+		}
+		return &userpb.UnlockUserResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &userpb.UnlockUserResponse{Success: true, WasLocked: wasLocked}, nil
+}
+
+// ResetTwoFactor disables 2FA for the user (admin-assisted recovery).
+func (s *AdminUserServer) ResetTwoFactor(ctx context.Context, req *userpb.ResetTwoFactorRequest) (*userpb.ResetTwoFactorResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &userpb.ResetTwoFactorResponse{Success: false, Message: "user_id required"}, nil
+	}
+	if err := s.userSvc.AdminReset2FA(ctx, req.UserId, req.AdminId, req.Reason); err != nil {
+		if err == repository.ErrUserNotFound {
+			return &userpb.ResetTwoFactorResponse{Success: false, Message: "user not found"}, nil
+		}
+		return &userpb.ResetTwoFactorResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &userpb.ResetTwoFactorResponse{Success: true}, nil
+}
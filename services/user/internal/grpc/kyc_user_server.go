@@ -29,10 +29,11 @@ func (s *KYCUserServer) GetUserForKYC(ctx context.Context, req *userpb.GetUserFo
 		return &userpb.GetUserForKYCResponse{Found: false}, nil
 	}
 	return &userpb.GetUserForKYCResponse{
-		Found:     true,
-		UserId:    user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
+		Found:       true,
+		UserId:      user.ID,
+		Email:       user.Email,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		PhoneNumber: user.PhoneNumber,
 	}, nil
 }
@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+
+	userpb "github.com/abubakvr/payup-backend/proto/user"
+	"github.com/abubakvr/payup-backend/services/user/internal/service"
+)
+
+// NotificationUserServer implements user.UserServiceForNotification: push tokens for fan-out and pruning, and the
+// recipient details templated notifications are addressed and localized with.
+type NotificationUserServer struct {
+	userpb.UnimplementedUserServiceForNotificationServer
+	userSvc *service.UserService
+}
+
+// NewNotificationUserServer returns a new NotificationUserServer.
+func NewNotificationUserServer(userSvc *service.UserService) *NotificationUserServer {
+	return &NotificationUserServer{userSvc: userSvc}
+}
+
+// ListPushTokens returns every push token registered by the user.
+func (s *NotificationUserServer) ListPushTokens(ctx context.Context, req *userpb.ListPushTokensRequest) (*userpb.ListPushTokensResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &userpb.ListPushTokensResponse{}, nil
+	}
+	tokens, err := s.userSvc.PushTokensForUser(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.ListPushTokensResponse{Tokens: make([]*userpb.PushToken, 0, len(tokens))}
+	for _, t := range tokens {
+		resp.Tokens = append(resp.Tokens, &userpb.PushToken{Token: t.Token, Platform: t.Platform})
+	}
+	return resp, nil
+}
+
+// RemovePushTokens deletes tokens the push provider reported as invalid.
+func (s *NotificationUserServer) RemovePushTokens(ctx context.Context, req *userpb.RemovePushTokensRequest) (*userpb.RemovePushTokensResponse, error) {
+	if req == nil || len(req.Tokens) == 0 {
+		return &userpb.RemovePushTokensResponse{}, nil
+	}
+	n, err := s.userSvc.RemoveInvalidPushTokens(req.Tokens, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.RemovePushTokensResponse{Removed: int32(n)}, nil
+}
+
+// GetNotificationRecipient returns where to reach the user and which language to render their notifications in.
+func (s *NotificationUserServer) GetNotificationRecipient(ctx context.Context, req *userpb.GetNotificationRecipientRequest) (*userpb.GetNotificationRecipientResponse, error) {
+	if req == nil || req.UserId == "" {
+		return &userpb.GetNotificationRecipientResponse{}, nil
+	}
+	user, language, err := s.userSvc.NotificationRecipient(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return &userpb.GetNotificationRecipientResponse{}, nil
+	}
+	return &userpb.GetNotificationRecipientResponse{
+		Found:       true,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Language:    language,
+	}, nil
+}
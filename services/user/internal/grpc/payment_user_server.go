@@ -18,17 +18,23 @@ func NewPaymentUserServer(userSvc *service.UserService) *PaymentUserServer {
 	return &PaymentUserServer{userSvc: userSvc}
 }
 
-// ValidateTransfer checks user exists, not banking restricted, transfers not paused, and PIN matches.
-// Returns daily/monthly limits so payment service can enforce them against its transaction data.
+// ValidateTransfer checks user exists, not banking restricted, transfers not paused, and PIN (with the step-up token for
+// large amounts) or passkey approval. Returns daily/monthly limits so payment service can enforce them against its transaction data.
 func (s *PaymentUserServer) ValidateTransfer(ctx context.Context, req *userpb.ValidateTransferRequest) (*userpb.ValidateTransferResponse, error) {
 	if req == nil || req.UserId == "" {
 		return &userpb.ValidateTransferResponse{Allowed: false, Message: "user_id required"}, nil
 	}
-	allowed, message, dailyLimit, monthlyLimit := s.userSvc.ValidateTransfer(ctx, req.UserId, req.Amount, req.Pin)
+	allowed, message, dailyLimit, monthlyLimit, stepUpRequired := s.userSvc.ValidateTransfer(ctx, req.UserId, req.Amount, service.TransferAuth{
+		Pin:              req.Pin,
+		StepUpToken:      req.StepUpToken,
+		PasskeyAssertion: req.PasskeyAssertion,
+		Payee:            service.Payee{BankCode: req.BeneficiaryBankCode, AccountNumber: req.BeneficiaryAccountNumber},
+	})
 	return &userpb.ValidateTransferResponse{
 		Allowed:             allowed,
 		Message:             message,
 		DailyTransferLimit:  dailyLimit,
 		MonthlyTransferLimit: monthlyLimit,
+		StepUpRequired:       stepUpRequired,
 	}, nil
 }
@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MilestoneEvent is the part of a kyc-events or wallet-events message the referral program reads: kyc_approved from
+// the KYC service and transfer_success from the payment service.
+type MilestoneEvent struct {
+	EventType string `json:"event_type"`
+	UserID    string `json:"user_id"`
+}
+
+// MilestoneConsumer reads kyc-events and wallet-events and calls onEvent for each event with a user id.
+type MilestoneConsumer struct {
+	reader  *kafka.Reader
+	onEvent func(ctx context.Context, eventType, userID string) error
+}
+
+// NewMilestoneConsumer creates the consumer. Returns nil if there are no brokers or no handler.
+func NewMilestoneConsumer(brokers []string, onEvent func(ctx context.Context, eventType, userID string) error) *MilestoneConsumer {
+	if len(brokers) == 0 || onEvent == nil {
+		return nil
+	}
+	return &MilestoneConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			GroupID:     "user-service",
+			GroupTopics: []string{"kyc-events", "wallet-events"},
+		}),
+		onEvent: onEvent,
+	}
+}
+
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = time.Minute
+)
+
+// backoff is the wait before retry attempt+1: 1s, 2s, 4s, ... capped at retryMaxDelay.
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << (attempt - 1)
+	if d <= 0 || d > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return d
+}
+
+// Start runs the consumer loop. Call in a goroutine. The offset is committed only after onEvent succeeds; a failing
+// event (database down, say) is retried with backoff until it clears, since committing would lose the milestone.
+func (c *MilestoneConsumer) Start() {
+	if c == nil {
+		return
+	}
+	ctx := context.Background()
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			log.Printf("user milestone consumer: %v", err)
+			continue
+		}
+		var ev MilestoneEvent
+		if err := json.Unmarshal(msg.Value, &ev); err != nil {
+			log.Printf("user milestone consumer: invalid JSON on %s: %v", msg.Topic, err)
+		} else if ev.EventType != "" && ev.UserID != "" {
+			for i := 1; ; i++ {
+				err := c.onEvent(ctx, ev.EventType, ev.UserID)
+				if err == nil {
+					break
+				}
+				log.Printf("user milestone consumer: %s user=%s attempt=%d: %v", ev.EventType, ev.UserID, i, err)
+				time.Sleep(backoff(i))
+			}
+		}
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			log.Printf("user milestone consumer: commit failed topic=%s offset=%d err=%v", msg.Topic, msg.Offset, err)
+		}
+	}
+}
@@ -16,10 +16,25 @@ type Producer struct {
 }
 
 // NotificationEvent is the payload for the notification-events topic (consumed by notification service).
+// UserID is the recipient's user ID, when known; the notification service files the event in their inbox and applies
+// their notification preferences. Types with a template in the notification service send Data (the values the
+// template fills in) instead of subject and html in Metadata.
 type NotificationEvent struct {
+	UserID   string                 `json:"user_id,omitempty"`
 	Type     string                 `json:"type"`
 	Channel  string                 `json:"channel"`
 	Metadata map[string]interface{} `json:"metadata"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// UserEvent is published to user-events when account details other services keep a copy of change. Email and
+// PhoneNumber are the values after the change; Field says which one changed.
+type UserEvent struct {
+	EventType   string `json:"event_type"` // user_contact_changed
+	UserID      string `json:"user_id"`
+	Field       string `json:"field"` // email or phone
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
 }
 
 func NewProducer(brokers []string) *Producer {
@@ -48,6 +63,20 @@ func (p *Producer) UserCreated(payload []byte) error {
 	})
 }
 
+// SendUserEvent publishes ev to user-events. Safe to call with a nil producer (no-op).
+func (p *Producer) SendUserEvent(ev UserEvent) error {
+	if p == nil || p.writer == nil {
+		return nil
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.writer.WriteMessages(ctx, kafka.Message{Key: []byte(ev.UserID), Value: payload})
+}
+
 // SendAuditEvent sends an audit event to the audit-events topic for the audit service to consume.
 func (p *Producer) SendAuditEvent(event AuditEvent) error {
 	payload, err := json.Marshal(event)
@@ -1,15 +1,18 @@
 package middleware
 
 import (
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/services/user/internal/common/response"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		// TODO: authentication logic here
-
-		ctx.Next()
-	}
+// AuthMiddleware rejects requests without a valid access token: bad signature or expiry, a purpose token (2FA
+// pending), or a token on the deny-list. Handlers read the claims with auth.DecodeJWTFromContext.
+func AuthMiddleware(a *authn.Authenticator) gin.HandlerFunc {
+	return a.Middleware(func(ctx *gin.Context, err error) {
+		response.AuthErrorResponse(ctx, string(response.AuthenticationFailed), err.Error())
+		ctx.Abort()
+	})
 }
 func EnforceResponseFormat() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Contact change kinds.
+const (
+	ContactEmail = "email"
+	ContactPhone = "phone"
+)
+
+// ContactChange statuses.
+const (
+	ContactChangePending   = "pending"
+	ContactChangeConfirmed = "confirmed"
+	ContactChangeCancelled = "cancelled"
+)
+
+// ContactChange is a new email or phone number waiting for the code sent to it.
+type ContactChange struct {
+	ID          string
+	UserID      string
+	Kind        string
+	NewValue    string
+	CodeHash    string
+	Channel     string
+	Status      string
+	RequestedAt time.Time
+	ExpiresAt   time.Time
+	ResolvedAt  *time.Time
+}
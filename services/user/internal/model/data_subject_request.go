@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Data-subject request kinds.
+const (
+	DataSubjectExport   = "export"
+	DataSubjectDeletion = "deletion"
+)
+
+// Data-subject request statuses. A held deletion is blocked by a retention hold (e.g. money left in the wallet) and
+// must be requested again once the hold is cleared.
+const (
+	DataSubjectPending   = "pending"
+	DataSubjectCompleted = "completed"
+	DataSubjectHeld      = "held"
+	DataSubjectCancelled = "cancelled"
+	DataSubjectFailed    = "failed"
+)
+
+// DataSubjectRequest is an NDPR data export or account deletion. The export archive itself is loaded separately.
+type DataSubjectRequest struct {
+	ID               string
+	UserID           string
+	Kind             string
+	Status           string
+	ScheduledFor     time.Time
+	Attempts         int
+	LastError        string
+	Result           json.RawMessage
+	ArchiveExpiresAt *time.Time
+	RequestedAt      time.Time
+	CompletedAt      *time.Time
+}
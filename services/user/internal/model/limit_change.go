@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// LimitChange statuses.
+const (
+	LimitChangePending   = "pending"
+	LimitChangeApplied   = "applied"
+	LimitChangeCancelled = "cancelled"
+)
+
+// LimitChange is a transfer limit increase waiting out the cooling-off period. A nil limit is not part of the change;
+// 0 means no limit.
+type LimitChange struct {
+	ID                   string
+	UserID               string
+	DailyTransferLimit   *float64
+	MonthlyTransferLimit *float64
+	Status               string
+	RequestedAt          time.Time
+	EffectiveAt          time.Time
+	ResolvedAt           *time.Time
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Passkey is a WebAuthn credential registered by the user on one authenticator.
+type Passkey struct {
+	ID             string
+	UserID         string
+	CredentialID   []byte
+	PublicKey      []byte // COSE key
+	Algorithm      int
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	Name           string
+	BackupEligible bool
+	BackedUp       bool
+	CreatedAt      time.Time
+	LastUsedAt     *time.Time
+}
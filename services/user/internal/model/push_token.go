@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Push platforms. The notification service sends ios tokens through APNs and android tokens through FCM.
+const (
+	PushPlatformIOS     = "ios"
+	PushPlatformAndroid = "android"
+)
+
+// PushToken is a device token registered by the mobile app for push notifications.
+type PushToken struct {
+	ID         string
+	UserID     string
+	Token      string
+	Platform   string
+	DeviceID   string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
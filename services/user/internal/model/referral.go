@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// Referral milestones a reward rule can wait for. Both are reached by the referee.
+const (
+	ReferralMilestoneKYCApproved   = "kyc_approved"
+	ReferralMilestoneFirstTransfer = "first_transfer"
+)
+
+// Referral statuses. A flagged referral tripped an anti-abuse check and earns nothing until reviewed.
+const (
+	ReferralPending  = "pending"
+	ReferralRewarded = "rewarded"
+	ReferralFlagged  = "flagged"
+)
+
+// Referral reward statuses.
+const (
+	ReferralRewardPending = "pending"
+	ReferralRewardPaid    = "paid"
+	ReferralRewardFailed  = "failed"
+)
+
+// Who a reward is paid to.
+const (
+	ReferralBeneficiaryReferrer = "referrer"
+	ReferralBeneficiaryReferee  = "referee"
+)
+
+// ReferralRule credits the referrer and/or referee once the referee has reached every milestone in Requires.
+type ReferralRule struct {
+	Name           string   `json:"name"`
+	Requires       []string `json:"requires"`
+	ReferrerAmount float64  `json:"referrer_amount"`
+	RefereeAmount  float64  `json:"referee_amount"`
+}
+
+// Referral attributes a registration to the owner of the referral code used.
+type Referral struct {
+	ID               string
+	ReferrerID       string
+	RefereeID        string
+	Code             string
+	RefereePhoneHash string
+	Status           string
+	FlaggedReason    string
+	KYCApprovedAt    *time.Time
+	FirstTransferAt  *time.Time
+	CreatedAt        time.Time
+}
+
+// Reached reports whether the referee has reached milestone.
+func (r *Referral) Reached(milestone string) bool {
+	switch milestone {
+	case ReferralMilestoneKYCApproved:
+		return r.KYCApprovedAt != nil
+	case ReferralMilestoneFirstTransfer:
+		return r.FirstTransferAt != nil
+	}
+	return false
+}
+
+// ReferralReward is one payout under one rule to one side of a referral.
+type ReferralReward struct {
+	ID             string
+	ReferralID     string
+	Rule           string
+	Beneficiary    string
+	UserID         string
+	Amount         float64
+	Status         string
+	Attempts       int
+	LastError      string
+	TransactionRef string
+	CreatedAt      time.Time
+	PaidAt         *time.Time
+}
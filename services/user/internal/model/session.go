@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// DeviceInfo identifies the client a login or token refresh comes from.
+type DeviceInfo struct {
+	DeviceID   string // X-Device-ID; derived from platform and user agent when the client sends none
+	DeviceName string // e.g. "Amina's iPhone"
+	Platform   string // ios, android, web
+	AppVersion string
+	IPAddress  string
+	UserAgent  string
+}
+
+// Session is one login on one device. ID is also the family_id of the refresh tokens issued for it.
+type Session struct {
+	ID            string
+	UserID        string
+	Device        DeviceInfo
+	CreatedAt     time.Time
+	LastSeenAt    time.Time
+	RevokedAt     *time.Time
+	RevokedReason string
+}
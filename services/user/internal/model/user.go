@@ -18,9 +18,11 @@ type User struct {
 	UpdatedAt          time.Time
 }
 
+// LoginRequest identifies the account by Email or, when Email is empty, by PhoneNumberHash.
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email           string `json:"email"`
+	PhoneNumberHash string `json:"-"`
+	Password        string `json:"password"`
 }
 
 // LoginResponse is returned from Login with tokens and expiries.
@@ -36,6 +38,7 @@ type LoginRequires2FAResponse struct {
 	RequiresTwoFactor       bool      `json:"requires_two_factor"`
 	TwoFactorToken          string    `json:"two_factor_token"`
 	TwoFactorTokenExpiresAt time.Time `json:"two_factor_token_expires_at"`
+	FallbackChannel         string    `json:"fallback_channel,omitempty"` // channel POST /2fa/send-otp uses by default
 	Message                 string    `json:"message"`
 }
 
@@ -60,21 +63,25 @@ type PasswordResetToken struct {
 type RefreshToken struct {
 	ID        string
 	UserID    string
+	FamilyID  string // shared by a login and every token rotated from it; empty on insert starts a new family
 	TokenHash [32]byte
 	ExpiresAt time.Time
 	Revoked   bool
-	CreatedAt time.Time
+	// RevokedReason is refresh_tokens.revoked_reason (repository.RevokeReason*); empty while the token is live
+	RevokedReason string
+	CreatedAt     time.Time
 }
 
 // UserSettings holds optional per-user preferences. Created when the user (profile) is created.
 type UserSettings struct {
 	UserID                   string
 	PinHash                  *string
-	BiometricEnabled         bool
+	BiometricEnabled         bool // passkey assertions may replace the PIN for transfers
 	TwoFactorEnabled         bool
 	TotpSecret               *string    // Active TOTP secret when 2FA is enabled (never expose to client).
 	TotpSecretPending        *string    // During 2FA setup, holds the new secret until verified.
 	TotpPendingCreatedAt     *time.Time // When pending secret was created (for expiry, e.g. 10 min).
+	TwoFactorFallbackChannel *string    // sms, whatsapp or email: where the fallback login code is sent; nil if not chosen.
 	DailyTransferLimit       *float64
 	MonthlyTransferLimit     *float64
 	TransactionAlertsEnabled bool
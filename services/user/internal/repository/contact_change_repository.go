@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrContactInUse is returned when another account took the new email or phone number before the change was confirmed.
+var ErrContactInUse = errors.New("this email or phone number is already used by another account")
+
+const contactChangeColumns = `id, user_id, kind, new_value, code_hash, channel, status, requested_at, expires_at, resolved_at`
+
+// CreateContactChange replaces the user's pending change of c.Kind with c and returns the stored row.
+func (r *UserRepository) CreateContactChange(c *model.ContactChange) (*model.ContactChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`UPDATE contact_changes SET status = $3, resolved_at = $4 WHERE user_id = $1 AND kind = $2 AND status = $5`,
+		c.UserID, c.Kind, model.ContactChangeCancelled, time.Now(), model.ContactChangePending); err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO contact_changes (user_id, kind, new_value, code_hash, channel, status, requested_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + contactChangeColumns
+	created, err := scanContactChange(tx.QueryRow(query, c.UserID, c.Kind, c.NewValue, c.CodeHash, c.Channel,
+		model.ContactChangePending, c.RequestedAt, c.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+	return created, tx.Commit()
+}
+
+// GetPendingContactChange returns the user's pending change of kind, or nil if there is none.
+func (r *UserRepository) GetPendingContactChange(userID, kind string) (*model.ContactChange, error) {
+	c, err := scanContactChange(r.db.QueryRow(`SELECT `+contactChangeColumns+` FROM contact_changes WHERE user_id = $1 AND kind = $2 AND status = $3`,
+		userID, kind, model.ContactChangePending))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// CancelContactChange cancels a pending change. Returns false if it was no longer pending.
+func (r *UserRepository) CancelContactChange(id string) (bool, error) {
+	res, err := r.db.Exec(`UPDATE contact_changes SET status = $2, resolved_at = $3 WHERE id = $1 AND status = $4`,
+		id, model.ContactChangeCancelled, time.Now(), model.ContactChangePending)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+// LastContactChange returns when the user last confirmed a change of kind, or nil if never.
+func (r *UserRepository) LastContactChange(userID, kind string) (*time.Time, error) {
+	var at sql.NullTime
+	err := r.db.QueryRow(`SELECT MAX(resolved_at) FROM contact_changes WHERE user_id = $1 AND kind = $2 AND status = $3`,
+		userID, kind, model.ContactChangeConfirmed).Scan(&at)
+	if err != nil || !at.Valid {
+		return nil, err
+	}
+	return &at.Time, nil
+}
+
+// ConfirmContactChange writes the pending change to the users row and marks it confirmed, in one transaction. A new
+// email counts as verified: the user just entered the code sent to it. Returns false if the change was no longer
+// pending (confirmed or replaced concurrently).
+func (r *UserRepository) ConfirmContactChange(c *model.ContactChange, phoneHash string, now time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	res, err := tx.Exec(`UPDATE contact_changes SET status = $2, resolved_at = $3 WHERE id = $1 AND status = $4`,
+		c.ID, model.ContactChangeConfirmed, now, model.ContactChangePending)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		return false, nil
+	}
+	if c.Kind == model.ContactEmail {
+		_, err = tx.Exec(`UPDATE users SET email = $2, email_verified = true, updated_at = $3 WHERE id = $1`, c.UserID, c.NewValue, now)
+	} else {
+		_, err = tx.Exec(`UPDATE users SET phone_number = $2, phone_number_hash = $3, updated_at = $4 WHERE id = $1`, c.UserID, c.NewValue, phoneHash, now)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return false, ErrContactInUse
+		}
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func scanContactChange(row sessionScanner) (*model.ContactChange, error) {
+	var c model.ContactChange
+	var resolvedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.UserID, &c.Kind, &c.NewValue, &c.CodeHash, &c.Channel, &c.Status, &c.RequestedAt, &c.ExpiresAt, &resolvedAt); err != nil {
+		return nil, err
+	}
+	if resolvedAt.Valid {
+		c.ResolvedAt = &resolvedAt.Time
+	}
+	return &c, nil
+}
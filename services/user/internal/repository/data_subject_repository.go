@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrDataSubjectRequestPending is returned when the user already has a pending request of the same kind.
+var ErrDataSubjectRequestPending = errors.New("a request of this kind is already pending")
+
+const dataSubjectColumns = `id, user_id, kind, status, scheduled_for, attempts, COALESCE(last_error, ''), result, archive_expires_at, requested_at, completed_at`
+
+// CreateDataSubjectRequest stores a pending request and returns it.
+func (r *UserRepository) CreateDataSubjectRequest(userID, kind string, scheduledFor time.Time) (*model.DataSubjectRequest, error) {
+	query := `INSERT INTO data_subject_requests (user_id, kind, status, scheduled_for, requested_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + dataSubjectColumns
+	d, err := scanDataSubjectRequest(r.db.QueryRow(query, userID, kind, model.DataSubjectPending, scheduledFor, time.Now()))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDataSubjectRequestPending
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// ListDataSubjectRequests returns the user's requests, newest first.
+func (r *UserRepository) ListDataSubjectRequests(userID string) ([]model.DataSubjectRequest, error) {
+	rows, err := r.db.Query(`SELECT `+dataSubjectColumns+` FROM data_subject_requests WHERE user_id = $1 ORDER BY requested_at DESC LIMIT 50`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.DataSubjectRequest
+	for rows.Next() {
+		d, err := scanDataSubjectRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// GetExportArchive returns the zip of a completed export owned by userID, or nil if there is none or it has expired.
+func (r *UserRepository) GetExportArchive(userID, id string, now time.Time) ([]byte, error) {
+	var archive []byte
+	err := r.db.QueryRow(`SELECT archive FROM data_subject_requests
+		WHERE id = $1 AND user_id = $2 AND kind = $3 AND status = $4 AND archive IS NOT NULL AND archive_expires_at > $5`,
+		id, userID, model.DataSubjectExport, model.DataSubjectCompleted, now).Scan(&archive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return archive, err
+}
+
+// CancelDataSubjectRequest cancels the user's pending request of kind. Returns nil if there was none.
+func (r *UserRepository) CancelDataSubjectRequest(userID, kind string) (*model.DataSubjectRequest, error) {
+	d, err := scanDataSubjectRequest(r.db.QueryRow(`UPDATE data_subject_requests SET status = $3, completed_at = $4
+		WHERE user_id = $1 AND kind = $2 AND status = $5
+		RETURNING `+dataSubjectColumns,
+		userID, kind, model.DataSubjectCancelled, time.Now(), model.DataSubjectPending))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// ClaimDueDataSubjectRequests returns up to limit pending requests whose scheduled_for has passed and pushes their
+// scheduled_for out by lease, so a second instance of the sweep skips them and a crashed run is retried later.
+func (r *UserRepository) ClaimDueDataSubjectRequests(now time.Time, lease time.Duration, limit int) ([]model.DataSubjectRequest, error) {
+	query := `UPDATE data_subject_requests SET scheduled_for = $2, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM data_subject_requests
+			WHERE status = $3 AND scheduled_for <= $1
+			ORDER BY scheduled_for
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + dataSubjectColumns
+	rows, err := r.db.Query(query, now, now.Add(lease), model.DataSubjectPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.DataSubjectRequest
+	for rows.Next() {
+		d, err := scanDataSubjectRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// CompleteDataSubjectRequest moves a pending request to status (completed, held or failed) with its result. archive
+// and archiveExpiresAt are set for exports only.
+func (r *UserRepository) CompleteDataSubjectRequest(id, status string, result interface{}, archive []byte, archiveExpiresAt *time.Time) error {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	var expires sql.NullTime
+	if archiveExpiresAt != nil {
+		expires = sql.NullTime{Time: *archiveExpiresAt, Valid: true}
+	}
+	_, err = r.db.Exec(`UPDATE data_subject_requests SET status = $2, result = $3, archive = $4, archive_expires_at = $5, completed_at = $6
+		WHERE id = $1 AND status = $7`,
+		id, status, resultJSON, archive, expires, time.Now(), model.DataSubjectPending)
+	return err
+}
+
+// RetryDataSubjectRequest records why a run failed and when the request should be tried again.
+func (r *UserRepository) RetryDataSubjectRequest(id, lastError string, retryAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE data_subject_requests SET last_error = $2, scheduled_for = $3 WHERE id = $1 AND status = $4`,
+		id, lastError, retryAt, model.DataSubjectPending)
+	return err
+}
+
+// PurgeExpiredExports drops export archives past their expiry. Returns the number cleared.
+func (r *UserRepository) PurgeExpiredExports(now time.Time) (int64, error) {
+	res, err := r.db.Exec(`UPDATE data_subject_requests SET archive = NULL WHERE archive IS NOT NULL AND archive_expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// AnonymizeUser blanks the account's personal data in one transaction. The users row stays (its id is referenced by
+// audit and payment records) with a placeholder email and phone hash so the real ones can be registered again, and a
+// password no hash can match. Second factors, passkeys, pending changes and export archives are removed; sessions keep
+// their timestamps but lose device and network details.
+func (r *UserRepository) AnonymizeUser(userID string, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE users SET email = $2, first_name = ''::bytea, last_name = ''::bytea, phone_number = ''::bytea,
+			phone_number_hash = $3, password_hash = '!', email_verified = false, deleted_at = $4, updated_at = $4
+			WHERE id = $1`,
+			[]interface{}{userID, "deleted+" + userID + "@deleted.invalid", deletedPhoneHash(userID), now}},
+		{`UPDATE user_settings SET pin_hash = NULL, biometric_enabled = false, two_factor_enabled = false,
+			totp_secret = NULL, totp_secret_pending = NULL, totp_pending_created_at = NULL, two_factor_fallback_channel = NULL,
+			transfers_disabled = true, updated_at = $2
+			WHERE user_id = $1`, []interface{}{userID, now}},
+		{`UPDATE user_sessions SET device_name = NULL, ip_address = NULL, user_agent = NULL WHERE user_id = $1`, []interface{}{userID}},
+		{`DELETE FROM webauthn_credentials WHERE user_id = $1`, []interface{}{userID}},
+		{`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, []interface{}{userID}},
+		{`DELETE FROM contact_changes WHERE user_id = $1`, []interface{}{userID}},
+		{`DELETE FROM email_verification_tokens WHERE user_id = $1`, []interface{}{userID}},
+		{`DELETE FROM password_reset_tokens WHERE user_id = $1`, []interface{}{userID}},
+		{`UPDATE transfer_limit_changes SET status = $2, resolved_at = $3 WHERE user_id = $1 AND status = $4`,
+			[]interface{}{userID, model.LimitChangeCancelled, now, model.LimitChangePending}},
+		{`UPDATE data_subject_requests SET archive = NULL, status = CASE WHEN status = $2 AND kind = $3 THEN $4 ELSE status END
+			WHERE user_id = $1`,
+			[]interface{}{userID, model.DataSubjectPending, model.DataSubjectExport, model.DataSubjectCancelled}},
+	}
+	for _, st := range statements {
+		if _, err := tx.Exec(st.query, st.args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// deletedPhoneHash is the phone_number_hash of a deleted account: unique, and not the hash of any phone number.
+func deletedPhoneHash(userID string) string {
+	h := sha256.Sum256([]byte("deleted:" + userID))
+	return hex.EncodeToString(h[:])
+}
+
+func scanDataSubjectRequest(row sessionScanner) (*model.DataSubjectRequest, error) {
+	var d model.DataSubjectRequest
+	var result []byte
+	var archiveExpiresAt, completedAt sql.NullTime
+	if err := row.Scan(&d.ID, &d.UserID, &d.Kind, &d.Status, &d.ScheduledFor, &d.Attempts, &d.LastError, &result,
+		&archiveExpiresAt, &d.RequestedAt, &completedAt); err != nil {
+		return nil, err
+	}
+	if len(result) > 0 {
+		d.Result = result
+	}
+	if archiveExpiresAt.Valid {
+		d.ArchiveExpiresAt = &archiveExpiresAt.Time
+	}
+	if completedAt.Valid {
+		d.CompletedAt = &completedAt.Time
+	}
+	return &d, nil
+}
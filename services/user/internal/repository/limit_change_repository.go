@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+const limitChangeColumns = `id, user_id, daily_transfer_limit, monthly_transfer_limit, status, requested_at, effective_at, resolved_at`
+
+// SchedulePendingLimitChange replaces the user's pending limit change with c and returns the stored row. c keeps its own
+// requested_at and effective_at, so a trimmed change can carry over the original cooling-off.
+func (r *UserRepository) SchedulePendingLimitChange(c *model.LimitChange) (*model.LimitChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`UPDATE transfer_limit_changes SET status = $2, resolved_at = $3 WHERE user_id = $1 AND status = $4`,
+		c.UserID, model.LimitChangeCancelled, time.Now(), model.LimitChangePending); err != nil {
+		return nil, err
+	}
+	query := `INSERT INTO transfer_limit_changes (user_id, daily_transfer_limit, monthly_transfer_limit, status, requested_at, effective_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + limitChangeColumns
+	created, err := scanLimitChange(tx.QueryRow(query, c.UserID, nullFloat(c.DailyTransferLimit), nullFloat(c.MonthlyTransferLimit),
+		model.LimitChangePending, c.RequestedAt, c.EffectiveAt))
+	if err != nil {
+		return nil, err
+	}
+	return created, tx.Commit()
+}
+
+// GetPendingLimitChange returns the user's pending limit change, or nil if there is none.
+func (r *UserRepository) GetPendingLimitChange(userID string) (*model.LimitChange, error) {
+	c, err := scanLimitChange(r.db.QueryRow(`SELECT `+limitChangeColumns+` FROM transfer_limit_changes WHERE user_id = $1 AND status = $2`,
+		userID, model.LimitChangePending))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// CancelPendingLimitChange cancels the user's pending limit change. Returns the cancelled change, or nil if there was none.
+func (r *UserRepository) CancelPendingLimitChange(userID string) (*model.LimitChange, error) {
+	c, err := scanLimitChange(r.db.QueryRow(`UPDATE transfer_limit_changes SET status = $3, resolved_at = $4
+		WHERE user_id = $1 AND status = $2
+		RETURNING `+limitChangeColumns, userID, model.LimitChangePending, model.LimitChangeCancelled, time.Now()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// ApplyDueLimitChanges copies up to limit pending changes whose effective_at has passed into user_settings and marks
+// them applied, in one statement. Rows locked by a concurrent run are skipped, so several instances may sweep at once.
+func (r *UserRepository) ApplyDueLimitChanges(now time.Time, limit int) ([]model.LimitChange, error) {
+	query := `
+		WITH due AS (
+			UPDATE transfer_limit_changes SET status = $2, resolved_at = $1
+			WHERE id IN (
+				SELECT id FROM transfer_limit_changes
+				WHERE status = $3 AND effective_at <= $1
+				ORDER BY effective_at
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + limitChangeColumns + `
+		), settings AS (
+			UPDATE user_settings s SET
+				daily_transfer_limit = COALESCE(due.daily_transfer_limit, s.daily_transfer_limit),
+				monthly_transfer_limit = COALESCE(due.monthly_transfer_limit, s.monthly_transfer_limit),
+				updated_at = $1
+			FROM due WHERE s.user_id = due.user_id
+		)
+		SELECT ` + limitChangeColumns + ` FROM due`
+	rows, err := r.db.Query(query, now, model.LimitChangeApplied, model.LimitChangePending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.LimitChange
+	for rows.Next() {
+		c, err := scanLimitChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+func scanLimitChange(row sessionScanner) (*model.LimitChange, error) {
+	var c model.LimitChange
+	var daily, monthly sql.NullFloat64
+	var resolvedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.UserID, &daily, &monthly, &c.Status, &c.RequestedAt, &c.EffectiveAt, &resolvedAt); err != nil {
+		return nil, err
+	}
+	if daily.Valid {
+		c.DailyTransferLimit = &daily.Float64
+	}
+	if monthly.Valid {
+		c.MonthlyTransferLimit = &monthly.Float64
+	}
+	if resolvedAt.Valid {
+		c.ResolvedAt = &resolvedAt.Time
+	}
+	return &c, nil
+}
+
+func nullFloat(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
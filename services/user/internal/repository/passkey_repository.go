@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrPasskeyExists is returned when the credential ID is already registered (to this or another account).
+var ErrPasskeyExists = errors.New("passkey already registered")
+
+const passkeyColumns = `id, user_id, credential_id, public_key, algorithm, sign_count, aaguid, transports,
+	COALESCE(name, ''), backup_eligible, backed_up, created_at, last_used_at`
+
+// CreatePasskey stores a verified credential and turns on passkey payments (biometric_enabled) for the user.
+func (r *UserRepository) CreatePasskey(p *model.Passkey) (*model.Passkey, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	query := `INSERT INTO webauthn_credentials (user_id, credential_id, public_key, algorithm, sign_count, aaguid, transports,
+			name, backup_eligible, backed_up)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10)
+		RETURNING ` + passkeyColumns
+	created, err := scanPasskey(tx.QueryRow(query, p.UserID, p.CredentialID, p.PublicKey, p.Algorithm, int64(p.SignCount), p.AAGUID,
+		strings.Join(p.Transports, ","), p.Name, p.BackupEligible, p.BackedUp))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrPasskeyExists
+		}
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE user_settings SET biometric_enabled = true, updated_at = $2 WHERE user_id = $1`, p.UserID, time.Now()); err != nil {
+		return nil, err
+	}
+	return created, tx.Commit()
+}
+
+// ListPasskeys returns the user's passkeys, newest first.
+func (r *UserRepository) ListPasskeys(userID string) ([]model.Passkey, error) {
+	rows, err := r.db.Query(`SELECT `+passkeyColumns+` FROM webauthn_credentials WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.Passkey
+	for rows.Next() {
+		p, err := scanPasskey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *p)
+	}
+	return out, rows.Err()
+}
+
+// GetPasskeyByCredentialID returns the passkey with the WebAuthn credential ID, or nil if none is registered.
+func (r *UserRepository) GetPasskeyByCredentialID(credentialID []byte) (*model.Passkey, error) {
+	p, err := scanPasskey(r.db.QueryRow(`SELECT `+passkeyColumns+` FROM webauthn_credentials WHERE credential_id = $1`, credentialID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
+
+// RecordPasskeyUse stores the signature counter and backup state from a successful assertion. The counter only moves
+// forward, so two concurrent assertions cannot roll it back.
+func (r *UserRepository) RecordPasskeyUse(id string, signCount uint32, backedUp bool) error {
+	_, err := r.db.Exec(`UPDATE webauthn_credentials SET sign_count = GREATEST(sign_count, $2), backed_up = $3, last_used_at = $4
+		WHERE id = $1`, id, int64(signCount), backedUp, time.Now())
+	return err
+}
+
+// DeletePasskey removes the user's passkey. When it was the last one, passkey payments are turned off. Returns false
+// if the passkey does not exist or belongs to another user.
+func (r *UserRepository) DeletePasskey(userID, id string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	result, err := tx.Exec(`DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n != 1 {
+		return false, nil
+	}
+	if _, err := tx.Exec(`UPDATE user_settings SET biometric_enabled = false, updated_at = $2
+		WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM webauthn_credentials WHERE user_id = $1)`, userID, time.Now()); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func scanPasskey(row sessionScanner) (*model.Passkey, error) {
+	var p model.Passkey
+	var signCount int64
+	var transports string
+	if err := row.Scan(&p.ID, &p.UserID, &p.CredentialID, &p.PublicKey, &p.Algorithm, &signCount, &p.AAGUID, &transports,
+		&p.Name, &p.BackupEligible, &p.BackedUp, &p.CreatedAt, &p.LastUsedAt); err != nil {
+		return nil, err
+	}
+	p.SignCount = uint32(signCount)
+	if transports != "" {
+		p.Transports = strings.Split(transports, ",")
+	}
+	return &p, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+// UpsertPushToken registers token for the user. A token already registered (to anyone) is moved to this user, and
+// when deviceID is set, older tokens of the same device are dropped: the app got a new token after a reinstall.
+func (r *UserRepository) UpsertPushToken(userID, token, platform, deviceID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	now := time.Now()
+	if _, err := tx.Exec(`INSERT INTO push_tokens (user_id, token, platform, device_id, created_at, last_seen_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $5)
+		ON CONFLICT (token) DO UPDATE SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform,
+			device_id = EXCLUDED.device_id, last_seen_at = EXCLUDED.last_seen_at`,
+		userID, token, platform, deviceID, now); err != nil {
+		return err
+	}
+	if deviceID != "" {
+		if _, err := tx.Exec(`DELETE FROM push_tokens WHERE user_id = $1 AND device_id = $2 AND token <> $3`, userID, deviceID, token); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeletePushToken removes one of the user's tokens. Returns false if the user has no such token.
+func (r *UserRepository) DeletePushToken(userID, token string) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM push_tokens WHERE user_id = $1 AND token = $2`, userID, token)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeletePushTokensForDevice removes the tokens the user registered from deviceID (used when that session is revoked).
+func (r *UserRepository) DeletePushTokensForDevice(userID, deviceID string) error {
+	_, err := r.db.Exec(`DELETE FROM push_tokens WHERE user_id = $1 AND device_id = $2`, userID, deviceID)
+	return err
+}
+
+// DeletePushTokens removes the given tokens whoever they belong to and returns how many were removed.
+func (r *UserRepository) DeletePushTokens(tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	res, err := r.db.Exec(`DELETE FROM push_tokens WHERE token = ANY($1)`, tokens)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ListPushTokens returns the user's tokens, most recently seen first.
+func (r *UserRepository) ListPushTokens(userID string) ([]model.PushToken, error) {
+	rows, err := r.db.Query(`SELECT id, user_id, token, platform, COALESCE(device_id, ''), created_at, last_seen_at
+		FROM push_tokens WHERE user_id = $1 ORDER BY last_seen_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.PushToken
+	for rows.Next() {
+		var t model.PushToken
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Token, &t.Platform, &t.DeviceID, &t.CreatedAt, &t.LastSeenAt); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrReferralCodeTaken is returned when a generated referral code collides with another user's.
+var ErrReferralCodeTaken = errors.New("referral code already taken")
+
+const referralColumns = `id, referrer_id, referee_id, code, referee_phone_hash, status, COALESCE(flagged_reason, ''),
+	kyc_approved_at, first_transfer_at, created_at`
+
+const referralRewardColumns = `id, referral_id, rule, beneficiary, user_id, amount, status, attempts, COALESCE(last_error, ''),
+	COALESCE(transaction_ref, ''), created_at, paid_at`
+
+// GetReferralCode returns the user's referral code, or "" if none has been assigned yet.
+func (r *UserRepository) GetReferralCode(userID string) (string, error) {
+	var code sql.NullString
+	err := r.db.QueryRow(`SELECT referral_code FROM users WHERE id = $1`, userID).Scan(&code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return code.String, err
+}
+
+// AssignReferralCode sets the user's referral code unless one is already set, and returns the code the user ends up
+// with. Returns ErrReferralCodeTaken if code belongs to someone else.
+func (r *UserRepository) AssignReferralCode(userID, code string) (string, error) {
+	_, err := r.db.Exec(`UPDATE users SET referral_code = $2 WHERE id = $1 AND referral_code IS NULL`, userID, code)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return "", ErrReferralCodeTaken
+		}
+		return "", err
+	}
+	return r.GetReferralCode(userID)
+}
+
+// GetUserIDByReferralCode returns the id of the (not deleted) user owning code, or "" if there is none.
+func (r *UserRepository) GetUserIDByReferralCode(code string) (string, error) {
+	var id string
+	err := r.db.QueryRow(`SELECT id FROM users WHERE referral_code = $1 AND deleted_at IS NULL`, code).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return id, err
+}
+
+// CreateReferral attributes refereeID's registration to referrerID.
+func (r *UserRepository) CreateReferral(referrerID, refereeID, code, refereePhoneHash string) error {
+	_, err := r.db.Exec(`INSERT INTO referrals (referrer_id, referee_id, code, referee_phone_hash) VALUES ($1, $2, $3, $4)`,
+		referrerID, refereeID, code, refereePhoneHash)
+	return err
+}
+
+// MarkReferralMilestone records that the referee reached milestone (first time only) and returns their referral, or
+// nil if they were not referred.
+func (r *UserRepository) MarkReferralMilestone(refereeID, milestone string, at time.Time) (*model.Referral, error) {
+	var column string
+	switch milestone {
+	case model.ReferralMilestoneKYCApproved:
+		column = "kyc_approved_at"
+	case model.ReferralMilestoneFirstTransfer:
+		column = "first_transfer_at"
+	default:
+		return nil, fmt.Errorf("unknown referral milestone %q", milestone)
+	}
+	ref, err := scanReferral(r.db.QueryRow(`UPDATE referrals SET `+column+` = COALESCE(`+column+`, $2), updated_at = $2
+		WHERE referee_id = $1
+		RETURNING `+referralColumns, refereeID, at))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ref, err
+}
+
+// FlagReferral marks a referral as failing an anti-abuse check. Its rewards are not granted.
+func (r *UserRepository) FlagReferral(id, reason string) error {
+	_, err := r.db.Exec(`UPDATE referrals SET status = $2, flagged_reason = $3, updated_at = $4 WHERE id = $1`,
+		id, model.ReferralFlagged, reason, time.Now())
+	return err
+}
+
+// ReferralPhoneReused reports whether the referee's phone number was used by the referrer or by an earlier referral
+// (e.g. a deleted account registered again through another code).
+func (r *UserRepository) ReferralPhoneReused(ref *model.Referral) (bool, error) {
+	var reused bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM referrals WHERE referee_phone_hash = $1 AND id <> $2 AND created_at <= $3)
+		OR EXISTS (SELECT 1 FROM users WHERE id = $4 AND phone_number_hash = $1)`,
+		ref.RefereePhoneHash, ref.ID, ref.CreatedAt, ref.ReferrerID).Scan(&reused)
+	return reused, err
+}
+
+// ReferralDeviceShared reports whether the referee has signed in on a device the referrer has also signed in on, and
+// whether on one another user referred by the same referrer has signed in on.
+func (r *UserRepository) ReferralDeviceShared(ref *model.Referral) (withReferrer, withOtherReferee bool, err error) {
+	err = r.db.QueryRow(`SELECT
+		EXISTS (SELECT 1 FROM user_sessions a JOIN user_sessions b ON b.device_id = a.device_id
+			WHERE a.user_id = $1 AND b.user_id = $2),
+		EXISTS (SELECT 1 FROM user_sessions a
+			JOIN user_sessions b ON b.device_id = a.device_id AND b.user_id <> a.user_id
+			JOIN referrals o ON o.referee_id = b.user_id
+			WHERE a.user_id = $1 AND o.referrer_id = $2 AND o.id <> $3)`,
+		ref.RefereeID, ref.ReferrerID, ref.ID).Scan(&withReferrer, &withOtherReferee)
+	return withReferrer, withOtherReferee, err
+}
+
+// CountRewardedReferrals returns how many of the referrer's referrals have been granted rewards.
+func (r *UserRepository) CountRewardedReferrals(referrerID string) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM referrals WHERE referrer_id = $1 AND status = $2`,
+		referrerID, model.ReferralRewarded).Scan(&n)
+	return n, err
+}
+
+// GrantReferralRewards queues rewards for payout and marks the referral rewarded, in one transaction. A reward already
+// granted under the same rule to the same side is skipped, so replayed events pay nothing twice. Returns how many were
+// new.
+func (r *UserRepository) GrantReferralRewards(referralID string, rewards []model.ReferralReward) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	now := time.Now()
+	created := 0
+	for _, rw := range rewards {
+		res, err := tx.Exec(`INSERT INTO referral_rewards (referral_id, rule, beneficiary, user_id, amount, status, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+			ON CONFLICT (referral_id, rule, beneficiary) DO NOTHING`,
+			referralID, rw.Rule, rw.Beneficiary, rw.UserID, rw.Amount, model.ReferralRewardPending, now)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			created++
+		}
+	}
+	if _, err := tx.Exec(`UPDATE referrals SET status = $2, updated_at = $3 WHERE id = $1 AND status = $4`,
+		referralID, model.ReferralRewarded, now, model.ReferralPending); err != nil {
+		return 0, err
+	}
+	return created, tx.Commit()
+}
+
+// ClaimDueReferralRewards returns up to limit pending rewards due for a payout attempt and pushes their next attempt
+// out by lease, so a second instance of the sweep skips them and a crashed run is retried later.
+func (r *UserRepository) ClaimDueReferralRewards(now time.Time, lease time.Duration, limit int) ([]model.ReferralReward, error) {
+	query := `UPDATE referral_rewards SET next_attempt_at = $2, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM referral_rewards
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + referralRewardColumns
+	rows, err := r.db.Query(query, now, now.Add(lease), model.ReferralRewardPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.ReferralReward
+	for rows.Next() {
+		rw, err := scanReferralReward(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rw)
+	}
+	return out, rows.Err()
+}
+
+// MarkReferralRewardPaid records the wallet credit that paid a reward.
+func (r *UserRepository) MarkReferralRewardPaid(id, transactionRef string, now time.Time) error {
+	_, err := r.db.Exec(`UPDATE referral_rewards SET status = $2, transaction_ref = $3, paid_at = $4, last_error = NULL
+		WHERE id = $1 AND status = $5`,
+		id, model.ReferralRewardPaid, transactionRef, now, model.ReferralRewardPending)
+	return err
+}
+
+// RetryReferralReward records why a payout failed and when it should be tried again.
+func (r *UserRepository) RetryReferralReward(id, lastError string, retryAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE referral_rewards SET last_error = $2, next_attempt_at = $3 WHERE id = $1 AND status = $4`,
+		id, lastError, retryAt, model.ReferralRewardPending)
+	return err
+}
+
+// FailReferralReward gives up on a reward after its last attempt.
+func (r *UserRepository) FailReferralReward(id, lastError string) error {
+	_, err := r.db.Exec(`UPDATE referral_rewards SET status = $2, last_error = $3 WHERE id = $1 AND status = $4`,
+		id, model.ReferralRewardFailed, lastError, model.ReferralRewardPending)
+	return err
+}
+
+// ListReferralsByReferrer returns the users the referrer brought in, newest first, with what each has earned the
+// referrer so far (paid rewards only).
+func (r *UserRepository) ListReferralsByReferrer(referrerID string, limit int) ([]model.Referral, []float64, error) {
+	rows, err := r.db.Query(`SELECT `+referralColumns+`,
+			(SELECT COALESCE(SUM(amount), 0) FROM referral_rewards w
+			 WHERE w.referral_id = referrals.id AND w.beneficiary = $2 AND w.status = $3)
+		FROM referrals WHERE referrer_id = $1 ORDER BY created_at DESC LIMIT $4`,
+		referrerID, model.ReferralBeneficiaryReferrer, model.ReferralRewardPaid, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var refs []model.Referral
+	var earned []float64
+	for rows.Next() {
+		var ref model.Referral
+		var flaggedReason string
+		var kycAt, transferAt sql.NullTime
+		var amount float64
+		if err := rows.Scan(&ref.ID, &ref.ReferrerID, &ref.RefereeID, &ref.Code, &ref.RefereePhoneHash, &ref.Status,
+			&flaggedReason, &kycAt, &transferAt, &ref.CreatedAt, &amount); err != nil {
+			return nil, nil, err
+		}
+		ref.FlaggedReason = flaggedReason
+		if kycAt.Valid {
+			ref.KYCApprovedAt = &kycAt.Time
+		}
+		if transferAt.Valid {
+			ref.FirstTransferAt = &transferAt.Time
+		}
+		refs = append(refs, ref)
+		earned = append(earned, amount)
+	}
+	return refs, earned, rows.Err()
+}
+
+// SumReferralRewardsPaid returns the total the user has been paid in referral rewards, as referrer or referee.
+func (r *UserRepository) SumReferralRewardsPaid(userID string) (float64, error) {
+	var total float64
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM referral_rewards WHERE user_id = $1 AND status = $2`,
+		userID, model.ReferralRewardPaid).Scan(&total)
+	return total, err
+}
+
+func scanReferral(row sessionScanner) (*model.Referral, error) {
+	var ref model.Referral
+	var kycAt, transferAt sql.NullTime
+	if err := row.Scan(&ref.ID, &ref.ReferrerID, &ref.RefereeID, &ref.Code, &ref.RefereePhoneHash, &ref.Status,
+		&ref.FlaggedReason, &kycAt, &transferAt, &ref.CreatedAt); err != nil {
+		return nil, err
+	}
+	if kycAt.Valid {
+		ref.KYCApprovedAt = &kycAt.Time
+	}
+	if transferAt.Valid {
+		ref.FirstTransferAt = &transferAt.Time
+	}
+	return &ref, nil
+}
+
+func scanReferralReward(row sessionScanner) (*model.ReferralReward, error) {
+	var rw model.ReferralReward
+	var paidAt sql.NullTime
+	if err := row.Scan(&rw.ID, &rw.ReferralID, &rw.Rule, &rw.Beneficiary, &rw.UserID, &rw.Amount, &rw.Status,
+		&rw.Attempts, &rw.LastError, &rw.TransactionRef, &rw.CreatedAt, &paidAt); err != nil {
+		return nil, err
+	}
+	if paidAt.Valid {
+		rw.PaidAt = &paidAt.Time
+	}
+	return &rw, nil
+}
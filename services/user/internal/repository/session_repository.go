@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+const sessionColumns = `id, user_id, device_id, COALESCE(device_name, ''), COALESCE(platform, ''), COALESCE(app_version, ''),
+	COALESCE(ip_address, ''), COALESCE(user_agent, ''), created_at, last_seen_at, revoked_at, COALESCE(revoked_reason, '')`
+
+// CreateSession records a new login on a device. The returned session ID is the family for its refresh tokens.
+func (r *UserRepository) CreateSession(userID string, d model.DeviceInfo) (*model.Session, error) {
+	query := `INSERT INTO user_sessions (user_id, device_id, device_name, platform, app_version, ip_address, user_agent)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''))
+		RETURNING ` + sessionColumns
+	return scanSession(r.db.QueryRow(query, userID, d.DeviceID, d.DeviceName, d.Platform, d.AppVersion, d.IPAddress, d.UserAgent))
+}
+
+// DeviceHistory reports whether the user has logged in before at all, and whether they have logged in from deviceID.
+func (r *UserRepository) DeviceHistory(userID, deviceID string) (anySession, knownDevice bool, err error) {
+	query := `SELECT COUNT(*) > 0, COALESCE(BOOL_OR(device_id = $2), false) FROM user_sessions WHERE user_id = $1`
+	err = r.db.QueryRow(query, userID, deviceID).Scan(&anySession, &knownDevice)
+	return anySession, knownDevice, err
+}
+
+// GetSession returns the user's session by ID, or nil if it does not exist or belongs to another user.
+func (r *UserRepository) GetSession(userID, sessionID string) (*model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE id = $1 AND user_id = $2`
+	s, err := scanSession(r.db.QueryRow(query, sessionID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// ListActiveSessions returns sessions that are not revoked and still hold a live refresh token, most recent first.
+func (r *UserRepository) ListActiveSessions(userID string) ([]model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions s
+		WHERE s.user_id = $1 AND s.revoked_at IS NULL
+		  AND EXISTS (SELECT 1 FROM refresh_tokens t WHERE t.family_id = s.id AND t.revoked = false AND t.expires_at > now())
+		ORDER BY s.last_seen_at DESC`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *s)
+	}
+	return out, rows.Err()
+}
+
+// TouchSession records activity on a session (token refresh) from the given IP and app version.
+func (r *UserRepository) TouchSession(sessionID, ipAddress, appVersion string) error {
+	query := `UPDATE user_sessions SET last_seen_at = $2, ip_address = COALESCE(NULLIF($3, ''), ip_address),
+		app_version = COALESCE(NULLIF($4, ''), app_version) WHERE id = $1`
+	_, err := r.db.Exec(query, sessionID, time.Now(), ipAddress, appVersion)
+	return err
+}
+
+// revokeTokensAndSessions revokes live refresh tokens where tokenColumn = key and marks the matching sessions
+// (sessionColumn = key) revoked, in one transaction. Returns the number of refresh tokens revoked.
+func (r *UserRepository) revokeTokensAndSessions(tokenColumn, sessionColumn, key, reason string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	now := time.Now()
+	result, err := tx.Exec(`UPDATE refresh_tokens SET revoked = true, revoked_at = $2, revoked_reason = $3
+		WHERE `+tokenColumn+` = $1 AND revoked = false`, key, now, reason)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	if _, err := tx.Exec(`UPDATE user_sessions SET revoked_at = $2, revoked_reason = $3
+		WHERE `+sessionColumn+` = $1 AND revoked_at IS NULL`, key, now, reason); err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+type sessionScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSession(row sessionScanner) (*model.Session, error) {
+	var s model.Session
+	var revokedAt sql.NullTime
+	err := row.Scan(&s.ID, &s.UserID, &s.Device.DeviceID, &s.Device.DeviceName, &s.Device.Platform, &s.Device.AppVersion,
+		&s.Device.IPAddress, &s.Device.UserAgent, &s.CreatedAt, &s.LastSeenAt, &revokedAt, &s.RevokedReason)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.Time
+	}
+	return &s, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+)
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and stores the new set (SHA-256 hex of each code).
+func (r *UserRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, h := range codeHashes {
+		if _, err := tx.Exec(`INSERT INTO two_factor_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`,
+			userID, h, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ConsumeRecoveryCode marks an unused code as used. Returns false if the code does not exist or was already used;
+// the conditional update makes concurrent use of the same code succeed only once.
+func (r *UserRepository) ConsumeRecoveryCode(userID, codeHash string) (bool, error) {
+	result, err := r.db.Exec(`UPDATE two_factor_recovery_codes SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, codeHash, time.Now())
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n == 1, nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes the user has left.
+func (r *UserRepository) CountUnusedRecoveryCodes(userID string) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM two_factor_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&n)
+	return n, err
+}
+
+// DeleteRecoveryCodes removes all recovery codes of the user (2FA disabled or reset).
+func (r *UserRepository) DeleteRecoveryCodes(userID string) error {
+	_, err := r.db.Exec(`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID)
+	return err
+}
+
+// SetTwoFactorFallbackChannel stores the preferred channel for fallback login codes; nil clears it.
+func (r *UserRepository) SetTwoFactorFallbackChannel(userID string, channel *string) error {
+	_, err := r.db.Exec(`UPDATE user_settings SET two_factor_fallback_channel = $2, updated_at = $3 WHERE user_id = $1`,
+		userID, channel, time.Now())
+	return err
+}
@@ -11,8 +11,9 @@ import (
 
 // TokenGenerator produces access and refresh tokens. Implement in service and inject into NewUserRepository.
 type TokenGenerator interface {
-	GenerateAccessToken(userID, email string) (token string, expiresAt time.Time, err error)
-	GenerateAndStoreRefreshToken(userID string, inserter RefreshTokenInserter) (token string, expiresAt time.Time, err error)
+	GenerateAccessToken(userID, email, sessionID string) (token string, expiresAt time.Time, err error)
+	// GenerateAndStoreRefreshToken issues a refresh token in familyID, or in a new family when familyID is empty.
+	GenerateAndStoreRefreshToken(userID, familyID string, inserter RefreshTokenInserter) (token string, expiresAt time.Time, err error)
 }
 
 // RefreshTokenInserter persists a refresh token. *UserRepository implements this.
@@ -20,6 +21,22 @@ type RefreshTokenInserter interface {
 	CreateRefreshToken(token model.RefreshToken) error
 }
 
+// Refresh token revocation reasons (refresh_tokens.revoked_reason).
+const (
+	RevokeReasonRotated         = "rotated"
+	RevokeReasonLogout          = "logout"
+	RevokeReasonLogoutAll       = "logout_all"
+	RevokeReasonSessionRevoked  = "session_revoked"
+	RevokeReasonAdminRevoked    = "admin_revoked"
+	RevokeReasonReuseDetected   = "reuse_detected"
+	RevokeReasonPasswordChanged = "password_changed"
+	RevokeReasonPasswordReset   = "password_reset"
+	RevokeReasonUserRestricted  = "user_restricted"
+	RevokeReason2FAReset        = "2fa_reset"
+	RevokeReasonEmailChanged    = "email_changed"
+	RevokeReasonAccountDeleted  = "account_deleted"
+)
+
 var ErrInvalidCredentials = errors.New("invalid email or password")
 var ErrUserNotFound = errors.New("user not found")
 
@@ -70,24 +87,27 @@ func (r *UserRepository) GetUserSettings(userID string) (*model.UserSettings, er
 		SELECT user_id, pin_hash, biometric_enabled, two_factor_enabled,
 		       totp_secret, totp_secret_pending, totp_pending_created_at,
 		       daily_transfer_limit, monthly_transfer_limit, transaction_alerts_enabled,
-		transfers_disabled, language, theme, created_at, updated_at
+		transfers_disabled, language, theme, two_factor_fallback_channel, created_at, updated_at
 		FROM user_settings WHERE user_id = $1
 	`
 	row := r.db.QueryRow(query, userID)
 	var s model.UserSettings
-	var pinHash, language, theme, totpSecret, totpPending sql.NullString
+	var pinHash, language, theme, totpSecret, totpPending, fallbackChannel sql.NullString
 	var totpPendingAt sql.NullTime
 	var dailyLimit, monthlyLimit sql.NullFloat64
 	err := row.Scan(&s.UserID, &pinHash, &s.BiometricEnabled, &s.TwoFactorEnabled,
 		&totpSecret, &totpPending, &totpPendingAt,
 		&dailyLimit, &monthlyLimit, &s.TransactionAlertsEnabled,
-		&s.TransfersDisabled, &language, &theme, &s.CreatedAt, &s.UpdatedAt)
+		&s.TransfersDisabled, &language, &theme, &fallbackChannel, &s.CreatedAt, &s.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	if fallbackChannel.Valid {
+		s.TwoFactorFallbackChannel = &fallbackChannel.String
+	}
 	if pinHash.Valid {
 		s.PinHash = &pinHash.String
 	}
@@ -212,9 +232,15 @@ func (r *UserRepository) DisableTotp(userID string) error {
 	return nil
 }
 
+// Login loads the account by email, or by phone number hash when no email is given.
 func (r *UserRepository) Login(loginRequest model.LoginRequest) (*model.User, error) {
 	query := `SELECT id, email, first_name, last_name, phone_number, phone_number_hash, password_hash, email_verified, COALESCE(banking_restricted, false), created_at, updated_at FROM users WHERE email = $1`
-	row := r.db.QueryRow(query, loginRequest.Email)
+	key := loginRequest.Email
+	if key == "" {
+		query = `SELECT id, email, first_name, last_name, phone_number, phone_number_hash, password_hash, email_verified, COALESCE(banking_restricted, false), created_at, updated_at FROM users WHERE phone_number_hash = $1`
+		key = loginRequest.PhoneNumberHash
+	}
+	row := r.db.QueryRow(query, key)
 	var user model.User
 	err := row.Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.PhoneNumber, &user.PhoneNumberHash, &user.PasswordHash, &user.EmailVerified, &user.BankingRestricted, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
@@ -245,11 +271,12 @@ func (r *UserRepository) CreatePasswordResetToken(token model.PasswordResetToken
 }
 
 func (r *UserRepository) CreateRefreshToken(token model.RefreshToken) error {
-	query := `INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-	VALUES ($1, $2, $3)
+	query := `INSERT INTO refresh_tokens (user_id, token_hash, expires_at, family_id)
+	VALUES ($1, $2, $3, COALESCE($4::uuid, gen_random_uuid()))
 	`
 	tokenHashHex := hex.EncodeToString(token.TokenHash[:])
-	_, err := r.db.Exec(query, token.UserID, tokenHashHex, token.ExpiresAt)
+	familyID := sql.NullString{String: token.FamilyID, Valid: token.FamilyID != ""}
+	_, err := r.db.Exec(query, token.UserID, tokenHashHex, token.ExpiresAt, familyID)
 	return err
 }
 
@@ -303,10 +330,11 @@ func (r *UserRepository) GetUserByID(id string) (*model.User, error) {
 	return &user, nil
 }
 
-// ExistsByID returns true if a user with the given ID exists. Used for auth validate (with Redis cache).
+// ExistsByID returns true if a user with the given ID exists and has not been deleted. Used for auth validate (with
+// Redis cache).
 func (r *UserRepository) ExistsByID(id string) (bool, error) {
 	var n int
-	err := r.db.QueryRow(`SELECT 1 FROM users WHERE id = $1 LIMIT 1`).Scan(&n)
+	err := r.db.QueryRow(`SELECT 1 FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1`, id).Scan(&n)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -399,12 +427,50 @@ func (r *UserRepository) GetPasswordResetToken(tokenHashHex string) (*model.Pass
 	return &token, nil
 }
 
-func (r *UserRepository) GetRefreshToken(tokenHash string) (*model.RefreshToken, error) {
-	query := `SELECT id, user_id, token_hash, expires_at, revoked, created_at FROM refresh_tokens WHERE token_hash = $1`
-	row := r.db.QueryRow(query, tokenHash)
+// GetRefreshToken returns the refresh token with the given hash (hex), or nil if not found.
+func (r *UserRepository) GetRefreshToken(tokenHashHex string) (*model.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, revoked, COALESCE(revoked_reason, ''), created_at
+		FROM refresh_tokens WHERE token_hash = $1`
+	row := r.db.QueryRow(query, tokenHashHex)
 	var token model.RefreshToken
-	err := row.Scan(&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.Revoked, &token.CreatedAt)
-	return &token, err
+	var tokenHashStr string
+	err := row.Scan(&token.ID, &token.UserID, &token.FamilyID, &tokenHashStr, &token.ExpiresAt, &token.Revoked, &token.RevokedReason, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	decoded, err := hex.DecodeString(tokenHashStr)
+	if err != nil || len(decoded) != 32 {
+		return nil, errors.New("invalid token hash")
+	}
+	copy(token.TokenHash[:], decoded)
+	return &token, nil
+}
+
+// ConsumeRefreshToken revokes a live refresh token as rotated. Returns false if it was already revoked, so two
+// concurrent refreshes with the same token cannot both succeed.
+func (r *UserRepository) ConsumeRefreshToken(tokenID string) (bool, error) {
+	query := `UPDATE refresh_tokens SET revoked = true, revoked_at = $2, revoked_reason = $3 WHERE id = $1 AND revoked = false`
+	result, err := r.db.Exec(query, tokenID, time.Now(), RevokeReasonRotated)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n == 1, nil
+}
+
+// RevokeRefreshTokenFamily revokes every live token in the family and the session it belongs to.
+// Returns the number of tokens revoked.
+func (r *UserRepository) RevokeRefreshTokenFamily(familyID, reason string) (int64, error) {
+	return r.revokeTokensAndSessions("family_id", "id", familyID, reason)
+}
+
+// RevokeAllRefreshTokens revokes every live refresh token and session of the user (all devices). Returns the number
+// of tokens revoked.
+func (r *UserRepository) RevokeAllRefreshTokens(userID, reason string) (int64, error) {
+	return r.revokeTokensAndSessions("user_id", "user_id", userID, reason)
 }
 
 func (r *UserRepository) UpdateUser(user model.User) error {
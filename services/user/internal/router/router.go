@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/abubakvr/payup-backend/services/user/internal/config"
+	"github.com/abubakvr/payup-backend/pkg/authn"
 	"github.com/abubakvr/payup-backend/services/user/internal/controller"
+	"github.com/abubakvr/payup-backend/services/user/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,35 +26,87 @@ func AdminKeyAuth(adminKey string) gin.HandlerFunc {
 	}
 }
 
-func SetupRouter(cfg *config.Config, ctrl *controller.UserController) *gin.Engine {
+func SetupRouter(cfg *config.Config, ctrl *controller.UserController, authenticator *authn.Authenticator) *gin.Engine {
 	router := gin.Default()
+	// Routes on this group need a valid, non-revoked access token.
+	protected := router.Group("", middleware.AuthMiddleware(authenticator))
 
 	router.GET("/health", func(c *gin.Context) {
 		c.String(http.StatusOK, "User Service is healthy")
 	})
 
 	router.GET("/auth/validate", ctrl.AuthValidate)
+	// Public signing keys; payment, KYC and the gateway verify user tokens against these.
+	router.GET("/.well-known/jwks.json", ctrl.JWKS)
 	router.POST("/register", ctrl.RegisterUser)
 	router.POST("/login", ctrl.Login)
+	// Refresh rotates the refresh token; logout revokes it with every token rotated from the same login.
+	router.POST("/auth/refresh", ctrl.RefreshToken)
+	router.POST("/auth/logout", ctrl.Logout)
+
+	// Sessions: devices the user is logged in on (JWT required).
+	protected.GET("/sessions", ctrl.ListSessions)
+	protected.DELETE("/sessions/:id", ctrl.RevokeSession)
+	protected.POST("/sessions/logout-all", ctrl.LogOutEverywhere)
 	router.POST("/verify-email", ctrl.VerifyEmail)
 	router.POST("/resend-verification", ctrl.ResendVerification)
 	router.POST("/forgot-password", ctrl.ForgotPassword)
 	router.POST("/reset-password", ctrl.ResetPassword)
-	router.POST("/change-password", ctrl.ChangePassword)
+	protected.POST("/change-password", ctrl.ChangePassword)
+	// Email and phone number changes: request sends a code to the new address, confirm applies the change.
+	protected.POST("/account/email", ctrl.RequestEmailChange)
+	protected.POST("/account/email/confirm", ctrl.ConfirmEmailChange)
+	protected.POST("/account/phone", ctrl.RequestPhoneChange)
+	protected.POST("/account/phone/confirm", ctrl.ConfirmPhoneChange)
+
+	// Privacy (NDPR): data export and account deletion. Deletion runs after a grace period and can be cancelled until then.
+	protected.POST("/privacy/export", ctrl.RequestDataExport)
+	protected.GET("/privacy/requests", ctrl.ListDataSubjectRequests)
+	protected.GET("/privacy/exports/:id/download", ctrl.DownloadDataExport)
+	protected.POST("/privacy/deletion", ctrl.RequestAccountDeletion)
+	protected.DELETE("/privacy/deletion", ctrl.CancelAccountDeletion)
+
+	// Push notifications: device tokens (FCM for android, APNs for ios) the notification service fans alerts out to.
+	protected.POST("/push-tokens", ctrl.RegisterPushToken)
+	protected.GET("/push-tokens", ctrl.ListPushTokens)
+	protected.DELETE("/push-tokens", ctrl.DeletePushToken)
+
+	// Referrals: the user's invite code (created on first request) and the rewards their referrals have earned.
+	protected.GET("/referrals", ctrl.GetReferrals)
 
 	// User settings: GET (read), PATCH (partial update), and dedicated routes for pin, limits, pause/resume.
-	router.GET("/settings", ctrl.GetSettings)
-	router.PATCH("/settings", ctrl.UpdateSettings)
-	router.PUT("/settings/pin", ctrl.SetPin)
-	router.PUT("/settings/limits", ctrl.SetLimits)
-	router.POST("/settings/pause-account", ctrl.PauseAccount)
-	router.POST("/settings/resume-account", ctrl.ResumeAccount)
+	protected.GET("/settings", ctrl.GetSettings)
+	protected.PATCH("/settings", ctrl.UpdateSettings)
+	protected.PUT("/settings/pin", ctrl.SetPin)
+	protected.PUT("/settings/limits", ctrl.SetLimits)
+	protected.DELETE("/settings/limits/pending", ctrl.CancelLimitIncrease)
+	protected.POST("/settings/pause-account", ctrl.PauseAccount)
+	protected.POST("/settings/resume-account", ctrl.ResumeAccount)
 
 	// Two-factor auth (TOTP): setup and verify-setup require JWT; verify-login is public (uses token from login response).
-	router.POST("/2fa/setup", ctrl.Setup2FA)
-	router.POST("/2fa/verify-setup", ctrl.VerifySetup2FA)
+	protected.POST("/2fa/setup", ctrl.Setup2FA)
+	protected.POST("/2fa/verify-setup", ctrl.VerifySetup2FA)
 	router.POST("/2fa/verify-login", ctrl.VerifyLogin2FA)
-	router.POST("/2fa/disable", ctrl.Disable2FA)
+	protected.POST("/2fa/disable", ctrl.Disable2FA)
+	// Recovery: one-time recovery codes and a fallback code by SMS/WhatsApp/email (send-otp is public, like verify-login).
+	router.POST("/2fa/send-otp", ctrl.Send2FAOTP)
+	protected.GET("/2fa/status", ctrl.Get2FAStatus)
+	protected.POST("/2fa/recovery-codes", ctrl.RegenerateRecoveryCodes)
+	protected.PUT("/2fa/fallback", ctrl.Set2FAFallback)
+
+	// Step-up: re-confirm password and a code for a short-lived X-Step-Up-Token (sensitive settings, large transfers).
+	protected.POST("/auth/step-up", ctrl.StepUp)
+	protected.POST("/auth/step-up/send-otp", ctrl.SendStepUpOTP)
+
+	// Passkeys (WebAuthn): registration and management need JWT; login is public; transaction/begin issues the
+	// challenge a payment is approved with instead of the PIN.
+	protected.POST("/passkeys/register/begin", ctrl.BeginPasskeyRegistration)
+	protected.POST("/passkeys/register/finish", ctrl.FinishPasskeyRegistration)
+	protected.GET("/passkeys", ctrl.ListPasskeys)
+	protected.DELETE("/passkeys/:id", ctrl.DeletePasskey)
+	router.POST("/passkeys/login/begin", ctrl.BeginPasskeyLogin)
+	router.POST("/passkeys/login/finish", ctrl.FinishPasskeyLogin)
+	protected.POST("/passkeys/transaction/begin", ctrl.BeginPasskeyTransaction)
 
 	// Admin (X-Admin-Key required)
 	admin := router.Group("/admin", AdminKeyAuth(cfg.AdminAPIKey))
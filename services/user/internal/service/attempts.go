@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/pkg/attempts"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// attemptLimiters are the brute-force limits on secrets the user service verifies. Per-IP limits are looser than
+// per-user ones so a shared NAT does not lock out a whole office.
+type attemptLimiters struct {
+	loginUser *attempts.Limiter
+	loginIP   *attempts.Limiter
+	twoFactor *attempts.Limiter
+	otpSend   *attempts.Limiter
+	stepUp    *attempts.Limiter
+	pin       *attempts.Limiter
+	contact   *attempts.Limiter
+}
+
+func newAttemptLimiters(rdb goredis.UniversalClient) attemptLimiters {
+	return attemptLimiters{
+		loginUser: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "login:user", MaxFailures: 5, Window: 15 * time.Minute,
+			Lockouts: []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
+		}),
+		loginIP: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "login:ip", MaxFailures: 50, Window: 15 * time.Minute,
+			Lockouts: []time.Duration{15 * time.Minute, time.Hour},
+		}),
+		twoFactor: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "2fa:user", MaxFailures: 5, Window: 10 * time.Minute,
+			Lockouts: []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
+		}),
+		// Every fallback login code sent counts: each one costs an SMS/WhatsApp message and is a new guessing target.
+		otpSend: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "2fa:otp_send", MaxFailures: 3, Window: 15 * time.Minute,
+			Lockouts: []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
+		}),
+		// Step-up re-checks the password and a code; failures of either count.
+		stepUp: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "stepup:user", MaxFailures: 5, Window: 15 * time.Minute,
+			Lockouts: []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
+		}),
+		// A 4-digit PIN has 10,000 values: 3 tries per lockout and escalating locks keep guessing impractical.
+		pin: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "pin:user", MaxFailures: 3, Window: time.Hour,
+			Lockouts: []time.Duration{30 * time.Minute, 4 * time.Hour, 24 * time.Hour},
+		}),
+		// Codes confirming a new email or phone number; a lockout also drops the pending change.
+		contact: attempts.NewLimiter(rdb, attempts.Policy{
+			Name: "contact:user", MaxFailures: 5, Window: 15 * time.Minute,
+			Lockouts: []time.Duration{15 * time.Minute, time.Hour, 24 * time.Hour},
+		}),
+	}
+}
+
+// loginIdentity is how a login names the account: by email, or by phone number when Email is empty.
+type loginIdentity struct {
+	Email       string
+	PhoneNumber string
+}
+
+// subject is the per-user login key. Unknown emails and numbers are limited the same as known ones; a number is keyed
+// by its hash so the limiter never stores it.
+func (id loginIdentity) subject() string {
+	if id.Email == "" {
+		return phoneLoginSubject(hashPhone(id.PhoneNumber))
+	}
+	return loginSubject(id.Email)
+}
+
+// auditMetadata names the account in login audit events, with the failure reason if any. Phone numbers are masked.
+func (id loginIdentity) auditMetadata(reason string) map[string]interface{} {
+	m := map[string]interface{}{}
+	if reason != "" {
+		m["reason"] = reason
+	}
+	if id.Email == "" {
+		m["phone_number"] = maskPhone(id.PhoneNumber)
+	} else {
+		m["email"] = id.Email
+	}
+	return m
+}
+
+// loginSubject is the per-user login key for email logins.
+func loginSubject(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// phoneLoginSubject is the per-user login key for phone logins.
+func phoneLoginSubject(phoneHash string) string {
+	return "phone:" + phoneHash
+}
+
+// checkLogin returns a lockout error if the account named by id or the client IP is locked out.
+func (s *UserService) checkLogin(ctx context.Context, id loginIdentity, ip string) error {
+	if err := s.limits.loginIP.Check(ctx, ip); err != nil {
+		return err
+	}
+	return s.limits.loginUser.Check(ctx, id.subject())
+}
+
+// failLogin records a wrong password for the account and IP. Returns the lockout error when this attempt locked either.
+func (s *UserService) failLogin(ctx context.Context, id loginIdentity, ip string, userID *string) error {
+	ipErr := s.limits.loginIP.Fail(ctx, ip)
+	userErr := s.limits.loginUser.Fail(ctx, id.subject())
+	if le, ok := attempts.IsLocked(userErr); ok {
+		entityID := ""
+		if userID != nil {
+			entityID = *userID
+		}
+		metadata := id.auditMetadata("")
+		metadata["ip"], metadata["scope"], metadata["locked_seconds"] = ip, "user", int(le.RetryAfter.Seconds())
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "login_locked",
+			Entity:   "user",
+			EntityID: entityID,
+			UserID:   userID,
+			Metadata: metadata,
+		})
+		return userErr
+	}
+	if le, ok := attempts.IsLocked(ipErr); ok {
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "login_locked",
+			Entity:   "ip",
+			Metadata: map[string]interface{}{"ip": ip, "scope": "ip", "locked_seconds": int(le.RetryAfter.Seconds())},
+		})
+		return ipErr
+	}
+	return nil
+}
+
+// UnlockUser lifts login, 2FA, login-code, step-up and PIN lockouts for the user and forgets earlier ones (admin action).
+// Returns whether any lock was active.
+func (s *UserService) UnlockUser(ctx context.Context, userID string) (bool, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, repository.ErrUserNotFound
+	}
+	wasLocked := false
+	for _, u := range []struct {
+		l       *attempts.Limiter
+		subject string
+	}{
+		{s.limits.loginUser, loginSubject(user.Email)},
+		{s.limits.loginUser, phoneLoginSubject(user.PhoneNumberHash)},
+		{s.limits.twoFactor, user.ID},
+		{s.limits.otpSend, user.ID},
+		{s.limits.stepUp, user.ID},
+		{s.limits.pin, user.ID},
+		{s.limits.contact, user.ID},
+	} {
+		was, err := u.l.Unlock(ctx, u.subject)
+		if err != nil {
+			return false, err
+		}
+		wasLocked = wasLocked || was
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "user_unlocked",
+		Entity:   "user",
+		EntityID: user.ID,
+		UserID:   &user.ID,
+		Metadata: map[string]interface{}{"was_locked": wasLocked},
+	})
+	return wasLocked, nil
+}
+
+// pinLockedMessage is the ValidateTransfer reason while the PIN is locked. It starts with "PIN locked" so callers can
+// tell it apart from "invalid PIN".
+func pinLockedMessage(err error) string {
+	if le, ok := attempts.IsLocked(err); ok {
+		return "PIN locked: " + le.Error()
+	}
+	return "PIN locked"
+}
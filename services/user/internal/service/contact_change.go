@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/pkg/attempts"
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+)
+
+const contactChangeCodeTTL = 15 * time.Minute
+
+var (
+	ErrContactUnchanged  = errors.New("this is already your current email or phone number")
+	ErrEmailInUse        = errors.New("email is already used by another account")
+	ErrPhoneInUse        = errors.New("phone number is already used by another account")
+	ErrNoContactChange   = errors.New("no pending change or the code has expired; request a new code")
+	ErrInvalidChangeCode = errors.New("invalid code")
+)
+
+// ContactCooldownError refuses a change made too soon after the last one of the same kind. A stolen session cannot
+// swap the email and then the phone number back and forth to lock the owner out of recovery.
+type ContactCooldownError struct {
+	Kind  string
+	Until time.Time
+}
+
+func (e *ContactCooldownError) Error() string {
+	return fmt.Sprintf("your %s was changed recently; you can change it again after %s", contactNoun(e.Kind), e.Until.UTC().Format("2 Jan 2006 15:04 MST"))
+}
+
+// hashPhone is the users.phone_number_hash of a number: SHA-256 hex of its E.164 form, so 0803..., 234803... and
+// +234 803... are the same account. Migration 0022 rehashed the numbers stored before this.
+func hashPhone(phone string) string {
+	h := sha256.Sum256([]byte(normalizePhone(phone)))
+	return hex.EncodeToString(h[:])
+}
+
+// normalizePhone returns phone in E.164 (+2348031234567): separators dropped and a Nigerian national number (11
+// digits with a leading 0) given the 234 country code. Keep in step with the backfill in migration 0022.
+func normalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) == 11 && digits[0] == '0' {
+		digits = "234" + digits[1:]
+	}
+	return "+" + digits
+}
+
+func contactNoun(kind string) string {
+	if kind == model.ContactPhone {
+		return "phone number"
+	}
+	return "email"
+}
+
+// RequestEmailChange sends a code to newEmail after checking the password or step-up token. The email changes when
+// the code is confirmed; the current address is told about the request.
+func (s *UserService) RequestEmailChange(ctx context.Context, userID, newEmail string, auth SensitiveAuth) (*dto.ContactChangeResponse, error) {
+	newEmail = strings.TrimSpace(newEmail)
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, ErrContactUnchanged
+	}
+	existing, err := s.userRepo.GetUserByEmail(newEmail)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrEmailInUse
+	}
+	return s.requestContactChange(ctx, user, model.ContactEmail, newEmail, "email", auth)
+}
+
+// RequestPhoneChange sends a code to newPhone by SMS (default) or WhatsApp after checking the password or step-up
+// token. The number changes when the code is confirmed; the current number and email are told about the request.
+func (s *UserService) RequestPhoneChange(ctx context.Context, userID, newPhone, channel string, auth SensitiveAuth) (*dto.ContactChangeResponse, error) {
+	newPhone = strings.TrimSpace(newPhone)
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if normalizePhone(newPhone) == normalizePhone(user.PhoneNumber) {
+		return nil, ErrContactUnchanged
+	}
+	existing, err := s.userRepo.GetUserByPhoneNumberHash(hashPhone(newPhone))
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrPhoneInUse
+	}
+	if channel == "" {
+		channel = "sms"
+	}
+	return s.requestContactChange(ctx, user, model.ContactPhone, newPhone, channel, auth)
+}
+
+func (s *UserService) requestContactChange(ctx context.Context, user *model.User, kind, value, channel string, auth SensitiveAuth) (*dto.ContactChangeResponse, error) {
+	if err := s.confirmSensitive(ctx, user, StepUpChangeContact, auth); err != nil {
+		return nil, err
+	}
+	if err := s.checkContactCooldown(user.ID, kind); err != nil {
+		return nil, err
+	}
+	if err := s.limits.otpSend.Check(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	code, err := randomString("0123456789", 6)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	change, err := s.userRepo.CreateContactChange(&model.ContactChange{
+		UserID:      user.ID,
+		Kind:        kind,
+		NewValue:    value,
+		CodeHash:    hashSecondFactorCode(code),
+		Channel:     channel,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(contactChangeCodeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sendContactChangeCode(user, change, code); err != nil {
+		log.Printf("user service: send %s change code user=%s channel=%s err=%v", kind, user.ID, channel, err)
+		_, _ = s.userRepo.CancelContactChange(change.ID)
+		return nil, ErrFallbackUnavailable
+	}
+	_ = s.limits.otpSend.Fail(ctx, user.ID)
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   kind + "_change_requested",
+		Entity:   "user",
+		EntityID: user.ID,
+		UserID:   &user.ID,
+		Metadata: map[string]interface{}{"channel": channel, "new_value": maskContact(kind, value)},
+	})
+	s.notifyContactChange(user, change, false)
+	return &dto.ContactChangeResponse{
+		Kind:        kind,
+		Channel:     channel,
+		Destination: maskContact(kind, value),
+		ExpiresIn:   int(contactChangeCodeTTL.Seconds()),
+	}, nil
+}
+
+// checkContactCooldown refuses a change of kind while the last confirmed one is younger than the cooldown.
+func (s *UserService) checkContactCooldown(userID, kind string) error {
+	if s.contactChangeCooldown <= 0 {
+		return nil
+	}
+	last, err := s.userRepo.LastContactChange(userID, kind)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		if until := last.Add(s.contactChangeCooldown); time.Now().Before(until) {
+			return &ContactCooldownError{Kind: kind, Until: until}
+		}
+	}
+	return nil
+}
+
+// ConfirmContactChange applies the pending change of kind when code matches. Wrong codes count towards a lockout,
+// which also drops the pending change. An email change signs the user out everywhere, since access tokens carry the
+// old address; other services are told about the change on user-events.
+func (s *UserService) ConfirmContactChange(ctx context.Context, userID, kind, code string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+	if err := s.limits.contact.Check(ctx, userID); err != nil {
+		return err
+	}
+	change, err := s.userRepo.GetPendingContactChange(userID, kind)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if change == nil || now.After(change.ExpiresAt) {
+		return ErrNoContactChange
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecondFactorCode(code)), []byte(change.CodeHash)) != 1 {
+		lockErr := s.limits.contact.Fail(ctx, userID)
+		if _, locked := attempts.IsLocked(lockErr); locked {
+			_, _ = s.userRepo.CancelContactChange(change.ID)
+			_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+				Service:  "user",
+				Action:   kind + "_change_locked",
+				Entity:   "user",
+				EntityID: userID,
+				UserID:   &userID,
+				Metadata: map[string]interface{}{"new_value": maskContact(kind, change.NewValue)},
+			})
+			return lockErr
+		}
+		return ErrInvalidChangeCode
+	}
+	s.limits.contact.Succeed(ctx, userID)
+	// The number was free when the code was sent; the unique index catches anyone who registered it since.
+	ok, err := s.userRepo.ConfirmContactChange(change, hashPhone(change.NewValue), now)
+	if errors.Is(err, repository.ErrContactInUse) {
+		_, _ = s.userRepo.CancelContactChange(change.ID)
+		if kind == model.ContactPhone {
+			return ErrPhoneInUse
+		}
+		return ErrEmailInUse
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoContactChange
+	}
+
+	updated := *user
+	if kind == model.ContactEmail {
+		updated.Email, updated.EmailVerified = change.NewValue, true
+		if err := s.RevokeAllSessions(ctx, userID, repository.RevokeReasonEmailChanged); err != nil {
+			log.Printf("user service: revoke sessions after email change user=%s err=%v", userID, err)
+		}
+	} else {
+		updated.PhoneNumber, updated.PhoneNumberHash = change.NewValue, hashPhone(change.NewValue)
+	}
+	if err := s.producer.SendUserEvent(kafka.UserEvent{
+		EventType:   "user_contact_changed",
+		UserID:      userID,
+		Field:       kind,
+		Email:       updated.Email,
+		PhoneNumber: updated.PhoneNumber,
+	}); err != nil {
+		log.Printf("user service: publish user_contact_changed user=%s err=%v", userID, err)
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   kind + "_changed",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{
+			"old_value": maskContact(kind, contactValue(user, kind)),
+			"new_value": maskContact(kind, change.NewValue),
+		},
+	})
+	s.notifyContactChange(user, change, true)
+	return nil
+}
+
+// sendContactChangeCode sends the confirmation code to the new address.
+func (s *UserService) sendContactChangeCode(user *model.User, c *model.ContactChange, code string) error {
+	minutes := int(contactChangeCodeTTL.Minutes())
+	body := fmt.Sprintf("Your PayUp code to confirm this %s is %s. It expires in %d minutes. Never share it with anyone.", contactNoun(c.Kind), code, minutes)
+	eventType := c.Kind + "_change_otp"
+	switch c.Channel {
+	case "sms":
+		return s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  "sms",
+			Metadata: map[string]interface{}{"to": c.NewValue, "body": body, "channel": "dnd"},
+		})
+	case "whatsapp":
+		return s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  "whatsapp",
+			Metadata: map[string]interface{}{"to": c.NewValue, "otp": code},
+		})
+	default:
+		return s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  "email",
+			Metadata: map[string]interface{}{"to": c.NewValue, "to_name": user.FirstName},
+			Data:     map[string]interface{}{"otp": code},
+		})
+	}
+}
+
+// notifyContactChange tells the current (old) addresses that a change was requested or made, so an owner whose
+// session was stolen hears about it where the attacker cannot intercept it. Phone changes also go to the old number.
+func (s *UserService) notifyContactChange(user *model.User, c *model.ContactChange, applied bool) {
+	noun := contactNoun(c.Kind)
+	masked := maskContact(c.Kind, c.NewValue)
+	body := fmt.Sprintf("Someone asked to change the %s on your PayUp account to %s. ", noun, masked)
+	if applied {
+		body = fmt.Sprintf("The %s on your PayUp account was changed to %s. ", noun, masked)
+		if c.Kind == model.ContactEmail {
+			body += "You were signed out on all devices; sign in with the new email. "
+		}
+	}
+	body += "If this wasn't you, contact support immediately."
+	_ = s.sendNotification(kafka.NotificationEvent{
+		UserID:   user.ID,
+		Type:     c.Kind + "_change",
+		Channel:  "email",
+		Metadata: map[string]interface{}{"to": user.Email, "to_name": user.FirstName},
+		Data:     map[string]interface{}{"new_value": masked, "applied": applied},
+	})
+	if c.Kind == model.ContactPhone && user.PhoneNumber != "" {
+		_ = s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     c.Kind + "_change",
+			Channel:  "sms",
+			Metadata: map[string]interface{}{"to": user.PhoneNumber, "body": body},
+		})
+	}
+}
+
+func contactValue(user *model.User, kind string) string {
+	if kind == model.ContactPhone {
+		return user.PhoneNumber
+	}
+	return user.Email
+}
+
+func maskContact(kind, value string) string {
+	if kind == model.ContactPhone {
+		return maskPhone(value)
+	}
+	return maskEmail(value)
+}
@@ -0,0 +1,24 @@
+package service
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"08031234567":       "+2348031234567",
+		"2348031234567":     "+2348031234567",
+		"+234 803 123 4567": "+2348031234567",
+		" 0803-123-4567 ":   "+2348031234567",
+		"+447911123456":     "+447911123456",
+	}
+	for in, want := range cases {
+		if got := normalizePhone(in); got != want {
+			t.Errorf("normalizePhone(%q) = %q, want %q", in, got, want)
+		}
+		if hashPhone(in) != hashPhone(want) {
+			t.Errorf("hashPhone(%q) differs from hashPhone(%q)", in, want)
+		}
+	}
+	if hashPhone("08031234567") == hashPhone("08031234568") {
+		t.Error("different numbers share a hash")
+	}
+}
@@ -0,0 +1,530 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/clients"
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+	"github.com/abubakvr/payup-backend/services/user/redis"
+	"github.com/google/uuid"
+)
+
+const (
+	// dataExportTTL is how long a finished export can be downloaded before the archive is purged.
+	dataExportTTL = 7 * 24 * time.Hour
+	// dataSubjectLease keeps a claimed request away from other sweeps while it is worked; a crashed run retries after it.
+	dataSubjectLease       = 15 * time.Minute
+	dataSubjectMaxAttempts = 8
+	dataSubjectBatch       = 20
+	auditExportPage        = 200
+)
+
+var (
+	ErrDataExportPending    = errors.New("a data export is already being prepared")
+	ErrDeletionPending      = errors.New("account deletion is already scheduled")
+	ErrNoDeletionRequest    = errors.New("no account deletion is scheduled")
+	ErrDataExportNotFound   = errors.New("export not found or no longer available; request a new one")
+	errPrivacyNotConfigured = errors.New("KYC or payment service not configured; cannot erase account data")
+)
+
+// PrivacyConfig wires data-subject requests: the services a data export gathers from and an account deletion erases
+// in, and the grace period before a deletion runs. A nil client leaves that service out of exports; deletions wait
+// (retrying) until the KYC and payment clients are configured, since neither may be skipped.
+type PrivacyConfig struct {
+	KYC           *clients.KYCClient
+	Payment       *clients.PaymentClient
+	Audit         *clients.AuditClient
+	DeletionGrace time.Duration
+}
+
+// dataSubjectStore is the part of the user repository that keeps export and deletion requests and erases accounts.
+type dataSubjectStore interface {
+	GetUserByID(id string) (*model.User, error)
+	CreateDataSubjectRequest(userID, kind string, scheduledFor time.Time) (*model.DataSubjectRequest, error)
+	ListDataSubjectRequests(userID string) ([]model.DataSubjectRequest, error)
+	GetExportArchive(userID, id string, now time.Time) ([]byte, error)
+	CancelDataSubjectRequest(userID, kind string) (*model.DataSubjectRequest, error)
+	ClaimDueDataSubjectRequests(now time.Time, lease time.Duration, limit int) ([]model.DataSubjectRequest, error)
+	CompleteDataSubjectRequest(id, status string, result interface{}, archive []byte, archiveExpiresAt *time.Time) error
+	RetryDataSubjectRequest(id, lastError string, retryAt time.Time) error
+	PurgeExpiredExports(now time.Time) (int64, error)
+	AnonymizeUser(userID string, now time.Time) error
+}
+
+// serviceOutcome is one service's part of a request's result.
+type serviceOutcome struct {
+	Exported    *bool    `json:"exported,omitempty"`
+	Held        bool     `json:"held,omitempty"`
+	HoldReasons []string `json:"holdReasons,omitempty"`
+	Erased      []string `json:"erased,omitempty"`
+	Retained    []string `json:"retained,omitempty"`
+	Note        string   `json:"note,omitempty"`
+}
+
+// RequestDataExport queues an export of everything held about the user across services, after checking the password
+// or step-up token. The archive is built by the background sweep and can be downloaded for dataExportTTL.
+func (s *UserService) RequestDataExport(ctx context.Context, userID string, auth SensitiveAuth) (*dto.DataSubjectRequestResponse, error) {
+	user, err := s.privacyRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if err := s.confirmSensitive(ctx, user, StepUpExportData, auth); err != nil {
+		return nil, err
+	}
+	req, err := s.privacyRepo.CreateDataSubjectRequest(userID, model.DataSubjectExport, time.Now())
+	if errors.Is(err, repository.ErrDataSubjectRequestPending) {
+		return nil, ErrDataExportPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.auditDataSubject("data_export_requested", req, nil)
+	return dataSubjectResponse(req), nil
+}
+
+// RequestAccountDeletion schedules the account for deletion after the grace period, during which the user can cancel.
+// The current email is told when it will happen.
+func (s *UserService) RequestAccountDeletion(ctx context.Context, userID string, auth SensitiveAuth) (*dto.DataSubjectRequestResponse, error) {
+	user, err := s.privacyRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if err := s.confirmSensitive(ctx, user, StepUpDeleteAccount, auth); err != nil {
+		return nil, err
+	}
+	req, err := s.privacyRepo.CreateDataSubjectRequest(userID, model.DataSubjectDeletion, time.Now().Add(s.privacy.DeletionGrace))
+	if errors.Is(err, repository.ErrDataSubjectRequestPending) {
+		return nil, ErrDeletionPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.auditDataSubject("account_deletion_requested", req, nil)
+	when := req.ScheduledFor.UTC().Format("2 Jan 2006 15:04 MST")
+	s.notifyPrivacy(user, "account_deletion_scheduled", "Your PayUp account is scheduled for deletion",
+		fmt.Sprintf("Your PayUp account will be deleted on %s. Until then you can cancel in Settings > Privacy. "+
+			"Withdraw any wallet balance first: an account with money in it cannot be deleted. "+
+			"If this wasn't you, cancel now and change your password.", when))
+	return dataSubjectResponse(req), nil
+}
+
+// CancelAccountDeletion cancels a deletion still in its grace period.
+func (s *UserService) CancelAccountDeletion(ctx context.Context, userID string) (*dto.DataSubjectRequestResponse, error) {
+	req, err := s.privacyRepo.CancelDataSubjectRequest(userID, model.DataSubjectDeletion)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrNoDeletionRequest
+	}
+	s.auditDataSubject("account_deletion_cancelled", req, nil)
+	if user, _ := s.privacyRepo.GetUserByID(userID); user != nil {
+		s.notifyPrivacy(user, "account_deletion_cancelled", "Your PayUp account will not be deleted",
+			"The scheduled deletion of your PayUp account was cancelled. If this wasn't you, change your password now.")
+	}
+	return dataSubjectResponse(req), nil
+}
+
+// ListDataSubjectRequests returns the user's export and deletion requests, newest first.
+func (s *UserService) ListDataSubjectRequests(userID string) ([]dto.DataSubjectRequestResponse, error) {
+	reqs, err := s.privacyRepo.ListDataSubjectRequests(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.DataSubjectRequestResponse, 0, len(reqs))
+	for i := range reqs {
+		out = append(out, *dataSubjectResponse(&reqs[i]))
+	}
+	return out, nil
+}
+
+// DownloadDataExport returns the zip of a finished export that has not expired.
+func (s *UserService) DownloadDataExport(ctx context.Context, userID, requestID string) ([]byte, error) {
+	if _, err := uuid.Parse(requestID); err != nil {
+		return nil, ErrDataExportNotFound
+	}
+	archive, err := s.privacyRepo.GetExportArchive(userID, requestID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if archive == nil {
+		return nil, ErrDataExportNotFound
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "data_export_downloaded",
+		Entity:   "data_subject_request",
+		EntityID: requestID,
+		UserID:   &userID,
+	})
+	return archive, nil
+}
+
+// ProcessDataSubjectRequests purges expired export archives, then works exports and deletions that are due. A run
+// that fails is retried later, up to dataSubjectMaxAttempts. Returns the number of requests finished.
+func (s *UserService) ProcessDataSubjectRequests(ctx context.Context) (int, error) {
+	if n, err := s.privacyRepo.PurgeExpiredExports(time.Now()); err != nil {
+		return 0, err
+	} else if n > 0 {
+		log.Printf("user service: purged %d expired data export(s)", n)
+	}
+	done := 0
+	for ctx.Err() == nil {
+		due, err := s.privacyRepo.ClaimDueDataSubjectRequests(time.Now(), dataSubjectLease, dataSubjectBatch)
+		if err != nil {
+			return done, err
+		}
+		for i := range due {
+			r := &due[i]
+			if r.Kind == model.DataSubjectExport {
+				err = s.processDataExport(ctx, r)
+			} else {
+				err = s.processAccountDeletion(ctx, r)
+			}
+			if err == nil {
+				done++
+				continue
+			}
+			log.Printf("user service: %s request=%s user=%s attempt=%d: %v", r.Kind, r.ID, r.UserID, r.Attempts, err)
+			if r.Attempts >= dataSubjectMaxAttempts {
+				_ = s.privacyRepo.CompleteDataSubjectRequest(r.ID, model.DataSubjectFailed, map[string]string{"error": err.Error()}, nil, nil)
+				s.auditDataSubject(r.Kind+"_failed", r, map[string]interface{}{"error": err.Error()})
+				continue
+			}
+			_ = s.privacyRepo.RetryDataSubjectRequest(r.ID, err.Error(), time.Now().Add(time.Duration(r.Attempts)*10*time.Minute))
+		}
+		if len(due) < dataSubjectBatch {
+			break
+		}
+	}
+	return done, ctx.Err()
+}
+
+// processDataExport builds the export zip: user.json from this service, then kyc.json, payment.json and audit.json
+// from the other services over gRPC.
+func (s *UserService) processDataExport(ctx context.Context, r *model.DataSubjectRequest) error {
+	user, err := s.privacyRepo.GetUserByID(r.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return s.privacyRepo.CompleteDataSubjectRequest(r.ID, model.DataSubjectCancelled, map[string]string{"note": "account no longer exists"}, nil, nil)
+	}
+	account, err := s.accountExport(user)
+	if err != nil {
+		return err
+	}
+	files := []exportFile{{name: "user.json", data: account}}
+	result := map[string]serviceOutcome{"user": exported(true)}
+
+	if c := s.privacy.KYC; c != nil {
+		resp, err := c.ExportUserData(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("kyc export: %w", err)
+		}
+		files = append(files, exportFile{name: "kyc.json", data: []byte(resp.JsonPayload)})
+		result["kyc"] = exported(resp.Found)
+	} else {
+		result["kyc"] = serviceOutcome{Note: "not configured"}
+	}
+	if c := s.privacy.Payment; c != nil {
+		resp, err := c.ExportUserData(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("payment export: %w", err)
+		}
+		files = append(files, exportFile{name: "payment.json", data: []byte(resp.JsonPayload)})
+		result["payment"] = exported(resp.Found)
+	} else {
+		result["payment"] = serviceOutcome{Note: "not configured"}
+	}
+	if c := s.privacy.Audit; c != nil {
+		logs, err := s.auditExport(ctx, c, user.ID)
+		if err != nil {
+			return fmt.Errorf("audit export: %w", err)
+		}
+		files = append(files, exportFile{name: "audit.json", data: logs})
+		result["audit"] = exported(true)
+	} else {
+		result["audit"] = serviceOutcome{Note: "not configured"}
+	}
+
+	archive, err := zipExport(files)
+	if err != nil {
+		return err
+	}
+	expires := time.Now().Add(dataExportTTL)
+	if err := s.privacyRepo.CompleteDataSubjectRequest(r.ID, model.DataSubjectCompleted, result, archive, &expires); err != nil {
+		return err
+	}
+	s.auditDataSubject("data_export_completed", r, map[string]interface{}{"bytes": len(archive)})
+	s.notifyPrivacy(user, "data_export_ready", "Your PayUp data export is ready",
+		fmt.Sprintf("The copy of your PayUp data you asked for is ready. Download it in Settings > Privacy before %s; after that it is deleted.",
+			expires.UTC().Format("2 Jan 2006 15:04 MST")))
+	return nil
+}
+
+// processAccountDeletion erases the account across services. Payment goes first: while it reports a hold (money in
+// the wallet, a pending payment, an open dispute) nothing is erased anywhere and the request ends held. Financial and
+// KYC records the law requires us to keep are retained by those services, and the audit trail is never erased.
+func (s *UserService) processAccountDeletion(ctx context.Context, r *model.DataSubjectRequest) error {
+	user, err := s.privacyRepo.GetUserByID(r.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return s.privacyRepo.CompleteDataSubjectRequest(r.ID, model.DataSubjectCompleted, map[string]string{"note": "account no longer exists"}, nil, nil)
+	}
+	if s.privacy.Payment == nil || s.privacy.KYC == nil {
+		return errPrivacyNotConfigured
+	}
+	result := map[string]serviceOutcome{}
+	pay, err := s.privacy.Payment.EraseUserData(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("payment erase: %w", err)
+	}
+	if pay.Held {
+		result["payment"] = serviceOutcome{Held: true, HoldReasons: pay.HoldReasons}
+		if err := s.privacyRepo.CompleteDataSubjectRequest(r.ID, model.DataSubjectHeld, result, nil, nil); err != nil {
+			return err
+		}
+		s.auditDataSubject("account_deletion_held", r, map[string]interface{}{"reasons": pay.HoldReasons})
+		s.notifyPrivacy(user, "account_deletion_held", "We couldn't delete your PayUp account yet",
+			"Your PayUp account was not deleted because "+strings.Join(pay.HoldReasons, "; ")+
+				". Sort these out, then request deletion again in Settings > Privacy.")
+		return nil
+	}
+	result["payment"] = serviceOutcome{Erased: pay.Erased, Retained: pay.Retained}
+	kyc, err := s.privacy.KYC.EraseUserData(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("kyc erase: %w", err)
+	}
+	result["kyc"] = serviceOutcome{Erased: kyc.Erased, Retained: kyc.Retained}
+
+	if err := s.privacyRepo.AnonymizeUser(user.ID, time.Now()); err != nil {
+		return err
+	}
+	result["user"] = serviceOutcome{Erased: []string{"profile", "credentials", "devices"}, Retained: []string{"account_id"}}
+	result["audit"] = serviceOutcome{Retained: []string{"audit_trail"}}
+	if err := s.RevokeAllSessions(ctx, user.ID, repository.RevokeReasonAccountDeleted); err != nil {
+		log.Printf("user service: revoke sessions after account deletion user=%s err=%v", user.ID, err)
+	}
+	redis.DeleteUserExists(ctx, user.ID)
+	if err := s.privacyRepo.CompleteDataSubjectRequest(r.ID, model.DataSubjectCompleted, result, nil, nil); err != nil {
+		return err
+	}
+	s.auditDataSubject("account_deleted", r, map[string]interface{}{"result": result})
+	// user still holds the original email; the row no longer does.
+	s.notifyPrivacy(user, "account_deleted", "Your PayUp account has been deleted",
+		"Your PayUp account has been deleted and your personal details removed. Records we must keep by law, such as "+
+			"transaction history and identity verification, are retained for the required period and then destroyed.")
+	return nil
+}
+
+// accountExport is user.json: the profile, non-secret settings, signed-in devices, passkeys and past requests.
+func (s *UserService) accountExport(user *model.User) ([]byte, error) {
+	type settingsExport struct {
+		PinSet                   bool     `json:"pinSet"`
+		BiometricEnabled         bool     `json:"biometricEnabled"`
+		TwoFactorEnabled         bool     `json:"twoFactorEnabled"`
+		TwoFactorFallbackChannel *string  `json:"twoFactorFallbackChannel,omitempty"`
+		DailyTransferLimit       *float64 `json:"dailyTransferLimit,omitempty"`
+		MonthlyTransferLimit     *float64 `json:"monthlyTransferLimit,omitempty"`
+		TransactionAlertsEnabled bool     `json:"transactionAlertsEnabled"`
+		TransfersDisabled        bool     `json:"transfersDisabled"`
+		Language                 *string  `json:"language,omitempty"`
+		Theme                    *string  `json:"theme,omitempty"`
+	}
+	type passkeyExport struct {
+		Name       string     `json:"name"`
+		BackedUp   bool       `json:"backedUp"`
+		CreatedAt  time.Time  `json:"createdAt"`
+		LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	}
+	out := struct {
+		Profile struct {
+			ID            string    `json:"id"`
+			Email         string    `json:"email"`
+			EmailVerified bool      `json:"emailVerified"`
+			FirstName     string    `json:"firstName"`
+			LastName      string    `json:"lastName"`
+			PhoneNumber   string    `json:"phoneNumber"`
+			CreatedAt     time.Time `json:"createdAt"`
+		} `json:"profile"`
+		Settings *settingsExport                  `json:"settings,omitempty"`
+		Sessions []dto.SessionResponse            `json:"sessions"`
+		Passkeys []passkeyExport                  `json:"passkeys"`
+		Devices  []dto.PushTokenResponse          `json:"pushDevices"`
+		Requests []dto.DataSubjectRequestResponse `json:"privacyRequests"`
+	}{}
+	out.Profile.ID, out.Profile.Email, out.Profile.EmailVerified = user.ID, user.Email, user.EmailVerified
+	out.Profile.FirstName, out.Profile.LastName, out.Profile.PhoneNumber = user.FirstName, user.LastName, user.PhoneNumber
+	out.Profile.CreatedAt = user.CreatedAt
+
+	settings, err := s.userRepo.GetUserSettings(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if settings != nil {
+		out.Settings = &settingsExport{
+			PinSet:                   settings.PinHash != nil,
+			BiometricEnabled:         settings.BiometricEnabled,
+			TwoFactorEnabled:         settings.TwoFactorEnabled,
+			TwoFactorFallbackChannel: settings.TwoFactorFallbackChannel,
+			DailyTransferLimit:       settings.DailyTransferLimit,
+			MonthlyTransferLimit:     settings.MonthlyTransferLimit,
+			TransactionAlertsEnabled: settings.TransactionAlertsEnabled,
+			TransfersDisabled:        settings.TransfersDisabled,
+			Language:                 settings.Language,
+			Theme:                    settings.Theme,
+		}
+	}
+	sessions, err := s.userRepo.ListActiveSessions(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		out.Sessions = append(out.Sessions, toSessionResponse(&sessions[i], ""))
+	}
+	passkeys, err := s.userRepo.ListPasskeys(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range passkeys {
+		out.Passkeys = append(out.Passkeys, passkeyExport{Name: p.Name, BackedUp: p.BackedUp, CreatedAt: p.CreatedAt, LastUsedAt: p.LastUsedAt})
+	}
+	if out.Devices, err = s.ListPushTokens(user.ID); err != nil {
+		return nil, err
+	}
+	if out.Requests, err = s.ListDataSubjectRequests(user.ID); err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// auditExport pages through the user's audit trail.
+func (s *UserService) auditExport(ctx context.Context, c *clients.AuditClient, userID string) ([]byte, error) {
+	type entry struct {
+		Service   string          `json:"service"`
+		Action    string          `json:"action"`
+		Entity    string          `json:"entity"`
+		EntityID  string          `json:"entityId"`
+		Metadata  json.RawMessage `json:"metadata,omitempty"`
+		CreatedAt string          `json:"createdAt"`
+	}
+	entries := []entry{}
+	for offset := int32(0); ; offset += auditExportPage {
+		resp, err := c.GetUserAudits(ctx, userID, auditExportPage, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range resp.Logs {
+			e := entry{Service: l.Service, Action: l.Action, Entity: l.Entity, EntityID: l.EntityId, CreatedAt: l.CreatedAt}
+			if json.Valid([]byte(l.MetadataJson)) {
+				e.Metadata = json.RawMessage(l.MetadataJson)
+			}
+			entries = append(entries, e)
+		}
+		if len(resp.Logs) < auditExportPage || int64(offset)+int64(len(resp.Logs)) >= resp.Total {
+			break
+		}
+	}
+	return json.Marshal(entries)
+}
+
+type exportFile struct {
+	name string
+	data []byte
+}
+
+// zipExport writes files into a zip, indenting each JSON document so the archive is readable as is.
+func zipExport(files []exportFile) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		data := f.data
+		if len(data) == 0 {
+			data = []byte("null")
+		}
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, data, "", "  ") == nil {
+			data = pretty.Bytes()
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func exported(found bool) serviceOutcome {
+	return serviceOutcome{Exported: &found}
+}
+
+func dataSubjectResponse(r *model.DataSubjectRequest) *dto.DataSubjectRequestResponse {
+	out := &dto.DataSubjectRequestResponse{
+		ID:          r.ID,
+		Kind:        r.Kind,
+		Status:      r.Status,
+		RequestedAt: r.RequestedAt,
+		CompletedAt: r.CompletedAt,
+		Result:      r.Result,
+	}
+	if r.Status == model.DataSubjectPending {
+		at := r.ScheduledFor
+		out.ScheduledFor = &at
+	}
+	if r.ArchiveExpiresAt != nil && time.Now().Before(*r.ArchiveExpiresAt) {
+		out.DownloadExpiresAt = r.ArchiveExpiresAt
+	}
+	return out
+}
+
+func (s *UserService) auditDataSubject(action string, r *model.DataSubjectRequest, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["kind"] = r.Kind
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   action,
+		Entity:   "data_subject_request",
+		EntityID: r.ID,
+		UserID:   &r.UserID,
+		Metadata: metadata,
+	})
+}
+
+// notifyPrivacy emails the user about their export or deletion.
+func (s *UserService) notifyPrivacy(user *model.User, eventType, subject, body string) {
+	_ = s.sendNotification(kafka.NotificationEvent{
+		UserID:  user.ID,
+		Type:    eventType,
+		Channel: "email",
+		Metadata: map[string]interface{}{
+			"to":      user.Email,
+			"to_name": user.FirstName,
+			"subject": subject,
+			"body":    body,
+			"html":    "<p>Hi " + html.EscapeString(user.FirstName) + ",</p><p>" + html.EscapeString(body) + "</p>",
+		},
+	})
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"github.com/abubakvr/payup-backend/services/user/internal/clients"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"google.golang.org/grpc"
+)
+
+// fakeDataSubjects holds one user and their due requests. Methods the tests do not reach panic through the embedded
+// nil interface.
+type fakeDataSubjects struct {
+	dataSubjectStore
+	user       *model.User
+	requests   map[string]*model.DataSubjectRequest
+	results    map[string]interface{}
+	anonymized bool
+}
+
+func (f *fakeDataSubjects) GetUserByID(id string) (*model.User, error) {
+	if f.user == nil || f.user.ID != id {
+		return nil, nil
+	}
+	return f.user, nil
+}
+
+func (f *fakeDataSubjects) PurgeExpiredExports(time.Time) (int64, error) { return 0, nil }
+
+func (f *fakeDataSubjects) ClaimDueDataSubjectRequests(now time.Time, lease time.Duration, limit int) ([]model.DataSubjectRequest, error) {
+	var due []model.DataSubjectRequest
+	for _, r := range f.requests {
+		if r.Status == model.DataSubjectPending && !r.ScheduledFor.After(now) {
+			r.Attempts++
+			due = append(due, *r)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeDataSubjects) CompleteDataSubjectRequest(id, status string, result interface{}, archive []byte, archiveExpiresAt *time.Time) error {
+	f.requests[id].Status = status
+	f.results[id] = result
+	return nil
+}
+
+func (f *fakeDataSubjects) RetryDataSubjectRequest(id, lastError string, retryAt time.Time) error {
+	f.requests[id].LastError, f.requests[id].ScheduledFor = lastError, retryAt
+	return nil
+}
+
+func (f *fakeDataSubjects) AnonymizeUser(string, time.Time) error {
+	f.anonymized = true
+	return nil
+}
+
+// sentNotifications records what the service asked the notification service to send.
+type sentNotifications []kafka.NotificationEvent
+
+func (s *sentNotifications) SendNotification(ev kafka.NotificationEvent) error {
+	*s = append(*s, ev)
+	return nil
+}
+
+// holdingPayments is a payment service that refuses every erasure.
+type holdingPayments struct {
+	paymentpb.UnimplementedPaymentServiceServer
+	reasons []string
+}
+
+func (p *holdingPayments) EraseUserData(context.Context, *paymentpb.EraseUserDataRequest) (*paymentpb.EraseUserDataResponse, error) {
+	return &paymentpb.EraseUserDataResponse{Held: true, HoldReasons: p.reasons}, nil
+}
+
+// startPaymentService serves srv on a loopback port and returns a client for it.
+func startPaymentService(t *testing.T, srv paymentpb.PaymentServiceServer) *clients.PaymentClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	paymentpb.RegisterPaymentServiceServer(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+	c, err := clients.NewPaymentClient(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestAccountDeletionHeld(t *testing.T) {
+	store := &fakeDataSubjects{
+		user: &model.User{ID: "user-1", Email: "ada@example.com", FirstName: "<b>Ada</b>"},
+		requests: map[string]*model.DataSubjectRequest{"dsr-1": {
+			ID: "dsr-1", UserID: "user-1", Kind: model.DataSubjectDeletion, Status: model.DataSubjectPending,
+			ScheduledFor: time.Now().Add(-time.Minute),
+		}},
+		results: map[string]interface{}{},
+	}
+	// The KYC service is never dialled: payment holds the deletion first.
+	kyc, err := clients.NewKYCClient("127.0.0.1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kyc.Close()
+	payments := startPaymentService(t, &holdingPayments{reasons: []string{"wallet balance of NGN 1,200.00 must be withdrawn"}})
+	var sent sentNotifications
+	svc := &UserService{privacyRepo: store, notifier: &sent, privacy: PrivacyConfig{KYC: kyc, Payment: payments}}
+
+	done, err := svc.ProcessDataSubjectRequests(context.Background())
+	if err != nil || done != 1 {
+		t.Fatalf("process = %d, %v", done, err)
+	}
+	if got := store.requests["dsr-1"].Status; got != model.DataSubjectHeld || store.anonymized {
+		t.Fatalf("request %s, anonymized %v; want held with the account intact", got, store.anonymized)
+	}
+	result, _ := json.Marshal(store.results["dsr-1"])
+	if !strings.Contains(string(result), "must be withdrawn") {
+		t.Errorf("result %s does not record the hold reason", result)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("sent %d notifications, want the held email", len(sent))
+	}
+	email := sent[0]
+	if email.Type != "account_deletion_held" || email.Channel != "email" || email.Metadata["to"] != "ada@example.com" {
+		t.Errorf("notification = %+v", email)
+	}
+	body := email.Metadata["html"].(string)
+	if !strings.HasPrefix(body, "<p>Hi &lt;b&gt;Ada&lt;/b&gt;,</p>") || !strings.Contains(body, "wallet balance of NGN 1,200.00 must be withdrawn") {
+		t.Errorf("html = %q", body)
+	}
+}
@@ -1,14 +1,39 @@
 package service
 
 import (
-	"os"
-
+	"log"
 	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/abubakvr/payup-backend/pkg/jwtauth"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+// jwtKeys signs user tokens. Keys come from JWT_KEYS_DIR (one <kid>.pem per key, JWT_ACTIVE_KID picks the signer);
+// other services verify against the public keys served at /.well-known/jwks.json.
+var jwtKeys = mustLoadJWTKeys()
+
+func mustLoadJWTKeys() *jwtauth.KeySet {
+	ks, err := jwtauth.KeySetFromEnv("JWT")
+	if err != nil {
+		log.Fatalf("jwt keys: %v", err)
+	}
+	return ks
+}
+
+// JWKS returns the public signing keys for GET /.well-known/jwks.json.
+func JWKS() jwtauth.JWKS {
+	return jwtKeys.JWKS()
+}
+
+// denyListTTL bounds how long a revocation is kept; no user token lives longer than an hour.
+const denyListTTL = time.Hour
+
+// userTokenVerifier checks user tokens against the local key set, the same keys other services fetch via JWKS.
+func userTokenVerifier() authn.TokenVerifier {
+	return jwtauth.LocalVerifier{Keys: jwtKeys, Issuer: jwtauth.UserIssuer, Audience: jwtauth.UserAudience}
+}
 
 // Purpose2FALogin is the JWT purpose claim value for the short-lived token returned when login requires 2FA.
 const Purpose2FALogin = "2fa_login"
@@ -18,6 +43,11 @@ type Claims struct {
 	Email     string `json:"email"`
 	Role      string `json:"role"`
 	Purpose   string `json:"purpose,omitempty"` // e.g. Purpose2FALogin for 2FA verify-login flow
+	SessionID string `json:"sid,omitempty"`     // login session (user_sessions.id) the access token was issued for
+	// StepUpAction is the one sensitive action a step-up token (purpose PurposeStepUp) authorises.
+	StepUpAction string `json:"step_up_action,omitempty"`
+	// StepUpAmount is the largest transfer (naira) a transfer step-up token approves.
+	StepUpAmount float64 `json:"step_up_amount,omitempty"`
 	IssuedAt  int64  `json:"issued_at"`
 	ExpiresAt int64  `json:"expires_at"`
 	jwt.RegisteredClaims
@@ -29,33 +59,59 @@ func GenerateJWT(userID, email string, expiryMinutes int) (string, error) {
 
 // GenerateJWTWithPurpose creates a JWT with an optional purpose claim (e.g. Purpose2FALogin).
 func GenerateJWTWithPurpose(userID, email, purpose string, expiryMinutes int) (string, error) {
-	claims := &Claims{
+	return signClaims(newClaims(userID, email, purpose, expiryMinutes))
+}
+
+// GenerateSessionJWT creates an access token bound to a login session (sid claim).
+func GenerateSessionJWT(userID, email, sessionID string, expiryMinutes int) (string, error) {
+	claims := newClaims(userID, email, "", expiryMinutes)
+	claims.SessionID = sessionID
+	return signClaims(claims)
+}
+
+func newClaims(userID, email, purpose string, expiryMinutes int) *Claims {
+	now := time.Now()
+	exp := now.Add(time.Minute * time.Duration(expiryMinutes))
+	return &Claims{
 		UserID:    userID,
 		Email:     email,
 		Role:      "user",
 		Purpose:   purpose,
-		IssuedAt:  time.Now().Unix(),
-		ExpiresAt: time.Now().Add(time.Minute * time.Duration(expiryMinutes)).Unix(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: exp.Unix(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:   "payup",
-			Subject:  userID,
-			Audience: jwt.ClaimStrings{"api"},
+			ID:        uuid.NewString(),
+			Issuer:    jwtauth.UserIssuer,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{jwtauth.UserAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
 		},
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+func signClaims(claims *Claims) (string, error) {
+	return jwtKeys.Sign(claims)
 }
 
-func ValidateJWT(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+// authnClaims converts to the shared claims so deny-list checks see the same jti, sid and iat.
+func (c *Claims) authnClaims() *authn.Claims {
+	return &authn.Claims{
+		UserID:           c.UserID,
+		Email:            c.Email,
+		Role:             c.Role,
+		Purpose:          c.Purpose,
+		SessionID:        c.SessionID,
+		RegisteredClaims: c.RegisteredClaims,
 	}
+}
 
-	return nil, err
+func ValidateJWT(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := jwtKeys.Parse(tokenString, jwtauth.UserIssuer, jwtauth.UserAudience, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
 
 // Generate2FAPendingToken returns a short-lived JWT (5 min) with purpose 2fa_login. Used when login requires TOTP.
@@ -67,3 +123,16 @@ func Generate2FAPendingToken(userID, email string) (string, time.Time, error) {
 	}
 	return token, time.Now().Add(time.Minute * expiryMin), nil
 }
+
+// GenerateStepUpToken returns a short-lived token (purpose step_up) that authorises action for the user's session.
+func GenerateStepUpToken(userID, email, sessionID, action string, amount float64, expiryMinutes int) (string, time.Time, error) {
+	claims := newClaims(userID, email, PurposeStepUp, expiryMinutes)
+	claims.SessionID = sessionID
+	claims.StepUpAction = action
+	claims.StepUpAmount = amount
+	token, err := signClaims(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, claims.RegisteredClaims.ExpiresAt.Time, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+// DefaultLimitIncreaseDelay is the cooling-off before a raised transfer limit takes effect.
+const DefaultLimitIncreaseDelay = 24 * time.Hour
+
+// limitSweepBatch is how many due limit changes ApplyDueLimitChanges applies per query.
+const limitSweepBatch = 100
+
+var ErrNoPendingLimitChange = errors.New("no pending limit increase")
+
+// raisesLimit reports whether moving a transfer limit from cur to next raises it. A nil or 0 limit means no limit, so
+// nothing raises an unlimited one, and next = 0 (remove the limit) raises any other.
+func raisesLimit(cur, next *float64) bool {
+	if next == nil || cur == nil || *cur == 0 {
+		return false
+	}
+	return *next == 0 || *next > *cur
+}
+
+// limitStage is the outcome of a limit request: decreases are already applied to the settings; next is the pending
+// change to keep (nil for none).
+type limitStage struct {
+	next    *model.LimitChange
+	changed bool // next differs from the stored pending change
+	raised  bool // the request added an increase, which restarts the cooling-off
+}
+
+// stageLimitChange applies the requested decreases to settings and works out the pending change that follows: the
+// existing one with this request's increases merged in and any limit it lowered dropped. Without a cooling-off
+// (limitIncreaseDelay 0) increases apply at once too.
+func (s *UserService) stageLimitChange(settings *model.UserSettings, pending *model.LimitChange, daily, monthly *float64, now time.Time) limitStage {
+	next := model.LimitChange{UserID: settings.UserID, RequestedAt: now, EffectiveAt: now.Add(s.limitIncreaseDelay)}
+	if pending != nil {
+		next = *pending
+	}
+	var stage limitStage
+	apply := func(current **float64, req *float64, scheduled **float64) {
+		if req == nil {
+			return
+		}
+		if s.limitIncreaseDelay > 0 && raisesLimit(*current, req) {
+			*scheduled = req
+			stage.raised = true
+		} else {
+			*current = req
+			if *scheduled != nil {
+				*scheduled = nil
+				stage.changed = true
+			}
+		}
+	}
+	apply(&settings.DailyTransferLimit, daily, &next.DailyTransferLimit)
+	apply(&settings.MonthlyTransferLimit, monthly, &next.MonthlyTransferLimit)
+	if stage.raised {
+		stage.changed = true
+		next.RequestedAt = now
+		next.EffectiveAt = now.Add(s.limitIncreaseDelay)
+	}
+	if next.DailyTransferLimit != nil || next.MonthlyTransferLimit != nil {
+		stage.next = &next
+	}
+	return stage
+}
+
+// commitLimitChange stores the staged pending change once the settings are saved and returns the pending change now in
+// effect. A new increase is audited and announced to the user on every channel.
+func (s *UserService) commitLimitChange(user *model.User, pending *model.LimitChange, stage limitStage) (*model.LimitChange, error) {
+	if !stage.changed {
+		return pending, nil
+	}
+	if stage.next == nil {
+		_, err := s.userRepo.CancelPendingLimitChange(user.ID)
+		return nil, err
+	}
+	stored, err := s.userRepo.SchedulePendingLimitChange(stage.next)
+	if err != nil {
+		return nil, err
+	}
+	if stage.raised {
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "limit_increase_requested",
+			Entity:   "user_settings",
+			EntityID: user.ID,
+			UserID:   &user.ID,
+			Metadata: limitChangeMetadata(stored),
+		})
+		s.notifyLimitChange(user, stored, false)
+	}
+	return stored, nil
+}
+
+// CancelLimitIncrease cancels the user's pending transfer limit increase; the current limits stay as they are.
+func (s *UserService) CancelLimitIncrease(ctx context.Context, userID string) (*dto.SettingsResponse, error) {
+	cancelled, err := s.userRepo.CancelPendingLimitChange(userID)
+	if err != nil {
+		return nil, err
+	}
+	if cancelled == nil {
+		return nil, ErrNoPendingLimitChange
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "limit_increase_cancelled",
+		Entity:   "user_settings",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: limitChangeMetadata(cancelled),
+	})
+	return s.GetSettings(ctx, userID)
+}
+
+// ApplyDueLimitChanges applies pending limit increases whose cooling-off has ended and tells each user. Returns how
+// many were applied. Meant to run on a timer.
+func (s *UserService) ApplyDueLimitChanges(ctx context.Context) (int, error) {
+	total := 0
+	for ctx.Err() == nil {
+		applied, err := s.userRepo.ApplyDueLimitChanges(time.Now(), limitSweepBatch)
+		if err != nil {
+			return total, err
+		}
+		total += len(applied)
+		for i := range applied {
+			c := &applied[i]
+			_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+				Service:  "user",
+				Action:   "limit_increase_applied",
+				Entity:   "user_settings",
+				EntityID: c.UserID,
+				UserID:   &c.UserID,
+				Metadata: limitChangeMetadata(c),
+			})
+			user, err := s.userRepo.GetUserByID(c.UserID)
+			if err != nil || user == nil {
+				log.Printf("user service: limit increase applied user=%s: load user for notification: %v", c.UserID, err)
+				continue
+			}
+			s.notifyLimitChange(user, c, true)
+		}
+		if len(applied) < limitSweepBatch {
+			break
+		}
+	}
+	return total, ctx.Err()
+}
+
+// notifyLimitChange tells the user by email, SMS and WhatsApp that a limit increase was requested or has taken effect.
+func (s *UserService) notifyLimitChange(user *model.User, c *model.LimitChange, applied bool) {
+	if s.notifier == nil {
+		return
+	}
+	changes := describeLimitChange(c)
+	effectiveAt := c.EffectiveAt.UTC().Format("02 Jan 2006 15:04 UTC")
+	eventType := "limit_increase_requested"
+	body := fmt.Sprintf("A transfer limit increase was requested on your PayUp account (%s). It takes effect on %s. "+
+		"If this wasn't you, cancel it in Settings > Limits and change your password now.", changes, effectiveAt)
+	if applied {
+		eventType = "limit_increase_applied"
+		body = fmt.Sprintf("Your PayUp transfer limit increase is now active (%s). If you didn't request it, pause your "+
+			"account in Settings and contact support.", changes)
+	}
+	if err := s.sendNotification(kafka.NotificationEvent{
+		UserID:   user.ID,
+		Type:     eventType,
+		Channel:  "email",
+		Metadata: map[string]interface{}{"to": user.Email},
+		Data:     map[string]interface{}{"changes": changes, "effective_at": effectiveAt},
+	}); err != nil {
+		log.Printf("user service: %s email user=%s err=%v", eventType, user.ID, err)
+	}
+	if user.PhoneNumber == "" {
+		return
+	}
+	for _, channel := range []string{"sms", "whatsapp"} {
+		if err := s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  channel,
+			Metadata: map[string]interface{}{"to": user.PhoneNumber, "body": body},
+		}); err != nil {
+			log.Printf("user service: %s %s user=%s err=%v", eventType, channel, user.ID, err)
+		}
+	}
+}
+
+func describeLimitChange(c *model.LimitChange) string {
+	format := func(v float64) string {
+		if v == 0 {
+			return "no limit"
+		}
+		return fmt.Sprintf("NGN %.2f", v)
+	}
+	out := ""
+	if c.DailyTransferLimit != nil {
+		out = "daily: " + format(*c.DailyTransferLimit)
+	}
+	if c.MonthlyTransferLimit != nil {
+		if out != "" {
+			out += ", "
+		}
+		out += "monthly: " + format(*c.MonthlyTransferLimit)
+	}
+	return out
+}
+
+func limitChangeMetadata(c *model.LimitChange) map[string]interface{} {
+	m := map[string]interface{}{"change_id": c.ID, "effective_at": c.EffectiveAt.UTC().Format(time.RFC3339)}
+	if c.DailyTransferLimit != nil {
+		m["daily_transfer_limit"] = *c.DailyTransferLimit
+	}
+	if c.MonthlyTransferLimit != nil {
+		m["monthly_transfer_limit"] = *c.MonthlyTransferLimit
+	}
+	return m
+}
+
+func toPendingLimitChange(c *model.LimitChange) *dto.PendingLimitChangeResponse {
+	if c == nil {
+		return nil
+	}
+	return &dto.PendingLimitChangeResponse{
+		DailyTransferLimit:   c.DailyTransferLimit,
+		MonthlyTransferLimit: c.MonthlyTransferLimit,
+		RequestedAt:          c.RequestedAt.Format(time.RFC3339),
+		EffectiveAt:          c.EffectiveAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+func amount(v float64) *float64 { return &v }
+
+func TestStageLimitChange(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+	svc := &UserService{limitIncreaseDelay: 24 * time.Hour}
+
+	t.Run("decrease applies at once", func(t *testing.T) {
+		settings := &model.UserSettings{UserID: "u", DailyTransferLimit: amount(50000)}
+		stage := svc.stageLimitChange(settings, nil, amount(20000), nil, now)
+		if *settings.DailyTransferLimit != 20000 || stage.next != nil || stage.changed || stage.raised {
+			t.Fatalf("settings=%v stage=%+v", *settings.DailyTransferLimit, stage)
+		}
+	})
+
+	t.Run("increase waits out the cooling-off", func(t *testing.T) {
+		settings := &model.UserSettings{UserID: "u", DailyTransferLimit: amount(50000), MonthlyTransferLimit: amount(500000)}
+		stage := svc.stageLimitChange(settings, nil, amount(100000), amount(0), now)
+		if *settings.DailyTransferLimit != 50000 || *settings.MonthlyTransferLimit != 500000 {
+			t.Fatalf("limits changed before cooling-off: %v %v", *settings.DailyTransferLimit, *settings.MonthlyTransferLimit)
+		}
+		if !stage.raised || stage.next == nil || *stage.next.DailyTransferLimit != 100000 || *stage.next.MonthlyTransferLimit != 0 ||
+			!stage.next.EffectiveAt.Equal(now.Add(24*time.Hour)) {
+			t.Fatalf("stage=%+v next=%+v", stage, stage.next)
+		}
+	})
+
+	t.Run("lowering drops that limit from the pending change", func(t *testing.T) {
+		settings := &model.UserSettings{UserID: "u", DailyTransferLimit: amount(50000), MonthlyTransferLimit: amount(500000)}
+		pending := &model.LimitChange{UserID: "u", DailyTransferLimit: amount(100000), MonthlyTransferLimit: amount(900000),
+			RequestedAt: earlier, EffectiveAt: earlier.Add(24 * time.Hour)}
+		stage := svc.stageLimitChange(settings, pending, amount(40000), nil, now)
+		if *settings.DailyTransferLimit != 40000 || !stage.changed || stage.raised {
+			t.Fatalf("settings=%v stage=%+v", *settings.DailyTransferLimit, stage)
+		}
+		if stage.next.DailyTransferLimit != nil || *stage.next.MonthlyTransferLimit != 900000 || !stage.next.EffectiveAt.Equal(pending.EffectiveAt) {
+			t.Fatalf("next=%+v", stage.next)
+		}
+	})
+
+	t.Run("unlimited cannot be raised", func(t *testing.T) {
+		settings := &model.UserSettings{UserID: "u"}
+		stage := svc.stageLimitChange(settings, nil, amount(100000), nil, now)
+		if settings.DailyTransferLimit == nil || *settings.DailyTransferLimit != 100000 || stage.next != nil {
+			t.Fatalf("settings=%v stage=%+v", settings.DailyTransferLimit, stage)
+		}
+	})
+
+	t.Run("no cooling-off configured", func(t *testing.T) {
+		settings := &model.UserSettings{UserID: "u", DailyTransferLimit: amount(50000)}
+		stage := (&UserService{}).stageLimitChange(settings, nil, amount(100000), nil, now)
+		if *settings.DailyTransferLimit != 100000 || stage.next != nil {
+			t.Fatalf("settings=%v stage=%+v", *settings.DailyTransferLimit, stage)
+		}
+	})
+}
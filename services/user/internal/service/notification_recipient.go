@@ -0,0 +1,21 @@
+package service
+
+import "github.com/abubakvr/payup-backend/services/user/internal/model"
+
+// NotificationRecipient returns the user's contact details and preferred language for the notification service,
+// which addresses and localizes templated notifications from them. Returns nil when the user does not exist.
+func (s *UserService) NotificationRecipient(userID string) (*model.User, string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, "", err
+	}
+	settings, err := s.userRepo.GetUserSettings(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	language := ""
+	if settings != nil && settings.Language != nil {
+		language = *settings.Language
+	}
+	return user, language, nil
+}
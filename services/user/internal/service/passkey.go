@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/webauthn"
+	"github.com/abubakvr/payup-backend/services/user/redis"
+	"github.com/google/uuid"
+)
+
+// Passkey ceremony purposes; a challenge issued for one cannot be answered for another.
+const (
+	passkeyRegister    = "register"
+	passkeyLogin       = "login"
+	passkeyTransaction = "transaction"
+	// passkeyCeremonyTTL leaves room over the client timeout (webauthn.DefaultTimeoutMs) for the round trips.
+	passkeyCeremonyTTL = 5 * time.Minute
+)
+
+var (
+	ErrPasskeyChallenge   = errors.New("passkey challenge expired or unknown; start again")
+	ErrPasskeyInvalid     = errors.New("passkey verification failed")
+	ErrPasskeyNotFound    = errors.New("passkey not found")
+	ErrPasskeyPaymentsOff = errors.New("passkey payments not enabled")
+	ErrPasskeyUnavailable = errors.New("passkeys are temporarily unavailable")
+)
+
+// passkeyCeremony is what is remembered between begin and finish.
+type passkeyCeremony struct {
+	Purpose   string  `json:"purpose"`
+	Challenge string  `json:"challenge"`
+	UserID    string  `json:"userId,omitempty"`
+	Amount    float64 `json:"amount,omitempty"`
+	Payee     *Payee  `json:"payee,omitempty"`
+}
+
+// Payee is who a passkey-approved payment goes to: bank code and account number for a transfer, biller code and
+// customer ID for a bill payment.
+type Payee struct {
+	BankCode      string `json:"bankCode"`
+	AccountNumber string `json:"accountNumber"`
+}
+
+func (p Payee) normalized() Payee {
+	return Payee{BankCode: strings.ToUpper(strings.TrimSpace(p.BankCode)), AccountNumber: strings.TrimSpace(p.AccountNumber)}
+}
+
+func (s *UserService) startPasskeyCeremony(ctx context.Context, c passkeyCeremony) (id, challenge string, err error) {
+	challenge, err = webauthn.NewChallenge()
+	if err != nil {
+		return "", "", err
+	}
+	c.Challenge = challenge
+	state, err := json.Marshal(c)
+	if err != nil {
+		return "", "", err
+	}
+	id = uuid.NewString()
+	if err := redis.SetWebAuthnCeremony(ctx, id, state, passkeyCeremonyTTL); err != nil {
+		log.Printf("user service: store passkey ceremony err=%v", err)
+		return "", "", ErrPasskeyUnavailable
+	}
+	return id, challenge, nil
+}
+
+// finishPasskeyCeremony consumes the ceremony; each challenge can be answered once.
+func (s *UserService) finishPasskeyCeremony(ctx context.Context, id, purpose string) (*passkeyCeremony, error) {
+	state, err := redis.ConsumeWebAuthnCeremony(ctx, id)
+	if err != nil {
+		log.Printf("user service: load passkey ceremony err=%v", err)
+		return nil, ErrPasskeyUnavailable
+	}
+	var c passkeyCeremony
+	if state == nil || json.Unmarshal(state, &c) != nil || c.Purpose != purpose {
+		return nil, ErrPasskeyChallenge
+	}
+	return &c, nil
+}
+
+// passkeyUserHandle is the opaque user.id given to authenticators: the 16 bytes of the user UUID, no personal data.
+func passkeyUserHandle(userID string) []byte {
+	if id, err := uuid.Parse(userID); err == nil {
+		return id[:]
+	}
+	return []byte(userID)
+}
+
+// BeginPasskeyRegistration returns creation options for a new passkey after the password or a register_passkey
+// step-up token: a passkey signs in without the password or 2FA.
+func (s *UserService) BeginPasskeyRegistration(ctx context.Context, userID string, auth SensitiveAuth) (*dto.PasskeyCeremonyResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if err := s.confirmSensitive(ctx, user, StepUpRegisterPasskey, auth); err != nil {
+		return nil, err
+	}
+	existing, err := s.userRepo.ListPasskeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	exclude := make([]webauthn.Descriptor, 0, len(existing))
+	for _, p := range existing {
+		exclude = append(exclude, webauthn.NewDescriptor(p.CredentialID, p.Transports))
+	}
+	id, challenge, err := s.startPasskeyCeremony(ctx, passkeyCeremony{Purpose: passkeyRegister, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	displayName := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if displayName == "" {
+		displayName = user.Email
+	}
+	return &dto.PasskeyCeremonyResponse{
+		ChallengeID: id,
+		PublicKey:   s.passkeys.CreationOptions(challenge, passkeyUserHandle(userID), user.Email, displayName, exclude),
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's response and stores the passkey. The first passkey also
+// turns on passkey payments.
+func (s *UserService) FinishPasskeyRegistration(ctx context.Context, userID string, req *dto.PasskeyRegisterFinishRequest) (*dto.PasskeyResponse, error) {
+	c, err := s.finishPasskeyCeremony(ctx, req.ChallengeID, passkeyRegister)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserID != userID {
+		return nil, ErrPasskeyChallenge
+	}
+	cred, err := s.passkeys.VerifyRegistration(&req.Credential, c.Challenge, true)
+	if err != nil {
+		log.Printf("user service: passkey registration rejected user=%s err=%v", userID, err)
+		return nil, ErrPasskeyInvalid
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "Passkey"
+	}
+	p, err := s.userRepo.CreatePasskey(&model.Passkey{
+		UserID:         userID,
+		CredentialID:   cred.ID,
+		PublicKey:      cred.PublicKey,
+		Algorithm:      cred.Algorithm,
+		SignCount:      cred.SignCount,
+		AAGUID:         cred.AAGUID,
+		Transports:     cred.Transports,
+		Name:           name,
+		BackupEligible: cred.BackupEligible,
+		BackedUp:       cred.BackedUp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "passkey_registered",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"passkey_id": p.ID, "name": p.Name, "attestation": cred.AttestationFmt, "backed_up": p.BackedUp},
+	})
+	if user, err := s.userRepo.GetUserByID(userID); err == nil && user != nil {
+		s.notifyPasskeyAdded(user, p.Name)
+	}
+	resp := toPasskeyResponse(p)
+	return &resp, nil
+}
+
+// notifyPasskeyAdded emails the user: a passkey they did not add gives someone else password-free access.
+func (s *UserService) notifyPasskeyAdded(user *model.User, name string) {
+	body := "A passkey (" + name + ") was added to your PayUp account. It can be used to sign in and approve payments. " +
+		"If this wasn't you, remove it under Security > Passkeys and change your password."
+	_ = s.sendNotification(kafka.NotificationEvent{
+		UserID:  user.ID,
+		Type:    "passkey_added",
+		Channel: "email",
+		Metadata: map[string]interface{}{
+			"to":      user.Email,
+			"subject": "A passkey was added to your PayUp account",
+			"body":    body,
+			"html":    "<p>" + html.EscapeString(body) + "</p>",
+		},
+	})
+}
+
+// ListPasskeys returns the user's passkeys.
+func (s *UserService) ListPasskeys(ctx context.Context, userID string) ([]dto.PasskeyResponse, error) {
+	passkeys, err := s.userRepo.ListPasskeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.PasskeyResponse, 0, len(passkeys))
+	for i := range passkeys {
+		out = append(out, toPasskeyResponse(&passkeys[i]))
+	}
+	return out, nil
+}
+
+// DeletePasskey removes one of the user's passkeys.
+func (s *UserService) DeletePasskey(ctx context.Context, userID, passkeyID string) error {
+	if _, err := uuid.Parse(passkeyID); err != nil {
+		return ErrPasskeyNotFound
+	}
+	ok, err := s.userRepo.DeletePasskey(userID, passkeyID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPasskeyNotFound
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "passkey_removed",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"passkey_id": passkeyID},
+	})
+	return nil
+}
+
+// BeginPasskeyLogin returns request options for passwordless login. No user is named: the authenticator offers the
+// passkeys it holds for PayUp and reports which account in the user handle.
+func (s *UserService) BeginPasskeyLogin(ctx context.Context) (*dto.PasskeyCeremonyResponse, error) {
+	id, challenge, err := s.startPasskeyCeremony(ctx, passkeyCeremony{Purpose: passkeyLogin})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyCeremonyResponse{ChallengeID: id, PublicKey: s.passkeys.RequestOptions(challenge, nil)}, nil
+}
+
+// FinishPasskeyLogin verifies a passkey assertion and starts a session. A user-verified passkey stands for both
+// factors, so 2FA is not asked for. Failures count towards the per-IP login limit.
+func (s *UserService) FinishPasskeyLogin(ctx context.Context, req *dto.PasskeyAssertionRequest, device model.DeviceInfo) (*model.LoginResponse, error) {
+	if err := s.limits.loginIP.Check(ctx, device.IPAddress); err != nil {
+		return nil, err
+	}
+	c, err := s.finishPasskeyCeremony(ctx, req.ChallengeID, passkeyLogin)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.passkeyForAssertion(&req.Credential)
+	if err == nil {
+		err = s.verifyPasskeyAssertion(ctx, p, &req.Credential, c.Challenge)
+	}
+	if err != nil {
+		if errors.Is(err, ErrPasskeyInvalid) {
+			if lockErr := s.limits.loginIP.Fail(ctx, device.IPAddress); lockErr != nil {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrPasskeyInvalid
+	}
+	if !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	resp, session, err := s.startSession(user, device)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "login",
+		Entity:   "user",
+		EntityID: user.ID,
+		UserID:   &user.ID,
+		Metadata: map[string]interface{}{"email": user.Email, "method": "passkey", "passkey_id": p.ID, "session_id": session.ID, "device_id": session.Device.DeviceID},
+	})
+	redis.SetUserExists(ctx, user.ID, s.userExistsCacheTTL)
+	return resp, nil
+}
+
+// BeginPasskeyTransaction returns request options to approve a payment of amount to payee with one of the user's
+// passkeys. The assertion is sent to payment in place of the PIN and checked by ValidateTransfer.
+func (s *UserService) BeginPasskeyTransaction(ctx context.Context, userID string, amount float64, payee Payee) (*dto.PasskeyCeremonyResponse, error) {
+	payee = payee.normalized()
+	if payee.BankCode == "" || payee.AccountNumber == "" {
+		return nil, errors.New("beneficiary bank code and account number required")
+	}
+	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
+	if err != nil || settings == nil {
+		return nil, errors.New("user settings not found")
+	}
+	if !settings.BiometricEnabled {
+		return nil, ErrPasskeyPaymentsOff
+	}
+	passkeys, err := s.userRepo.ListPasskeys(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(passkeys) == 0 {
+		return nil, ErrPasskeyPaymentsOff
+	}
+	allow := make([]webauthn.Descriptor, 0, len(passkeys))
+	for _, p := range passkeys {
+		allow = append(allow, webauthn.NewDescriptor(p.CredentialID, p.Transports))
+	}
+	id, challenge, err := s.startPasskeyCeremony(ctx, passkeyCeremony{Purpose: passkeyTransaction, UserID: userID, Amount: amount, Payee: &payee})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyCeremonyResponse{ChallengeID: id, PublicKey: s.passkeys.RequestOptions(challenge, allow)}, nil
+}
+
+// verifyTransferPasskey checks a passkey approval (JSON of dto.PasskeyAssertionRequest) for a payment of amount to
+// payee. The challenge is consumed only once everything matches and the signature verifies, so a request for another
+// payee or amount cannot burn the approval of the payment the user meant to make.
+func (s *UserService) verifyTransferPasskey(ctx context.Context, userID string, amount float64, payee Payee, assertion string) error {
+	var req dto.PasskeyAssertionRequest
+	if err := json.Unmarshal([]byte(assertion), &req); err != nil || req.ChallengeID == "" {
+		return ErrPasskeyInvalid
+	}
+	state, err := redis.GetWebAuthnCeremony(ctx, req.ChallengeID)
+	if err != nil {
+		log.Printf("user service: load passkey ceremony err=%v", err)
+		return ErrPasskeyUnavailable
+	}
+	var c passkeyCeremony
+	if state == nil || json.Unmarshal(state, &c) != nil || c.Purpose != passkeyTransaction {
+		return ErrPasskeyChallenge
+	}
+	if c.UserID != userID || math.Abs(c.Amount-amount) >= 0.005 || c.Payee == nil || *c.Payee != payee.normalized() {
+		return errors.New("passkey approval is for a different payment")
+	}
+	p, err := s.passkeyForAssertion(&req.Credential)
+	if err != nil {
+		return err
+	}
+	if p.UserID != userID {
+		return ErrPasskeyInvalid
+	}
+	if err := s.verifyPasskeyAssertion(ctx, p, &req.Credential, c.Challenge); err != nil {
+		return err
+	}
+	// A concurrent request may have used the same approval between the read and here; only one consumes it.
+	if _, err := s.finishPasskeyCeremony(ctx, req.ChallengeID, passkeyTransaction); err != nil {
+		return err
+	}
+	return nil
+}
+
+// passkeyForAssertion looks up the stored passkey the assertion names and checks the user handle, when sent, is its owner.
+func (s *UserService) passkeyForAssertion(resp *webauthn.CredentialAssertionResponse) (*model.Passkey, error) {
+	credID, err := resp.CredentialID()
+	if err != nil {
+		return nil, ErrPasskeyInvalid
+	}
+	p, err := s.userRepo.GetPasskeyByCredentialID(credID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPasskeyInvalid
+	}
+	if handle, err := resp.UserHandleBytes(); err != nil || (len(handle) > 0 && string(handle) != string(passkeyUserHandle(p.UserID))) {
+		return nil, ErrPasskeyInvalid
+	}
+	return p, nil
+}
+
+// verifyPasskeyAssertion verifies the signature with user verification and records the new signature counter. A
+// counter that went backwards means the credential may have been copied; it is rejected and audited.
+func (s *UserService) verifyPasskeyAssertion(ctx context.Context, p *model.Passkey, resp *webauthn.CredentialAssertionResponse, challenge string) error {
+	a, err := s.passkeys.VerifyAssertion(resp, challenge, p.PublicKey, p.SignCount, true)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCount) {
+			_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+				Service:  "user",
+				Action:   "passkey_clone_suspected",
+				Entity:   "user",
+				EntityID: p.UserID,
+				UserID:   &p.UserID,
+				Metadata: map[string]interface{}{"passkey_id": p.ID, "stored_sign_count": p.SignCount},
+			})
+		}
+		log.Printf("user service: passkey assertion rejected user=%s passkey=%s err=%v", p.UserID, p.ID, err)
+		return ErrPasskeyInvalid
+	}
+	if err := s.userRepo.RecordPasskeyUse(p.ID, a.SignCount, a.BackedUp); err != nil {
+		log.Printf("user service: record passkey use passkey=%s err=%v", p.ID, err)
+	}
+	return nil
+}
+
+func toPasskeyResponse(p *model.Passkey) dto.PasskeyResponse {
+	resp := dto.PasskeyResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		Transports: p.Transports,
+		BackedUp:   p.BackedUp,
+		CreatedAt:  p.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if p.LastUsedAt != nil {
+		t := p.LastUsedAt.UTC().Format(time.RFC3339)
+		resp.LastUsedAt = &t
+	}
+	return resp
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+// ErrPushTokenNotFound is returned when the user has no such push token.
+var ErrPushTokenNotFound = errors.New("push token not found")
+
+// RegisterPushToken stores the app's device token so the notification service can reach this device. Registering the
+// same token again just refreshes it; a token last registered by another account moves to this one.
+func (s *UserService) RegisterPushToken(userID string, req dto.RegisterPushTokenRequest) error {
+	if err := s.userRepo.UpsertPushToken(userID, req.Token, req.Platform, req.DeviceID); err != nil {
+		return err
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "push_token_registered",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"platform": req.Platform, "device_id": req.DeviceID},
+	})
+	return nil
+}
+
+// DeletePushToken stops push notifications to one device (the app calls it on sign-out).
+func (s *UserService) DeletePushToken(userID, token string) error {
+	found, err := s.userRepo.DeletePushToken(userID, token)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrPushTokenNotFound
+	}
+	return nil
+}
+
+// ListPushTokens returns the devices registered for push.
+func (s *UserService) ListPushTokens(userID string) ([]dto.PushTokenResponse, error) {
+	tokens, err := s.userRepo.ListPushTokens(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.PushTokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		out = append(out, dto.PushTokenResponse{
+			ID:         t.ID,
+			Platform:   t.Platform,
+			DeviceID:   t.DeviceID,
+			CreatedAt:  t.CreatedAt.UTC().Format(time.RFC3339),
+			LastSeenAt: t.LastSeenAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
+
+// PushTokensForUser returns the raw tokens for fan-out (notification service, over gRPC).
+func (s *UserService) PushTokensForUser(userID string) ([]model.PushToken, error) {
+	return s.userRepo.ListPushTokens(userID)
+}
+
+// RemoveInvalidPushTokens drops tokens FCM or APNs rejected as unregistered or malformed.
+func (s *UserService) RemoveInvalidPushTokens(tokens []string, reason string) (int64, error) {
+	n, err := s.userRepo.DeletePushTokens(tokens)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "push_tokens_pruned",
+			Entity:   "push_token",
+			Metadata: map[string]interface{}{"removed": n, "reason": reason},
+		})
+	}
+	return n, nil
+}
+
+// dropDevicePushTokens stops push to a device whose session was revoked, so a signed-out phone gets no more alerts.
+func (s *UserService) dropDevicePushTokens(userID, deviceID string) {
+	if deviceID == "" {
+		return
+	}
+	if err := s.userRepo.DeletePushTokensForDevice(userID, deviceID); err != nil {
+		log.Printf("user service: drop push tokens user=%s device=%s err=%v", userID, deviceID, err)
+	}
+}
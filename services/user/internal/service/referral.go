@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+)
+
+const (
+	referralCodeLength = 8
+	// referralCodeAlphabet leaves out 0/O and 1/I/L so codes survive being read out loud.
+	referralCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	// referralRewardLease keeps a claimed reward away from other sweeps while its credit is in flight.
+	referralRewardLease       = 10 * time.Minute
+	referralRewardMaxAttempts = 10
+	referralRewardBatch       = 50
+	referralListLimit         = 100
+)
+
+// Referral flag reasons.
+const (
+	referralFlagPhoneReused          = "phone_reused"
+	referralFlagDeviceOfReferrer     = "device_shared_with_referrer"
+	referralFlagDeviceOfOtherReferee = "device_shared_with_other_referee"
+	referralFlagReferrerCap          = "referrer_limit_reached"
+)
+
+// ErrInvalidReferralCode is returned at registration for a referral code no active user owns.
+var ErrInvalidReferralCode = errors.New("invalid referral code")
+
+// referralStore is the part of the user repository that records milestones and queues and pays rewards.
+type referralStore interface {
+	MarkReferralMilestone(refereeID, milestone string, at time.Time) (*model.Referral, error)
+	FlagReferral(id, reason string) error
+	ReferralPhoneReused(ref *model.Referral) (bool, error)
+	ReferralDeviceShared(ref *model.Referral) (withReferrer, withOtherReferee bool, err error)
+	CountRewardedReferrals(referrerID string) (int, error)
+	GrantReferralRewards(referralID string, rewards []model.ReferralReward) (int, error)
+	ClaimDueReferralRewards(now time.Time, lease time.Duration, limit int) ([]model.ReferralReward, error)
+	MarkReferralRewardPaid(id, transactionRef string, now time.Time) error
+	RetryReferralReward(id, lastError string, retryAt time.Time) error
+	FailReferralReward(id, lastError string) error
+}
+
+// WalletCreditor pays referral rewards into wallets; *clients.PaymentClient is one. A repeat with the same
+// idempotencyKey must return the original credit.
+type WalletCreditor interface {
+	CreditWallet(ctx context.Context, userID string, amount float64, narration, idempotencyKey string) (*paymentpb.DebitCreditWalletResponse, error)
+}
+
+// ReferralConfig sets the reward rules and where rewards are paid. No rules, or no payment client, leaves referrals
+// attributed but unrewarded (rewards wait in the queue for a client). MaxRewardedPerReferrer caps how many of one
+// user's referrals earn rewards; 0 is no cap.
+type ReferralConfig struct {
+	Rules                  []model.ReferralRule
+	MaxRewardedPerReferrer int
+	Payment                WalletCreditor
+}
+
+// DefaultReferralRules credits ₦500 to both sides once the referee's KYC is approved and their first transfer succeeds.
+func DefaultReferralRules() []model.ReferralRule {
+	return []model.ReferralRule{{
+		Name:           "kyc_and_first_transfer",
+		Requires:       []string{model.ReferralMilestoneKYCApproved, model.ReferralMilestoneFirstTransfer},
+		ReferrerAmount: 500,
+		RefereeAmount:  500,
+	}}
+}
+
+// referralMilestones maps the Kafka events the program listens to onto referral milestones.
+var referralMilestones = map[string]string{
+	"kyc_approved":     model.ReferralMilestoneKYCApproved,
+	"transfer_success": model.ReferralMilestoneFirstTransfer,
+}
+
+// normalizeReferralCode upper-cases and trims a code typed by a user.
+func normalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// resolveReferralCode returns the id of the user owning code, or ErrInvalidReferralCode.
+func (s *UserService) resolveReferralCode(code string) (string, error) {
+	if len(code) != referralCodeLength {
+		return "", ErrInvalidReferralCode
+	}
+	referrerID, err := s.userRepo.GetUserIDByReferralCode(code)
+	if err != nil {
+		return "", err
+	}
+	if referrerID == "" {
+		return "", ErrInvalidReferralCode
+	}
+	return referrerID, nil
+}
+
+// attributeReferral records that userID registered with referrerID's code. Registration has already succeeded, so a
+// failure is only logged.
+func (s *UserService) attributeReferral(referrerID, userID, code, phoneHash string) {
+	if err := s.userRepo.CreateReferral(referrerID, userID, code, phoneHash); err != nil {
+		log.Printf("user service: attribute referral user=%s referrer=%s: %v", userID, referrerID, err)
+		return
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "referral_attributed",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"referrer_id": referrerID, "code": code},
+	})
+}
+
+// ensureReferralCode returns the user's referral code, generating one the first time it is asked for.
+func (s *UserService) ensureReferralCode(userID string) (string, error) {
+	code, err := s.userRepo.GetReferralCode(userID)
+	if err != nil || code != "" {
+		return code, err
+	}
+	for i := 0; i < 5; i++ {
+		candidate, err := generateReferralCode()
+		if err != nil {
+			return "", err
+		}
+		code, err = s.userRepo.AssignReferralCode(userID, candidate)
+		if errors.Is(err, repository.ErrReferralCodeTaken) {
+			continue
+		}
+		return code, err
+	}
+	return "", errors.New("could not allocate a referral code")
+}
+
+func generateReferralCode() (string, error) {
+	b := make([]byte, referralCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = referralCodeAlphabet[int(b[i])%len(referralCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// GetReferralSummary returns the user's referral code (created on first use), the reward rules and the users they
+// referred.
+func (s *UserService) GetReferralSummary(userID string) (*dto.ReferralSummaryResponse, error) {
+	code, err := s.ensureReferralCode(userID)
+	if err != nil {
+		return nil, err
+	}
+	refs, earned, err := s.userRepo.ListReferralsByReferrer(userID, referralListLimit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.userRepo.SumReferralRewardsPaid(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := &dto.ReferralSummaryResponse{
+		Code:        code,
+		Rules:       make([]dto.ReferralRuleResponse, 0, len(s.referrals.Rules)),
+		TotalEarned: total,
+		Referrals:   make([]dto.ReferralResponse, 0, len(refs)),
+	}
+	for _, rule := range s.referrals.Rules {
+		out.Rules = append(out.Rules, dto.ReferralRuleResponse{
+			Name:           rule.Name,
+			Requires:       rule.Requires,
+			ReferrerAmount: rule.ReferrerAmount,
+			RefereeAmount:  rule.RefereeAmount,
+		})
+	}
+	for i, ref := range refs {
+		status := ref.Status
+		if status == model.ReferralFlagged {
+			status = "under_review"
+		}
+		out.Referrals = append(out.Referrals, dto.ReferralResponse{
+			ID:            ref.ID,
+			Status:        status,
+			KYCApproved:   ref.KYCApprovedAt != nil,
+			FirstTransfer: ref.FirstTransferAt != nil,
+			Earned:        earned[i],
+			CreatedAt:     ref.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// HandleReferralEvent records a milestone event (kyc_approved, transfer_success) for a referred user and grants the
+// rewards of every rule whose milestones are now all reached. Events for users who were not referred, and other event
+// types, are ignored. Replays grant nothing new.
+func (s *UserService) HandleReferralEvent(ctx context.Context, eventType, userID string) error {
+	milestone, ok := referralMilestones[eventType]
+	if !ok || len(s.referrals.Rules) == 0 {
+		return nil
+	}
+	ref, err := s.referralRepo.MarkReferralMilestone(userID, milestone, time.Now())
+	if err != nil || ref == nil || ref.Status == model.ReferralFlagged {
+		return err
+	}
+	var rewards []model.ReferralReward
+	for _, rule := range s.referrals.Rules {
+		if !ruleSatisfied(rule, ref) {
+			continue
+		}
+		if rule.ReferrerAmount > 0 {
+			rewards = append(rewards, model.ReferralReward{Rule: rule.Name, Beneficiary: model.ReferralBeneficiaryReferrer, UserID: ref.ReferrerID, Amount: rule.ReferrerAmount})
+		}
+		if rule.RefereeAmount > 0 {
+			rewards = append(rewards, model.ReferralReward{Rule: rule.Name, Beneficiary: model.ReferralBeneficiaryReferee, UserID: ref.RefereeID, Amount: rule.RefereeAmount})
+		}
+	}
+	if len(rewards) == 0 {
+		return nil
+	}
+	// Abuse checks run once, when the referral first qualifies; by then the referee has signed in on their devices.
+	if ref.Status == model.ReferralPending {
+		reason, err := s.referralAbuseReason(ref)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			if err := s.referralRepo.FlagReferral(ref.ID, reason); err != nil {
+				return err
+			}
+			s.auditReferral("referral_flagged", ref, map[string]interface{}{"reason": reason})
+			return nil
+		}
+	}
+	granted, err := s.referralRepo.GrantReferralRewards(ref.ID, rewards)
+	if err != nil {
+		return err
+	}
+	if granted > 0 {
+		s.auditReferral("referral_rewards_granted", ref, map[string]interface{}{"milestone": milestone, "rewards": granted})
+	}
+	return nil
+}
+
+func ruleSatisfied(rule model.ReferralRule, ref *model.Referral) bool {
+	if len(rule.Requires) == 0 {
+		return false
+	}
+	for _, m := range rule.Requires {
+		if !ref.Reached(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// referralAbuseReason returns why the referral should not be rewarded, or "" if it passes every check.
+func (s *UserService) referralAbuseReason(ref *model.Referral) (string, error) {
+	if reused, err := s.referralRepo.ReferralPhoneReused(ref); err != nil {
+		return "", err
+	} else if reused {
+		return referralFlagPhoneReused, nil
+	}
+	withReferrer, withOtherReferee, err := s.referralRepo.ReferralDeviceShared(ref)
+	if err != nil {
+		return "", err
+	}
+	if withReferrer {
+		return referralFlagDeviceOfReferrer, nil
+	}
+	if withOtherReferee {
+		return referralFlagDeviceOfOtherReferee, nil
+	}
+	if max := s.referrals.MaxRewardedPerReferrer; max > 0 {
+		n, err := s.referralRepo.CountRewardedReferrals(ref.ReferrerID)
+		if err != nil {
+			return "", err
+		}
+		if n >= max {
+			return referralFlagReferrerCap, nil
+		}
+	}
+	return "", nil
+}
+
+// ProcessReferralRewards pays queued referral rewards into the beneficiaries' wallets through the payment service.
+// Each credit carries the reward id as its idempotency key, so a payout retried after a timeout is not paid twice.
+// Run periodically; returns how many rewards were paid.
+func (s *UserService) ProcessReferralRewards(ctx context.Context) (int, error) {
+	paid := 0
+	for ctx.Err() == nil {
+		due, err := s.referralRepo.ClaimDueReferralRewards(time.Now(), referralRewardLease, referralRewardBatch)
+		if err != nil {
+			return paid, err
+		}
+		for i := range due {
+			rw := &due[i]
+			err := s.payReferralReward(ctx, rw)
+			if err == nil {
+				paid++
+				continue
+			}
+			log.Printf("user service: referral reward=%s user=%s attempt=%d: %v", rw.ID, rw.UserID, rw.Attempts, err)
+			if rw.Attempts >= referralRewardMaxAttempts {
+				_ = s.referralRepo.FailReferralReward(rw.ID, err.Error())
+				s.auditReferralReward("referral_reward_failed", rw, map[string]interface{}{"error": err.Error()})
+				continue
+			}
+			// Most failures are a beneficiary without an active wallet yet; back off up to a day.
+			backoff := time.Duration(1<<uint(rw.Attempts)) * 5 * time.Minute
+			if backoff > 24*time.Hour {
+				backoff = 24 * time.Hour
+			}
+			_ = s.referralRepo.RetryReferralReward(rw.ID, err.Error(), time.Now().Add(backoff))
+		}
+		if len(due) < referralRewardBatch {
+			break
+		}
+	}
+	return paid, ctx.Err()
+}
+
+func (s *UserService) payReferralReward(ctx context.Context, rw *model.ReferralReward) error {
+	c := s.referrals.Payment
+	if c == nil {
+		return errors.New("payment service not configured")
+	}
+	resp, err := c.CreditWallet(ctx, rw.UserID, rw.Amount, "PayUp referral reward", "referral-reward:"+rw.ID)
+	if err != nil {
+		return fmt.Errorf("credit wallet: %w", err)
+	}
+	if !resp.GetSuccess() {
+		return fmt.Errorf("credit wallet: %s", resp.GetErrorMessage())
+	}
+	if err := s.referralRepo.MarkReferralRewardPaid(rw.ID, resp.GetTransactionRef(), time.Now()); err != nil {
+		return err
+	}
+	s.auditReferralReward("referral_reward_paid", rw, map[string]interface{}{"transaction_ref": resp.GetTransactionRef()})
+	return nil
+}
+
+func (s *UserService) auditReferral(action string, ref *model.Referral, metadata map[string]interface{}) {
+	metadata["referrer_id"] = ref.ReferrerID
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   action,
+		Entity:   "referral",
+		EntityID: ref.ID,
+		UserID:   &ref.RefereeID,
+		Metadata: metadata,
+	})
+}
+
+func (s *UserService) auditReferralReward(action string, rw *model.ReferralReward, metadata map[string]interface{}) {
+	metadata["referral_id"] = rw.ReferralID
+	metadata["rule"] = rw.Rule
+	metadata["beneficiary"] = rw.Beneficiary
+	metadata["amount"] = rw.Amount
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   action,
+		Entity:   "referral_reward",
+		EntityID: rw.ID,
+		UserID:   &rw.UserID,
+		Metadata: metadata,
+	})
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	paymentpb "github.com/abubakvr/payup-backend/proto/payment"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+// fakeReferrals keeps referrals and rewards in memory with the repository's rules: one reward per referral, rule and
+// side, and claimed rewards leased until their next attempt.
+type fakeReferrals struct {
+	referrals   map[string]*model.Referral // by referee id
+	rewards     []*model.ReferralReward
+	due         map[string]time.Time // next attempt by reward id
+	phoneReused bool
+	device      [2]bool // shared with the referrer, with another referee
+	rewarded    int     // the referrer's referrals already rewarded
+}
+
+func newFakeReferrals() *fakeReferrals {
+	return &fakeReferrals{referrals: map[string]*model.Referral{}, due: map[string]time.Time{}}
+}
+
+func (f *fakeReferrals) refer(referrerID, refereeID string) *model.Referral {
+	ref := &model.Referral{ID: "ref-" + refereeID, ReferrerID: referrerID, RefereeID: refereeID, Status: model.ReferralPending}
+	f.referrals[refereeID] = ref
+	return ref
+}
+
+func (f *fakeReferrals) MarkReferralMilestone(refereeID, milestone string, at time.Time) (*model.Referral, error) {
+	ref, ok := f.referrals[refereeID]
+	if !ok {
+		return nil, nil
+	}
+	switch milestone {
+	case model.ReferralMilestoneKYCApproved:
+		if ref.KYCApprovedAt == nil {
+			ref.KYCApprovedAt = &at
+		}
+	case model.ReferralMilestoneFirstTransfer:
+		if ref.FirstTransferAt == nil {
+			ref.FirstTransferAt = &at
+		}
+	}
+	cp := *ref
+	return &cp, nil
+}
+
+func (f *fakeReferrals) FlagReferral(id, reason string) error {
+	for _, ref := range f.referrals {
+		if ref.ID == id {
+			ref.Status, ref.FlaggedReason = model.ReferralFlagged, reason
+		}
+	}
+	return nil
+}
+
+func (f *fakeReferrals) ReferralPhoneReused(*model.Referral) (bool, error) { return f.phoneReused, nil }
+
+func (f *fakeReferrals) ReferralDeviceShared(*model.Referral) (bool, bool, error) {
+	return f.device[0], f.device[1], nil
+}
+
+func (f *fakeReferrals) CountRewardedReferrals(string) (int, error) { return f.rewarded, nil }
+
+func (f *fakeReferrals) GrantReferralRewards(referralID string, rewards []model.ReferralReward) (int, error) {
+	created := 0
+	for _, rw := range rewards {
+		dup := false
+		for _, have := range f.rewards {
+			dup = dup || (have.ReferralID == referralID && have.Rule == rw.Rule && have.Beneficiary == rw.Beneficiary)
+		}
+		if dup {
+			continue
+		}
+		rw.ID, rw.ReferralID, rw.Status = fmt.Sprintf("rw-%d", len(f.rewards)+1), referralID, model.ReferralRewardPending
+		f.rewards = append(f.rewards, &rw)
+		created++
+	}
+	for _, ref := range f.referrals {
+		if ref.ID == referralID && ref.Status == model.ReferralPending {
+			ref.Status = model.ReferralRewarded
+		}
+	}
+	return created, nil
+}
+
+func (f *fakeReferrals) ClaimDueReferralRewards(now time.Time, lease time.Duration, limit int) ([]model.ReferralReward, error) {
+	var out []model.ReferralReward
+	for _, rw := range f.rewards {
+		if rw.Status != model.ReferralRewardPending || f.due[rw.ID].After(now) || len(out) == limit {
+			continue
+		}
+		rw.Attempts++
+		f.due[rw.ID] = now.Add(lease)
+		out = append(out, *rw)
+	}
+	return out, nil
+}
+
+func (f *fakeReferrals) reward(id string) *model.ReferralReward {
+	for _, rw := range f.rewards {
+		if rw.ID == id {
+			return rw
+		}
+	}
+	return nil
+}
+
+func (f *fakeReferrals) MarkReferralRewardPaid(id, transactionRef string, now time.Time) error {
+	rw := f.reward(id)
+	rw.Status, rw.TransactionRef, rw.PaidAt = model.ReferralRewardPaid, transactionRef, &now
+	return nil
+}
+
+func (f *fakeReferrals) RetryReferralReward(id, lastError string, retryAt time.Time) error {
+	f.reward(id).LastError, f.due[id] = lastError, retryAt
+	return nil
+}
+
+func (f *fakeReferrals) FailReferralReward(id, lastError string) error {
+	rw := f.reward(id)
+	rw.Status, rw.LastError = model.ReferralRewardFailed, lastError
+	return nil
+}
+
+// fakeCreditor is the payment service: it credits each idempotency key once and can fail the next calls.
+type fakeCreditor struct {
+	keys     []string        // idempotency key of every call
+	credited map[string]bool // keys that moved money
+	failNext int
+}
+
+func (c *fakeCreditor) CreditWallet(_ context.Context, _ string, _ float64, _, key string) (*paymentpb.DebitCreditWalletResponse, error) {
+	c.keys = append(c.keys, key)
+	if c.failNext > 0 {
+		c.failNext--
+		return nil, errors.New("context deadline exceeded")
+	}
+	c.credited[key] = true
+	return &paymentpb.DebitCreditWalletResponse{Success: true, TransactionRef: "TXN-" + key}, nil
+}
+
+func newReferralService(max int) (*UserService, *fakeReferrals, *fakeCreditor) {
+	store, creditor := newFakeReferrals(), &fakeCreditor{credited: map[string]bool{}}
+	svc := &UserService{
+		referralRepo: store,
+		referrals:    ReferralConfig{Rules: DefaultReferralRules(), MaxRewardedPerReferrer: max, Payment: creditor},
+	}
+	return svc, store, creditor
+}
+
+func TestHandleReferralEvent(t *testing.T) {
+	ctx := context.Background()
+	svc, store, _ := newReferralService(0)
+	store.refer("referrer", "referee")
+
+	if err := svc.HandleReferralEvent(ctx, "kyc_approved", "referee"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.rewards) != 0 {
+		t.Fatalf("rewards after KYC only = %d, want 0 until the first transfer", len(store.rewards))
+	}
+	if err := svc.HandleReferralEvent(ctx, "transfer_success", "referee"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.rewards) != 2 || store.referrals["referee"].Status != model.ReferralRewarded {
+		t.Fatalf("rewards = %d, status = %s; want both sides rewarded", len(store.rewards), store.referrals["referee"].Status)
+	}
+
+	// Replays of either event, and events for users nobody referred, grant nothing.
+	for _, ev := range []struct{ typ, user string }{
+		{"transfer_success", "referee"}, {"kyc_approved", "referee"}, {"transfer_success", "referee"},
+		{"transfer_success", "someone-else"}, {"user_registered", "referee"},
+	} {
+		if err := svc.HandleReferralEvent(ctx, ev.typ, ev.user); err != nil {
+			t.Fatalf("%s %s: %v", ev.typ, ev.user, err)
+		}
+	}
+	if len(store.rewards) != 2 {
+		t.Errorf("rewards after replays = %d, want 2", len(store.rewards))
+	}
+}
+
+func TestHandleReferralEventAbuse(t *testing.T) {
+	cases := []struct {
+		name     string
+		max      int
+		setup    func(*fakeReferrals)
+		wantFlag string // "" means rewarded
+	}{
+		{"clean", 0, func(*fakeReferrals) {}, ""},
+		{"phone reused", 0, func(f *fakeReferrals) { f.phoneReused = true }, referralFlagPhoneReused},
+		{"device of referrer", 0, func(f *fakeReferrals) { f.device[0] = true }, referralFlagDeviceOfReferrer},
+		{"device of another referee", 0, func(f *fakeReferrals) { f.device[1] = true }, referralFlagDeviceOfOtherReferee},
+		{"referrer at cap", 3, func(f *fakeReferrals) { f.rewarded = 3 }, referralFlagReferrerCap},
+		{"referrer below cap", 3, func(f *fakeReferrals) { f.rewarded = 2 }, ""},
+		{"no cap", 0, func(f *fakeReferrals) { f.rewarded = 1000 }, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, store, _ := newReferralService(c.max)
+			store.refer("referrer", "referee")
+			c.setup(store)
+			for _, ev := range []string{"kyc_approved", "transfer_success", "transfer_success"} {
+				if err := svc.HandleReferralEvent(ctx, ev, "referee"); err != nil {
+					t.Fatalf("%s: %v", ev, err)
+				}
+			}
+			ref := store.referrals["referee"]
+			if c.wantFlag == "" {
+				if ref.Status != model.ReferralRewarded || len(store.rewards) != 2 {
+					t.Fatalf("status = %s (%s), rewards = %d; want rewarded", ref.Status, ref.FlaggedReason, len(store.rewards))
+				}
+				return
+			}
+			if ref.Status != model.ReferralFlagged || ref.FlaggedReason != c.wantFlag || len(store.rewards) != 0 {
+				t.Fatalf("status = %s (%s), rewards = %d; want flagged %s and nothing granted", ref.Status, ref.FlaggedReason, len(store.rewards), c.wantFlag)
+			}
+		})
+	}
+}
+
+func TestProcessReferralRewardsRetryKeepsKey(t *testing.T) {
+	ctx := context.Background()
+	svc, store, creditor := newReferralService(0)
+	store.refer("referrer", "referee")
+	for _, ev := range []string{"kyc_approved", "transfer_success"} {
+		if err := svc.HandleReferralEvent(ctx, ev, "referee"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first payout of each reward times out; the credit may or may not have landed.
+	creditor.failNext = 2
+	if paid, err := svc.ProcessReferralRewards(ctx); err != nil || paid != 0 {
+		t.Fatalf("first sweep paid %d, err %v", paid, err)
+	}
+	for _, rw := range store.rewards {
+		if rw.Status != model.ReferralRewardPending || !store.due[rw.ID].After(time.Now()) {
+			t.Fatalf("reward %s = %s due %s; want pending and backed off", rw.ID, rw.Status, store.due[rw.ID])
+		}
+		store.due[rw.ID] = time.Time{} // the backoff has passed
+	}
+	if paid, err := svc.ProcessReferralRewards(ctx); err != nil || paid != 2 {
+		t.Fatalf("second sweep paid %d, err %v", paid, err)
+	}
+
+	attempts := map[string]int{}
+	for _, key := range creditor.keys {
+		attempts[key]++
+	}
+	for _, rw := range store.rewards {
+		key := "referral-reward:" + rw.ID
+		if attempts[key] != 2 || !creditor.credited[key] {
+			t.Errorf("reward %s: %d calls with key %s, credited %v; want the retry to reuse the key", rw.ID, attempts[key], key, creditor.credited[key])
+		}
+		if rw.Status != model.ReferralRewardPaid || rw.TransactionRef != "TXN-"+key {
+			t.Errorf("reward %s = %s ref %s", rw.ID, rw.Status, rw.TransactionRef)
+		}
+	}
+	if len(attempts) != 2 {
+		t.Errorf("idempotency keys = %v, want one per reward", attempts)
+	}
+	if paid, _ := svc.ProcessReferralRewards(ctx); paid != 0 || len(creditor.keys) != 4 {
+		t.Errorf("sweep after payout paid %d and made %d calls", paid, len(creditor.keys))
+	}
+}
+
+func TestProcessReferralRewardsGivesUp(t *testing.T) {
+	ctx := context.Background()
+	svc, store, creditor := newReferralService(0)
+	store.refer("referrer", "referee")
+	for _, ev := range []string{"kyc_approved", "transfer_success"} {
+		if err := svc.HandleReferralEvent(ctx, ev, "referee"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	creditor.failNext = 1 << 30
+	for i := 0; i < referralRewardMaxAttempts; i++ {
+		if _, err := svc.ProcessReferralRewards(ctx); err != nil {
+			t.Fatal(err)
+		}
+		for _, rw := range store.rewards {
+			store.due[rw.ID] = time.Time{}
+		}
+	}
+	for _, rw := range store.rewards {
+		if rw.Status != model.ReferralRewardFailed || rw.Attempts != referralRewardMaxAttempts {
+			t.Errorf("reward %s = %s after %d attempts, want failed after %d", rw.ID, rw.Status, rw.Attempts, referralRewardMaxAttempts)
+		}
+	}
+}
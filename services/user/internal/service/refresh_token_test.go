@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+)
+
+// fakeTokens keeps refresh tokens in memory, revoking them the way the repository does: only live tokens, with a reason.
+type fakeTokens struct {
+	byHash map[string]*model.RefreshToken
+}
+
+func (f *fakeTokens) CreateRefreshToken(t model.RefreshToken) error {
+	t.ID = fmt.Sprintf("rt-%d", len(f.byHash)+1)
+	f.byHash[hex.EncodeToString(t.TokenHash[:])] = &t
+	return nil
+}
+
+func (f *fakeTokens) GetRefreshToken(tokenHashHex string) (*model.RefreshToken, error) {
+	t, ok := f.byHash[tokenHashHex]
+	if !ok {
+		return nil, nil
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (f *fakeTokens) ConsumeRefreshToken(tokenID string) (bool, error) {
+	for _, t := range f.byHash {
+		if t.ID == tokenID && !t.Revoked {
+			t.Revoked, t.RevokedReason = true, repository.RevokeReasonRotated
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeTokens) RevokeRefreshTokenFamily(familyID, reason string) (int64, error) {
+	var n int64
+	for _, t := range f.byHash {
+		if t.FamilyID == familyID && !t.Revoked {
+			t.Revoked, t.RevokedReason = true, reason
+			n++
+		}
+	}
+	return n, nil
+}
+
+// reasons counts the family's tokens by revoke reason ("" for live ones).
+func (f *fakeTokens) reasons(familyID string) map[string]int {
+	out := map[string]int{}
+	for _, t := range f.byHash {
+		if t.FamilyID == familyID {
+			out[t.RevokedReason]++
+		}
+	}
+	return out
+}
+
+func (f *fakeTokens) GetUserByID(id string) (*model.User, error) {
+	return &model.User{ID: id, Email: "ada@example.com"}, nil
+}
+
+func (f *fakeTokens) TouchSession(string, string, string) error { return nil }
+
+// newTokenService returns a service and the refresh token of a fresh login (session "sid-1") by user-1.
+func newTokenService(t *testing.T) (*UserService, *fakeTokens, string) {
+	store := &fakeTokens{byHash: map[string]*model.RefreshToken{}}
+	svc := &UserService{tokenRepo: store, tokenGen: NewTokenGenerator()}
+	token, _, err := svc.tokenGen.GenerateAndStoreRefreshToken("user-1", "sid-1", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, store, token
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	svc, store, first := newTokenService(t)
+	resp, err := svc.RefreshToken(ctx, first, model.DeviceInfo{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" || resp.RefreshToken == first {
+		t.Fatalf("response = %+v", resp)
+	}
+	second, err := svc.RefreshToken(ctx, resp.RefreshToken, model.DeviceInfo{})
+	if err != nil {
+		t.Fatalf("refresh with the rotated token: %v", err)
+	}
+	if got := store.reasons("sid-1"); got[repository.RevokeReasonRotated] != 2 || got[""] != 1 {
+		t.Errorf("family tokens = %v; want two rotated and the latest live", got)
+	}
+	if second.RefreshToken == resp.RefreshToken {
+		t.Error("second refresh returned the same refresh token")
+	}
+}
+
+func TestRefreshTokenReplayRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	svc, store, first := newTokenService(t)
+	resp, err := svc.RefreshToken(ctx, first, model.DeviceInfo{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// The rotated token comes back: someone kept a copy.
+	if _, err := svc.RefreshToken(ctx, first, model.DeviceInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replay = %v, want ErrRefreshTokenReused", err)
+	}
+	if got := store.reasons("sid-1"); got[""] != 0 || got[repository.RevokeReasonReuseDetected] != 1 {
+		t.Errorf("family tokens = %v; want the live token revoked for reuse", got)
+	}
+	// The legitimate holder's current token is gone too, and presenting it again is not a second theft.
+	if _, err := svc.RefreshToken(ctx, resp.RefreshToken, model.DeviceInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh with the revoked successor = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshTokenAfterLogout(t *testing.T) {
+	ctx := context.Background()
+	svc, store, first := newTokenService(t)
+	resp, err := svc.RefreshToken(ctx, first, model.DeviceInfo{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if err := svc.Logout(ctx, resp.RefreshToken, nil); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+
+	// An app refreshing with the token it just logged out is not a theft.
+	if _, err := svc.RefreshToken(ctx, resp.RefreshToken, model.DeviceInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh after logout = %v, want ErrInvalidRefreshToken", err)
+	}
+	if got := store.reasons("sid-1"); got[repository.RevokeReasonLogout] != 1 || got[repository.RevokeReasonReuseDetected] != 0 {
+		t.Errorf("family tokens = %v; want the logout to stand", got)
+	}
+	if err := svc.Logout(ctx, resp.RefreshToken, nil); err != nil {
+		t.Errorf("second logout: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+	"github.com/google/uuid"
+)
+
+// ErrSessionNotFound is returned when a session does not exist or belongs to another user.
+var ErrSessionNotFound = errors.New("session not found")
+
+// startSession records a login on the device and issues the first token pair for it. Users who have logged in
+// before get an email and WhatsApp alert when the device is new to their account.
+func (s *UserService) startSession(user *model.User, device model.DeviceInfo) (*model.LoginResponse, *model.Session, error) {
+	device = normalizeDevice(device)
+	hasHistory, knownDevice, err := s.userRepo.DeviceHistory(user.ID, device.DeviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := s.userRepo.CreateSession(user.ID, device)
+	if err != nil {
+		return nil, nil, err
+	}
+	accessToken, expiresAt, err := s.tokenGen.GenerateAccessToken(user.ID, user.Email, session.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refreshToken, refreshExpiresAt, err := s.tokenGen.GenerateAndStoreRefreshToken(user.ID, session.ID, s.userRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hasHistory && !knownDevice {
+		s.sendNewDeviceAlert(user, session)
+	}
+	return &model.LoginResponse{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, session, nil
+}
+
+// revokeSessionAccess puts the session's access tokens (sid claim) on the deny-list.
+func (s *UserService) revokeSessionAccess(ctx context.Context, sessionID string) {
+	if err := s.deny.RevokeSession(ctx, sessionID); err != nil {
+		log.Printf("user service: deny-list revoke session=%s err=%v", sessionID, err)
+	}
+}
+
+// ListSessions returns the user's active sessions. currentSessionID (the sid of the caller's access token) is flagged.
+func (s *UserService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]dto.SessionResponse, error) {
+	list, err := s.userRepo.ListActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.SessionResponse, 0, len(list))
+	for i := range list {
+		out = append(out, toSessionResponse(&list[i], currentSessionID))
+	}
+	return out, nil
+}
+
+// RevokeSession logs the user out of one session: its refresh tokens and access tokens stop working.
+func (s *UserService) RevokeSession(ctx context.Context, userID, sessionID, reason string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return ErrSessionNotFound
+	}
+	session, err := s.userRepo.GetSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return ErrSessionNotFound
+	}
+	n, err := s.userRepo.RevokeRefreshTokenFamily(sessionID, reason)
+	if err != nil {
+		return err
+	}
+	s.revokeSessionAccess(ctx, sessionID)
+	s.dropDevicePushTokens(userID, session.Device.DeviceID)
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "session_revoked",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"session_id": sessionID, "device_id": session.Device.DeviceID, "reason": reason, "tokens_revoked": n},
+	})
+	return nil
+}
+
+// LogOutEverywhere revokes every session of the user, including the caller's.
+func (s *UserService) LogOutEverywhere(ctx context.Context, userID string) error {
+	return s.RevokeAllSessions(ctx, userID, repository.RevokeReasonLogoutAll)
+}
+
+// AdminRevokeSessions kills one session of the user, or all of them when sessionID is empty (admin portal).
+func (s *UserService) AdminRevokeSessions(ctx context.Context, userID, sessionID string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return repository.ErrUserNotFound
+	}
+	if sessionID == "" {
+		return s.RevokeAllSessions(ctx, userID, repository.RevokeReasonAdminRevoked)
+	}
+	return s.RevokeSession(ctx, userID, sessionID, repository.RevokeReasonAdminRevoked)
+}
+
+func toSessionResponse(sess *model.Session, currentSessionID string) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:         sess.ID,
+		DeviceID:   sess.Device.DeviceID,
+		DeviceName: sess.Device.DeviceName,
+		Platform:   sess.Device.Platform,
+		AppVersion: sess.Device.AppVersion,
+		IPAddress:  sess.Device.IPAddress,
+		UserAgent:  sess.Device.UserAgent,
+		Current:    currentSessionID != "" && sess.ID == currentSessionID,
+		CreatedAt:  sess.CreatedAt.Format(time.RFC3339),
+		LastSeenAt: sess.LastSeenAt.Format(time.RFC3339),
+	}
+}
+
+// normalizeDevice trims client-supplied device fields to their column sizes. Clients that send no device ID get one
+// derived from platform and user agent, so the same browser or app build is still recognised on the next login.
+func normalizeDevice(d model.DeviceInfo) model.DeviceInfo {
+	d.DeviceID = truncate(strings.TrimSpace(d.DeviceID), 128)
+	d.DeviceName = truncate(strings.TrimSpace(d.DeviceName), 100)
+	d.Platform = truncate(strings.ToLower(strings.TrimSpace(d.Platform)), 20)
+	d.AppVersion = truncate(strings.TrimSpace(d.AppVersion), 30)
+	d.IPAddress = truncate(strings.TrimSpace(d.IPAddress), 45)
+	d.UserAgent = truncate(strings.TrimSpace(d.UserAgent), 255)
+	if d.DeviceID == "" {
+		h := sha256.Sum256([]byte(d.Platform + "|" + d.UserAgent))
+		d.DeviceID = "ua-" + hex.EncodeToString(h[:12])
+	}
+	return d
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// deviceLabel is how a session's device is named in alerts, e.g. "Pixel 8 (android)".
+func deviceLabel(d model.DeviceInfo) string {
+	name := d.DeviceName
+	if name == "" {
+		name = d.UserAgent
+	}
+	if name == "" {
+		name = "Unknown device"
+	}
+	if d.Platform != "" {
+		name += " (" + d.Platform + ")"
+	}
+	return name
+}
+
+func (s *UserService) sendNewDeviceAlert(user *model.User, session *model.Session) {
+	if s.notifier == nil {
+		return
+	}
+	toName := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if toName == "" {
+		toName = user.Email
+	}
+	device := deviceLabel(session.Device)
+	when := session.CreatedAt.UTC().Format("02 Jan 2006 15:04 UTC")
+	body := "New login to your PayUp account on " + device + " from IP " + session.Device.IPAddress + " at " + when +
+		". If this wasn't you, change your password now; that logs out every device."
+	if err := s.sendNotification(kafka.NotificationEvent{
+		UserID:   user.ID,
+		Type:     "new_device_login",
+		Channel:  "email",
+		Metadata: map[string]interface{}{"to": user.Email, "to_name": toName},
+		Data:     map[string]interface{}{"device": device, "ip_address": session.Device.IPAddress, "time": when},
+	}); err != nil {
+		log.Printf("user service: new device alert email user=%s err=%v", user.ID, err)
+	}
+	if user.PhoneNumber != "" {
+		_ = s.sendNotification(kafka.NotificationEvent{
+			UserID:  user.ID,
+			Type:    "new_device_login",
+			Channel: "whatsapp",
+			Metadata: map[string]interface{}{
+				"to":   user.PhoneNumber,
+				"body": body,
+			},
+		})
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "new_device_login",
+		Entity:   "user",
+		EntityID: user.ID,
+		UserID:   &user.ID,
+		Metadata: map[string]interface{}{"session_id": session.ID, "device_id": session.Device.DeviceID, "ip": session.Device.IPAddress},
+	})
+}
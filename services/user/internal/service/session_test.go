@@ -0,0 +1,74 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+func TestNormalizeDevice(t *testing.T) {
+	got := normalizeDevice(model.DeviceInfo{
+		DeviceID:   "  dev-1 ",
+		DeviceName: strings.Repeat("n", 150),
+		Platform:   " iOS ",
+		IPAddress:  "102.89.1.1",
+		UserAgent:  strings.Repeat("u", 300),
+	})
+	if got.DeviceID != "dev-1" || got.Platform != "ios" {
+		t.Errorf("device = %+v", got)
+	}
+	if len(got.DeviceName) != 100 || len(got.UserAgent) != 255 {
+		t.Errorf("name %d, user agent %d bytes; want 100 and 255", len(got.DeviceName), len(got.UserAgent))
+	}
+}
+
+func TestNormalizeDeviceDerivesID(t *testing.T) {
+	chrome := model.DeviceInfo{Platform: "web", UserAgent: "Mozilla/5.0 Chrome/126"}
+	first, again := normalizeDevice(chrome), normalizeDevice(model.DeviceInfo{Platform: "WEB", UserAgent: " Mozilla/5.0 Chrome/126 "})
+	if !strings.HasPrefix(first.DeviceID, "ua-") || len(first.DeviceID) != 27 {
+		t.Fatalf("derived device ID %q", first.DeviceID)
+	}
+	if again.DeviceID != first.DeviceID {
+		t.Errorf("same browser derived %q then %q", first.DeviceID, again.DeviceID)
+	}
+	firefox := normalizeDevice(model.DeviceInfo{Platform: "web", UserAgent: "Mozilla/5.0 Firefox/127"})
+	if firefox.DeviceID == first.DeviceID {
+		t.Error("different browsers share a derived device ID")
+	}
+}
+
+func TestDeviceLabel(t *testing.T) {
+	cases := []struct {
+		device model.DeviceInfo
+		want   string
+	}{
+		{model.DeviceInfo{DeviceName: "Pixel 8", Platform: "android", UserAgent: "okhttp/4"}, "Pixel 8 (android)"},
+		{model.DeviceInfo{UserAgent: "Mozilla/5.0", Platform: "web"}, "Mozilla/5.0 (web)"},
+		{model.DeviceInfo{}, "Unknown device"},
+	}
+	for _, c := range cases {
+		if got := deviceLabel(c.device); got != c.want {
+			t.Errorf("deviceLabel(%+v) = %q, want %q", c.device, got, c.want)
+		}
+	}
+}
+
+func TestToSessionResponse(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	sess := &model.Session{
+		ID: "sid-1", Device: model.DeviceInfo{DeviceID: "dev-1", Platform: "ios"},
+		CreatedAt: created, LastSeenAt: created.Add(time.Hour),
+	}
+	got := toSessionResponse(sess, "sid-1")
+	if !got.Current || got.DeviceID != "dev-1" || got.CreatedAt != "2026-03-01T09:30:00Z" || got.LastSeenAt != "2026-03-01T10:30:00Z" {
+		t.Errorf("response = %+v", got)
+	}
+	if toSessionResponse(sess, "sid-2").Current {
+		t.Error("another session is flagged current")
+	}
+	if toSessionResponse(&model.Session{}, "").Current {
+		t.Error("a caller without a sid has a current session")
+	}
+}
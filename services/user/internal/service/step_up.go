@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	passwd "github.com/abubakvr/payup-backend/services/user/internal/password"
+	"github.com/abubakvr/payup-backend/services/user/redis"
+	"github.com/pquerna/otp/totp"
+)
+
+// Sensitive actions a step-up token can be issued for.
+const (
+	StepUpSetPin          = "set_pin"
+	StepUpIncreaseLimits  = "increase_limits"
+	StepUpPauseAccount    = "pause_account"
+	StepUpResumeAccount   = "resume_account"
+	StepUpDisable2FA      = "disable_2fa"
+	StepUpRecoveryCodes   = "regenerate_recovery_codes"
+	StepUpRegisterPasskey = "register_passkey"
+	StepUpTransfer        = "transfer"
+	StepUpChangeContact   = "change_contact"
+	StepUpExportData      = "export_data"
+	StepUpDeleteAccount   = "delete_account"
+	stepUpTokenExpiryMins = 5
+)
+
+// PurposeStepUp is the JWT purpose claim of step-up tokens. Like the 2FA pending token it is refused as an access token.
+const PurposeStepUp = "step_up"
+
+var (
+	// ErrStepUpRequired means the action needs a step-up token (X-Step-Up-Token) under the current policy.
+	ErrStepUpRequired = errors.New("step-up authentication required for this action")
+	ErrStepUpInvalid  = errors.New("invalid or expired step-up token")
+	// ErrStepUpOneAction is returned when one PATCH /settings request combines changes that each need step-up.
+	ErrStepUpOneAction = errors.New("these changes need separate step-up confirmations; make them one at a time")
+)
+
+// DefaultStepUpActions need step-up unless STEP_UP_ACTIONS says otherwise. Pausing the account is left out: it only
+// makes the account safer, and a user who suspects fraud should not be slowed down.
+var DefaultStepUpActions = []string{StepUpSetPin, StepUpIncreaseLimits, StepUpResumeAccount, StepUpDisable2FA, StepUpRecoveryCodes, StepUpRegisterPasskey,
+	StepUpChangeContact, StepUpExportData, StepUpDeleteAccount}
+
+// StepUpPolicy says which sensitive actions need a step-up token rather than just the password. Transfers need one
+// from TransferThreshold (naira) upwards; 0 never requires it.
+type StepUpPolicy struct {
+	actions           map[string]bool
+	TransferThreshold float64
+}
+
+// NewStepUpPolicy returns a policy requiring step-up for actions and for transfers of at least transferThreshold.
+func NewStepUpPolicy(actions []string, transferThreshold float64) StepUpPolicy {
+	p := StepUpPolicy{actions: make(map[string]bool, len(actions)), TransferThreshold: transferThreshold}
+	for _, a := range actions {
+		if a = strings.TrimSpace(a); a != "" {
+			p.actions[a] = true
+		}
+	}
+	return p
+}
+
+// Requires reports whether action needs step-up.
+func (p StepUpPolicy) Requires(action string) bool {
+	return p.actions[action]
+}
+
+// RequiresTransfer reports whether a transfer of amount needs step-up.
+func (p StepUpPolicy) RequiresTransfer(amount float64) bool {
+	return p.TransferThreshold > 0 && amount >= p.TransferThreshold
+}
+
+func validStepUpAction(action string) bool {
+	switch action {
+	case StepUpSetPin, StepUpIncreaseLimits, StepUpPauseAccount, StepUpResumeAccount, StepUpDisable2FA, StepUpRecoveryCodes,
+		StepUpRegisterPasskey, StepUpTransfer, StepUpChangeContact, StepUpExportData, StepUpDeleteAccount:
+		return true
+	}
+	return false
+}
+
+// StepUp re-verifies the password and a second factor and returns a short-lived token for one action. Users with 2FA
+// confirm with their authenticator (method totp) or a code from SendStepUpOTP (method otp); users without 2FA use the
+// code. The token is bound to the caller's session.
+func (s *UserService) StepUp(ctx context.Context, userID, sessionID string, req *dto.StepUpRequest) (*dto.StepUpResponse, error) {
+	if !validStepUpAction(req.Action) {
+		return nil, errors.New("unknown action")
+	}
+	var amount float64
+	if req.Action == StepUpTransfer {
+		if req.Amount <= 0 {
+			return nil, errors.New("amount required: a transfer step-up approves transfers up to that amount")
+		}
+		amount = req.Amount
+	}
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
+	if err != nil || settings == nil {
+		return nil, errors.New("user settings not found")
+	}
+	if err := s.limits.stepUp.Check(ctx, userID); err != nil {
+		return nil, err
+	}
+	ok, err := s.verifyStepUpFactors(ctx, user, settings, req)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if lockErr := s.limits.stepUp.Fail(ctx, userID); lockErr != nil {
+			_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+				Service:  "user",
+				Action:   "step_up_locked",
+				Entity:   "user",
+				EntityID: userID,
+				UserID:   &userID,
+				Metadata: map[string]interface{}{"action": req.Action},
+			})
+			return nil, lockErr
+		}
+		return nil, ErrInvalidTOTPCode
+	}
+	s.limits.stepUp.Succeed(ctx, userID)
+	token, expiresAt, err := GenerateStepUpToken(userID, user.Email, sessionID, req.Action, amount, stepUpTokenExpiryMins)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "step_up",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"action": req.Action, "method": req.Method, "session_id": sessionID, "amount": amount},
+	})
+	return &dto.StepUpResponse{StepUpToken: token, Action: req.Action, Amount: amount, ExpiresAt: expiresAt.Format(time.RFC3339)}, nil
+}
+
+// verifyStepUpFactors checks the password and the TOTP or one-time code. Wrong values return (false, nil).
+func (s *UserService) verifyStepUpFactors(ctx context.Context, user *model.User, settings *model.UserSettings, req *dto.StepUpRequest) (bool, error) {
+	if !passwd.CheckPassword(req.Password, user.PasswordHash) {
+		return false, nil
+	}
+	method := req.Method
+	if method == "" {
+		method = TwoFactorMethodTOTP
+		if !settings.TwoFactorEnabled {
+			method = TwoFactorMethodOTP
+		}
+	}
+	if method == TwoFactorMethodTOTP {
+		if !settings.TwoFactorEnabled || settings.TotpSecret == nil {
+			return false, errors.New("2FA not enabled; confirm with a one-time code (method otp)")
+		}
+		return totp.Validate(req.Code, *settings.TotpSecret), nil
+	}
+	ok, err := redis.ConsumeOTP(ctx, otpPurposeStepUp, user.ID, hashSecondFactorCode(req.Code))
+	if errors.Is(err, redis.ErrUnavailable) {
+		return false, ErrFallbackUnavailable
+	}
+	return ok, err
+}
+
+// SendStepUpOTP sends a one-time confirmation code for StepUp (channel: sms, whatsapp or email).
+func (s *UserService) SendStepUpOTP(ctx context.Context, userID, channel string) (*dto.Send2FAOTPResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
+	if err != nil || settings == nil {
+		return nil, errors.New("user settings not found")
+	}
+	return s.sendOneTimeCode(ctx, user, settings, channel, otpPurposeStepUp)
+}
+
+// checkStepUpToken validates a step-up token for the user and action. A non-empty sessionID must match the session
+// the token was issued in. Settings tokens stay valid for their few minutes so a retried request does not need a new
+// one; transfer tokens are single-use (see stepUpForTransfer).
+func (s *UserService) checkStepUpToken(ctx context.Context, userID, sessionID, action, token string) error {
+	_, err := s.parseStepUpToken(ctx, userID, sessionID, action, token)
+	return err
+}
+
+func (s *UserService) parseStepUpToken(ctx context.Context, userID, sessionID, action, token string) (*Claims, error) {
+	claims, err := ValidateJWT(token)
+	if err != nil || claims.Purpose != PurposeStepUp || claims.UserID != userID || claims.StepUpAction != action {
+		return nil, ErrStepUpInvalid
+	}
+	if sessionID != "" && claims.SessionID != "" && claims.SessionID != sessionID {
+		return nil, ErrStepUpInvalid
+	}
+	if err := s.deny.Check(ctx, claims.authnClaims()); err != nil {
+		return nil, ErrStepUpInvalid
+	}
+	return claims, nil
+}
+
+// SensitiveAuth is how a caller confirms a sensitive change: the password, or a step-up token (X-Step-Up-Token) issued
+// in SessionID, the session of the access token.
+type SensitiveAuth struct {
+	Password    string
+	StepUpToken string
+	SessionID   string
+}
+
+// confirmSensitive authorises a sensitive settings change: a step-up token for the action if one was sent, otherwise
+// the password, unless the policy requires step-up for the action.
+func (s *UserService) confirmSensitive(ctx context.Context, user *model.User, action string, auth SensitiveAuth) error {
+	if auth.StepUpToken != "" {
+		return s.checkStepUpToken(ctx, user.ID, auth.SessionID, action, auth.StepUpToken)
+	}
+	if s.stepUp.Requires(action) {
+		return ErrStepUpRequired
+	}
+	if auth.Password == "" {
+		return errors.New("password required")
+	}
+	if !passwd.CheckPassword(auth.Password, user.PasswordHash) {
+		return errors.New("invalid password")
+	}
+	return nil
+}
+
+// confirmSettingsUpdate authorises a PATCH /settings that touches protected fields. actions are the step-up actions
+// the update amounts to (raising limits, resuming transfers, turning 2FA off); at most one of them may need step-up.
+func (s *UserService) confirmSettingsUpdate(ctx context.Context, user *model.User, actions []string, auth SensitiveAuth) error {
+	var required []string
+	for _, a := range actions {
+		if s.stepUp.Requires(a) {
+			required = append(required, a)
+		}
+	}
+	switch {
+	case len(required) > 1:
+		return ErrStepUpOneAction
+	case len(required) == 1:
+		return s.confirmSensitive(ctx, user, required[0], auth)
+	case len(actions) == 1 && auth.StepUpToken != "":
+		return s.confirmSensitive(ctx, user, actions[0], auth)
+	}
+	return s.confirmSensitive(ctx, user, "", SensitiveAuth{Password: auth.Password})
+}
+
+// limitsIncrease reports whether the new limits allow more spending than the current ones (see raisesLimit).
+func limitsIncrease(current *model.UserSettings, daily, monthly *float64) bool {
+	return raisesLimit(current.DailyTransferLimit, daily) || raisesLimit(current.MonthlyTransferLimit, monthly)
+}
+
+// stepUpForTransfer returns the ValidateTransfer refusal message, or "" when the transfer may go ahead.
+func (s *UserService) stepUpForTransfer(ctx context.Context, userID string, amount float64, stepUpToken string) string {
+	if !s.stepUp.RequiresTransfer(amount) {
+		return ""
+	}
+	if stepUpToken == "" {
+		return "step-up required: confirm large transfers with POST /auth/step-up"
+	}
+	claims, err := s.parseStepUpToken(ctx, userID, "", StepUpTransfer, stepUpToken)
+	if err != nil {
+		return "step-up required: " + err.Error()
+	}
+	if amount > claims.StepUpAmount {
+		return fmt.Sprintf("step-up required: token approves transfers up to %.2f", claims.StepUpAmount)
+	}
+	// One token, one transfer: it is burnt on first use, so a leaked or replayed token cannot approve another.
+	if err := s.deny.Consume(ctx, claims.ID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return "step-up required: " + ErrStepUpInvalid.Error()
+	}
+	return ""
+}
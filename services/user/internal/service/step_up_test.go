@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abubakvr/payup-backend/pkg/authn"
+	"github.com/redis/go-redis/v9"
+)
+
+// denyRedis is the deny-list's Redis: MGet for revocation checks and SetNX for single-use tokens.
+type denyRedis struct {
+	redis.UniversalClient
+	keys map[string]bool
+}
+
+func (r *denyRedis) MGet(_ context.Context, keys ...string) *redis.SliceCmd {
+	vals := make([]interface{}, len(keys))
+	for i, k := range keys {
+		if r.keys[k] {
+			vals[i] = "1"
+		}
+	}
+	return redis.NewSliceResult(vals, nil)
+}
+
+func (r *denyRedis) SetNX(_ context.Context, key string, _ interface{}, _ time.Duration) *redis.BoolCmd {
+	if r.keys[key] {
+		return redis.NewBoolResult(false, nil)
+	}
+	r.keys[key] = true
+	return redis.NewBoolResult(true, nil)
+}
+
+func TestStepUpForTransferAmount(t *testing.T) {
+	svc := &UserService{stepUp: NewStepUpPolicy(nil, 100000), deny: authn.NewDenyList(&denyRedis{keys: map[string]bool{}}, time.Hour)}
+	token := func(userID, action string, amount float64) string {
+		tok, _, err := GenerateStepUpToken(userID, "ada@example.com", "sid-1", action, amount, stepUpTokenExpiryMins)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok
+	}
+	ctx := context.Background()
+
+	if msg := svc.stepUpForTransfer(ctx, "user-1", 50000, ""); msg != "" {
+		t.Errorf("below threshold: %q", msg)
+	}
+	cases := []struct {
+		name   string
+		amount float64
+		token  string
+		ok     bool
+	}{
+		{"no token", 150000, "", false},
+		{"within approved amount", 150000, token("user-1", StepUpTransfer, 150000), true},
+		{"above approved amount", 150000.01, token("user-1", StepUpTransfer, 150000), false},
+		{"token without amount", 150000, token("user-1", StepUpTransfer, 0), false},
+		{"another action", 150000, token("user-1", StepUpSetPin, 150000), false},
+		{"another user", 150000, token("user-2", StepUpTransfer, 150000), false},
+	}
+	for _, c := range cases {
+		msg := svc.stepUpForTransfer(ctx, "user-1", c.amount, c.token)
+		if ok := msg == ""; ok != c.ok {
+			t.Errorf("%s: message %q, want allowed=%v", c.name, msg, c.ok)
+		} else if !ok && !strings.HasPrefix(msg, "step-up required") {
+			t.Errorf("%s: message %q lacks the step-up prefix payment maps to 403", c.name, msg)
+		}
+	}
+}
+
+func TestStepUpForTransferSingleUse(t *testing.T) {
+	svc := &UserService{stepUp: NewStepUpPolicy(nil, 100000), deny: authn.NewDenyList(&denyRedis{keys: map[string]bool{}}, time.Hour)}
+	tok, _, err := GenerateStepUpToken("user-1", "ada@example.com", "sid-1", StepUpTransfer, 200000, stepUpTokenExpiryMins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if msg := svc.stepUpForTransfer(ctx, "user-1", 120000, tok); msg != "" {
+		t.Fatalf("first transfer: %q", msg)
+	}
+	if msg := svc.stepUpForTransfer(ctx, "user-1", 120000, tok); msg == "" {
+		t.Error("token approved a second transfer")
+	}
+}
@@ -23,8 +23,8 @@ func NewTokenGenerator() repository.TokenGenerator {
 	return &tokenGenerator{}
 }
 
-func (t *tokenGenerator) GenerateAccessToken(userID, email string) (string, time.Time, error) {
-	token, err := GenerateJWT(userID, email, accessTokenExpiryMinutes)
+func (t *tokenGenerator) GenerateAccessToken(userID, email, sessionID string) (string, time.Time, error) {
+	token, err := GenerateSessionJWT(userID, email, sessionID, accessTokenExpiryMinutes)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -32,7 +32,7 @@ func (t *tokenGenerator) GenerateAccessToken(userID, email string) (string, time
 	return token, expiresAt, nil
 }
 
-func (t *tokenGenerator) GenerateAndStoreRefreshToken(userID string, inserter repository.RefreshTokenInserter) (string, time.Time, error) {
+func (t *tokenGenerator) GenerateAndStoreRefreshToken(userID, familyID string, inserter repository.RefreshTokenInserter) (string, time.Time, error) {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
 		return "", time.Time{}, err
@@ -42,6 +42,7 @@ func (t *tokenGenerator) GenerateAndStoreRefreshToken(userID string, inserter re
 	expiresAt := time.Now().AddDate(0, 0, refreshTokenExpiryDays)
 	rt := model.RefreshToken{
 		UserID:    userID,
+		FamilyID:  familyID,
 		TokenHash: hash,
 		ExpiresAt: expiresAt,
 		Revoked:   false,
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/dto"
+	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+	"github.com/abubakvr/payup-backend/services/user/redis"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+	// Recovery codes avoid look-alike characters (0/o, 1/l/i) so they can be read off paper.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	twoFactorOTPTTL      = 5 * time.Minute
+
+	// Second-factor methods accepted by VerifyLogin2FA.
+	TwoFactorMethodTOTP         = "totp"
+	TwoFactorMethodRecoveryCode = "recovery_code"
+	TwoFactorMethodOTP          = "otp"
+)
+
+var (
+	ErrNoFallbackDestination = errors.New("no phone number on file for this channel")
+	ErrFallbackUnavailable   = errors.New("one-time login codes are temporarily unavailable")
+)
+
+// recoveryCodeStore is the part of the user repository that keeps the hashed recovery codes.
+type recoveryCodeStore interface {
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	ConsumeRecoveryCode(userID, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(userID string) (int, error)
+}
+
+// randomString returns n characters drawn uniformly from alphabet.
+func randomString(alphabet string, n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range b {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[v.Int64()]
+	}
+	return string(b), nil
+}
+
+// hashSecondFactorCode normalises what the user typed (case, spaces, dashes) and returns its SHA-256 hex.
+func hashSecondFactorCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueRecoveryCodes replaces the user's recovery codes with a new set and returns the plain codes (xxxxx-xxxxx).
+func (s *UserService) issueRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := randomString(recoveryCodeAlphabet, 10)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashSecondFactorCode(raw)
+	}
+	if err := s.recoveryRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking the password or step-up token. Old codes
+// stop working.
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, userID string, auth SensitiveAuth) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if err := s.confirmSensitive(ctx, user, StepUpRecoveryCodes, auth); err != nil {
+		return nil, err
+	}
+	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
+	if err != nil || settings == nil {
+		return nil, errors.New("user settings not found")
+	}
+	if !settings.TwoFactorEnabled {
+		return nil, Err2FANotEnabled
+	}
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "2fa_recovery_codes_regenerated",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"count": len(codes)},
+	})
+	return codes, nil
+}
+
+// TwoFactorStatus reports whether 2FA is on, the fallback channel and how many recovery codes are left.
+func (s *UserService) TwoFactorStatus(ctx context.Context, userID string) (*dto.TwoFactorStatusResponse, error) {
+	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
+	if err != nil || settings == nil {
+		return nil, errors.New("user settings not found")
+	}
+	resp := &dto.TwoFactorStatusResponse{Enabled: settings.TwoFactorEnabled}
+	if settings.TwoFactorFallbackChannel != nil {
+		resp.FallbackChannel = *settings.TwoFactorFallbackChannel
+	}
+	if settings.TwoFactorEnabled {
+		n, err := s.recoveryRepo.CountUnusedRecoveryCodes(userID)
+		if err != nil {
+			return nil, err
+		}
+		resp.RecoveryCodesRemaining = n
+	}
+	return resp, nil
+}
+
+// SetTwoFactorFallback stores where fallback login codes go by default (sms, whatsapp, email; none clears it).
+func (s *UserService) SetTwoFactorFallback(ctx context.Context, userID, channel string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+	if _, err := s.userRepo.GetOrCreateUserSettings(userID); err != nil {
+		return err
+	}
+	var value *string
+	if channel != "none" {
+		if channel != "email" && user.PhoneNumber == "" {
+			return ErrNoFallbackDestination
+		}
+		value = &channel
+	}
+	if err := s.userRepo.SetTwoFactorFallbackChannel(userID, value); err != nil {
+		return err
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "2fa_fallback_changed",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"channel": channel},
+	})
+	return nil
+}
+
+// pending2FAClaims validates a 2FA pending token from the login response.
+func (s *UserService) pending2FAClaims(ctx context.Context, twoFactorToken string) (*Claims, error) {
+	claims, err := ValidateJWT(twoFactorToken)
+	if err != nil {
+		return nil, errors.New("invalid or expired two-factor token")
+	}
+	if claims.Purpose != Purpose2FALogin {
+		return nil, errors.New("invalid token purpose")
+	}
+	if err := s.deny.Check(ctx, claims.authnClaims()); err != nil {
+		return nil, errors.New("invalid or expired two-factor token")
+	}
+	return claims, nil
+}
+
+// SendLogin2FAOTP sends a one-time login code for a pending 2FA login, for users without their authenticator app.
+// The channel defaults to the user's fallback preference, then email. Sends are rate limited per user.
+func (s *UserService) SendLogin2FAOTP(ctx context.Context, twoFactorToken, channel string) (*dto.Send2FAOTPResponse, error) {
+	claims, err := s.pending2FAClaims(ctx, twoFactorToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(claims.UserID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	settings, err := s.userRepo.GetUserSettings(user.ID)
+	if err != nil || settings == nil || !settings.TwoFactorEnabled {
+		return nil, errors.New("2FA not enabled for this account")
+	}
+	return s.sendOneTimeCode(ctx, user, settings, channel, otpPurpose2FALogin)
+}
+
+// One-time code purposes; each has its own code so a login code cannot authorise a step-up and vice versa.
+const (
+	otpPurpose2FALogin = "2fa_login"
+	otpPurposeStepUp   = "step_up"
+)
+
+// sendOneTimeCode generates a 6-digit code for purpose, stores its hash and sends it on channel (default: the user's
+// fallback preference, then email).
+func (s *UserService) sendOneTimeCode(ctx context.Context, user *model.User, settings *model.UserSettings, channel, purpose string) (*dto.Send2FAOTPResponse, error) {
+	if channel == "" && settings.TwoFactorFallbackChannel != nil {
+		channel = *settings.TwoFactorFallbackChannel
+	}
+	if channel == "" {
+		channel = "email"
+	}
+	if channel != "email" && user.PhoneNumber == "" {
+		return nil, ErrNoFallbackDestination
+	}
+	if err := s.limits.otpSend.Check(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	code, err := randomString("0123456789", 6)
+	if err != nil {
+		return nil, err
+	}
+	if err := redis.SetOTP(ctx, purpose, user.ID, hashSecondFactorCode(code), twoFactorOTPTTL); err != nil {
+		log.Printf("user service: store %s otp user=%s err=%v", purpose, user.ID, err)
+		return nil, ErrFallbackUnavailable
+	}
+	what := "login code"
+	if purpose == otpPurposeStepUp {
+		what = "confirmation code"
+	}
+	minutes := int(twoFactorOTPTTL.Minutes())
+	body := fmt.Sprintf("Your PayUp %s is %s. It expires in %d minutes. Never share it with anyone.", what, code, minutes)
+	eventType := purpose + "_otp"
+	destination := maskEmail(user.Email)
+	switch channel {
+	case "sms":
+		destination = maskPhone(user.PhoneNumber)
+		err = s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  "sms",
+			Metadata: map[string]interface{}{"to": user.PhoneNumber, "body": body, "channel": "dnd"},
+		})
+	case "whatsapp":
+		destination = maskPhone(user.PhoneNumber)
+		err = s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  "whatsapp",
+			Metadata: map[string]interface{}{"to": user.PhoneNumber, "otp": code},
+		})
+	default:
+		err = s.sendNotification(kafka.NotificationEvent{
+			UserID:   user.ID,
+			Type:     eventType,
+			Channel:  "email",
+			Metadata: map[string]interface{}{"to": user.Email},
+			Data:     map[string]interface{}{"otp": code},
+		})
+	}
+	if err != nil {
+		log.Printf("user service: send %s otp user=%s channel=%s err=%v", purpose, user.ID, channel, err)
+		return nil, ErrFallbackUnavailable
+	}
+	// Count the send; the lockout this may start applies to the next request, this code is already out.
+	_ = s.limits.otpSend.Fail(ctx, user.ID)
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "otp_sent",
+		Entity:   "user",
+		EntityID: user.ID,
+		UserID:   &user.ID,
+		Metadata: map[string]interface{}{"channel": channel, "purpose": purpose},
+	})
+	return &dto.Send2FAOTPResponse{Channel: channel, Destination: destination, ExpiresIn: int(twoFactorOTPTTL.Seconds())}, nil
+}
+
+// verifySecondFactor checks code with the chosen method. Recovery codes and one-time codes are spent on success.
+func (s *UserService) verifySecondFactor(ctx context.Context, user *model.User, settings *model.UserSettings, method, code string) (bool, error) {
+	switch method {
+	case TwoFactorMethodRecoveryCode:
+		ok, err := s.recoveryRepo.ConsumeRecoveryCode(user.ID, hashSecondFactorCode(code))
+		if err != nil || !ok {
+			return false, err
+		}
+		remaining, _ := s.recoveryRepo.CountUnusedRecoveryCodes(user.ID)
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "2fa_recovery_code_used",
+			Entity:   "user",
+			EntityID: user.ID,
+			UserID:   &user.ID,
+			Metadata: map[string]interface{}{"remaining": remaining},
+		})
+		s.notifyRecoveryCodeUsed(user, remaining)
+		return true, nil
+	case TwoFactorMethodOTP:
+		ok, err := redis.ConsumeOTP(ctx, otpPurpose2FALogin, user.ID, hashSecondFactorCode(code))
+		if errors.Is(err, redis.ErrUnavailable) {
+			return false, ErrFallbackUnavailable
+		}
+		return ok, err
+	default:
+		return settings.TotpSecret != nil && totp.Validate(code, *settings.TotpSecret), nil
+	}
+}
+
+// notifyRecoveryCodeUsed emails the user: a recovery code login they did not make means their password and codes leaked.
+func (s *UserService) notifyRecoveryCodeUsed(user *model.User, remaining int) {
+	body := fmt.Sprintf("A recovery code was used to sign in to your PayUp account. You have %d recovery codes left. "+
+		"If this wasn't you, reset your password and contact support.", remaining)
+	_ = s.sendNotification(kafka.NotificationEvent{
+		UserID:  user.ID,
+		Type:    "2fa_recovery_code_used",
+		Channel: "email",
+		Metadata: map[string]interface{}{
+			"to":      user.Email,
+			"subject": "A recovery code was used on your PayUp account",
+			"body":    body,
+			"html":    "<p>" + body + "</p>",
+		},
+	})
+}
+
+// AdminReset2FA turns off 2FA for a user who lost every second factor (admin action after identity checks). Recovery
+// codes are deleted and all sessions revoked; the user signs in with their password and enrols again.
+func (s *UserService) AdminReset2FA(ctx context.Context, userID, adminID, reason string) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return repository.ErrUserNotFound
+	}
+	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
+	if err != nil || settings == nil {
+		return errors.New("user settings not found")
+	}
+	if !settings.TwoFactorEnabled {
+		return Err2FANotEnabled
+	}
+	if err := s.userRepo.DisableTotp(userID); err != nil {
+		return err
+	}
+	if err := s.userRepo.DeleteRecoveryCodes(userID); err != nil {
+		return err
+	}
+	if err := s.RevokeAllSessions(ctx, userID, repository.RevokeReason2FAReset); err != nil {
+		log.Printf("user service: revoke sessions after 2fa reset user=%s err=%v", userID, err)
+	}
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "2fa_reset_by_admin",
+		Entity:   "user",
+		EntityID: userID,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"admin_id": adminID, "reason": reason},
+	})
+	body := "Two-factor authentication on your PayUp account was reset by our support team and you were signed out everywhere. " +
+		"Sign in and set it up again. If you did not ask for this, contact support immediately."
+	_ = s.sendNotification(kafka.NotificationEvent{
+		UserID:  userID,
+		Type:    "2fa_reset",
+		Channel: "email",
+		Metadata: map[string]interface{}{
+			"to":      user.Email,
+			"subject": "Two-factor authentication was reset",
+			"body":    body,
+			"html":    "<p>" + body + "</p>",
+		},
+	})
+	return nil
+}
+
+// maskEmail keeps the first character of the local part: a***@example.com.
+func maskEmail(email string) string {
+	at := strings.IndexByte(email, '@')
+	if at <= 0 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPhone keeps the last four digits.
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return "****"
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
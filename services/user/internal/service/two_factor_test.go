@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/abubakvr/payup-backend/services/user/internal/model"
+)
+
+// fakeRecoveryCodes keeps one user's recovery code hashes, true once used.
+type fakeRecoveryCodes struct {
+	used map[string]bool
+}
+
+func (f *fakeRecoveryCodes) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	f.used = map[string]bool{}
+	for _, h := range codeHashes {
+		f.used[h] = false
+	}
+	return nil
+}
+
+func (f *fakeRecoveryCodes) ConsumeRecoveryCode(userID, codeHash string) (bool, error) {
+	used, ok := f.used[codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	f.used[codeHash] = true
+	return true, nil
+}
+
+func (f *fakeRecoveryCodes) CountUnusedRecoveryCodes(userID string) (int, error) {
+	n := 0
+	for _, used := range f.used {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestHashSecondFactorCode(t *testing.T) {
+	want := hashSecondFactorCode("abcde-fghjk")
+	for _, typed := range []string{"ABCDE-FGHJK", "abcde fghjk", "abcdefghjk", "AbCdE - FgHjK"} {
+		if hashSecondFactorCode(typed) != want {
+			t.Errorf("%q hashes differently from the printed code", typed)
+		}
+	}
+	if hashSecondFactorCode("abcde-fghjm") == want {
+		t.Error("different codes share a hash")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeRecoveryCodes{}
+	svc := &UserService{recoveryRepo: store}
+	user := &model.User{ID: "user-1", Email: "ada@example.com"}
+
+	codes, err := svc.issueRecoveryCodes(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format := regexp.MustCompile(`^[` + recoveryCodeAlphabet + `]{5}-[` + recoveryCodeAlphabet + `]{5}$`)
+	seen := map[string]bool{}
+	for _, c := range codes {
+		if !format.MatchString(c) || seen[c] {
+			t.Errorf("code %q is malformed or repeated", c)
+		}
+		seen[c] = true
+		if _, stored := store.used[c]; stored {
+			t.Errorf("code %q is stored in plain text", c)
+		}
+	}
+	if len(codes) != recoveryCodeCount || len(store.used) != recoveryCodeCount {
+		t.Fatalf("issued %d codes, stored %d", len(codes), len(store.used))
+	}
+
+	typed := strings.ToUpper(strings.Replace(codes[0], "-", "", 1))
+	if ok, err := svc.verifySecondFactor(ctx, user, nil, TwoFactorMethodRecoveryCode, typed); !ok || err != nil {
+		t.Fatalf("first use = %v, %v", ok, err)
+	}
+	if ok, _ := svc.verifySecondFactor(ctx, user, nil, TwoFactorMethodRecoveryCode, codes[0]); ok {
+		t.Error("a recovery code worked twice")
+	}
+	if n, _ := store.CountUnusedRecoveryCodes(user.ID); n != recoveryCodeCount-1 {
+		t.Errorf("%d codes left, want %d", n, recoveryCodeCount-1)
+	}
+
+	// Regenerating retires every earlier code.
+	if _, err := svc.issueRecoveryCodes(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := svc.verifySecondFactor(ctx, user, nil, TwoFactorMethodRecoveryCode, codes[1]); ok {
+		t.Error("a code from the previous set still works")
+	}
+}
+
+func TestMaskDestination(t *testing.T) {
+	cases := []struct{ got, want string }{
+		{maskEmail("ada@example.com"), "a***@example.com"},
+		{maskEmail("not-an-email"), "***"},
+		{maskPhone("+2348031234567"), "**********4567"},
+		{maskPhone("123"), "****"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("masked %q, want %q", c.got, c.want)
+		}
+	}
+}
@@ -9,37 +9,95 @@ import (
 	"strings"
 	"time"
 
+	"github.com/abubakvr/payup-backend/pkg/authn"
 	"github.com/abubakvr/payup-backend/services/user/internal/dto"
 	"github.com/abubakvr/payup-backend/services/user/internal/kafka"
 	"github.com/abubakvr/payup-backend/services/user/internal/model"
 	passwd "github.com/abubakvr/payup-backend/services/user/internal/password"
 	"github.com/abubakvr/payup-backend/services/user/internal/repository"
+	"github.com/abubakvr/payup-backend/services/user/internal/webauthn"
 	"github.com/abubakvr/payup-backend/services/user/redis"
 	"github.com/google/uuid"
 	"github.com/pquerna/otp/totp"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 type UserService struct {
 	userRepo                 *repository.UserRepository
+	referralRepo             referralStore
+	tokenRepo                refreshTokenStore
+	recoveryRepo             recoveryCodeStore
+	privacyRepo              dataSubjectStore
 	tokenGen                 repository.TokenGenerator
 	producer                 *kafka.Producer
+	notifier                 notificationSender
 	emailVerificationBaseURL string
 	passwordResetBaseURL     string
 	userExistsCacheTTL       time.Duration
+	limits                   attemptLimiters
+	deny                     *authn.DenyList
+	authenticator            *authn.Authenticator
+	stepUp                   StepUpPolicy
+	passkeys                 webauthn.Config
+	limitIncreaseDelay       time.Duration
+	contactChangeCooldown    time.Duration
+	privacy                  PrivacyConfig
+	referrals                ReferralConfig
 }
 
-func NewUserService(userRepo *repository.UserRepository, tokenGen repository.TokenGenerator, producer *kafka.Producer, emailVerificationBaseURL, passwordResetBaseURL string, userExistsCacheTTL time.Duration) *UserService {
+// NewUserService wires the service. rdb backs the login/2FA/PIN attempt limits and the access-token deny-list; nil
+// disables both. stepUp decides which sensitive actions need a step-up token; passkeys is the WebAuthn relying party.
+// limitIncreaseDelay is the cooling-off before a transfer limit increase takes effect (0 applies increases at once);
+// contactChangeCooldown is the minimum time between two email (or two phone number) changes. privacy wires data
+// exports and account deletion to the other services; referrals sets the referral reward rules and payout client.
+func NewUserService(userRepo *repository.UserRepository, tokenGen repository.TokenGenerator, producer *kafka.Producer, emailVerificationBaseURL, passwordResetBaseURL string, userExistsCacheTTL time.Duration, rdb goredis.UniversalClient, stepUp StepUpPolicy, passkeys webauthn.Config, limitIncreaseDelay, contactChangeCooldown time.Duration, privacy PrivacyConfig, referrals ReferralConfig) *UserService {
 	if userExistsCacheTTL <= 0 {
 		userExistsCacheTTL = 15 * time.Minute
 	}
-	return &UserService{
+	deny := authn.NewDenyList(rdb, denyListTTL)
+	svc := &UserService{
 		userRepo:                 userRepo,
+		referralRepo:             userRepo,
+		tokenRepo:                userRepo,
+		recoveryRepo:             userRepo,
+		privacyRepo:              userRepo,
 		tokenGen:                 tokenGen,
 		producer:                 producer,
 		emailVerificationBaseURL: emailVerificationBaseURL,
 		passwordResetBaseURL:     passwordResetBaseURL,
 		userExistsCacheTTL:       userExistsCacheTTL,
+		limits:                   newAttemptLimiters(rdb),
+		deny:                     deny,
+		authenticator:            authn.NewAuthenticator(userTokenVerifier(), deny),
+		stepUp:                   stepUp,
+		passkeys:                 passkeys,
+		limitIncreaseDelay:       limitIncreaseDelay,
+		contactChangeCooldown:    contactChangeCooldown,
+		privacy:                  privacy,
+		referrals:                referrals,
+	}
+	if producer != nil {
+		svc.notifier = producer
+	}
+	return svc
+}
+
+// notificationSender publishes to the notification-events topic; *kafka.Producer is one.
+type notificationSender interface {
+	SendNotification(ev kafka.NotificationEvent) error
+}
+
+// sendNotification sends an event to the notification service (email, SMS, WhatsApp, push) via Kafka.
+func (s *UserService) sendNotification(ev kafka.NotificationEvent) error {
+	if s.notifier == nil {
+		return nil
 	}
+	return s.notifier.SendNotification(ev)
+}
+
+// Authenticator validates access tokens for the JWT routes and the gateway's /auth/validate.
+func (s *UserService) Authenticator() *authn.Authenticator {
+	return s.authenticator
 }
 
 // UserExists returns true if the user exists (Redis cache then DB). Used by auth validate to return 401 for deleted/invalid user IDs.
@@ -57,7 +115,18 @@ func (s *UserService) UserExists(ctx context.Context, userID string) (bool, erro
 	return exists, nil
 }
 
-func (s *UserService) CreateUser(ctx context.Context, email, password, firstName, lastName, phoneNumber string) (string, error) {
+// CreateUser registers an account. referralCode is optional; an unknown code fails registration with
+// ErrInvalidReferralCode so the user can correct it.
+func (s *UserService) CreateUser(ctx context.Context, email, password, firstName, lastName, phoneNumber, referralCode string) (string, error) {
+	var referrerID string
+	if referralCode = normalizeReferralCode(referralCode); referralCode != "" {
+		id, err := s.resolveReferralCode(referralCode)
+		if err != nil {
+			return "", err
+		}
+		referrerID = id
+	}
+
 	emailExists, err := s.userRepo.GetUserByEmail(email)
 	if err != nil {
 		return "", err
@@ -71,8 +140,7 @@ func (s *UserService) CreateUser(ctx context.Context, email, password, firstName
 		return "", err
 	}
 
-	h := sha256.Sum256([]byte(phoneNumber))
-	phoneHash := hex.EncodeToString(h[:])
+	phoneHash := hashPhone(phoneNumber)
 	existingByPhoneHash, err := s.userRepo.GetUserByPhoneNumberHash(phoneHash)
 	if err != nil {
 		return "", err
@@ -128,6 +196,10 @@ func (s *UserService) CreateUser(ctx context.Context, email, password, firstName
 		Metadata: map[string]interface{}{"email": email},
 	})
 
+	if referrerID != "" {
+		s.attributeReferral(referrerID, userID, referralCode, phoneHash)
+	}
+
 	s.sendVerificationEmail(email, firstName, lastName, token)
 
 	// Warm user-exists cache so the next auth_validate (e.g. after client gets token) does not 401
@@ -144,6 +216,12 @@ var Err2FAAlreadyEnabled = errors.New("two-factor authentication is already enab
 var Err2FANotEnabled = errors.New("two-factor authentication is not enabled")
 var ErrInvalidTOTPCode = errors.New("invalid or expired TOTP code")
 
+// ErrInvalidRefreshToken is returned for unknown or expired refresh tokens, and for tokens revoked by logout.
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// ErrRefreshTokenReused is returned when an already rotated refresh token is presented; its whole family has been revoked.
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected; please log in again")
+
 const totpPendingExpiry = 10 * time.Minute
 const totpIssuer = "PayUp"
 
@@ -153,11 +231,19 @@ type LoginResult struct {
 	Requires2FA *model.LoginRequires2FAResponse
 }
 
-func (s *UserService) Login(ctx context.Context, email, password string) (*LoginResult, error) {
+// Login checks the password of the account named by email, or by phoneNumber when email is empty.
+func (s *UserService) Login(ctx context.Context, email, phoneNumber, password string, device model.DeviceInfo) (*LoginResult, error) {
+	id := loginIdentity{Email: email, PhoneNumber: phoneNumber}
 	loginRequest := model.LoginRequest{
 		Email:    email,
 		Password: password,
 	}
+	if email == "" {
+		loginRequest.PhoneNumberHash = hashPhone(phoneNumber)
+	}
+	if err := s.checkLogin(ctx, id, device.IPAddress); err != nil {
+		return nil, err
+	}
 	user, err := s.userRepo.Login(loginRequest)
 	if err != nil {
 		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
@@ -166,8 +252,13 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*Login
 			Entity:   "user",
 			EntityID: "",
 			UserID:   nil,
-			Metadata: map[string]interface{}{"email": email, "reason": "login_error"},
+			Metadata: id.auditMetadata("login_error"),
 		})
+		if errors.Is(err, repository.ErrInvalidCredentials) {
+			if lockErr := s.failLogin(ctx, id, device.IPAddress, nil); lockErr != nil {
+				return nil, lockErr
+			}
+		}
 		return nil, err
 	}
 	if user == nil {
@@ -177,8 +268,11 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*Login
 			Entity:   "user",
 			EntityID: "",
 			UserID:   nil,
-			Metadata: map[string]interface{}{"email": email, "reason": "invalid_credentials"},
+			Metadata: id.auditMetadata("invalid_credentials"),
 		})
+		if lockErr := s.failLogin(ctx, id, device.IPAddress, nil); lockErr != nil {
+			return nil, lockErr
+		}
 		return nil, repository.ErrInvalidCredentials
 	}
 	if !user.EmailVerified {
@@ -188,7 +282,7 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*Login
 			Entity:   "user",
 			EntityID: user.ID,
 			UserID:   &user.ID,
-			Metadata: map[string]interface{}{"email": email, "reason": "email_not_verified"},
+			Metadata: id.auditMetadata("email_not_verified"),
 		})
 		return nil, ErrEmailNotVerified
 	}
@@ -199,10 +293,14 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*Login
 			Entity:   "user",
 			EntityID: user.ID,
 			UserID:   &user.ID,
-			Metadata: map[string]interface{}{"email": email, "reason": "invalid_password"},
+			Metadata: id.auditMetadata("invalid_password"),
 		})
+		if lockErr := s.failLogin(ctx, id, device.IPAddress, &user.ID); lockErr != nil {
+			return nil, lockErr
+		}
 		return nil, repository.ErrInvalidCredentials
 	}
+	s.limits.loginUser.Succeed(ctx, id.subject())
 
 	settings, err := s.userRepo.GetUserSettings(user.ID)
 	if err != nil {
@@ -215,21 +313,19 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*Login
 			return nil, err
 		}
 		redis.SetUserExists(ctx, user.ID, s.userExistsCacheTTL)
-		return &LoginResult{
-			Requires2FA: &model.LoginRequires2FAResponse{
-				RequiresTwoFactor:       true,
-				TwoFactorToken:          token,
-				TwoFactorTokenExpiresAt: expiresAt,
-				Message:                 "Enter the code from your authenticator app",
-			},
-		}, nil
+		requires := &model.LoginRequires2FAResponse{
+			RequiresTwoFactor:       true,
+			TwoFactorToken:          token,
+			TwoFactorTokenExpiresAt: expiresAt,
+			Message:                 "Enter the code from your authenticator app",
+		}
+		if settings.TwoFactorFallbackChannel != nil {
+			requires.FallbackChannel = *settings.TwoFactorFallbackChannel
+		}
+		return &LoginResult{Requires2FA: requires}, nil
 	}
 
-	accessToken, expiresAt, err := s.tokenGen.GenerateAccessToken(user.ID, user.Email)
-	if err != nil {
-		return nil, err
-	}
-	refreshToken, refreshExpiresAt, err := s.tokenGen.GenerateAndStoreRefreshToken(user.ID, s.userRepo)
+	resp, session, err := s.startSession(user, device)
 	if err != nil {
 		return nil, err
 	}
@@ -240,18 +336,11 @@ func (s *UserService) Login(ctx context.Context, email, password string) (*Login
 		Entity:   "user",
 		EntityID: user.ID,
 		UserID:   &user.ID,
-		Metadata: map[string]interface{}{"email": user.Email},
+		Metadata: map[string]interface{}{"email": user.Email, "session_id": session.ID, "device_id": session.Device.DeviceID},
 	})
 
 	redis.SetUserExists(ctx, user.ID, s.userExistsCacheTTL)
-	return &LoginResult{
-		Success: &model.LoginResponse{
-			AccessToken:      accessToken,
-			RefreshToken:     refreshToken,
-			ExpiresAt:        expiresAt,
-			RefreshExpiresAt: refreshExpiresAt,
-		},
-	}, nil
+	return &LoginResult{Success: resp}, nil
 }
 
 // Setup2FA starts 2FA enrollment: generates a TOTP secret, stores it as pending, returns secret and QR URL for the authenticator app.
@@ -282,33 +371,45 @@ func (s *UserService) Setup2FA(ctx context.Context, userID string) (secret, qrCo
 	return key.Secret(), key.URL(), nil
 }
 
-// VerifySetup2FA verifies the TOTP code and enables 2FA (moves pending secret to active).
-func (s *UserService) VerifySetup2FA(ctx context.Context, userID, code string) error {
+// VerifySetup2FA verifies the TOTP code, enables 2FA (moves pending secret to active) and returns the first set of
+// recovery codes.
+func (s *UserService) VerifySetup2FA(ctx context.Context, userID, code string) ([]string, error) {
 	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil || settings == nil {
-		return errors.New("user settings not found")
+		return nil, errors.New("user settings not found")
 	}
 	if settings.TotpSecretPending == nil {
-		return errors.New("no 2FA setup in progress; start with POST /2fa/setup")
+		return nil, errors.New("no 2FA setup in progress; start with POST /2fa/setup")
 	}
 	if settings.TotpPendingCreatedAt != nil && time.Since(*settings.TotpPendingCreatedAt) > totpPendingExpiry {
 		_ = s.userRepo.DisableTotp(userID) // clear pending
-		return errors.New("2FA setup expired; please start again")
+		return nil, errors.New("2FA setup expired; please start again")
 	}
 	if !totp.Validate(code, *settings.TotpSecretPending) {
-		return ErrInvalidTOTPCode
+		return nil, ErrInvalidTOTPCode
+	}
+	if err := s.userRepo.EnableTotpFromPending(userID); err != nil {
+		return nil, err
 	}
-	return s.userRepo.EnableTotpFromPending(userID)
+	return s.issueRecoveryCodes(userID)
 }
 
-// VerifyLogin2FA exchanges a 2FA pending token + TOTP code for access and refresh tokens.
-func (s *UserService) VerifyLogin2FA(ctx context.Context, twoFactorToken, code string) (*model.LoginResponse, error) {
-	claims, err := ValidateJWT(twoFactorToken)
+// VerifyLogin2FA exchanges a 2FA pending token + second factor for access and refresh tokens. method is
+// TwoFactorMethodTOTP (default), TwoFactorMethodRecoveryCode or TwoFactorMethodOTP (code from SendLogin2FAOTP).
+func (s *UserService) VerifyLogin2FA(ctx context.Context, twoFactorToken, method, code string, device model.DeviceInfo) (*model.LoginResponse, error) {
+	if method == "" {
+		method = TwoFactorMethodTOTP
+	}
+	claims, err := s.pending2FAClaims(ctx, twoFactorToken)
 	if err != nil {
-		return nil, errors.New("invalid or expired two-factor token")
+		return nil, err
 	}
-	if claims.Purpose != Purpose2FALogin {
-		return nil, errors.New("invalid token purpose")
+	user, err := s.userRepo.GetUserByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
 	}
 	settings, err := s.userRepo.GetUserSettings(claims.UserID)
 	if err != nil || settings == nil {
@@ -317,14 +418,35 @@ func (s *UserService) VerifyLogin2FA(ctx context.Context, twoFactorToken, code s
 	if !settings.TwoFactorEnabled || settings.TotpSecret == nil {
 		return nil, errors.New("2FA not enabled for this account")
 	}
-	if !totp.Validate(code, *settings.TotpSecret) {
-		return nil, ErrInvalidTOTPCode
+	if err := s.limits.twoFactor.Check(ctx, claims.UserID); err != nil {
+		return nil, err
 	}
-	accessToken, expiresAt, err := s.tokenGen.GenerateAccessToken(claims.UserID, claims.Email)
+	ok, err := s.verifySecondFactor(ctx, user, settings, method, code)
 	if err != nil {
 		return nil, err
 	}
-	refreshToken, refreshExpiresAt, err := s.tokenGen.GenerateAndStoreRefreshToken(claims.UserID, s.userRepo)
+	if !ok {
+		if lockErr := s.limits.twoFactor.Fail(ctx, claims.UserID); lockErr != nil {
+			_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+				Service:  "user",
+				Action:   "2fa_locked",
+				Entity:   "user",
+				EntityID: claims.UserID,
+				UserID:   &claims.UserID,
+				Metadata: map[string]interface{}{"ip": device.IPAddress, "method": method},
+			})
+			return nil, lockErr
+		}
+		return nil, ErrInvalidTOTPCode
+	}
+	s.limits.twoFactor.Succeed(ctx, claims.UserID)
+	if claims.RegisteredClaims.ExpiresAt != nil {
+		// The pending token is single use: a replay cannot open a second session.
+		if err := s.deny.RevokeToken(ctx, claims.ID, claims.RegisteredClaims.ExpiresAt.Time); err != nil {
+			log.Printf("user service: revoke 2fa token user=%s err=%v", claims.UserID, err)
+		}
+	}
+	resp, session, err := s.startSession(user, device)
 	if err != nil {
 		return nil, err
 	}
@@ -334,25 +456,20 @@ func (s *UserService) VerifyLogin2FA(ctx context.Context, twoFactorToken, code s
 		Entity:   "user",
 		EntityID: claims.UserID,
 		UserID:   &claims.UserID,
-		Metadata: map[string]interface{}{"email": claims.Email, "2fa": true},
+		Metadata: map[string]interface{}{"email": claims.Email, "2fa": true, "2fa_method": method, "session_id": session.ID, "device_id": session.Device.DeviceID},
 	})
 	redis.SetUserExists(ctx, claims.UserID, s.userExistsCacheTTL)
-	return &model.LoginResponse{
-		AccessToken:      accessToken,
-		RefreshToken:     refreshToken,
-		ExpiresAt:        expiresAt,
-		RefreshExpiresAt: refreshExpiresAt,
-	}, nil
+	return resp, nil
 }
 
-// Disable2FA turns off 2FA after verifying the user's password.
-func (s *UserService) Disable2FA(ctx context.Context, userID string, password string) error {
+// Disable2FA turns off 2FA after verifying the user's password or step-up token.
+func (s *UserService) Disable2FA(ctx context.Context, userID string, auth SensitiveAuth) error {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil || user == nil {
 		return errors.New("user not found")
 	}
-	if !passwd.CheckPassword(password, user.PasswordHash) {
-		return errors.New("invalid password")
+	if err := s.confirmSensitive(ctx, user, StepUpDisable2FA, auth); err != nil {
+		return err
 	}
 	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil || settings == nil {
@@ -361,7 +478,10 @@ func (s *UserService) Disable2FA(ctx context.Context, userID string, password st
 	if !settings.TwoFactorEnabled {
 		return Err2FANotEnabled
 	}
-	return s.userRepo.DisableTotp(userID)
+	if err := s.userRepo.DisableTotp(userID); err != nil {
+		return err
+	}
+	return s.userRepo.DeleteRecoveryCodes(userID)
 }
 
 // ListUsers returns users for admin (paginated). Excludes password.
@@ -380,35 +500,53 @@ func (s *UserService) GetUserForAdmin(ctx context.Context, userID string) (*mode
 	return user, nil
 }
 
+// TransferAuth is how the user approves a transfer: the PIN, or a passkey assertion (JSON of
+// dto.PasskeyAssertionRequest) over a challenge from POST /passkeys/transaction/begin for the same amount and Payee. A
+// PIN-approved transfer at or above the step-up threshold also needs StepUpToken; a user-verified passkey already
+// counts as two factors.
+type TransferAuth struct {
+	Pin              string
+	StepUpToken      string
+	PasskeyAssertion string
+	Payee            Payee
+}
+
 // ValidateTransfer checks whether the user is allowed to attempt a transfer (used by payment service).
-// Checks: user exists, not banking restricted, transfers not paused, PIN set and matches.
-// Returns allowed, message, and daily/monthly limits (0 = not set) so payment can enforce limits.
-func (s *UserService) ValidateTransfer(ctx context.Context, userID string, amount float64, pin string) (allowed bool, message string, dailyLimit, monthlyLimit float64) {
+// Checks: user exists, not banking restricted, transfers not paused, and the PIN (plus step-up for large amounts) or a
+// passkey approval. Returns allowed, message, and daily/monthly limits (0 = not set) so payment can enforce limits;
+// stepUpRequired tells payment to ask the client for a step-up token.
+func (s *UserService) ValidateTransfer(ctx context.Context, userID string, amount float64, auth TransferAuth) (allowed bool, message string, dailyLimit, monthlyLimit float64, stepUpRequired bool) {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
-		return false, "user lookup failed", 0, 0
+		return false, "user lookup failed", 0, 0, false
 	}
 	if user == nil {
-		return false, "user not found", 0, 0
+		return false, "user not found", 0, 0, false
 	}
 	if user.BankingRestricted {
-		return false, "account restricted", 0, 0
+		return false, "account restricted", 0, 0, false
 	}
 	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil || settings == nil {
-		return false, "settings not found", 0, 0
+		return false, "settings not found", 0, 0, false
 	}
 	if settings.TransfersDisabled {
-		return false, "transfers paused", 0, 0
+		return false, "transfers paused", 0, 0, false
 	}
-	if settings.PinHash == nil || *settings.PinHash == "" {
-		return false, "PIN not set", 0, 0
-	}
-	if pin == "" {
-		return false, "PIN required", 0, 0
-	}
-	if !passwd.CheckPassword(pin, *settings.PinHash) {
-		return false, "invalid PIN", 0, 0
+	if auth.PasskeyAssertion != "" {
+		if !settings.BiometricEnabled {
+			return false, ErrPasskeyPaymentsOff.Error(), 0, 0, false
+		}
+		if err := s.verifyTransferPasskey(ctx, userID, amount, auth.Payee, auth.PasskeyAssertion); err != nil {
+			return false, err.Error(), 0, 0, false
+		}
+	} else {
+		if msg := s.checkTransferPin(ctx, userID, settings, auth.Pin); msg != "" {
+			return false, msg, 0, 0, false
+		}
+		if msg := s.stepUpForTransfer(ctx, userID, amount, auth.StepUpToken); msg != "" {
+			return false, msg, 0, 0, true
+		}
 	}
 	if settings.DailyTransferLimit != nil {
 		dailyLimit = *settings.DailyTransferLimit
@@ -416,7 +554,38 @@ func (s *UserService) ValidateTransfer(ctx context.Context, userID string, amoun
 	if settings.MonthlyTransferLimit != nil {
 		monthlyLimit = *settings.MonthlyTransferLimit
 	}
-	return true, "", dailyLimit, monthlyLimit
+	return true, "", dailyLimit, monthlyLimit, false
+}
+
+// checkTransferPin verifies the transaction PIN under the PIN attempt limit, reserving the attempt before the hash is
+// checked so parallel guesses cannot exceed it. Returns the refusal message, or "".
+func (s *UserService) checkTransferPin(ctx context.Context, userID string, settings *model.UserSettings, pin string) string {
+	if settings.PinHash == nil || *settings.PinHash == "" {
+		return "PIN not set"
+	}
+	if pin == "" {
+		return "PIN required"
+	}
+	attempt, err := s.limits.pin.Reserve(ctx, userID)
+	if err != nil {
+		return pinLockedMessage(err)
+	}
+	if !passwd.CheckPassword(pin, *settings.PinHash) {
+		if lockErr := attempt.Fail(ctx); lockErr != nil {
+			_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+				Service:  "user",
+				Action:   "pin_locked",
+				Entity:   "user",
+				EntityID: userID,
+				UserID:   &userID,
+				Metadata: map[string]interface{}{"reason": "too_many_invalid_pins"},
+			})
+			return pinLockedMessage(lockErr)
+		}
+		return "invalid PIN"
+	}
+	attempt.Succeed(ctx)
+	return ""
 }
 
 // SetUserRestricted sets the user's banking_restricted flag (admin only). Sends audit event and notification email when restricting.
@@ -442,6 +611,9 @@ func (s *UserService) SetUserRestricted(ctx context.Context, userID string, rest
 	})
 	// Notify user by email when restricting (not when unrestricting)
 	if restricted {
+		if err := s.RevokeAllSessions(ctx, userID, repository.RevokeReasonUserRestricted); err != nil {
+			log.Printf("user service: revoke sessions after restriction user=%s err=%v", userID, err)
+		}
 		toName := strings.TrimSpace(user.FirstName + " " + user.LastName)
 		if toName == "" {
 			toName = user.Email
@@ -449,7 +621,8 @@ func (s *UserService) SetUserRestricted(ctx context.Context, userID string, rest
 		subject := "Account restriction notice"
 		body := "Your account has been restricted from certain banking activities. If you have questions, please contact support."
 		html := `<p>Your account has been restricted from certain banking activities.</p><p>If you have questions, please contact support.</p>`
-		_ = s.producer.SendNotification(kafka.NotificationEvent{
+		_ = s.sendNotification(kafka.NotificationEvent{
+			UserID:  userID,
 			Type:    "user_restricted",
 			Channel: "email",
 			Metadata: map[string]interface{}{
@@ -579,6 +752,9 @@ func (s *UserService) ResetPassword(ctx context.Context, token, newPassword stri
 	if err := s.userRepo.MarkPasswordResetTokenUsed(tokenDetails.ID); err != nil {
 		return err
 	}
+	if err := s.RevokeAllSessions(ctx, tokenDetails.UserID, repository.RevokeReasonPasswordReset); err != nil {
+		log.Printf("user service: revoke sessions after password reset user=%s err=%v", tokenDetails.UserID, err)
+	}
 
 	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
 		Service:  "user",
@@ -613,6 +789,9 @@ func (s *UserService) ChangePassword(ctx context.Context, email, oldPassword, ne
 	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword, updatedAt); err != nil {
 		return err
 	}
+	if err := s.RevokeAllSessions(ctx, user.ID, repository.RevokeReasonPasswordChanged); err != nil {
+		log.Printf("user service: revoke sessions after password change user=%s err=%v", user.ID, err)
+	}
 	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
 		Service:  "user",
 		Action:   "password_changed",
@@ -624,22 +803,175 @@ func (s *UserService) ChangePassword(ctx context.Context, email, oldPassword, ne
 	return nil
 }
 
-func (s *UserService) RefreshToken(ctx context.Context, token string) (string, error) {
-	tokenDetails, err := s.userRepo.GetRefreshToken(token)
+// refreshTokenStore is the part of the user repository that rotates and revokes refresh tokens.
+type refreshTokenStore interface {
+	repository.RefreshTokenInserter
+	GetRefreshToken(tokenHashHex string) (*model.RefreshToken, error)
+	ConsumeRefreshToken(tokenID string) (bool, error)
+	RevokeRefreshTokenFamily(familyID, reason string) (int64, error)
+	GetUserByID(id string) (*model.User, error)
+	TouchSession(sessionID, ipAddress, appVersion string) error
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new refresh token in the same family.
+// The presented token is revoked. Presenting a token that was already rotated means it was copied, so the whole
+// family is revoked and the caller has to log in again. A token revoked any other way (logout, password change) is
+// simply invalid: its session has already ended.
+func (s *UserService) RefreshToken(ctx context.Context, token string, device model.DeviceInfo) (*model.LoginResponse, error) {
+	rt, err := s.getRefreshToken(token)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if tokenDetails == nil {
-		return "", errors.New("invalid token")
+	if rt.Revoked {
+		return nil, s.revokedRefreshToken(rt)
+	}
+	if time.Now().After(rt.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	user, err := s.tokenRepo.GetUserByID(rt.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	consumed, err := s.tokenRepo.ConsumeRefreshToken(rt.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		// Revoked since it was read: by a concurrent refresh of the same token (one caller holds a stolen copy), or by
+		// a logout.
+		if rt, err = s.getRefreshToken(token); err != nil {
+			return nil, err
+		}
+		return nil, s.revokedRefreshToken(rt)
+	}
+	accessToken, expiresAt, err := s.tokenGen.GenerateAccessToken(user.ID, user.Email, rt.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, refreshExpiresAt, err := s.tokenGen.GenerateAndStoreRefreshToken(user.ID, rt.FamilyID, s.tokenRepo)
+	if err != nil {
+		return nil, err
+	}
+	device = normalizeDevice(device)
+	if err := s.tokenRepo.TouchSession(rt.FamilyID, device.IPAddress, device.AppVersion); err != nil {
+		log.Printf("user service: touch session=%s err=%v", rt.FamilyID, err)
+	}
+	redis.SetUserExists(ctx, user.ID, s.userExistsCacheTTL)
+	return &model.LoginResponse{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// revokedRefreshToken is the error for presenting a revoked refresh token. Only a rotated token is evidence of a copy:
+// its family is revoked and ErrRefreshTokenReused returned.
+func (s *UserService) revokedRefreshToken(rt *model.RefreshToken) error {
+	if rt.RevokedReason != repository.RevokeReasonRotated {
+		return ErrInvalidRefreshToken
+	}
+	s.handleRefreshTokenReuse(rt)
+	return ErrRefreshTokenReused
+}
+
+// Logout revokes the refresh token and every token rotated from the same login, and puts the login's access tokens
+// on the deny-list. access, when the caller also sent its access token, is revoked by jti. Unknown or already revoked
+// tokens are not an error, so logout is safe to retry.
+func (s *UserService) Logout(ctx context.Context, token string, access *authn.Claims) error {
+	if access != nil && access.ExpiresAt != nil {
+		if err := s.deny.RevokeToken(ctx, access.ID, access.ExpiresAt.Time); err != nil {
+			log.Printf("user service: revoke access token user=%s err=%v", access.UserID, err)
+		}
+	}
+	rt, err := s.getRefreshToken(token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+	n, err := s.tokenRepo.RevokeRefreshTokenFamily(rt.FamilyID, repository.RevokeReasonLogout)
+	if err != nil {
+		return err
+	}
+	s.revokeSessionAccess(ctx, rt.FamilyID)
+	if n > 0 {
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "logout",
+			Entity:   "user",
+			EntityID: rt.UserID,
+			UserID:   &rt.UserID,
+			Metadata: map[string]interface{}{"family_id": rt.FamilyID},
+		})
+	}
+	return nil
+}
+
+// RevokeAllSessions revokes every refresh token of the user and every access token issued so far.
+func (s *UserService) RevokeAllSessions(ctx context.Context, userID, reason string) error {
+	n, err := s.userRepo.RevokeAllRefreshTokens(userID, reason)
+	if err != nil {
+		return err
+	}
+	if err := s.deny.RevokeUser(ctx, userID); err != nil {
+		log.Printf("user service: deny-list revoke user=%s err=%v", userID, err)
+	}
+	if n > 0 {
+		_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+			Service:  "user",
+			Action:   "sessions_revoked",
+			Entity:   "user",
+			EntityID: userID,
+			UserID:   &userID,
+			Metadata: map[string]interface{}{"reason": reason, "count": n},
+		})
+	}
+	return nil
+}
+
+func (s *UserService) getRefreshToken(token string) (*model.RefreshToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	hashed := sha256.Sum256([]byte(token))
+	rt, err := s.tokenRepo.GetRefreshToken(hex.EncodeToString(hashed[:]))
+	if err != nil {
+		return nil, err
+	}
+	if rt == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	return rt, nil
+}
+
+func (s *UserService) handleRefreshTokenReuse(rt *model.RefreshToken) {
+	n, err := s.tokenRepo.RevokeRefreshTokenFamily(rt.FamilyID, repository.RevokeReasonReuseDetected)
+	if err != nil {
+		log.Printf("user service: revoke refresh token family=%s after reuse failed err=%v", rt.FamilyID, err)
+		return
 	}
-	return "", nil
+	s.revokeSessionAccess(context.Background(), rt.FamilyID)
+	_ = s.producer.SendAuditLog(kafka.AuditLogParams{
+		Service:  "user",
+		Action:   "refresh_token_reuse",
+		Entity:   "user",
+		EntityID: rt.UserID,
+		UserID:   &rt.UserID,
+		Metadata: map[string]interface{}{"family_id": rt.FamilyID, "revoked": n},
+	})
 }
 
 func (s *UserService) sendVerificationEmail(to, firstName, lastName, token string) {
 	log.Printf("user service: sendVerificationEmail called to=%s firstName=%s token_len=%d baseURL_set=%v",
 		to, firstName, len(token), s.emailVerificationBaseURL != "")
-	if s.producer == nil {
-		log.Printf("user service: sendVerificationEmail skipped (producer is nil)")
+	if s.notifier == nil {
+		log.Printf("user service: sendVerificationEmail skipped (no notification producer)")
 		return
 	}
 	// Link format: {{baseUrl}}/verify-email?token=<token>
@@ -670,7 +1002,7 @@ func (s *UserService) sendVerificationEmail(to, firstName, lastName, token strin
 		},
 	}
 	log.Printf("user service: publishing notification event type=%s channel=%s to topic=notification-events", ev.Type, ev.Channel)
-	if err := s.producer.SendNotification(ev); err != nil {
+	if err := s.sendNotification(ev); err != nil {
 		log.Printf("user service: SendNotification failed err=%v", err)
 		return
 	}
@@ -678,7 +1010,7 @@ func (s *UserService) sendVerificationEmail(to, firstName, lastName, token strin
 }
 
 func (s *UserService) sendPasswordResetEmail(to, firstName, token string) {
-	if s.producer == nil {
+	if s.notifier == nil {
 		return
 	}
 	link := s.passwordResetBaseURL
@@ -693,7 +1025,7 @@ func (s *UserService) sendPasswordResetEmail(to, firstName, token string) {
 	}
 	subject := "Reset your password"
 	html := "<p>Hi " + firstName + ",</p><p>We received a request to reset your password. Click the link below to set a new password:</p><p><a href=\"" + link + "\">Reset password</a></p><p>This link expires in 1 hour. If you didn't request this, you can ignore this email.</p>"
-	_ = s.producer.SendNotification(kafka.NotificationEvent{
+	_ = s.sendNotification(kafka.NotificationEvent{
 		Type:    "password_reset",
 		Channel: "email",
 		Metadata: map[string]interface{}{
@@ -714,12 +1046,19 @@ func (s *UserService) GetSettings(ctx context.Context, userID string) (*dto.Sett
 	if settings == nil {
 		return nil, errors.New("user settings not found")
 	}
-	return toSettingsResponse(settings), nil
+	resp := toSettingsResponse(settings)
+	pending, err := s.userRepo.GetPendingLimitChange(userID)
+	if err != nil {
+		return nil, err
+	}
+	resp.PendingLimitChange = toPendingLimitChange(pending)
+	return resp, nil
 }
 
 // UpdateSettings applies a partial update to the user's settings. Only non-nil fields in req are updated. Creates default settings if missing.
-// When updating any field other than theme or language, password is required and verified.
-func (s *UserService) UpdateSettings(ctx context.Context, userID string, req *dto.UpdateSettingsRequest) (*dto.SettingsResponse, error) {
+// When updating any field other than theme or language, password is required and verified; raising limits, resuming
+// transfers and turning 2FA off need a step-up token instead when the policy says so.
+func (s *UserService) UpdateSettings(ctx context.Context, userID string, req *dto.UpdateSettingsRequest, auth SensitiveAuth) (*dto.SettingsResponse, error) {
 	current, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil {
 		return nil, err
@@ -728,22 +1067,36 @@ func (s *UserService) UpdateSettings(ctx context.Context, userID string, req *dt
 		return nil, errors.New("user settings not found")
 	}
 	// Protected fields (require password): anything other than theme and language.
+	var user *model.User
 	updatingProtected := req.BiometricEnabled != nil || req.TwoFactorEnabled != nil ||
 		req.DailyTransferLimit != nil || req.MonthlyTransferLimit != nil ||
 		req.TransactionAlertsEnabled != nil || req.TransfersDisabled != nil
 	if updatingProtected {
-		if req.Password == nil || *req.Password == "" {
+		if req.Password != nil {
+			auth.Password = *req.Password
+		}
+		if auth.Password == "" && auth.StepUpToken == "" {
 			return nil, errors.New("password required to update these settings")
 		}
-		user, err := s.userRepo.GetUserByID(userID)
+		user, err = s.userRepo.GetUserByID(userID)
 		if err != nil {
 			return nil, err
 		}
 		if user == nil {
 			return nil, errors.New("user not found")
 		}
-		if !passwd.CheckPassword(*req.Password, user.PasswordHash) {
-			return nil, errors.New("invalid password")
+		var actions []string
+		if limitsIncrease(current, req.DailyTransferLimit, req.MonthlyTransferLimit) {
+			actions = append(actions, StepUpIncreaseLimits)
+		}
+		if req.TransfersDisabled != nil && !*req.TransfersDisabled && current.TransfersDisabled {
+			actions = append(actions, StepUpResumeAccount)
+		}
+		if req.TwoFactorEnabled != nil && !*req.TwoFactorEnabled && current.TwoFactorEnabled {
+			actions = append(actions, StepUpDisable2FA)
+		}
+		if err := s.confirmSettingsUpdate(ctx, user, actions, auth); err != nil {
+			return nil, err
 		}
 	}
 	// Merge: only update fields that were sent (non-nil in req). PIN is set only via PUT /settings/pin.
@@ -753,12 +1106,11 @@ func (s *UserService) UpdateSettings(ctx context.Context, userID string, req *dt
 	if req.TwoFactorEnabled != nil {
 		current.TwoFactorEnabled = *req.TwoFactorEnabled
 	}
-	if req.DailyTransferLimit != nil {
-		current.DailyTransferLimit = req.DailyTransferLimit
-	}
-	if req.MonthlyTransferLimit != nil {
-		current.MonthlyTransferLimit = req.MonthlyTransferLimit
+	pending, err := s.userRepo.GetPendingLimitChange(userID)
+	if err != nil {
+		return nil, err
 	}
+	limits := s.stageLimitChange(current, pending, req.DailyTransferLimit, req.MonthlyTransferLimit, time.Now())
 	if req.TransactionAlertsEnabled != nil {
 		current.TransactionAlertsEnabled = *req.TransactionAlertsEnabled
 	}
@@ -775,11 +1127,18 @@ func (s *UserService) UpdateSettings(ctx context.Context, userID string, req *dt
 	if err := s.userRepo.UpdateUserSettings(current); err != nil {
 		return nil, err
 	}
-	return toSettingsResponse(current), nil
+	if user != nil {
+		if pending, err = s.commitLimitChange(user, pending, limits); err != nil {
+			return nil, err
+		}
+	}
+	resp := toSettingsResponse(current)
+	resp.PendingLimitChange = toPendingLimitChange(pending)
+	return resp, nil
 }
 
-// SetPin sets or updates the user's PIN (4 digits). Requires password; when user already has a PIN, currentPin is required and must match. Hashed server-side; never stored plain.
-func (s *UserService) SetPin(ctx context.Context, userID string, password string, currentPin *string, pin string) (*dto.SettingsResponse, error) {
+// SetPin sets or updates the user's PIN (4 digits). Requires password or step-up token; when user already has a PIN, currentPin is required and must match. Hashed server-side; never stored plain.
+func (s *UserService) SetPin(ctx context.Context, userID string, auth SensitiveAuth, currentPin *string, pin string) (*dto.SettingsResponse, error) {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
 		return nil, err
@@ -787,8 +1146,8 @@ func (s *UserService) SetPin(ctx context.Context, userID string, password string
 	if user == nil {
 		return nil, errors.New("user not found")
 	}
-	if !passwd.CheckPassword(password, user.PasswordHash) {
-		return nil, errors.New("invalid password")
+	if err := s.confirmSensitive(ctx, user, StepUpSetPin, auth); err != nil {
+		return nil, err
 	}
 	settings, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil {
@@ -818,8 +1177,9 @@ func (s *UserService) SetPin(ctx context.Context, userID string, password string
 	return toSettingsResponse(settings), nil
 }
 
-// SetLimits updates daily and/or monthly transfer limits for the user. Requires password.
-func (s *UserService) SetLimits(ctx context.Context, userID string, req *dto.SetLimitsRequest) (*dto.SettingsResponse, error) {
+// SetLimits updates daily and/or monthly transfer limits for the user. Lowering limits requires the password and applies
+// at once; raising them is the increase_limits step-up action and is scheduled behind the cooling-off period.
+func (s *UserService) SetLimits(ctx context.Context, userID string, req *dto.SetLimitsRequest, auth SensitiveAuth) (*dto.SettingsResponse, error) {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
 		return nil, err
@@ -827,9 +1187,6 @@ func (s *UserService) SetLimits(ctx context.Context, userID string, req *dto.Set
 	if user == nil {
 		return nil, errors.New("user not found")
 	}
-	if !passwd.CheckPassword(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid password")
-	}
 	current, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil {
 		return nil, err
@@ -837,30 +1194,42 @@ func (s *UserService) SetLimits(ctx context.Context, userID string, req *dto.Set
 	if current == nil {
 		return nil, errors.New("user settings not found")
 	}
-	if req.DailyTransferLimit != nil {
-		current.DailyTransferLimit = req.DailyTransferLimit
+	auth.Password = req.Password
+	var actions []string
+	if limitsIncrease(current, req.DailyTransferLimit, req.MonthlyTransferLimit) {
+		actions = append(actions, StepUpIncreaseLimits)
+	}
+	if err := s.confirmSettingsUpdate(ctx, user, actions, auth); err != nil {
+		return nil, err
 	}
-	if req.MonthlyTransferLimit != nil {
-		current.MonthlyTransferLimit = req.MonthlyTransferLimit
+	pending, err := s.userRepo.GetPendingLimitChange(userID)
+	if err != nil {
+		return nil, err
 	}
+	limits := s.stageLimitChange(current, pending, req.DailyTransferLimit, req.MonthlyTransferLimit, time.Now())
 	current.UpdatedAt = time.Now()
 	if err := s.userRepo.UpdateUserSettings(current); err != nil {
 		return nil, err
 	}
-	return toSettingsResponse(current), nil
+	if pending, err = s.commitLimitChange(user, pending, limits); err != nil {
+		return nil, err
+	}
+	resp := toSettingsResponse(current)
+	resp.PendingLimitChange = toPendingLimitChange(pending)
+	return resp, nil
 }
 
-// PauseAccount sets transfers_disabled = true (disables transfers). Requires password.
-func (s *UserService) PauseAccount(ctx context.Context, userID string, password string) (*dto.SettingsResponse, error) {
-	return s.setTransfersDisabled(ctx, userID, password, true)
+// PauseAccount sets transfers_disabled = true (disables transfers). Requires password or step-up token.
+func (s *UserService) PauseAccount(ctx context.Context, userID string, auth SensitiveAuth) (*dto.SettingsResponse, error) {
+	return s.setTransfersDisabled(ctx, userID, auth, true)
 }
 
-// ResumeAccount sets transfers_disabled = false (re-enables transfers). Requires password.
-func (s *UserService) ResumeAccount(ctx context.Context, userID string, password string) (*dto.SettingsResponse, error) {
-	return s.setTransfersDisabled(ctx, userID, password, false)
+// ResumeAccount sets transfers_disabled = false (re-enables transfers). Requires password or step-up token.
+func (s *UserService) ResumeAccount(ctx context.Context, userID string, auth SensitiveAuth) (*dto.SettingsResponse, error) {
+	return s.setTransfersDisabled(ctx, userID, auth, false)
 }
 
-func (s *UserService) setTransfersDisabled(ctx context.Context, userID string, password string, disabled bool) (*dto.SettingsResponse, error) {
+func (s *UserService) setTransfersDisabled(ctx context.Context, userID string, auth SensitiveAuth, disabled bool) (*dto.SettingsResponse, error) {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
 		return nil, err
@@ -868,8 +1237,12 @@ func (s *UserService) setTransfersDisabled(ctx context.Context, userID string, p
 	if user == nil {
 		return nil, errors.New("user not found")
 	}
-	if !passwd.CheckPassword(password, user.PasswordHash) {
-		return nil, errors.New("invalid password")
+	action := StepUpResumeAccount
+	if disabled {
+		action = StepUpPauseAccount
+	}
+	if err := s.confirmSensitive(ctx, user, action, auth); err != nil {
+		return nil, err
 	}
 	current, err := s.userRepo.GetOrCreateUserSettings(userID)
 	if err != nil {
@@ -153,6 +153,31 @@ func TestLoginRequest_Validation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "phone number",
+			req: dto.LoginRequest{
+				PhoneNumber: "08012345678",
+				Password:    "anypassword",
+			},
+			wantErr: false,
+		},
+		{
+			name: "short phone number",
+			req: dto.LoginRequest{
+				PhoneNumber: "0801",
+				Password:    "anypassword",
+			},
+			wantErr: true,
+		},
+		{
+			name: "email and phone number",
+			req: dto.LoginRequest{
+				Email:       "user@example.com",
+				PhoneNumber: "08012345678",
+				Password:    "anypassword",
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty",
 			req:  dto.LoginRequest{},
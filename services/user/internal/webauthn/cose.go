@@ -0,0 +1,141 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ugorji/go/codec"
+)
+
+// COSE algorithm identifiers offered in pubKeyCredParams, most preferred first.
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// SupportedAlgorithms are the credential key algorithms the server accepts.
+var SupportedAlgorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key parameters (RFC 9052/9053) used by WebAuthn credential keys.
+const (
+	coseKty = 1
+	coseAlg = 3
+	coseCrv = -1 // EC2/OKP curve; RSA modulus n
+	coseX   = -2 // EC2/OKP x; RSA exponent e
+	coseY   = -3
+
+	coseKtyOKP     = 1
+	coseKtyEC2     = 2
+	coseKtyRSA     = 3
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+)
+
+var ErrUnsupportedKey = errors.New("webauthn: unsupported credential public key")
+
+var cborHandle codec.CborHandle
+
+// publicKey is a parsed COSE credential key.
+type publicKey struct {
+	alg int
+	key crypto.PublicKey
+}
+
+// decodeCOSEKey decodes the COSE key at the start of b and returns it with the number of bytes it used.
+func decodeCOSEKey(b []byte) (map[int]interface{}, int, error) {
+	dec := codec.NewDecoderBytes(b, &cborHandle)
+	var m map[int]interface{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, 0, fmt.Errorf("webauthn: credential public key: %w", err)
+	}
+	return m, dec.NumBytesRead(), nil
+}
+
+// parsePublicKey parses a stored COSE key.
+func parsePublicKey(cose []byte) (*publicKey, error) {
+	m, _, err := decodeCOSEKey(cose)
+	if err != nil {
+		return nil, err
+	}
+	return publicKeyFromCOSE(m)
+}
+
+func publicKeyFromCOSE(m map[int]interface{}) (*publicKey, error) {
+	kty, _ := coseInt(m[coseKty])
+	alg, _ := coseInt(m[coseAlg])
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := coseInt(m[coseCrv])
+		x, _ := m[coseX].([]byte)
+		y, _ := m[coseY].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, key: pub}, nil
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := coseInt(m[coseCrv])
+		x, _ := m[coseX].([]byte)
+		if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, _ := m[coseCrv].([]byte)
+		e, _ := m[coseX].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}}, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// verify checks sig over data with the key's algorithm.
+func (k *publicKey) verify(data, sig []byte) error {
+	return verifySignature(k.alg, k.key, data, sig)
+}
+
+func verifySignature(alg int, key crypto.PublicKey, data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	switch alg {
+	case AlgES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if ok && ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return nil
+		}
+	case AlgEdDSA:
+		pub, ok := key.(ed25519.PublicKey)
+		if ok && ed25519.Verify(pub, data, sig) {
+			return nil
+		}
+	case AlgRS256:
+		pub, ok := key.(*rsa.PublicKey)
+		if ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	}
+	return ErrSignature
+}
+
+// coseInt reads a CBOR integer, which decodes as uint64 (non-negative) or int64 (negative).
+func coseInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case uint64:
+		return int(n), true
+	case int64:
+		return int(n), true
+	}
+	return 0, false
+}
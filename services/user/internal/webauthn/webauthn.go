@@ -0,0 +1,442 @@
+// Package webauthn verifies passkey (WebAuthn) registration and assertion responses on the server. It implements the
+// relying-party checks of WebAuthn Level 2 that PayUp needs: "none" and "packed" attestation, ES256, EdDSA and RS256
+// credential keys, user verification and signature counters. Challenges and credentials are stored by the caller.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ugorji/go/codec"
+)
+
+var (
+	ErrClientData      = errors.New("webauthn: invalid client data")
+	ErrChallenge       = errors.New("webauthn: challenge mismatch")
+	ErrOrigin          = errors.New("webauthn: origin not allowed")
+	ErrRPID            = errors.New("webauthn: authenticator data is for another relying party")
+	ErrUserPresence    = errors.New("webauthn: user presence not asserted")
+	ErrUserVerified    = errors.New("webauthn: user verification required")
+	ErrAttestation     = errors.New("webauthn: invalid or unsupported attestation")
+	ErrAuthData        = errors.New("webauthn: malformed authenticator data")
+	ErrSignature       = errors.New("webauthn: signature verification failed")
+	ErrSignCount       = errors.New("webauthn: signature counter did not increase; the authenticator may be cloned")
+	ErrCredentialMatch = errors.New("webauthn: response is for a different credential")
+)
+
+// Authenticator data flags.
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackedUp       = 0x10
+	flagAttestedData   = 0x40
+	flagExtensions     = 0x80
+)
+
+// DefaultTimeoutMs is the ceremony timeout suggested to the client.
+const DefaultTimeoutMs = 120000
+
+// Config identifies the relying party. Origins lists every origin the ceremonies may come from: web origins such as
+// https://app.payup.ng and Android app origins (android:apk-key-hash:...).
+type Config struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+// Credential is a verified new passkey to store.
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // COSE_Key as sent by the authenticator
+	Algorithm      int
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	BackupEligible bool
+	BackedUp       bool
+	AttestationFmt string
+}
+
+// CredentialCreationResponse is the JSON of the PublicKeyCredential from navigator.credentials.create, with binary
+// fields base64url-encoded.
+type CredentialCreationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports,omitempty"`
+	} `json:"response"`
+}
+
+// CredentialAssertionResponse is the JSON of the PublicKeyCredential from navigator.credentials.get.
+type CredentialAssertionResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// CredentialID returns the decoded rawId (or id).
+func (r *CredentialAssertionResponse) CredentialID() ([]byte, error) {
+	return credentialID(r.RawID, r.ID)
+}
+
+// UserHandleBytes returns the decoded user handle; empty for non-discoverable credentials.
+func (r *CredentialAssertionResponse) UserHandleBytes() ([]byte, error) {
+	return DecodeBase64URL(r.Response.UserHandle)
+}
+
+// Descriptor names a credential in allowCredentials/excludeCredentials.
+type Descriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// NewDescriptor returns a public-key descriptor for a stored credential.
+func NewDescriptor(id []byte, transports []string) Descriptor {
+	return Descriptor{Type: "public-key", ID: EncodeBase64URL(id), Transports: transports}
+}
+
+// CreationOptions is PublicKeyCredentialCreationOptions for navigator.credentials.create (binary fields base64url).
+type CreationOptions struct {
+	Challenge string `json:"challenge"`
+	RP        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams       []CredentialParameter `json:"pubKeyCredParams"`
+	Timeout                int                   `json:"timeout"`
+	ExcludeCredentials     []Descriptor          `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection struct {
+		ResidentKey        string `json:"residentKey"`
+		RequireResidentKey bool   `json:"requireResidentKey"`
+		UserVerification   string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+// CredentialParameter is one entry of pubKeyCredParams.
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// RequestOptions is PublicKeyCredentialRequestOptions for navigator.credentials.get.
+type RequestOptions struct {
+	Challenge        string       `json:"challenge"`
+	Timeout          int          `json:"timeout"`
+	RPID             string       `json:"rpId"`
+	AllowCredentials []Descriptor `json:"allowCredentials,omitempty"`
+	UserVerification string       `json:"userVerification"`
+}
+
+// NewChallenge returns a random base64url challenge.
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return EncodeBase64URL(b), nil
+}
+
+// CreationOptions asks for a discoverable, user-verified passkey without attestation. userHandle is the opaque user
+// ID the authenticator returns at login; exclude stops the same authenticator being registered twice.
+func (c Config) CreationOptions(challenge string, userHandle []byte, name, displayName string, exclude []Descriptor) *CreationOptions {
+	o := &CreationOptions{Challenge: challenge, Timeout: DefaultTimeoutMs, ExcludeCredentials: exclude, Attestation: "none"}
+	o.RP.ID, o.RP.Name = c.RPID, c.RPName
+	o.User.ID, o.User.Name, o.User.DisplayName = EncodeBase64URL(userHandle), name, displayName
+	for _, alg := range SupportedAlgorithms {
+		o.PubKeyCredParams = append(o.PubKeyCredParams, CredentialParameter{Type: "public-key", Alg: alg})
+	}
+	o.AuthenticatorSelection.ResidentKey = "required"
+	o.AuthenticatorSelection.RequireResidentKey = true
+	o.AuthenticatorSelection.UserVerification = "required"
+	return o
+}
+
+// RequestOptions asks for a user-verified assertion. An empty allow list lets the user pick any passkey for the RP.
+func (c Config) RequestOptions(challenge string, allow []Descriptor) *RequestOptions {
+	return &RequestOptions{Challenge: challenge, Timeout: DefaultTimeoutMs, RPID: c.RPID, AllowCredentials: allow, UserVerification: "required"}
+}
+
+// clientData is CollectedClientData.
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// verifyClientData checks the ceremony type, challenge and origin and returns the hash signed by the authenticator.
+func (c Config) verifyClientData(raw []byte, ceremony, challenge string) ([32]byte, error) {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil || cd.Type != ceremony {
+		return [32]byte{}, ErrClientData
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(strings.TrimRight(cd.Challenge, "=")), []byte(strings.TrimRight(challenge, "="))) != 1 {
+		return [32]byte{}, ErrChallenge
+	}
+	if cd.CrossOrigin || !c.originAllowed(cd.Origin) {
+		return [32]byte{}, ErrOrigin
+	}
+	return sha256.Sum256(raw), nil
+}
+
+func (c Config) originAllowed(origin string) bool {
+	for _, o := range c.Origins {
+		if o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticatorData is the parsed authData structure.
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	aaguid    []byte
+	credID    []byte
+	credKey   map[int]interface{}
+	rawKey    []byte
+}
+
+func parseAuthenticatorData(b []byte) (*authenticatorData, error) {
+	if len(b) < 37 {
+		return nil, ErrAuthData
+	}
+	ad := &authenticatorData{rpIDHash: b[:32], flags: b[32], signCount: binary.BigEndian.Uint32(b[33:37])}
+	rest := b[37:]
+	if ad.flags&flagAttestedData != 0 {
+		if len(rest) < 18 {
+			return nil, ErrAuthData
+		}
+		ad.aaguid = rest[:16]
+		n := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if n == 0 || n > 1023 || len(rest) < n {
+			return nil, ErrAuthData
+		}
+		ad.credID = rest[:n]
+		rest = rest[n:]
+		key, used, err := decodeCOSEKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		ad.credKey, ad.rawKey = key, rest[:used]
+		rest = rest[used:]
+	}
+	if ad.flags&flagExtensions == 0 && len(rest) != 0 {
+		return nil, ErrAuthData
+	}
+	return ad, nil
+}
+
+// checkFlags verifies the RP ID hash, user presence and, if required, user verification.
+func (c Config) checkFlags(ad *authenticatorData, requireUV bool) error {
+	want := sha256.Sum256([]byte(c.RPID))
+	if !bytes.Equal(ad.rpIDHash, want[:]) {
+		return ErrRPID
+	}
+	if ad.flags&flagUserPresent == 0 {
+		return ErrUserPresence
+	}
+	if requireUV && ad.flags&flagUserVerified == 0 {
+		return ErrUserVerified
+	}
+	return nil
+}
+
+// attestationObject is the CBOR map returned by navigator.credentials.create.
+type attestationObject struct {
+	Fmt      string                 `codec:"fmt"`
+	AttStmt  map[string]interface{} `codec:"attStmt"`
+	AuthData []byte                 `codec:"authData"`
+}
+
+// VerifyRegistration checks a registration response against the challenge issued for it and returns the credential.
+func (c Config) VerifyRegistration(resp *CredentialCreationResponse, challenge string, requireUV bool) (*Credential, error) {
+	if resp == nil || resp.Type != "public-key" {
+		return nil, ErrClientData
+	}
+	rawClientData, err := DecodeBase64URL(resp.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrClientData
+	}
+	clientDataHash, err := c.verifyClientData(rawClientData, "webauthn.create", challenge)
+	if err != nil {
+		return nil, err
+	}
+	rawAtt, err := DecodeBase64URL(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, ErrAttestation
+	}
+	var att attestationObject
+	if err := codec.NewDecoderBytes(rawAtt, &cborHandle).Decode(&att); err != nil {
+		return nil, ErrAttestation
+	}
+	ad, err := parseAuthenticatorData(att.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkFlags(ad, requireUV); err != nil {
+		return nil, err
+	}
+	if ad.credID == nil {
+		return nil, ErrAuthData
+	}
+	pub, err := publicKeyFromCOSE(ad.credKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAttestation(&att, pub, clientDataHash); err != nil {
+		return nil, err
+	}
+	if id, err := credentialID(resp.RawID, resp.ID); err != nil || !bytes.Equal(id, ad.credID) {
+		return nil, ErrCredentialMatch
+	}
+	return &Credential{
+		ID:             bytes.Clone(ad.credID),
+		PublicKey:      bytes.Clone(ad.rawKey),
+		Algorithm:      pub.alg,
+		SignCount:      ad.signCount,
+		AAGUID:         bytes.Clone(ad.aaguid),
+		Transports:     resp.Response.Transports,
+		BackupEligible: ad.flags&flagBackupEligible != 0,
+		BackedUp:       ad.flags&flagBackedUp != 0,
+		AttestationFmt: att.Fmt,
+	}, nil
+}
+
+// verifyAttestation accepts "none" and "packed". Packed statements are checked for a valid signature (self
+// attestation, or by the leaf certificate); certificate chains are not evaluated since PayUp does not restrict
+// authenticator models.
+func verifyAttestation(att *attestationObject, credKey *publicKey, clientDataHash [32]byte) error {
+	switch att.Fmt {
+	case "none":
+		if len(att.AttStmt) != 0 {
+			return ErrAttestation
+		}
+		return nil
+	case "packed":
+		alg, ok := coseInt(att.AttStmt["alg"])
+		sig, _ := att.AttStmt["sig"].([]byte)
+		if !ok || len(sig) == 0 {
+			return ErrAttestation
+		}
+		signed := append(bytes.Clone(att.AuthData), clientDataHash[:]...)
+		x5c, _ := att.AttStmt["x5c"].([]interface{})
+		if len(x5c) == 0 {
+			if alg != credKey.alg || credKey.verify(signed, sig) != nil {
+				return ErrAttestation
+			}
+			return nil
+		}
+		der, _ := x5c[0].([]byte)
+		cert, err := x509.ParseCertificate(der)
+		if err != nil || verifySignature(alg, cert.PublicKey, signed, sig) != nil {
+			return ErrAttestation
+		}
+		return nil
+	}
+	return ErrAttestation
+}
+
+// Assertion is the verified result of an authentication ceremony.
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+	BackedUp     bool
+}
+
+// VerifyAssertion checks an assertion for the stored credential (COSE key and last signature counter) against the
+// challenge issued for it.
+func (c Config) VerifyAssertion(resp *CredentialAssertionResponse, challenge string, publicKeyCOSE []byte, storedSignCount uint32, requireUV bool) (*Assertion, error) {
+	if resp == nil || resp.Type != "public-key" {
+		return nil, ErrClientData
+	}
+	rawClientData, err := DecodeBase64URL(resp.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrClientData
+	}
+	clientDataHash, err := c.verifyClientData(rawClientData, "webauthn.get", challenge)
+	if err != nil {
+		return nil, err
+	}
+	rawAuthData, err := DecodeBase64URL(resp.Response.AuthenticatorData)
+	if err != nil {
+		return nil, ErrAuthData
+	}
+	ad, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkFlags(ad, requireUV); err != nil {
+		return nil, err
+	}
+	sig, err := DecodeBase64URL(resp.Response.Signature)
+	if err != nil {
+		return nil, ErrSignature
+	}
+	pub, err := parsePublicKey(publicKeyCOSE)
+	if err != nil {
+		return nil, err
+	}
+	if err := pub.verify(append(bytes.Clone(rawAuthData), clientDataHash[:]...), sig); err != nil {
+		return nil, err
+	}
+	// Authenticators that keep no counter always send 0 (synced passkeys do this).
+	if (ad.signCount != 0 || storedSignCount != 0) && ad.signCount <= storedSignCount {
+		return nil, ErrSignCount
+	}
+	return &Assertion{
+		SignCount:    ad.signCount,
+		UserVerified: ad.flags&flagUserVerified != 0,
+		BackedUp:     ad.flags&flagBackedUp != 0,
+	}, nil
+}
+
+// EncodeBase64URL encodes without padding, as WebAuthn JSON does.
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL decodes base64url with or without padding.
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func credentialID(rawID, id string) ([]byte, error) {
+	if rawID == "" {
+		rawID = id
+	}
+	b, err := DecodeBase64URL(rawID)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("webauthn: invalid credential id")
+	}
+	return b, nil
+}
@@ -0,0 +1,278 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	testRPID   = "payup.test"
+	testOrigin = "https://app.payup.test"
+)
+
+var testConfig = Config{RPID: testRPID, RPName: "PayUp", Origins: []string{testOrigin, "android:apk-key-hash:abc"}}
+
+// softAuthenticator is a software passkey: it answers create/get the way a platform authenticator does.
+type softAuthenticator struct {
+	t         *testing.T
+	alg       int
+	ecKey     *ecdsa.PrivateKey
+	edKey     ed25519.PrivateKey
+	credID    []byte
+	signCount uint32
+	counter   bool // false: always report 0, like synced passkeys
+	uv        bool
+	origin    string
+	rpID      string
+}
+
+func newSoftAuthenticator(t *testing.T, alg int) *softAuthenticator {
+	a := &softAuthenticator{t: t, alg: alg, credID: randomBytes(t, 16), counter: true, uv: true, origin: testOrigin, rpID: testRPID}
+	var err error
+	switch alg {
+	case AlgES256:
+		a.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, a.edKey, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (a *softAuthenticator) cbor(v interface{}) []byte {
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, &cborHandle).Encode(v); err != nil {
+		a.t.Fatal(err)
+	}
+	return out
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	if a.alg == AlgEdDSA {
+		return a.cbor(map[int]interface{}{coseKty: coseKtyOKP, coseAlg: AlgEdDSA, coseCrv: coseCrvEd25519, coseX: []byte(a.edKey.Public().(ed25519.PublicKey))})
+	}
+	pub, err := a.ecKey.PublicKey.Bytes()
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return a.cbor(map[int]interface{}{coseKty: coseKtyEC2, coseAlg: AlgES256, coseCrv: coseCrvP256, coseX: pub[1:33], coseY: pub[33:]})
+}
+
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpHash := sha256.Sum256([]byte(a.rpID))
+	flags := byte(flagUserPresent)
+	if a.uv {
+		flags |= flagUserVerified
+	}
+	if a.counter {
+		a.signCount++
+	}
+	out := append([]byte{}, rpHash[:]...)
+	out = append(out, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(out[33:], a.signCount)
+	if attested {
+		out[32] = flags | flagAttestedData
+		out = append(out, make([]byte, 16)...) // AAGUID
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credID)))
+		out = append(out, a.credID...)
+		out = append(out, a.coseKey()...)
+		return out
+	}
+	out[32] = flags
+	return out
+}
+
+func (a *softAuthenticator) clientData(typ, challenge string) []byte {
+	b, _ := json.Marshal(map[string]interface{}{"type": typ, "challenge": challenge, "origin": a.origin, "crossOrigin": false})
+	return b
+}
+
+func (a *softAuthenticator) sign(data []byte) []byte {
+	if a.alg == AlgEdDSA {
+		return ed25519.Sign(a.edKey, data)
+	}
+	digest := sha256.Sum256(data)
+	sig, err := a.ecKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return sig
+}
+
+// create answers navigator.credentials.create; packed adds a self-attestation statement instead of "none".
+func (a *softAuthenticator) create(challenge string, packed bool) *CredentialCreationResponse {
+	cd := a.clientData("webauthn.create", challenge)
+	authData := a.authData(true)
+	att := map[string]interface{}{"fmt": "none", "attStmt": map[string]interface{}{}, "authData": authData}
+	if packed {
+		cdHash := sha256.Sum256(cd)
+		att["fmt"] = "packed"
+		att["attStmt"] = map[string]interface{}{"alg": a.alg, "sig": a.sign(append(append([]byte{}, authData...), cdHash[:]...))}
+	}
+	resp := &CredentialCreationResponse{ID: EncodeBase64URL(a.credID), RawID: EncodeBase64URL(a.credID), Type: "public-key"}
+	resp.Response.ClientDataJSON = EncodeBase64URL(cd)
+	resp.Response.AttestationObject = EncodeBase64URL(a.cbor(att))
+	resp.Response.Transports = []string{"internal", "hybrid"}
+	return resp
+}
+
+// get answers navigator.credentials.get.
+func (a *softAuthenticator) get(challenge string, userHandle []byte) *CredentialAssertionResponse {
+	cd := a.clientData("webauthn.get", challenge)
+	authData := a.authData(false)
+	cdHash := sha256.Sum256(cd)
+	resp := &CredentialAssertionResponse{ID: EncodeBase64URL(a.credID), RawID: EncodeBase64URL(a.credID), Type: "public-key"}
+	resp.Response.ClientDataJSON = EncodeBase64URL(cd)
+	resp.Response.AuthenticatorData = EncodeBase64URL(authData)
+	resp.Response.Signature = EncodeBase64URL(a.sign(append(append([]byte{}, authData...), cdHash[:]...)))
+	resp.Response.UserHandle = EncodeBase64URL(userHandle)
+	return resp
+}
+
+func mustChallenge(t *testing.T) string {
+	c, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRegisterAndAssert(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		alg    int
+		packed bool
+	}{
+		{"es256 none", AlgES256, false},
+		{"es256 packed self", AlgES256, true},
+		{"eddsa none", AlgEdDSA, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			auth := newSoftAuthenticator(t, tc.alg)
+			regChallenge := mustChallenge(t)
+			cred, err := testConfig.VerifyRegistration(auth.create(regChallenge, tc.packed), regChallenge, true)
+			if err != nil {
+				t.Fatalf("VerifyRegistration: %v", err)
+			}
+			if cred.Algorithm != tc.alg || string(cred.ID) != string(auth.credID) || cred.SignCount != 1 {
+				t.Fatalf("credential = %+v", cred)
+			}
+
+			challenge := mustChallenge(t)
+			resp := auth.get(challenge, []byte("user-1"))
+			a, err := testConfig.VerifyAssertion(resp, challenge, cred.PublicKey, cred.SignCount, true)
+			if err != nil {
+				t.Fatalf("VerifyAssertion: %v", err)
+			}
+			if a.SignCount != 2 || !a.UserVerified {
+				t.Fatalf("assertion = %+v", a)
+			}
+			if h, _ := resp.UserHandleBytes(); string(h) != "user-1" {
+				t.Fatalf("user handle = %q", h)
+			}
+			// Replaying the same assertion fails on the counter once the new count is stored.
+			if _, err := testConfig.VerifyAssertion(resp, challenge, cred.PublicKey, a.SignCount, true); !errors.Is(err, ErrSignCount) {
+				t.Fatalf("replay: err = %v, want ErrSignCount", err)
+			}
+		})
+	}
+}
+
+func TestAssertionRejections(t *testing.T) {
+	auth := newSoftAuthenticator(t, AlgES256)
+	regChallenge := mustChallenge(t)
+	cred, err := testConfig.VerifyRegistration(auth.create(regChallenge, false), regChallenge, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := newSoftAuthenticator(t, AlgES256)
+
+	cases := []struct {
+		name    string
+		resp    func(challenge string) *CredentialAssertionResponse
+		wantErr error
+	}{
+		{"wrong challenge", func(string) *CredentialAssertionResponse { return auth.get(mustChallenge(t), nil) }, ErrChallenge},
+		{"wrong origin", func(c string) *CredentialAssertionResponse {
+			auth.origin = "https://evil.test"
+			defer func() { auth.origin = testOrigin }()
+			return auth.get(c, nil)
+		}, ErrOrigin},
+		{"wrong rp id", func(c string) *CredentialAssertionResponse {
+			auth.rpID = "evil.test"
+			defer func() { auth.rpID = testRPID }()
+			return auth.get(c, nil)
+		}, ErrRPID},
+		{"no user verification", func(c string) *CredentialAssertionResponse {
+			auth.uv = false
+			defer func() { auth.uv = true }()
+			return auth.get(c, nil)
+		}, ErrUserVerified},
+		{"other key", func(c string) *CredentialAssertionResponse { return other.get(c, nil) }, ErrSignature},
+		{"registration data as assertion", func(c string) *CredentialAssertionResponse {
+			r := auth.get(c, nil)
+			r.Response.ClientDataJSON = EncodeBase64URL(auth.clientData("webauthn.create", c))
+			return r
+		}, ErrClientData},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			challenge := mustChallenge(t)
+			if _, err := testConfig.VerifyAssertion(tc.resp(challenge), challenge, cred.PublicKey, 0, true); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignCountZeroAllowed(t *testing.T) {
+	auth := newSoftAuthenticator(t, AlgES256)
+	auth.counter = false
+	regChallenge := mustChallenge(t)
+	cred, err := testConfig.VerifyRegistration(auth.create(regChallenge, false), regChallenge, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		challenge := mustChallenge(t)
+		if _, err := testConfig.VerifyAssertion(auth.get(challenge, nil), challenge, cred.PublicKey, 0, true); err != nil {
+			t.Fatalf("assertion %d: %v", i, err)
+		}
+	}
+}
+
+func TestRegistrationRejectsTamperedAttestation(t *testing.T) {
+	auth := newSoftAuthenticator(t, AlgES256)
+	challenge := mustChallenge(t)
+	resp := auth.create(challenge, true)
+	other := newSoftAuthenticator(t, AlgES256)
+	forged := other.create(challenge, true)
+	// The attestation signature belongs to other's key, the credential to auth's.
+	resp.Response.AttestationObject = forged.Response.AttestationObject
+	if _, err := testConfig.VerifyRegistration(resp, challenge, true); !errors.Is(err, ErrCredentialMatch) && !errors.Is(err, ErrAttestation) {
+		t.Fatalf("err = %v, want credential mismatch or attestation error", err)
+	}
+	if _, err := testConfig.VerifyRegistration(auth.create(challenge, false), mustChallenge(t), true); !errors.Is(err, ErrChallenge) {
+		t.Fatalf("err = %v, want ErrChallenge", err)
+	}
+}
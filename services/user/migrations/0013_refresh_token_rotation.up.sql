@@ -0,0 +1,14 @@
+-- Rotating refresh tokens: every refresh replaces the presented token with a new one in the same family.
+-- Presenting an already-revoked token means it leaked, so the whole family is revoked.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id UUID;
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET DEFAULT gen_random_uuid();
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoked_reason VARCHAR(30);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_family_id ON refresh_tokens(family_id);
+
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Shared by a login and all tokens rotated from it; revoked together on reuse or logout.';
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, reuse_detected, password_changed, password_reset or user_restricted.';
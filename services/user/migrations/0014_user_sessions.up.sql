@@ -0,0 +1,29 @@
+-- A session is one login on one device. Its id is the family_id of the refresh tokens rotated from that login,
+-- so revoking a session revokes its refresh tokens.
+CREATE TABLE user_sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+  device_id VARCHAR(128) NOT NULL,
+  device_name VARCHAR(100),
+  platform VARCHAR(20),
+  app_version VARCHAR(30),
+  ip_address VARCHAR(45),
+  user_agent VARCHAR(255),
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at TIMESTAMPTZ,
+  revoked_reason VARCHAR(30)
+);
+
+CREATE INDEX idx_user_sessions_user ON user_sessions(user_id, last_seen_at DESC);
+CREATE INDEX idx_user_sessions_device ON user_sessions(user_id, device_id);
+
+COMMENT ON COLUMN user_sessions.device_id IS 'Client-supplied X-Device-ID, or a hash of platform and user agent when absent.';
+
+ALTER TABLE user_sessions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON user_sessions FOR ALL TO user_service USING (true) WITH CHECK (true);
+
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, logout_all, session_revoked, admin_revoked, reuse_detected, password_changed, password_reset or user_restricted.';
@@ -0,0 +1,2 @@
+ALTER TABLE user_settings DROP COLUMN IF EXISTS two_factor_fallback_channel;
+DROP TABLE IF EXISTS two_factor_recovery_codes;
@@ -0,0 +1,18 @@
+-- One-time recovery codes for users who lose their authenticator. Only the SHA-256 of each code is stored; a code is
+-- spent by setting used_at. Regenerating deletes the previous set.
+CREATE TABLE two_factor_recovery_codes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash CHAR(64) NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, code_hash)
+);
+
+ALTER TABLE two_factor_recovery_codes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON two_factor_recovery_codes FOR ALL TO user_service USING (true) WITH CHECK (true);
+
+-- Preferred channel for the fallback one-time code at 2FA login (sms, whatsapp or email); NULL means none chosen.
+ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS two_factor_fallback_channel VARCHAR(10);
+
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, logout_all, session_revoked, admin_revoked, reuse_detected, password_changed, password_reset, user_restricted or 2fa_reset.';
@@ -0,0 +1,2 @@
+COMMENT ON COLUMN user_settings.biometric_enabled IS NULL;
+DROP TABLE IF EXISTS webauthn_credentials;
@@ -0,0 +1,24 @@
+-- Passkeys (WebAuthn credentials), one row per authenticator. public_key is the COSE key from registration;
+-- sign_count is the last signature counter seen, used to spot cloned authenticators (0 for synced passkeys).
+CREATE TABLE webauthn_credentials (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  credential_id BYTEA NOT NULL UNIQUE,
+  public_key BYTEA NOT NULL,
+  algorithm INTEGER NOT NULL,
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  aaguid BYTEA,
+  transports VARCHAR(100) NOT NULL DEFAULT '', -- comma-separated hints from registration, e.g. internal,hybrid
+  name VARCHAR(100),
+  backup_eligible BOOLEAN NOT NULL DEFAULT false,
+  backed_up BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+
+ALTER TABLE webauthn_credentials ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON webauthn_credentials FOR ALL TO user_service USING (true) WITH CHECK (true);
+
+COMMENT ON COLUMN user_settings.biometric_enabled IS 'Passkey (WebAuthn) assertions may replace the PIN for transfers. Turned on with the first passkey, off when the last is removed; the user may also switch it off.';
@@ -0,0 +1,20 @@
+-- Scheduled transfer limit increases. Decreases apply to user_settings at once; increases wait here until
+-- effective_at (cooling-off) so a stolen password cannot raise limits and drain the wallet straight away.
+-- A NULL limit column means that limit is not part of the change; 0 means no limit, as in user_settings.
+CREATE TABLE transfer_limit_changes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  daily_transfer_limit DECIMAL(18, 2),
+  monthly_transfer_limit DECIMAL(18, 2),
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, applied, cancelled
+  requested_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  effective_at TIMESTAMPTZ NOT NULL,
+  resolved_at TIMESTAMPTZ
+);
+
+-- At most one pending change per user; a newer request replaces it.
+CREATE UNIQUE INDEX idx_transfer_limit_changes_pending_user ON transfer_limit_changes(user_id) WHERE status = 'pending';
+CREATE INDEX idx_transfer_limit_changes_due ON transfer_limit_changes(effective_at) WHERE status = 'pending';
+
+ALTER TABLE transfer_limit_changes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON transfer_limit_changes FOR ALL TO user_service USING (true) WITH CHECK (true);
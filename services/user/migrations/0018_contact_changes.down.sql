@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS contact_changes;
+
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, logout_all, session_revoked, admin_revoked, reuse_detected, password_changed, password_reset, user_restricted or 2fa_reset.';
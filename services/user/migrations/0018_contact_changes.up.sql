@@ -0,0 +1,23 @@
+-- Email and phone number changes waiting for the user to enter the code sent to the new address. The users row only
+-- changes once the code is confirmed; confirmed rows stay as history and drive the cooldown between changes.
+CREATE TABLE contact_changes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind VARCHAR(10) NOT NULL, -- email, phone
+  new_value TEXT NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  channel VARCHAR(20) NOT NULL, -- where the code went: email, sms, whatsapp
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, confirmed, cancelled
+  requested_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  resolved_at TIMESTAMPTZ
+);
+
+-- At most one pending change of each kind per user; a newer request replaces it.
+CREATE UNIQUE INDEX idx_contact_changes_pending ON contact_changes(user_id, kind) WHERE status = 'pending';
+CREATE INDEX idx_contact_changes_confirmed ON contact_changes(user_id, kind, resolved_at) WHERE status = 'confirmed';
+
+ALTER TABLE contact_changes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON contact_changes FOR ALL TO user_service USING (true) WITH CHECK (true);
+
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, logout_all, session_revoked, admin_revoked, reuse_detected, password_changed, password_reset, user_restricted, 2fa_reset or email_changed.';
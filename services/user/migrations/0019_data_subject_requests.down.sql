@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+DROP TABLE IF EXISTS data_subject_requests;
+
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, logout_all, session_revoked, admin_revoked, reuse_detected, password_changed, password_reset, user_restricted, 2fa_reset or email_changed.';
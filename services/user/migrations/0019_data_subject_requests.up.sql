@@ -0,0 +1,30 @@
+-- NDPR data-subject requests: data exports and account deletions. A background sweep works pending requests once
+-- scheduled_for has passed (deletions wait out a grace period the user can cancel in). Finished rows stay as the record
+-- that the request was honoured.
+CREATE TABLE data_subject_requests (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind VARCHAR(10) NOT NULL, -- export, deletion
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, completed, held, cancelled, failed
+  scheduled_for TIMESTAMPTZ NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  result JSONB, -- per service: what was exported, erased or retained, and hold reasons
+  archive BYTEA, -- export zip; cleared when it expires
+  archive_expires_at TIMESTAMPTZ,
+  requested_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  completed_at TIMESTAMPTZ
+);
+
+-- At most one pending request of each kind per user.
+CREATE UNIQUE INDEX idx_data_subject_requests_pending ON data_subject_requests(user_id, kind) WHERE status = 'pending';
+CREATE INDEX idx_data_subject_requests_due ON data_subject_requests(scheduled_for) WHERE status = 'pending';
+CREATE INDEX idx_data_subject_requests_user ON data_subject_requests(user_id, requested_at DESC);
+
+ALTER TABLE data_subject_requests ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON data_subject_requests FOR ALL TO user_service USING (true) WITH CHECK (true);
+
+-- Deleted accounts keep their row (id referenced by audit and payment records) with personal data blanked.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN refresh_tokens.revoked_reason IS 'rotated, logout, logout_all, session_revoked, admin_revoked, reuse_detected, password_changed, password_reset, user_restricted, 2fa_reset, email_changed or account_deleted.';
@@ -0,0 +1,46 @@
+-- Referral program. Every user can share one referral code; a registration that used a code is attributed to its
+-- owner in referrals. Rewards are paid once per rule and side of a referral, after the referee reaches the milestones
+-- the rule waits for (KYC approval, first successful transfer).
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(16);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_referral_code ON users(referral_code) WHERE referral_code IS NOT NULL;
+
+CREATE TABLE referrals (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  referee_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  code VARCHAR(16) NOT NULL,
+  referee_phone_hash CHAR(64) NOT NULL, -- phone hash at registration; survives phone changes and account deletion
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, rewarded, flagged
+  flagged_reason VARCHAR(50),
+  kyc_approved_at TIMESTAMPTZ,
+  first_transfer_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_referrals_referrer ON referrals(referrer_id, created_at DESC);
+CREATE INDEX idx_referrals_phone_hash ON referrals(referee_phone_hash);
+
+CREATE TABLE referral_rewards (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  referral_id UUID NOT NULL REFERENCES referrals(id) ON DELETE CASCADE,
+  rule VARCHAR(50) NOT NULL,
+  beneficiary VARCHAR(10) NOT NULL, -- referrer, referee
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  amount NUMERIC(18,2) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, paid, failed
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  transaction_ref VARCHAR(60),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  paid_at TIMESTAMPTZ,
+  UNIQUE (referral_id, rule, beneficiary)
+);
+
+CREATE INDEX idx_referral_rewards_due ON referral_rewards(next_attempt_at) WHERE status = 'pending';
+
+ALTER TABLE referrals ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON referrals FOR ALL TO user_service USING (true) WITH CHECK (true);
+ALTER TABLE referral_rewards ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON referral_rewards FOR ALL TO user_service USING (true) WITH CHECK (true);
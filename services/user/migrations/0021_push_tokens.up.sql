@@ -0,0 +1,16 @@
+-- Device tokens for mobile push. A token belongs to one app install; registering it again (another user signed in on
+-- the same phone) moves it to the new user. The notification service removes tokens FCM or APNs report as invalid.
+CREATE TABLE push_tokens (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token TEXT NOT NULL UNIQUE,
+  platform VARCHAR(10) NOT NULL, -- ios (APNs), android (FCM)
+  device_id VARCHAR(255),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_push_tokens_user ON push_tokens(user_id);
+
+ALTER TABLE push_tokens ENABLE ROW LEVEL SECURITY;
+CREATE POLICY user_service_policy ON push_tokens FOR ALL TO user_service USING (true) WITH CHECK (true);
@@ -0,0 +1,7 @@
+-- Back to hashes of the numbers as stored. Referrals follow their referee's current number.
+UPDATE referrals r SET referee_phone_hash = encode(sha256(u.phone_number), 'hex')
+FROM users u
+WHERE r.referee_id = u.id AND r.referee_phone_hash = u.phone_number_hash AND u.deleted_at IS NULL AND length(u.phone_number) > 0;
+
+UPDATE users SET phone_number_hash = encode(sha256(phone_number), 'hex')
+WHERE deleted_at IS NULL AND length(phone_number) > 0;
@@ -0,0 +1,35 @@
+-- users.phone_number_hash was the SHA-256 of the number exactly as typed, so 08031234567 and +2348031234567 could
+-- open two accounts, phone login needed the original spelling and the referral abuse check missed reused numbers.
+-- Rehash every number in E.164 form (the same rules as normalizePhone in the service) and carry the referee hashes
+-- of referrals along. Accounts whose numbers are the same in E.164 keep their old hashes and are reported below;
+-- they need a manual merge.
+CREATE TEMP TABLE phone_rehash AS
+SELECT id, phone_number_hash AS old_hash,
+  encode(sha256(convert_to('+' || CASE WHEN length(digits) = 11 AND left(digits, 1) = '0' THEN '234' || substr(digits, 2) ELSE digits END, 'UTF8')), 'hex') AS new_hash
+FROM (
+  SELECT id, phone_number_hash, regexp_replace(convert_from(phone_number, 'UTF8'), '[^0-9]', '', 'g') AS digits
+  FROM users
+  WHERE deleted_at IS NULL AND length(phone_number) > 0
+) u;
+
+DO $$
+DECLARE
+  dup INTEGER;
+BEGIN
+  SELECT COUNT(*) INTO dup FROM phone_rehash WHERE new_hash IN (SELECT new_hash FROM phone_rehash GROUP BY new_hash HAVING COUNT(*) > 1);
+  IF dup > 0 THEN
+    RAISE NOTICE '% accounts share a phone number once normalized; their hashes were left unchanged', dup;
+  END IF;
+END $$;
+
+DELETE FROM phone_rehash WHERE new_hash IN (SELECT new_hash FROM phone_rehash GROUP BY new_hash HAVING COUNT(*) > 1);
+
+UPDATE referrals r SET referee_phone_hash = p.new_hash
+FROM phone_rehash p
+WHERE r.referee_phone_hash = p.old_hash AND p.old_hash <> p.new_hash;
+
+UPDATE users u SET phone_number_hash = p.new_hash
+FROM phone_rehash p
+WHERE u.id = p.id AND p.old_hash <> p.new_hash;
+
+DROP TABLE phone_rehash;
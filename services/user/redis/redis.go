@@ -2,6 +2,8 @@ package redis
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"log"
 	"os"
 	"time"
@@ -59,6 +61,14 @@ func SetUserExists(ctx context.Context, userID string, ttl time.Duration) {
 	_ = rdb.Set(ctx, key, "1", ttl).Err()
 }
 
+// DeleteUserExists drops the cached "user exists" entry, e.g. when the account is deleted.
+func DeleteUserExists(ctx context.Context, userID string) {
+	if rdb == nil {
+		return
+	}
+	_ = rdb.Del(ctx, userExistsKeyPrefix+userID).Err()
+}
+
 func ProcessTransaction(ctx context.Context, transactionID string, fn func() error) error {
 	ok, err := rdb.SetNX(ctx, transactionID, "processing", 60*time.Minute).Result()
 	if err != nil {
@@ -78,3 +88,80 @@ func ProcessTransaction(ctx context.Context, transactionID string, fn func() err
 	rdb.Set(ctx, transactionID, "Done", 24*time.Hour)
 	return nil
 }
+
+// Client returns the shared Redis client, or nil before InitRedis.
+func Client() *redis.Client {
+	return rdb
+}
+
+const otpKeyPrefix = "otp:"
+
+// ErrUnavailable is returned by helpers that need Redis when it is not initialised.
+var ErrUnavailable = errors.New("redis not available")
+
+// SetOTP stores the hash of a one-time code for the user and purpose (e.g. 2fa_login, step_up), replacing any earlier
+// code for the same purpose.
+func SetOTP(ctx context.Context, purpose, userID, codeHash string, ttl time.Duration) error {
+	if rdb == nil {
+		return ErrUnavailable
+	}
+	return rdb.Set(ctx, otpKeyPrefix+purpose+":"+userID, codeHash, ttl).Err()
+}
+
+// ConsumeOTP deletes and returns true when codeHash matches the stored code. A wrong code leaves it in place
+// (attempts are limited by the caller).
+func ConsumeOTP(ctx context.Context, purpose, userID, codeHash string) (bool, error) {
+	if rdb == nil {
+		return false, ErrUnavailable
+	}
+	key := otpKeyPrefix + purpose + ":" + userID
+	stored, err := rdb.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(codeHash)) != 1 {
+		return false, nil
+	}
+	// Del reports 0 when a concurrent request consumed the code first.
+	n, err := rdb.Del(ctx, key).Result()
+	return n == 1, err
+}
+
+const webauthnKeyPrefix = "webauthn:ceremony:"
+
+// SetWebAuthnCeremony stores the state of a passkey ceremony (challenge, user, purpose) under its ID until it is
+// finished or expires.
+func SetWebAuthnCeremony(ctx context.Context, id string, state []byte, ttl time.Duration) error {
+	if rdb == nil {
+		return ErrUnavailable
+	}
+	return rdb.Set(ctx, webauthnKeyPrefix+id, state, ttl).Err()
+}
+
+// GetWebAuthnCeremony returns the ceremony state without consuming it, or nil when unknown or expired.
+func GetWebAuthnCeremony(ctx context.Context, id string) ([]byte, error) {
+	if rdb == nil {
+		return nil, ErrUnavailable
+	}
+	b, err := rdb.Get(ctx, webauthnKeyPrefix+id).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return b, err
+}
+
+// ConsumeWebAuthnCeremony returns and deletes the ceremony state, so each challenge is answered once. Returns nil
+// when the ceremony is unknown or expired.
+func ConsumeWebAuthnCeremony(ctx context.Context, id string) ([]byte, error) {
+	if rdb == nil {
+		return nil, ErrUnavailable
+	}
+	b, err := rdb.GetDel(ctx, webauthnKeyPrefix+id).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return b, err
+}